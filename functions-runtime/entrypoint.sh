@@ -2,4 +2,22 @@
 
 set -eu
 
-deno run --allow-net --allow-env=DATA,SECURE_DATA,CONTEXT "$SCRIPT"
+args=(--allow-net --allow-env=DATA,SECURE_DATA,CONTEXT)
+
+# a ConfigMap bundle of the dependencies is only used if it matches its digest
+if [ -n "${VENDOR_BUNDLE:-}" ]; then
+  echo "${VENDOR_DIGEST#sha256:}  $VENDOR_BUNDLE" | sha256sum --check --quiet -
+  VENDOR_DIR=/tmp/vendor
+  mkdir -p "$VENDOR_DIR"
+  tar -xzf "$VENDOR_BUNDLE" -C "$VENDOR_DIR"
+fi
+
+if [ -n "${VENDOR_DIR:-}" ]; then
+  args+=(--import-map="$VENDOR_DIR/import_map.json")
+fi
+
+if [ "${NO_REMOTE:-}" = "true" ]; then
+  args+=(--no-remote)
+fi
+
+deno run "${args[@]}" "$SCRIPT"
@@ -17,11 +17,13 @@ fi
 
 # initialize variables with false (make sure they are also set in needs.prepare_ci_run.outputs !!!)
 BUILD_FUNCTIONS_RUNTIME_SVC=false
+BUILD_PYTHON_RUNTIME_SVC=false
 BUILD_LFC_SCHEDULER_SVC=false
 BUILD_OPERATOR_SVC=false
 
 artifacts=(
   "$FUNCTIONS_RUNTIME_SVC_ARTIFACT_PREFIX"
+  "$PYTHON_RUNTIME_SVC_ARTIFACT_PREFIX"
   "$LFC_SCHEDULER_SVC_ARTIFACT_PREFIX"
   "$OPERATOR_SVC_ARTIFACT_PREFIX"
 )
@@ -125,6 +127,7 @@ echo "::set-output name=BUILD_MATRIX::$matrix_config"
 echo ""
 echo "The following artifacts have changes and will be built fresh:"
 echo "BUILD_FUNCTIONS_RUNTIME_SVC: $BUILD_FUNCTIONS_RUNTIME_SVC"
+echo "BUILD_PYTHON_RUNTIME_SVC: $BUILD_PYTHON_RUNTIME_SVC"
 echo "BUILD_LFC_SCHEDULER_SVC: $BUILD_LFC_SCHEDULER_SVC"
 echo "BUILD_OPERATOR_SVC: $BUILD_OPERATOR_SVC"
 
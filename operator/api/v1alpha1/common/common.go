@@ -5,8 +5,6 @@ import (
 	"math/rand"
 
 	"go.opentelemetry.io/otel/attribute"
-	"go.opentelemetry.io/otel/metric/instrument/syncfloat64"
-	"go.opentelemetry.io/otel/metric/instrument/syncint64"
 )
 
 const WorkloadAnnotation = "keptn.sh/workload"
@@ -14,6 +12,7 @@ const VersionAnnotation = "keptn.sh/version"
 const AppAnnotation = "keptn.sh/app"
 const PreDeploymentTaskAnnotation = "keptn.sh/pre-deployment-tasks"
 const PostDeploymentTaskAnnotation = "keptn.sh/post-deployment-tasks"
+const MigrationTaskAnnotation = "keptn.sh/migration-tasks"
 const K8sRecommendedWorkloadAnnotations = "app.kubernetes.io/name"
 const K8sRecommendedVersionAnnotations = "app.kubernetes.io/version"
 const K8sRecommendedAppAnnotations = "app.kubernetes.io/part-of"
@@ -21,6 +20,47 @@ const PreDeploymentEvaluationAnnotation = "keptn.sh/pre-deployment-evaluations"
 const PostDeploymentEvaluationAnnotation = "keptn.sh/post-deployment-evaluations"
 const TaskNameAnnotation = "keptn.sh/task-name"
 const NamespaceEnabledAnnotation = "keptn.sh/lifecycle-controller"
+const EnvironmentAnnotation = "keptn.sh/environment"
+const IssueAnnotation = "keptn.sh/issue"
+const ChangeRequestAnnotation = "keptn.sh/change-request"
+const PostDeploymentEvaluationDelayAnnotation = "keptn.sh/post-deployment-evaluation-delay"
+const ContainerVersionAnnotationPrefix = "keptn.sh/container-version."
+const ReadinessCheckAnnotation = "keptn.sh/readiness-check"
+const PhaseTraceParentAnnotation = "keptn.sh/phase-traceparent"
+const MaxDeploymentDurationAnnotation = "keptn.sh/max-deployment-duration"
+const FunctionRunnerImageAnnotation = "keptn.sh/function-runner-image"
+const FunctionRunnerImagePullSecretsAnnotation = "keptn.sh/function-runner-image-pull-secrets"
+const EvaluationPresetsAnnotation = "keptn.sh/evaluation-presets"
+const EvaluationPresetsSourceAnnotation = "keptn.sh/evaluation-presets-source"
+const MaxRunningTaskJobsAnnotation = "keptn.sh/max-running-task-jobs"
+const SimulateFailureAnnotation = "keptn.sh/simulate-failure"
+const SkipPhasesAnnotation = "keptn.sh/skip-phases"
+const RequireApprovalAnnotation = "keptn.sh/require-approval"
+const ApprovedByAnnotation = "keptn.sh/approved-by"
+const RejectedByAnnotation = "keptn.sh/rejected-by"
+const RollbackOnFailureAnnotation = "keptn.sh/rollback-on-failure"
+
+// AbortAnnotation aborts a KeptnAppVersion that is in progress if set to "true", like its spec.abort field
+const AbortAnnotation = "keptn.sh/abort"
+
+// RollbackOfAnnotation is set on the KeptnWorkloadInstances of rollbacks and contains the name of the failed KeptnWorkloadInstance
+const RollbackOfAnnotation = "keptn.sh/rollback-of"
+
+// ImportedVersionAnnotation is set on a KeptnWorkload adopted from a Deployment with an earlier revision and contains
+// the version of that revision, which is imported as a completed KeptnWorkloadInstance
+const ImportedVersionAnnotation = "keptn.sh/imported-version"
+
+// ImportedAnnotation is set to "true" on the KeptnWorkloadInstances imported from the history of a Deployment, which are
+// not reconciled
+const ImportedAnnotation = "keptn.sh/imported"
+
+// EnvironmentLabel carries the environment of the namespace on the KeptnAppVersions, KeptnWorkloadInstances, KeptnTasks and KeptnEvaluations
+const EnvironmentLabel = "keptn.sh/environment"
+
+// EvaluationPresetLabel is set on the KeptnEvaluationDefinitions installed from the preset library and contains the name of the preset
+const EvaluationPresetLabel = "keptn.sh/evaluation-preset"
+
+const EnvironmentProduction = "production"
 
 const MaxAppNameLength = 25
 const MaxWorkloadNameLength = 25
@@ -35,22 +75,40 @@ const (
 	StateFailed      KeptnState = "Failed"
 	StateUnknown     KeptnState = "Unknown"
 	StatePending     KeptnState = "Pending"
+	// StateWarning is the state of a KeptnAppVersion which has succeeded, although some of its optional workloads have failed
+	StateWarning KeptnState = "Warning"
+	// StateDeploymentPaused is the deployment state of a KeptnWorkloadInstance whose Deployment is paused
+	StateDeploymentPaused KeptnState = "DeploymentPaused"
+	// StateCancelled is the state of a KeptnAppVersion which has been aborted, and of the phases, tasks and evaluations it cancelled
+	StateCancelled KeptnState = "Cancelled"
 )
 
 var ErrTooLongAnnotations = fmt.Errorf("too long annotations, maximum length for app and workload is 25 characters, for version 12 characters")
 
 func (k KeptnState) IsCompleted() bool {
-	return k == StateSucceeded || k == StateFailed
+	return k == StateSucceeded || k == StateFailed || k == StateWarning || k == StateCancelled
 }
 
 func (k KeptnState) IsSucceeded() bool {
-	return k == StateSucceeded
+	return k == StateSucceeded || k == StateWarning
+}
+
+func (k KeptnState) IsWarning() bool {
+	return k == StateWarning
 }
 
 func (k KeptnState) IsFailed() bool {
 	return k == StateFailed
 }
 
+func (k KeptnState) IsPaused() bool {
+	return k == StateDeploymentPaused
+}
+
+func (k KeptnState) IsCancelled() bool {
+	return k == StateCancelled
+}
+
 type StatusSummary struct {
 	Total       int
 	progressing int
@@ -62,11 +120,11 @@ type StatusSummary struct {
 
 func UpdateStatusSummary(status KeptnState, summary StatusSummary) StatusSummary {
 	switch status {
-	case StateFailed:
+	case StateFailed, StateCancelled:
 		summary.failed++
 	case StateSucceeded:
 		summary.succeeded++
-	case StateProgressing:
+	case StateProgressing, StateDeploymentPaused:
 		summary.progressing++
 	case StatePending, "":
 		summary.pending++
@@ -96,6 +154,36 @@ func GetOverallState(s StatusSummary) KeptnState {
 	return StateSucceeded
 }
 
+// GetPartialOverallState returns the overall state of items of which only minSucceeded have to succeed.
+// Pending items are not waited for once enough items have succeeded, since they might never be started.
+func GetPartialOverallState(s StatusSummary, minSucceeded int) KeptnState {
+	if s.Total-s.failed < minSucceeded {
+		return StateFailed
+	}
+	if s.succeeded >= minSucceeded && s.progressing == 0 {
+		return StateSucceeded
+	}
+	if s.progressing > 0 {
+		return StateProgressing
+	}
+	if s.pending > 0 {
+		return StatePending
+	}
+	return StateUnknown
+}
+
+// GetOptionalOverallState returns the overall state of items whose failure results in a warning instead of a failure.
+// Pending items are not waited for, since they might never be started.
+func GetOptionalOverallState(s StatusSummary) KeptnState {
+	if s.progressing > 0 {
+		return StateProgressing
+	}
+	if s.failed > 0 {
+		return StateWarning
+	}
+	return StateSucceeded
+}
+
 func TruncateString(s string, max int) string {
 	if len(s) > max {
 		return s[:max]
@@ -109,17 +197,7 @@ const PreDeploymentCheckType CheckType = "pre"
 const PostDeploymentCheckType CheckType = "post"
 const PreDeploymentEvaluationCheckType CheckType = "pre-eval"
 const PostDeploymentEvaluationCheckType CheckType = "post-eval"
-
-type KeptnMeters struct {
-	TaskCount          syncint64.Counter
-	TaskDuration       syncfloat64.Histogram
-	DeploymentCount    syncint64.Counter
-	DeploymentDuration syncfloat64.Histogram
-	AppCount           syncint64.Counter
-	AppDuration        syncfloat64.Histogram
-	EvaluationCount    syncint64.Counter
-	EvaluationDuration syncfloat64.Histogram
-}
+const MigrationCheckType CheckType = "migration"
 
 const (
 	AppName                 attribute.Key = attribute.Key("keptn.deployment.app.name")
@@ -132,14 +210,75 @@ const (
 	WorkloadPreviousVersion attribute.Key = attribute.Key("keptn.deployment.workload.previousversion")
 	WorkloadNamespace       attribute.Key = attribute.Key("keptn.deployment.workload.namespace")
 	WorkloadStatus          attribute.Key = attribute.Key("keptn.deployment.workload.status")
+	WorkloadChanges         attribute.Key = attribute.Key("keptn.deployment.workload.changes")
+	WorkloadCostDelta       attribute.Key = attribute.Key("keptn.deployment.workload.costdelta")
+	WorkloadPower           attribute.Key = attribute.Key("keptn.deployment.workload.power")
+	WorkloadPreviousPower   attribute.Key = attribute.Key("keptn.deployment.workload.previouspower")
+	WorkloadReplicas        attribute.Key = attribute.Key("keptn.deployment.workload.replicas")
+	WorkloadReplicasDelta   attribute.Key = attribute.Key("keptn.deployment.workload.replicas.delta")
+	WorkloadCPURequests     attribute.Key = attribute.Key("keptn.deployment.workload.requests.cpu")
+	WorkloadCPULimits       attribute.Key = attribute.Key("keptn.deployment.workload.limits.cpu")
+	WorkloadCPUDelta        attribute.Key = attribute.Key("keptn.deployment.workload.requests.cpu.delta")
+	WorkloadMemoryRequests  attribute.Key = attribute.Key("keptn.deployment.workload.requests.memory")
+	WorkloadMemoryLimits    attribute.Key = attribute.Key("keptn.deployment.workload.limits.memory")
+	WorkloadMemoryDelta     attribute.Key = attribute.Key("keptn.deployment.workload.requests.memory.delta")
 	TaskStatus              attribute.Key = attribute.Key("keptn.deployment.task.status")
 	TaskName                attribute.Key = attribute.Key("keptn.deployment.task.name")
 	TaskType                attribute.Key = attribute.Key("keptn.deployment.task.type")
 	EvaluationStatus        attribute.Key = attribute.Key("keptn.deployment.evaluation.status")
 	EvaluationName          attribute.Key = attribute.Key("keptn.deployment.evaluation.name")
 	EvaluationType          attribute.Key = attribute.Key("keptn.deployment.evaluation.type")
+	EvaluationObjective     attribute.Key = attribute.Key("keptn.deployment.evaluation.objective")
+	EvaluationQuery         attribute.Key = attribute.Key("keptn.deployment.evaluation.query")
+	EvaluationQueryStart    attribute.Key = attribute.Key("keptn.deployment.evaluation.query.start")
+	EvaluationQueryEnd      attribute.Key = attribute.Key("keptn.deployment.evaluation.query.end")
+	EvaluationResponse      attribute.Key = attribute.Key("keptn.deployment.evaluation.response")
+	ProviderName            attribute.Key = attribute.Key("keptn.deployment.evaluation.provider.name")
+	ProviderNamespace       attribute.Key = attribute.Key("keptn.deployment.evaluation.provider.namespace")
+	Environment             attribute.Key = attribute.Key("keptn.deployment.environment")
+	Namespace               attribute.Key = attribute.Key("keptn.deployment.namespace")
+	Phase                   attribute.Key = attribute.Key("keptn.deployment.phase")
+	Replayed                attribute.Key = attribute.Key("keptn.deployment.replayed")
+	ReconcileController     attribute.Key = attribute.Key("keptn.reconcile.controller")
+	ReconcileKey            attribute.Key = attribute.Key("keptn.reconcile.key")
+	ReconcileOutcome        attribute.Key = attribute.Key("keptn.reconcile.outcome")
+	ReconcileAPICalls       attribute.Key = attribute.Key("keptn.reconcile.apicalls")
 )
 
+// MetricsAttributeKeys are all attribute keys that can be used in metrics
+var MetricsAttributeKeys = []attribute.Key{
+	AppName, AppVersion, AppNamespace, AppStatus, AppPreviousVersion,
+	WorkloadName, WorkloadVersion, WorkloadPreviousVersion, WorkloadNamespace, WorkloadStatus,
+	TaskStatus, TaskName, TaskType,
+	EvaluationStatus, EvaluationName, EvaluationType,
+	ProviderName, ProviderNamespace,
+	Environment, Namespace, Phase,
+}
+
+// MetricsDimension is an attribute shared by the metrics of different resources, which dashboards use to correlate them
+type MetricsDimension string
+
+const (
+	DimensionApp         MetricsDimension = "app"
+	DimensionWorkload    MetricsDimension = "workload"
+	DimensionVersion     MetricsDimension = "version"
+	DimensionNamespace   MetricsDimension = "namespace"
+	DimensionPhase       MetricsDimension = "phase"
+	DimensionEnvironment MetricsDimension = "environment"
+)
+
+// MetricsDimensionKeys maps each shared dimension to the attribute keys representing it. The namespace and the phase
+// are represented by shared keys, while the keys of the resources, e.g. AppNamespace or TaskType, are kept for
+// existing dashboards.
+var MetricsDimensionKeys = map[MetricsDimension][]attribute.Key{
+	DimensionApp:         {AppName},
+	DimensionWorkload:    {WorkloadName},
+	DimensionVersion:     {AppVersion, WorkloadVersion},
+	DimensionNamespace:   {Namespace},
+	DimensionPhase:       {Phase},
+	DimensionEnvironment: {Environment},
+}
+
 func GenerateTaskName(checkType CheckType, taskName string) string {
 	randomId := rand.Intn(99_999-10_000) + 10000
 	return fmt.Sprintf("%s-%s-%d", checkType, TruncateString(taskName, 32), randomId)
@@ -149,13 +288,3 @@ func GenerateEvaluationName(checkType CheckType, evalName string) string {
 	randomId := rand.Intn(99_999-10_000) + 10000
 	return fmt.Sprintf("%s-%s-%d", checkType, TruncateString(evalName, 27), randomId)
 }
-
-type GaugeValue struct {
-	Value      int64
-	Attributes []attribute.KeyValue
-}
-
-type GaugeFloatValue struct {
-	Value      float64
-	Attributes []attribute.KeyValue
-}
@@ -0,0 +1,44 @@
+package common
+
+import (
+	"testing"
+
+	testrequire "github.com/stretchr/testify/require"
+)
+
+func summaryOf(states ...KeptnState) StatusSummary {
+	summary := StatusSummary{Total: len(states)}
+	for _, state := range states {
+		summary = UpdateStatusSummary(state, summary)
+	}
+	return summary
+}
+
+func TestGetPartialOverallState(t *testing.T) {
+	tests := []struct {
+		name         string
+		summary      StatusSummary
+		minSucceeded int
+		want         KeptnState
+	}{
+		{"enough succeeded", summaryOf(StateSucceeded, StateSucceeded, StateFailed), 2, StateSucceeded},
+		{"pending items are not waited for", summaryOf(StateSucceeded, StatePending), 1, StateSucceeded},
+		{"progressing items are waited for", summaryOf(StateSucceeded, StateProgressing), 1, StateProgressing},
+		{"too many failed", summaryOf(StateSucceeded, StateFailed, StateProgressing), 3, StateFailed},
+		{"not enough succeeded yet", summaryOf(StateSucceeded, StatePending, StatePending), 2, StatePending},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			testrequire.Equal(t, tt.want, GetPartialOverallState(tt.summary, tt.minSucceeded))
+		})
+	}
+}
+
+func TestGetOptionalOverallState(t *testing.T) {
+	testrequire.Equal(t, StateSucceeded, GetOptionalOverallState(summaryOf()))
+	testrequire.Equal(t, StateSucceeded, GetOptionalOverallState(summaryOf(StateSucceeded, StatePending)))
+	testrequire.Equal(t, StateProgressing, GetOptionalOverallState(summaryOf(StateFailed, StateProgressing)))
+	testrequire.Equal(t, StateWarning, GetOptionalOverallState(summaryOf(StateSucceeded, StateFailed)))
+	testrequire.True(t, StateWarning.IsSucceeded())
+	testrequire.True(t, StateWarning.IsCompleted())
+}
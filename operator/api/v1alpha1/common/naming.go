@@ -0,0 +1,80 @@
+package common
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// MaxK8sObjectLength is the maximum length of the names of Kubernetes objects
+const MaxK8sObjectLength = 253
+
+// InstanceIdLabel carries the hash of the full identity of a KeptnWorkloadInstance or KeptnAppVersion,
+// which stays stable even if the name of the instance has been truncated or sanitized
+const InstanceIdLabel = "keptn.sh/instance-id"
+
+// InstanceIdentityAnnotation carries the full identity of a KeptnWorkloadInstance or KeptnAppVersion in a readable form,
+// e.g. podtato-head/podtato-head-podtato-head-entry/0.1.0 for the app, workload and version of a workload instance
+const InstanceIdentityAnnotation = "keptn.sh/instance-identity"
+
+const identityHashLength = 10
+
+var invalidNameCharacters = regexp.MustCompile(`[^a-z0-9.-]+`)
+
+// CreateResourceName joins the parts of the identity of a resource to a valid object name.
+// Characters that are not allowed in object names are replaced by dashes. If the name had to be sanitized or
+// exceeds maxLength, it is truncated and suffixed with the hash of the identity, so that different identities never share a name.
+func CreateResourceName(maxLength int, parts ...string) string {
+	return createName(maxLength, false, parts...)
+}
+
+// CreateInstanceName returns the name of the KeptnWorkloadInstance or KeptnAppVersion of the given version of a
+// workload or app. A version containing a dash makes the name ambiguous, e.g. foo and 1-0 like foo-1 and 0, so that the
+// name is suffixed with the hash of the identity in this case, like names which had to be sanitized or truncated.
+func CreateInstanceName(name string, version string) string {
+	return createName(MaxK8sObjectLength, strings.Contains(version, "-"), name, version)
+}
+
+func createName(maxLength int, ambiguous bool, parts ...string) string {
+	name := strings.ToLower(strings.Join(parts, "-"))
+	sanitized := strings.Trim(invalidNameCharacters.ReplaceAllString(name, "-"), "-.")
+	if !ambiguous && sanitized == name && len(name) <= maxLength {
+		return name
+	}
+
+	hash := IdentityHash(parts...)
+	sanitized = strings.TrimRight(TruncateString(sanitized, maxLength-len(hash)-1), "-.")
+	if sanitized == "" {
+		return hash
+	}
+	return sanitized + "-" + hash
+}
+
+// Identity returns the parts of the identity of a resource joined by slashes, which is the value of the
+// InstanceIdentityAnnotation
+func Identity(parts ...string) string {
+	return strings.Join(parts, "/")
+}
+
+// IdentityHash returns a short deterministic hash of the parts of the identity of a resource, which is a valid label value
+func IdentityHash(parts ...string) string {
+	h := sha256.Sum256([]byte(Identity(parts...)))
+	return hex.EncodeToString(h[:])[:identityHashLength]
+}
+
+// VerifyIdentity returns a conflict error if the object retrieved by the name derived from the parts of an identity
+// carries the InstanceIdLabel of another identity. Objects without the label, which have been created before it has
+// been introduced, are not verified.
+func VerifyIdentity(obj metav1.Object, resource schema.GroupResource, parts ...string) error {
+	id, ok := obj.GetLabels()[InstanceIdLabel]
+	if !ok || id == IdentityHash(parts...) {
+		return nil
+	}
+	return errors.NewConflict(resource, obj.GetName(), fmt.Errorf("it belongs to instance %s, not to %s", id, Identity(parts...)))
+}
@@ -0,0 +1,67 @@
+package common
+
+import (
+	"strings"
+	"testing"
+
+	testrequire "github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+func TestCreateResourceName(t *testing.T) {
+	tests := []struct {
+		name  string
+		parts []string
+		want  string
+	}{
+		{name: "valid name is kept", parts: []string{"podtato-head", "0.1.0"}, want: "podtato-head-0.1.0"},
+		{name: "upper case is lowered", parts: []string{"PodTato", "1.0"}, want: "podtato-1.0"},
+		{name: "invalid characters", parts: []string{"podtato", "1.0+build.5"}, want: "podtato-1.0-build.5-" + IdentityHash("podtato", "1.0+build.5")},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			testrequire.Equal(t, tt.want, CreateResourceName(MaxK8sObjectLength, tt.parts...))
+		})
+	}
+}
+
+func TestCreateResourceName_NoCollisions(t *testing.T) {
+	plus := CreateResourceName(MaxK8sObjectLength, "podtato", "1.0+build")
+	dash := CreateResourceName(MaxK8sObjectLength, "podtato", "1.0-build")
+	testrequire.NotEqual(t, plus, dash)
+	testrequire.Equal(t, plus, CreateResourceName(MaxK8sObjectLength, "podtato", "1.0+build"))
+
+	long := strings.Repeat("a", 300)
+	first := CreateResourceName(MaxK8sObjectLength, long, "1.0")
+	second := CreateResourceName(MaxK8sObjectLength, long, "2.0")
+	testrequire.NotEqual(t, first, second)
+	for _, name := range []string{plus, first, second} {
+		testrequire.LessOrEqual(t, len(name), MaxK8sObjectLength)
+		testrequire.Empty(t, validation.IsDNS1123Subdomain(name))
+	}
+	testrequire.Empty(t, validation.IsValidLabelValue(IdentityHash(long, "1.0")))
+}
+
+func TestCreateInstanceName(t *testing.T) {
+	testrequire.Equal(t, "podtato-head-podtato-head-entry-0.1.0", CreateInstanceName("podtato-head-podtato-head-entry", "0.1.0"))
+
+	// foo-1 and 0 would share the name of foo and 1-0
+	dashed := CreateInstanceName("foo", "1-0")
+	testrequire.Equal(t, "foo-1-0-"+IdentityHash("foo", "1-0"), dashed)
+	testrequire.NotEqual(t, CreateInstanceName("foo-1", "0"), dashed)
+	testrequire.Empty(t, validation.IsDNS1123Subdomain(dashed))
+}
+
+func TestVerifyIdentity(t *testing.T) {
+	resource := schema.GroupResource{Group: "lifecycle.keptn.sh", Resource: "keptnworkloadinstances"}
+	instance := &metav1.ObjectMeta{Name: "foo-1-0", Labels: map[string]string{InstanceIdLabel: IdentityHash("foo-1", "0")}}
+
+	testrequire.Nil(t, VerifyIdentity(instance, resource, "foo-1", "0"))
+	testrequire.True(t, errors.IsConflict(VerifyIdentity(instance, resource, "foo", "1-0")))
+	// instances created before the label has been introduced are not verified
+	testrequire.Nil(t, VerifyIdentity(&metav1.ObjectMeta{Name: "foo-1-0"}, resource, "foo", "1-0"))
+	testrequire.Equal(t, "foo-1/0", Identity("foo-1", "0"))
+}
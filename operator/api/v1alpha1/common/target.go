@@ -0,0 +1,99 @@
+package common
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"time"
+)
+
+// TargetOperator is the comparison of an evaluation target
+type TargetOperator string
+
+const (
+	TargetLess           TargetOperator = "<"
+	TargetLessOrEqual    TargetOperator = "<="
+	TargetGreater        TargetOperator = ">"
+	TargetGreaterOrEqual TargetOperator = ">="
+)
+
+const TargetUnitPercent = "%"
+
+var targetExpression = regexp.MustCompile(`^\s*(<=|>=|<|>)\s*([+-]?(?:[0-9]+\.?[0-9]*|\.[0-9]+))\s*(ns|us|µs|ms|s|m|h|%)?\s*(vs\s+previous)?\s*$`)
+
+// Target is a parsed evaluation target like "< 500ms", ">= 99.5%" or "<= +10% vs previous"
+type Target struct {
+	Operator TargetOperator
+	// Value is the threshold normalized to the unit of the query result: durations are converted to seconds
+	// and absolute percentages to ratios. For relative percentages it is the allowed change in percent.
+	Value float64
+	// Unit is the unit the threshold has been written in, empty for plain numbers
+	Unit string
+	// Relative is set if the threshold is compared to the value of the previous version
+	Relative bool
+}
+
+// ParseTarget parses an evaluation target expression
+func ParseTarget(expression string) (Target, error) {
+	matches := targetExpression.FindStringSubmatch(expression)
+	if matches == nil {
+		return Target{}, fmt.Errorf("invalid evaluation target %q, expected e.g. \"< 500ms\", \">= 99.5%%\" or \"<= +10%% vs previous\"", expression)
+	}
+
+	value, err := strconv.ParseFloat(matches[2], 64)
+	if err != nil {
+		return Target{}, fmt.Errorf("invalid value in evaluation target %q: %w", expression, err)
+	}
+
+	target := Target{
+		Operator: TargetOperator(matches[1]),
+		Value:    value,
+		Unit:     matches[3],
+		Relative: matches[4] != "",
+	}
+
+	switch target.Unit {
+	case "":
+	case TargetUnitPercent:
+		if !target.Relative {
+			target.Value = value / 100
+		}
+	default:
+		unit, err := time.ParseDuration("1" + target.Unit)
+		if err != nil {
+			return Target{}, fmt.Errorf("invalid unit in evaluation target %q: %w", expression, err)
+		}
+		target.Value = value * unit.Seconds()
+	}
+	return target, nil
+}
+
+// Check compares the value to the target. The previous value is only used by relative targets.
+func (t Target) Check(value float64, previous float64) (bool, error) {
+	if math.IsNaN(value) {
+		return false, fmt.Errorf("value is not a number")
+	}
+
+	threshold := t.Value
+	if t.Relative {
+		if t.Unit == TargetUnitPercent {
+			threshold = previous + math.Abs(previous)*t.Value/100
+		} else {
+			threshold = previous + t.Value
+		}
+	}
+
+	switch t.Operator {
+	case TargetLess:
+		return value < threshold, nil
+	case TargetLessOrEqual:
+		return value <= threshold, nil
+	case TargetGreater:
+		return value > threshold, nil
+	case TargetGreaterOrEqual:
+		return value >= threshold, nil
+	default:
+		return false, fmt.Errorf("invalid operator %s", t.Operator)
+	}
+}
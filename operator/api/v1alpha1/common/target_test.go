@@ -0,0 +1,63 @@
+package common
+
+import (
+	"testing"
+
+	testrequire "github.com/stretchr/testify/require"
+)
+
+func TestParseTarget(t *testing.T) {
+	tests := []struct {
+		expression string
+		want       Target
+	}{
+		{expression: "<20", want: Target{Operator: TargetLess, Value: 20}},
+		{expression: ">4", want: Target{Operator: TargetGreater, Value: 4}},
+		{expression: "< 500ms", want: Target{Operator: TargetLess, Value: 0.5, Unit: "ms"}},
+		{expression: "<= 2m", want: Target{Operator: TargetLessOrEqual, Value: 120, Unit: "m"}},
+		{expression: ">= 99.5%", want: Target{Operator: TargetGreaterOrEqual, Value: 0.995, Unit: "%"}},
+		{expression: "<= +10% vs previous", want: Target{Operator: TargetLessOrEqual, Value: 10, Unit: "%", Relative: true}},
+		{expression: "> -0.5 vs previous", want: Target{Operator: TargetGreater, Value: -0.5, Relative: true}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.expression, func(t *testing.T) {
+			target, err := ParseTarget(tt.expression)
+			testrequire.Nil(t, err)
+			testrequire.Equal(t, tt.want.Operator, target.Operator)
+			testrequire.InDelta(t, tt.want.Value, target.Value, 1e-9)
+			testrequire.Equal(t, tt.want.Unit, target.Unit)
+			testrequire.Equal(t, tt.want.Relative, target.Relative)
+		})
+	}
+
+	for _, expression := range []string{"", "=5", "< 5kb", "<", "< 5 vs next"} {
+		_, err := ParseTarget(expression)
+		testrequire.NotNil(t, err, expression)
+	}
+}
+
+func TestTargetCheck(t *testing.T) {
+	tests := []struct {
+		expression string
+		value      float64
+		previous   float64
+		want       bool
+	}{
+		{expression: "< 500ms", value: 0.4, want: true},
+		{expression: "< 500ms", value: 0.5, want: false},
+		{expression: ">= 99.5%", value: 0.995, want: true},
+		{expression: ">= 99.5%", value: 0.99, want: false},
+		{expression: "<= +10% vs previous", value: 1.1, previous: 1, want: true},
+		{expression: "<= +10% vs previous", value: 1.2, previous: 1, want: false},
+		{expression: "< +50ms vs previous", value: 0.24, previous: 0.2, want: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.expression, func(t *testing.T) {
+			target, err := ParseTarget(tt.expression)
+			testrequire.Nil(t, err)
+			check, err := target.Check(tt.value, tt.previous)
+			testrequire.Nil(t, err)
+			testrequire.Equal(t, tt.want, check)
+		})
+	}
+}
@@ -0,0 +1,28 @@
+package common
+
+import (
+	"fmt"
+	"strings"
+
+	"golang.org/x/text/unicode/norm"
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+// NormalizeVersion normalizes the value of a version annotation, so that equal versions lead to the same instances:
+// compatible unicode characters are folded (e.g. full-width digits), surrounding whitespace and semver build metadata are removed
+// and the version is lowercased. Versions that are still no valid label value afterwards are rejected.
+func NormalizeVersion(version string) (string, error) {
+	normalized := strings.TrimSpace(norm.NFKC.String(version))
+	if i := strings.Index(normalized, "+"); i >= 0 {
+		normalized = normalized[:i]
+	}
+	normalized = strings.ToLower(normalized)
+
+	if normalized == "" {
+		return "", fmt.Errorf("invalid version %q: version must not be empty", version)
+	}
+	if errs := validation.IsValidLabelValue(normalized); len(errs) > 0 {
+		return "", fmt.Errorf("invalid version %q: %s", version, strings.Join(errs, ", "))
+	}
+	return normalized, nil
+}
@@ -0,0 +1,36 @@
+package common
+
+import (
+	"testing"
+
+	testrequire "github.com/stretchr/testify/require"
+)
+
+func TestNormalizeVersion(t *testing.T) {
+	tests := []struct {
+		version string
+		want    string
+		wantErr bool
+	}{
+		{version: "0.1.0", want: "0.1.0"},
+		{version: " v1.2.3-RC1 ", want: "v1.2.3-rc1"},
+		{version: "1.0.0+build.5", want: "1.0.0"},
+		{version: "１.０", want: "1.0"},
+		{version: "", wantErr: true},
+		{version: "+build", wantErr: true},
+		{version: "1.0 beta", wantErr: true},
+		{version: "1.0/2", wantErr: true},
+		{version: "-1.0", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.version, func(t *testing.T) {
+			got, err := NormalizeVersion(tt.version)
+			if tt.wantErr {
+				testrequire.NotNil(t, err)
+				return
+			}
+			testrequire.Nil(t, err)
+			testrequire.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -17,9 +17,10 @@ limitations under the License.
 package v1alpha1
 
 import (
-	"strings"
+	"github.com/keptn/lifecycle-controller/operator/api/v1alpha1/common"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
 )
 
 // EDIT THIS FILE!  THIS IS SCAFFOLDING FOR YOU TO OWN!
@@ -33,18 +34,125 @@ type KeptnAppSpec struct {
 	PostDeploymentTasks       []string           `json:"postDeploymentTasks,omitempty"`
 	PreDeploymentEvaluations  []string           `json:"preDeploymentEvaluations,omitempty"`
 	PostDeploymentEvaluations []string           `json:"postDeploymentEvaluations,omitempty"`
+	// InFlightChangePolicy defines how changes of the workloads, tasks and evaluations are handled while the version
+	// of the app is being deployed. Ignore keeps the KeptnAppVersion unchanged, while Rerender applies the changes to
+	// the phases of the KeptnAppVersion that have not completed yet.
+	// +optional
+	// +kubebuilder:default:=Ignore
+	// +kubebuilder:validation:Enum=Ignore;Rerender
+	InFlightChangePolicy InFlightChangePolicy `json:"inFlightChangePolicy,omitempty"`
+	// AllowPartialDeployment lets the deployment of the KeptnAppVersion succeed when only some of its workloads succeed,
+	// e.g. if the app bundles optional components that are not deployed to every cluster
+	// +optional
+	AllowPartialDeployment bool `json:"allowPartialDeployment,omitempty"`
+	// MinSucceededWorkloads is the number (e.g. 2) or percentage (e.g. 80%) of workloads that have to succeed
+	// if partial deployments are allowed. Defaults to 1.
+	// +optional
+	// +kubebuilder:validation:XIntOrString
+	// +kubebuilder:validation:Pattern=`^([0-9]+|[0-9]+%)$`
+	MinSucceededWorkloads *intstr.IntOrString `json:"minSucceededWorkloads,omitempty"`
+	// Timeouts define how long the phases of the deployment of the app may take before they fail
+	// +optional
+	Timeouts PhaseTimeouts `json:"timeouts,omitempty"`
+	// SimulateFailure forces the given phase of the app to fail without running its tasks, evaluations or checks
+	// +optional
+	// +kubebuilder:validation:Enum=pre-deployment;pre-deployment-evaluation;deployment;post-deployment;post-deployment-evaluation
+	SimulateFailure SimulatedFailure `json:"simulateFailure,omitempty"`
+	// RequireApproval lets the versions of the app wait after their pre-deployment evaluations until they are approved
+	// with the keptn.sh/approved-by annotation, e.g. as a promotion gate for production
+	// +optional
+	RequireApproval bool `json:"requireApproval,omitempty"`
+	// RollbackOnFailure restores all workloads of the app to their previous versions if the post-deployment evaluations
+	// of the app fail. It requires the Rollback feature gate of the operator.
+	// +optional
+	RollbackOnFailure bool `json:"rollbackOnFailure,omitempty"`
+	// AllowParallelVersions lets a version of the app start while earlier versions are still in progress. By default,
+	// a KeptnAppVersion waits until all earlier versions of the app have completed or have been aborted.
+	// +optional
+	AllowParallelVersions bool `json:"allowParallelVersions,omitempty"`
+	// SkipPhases are the app-level phases whose tasks and evaluations are not run, so that they succeed right away
+	// +optional
+	SkipPhases []SkippedPhase `json:"skipPhases,omitempty"`
 }
 
+// InFlightChangePolicy defines how changes of a KeptnApp are handled while its version is being deployed
+type InFlightChangePolicy string
+
+const (
+	// InFlightChangeIgnore keeps the workloads, tasks and evaluations of the KeptnAppVersion being deployed
+	InFlightChangeIgnore InFlightChangePolicy = "Ignore"
+	// InFlightChangeRerender applies the changes to the phases of the KeptnAppVersion that have not completed yet
+	InFlightChangeRerender InFlightChangePolicy = "Rerender"
+)
+
 // KeptnAppStatus defines the observed state of KeptnApp
 type KeptnAppStatus struct {
 	CurrentVersion string `json:"currentVersion,omitempty"`
+	// ContentHash is the hash of the semantic content of the spec the current KeptnAppVersion has been created from
+	ContentHash string `json:"contentHash,omitempty"`
+	// Conditions describe the state of the app, e.g. whether the referenced workloads and task and evaluation definitions exist
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+	// Drift lists the workloads whose running pods differ from the latest succeeded KeptnAppVersion
+	// +optional
+	Drift []WorkloadDrift `json:"drift,omitempty"`
 }
 
+// WorkloadDrift describes a workload whose running pods have not been deployed by the latest succeeded KeptnAppVersion,
+// e.g. because its image has been changed with kubectl
+type WorkloadDrift struct {
+	Workload string `json:"workload"`
+	// DeployedVersion is the version of the workload in the latest succeeded KeptnAppVersion
+	DeployedVersion string `json:"deployedVersion"`
+	// RunningVersions are the versions of the running pods of the workload
+	// +optional
+	RunningVersions []string `json:"runningVersions,omitempty"`
+	// RunningImages are the images of the running pods of the workload
+	// +optional
+	RunningImages []string `json:"runningImages,omitempty"`
+	// Reason is either VersionChanged, if pods of another version are running, ResourceReplaced, if the pods
+	// have the deployed version but do not belong to the deployed ReplicaSet, StatefulSet or DaemonSet, or NotRunning
+	Reason string `json:"reason"`
+}
+
+// DefinitionsResolved is the type of the condition indicating whether all task and evaluation definitions
+// referenced by a KeptnApp or KeptnWorkload exist
+const DefinitionsResolved = "DefinitionsResolved"
+
+// WorkloadsFound is the type of the condition indicating whether all workloads of a KeptnApp have a KeptnWorkload
+// or an annotated Deployment, StatefulSet or DaemonSet
+const WorkloadsFound = "WorkloadsFound"
+
+// VersionsInSync is the type of the condition indicating whether the running pods of all workloads of a KeptnApp have
+// been deployed by its latest succeeded KeptnAppVersion
+const VersionsInSync = "VersionsInSync"
+
 type KeptnWorkloadRef struct {
 	Name    string `json:"name"`
 	Version string `json:"version"`
+	// Criticality defines whether a failure of the workload fails the KeptnAppVersion (Critical, the default) or only
+	// results in a Warning state of the KeptnAppVersion (Optional)
+	// +optional
+	// +kubebuilder:validation:Enum=Critical;Optional
+	Criticality WorkloadCriticality `json:"criticality,omitempty"`
 }
 
+// WorkloadCriticality defines how a failure of a workload affects the KeptnAppVersion
+type WorkloadCriticality string
+
+const (
+	// WorkloadCritical workloads fail the KeptnAppVersion if they fail
+	WorkloadCritical WorkloadCriticality = "Critical"
+	// WorkloadOptional workloads only result in a Warning state of the KeptnAppVersion if they fail
+	WorkloadOptional WorkloadCriticality = "Optional"
+)
+
+// IsOptional returns whether a failure of the workload does not fail the KeptnAppVersion
+func (w KeptnWorkloadRef) IsOptional() bool {
+	return w.Criticality == WorkloadOptional
+}
+
+//+kubebuilder:rbac:groups=lifecycle.keptn.sh,resources=keptnapps;keptnapps/status,verbs=get;list;watch
+
 //+kubebuilder:object:root=true
 //+kubebuilder:subresource:status
 
@@ -71,5 +179,5 @@ func init() {
 }
 
 func (w KeptnApp) GetAppVersionName() string {
-	return strings.ToLower(w.Name + "-" + w.Spec.Version)
+	return common.CreateInstanceName(w.Name, w.Spec.Version)
 }
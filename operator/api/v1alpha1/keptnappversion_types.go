@@ -34,6 +34,10 @@ type KeptnAppVersionSpec struct {
 	PreviousVersion string `json:"previousVersion,omitempty"`
 
 	TraceId map[string]string `json:"traceId,omitempty"`
+	// Abort cancels the app version if it is still in progress: its running tasks and evaluations are deleted and
+	// its status is set to Cancelled
+	// +optional
+	Abort bool `json:"abort,omitempty"`
 }
 
 // KeptnAppVersionStatus defines the observed state of KeptnAppVersion
@@ -59,6 +63,43 @@ type KeptnAppVersionStatus struct {
 
 	StartTime metav1.Time `json:"startTime,omitempty"`
 	EndTime   metav1.Time `json:"endTime,omitempty"`
+	// ApprovalStatus is the state of the approval of the app version, if it requires one
+	ApprovalStatus common.KeptnState `json:"approvalStatus,omitempty"`
+	// Approver is the user who has approved or rejected the app version
+	Approver string `json:"approver,omitempty"`
+	// PhaseStartTime is the time the current phase has started
+	PhaseStartTime metav1.Time `json:"phaseStartTime,omitempty"`
+	// Reason describes why the app version has failed, e.g. since a phase has exceeded its timeout, or why it has not
+	// started yet
+	Reason string `json:"reason,omitempty"`
+	// Debug is the last decision of the reconciler, which is only recorded if the operator runs with --debug-status
+	// +optional
+	Debug *ReconcileDecision `json:"debug,omitempty"`
+	// Events are the last lifecycle events of the app version, which are only recorded if STATUS_EVENTS_LIMIT is set
+	// +optional
+	Events []LifecycleEvent `json:"events,omitempty"`
+	// IncidentKey is the key of the incident opened since the app version has failed its post-deployment evaluation,
+	// which is resolved once a later version of the app has been deployed successfully
+	// +optional
+	IncidentKey string `json:"incidentKey,omitempty"`
+}
+
+// LifecycleEvent is a copy of an event recorded for a resource, kept in its status for clients without access to
+// the Events API
+type LifecycleEvent struct {
+	// Type is the type of the event, i.e. Normal or Warning
+	Type string `json:"type"`
+	// Reason is the short reason of the event, e.g. AppPreDeployTasksFinished
+	Reason string `json:"reason"`
+	// Message is the message of the event
+	// +optional
+	Message string `json:"message,omitempty"`
+	// Count is the number of times the event has been recorded in a row
+	Count int32 `json:"count"`
+	// FirstTimestamp is the time the event has been recorded first
+	FirstTimestamp metav1.Time `json:"firstTimestamp"`
+	// LastTimestamp is the time the event has been recorded last
+	LastTimestamp metav1.Time `json:"lastTimestamp"`
 }
 
 type WorkloadStatus struct {
@@ -67,6 +108,8 @@ type WorkloadStatus struct {
 	Status common.KeptnState `json:"status,omitempty"`
 }
 
+//+kubebuilder:rbac:groups=lifecycle.keptn.sh,resources=keptnappversions;keptnappversions/status,verbs=get;list;watch
+
 //+kubebuilder:object:root=true
 //+kubebuilder:resource:path=keptnappversions,shortName=kav
 //+kubebuilder:subresource:status
@@ -125,6 +168,15 @@ func (v KeptnAppVersion) IsPreDeploymentEvaluationFailed() bool {
 	return v.Status.PreDeploymentEvaluationStatus.IsFailed()
 }
 
+// IsApproved returns whether the app version does not require an approval or has been approved
+func (v KeptnAppVersion) IsApproved() bool {
+	return !v.Spec.RequireApproval || v.Status.ApprovalStatus.IsSucceeded()
+}
+
+func (v KeptnAppVersion) IsApprovalFailed() bool {
+	return v.Status.ApprovalStatus.IsFailed()
+}
+
 func (v KeptnAppVersion) IsPostDeploymentCompleted() bool {
 	return v.Status.PostDeploymentStatus.IsCompleted()
 }
@@ -161,6 +213,28 @@ func (v KeptnAppVersion) AreWorkloadsFailed() bool {
 	return v.Status.WorkloadOverallStatus.IsFailed()
 }
 
+// HasFastPath returns whether the app consists of a single workload without app-level tasks and evaluations, like the
+// apps generated for workloads without app annotation. The app-level phases of such apps are skipped, unless the
+// failure of one of them is simulated or the app requires an approval.
+func (v KeptnAppVersion) HasFastPath() bool {
+	return len(v.Spec.Workloads) == 1 &&
+		len(v.Spec.PreDeploymentTasks) == 0 && len(v.Spec.PreDeploymentEvaluations) == 0 &&
+		len(v.Spec.PostDeploymentTasks) == 0 && len(v.Spec.PostDeploymentEvaluations) == 0 &&
+		v.Spec.SimulateFailure == "" && !v.Spec.RequireApproval
+}
+
+// HasStartedFastPath returns whether the app version is on the fast path and has started. App versions held back while
+// a previous version of the app is in progress have not started yet, so their workloads have to wait as well.
+func (v KeptnAppVersion) HasStartedFastPath() bool {
+	return v.HasFastPath() && v.Status.CurrentPhase != ""
+}
+
+// VerifyIdentity returns a conflict error if the app version retrieved by the name of the given app and version belongs
+// to another app or version, whose name collides with it
+func (v *KeptnAppVersion) VerifyIdentity(appName string, version string) error {
+	return common.VerifyIdentity(v, GroupVersion.WithResource("keptnappversions").GroupResource(), appName, version)
+}
+
 func (v *KeptnAppVersion) SetStartTime() {
 	if v.Status.StartTime.IsZero() {
 		v.Status.StartTime = metav1.NewTime(time.Now().UTC())
@@ -177,6 +251,11 @@ func (v *KeptnAppVersion) IsStartTimeSet() bool {
 	return !v.Status.StartTime.IsZero()
 }
 
+// IsAbortRequested returns whether the app version has been aborted with spec.abort or the keptn.sh/abort annotation
+func (v *KeptnAppVersion) IsAbortRequested() bool {
+	return v.Spec.Abort || v.Annotations[common.AbortAnnotation] == "true"
+}
+
 func (v *KeptnAppVersion) IsEndTimeSet() bool {
 	return !v.Status.EndTime.IsZero()
 }
@@ -186,6 +265,8 @@ func (v KeptnAppVersion) GetActiveMetricsAttributes() []attribute.KeyValue {
 		common.AppName.String(v.Spec.AppName),
 		common.AppVersion.String(v.Spec.Version),
 		common.AppNamespace.String(v.Namespace),
+		common.Environment.String(v.Labels[common.EnvironmentLabel]),
+		common.Namespace.String(v.Namespace),
 	}
 }
 
@@ -195,6 +276,8 @@ func (v KeptnAppVersion) GetMetricsAttributes() []attribute.KeyValue {
 		common.AppVersion.String(v.Spec.Version),
 		common.AppNamespace.String(v.Namespace),
 		common.AppStatus.String(string(v.Status.Status)),
+		common.Environment.String(v.Labels[common.EnvironmentLabel]),
+		common.Namespace.String(v.Namespace),
 	}
 }
 
@@ -203,5 +286,7 @@ func (v KeptnAppVersion) GetDurationMetricsAttributes() []attribute.KeyValue {
 		common.AppName.String(v.Spec.AppName),
 		common.AppVersion.String(v.Spec.Version),
 		common.AppPreviousVersion.String(v.Spec.PreviousVersion),
+		common.Environment.String(v.Labels[common.EnvironmentLabel]),
+		common.Namespace.String(v.Namespace),
 	}
 }
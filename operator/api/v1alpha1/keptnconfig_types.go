@@ -0,0 +1,117 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// EDIT THIS FILE!  THIS IS SCAFFOLDING FOR YOU TO OWN!
+// NOTE: json tags are required.  Any new fields you add must have json tags for the fields to be serialized.
+
+// KeptnConfigSpec defines the configuration of the operator
+type KeptnConfigSpec struct {
+	// Metrics configures the instruments the operator records its metrics with
+	// +optional
+	Metrics KeptnConfigMetrics `json:"metrics,omitempty"`
+	// Quotas limit the number of lifecycle resources in each namespace, so that runaway automation cannot flood the
+	// operator and the API server
+	// +optional
+	Quotas KeptnConfigQuotas `json:"quotas,omitempty"`
+}
+
+// KeptnConfigQuotas limit the number of lifecycle resources, which are checked when the resources are created
+type KeptnConfigQuotas struct {
+	// MaxActiveAppVersions is the maximum number of KeptnAppVersions in a namespace that have not completed yet.
+	// Further KeptnAppVersions are rejected. The number is not limited if 0.
+	// +optional
+	// +kubebuilder:validation:Minimum=0
+	MaxActiveAppVersions int `json:"maxActiveAppVersions,omitempty"`
+	// MaxTasksPerInstance is the maximum number of KeptnTasks of a single KeptnAppVersion or KeptnWorkloadInstance.
+	// Further KeptnTasks are rejected. The number is not limited if 0.
+	// +optional
+	// +kubebuilder:validation:Minimum=0
+	MaxTasksPerInstance int `json:"maxTasksPerInstance,omitempty"`
+}
+
+// KeptnConfigMetrics configures the names of the instruments and which of them are recorded
+type KeptnConfigMetrics struct {
+	// Prefix is prepended to the names of all instruments, e.g. the prefix acme records keptn.app.count as acme.keptn.app.count
+	// +optional
+	// +kubebuilder:validation:Pattern="^[a-zA-Z_][a-zA-Z0-9_.]*$"
+	Prefix string `json:"prefix,omitempty"`
+	// DisabledInstruments contains the names of the instruments that are not recorded, e.g. keptn.deployment.active.
+	// The names do not contain the prefix.
+	// +optional
+	DisabledInstruments []string `json:"disabledInstruments,omitempty"`
+	// Cardinality limits the values of high-cardinality attributes, so that each deployment does not create new series
+	// +optional
+	Cardinality KeptnConfigCardinality `json:"cardinality,omitempty"`
+}
+
+// KeptnConfigCardinality limits and normalizes the values of the attributes of the metrics
+type KeptnConfigCardinality struct {
+	// Attributes contains the keys of the attributes the limits are applied to.
+	// If empty, the limits are applied to the versions and previous versions of apps and workloads.
+	// +optional
+	Attributes []string `json:"attributes,omitempty"`
+	// MaxValueLength truncates longer values, e.g. long version strings. Values are not truncated if 0.
+	// +optional
+	// +kubebuilder:validation:Minimum=0
+	MaxValueLength int `json:"maxValueLength,omitempty"`
+	// ShortenCommitSHAs shortens values that are Git commit SHAs to their first 7 characters
+	// +optional
+	ShortenCommitSHAs bool `json:"shortenCommitSHAs,omitempty"`
+	// MaxValuesPerAttribute limits the number of distinct values recorded for each attribute. Further values are
+	// recorded as "other". The number of values is not limited if 0.
+	// +optional
+	// +kubebuilder:validation:Minimum=0
+	MaxValuesPerAttribute int `json:"maxValuesPerAttribute,omitempty"`
+}
+
+// KeptnConfigStatus defines the observed state of KeptnConfig
+type KeptnConfigStatus struct {
+}
+
+//+kubebuilder:rbac:groups=lifecycle.keptn.sh,resources=keptnconfigs;keptnconfigs/status,verbs=get;list;watch
+
+//+kubebuilder:object:root=true
+//+kubebuilder:subresource:status
+//+kubebuilder:resource:path=keptnconfigs,shortName=kc
+//+kubebuilder:printcolumn:name="Prefix",type=string,JSONPath=`.spec.metrics.prefix`
+
+// KeptnConfig is the Schema for the keptnconfigs API
+type KeptnConfig struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   KeptnConfigSpec   `json:"spec,omitempty"`
+	Status KeptnConfigStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// KeptnConfigList contains a list of KeptnConfig
+type KeptnConfigList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []KeptnConfig `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&KeptnConfig{}, &KeptnConfigList{})
+}
@@ -0,0 +1,101 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// DefinitionSourceLabel is set on the definitions created from a KeptnDefinitionSource and contains its name
+const DefinitionSourceLabel = "keptn.sh/definition-source"
+
+// DefinitionsSynced is the condition of a KeptnDefinitionSource reporting whether its artifact has been applied
+const DefinitionsSynced = "Synced"
+
+// KeptnDefinitionSourceSpec defines the OCI artifact the definitions are pulled from
+type KeptnDefinitionSourceSpec struct {
+	// URL is the OCI repository of the artifact, e.g. oci://ghcr.io/acme/keptn-gates
+	// +kubebuilder:validation:Pattern=`^oci://.+`
+	URL string `json:"url"`
+	// Tag is the tag of the artifact. It is ignored if a digest is set.
+	// +optional
+	// +kubebuilder:default:=latest
+	Tag string `json:"tag,omitempty"`
+	// Digest pins the artifact, e.g. sha256:4b825dc6.... If it is not set, the digest the tag refers to is used.
+	// +optional
+	// +kubebuilder:validation:Pattern=`^sha256:[a-f0-9]{64}$`
+	Digest string `json:"digest,omitempty"`
+	// Interval is the interval in which the tag is resolved again
+	// +optional
+	// +kubebuilder:default:="10m"
+	Interval metav1.Duration `json:"interval,omitempty"`
+	// SecretName is the name of a secret of type kubernetes.io/dockerconfigjson in the namespace of the source
+	// containing the credentials of the registry
+	// +optional
+	SecretName string `json:"secretName,omitempty"`
+	// Insecure allows pulling the artifact from a registry without TLS
+	// +optional
+	Insecure bool `json:"insecure,omitempty"`
+}
+
+// KeptnDefinitionSourceStatus defines the artifact the definitions have been applied from
+type KeptnDefinitionSourceStatus struct {
+	// Digest is the digest of the artifact the definitions have been applied from
+	// +optional
+	Digest string `json:"digest,omitempty"`
+	// Definitions are the definitions created from the artifact, e.g. KeptnTaskDefinition/slack-notification
+	// +optional
+	Definitions []string `json:"definitions,omitempty"`
+	// LastSyncTime is the time the artifact has been pulled the last time
+	// +optional
+	LastSyncTime *metav1.Time `json:"lastSyncTime,omitempty"`
+	// Conditions describe whether the artifact has been applied
+	// +optional
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+//+kubebuilder:rbac:groups=lifecycle.keptn.sh,resources=keptndefinitionsources;keptndefinitionsources/status,verbs=get;list;watch
+
+//+kubebuilder:object:root=true
+//+kubebuilder:subresource:status
+//+kubebuilder:resource:shortName=kds
+//+kubebuilder:printcolumn:name="URL",type=string,JSONPath=`.spec.url`
+//+kubebuilder:printcolumn:name="Digest",type=string,JSONPath=`.status.digest`
+//+kubebuilder:printcolumn:name="Synced",type=string,JSONPath=`.status.conditions[?(@.type=="Synced")].status`
+
+// KeptnDefinitionSource pulls KeptnTaskDefinitions and KeptnEvaluationDefinitions from an OCI artifact into its
+// namespace, so that a catalog of definitions can be versioned and distributed outside the cluster
+type KeptnDefinitionSource struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   KeptnDefinitionSourceSpec   `json:"spec,omitempty"`
+	Status KeptnDefinitionSourceStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// KeptnDefinitionSourceList contains a list of KeptnDefinitionSource
+type KeptnDefinitionSourceList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []KeptnDefinitionSource `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&KeptnDefinitionSource{}, &KeptnDefinitionSourceList{})
+}
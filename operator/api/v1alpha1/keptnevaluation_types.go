@@ -44,6 +44,9 @@ type KeptnEvaluationSpec struct {
 	RetryInterval metav1.Duration  `json:"retryInterval,omitempty"`
 	FailAction    string           `json:"failAction,omitempty"`
 	Type          common.CheckType `json:"checkType,omitempty"`
+	// Deadline is the point in time until the evaluation has to complete, derived from the deadline of its parent
+	// +optional
+	Deadline *metav1.Time `json:"deadline,omitempty"`
 }
 
 // KeptnEvaluationStatus defines the observed state of KeptnEvaluation
@@ -61,8 +64,17 @@ type EvaluationStatusItem struct {
 	Value   string            `json:"value"`
 	Status  common.KeptnState `json:"status"`
 	Message string            `json:"message,omitempty"`
+	// Query is the query that has been sent to the provider
+	Query string `json:"query,omitempty"`
+	// QueryStart and QueryEnd are the time range the query has been evaluated for. Both are equal for instant queries.
+	QueryStart metav1.Time `json:"queryStart,omitempty"`
+	QueryEnd   metav1.Time `json:"queryEnd,omitempty"`
+	// Response is the result returned by the provider, truncated to 1024 characters
+	Response string `json:"response,omitempty"`
 }
 
+//+kubebuilder:rbac:groups=lifecycle.keptn.sh,resources=keptnevaluations;keptnevaluations/status,verbs=get;list;watch
+
 //+kubebuilder:object:root=true
 //+kubebuilder:subresource:status
 //+kubebuilder:resource:path=keptnevaluations,shortName=ke
@@ -124,6 +136,9 @@ func (i KeptnEvaluation) GetActiveMetricsAttributes() []attribute.KeyValue {
 		common.WorkloadVersion.String(i.Spec.WorkloadVersion),
 		common.EvaluationName.String(i.Name),
 		common.EvaluationType.String(string(i.Spec.Type)),
+		common.Environment.String(i.Labels[common.EnvironmentLabel]),
+		common.Namespace.String(i.Namespace),
+		common.Phase.String(string(i.Spec.Type)),
 	}
 }
 
@@ -136,6 +151,9 @@ func (i KeptnEvaluation) GetMetricsAttributes() []attribute.KeyValue {
 		common.EvaluationName.String(i.Name),
 		common.EvaluationType.String(string(i.Spec.Type)),
 		common.EvaluationStatus.String(string(i.Status.OverallStatus)),
+		common.Environment.String(i.Labels[common.EnvironmentLabel]),
+		common.Namespace.String(i.Namespace),
+		common.Phase.String(string(i.Spec.Type)),
 	}
 }
 
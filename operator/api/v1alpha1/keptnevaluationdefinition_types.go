@@ -17,6 +17,10 @@ limitations under the License.
 package v1alpha1
 
 import (
+	"fmt"
+	"strconv"
+	"time"
+
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -30,9 +34,139 @@ type KeptnEvaluationDefinitionSpec struct {
 }
 
 type Objective struct {
-	Name             string `json:"name"`
-	Query            string `json:"query"`
-	EvaluationTarget string `json:"evaluationTarget"`
+	Name string `json:"name"`
+	// Query is run against the providers of the objective. It is not used if the objective reads a KeptnMetric.
+	Query string `json:"query,omitempty"`
+	// Secure hides the query and the responses of the providers in the status, logs and spans of the evaluations, e.g. if
+	// the query contains credentials.
+	// Values of well-known sensitive keys, such as tokens or passwords, are hidden in any query and response.
+	// +optional
+	Secure bool `json:"secure,omitempty"`
+	// KeptnMetric is the name of a KeptnMetric in the namespace of the evaluation whose cached value is compared to the
+	// evaluation target instead of running the query
+	KeptnMetric string `json:"keptnMetric,omitempty"`
+	// EvaluationTarget is the threshold the result of the query is compared to. It is not used by burn rate objectives.
+	EvaluationTarget string `json:"evaluationTarget,omitempty"`
+	// Sources lists the KeptnEvaluationProviders the query is run against instead of the source of the definition.
+	// The results of multiple providers are combined according to the QuorumPolicy.
+	Sources []string `json:"sources,omitempty"`
+	// +kubebuilder:validation:Enum=All;Any;Majority
+	// +kubebuilder:default:=All
+	QuorumPolicy QuorumPolicy `json:"quorumPolicy,omitempty"`
+	// Window is the time range before the evaluation the query is computed over. If not set, the current value is used.
+	// +optional
+	// +kubebuilder:validation:Pattern="^0|([0-9]+(\\.[0-9]+)?(ns|us|µs|ms|s|m|h))+$"
+	// +kubebuilder:validation:Type:=string
+	Window metav1.Duration `json:"window,omitempty"`
+	// Step is the resolution of the samples within the window. Defaults to a tenth of the window, and is at least 1s.
+	// +optional
+	// +kubebuilder:validation:Pattern="^0|([0-9]+(\\.[0-9]+)?(ns|us|µs|ms|s|m|h))+$"
+	// +kubebuilder:validation:Type:=string
+	Step metav1.Duration `json:"step,omitempty"`
+	// Aggregation defines how the samples within the window are combined to the value that is compared to the evaluation target
+	// +kubebuilder:validation:Enum=avg;min;max;p90;p95;p99
+	// +kubebuilder:default:=avg
+	Aggregation string `json:"aggregation,omitempty"`
+	// BurnRate evaluates the error budget burn rate of an SLO instead of comparing the result of the query to the evaluation target.
+	// The query has to return the ratio of failed requests within the time range {{.Window}}.
+	// +optional
+	BurnRate *BurnRate `json:"burnRate,omitempty"`
+}
+
+// BurnRate defines an SLO whose error budget must not be consumed faster than the maximum burn rate.
+// The objective fails if both the long and the short window exceed the maximum burn rate.
+type BurnRate struct {
+	// Target is the SLO in percent, e.g. 99.9
+	// +kubebuilder:validation:Pattern="^[0-9]+(\\.[0-9]+)?$"
+	Target string `json:"target"`
+	// Window is the time range of the SLO
+	// +optional
+	// +kubebuilder:default:="720h"
+	// +kubebuilder:validation:Pattern="^0|([0-9]+(\\.[0-9]+)?(ns|us|µs|ms|s|m|h))+$"
+	// +kubebuilder:validation:Type:=string
+	Window metav1.Duration `json:"window,omitempty"`
+	// +optional
+	// +kubebuilder:default:="1h"
+	// +kubebuilder:validation:Pattern="^0|([0-9]+(\\.[0-9]+)?(ns|us|µs|ms|s|m|h))+$"
+	// +kubebuilder:validation:Type:=string
+	LongWindow metav1.Duration `json:"longWindow,omitempty"`
+	// +optional
+	// +kubebuilder:default:="5m"
+	// +kubebuilder:validation:Pattern="^0|([0-9]+(\\.[0-9]+)?(ns|us|µs|ms|s|m|h))+$"
+	// +kubebuilder:validation:Type:=string
+	ShortWindow metav1.Duration `json:"shortWindow,omitempty"`
+	// MaxBurnRate is the highest allowed burn rate. Defaults to the burn rate which consumes 2% of the error budget
+	// within the long window, e.g. 14.4 for a window of 720h and a long window of 1h.
+	// +optional
+	// +kubebuilder:validation:Pattern="^[0-9]+(\\.[0-9]+)?$"
+	MaxBurnRate string `json:"maxBurnRate,omitempty"`
+}
+
+// GetTarget returns the SLO as ratio
+func (b BurnRate) GetTarget() (float64, error) {
+	target, err := strconv.ParseFloat(b.Target, 64)
+	if err != nil || target <= 0 || target >= 100 {
+		return 0, fmt.Errorf("invalid SLO target %q, expected a percentage between 0 and 100", b.Target)
+	}
+	return target / 100, nil
+}
+
+// GetWindows returns the long and the short window
+func (b BurnRate) GetWindows() (time.Duration, time.Duration) {
+	long, short := b.LongWindow.Duration, b.ShortWindow.Duration
+	if long <= 0 {
+		long = time.Hour
+	}
+	if short <= 0 {
+		short = 5 * time.Minute
+	}
+	return long, short
+}
+
+// GetMaxBurnRate returns the configured maximum burn rate or the burn rate which consumes 2% of the error budget within the long window
+func (b BurnRate) GetMaxBurnRate() (float64, error) {
+	if b.MaxBurnRate != "" {
+		maxBurnRate, err := strconv.ParseFloat(b.MaxBurnRate, 64)
+		if err != nil || maxBurnRate <= 0 {
+			return 0, fmt.Errorf("invalid maximum burn rate %q", b.MaxBurnRate)
+		}
+		return maxBurnRate, nil
+	}
+	window := b.Window.Duration
+	if window <= 0 {
+		window = 720 * time.Hour
+	}
+	long, _ := b.GetWindows()
+	return 0.02 * window.Hours() / long.Hours(), nil
+}
+
+// QuorumPolicy defines how many providers of an objective have to meet the evaluation target
+type QuorumPolicy string
+
+const (
+	QuorumAll      QuorumPolicy = "All"
+	QuorumAny      QuorumPolicy = "Any"
+	QuorumMajority QuorumPolicy = "Majority"
+)
+
+// IsReached returns whether the policy is met if the given number of providers out of total have passed
+func (p QuorumPolicy) IsReached(passed int, total int) bool {
+	switch p {
+	case QuorumAny:
+		return passed > 0
+	case QuorumMajority:
+		return passed*2 > total
+	default:
+		return passed == total
+	}
+}
+
+// GetSources returns the providers the objective is evaluated against
+func (o Objective) GetSources(defaultSource string) []string {
+	if len(o.Sources) == 0 {
+		return []string{defaultSource}
+	}
+	return o.Sources
 }
 
 // KeptnEvaluationDefinitionStatus defines the observed state of KeptnEvaluationDefinition
@@ -41,6 +175,8 @@ type KeptnEvaluationDefinitionStatus struct {
 	// Important: Run "make" to regenerate code after modifying this file
 }
 
+//+kubebuilder:rbac:groups=lifecycle.keptn.sh,resources=keptnevaluationdefinitions;keptnevaluationdefinitions/status,verbs=get;list;watch
+
 //+kubebuilder:object:root=true
 //+kubebuilder:subresource:status
 //+kubebuilder:resource:path=keptnevaluationdefinitions,shortName=ked
@@ -17,6 +17,8 @@ limitations under the License.
 package v1alpha1
 
 import (
+	"github.com/keptn/lifecycle-controller/operator/api/v1alpha1/common"
+	"go.opentelemetry.io/otel/attribute"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -25,19 +27,51 @@ import (
 
 // KeptnEvaluationProviderSpec defines the desired state of KeptnEvaluationProvider
 type KeptnEvaluationProviderSpec struct {
-	TargetServer string `json:"targetServer"`
-	SecretName   string `json:"secretName,omitempty"`
+	// Type is the type of the provider, which determines the API the queries are sent to. Prometheus providers run PromQL
+	// queries, Dynatrace providers run metric selectors against the metrics API v2 of the environment at the target server.
+	// +optional
+	// +kubebuilder:validation:Enum=prometheus;dynatrace
+	// +kubebuilder:default:=prometheus
+	Type         ProviderType `json:"type,omitempty"`
+	TargetServer string       `json:"targetServer"`
+	// SecretName is the name of the secret in the namespace of the provider containing its credentials, either a token or
+	// a user and password. Dynatrace providers require the API token with the metrics.read scope in the key token.
+	SecretName string `json:"secretName,omitempty"`
 }
 
+// ProviderType is the type of a KeptnEvaluationProvider
+type ProviderType string
+
+const (
+	// ProviderTypePrometheus is the type of providers without a type
+	ProviderTypePrometheus ProviderType = "prometheus"
+	ProviderTypeDynatrace  ProviderType = "dynatrace"
+)
+
 // KeptnEvaluationProviderStatus defines the observed state of KeptnEvaluationProvider
 type KeptnEvaluationProviderStatus struct {
-	// INSERT ADDITIONAL STATUS FIELD - define observed state of cluster
-	// Important: Run "make" to regenerate code after modifying this file
+	// Conditions describe the state of the provider, e.g. whether the operator can authenticate against it
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+	// LastAuthenticationTime is the last time the operator successfully authenticated against the provider
+	LastAuthenticationTime *metav1.Time `json:"lastAuthenticationTime,omitempty"`
+	// LastProbeTime is the last time the connectivity of the provider has been checked
+	LastProbeTime *metav1.Time `json:"lastProbeTime,omitempty"`
 }
 
+// ProviderAuthenticated is the type of the condition indicating whether the credentials of the provider are valid
+const ProviderAuthenticated = "Authenticated"
+
+// ProviderReachable is the type of the condition indicating whether the provider answered the last connectivity probe
+const ProviderReachable = "Reachable"
+
+//+kubebuilder:rbac:groups=lifecycle.keptn.sh,resources=keptnevaluationproviders;keptnevaluationproviders/status,verbs=get;list;watch
+
 //+kubebuilder:object:root=true
 //+kubebuilder:subresource:status
 //+kubebuilder:resource:path=keptnevaluationproviders,shortName=kep
+//+kubebuilder:printcolumn:name="Type",type=string,JSONPath=`.spec.type`
+//+kubebuilder:printcolumn:name="TargetServer",type=string,JSONPath=`.spec.targetServer`
+//+kubebuilder:printcolumn:name="Reachable",type=string,JSONPath=`.status.conditions[?(@.type=="Reachable")].status`
 
 // KeptnEvaluationProvider is the Schema for the keptnevaluationproviders API
 type KeptnEvaluationProvider struct {
@@ -60,3 +94,16 @@ type KeptnEvaluationProviderList struct {
 func init() {
 	SchemeBuilder.Register(&KeptnEvaluationProvider{}, &KeptnEvaluationProviderList{})
 }
+
+// IsDynatrace returns whether the queries of the provider are sent to the metrics API of Dynatrace
+func (p KeptnEvaluationProvider) IsDynatrace() bool {
+	return p.Spec.Type == ProviderTypeDynatrace
+}
+
+func (p KeptnEvaluationProvider) GetMetricsAttributes() []attribute.KeyValue {
+	return []attribute.KeyValue{
+		common.ProviderName.String(p.Name),
+		common.ProviderNamespace.String(p.Namespace),
+		common.Namespace.String(p.Namespace),
+	}
+}
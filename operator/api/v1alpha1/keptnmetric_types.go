@@ -0,0 +1,79 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// EDIT THIS FILE!  THIS IS SCAFFOLDING FOR YOU TO OWN!
+// NOTE: json tags are required.  Any new fields you add must have json tags for the fields to be serialized.
+
+// KeptnMetricSpec defines the desired state of KeptnMetric
+type KeptnMetricSpec struct {
+	// Provider is the name of the KeptnEvaluationProvider the query is run against
+	Provider string `json:"provider"`
+	// Query is the query whose result is stored in the status of the metric. It has to return a single value.
+	Query string `json:"query"`
+	// FetchInterval is the interval in which the value is fetched from the provider
+	// +optional
+	// +kubebuilder:default:="30s"
+	// +kubebuilder:validation:Pattern="^0|([0-9]+(\\.[0-9]+)?(ns|us|µs|ms|s|m|h))+$"
+	// +kubebuilder:validation:Type:=string
+	FetchInterval metav1.Duration `json:"fetchInterval,omitempty"`
+}
+
+// KeptnMetricStatus defines the observed state of KeptnMetric
+type KeptnMetricStatus struct {
+	// Value is the latest value returned by the provider
+	Value string `json:"value,omitempty"`
+	// LastUpdated is the time the value has been fetched
+	LastUpdated metav1.Time `json:"lastUpdated,omitempty"`
+	// Message describes why the last fetch failed, empty if it succeeded
+	Message string `json:"message,omitempty"`
+}
+
+//+kubebuilder:rbac:groups=lifecycle.keptn.sh,resources=keptnmetrics;keptnmetrics/status,verbs=get;list;watch
+
+//+kubebuilder:object:root=true
+//+kubebuilder:subresource:status
+//+kubebuilder:resource:path=keptnmetrics,shortName=km
+//+kubebuilder:printcolumn:name="Provider",type=string,JSONPath=`.spec.provider`
+//+kubebuilder:printcolumn:name="Query",type=string,JSONPath=`.spec.query`
+//+kubebuilder:printcolumn:name="Value",type=string,JSONPath=`.status.value`
+
+// KeptnMetric is the Schema for the keptnmetrics API
+type KeptnMetric struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   KeptnMetricSpec   `json:"spec,omitempty"`
+	Status KeptnMetricStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// KeptnMetricList contains a list of KeptnMetric
+type KeptnMetricList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []KeptnMetric `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&KeptnMetric{}, &KeptnMetricList{})
+}
@@ -0,0 +1,86 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// KeptnNamespaceStatusStatus summarizes whether the lifecycle of the workloads of a namespace is managed by Keptn
+type KeptnNamespaceStatusStatus struct {
+	// WebhookActive is true if the namespace is annotated with keptn.sh/lifecycle-controller: enabled and the
+	// pod mutating webhook of the operator is registered
+	WebhookActive bool `json:"webhookActive"`
+	// SchedulerGating is true if the Keptn scheduler is ready to hold back the pods of the workloads until their
+	// pre-deployment checks have succeeded
+	SchedulerGating bool `json:"schedulerGating"`
+	// Apps is the number of KeptnApps of the namespace
+	Apps int `json:"apps"`
+	// Workloads is the number of KeptnWorkloads of the namespace
+	Workloads int `json:"workloads"`
+	// ActiveAppVersions is the number of KeptnAppVersions of the namespace that are being deployed
+	ActiveAppVersions int `json:"activeAppVersions"`
+	// ActiveWorkloadInstances is the number of KeptnWorkloadInstances of the namespace that are being deployed
+	ActiveWorkloadInstances int `json:"activeWorkloadInstances"`
+	// LastErrors are the most recent warnings of the Keptn resources of the namespace
+	// +optional
+	LastErrors []NamespaceError `json:"lastErrors,omitempty"`
+	// LastUpdated is the time the status has been updated
+	LastUpdated metav1.Time `json:"lastUpdated,omitempty"`
+}
+
+// NamespaceError is a warning event of a Keptn resource
+type NamespaceError struct {
+	// Object is the kind and name of the resource, e.g. KeptnTask/pre-deployment-check-1234
+	Object  string      `json:"object"`
+	Reason  string      `json:"reason"`
+	Message string      `json:"message,omitempty"`
+	Time    metav1.Time `json:"time,omitempty"`
+}
+
+//+kubebuilder:rbac:groups=lifecycle.keptn.sh,resources=keptnnamespacestatuses;keptnnamespacestatuses/status,verbs=get;list;watch
+
+//+kubebuilder:object:root=true
+//+kubebuilder:subresource:status
+//+kubebuilder:resource:path=keptnnamespacestatuses,shortName=kns,scope=Cluster
+//+kubebuilder:printcolumn:name="Webhook",type=boolean,JSONPath=`.status.webhookActive`
+//+kubebuilder:printcolumn:name="Scheduler",type=boolean,JSONPath=`.status.schedulerGating`
+//+kubebuilder:printcolumn:name="Workloads",type=integer,JSONPath=`.status.workloads`
+//+kubebuilder:printcolumn:name="ActiveWorkloads",type=integer,JSONPath=`.status.activeWorkloadInstances`
+//+kubebuilder:printcolumn:name="LastError",type=string,JSONPath=`.status.lastErrors[0].reason`
+
+// KeptnNamespaceStatus is an informational resource created by the operator for each namespace that is enabled for
+// the Lifecycle Controller. It has the name of the namespace.
+type KeptnNamespaceStatus struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Status KeptnNamespaceStatusStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// KeptnNamespaceStatusList contains a list of KeptnNamespaceStatus
+type KeptnNamespaceStatusList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []KeptnNamespaceStatus `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&KeptnNamespaceStatus{}, &KeptnNamespaceStatusList{})
+}
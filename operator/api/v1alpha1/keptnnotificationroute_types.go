@@ -0,0 +1,108 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// NotificationSeverity is the severity of a lifecycle event. Warning events, such as failed phases, have the
+// severity Warning, all other events the severity Info.
+// +kubebuilder:validation:Enum=Info;Warning
+type NotificationSeverity string
+
+const (
+	NotificationSeverityInfo    NotificationSeverity = "Info"
+	NotificationSeverityWarning NotificationSeverity = "Warning"
+)
+
+// NotificationSinkType is the kind of system a notification is sent to
+// +kubebuilder:validation:Enum=Slack;PagerDuty;Webhook
+type NotificationSinkType string
+
+const (
+	NotificationSinkSlack     NotificationSinkType = "Slack"
+	NotificationSinkPagerDuty NotificationSinkType = "PagerDuty"
+	NotificationSinkWebhook   NotificationSinkType = "Webhook"
+)
+
+// KeptnNotificationRouteSpec defines which events are sent to which sinks
+type KeptnNotificationRouteSpec struct {
+	// Match selects the events that are sent to the sinks of the route. An empty match selects all events.
+	// +optional
+	Match NotificationMatch `json:"match,omitempty"`
+	// Sinks receive the selected events
+	// +kubebuilder:validation:MinItems=1
+	Sinks []NotificationSink `json:"sinks"`
+}
+
+// NotificationMatch selects events. All of its conditions have to be met.
+type NotificationMatch struct {
+	// Namespaces restricts the route to the events of objects in these namespaces
+	// +optional
+	Namespaces []string `json:"namespaces,omitempty"`
+	// AppSelector restricts the route to the events of the KeptnApps with matching labels and of their workloads,
+	// tasks and evaluations
+	// +optional
+	AppSelector *metav1.LabelSelector `json:"appSelector,omitempty"`
+	// Severities restricts the route to events of these severities
+	// +optional
+	Severities []NotificationSeverity `json:"severities,omitempty"`
+	// Reasons restricts the route to events with these reasons, e.g. AppDeployFailed
+	// +optional
+	Reasons []string `json:"reasons,omitempty"`
+}
+
+// NotificationSink is a system the selected events are sent to
+type NotificationSink struct {
+	Type NotificationSinkType `json:"type"`
+	// SecretName is the name of a secret in the namespace of the operator. Slack and Webhook sinks read the URL
+	// from its url key, PagerDuty sinks the routing key of the service from its routingKey key.
+	SecretName string `json:"secretName"`
+	// Channel overrides the channel of a Slack incoming webhook, e.g. #deployments
+	// +optional
+	Channel string `json:"channel,omitempty"`
+}
+
+//+kubebuilder:rbac:groups=lifecycle.keptn.sh,resources=keptnnotificationroutes,verbs=get;list;watch
+
+//+kubebuilder:object:root=true
+//+kubebuilder:resource:path=keptnnotificationroutes,shortName=knr,scope=Cluster
+//+kubebuilder:printcolumn:name="Namespaces",type=string,JSONPath=`.spec.match.namespaces`
+//+kubebuilder:printcolumn:name="Severities",type=string,JSONPath=`.spec.match.severities`
+//+kubebuilder:printcolumn:name="Sink",type=string,JSONPath=`.spec.sinks[0].type`
+
+// KeptnNotificationRoute routes the lifecycle events recorded by the operator to notification sinks
+type KeptnNotificationRoute struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec KeptnNotificationRouteSpec `json:"spec,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// KeptnNotificationRouteList contains a list of KeptnNotificationRoute
+type KeptnNotificationRouteList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []KeptnNotificationRoute `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&KeptnNotificationRoute{}, &KeptnNotificationRouteList{})
+}
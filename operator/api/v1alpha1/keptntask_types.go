@@ -38,6 +38,24 @@ type KeptnTaskSpec struct {
 	Parameters       TaskParameters   `json:"parameters,omitempty"`
 	SecureParameters SecureParameters `json:"secureParameters,omitempty"`
 	Type             common.CheckType `json:"checkType,omitempty"`
+	// Deadline is the point in time until the task has to complete, derived from the deadline of its parent
+	// +optional
+	Deadline *metav1.Time `json:"deadline,omitempty"`
+	// Retries is the number of times a failed Job of the task is started again, overriding the retries of its definition
+	// +optional
+	// +kubebuilder:validation:Minimum=0
+	Retries *int32 `json:"retries,omitempty"`
+	// Backoff is the delay before the first retry of a failed Job, overriding the backoff of its definition
+	// +optional
+	Backoff *metav1.Duration `json:"backoff,omitempty"`
+	// Timeout is the time a Job of the task may run before it is terminated, overriding the timeout of its definition
+	// +optional
+	Timeout *metav1.Duration `json:"timeout,omitempty"`
+	// ExactlyOnce guarantees that a single Job is started for the task, even if several replicas of the operator
+	// reconcile it at the same time. The Job is only created while holding a Lease of the task, failed Jobs are not
+	// retried, and the task fails if its Job has been lost.
+	// +optional
+	ExactlyOnce bool `json:"exactlyOnce,omitempty"`
 }
 
 type TaskContext struct {
@@ -64,10 +82,47 @@ type KeptnTaskStatus struct {
 	Status    common.KeptnState `json:"status,omitempty"`
 	StartTime metav1.Time       `json:"startTime,omitempty"`
 	EndTime   metav1.Time       `json:"endTime,omitempty"`
+	// Reason explains why a pending task has not started its Job yet, e.g. because the maximum number of running
+	// task Jobs in the namespace has been reached, or when a failed Job is started again
+	// +optional
+	Reason string `json:"reason,omitempty"`
+	// Attempts is the number of Jobs started for the task, including retries of failed Jobs
+	// +optional
+	Attempts int32 `json:"attempts,omitempty"`
+	// Lock is the state of the Lease guarding the creation of the Job of an exactly-once task
+	// +optional
+	Lock *TaskLockStatus `json:"lock,omitempty"`
 	// INSERT ADDITIONAL STATUS FIELD - define observed state of cluster
 	// Important: Run "make" to regenerate code after modifying this file
 }
 
+// TaskLockStatus is the state of the Lease of an exactly-once task
+type TaskLockStatus struct {
+	// LeaseName is the name of the Lease in the namespace of the task
+	LeaseName string `json:"leaseName"`
+	// Holder is the identity of the operator replica which holds or has last held the Lease
+	// +optional
+	Holder string `json:"holder,omitempty"`
+	// State is Waiting while another replica holds the Lease, Acquired while the Job is created and Released once
+	// the Job has been created
+	// +kubebuilder:validation:Enum=Waiting;Acquired;Released
+	State TaskLockState `json:"state"`
+	// TransitionTime is the time the state has last changed
+	// +optional
+	TransitionTime metav1.Time `json:"transitionTime,omitempty"`
+}
+
+// TaskLockState is the state of the Lease of an exactly-once task
+type TaskLockState string
+
+const (
+	TaskLockWaiting  TaskLockState = "Waiting"
+	TaskLockAcquired TaskLockState = "Acquired"
+	TaskLockReleased TaskLockState = "Released"
+)
+
+//+kubebuilder:rbac:groups=lifecycle.keptn.sh,resources=keptntasks;keptntasks/status,verbs=get;list;watch
+
 //+kubebuilder:object:root=true
 //+kubebuilder:subresource:status
 // +kubebuilder:printcolumn:name="AppName",type=string,JSONPath=`.spec.app`
@@ -76,6 +131,7 @@ type KeptnTaskStatus struct {
 // +kubebuilder:printcolumn:name="WorkloadVersion",type=string,JSONPath=`.spec.workloadVersion`
 // +kubebuilder:printcolumn:name="Job Name",type=string,JSONPath=`.status.jobName`
 // +kubebuilder:printcolumn:name="Status",type=string,JSONPath=`.status.status`
+// +kubebuilder:printcolumn:name="Lock",priority=1,type=string,JSONPath=`.status.lock.state`
 
 // KeptnTask is the Schema for the keptntasks API
 type KeptnTask struct {
@@ -127,6 +183,9 @@ func (i KeptnTask) GetActiveMetricsAttributes() []attribute.KeyValue {
 		common.WorkloadVersion.String(i.Spec.WorkloadVersion),
 		common.TaskName.String(i.Name),
 		common.TaskType.String(string(i.Spec.Type)),
+		common.Environment.String(i.Labels[common.EnvironmentLabel]),
+		common.Namespace.String(i.Namespace),
+		common.Phase.String(string(i.Spec.Type)),
 	}
 }
 
@@ -139,5 +198,8 @@ func (i KeptnTask) GetMetricsAttributes() []attribute.KeyValue {
 		common.TaskName.String(i.Name),
 		common.TaskType.String(string(i.Spec.Type)),
 		common.TaskStatus.String(string(i.Status.Status)),
+		common.Environment.String(i.Labels[common.EnvironmentLabel]),
+		common.Namespace.String(i.Namespace),
+		common.Phase.String(string(i.Spec.Type)),
 	}
 }
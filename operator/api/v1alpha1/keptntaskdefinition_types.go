@@ -17,6 +17,7 @@ limitations under the License.
 package v1alpha1
 
 import (
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -25,9 +26,52 @@ import (
 
 // KeptnTaskDefinitionSpec defines the desired state of KeptnTaskDefinition
 type KeptnTaskDefinitionSpec struct {
-	Function FunctionSpec `json:"function,omitempty"`
+	// Type is the type of the task. Tasks of type function execute the function in a Job, tasks of type wait
+	// succeed once their duration has passed, without starting a Job.
+	// +optional
+	// +kubebuilder:validation:Enum=function;wait
+	Type TaskType `json:"type,omitempty"`
+	// Duration is the time a task of type wait takes, e.g. 5m. If it is not set, the task succeeds immediately.
+	// +optional
+	Duration *metav1.Duration `json:"duration,omitempty"`
+	Function FunctionSpec     `json:"function,omitempty"`
+	// Python executes a Python script instead of a Deno function. The script is resolved like the code of a function,
+	// i.e. inline, from a ConfigMap, from an URL or from the task definition it references, and executed by the
+	// image of the Python runner. Vendored dependencies are not supported.
+	// +optional
+	Python *FunctionSpec `json:"python,omitempty"`
+	// Container runs the task in a container of the given image instead of executing a function with the Deno runtime.
+	// The context of the task is passed to the container in environment variables.
+	// +optional
+	Container *ContainerSpec `json:"container,omitempty"`
+	// Retries is the number of times a failed Job of a task is started again. Failed Jobs are not retried by default.
+	// +optional
+	// +kubebuilder:validation:Minimum=0
+	Retries *int32 `json:"retries,omitempty"`
+	// Backoff is the delay before the first retry of a failed Job, which is doubled with each further retry. Defaults to 10s.
+	// +optional
+	Backoff *metav1.Duration `json:"backoff,omitempty"`
+	// Timeout is the time a Job of a task may run, e.g. 10m, before it is terminated and the task fails, unless it has
+	// retries left. Jobs are not limited by default, apart from the deadline of the task.
+	// +optional
+	Timeout *metav1.Duration `json:"timeout,omitempty"`
+	// DependsOn are the names of the task definitions whose tasks have to succeed before a task of this definition is
+	// started. Only the task definitions of the same pre- or post-deployment check are taken into account, the
+	// remaining tasks of the check run in parallel.
+	// +optional
+	DependsOn []string `json:"dependsOn,omitempty"`
 }
 
+// TaskType is the type of a task definition
+type TaskType string
+
+const (
+	// TaskTypeFunction tasks execute a function in a Job. It is the type of task definitions without a type.
+	TaskTypeFunction TaskType = "function"
+	// TaskTypeWait tasks wait for a fixed duration
+	TaskTypeWait TaskType = "wait"
+)
+
 type FunctionSpec struct {
 	FunctionReference  FunctionReference  `json:"functionRef,omitempty"`
 	Inline             Inline             `json:"inline,omitempty"`
@@ -35,6 +79,65 @@ type FunctionSpec struct {
 	ConfigMapReference ConfigMapReference `json:"configMapRef,omitempty"`
 	Parameters         TaskParameters     `json:"parameters,omitempty"`
 	SecureParameters   SecureParameters   `json:"secureParameters,omitempty"`
+	// Runner overrides the image running the function, e.g. to use a mirror of the runner image in an internal registry
+	// +optional
+	Runner RunnerSpec `json:"runner,omitempty"`
+	// Dependencies provides the remote modules imported by the function from a vendored bundle, so that they are not
+	// fetched when the function is executed
+	// +optional
+	Dependencies FunctionDependencies `json:"dependencies,omitempty"`
+	// Identity configures the cloud workload identity of the pod running the function, so that the function can call
+	// AWS, GCP or Azure APIs without long-lived secrets
+	// +optional
+	Identity FunctionIdentity `json:"identity,omitempty"`
+}
+
+// FunctionIdentity defines the service account and the metadata of the pod running a function, which the cloud provider
+// uses to grant the pod a workload identity, e.g. IAM roles for service accounts on EKS or Workload Identity on GKE
+type FunctionIdentity struct {
+	// ServiceAccountName is the service account in the namespace of the task the pod runs as, e.g. one annotated with
+	// eks.amazonaws.com/role-arn, iam.gke.io/gcp-service-account or azure.workload.identity/client-id.
+	// If not set, the pod runs as the default service account of the namespace.
+	// +optional
+	// +kubebuilder:validation:Pattern=`^[a-z0-9]([-a-z0-9.]*[a-z0-9])?$`
+	ServiceAccountName string `json:"serviceAccountName,omitempty"`
+	// PodLabels are added to the pod, e.g. azure.workload.identity/use: "true"
+	// +optional
+	PodLabels map[string]string `json:"podLabels,omitempty"`
+	// PodAnnotations are added to the pod, e.g. eks.amazonaws.com/sts-regional-endpoints: "true"
+	// +optional
+	PodAnnotations map[string]string `json:"podAnnotations,omitempty"`
+}
+
+// FunctionDependencies defines a bundle of the remote modules imported by a function, created with `deno vendor`.
+// The bundle is either a ConfigMap or an image, both of which have to be pinned by their digest.
+type FunctionDependencies struct {
+	// ConfigMapRef references a ConfigMap in the namespace of the task containing the vendor directory as a
+	// gzipped tarball in the binary data key vendor.tar.gz
+	// +optional
+	ConfigMapRef ConfigMapReference `json:"configMapRef,omitempty"`
+	// Digest is the sha256 digest of the tarball of the ConfigMap, e.g. sha256:4b825dc6...
+	// +optional
+	// +kubebuilder:validation:Pattern=`^sha256:[a-f0-9]{64}$`
+	Digest string `json:"digest,omitempty"`
+	// Image is an image containing the vendor directory in /vendor, referenced by its digest, e.g.
+	// registry.internal/functions/slack-deps@sha256:4b825dc6.... The image has to contain a cp command.
+	// +optional
+	Image string `json:"image,omitempty"`
+	// NoRemote disallows fetching remote modules when the function is executed, so that all imports have to be
+	// resolved from the bundle. Functions referenced by an httpRef cannot be executed without fetching them.
+	// +optional
+	NoRemote bool `json:"noRemote,omitempty"`
+}
+
+// RunnerSpec defines the image of the container running a function
+type RunnerSpec struct {
+	// Image is the image of the function runner. If not set, the image configured for the namespace or the operator is used.
+	// +optional
+	Image string `json:"image,omitempty"`
+	// ImagePullSecrets are the secrets in the namespace of the task used to pull the image
+	// +optional
+	ImagePullSecrets []corev1.LocalObjectReference `json:"imagePullSecrets,omitempty"`
 }
 
 type ConfigMapReference struct {
@@ -53,7 +156,23 @@ type HttpReference struct {
 	Url string `json:"url,omitempty"`
 }
 
+// ContainerSpec defines the container running a task
 type ContainerSpec struct {
+	// Image is the image of the container, e.g. curlimages/curl:8.1.2
+	// +kubebuilder:validation:MinLength=1
+	Image string `json:"image"`
+	// Command overrides the entrypoint of the image
+	// +optional
+	Command []string `json:"command,omitempty"`
+	// Args are the arguments of the command
+	// +optional
+	Args []string `json:"args,omitempty"`
+	// Env are additional environment variables of the container, which take precedence over the ones set by Keptn
+	// +optional
+	Env map[string]string `json:"env,omitempty"`
+	// ImagePullSecrets are the secrets in the namespace of the task used to pull the image
+	// +optional
+	ImagePullSecrets []corev1.LocalObjectReference `json:"imagePullSecrets,omitempty"`
 }
 
 // KeptnTaskDefinitionStatus defines the observed state of KeptnTaskDefinition
@@ -69,6 +188,8 @@ type FunctionStatus struct {
 	ConfigMap string `json:"configMap,omitempty"`
 }
 
+//+kubebuilder:rbac:groups=lifecycle.keptn.sh,resources=keptntaskdefinitions;keptntaskdefinitions/status,verbs=get;list;watch
+
 //+kubebuilder:object:root=true
 //+kubebuilder:subresource:status
 
@@ -17,7 +17,7 @@ limitations under the License.
 package v1alpha1
 
 import (
-	"strings"
+	"github.com/keptn/lifecycle-controller/operator/api/v1alpha1/common"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
@@ -35,13 +35,147 @@ type KeptnWorkloadSpec struct {
 	PreDeploymentEvaluations  []string          `json:"preDeploymentEvaluations,omitempty"`
 	PostDeploymentEvaluations []string          `json:"postDeploymentEvaluations,omitempty"`
 	ResourceReference         ResourceReference `json:"resourceReference"`
+	// MigrationTasks are the task definitions run between the pre-deployment checks and the deployment, e.g. to migrate
+	// the database schema to the new version. Unlike pre-deployment tasks, they run one after the other in the given
+	// order and are not retried, and the migrations of different versions of the workload never run in parallel.
+	// +optional
+	MigrationTasks []string `json:"migrationTasks,omitempty"`
+	// Issue is the key of the ticket the deployment outcome is reported to, e.g. a JIRA issue
+	Issue string `json:"issue,omitempty"`
+	// ChangeRequest is the number of the ServiceNow change request which has to be in the Implement state before the
+	// workload is deployed, e.g. CHG0030001
+	// +optional
+	ChangeRequest string `json:"changeRequest,omitempty"`
+	// PostDeploymentEvaluationDelay is the time to wait after the deployment has succeeded before the post-deployment evaluations start
+	// +optional
+	// +kubebuilder:validation:Pattern="^0|([0-9]+(\\.[0-9]+)?(ns|us|µs|ms|s|m|h))+$"
+	// +kubebuilder:validation:Type:=string
+	PostDeploymentEvaluationDelay metav1.Duration `json:"postDeploymentEvaluationDelay,omitempty"`
+	// ContainerVersions are the versions of the individual containers of the workload, if they have been annotated separately
+	ContainerVersions map[string]string `json:"containerVersions,omitempty"`
+	// ReadinessCheck defines when the pods of the workload are considered to be deployed
+	// +optional
+	// +kubebuilder:validation:Enum=ReadyReplicas;PodReady;ContainersReady
+	ReadinessCheck ReadinessCheck `json:"readinessCheck,omitempty"`
+	// Timeouts define how long the phases of the deployment may take before they fail
+	// +optional
+	Timeouts PhaseTimeouts `json:"timeouts,omitempty"`
+	// SimulateFailure forces the given phase to fail without running its tasks, evaluations or checks
+	// +optional
+	// +kubebuilder:validation:Enum=pre-deployment;pre-deployment-evaluation;deployment;post-deployment;post-deployment-evaluation
+	SimulateFailure SimulatedFailure `json:"simulateFailure,omitempty"`
+	// RollbackOnFailure restores the Deployment or StatefulSet of the workload to its previous version if the
+	// post-deployment evaluations fail. It requires the Rollback feature gate of the operator.
+	// +optional
+	RollbackOnFailure bool `json:"rollbackOnFailure,omitempty"`
+	// SkipPhases are the phases whose tasks and evaluations are not run, so that they succeed right away
+	// +optional
+	SkipPhases []SkippedPhase `json:"skipPhases,omitempty"`
 }
 
+// SkippedPhase is a phase of a deployment which is skipped since it does not apply to the app or workload
+// +kubebuilder:validation:Enum=pre-deployment;pre-deployment-evaluation;post-deployment;post-deployment-evaluation
+type SkippedPhase string
+
+// IsValid returns whether the skipped phase names a phase with tasks or evaluations
+func (p SkippedPhase) IsValid() bool {
+	return SimulatedFailure(p).IsValid() && SimulatedFailure(p) != SimulatedFailureDeployment
+}
+
+// SkipsPhase returns whether the given phase of an app version or workload instance is one of the skipped phases
+func SkipsPhase(skipped []SkippedPhase, phase common.KeptnPhaseType) bool {
+	for _, p := range skipped {
+		if p.IsValid() && SimulatedFailure(p).Matches(phase) {
+			return true
+		}
+	}
+	return false
+}
+
+// SimulatedFailure is the phase of a deployment which is forced to fail, so that teams can rehearse their rollback,
+// notification and on-call procedures without breaking real checks
+type SimulatedFailure string
+
+const (
+	SimulatedFailurePreDeployment            SimulatedFailure = "pre-deployment"
+	SimulatedFailurePreDeploymentEvaluation  SimulatedFailure = "pre-deployment-evaluation"
+	SimulatedFailureDeployment               SimulatedFailure = "deployment"
+	SimulatedFailurePostDeployment           SimulatedFailure = "post-deployment"
+	SimulatedFailurePostDeploymentEvaluation SimulatedFailure = "post-deployment-evaluation"
+)
+
+// IsValid returns whether the simulated failure names a phase
+func (f SimulatedFailure) IsValid() bool {
+	switch f {
+	case SimulatedFailurePreDeployment, SimulatedFailurePreDeploymentEvaluation, SimulatedFailureDeployment, SimulatedFailurePostDeployment, SimulatedFailurePostDeploymentEvaluation:
+		return true
+	}
+	return false
+}
+
+// Matches returns whether the failure of the given phase of an app version or workload instance is simulated
+func (f SimulatedFailure) Matches(phase common.KeptnPhaseType) bool {
+	switch phase {
+	case common.PhaseAppPreDeployment, common.PhaseWorkloadPreDeployment:
+		return f == SimulatedFailurePreDeployment
+	case common.PhaseAppPreEvaluation, common.PhaseWorkloadPreEvaluation:
+		return f == SimulatedFailurePreDeploymentEvaluation
+	case common.PhaseAppDeployment, common.PhaseWorkloadDeployment:
+		return f == SimulatedFailureDeployment
+	case common.PhaseAppPostDeployment, common.PhaseWorkloadPostDeployment:
+		return f == SimulatedFailurePostDeployment
+	case common.PhaseAppPostEvaluation, common.PhaseWorkloadPostEvaluation:
+		return f == SimulatedFailurePostDeploymentEvaluation
+	}
+	return false
+}
+
+// PhaseTimeouts define how long the phases of a deployment may take before they fail. Phases without a timeout run
+// until they complete or the maximum deployment duration of the namespace is exceeded.
+type PhaseTimeouts struct {
+	// PreDeployment is the timeout of the pre-deployment tasks, e.g. 5m
+	// +optional
+	// +kubebuilder:validation:Pattern="^0|([0-9]+(\\.[0-9]+)?(ns|us|µs|ms|s|m|h))+$"
+	// +kubebuilder:validation:Type:=string
+	PreDeployment *metav1.Duration `json:"preDeployment,omitempty"`
+	// Evaluation is the timeout of the pre-deployment and of the post-deployment evaluations
+	// +optional
+	// +kubebuilder:validation:Pattern="^0|([0-9]+(\\.[0-9]+)?(ns|us|µs|ms|s|m|h))+$"
+	// +kubebuilder:validation:Type:=string
+	Evaluation *metav1.Duration `json:"evaluation,omitempty"`
+	// Deployment is the timeout of the deployment
+	// +optional
+	// +kubebuilder:validation:Pattern="^0|([0-9]+(\\.[0-9]+)?(ns|us|µs|ms|s|m|h))+$"
+	// +kubebuilder:validation:Type:=string
+	Deployment *metav1.Duration `json:"deployment,omitempty"`
+	// PostDeployment is the timeout of the post-deployment tasks
+	// +optional
+	// +kubebuilder:validation:Pattern="^0|([0-9]+(\\.[0-9]+)?(ns|us|µs|ms|s|m|h))+$"
+	// +kubebuilder:validation:Type:=string
+	PostDeployment *metav1.Duration `json:"postDeployment,omitempty"`
+}
+
+type ReadinessCheck string
+
+const (
+	// ReadinessCheckReadyReplicas considers a workload deployed as soon as its ReplicaSet reports the desired number of ready replicas
+	ReadinessCheckReadyReplicas ReadinessCheck = "ReadyReplicas"
+	// ReadinessCheckPodReady additionally requires the Ready condition of all pods of the workload, including their readiness gates
+	ReadinessCheckPodReady ReadinessCheck = "PodReady"
+	// ReadinessCheckContainersReady additionally requires all init containers to be completed and all containers,
+	// including sidecars injected by a service mesh, to be ready
+	ReadinessCheckContainersReady ReadinessCheck = "ContainersReady"
+)
+
 // KeptnWorkloadStatus defines the observed state of KeptnWorkload
 type KeptnWorkloadStatus struct {
 	CurrentVersion string `json:"currentVersion,omitempty"`
+	// Conditions describe the state of the workload, e.g. whether the referenced task and evaluation definitions exist
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
 }
 
+//+kubebuilder:rbac:groups=lifecycle.keptn.sh,resources=keptnworkloads;keptnworkloads/status,verbs=get;list;watch
+
 //+kubebuilder:object:root=true
 //+kubebuilder:subresource:status
 // +kubebuilder:printcolumn:name="AppName",type=string,JSONPath=`.spec.app`
@@ -75,5 +209,5 @@ func init() {
 }
 
 func (w KeptnWorkload) GetWorkloadInstanceName() string {
-	return strings.ToLower(w.Name + "-" + w.Spec.Version)
+	return common.CreateInstanceName(w.Name, w.Spec.Version)
 }
@@ -21,6 +21,7 @@ import (
 
 	"github.com/keptn/lifecycle-controller/operator/api/v1alpha1/common"
 	"go.opentelemetry.io/otel/attribute"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -54,8 +55,77 @@ type KeptnWorkloadInstanceStatus struct {
 	StartTime                          metav1.Time        `json:"startTime,omitempty"`
 	EndTime                            metav1.Time        `json:"endTime,omitempty"`
 	CurrentPhase                       string             `json:"currentPhase,omitempty"`
+	// PhaseStartTime is the time the current phase has started
+	PhaseStartTime metav1.Time `json:"phaseStartTime,omitempty"`
+	// MigrationStatus is the state of the migration tasks, which have to succeed before the pods of the workload are scheduled
+	// +kubebuilder:default:=Pending
+	MigrationStatus common.KeptnState `json:"migrationStatus,omitempty"`
+	// MigrationTaskStatus are the states of the migration tasks in the order they run
+	// +optional
+	MigrationTaskStatus []TaskStatus `json:"migrationTaskStatus,omitempty"`
+	// Reason describes why the workload instance has failed, e.g. since a phase has exceeded its timeout
+	Reason string `json:"reason,omitempty"`
+	// RollbackInstance is the name of the KeptnWorkloadInstance rolling the workload back to its previous version
+	// after this one has failed
+	RollbackInstance string `json:"rollbackInstance,omitempty"`
+	// DeploymentEndTime is the time the deployment phase has succeeded
+	DeploymentEndTime metav1.Time `json:"deploymentEndTime,omitempty"`
+	// ChangeSummary lists the changes of images, environment variables and resources compared to the previous version
+	ChangeSummary []string `json:"changeSummary,omitempty"`
+	// HourlyCostDelta is the projected difference of the hourly resource cost compared to the previous version
+	HourlyCostDelta string `json:"hourlyCostDelta,omitempty"`
+	// PreviousPowerConsumption is the power consumption in Watts of the previous version before the deployment
+	PreviousPowerConsumption string `json:"previousPowerConsumption,omitempty"`
+	// PowerConsumption is the power consumption in Watts of the workload after the deployment
+	PowerConsumption string `json:"powerConsumption,omitempty"`
+	// ResourceSummary is the resource footprint of the workload once it has been deployed
+	// +optional
+	ResourceSummary *ResourceSummary `json:"resourceSummary,omitempty"`
 	// +kubebuilder:default:=Pending
 	Status common.KeptnState `json:"status,omitempty"`
+	// ChangeRequest is the state of the change request gating the deployment
+	// +optional
+	ChangeRequest *ChangeRequestStatus `json:"changeRequest,omitempty"`
+	// Debug is the last decision of the reconciler, which is only recorded if the operator runs with --debug-status
+	// +optional
+	Debug *ReconcileDecision `json:"debug,omitempty"`
+	// Conditions describe the state of the workload instance, e.g. whether the scheduler has released its pods
+	// +optional
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+	// IssueCommented is whether the outcome of the workload instance is commented on the issue referenced by the
+	// keptn.sh/issue annotation. It is persisted before the comment is posted, so that the comment is posted only once.
+	// +optional
+	IssueCommented bool `json:"issueCommented,omitempty"`
+	// IncidentKey is the key of the incident opened since the workload instance has failed its post-deployment
+	// evaluation, which is resolved once a later version of the workload has been deployed successfully
+	// +optional
+	IncidentKey string `json:"incidentKey,omitempty"`
+}
+
+// PodsReleased is the type of the condition indicating whether the Keptn scheduler has released the pods of a
+// KeptnWorkloadInstance after its pre-deployment checks, i.e. whether the pods are being scheduled
+const PodsReleased = "PodsReleased"
+
+// ResourceSummary is the resource footprint of a deployed workload, summed up over the containers of all its replicas.
+// Init containers are not taken into account.
+type ResourceSummary struct {
+	// Replicas is the desired number of replicas of the workload
+	Replicas int32 `json:"replicas"`
+	// Requests are the sums of the resource requests
+	// +optional
+	Requests corev1.ResourceList `json:"requests,omitempty"`
+	// Limits are the sums of the resource limits
+	// +optional
+	Limits corev1.ResourceList `json:"limits,omitempty"`
+	// ReplicasDelta is the difference of the replicas compared to the previous version
+	// +optional
+	ReplicasDelta int32 `json:"replicasDelta,omitempty"`
+	// RequestsDelta are the differences of the resource requests compared to the previous version
+	// +optional
+	RequestsDelta corev1.ResourceList `json:"requestsDelta,omitempty"`
+	// LimitsDelta are the differences of the resource limits compared to the previous version
+	// +optional
+	LimitsDelta corev1.ResourceList `json:"limitsDelta,omitempty"`
 }
 
 type TaskStatus struct {
@@ -65,6 +135,9 @@ type TaskStatus struct {
 	TaskName  string            `json:"taskName,omitempty"`
 	StartTime metav1.Time       `json:"startTime,omitempty"`
 	EndTime   metav1.Time       `json:"endTime,omitempty"`
+	// Reason explains why a task has not been started, e.g. because it waits for the tasks it depends on
+	// +optional
+	Reason string `json:"reason,omitempty"`
 }
 
 type EvaluationStatus struct {
@@ -76,6 +149,52 @@ type EvaluationStatus struct {
 	EndTime        metav1.Time       `json:"endTime,omitempty"`
 }
 
+// ChangeRequestStatus is the state of the ServiceNow change request gating the deployment of a workload
+type ChangeRequestStatus struct {
+	// Number is the number of the change request, e.g. CHG0030001
+	Number string `json:"number"`
+	// Status is Succeeded once the change request is in the Implement state, and Failed if it has been rejected,
+	// canceled or closed, or has expired
+	// +optional
+	Status common.KeptnState `json:"status,omitempty"`
+	// Reason describes the state of the change request
+	// +optional
+	Reason string `json:"reason,omitempty"`
+	// Checks is the number of times the change request has been checked
+	// +optional
+	Checks int32 `json:"checks,omitempty"`
+	// LastCheckTime is the time the change request has been checked last
+	// +optional
+	LastCheckTime metav1.Time `json:"lastCheckTime,omitempty"`
+}
+
+// ReconcileDecision is the last decision of a reconciler, i.e. the branch it took, the states it based the decision on
+// and when it checks the resource again
+type ReconcileDecision struct {
+	// Phase is the phase the reconciler handled
+	// +optional
+	Phase string `json:"phase,omitempty"`
+	// Branch describes the branch the reconciler took, e.g. the event it recorded
+	// +kubebuilder:validation:MaxLength=512
+	Branch string `json:"branch"`
+	// Inputs are the states the reconciler based its decision on
+	// +kubebuilder:validation:MaxProperties=16
+	// +optional
+	Inputs map[string]string `json:"inputs,omitempty"`
+	// RequeueAfter is the delay after which the reconciler checks the resource again, unless a watched resource changes before.
+	// It is empty if the resource is not requeued.
+	// +optional
+	RequeueAfter string `json:"requeueAfter,omitempty"`
+	// Error is the error the reconciler returned
+	// +kubebuilder:validation:MaxLength=512
+	// +optional
+	Error string `json:"error,omitempty"`
+	// Time is the time of the decision
+	Time metav1.Time `json:"time"`
+}
+
+//+kubebuilder:rbac:groups=lifecycle.keptn.sh,resources=keptnworkloadinstances;keptnworkloadinstances/status,verbs=get;list;watch
+
 //+kubebuilder:object:root=true
 //+kubebuilder:resource:path=keptnworkloadinstances,shortName=kwi
 //+kubebuilder:subresource:status
@@ -85,6 +204,7 @@ type EvaluationStatus struct {
 // +kubebuilder:printcolumn:name="Phase",type=string,JSONPath=`.status.currentPhase`
 // +kubebuilder:printcolumn:name="PreDeploymentStatus",priority=1,type=string,JSONPath=`.status.preDeploymentStatus`
 // +kubebuilder:printcolumn:name="PreDeploymentEvaluationStatus",priority=1,type=string,JSONPath=`.status.preDeploymentEvaluationStatus`
+// +kubebuilder:printcolumn:name="MigrationStatus",type=string,priority=1,JSONPath=`.status.migrationStatus`
 // +kubebuilder:printcolumn:name="DeploymentStatus",type=string,priority=1,JSONPath=`.status.deploymentStatus`
 // +kubebuilder:printcolumn:name="PostDeploymentStatus",type=string,priority=1,JSONPath=`.status.postDeploymentStatus`
 // +kubebuilder:printcolumn:name="PostDeploymentEvaluationStatus",priority=1,type=string,JSONPath=`.status.postDeploymentEvaluationStatus`
@@ -159,6 +279,19 @@ func (v KeptnWorkloadInstance) IsPostDeploymentEvaluationFailed() bool {
 	return v.Status.PostDeploymentEvaluationStatus.IsFailed()
 }
 
+func (i KeptnWorkloadInstance) IsMigrationSucceeded() bool {
+	return i.Status.MigrationStatus.IsSucceeded()
+}
+
+func (i KeptnWorkloadInstance) IsMigrationFailed() bool {
+	return i.Status.MigrationStatus.IsFailed()
+}
+
+// HasMigrationTasks returns whether the workload instance runs migration tasks before its deployment
+func (i KeptnWorkloadInstance) HasMigrationTasks() bool {
+	return len(i.Spec.MigrationTasks) > 0
+}
+
 func (i KeptnWorkloadInstance) IsDeploymentCompleted() bool {
 	return i.Status.DeploymentStatus.IsCompleted()
 }
@@ -171,6 +304,14 @@ func (i KeptnWorkloadInstance) IsDeploymentFailed() bool {
 	return i.Status.DeploymentStatus.IsFailed()
 }
 
+// GetRemainingPostDeploymentEvaluationDelay returns how long the post-deployment evaluations still have to wait after the deployment has succeeded
+func (i KeptnWorkloadInstance) GetRemainingPostDeploymentEvaluationDelay() time.Duration {
+	if i.Status.DeploymentEndTime.IsZero() {
+		return 0
+	}
+	return time.Until(i.Status.DeploymentEndTime.Add(i.Spec.PostDeploymentEvaluationDelay.Duration))
+}
+
 func (i *KeptnWorkloadInstance) SetStartTime() {
 	if i.Status.StartTime.IsZero() {
 		i.Status.StartTime = metav1.NewTime(time.Now().UTC())
@@ -191,6 +332,11 @@ func (i *KeptnWorkloadInstance) IsEndTimeSet() bool {
 	return !i.Status.EndTime.IsZero()
 }
 
+// IsImported returns whether the workload instance has been imported from the history of a Deployment
+func (i *KeptnWorkloadInstance) IsImported() bool {
+	return i.Annotations[common.ImportedAnnotation] == "true"
+}
+
 func (i *TaskStatus) SetStartTime() {
 	if i.StartTime.IsZero() {
 		i.StartTime = metav1.NewTime(time.Now().UTC())
@@ -221,6 +367,8 @@ func (i KeptnWorkloadInstance) GetActiveMetricsAttributes() []attribute.KeyValue
 		common.WorkloadName.String(i.Spec.WorkloadName),
 		common.WorkloadVersion.String(i.Spec.Version),
 		common.WorkloadNamespace.String(i.Namespace),
+		common.Environment.String(i.Labels[common.EnvironmentLabel]),
+		common.Namespace.String(i.Namespace),
 	}
 }
 
@@ -231,6 +379,8 @@ func (i KeptnWorkloadInstance) GetMetricsAttributes() []attribute.KeyValue {
 		common.WorkloadVersion.String(i.Spec.Version),
 		common.WorkloadNamespace.String(i.Namespace),
 		common.WorkloadStatus.String(string(i.Status.Status)),
+		common.Environment.String(i.Labels[common.EnvironmentLabel]),
+		common.Namespace.String(i.Namespace),
 	}
 }
 
@@ -240,5 +390,13 @@ func (i KeptnWorkloadInstance) GetIntervalMetricsAttributes() []attribute.KeyVal
 		common.WorkloadName.String(i.Spec.WorkloadName),
 		common.WorkloadVersion.String(i.Spec.Version),
 		common.WorkloadPreviousVersion.String(i.Spec.PreviousVersion),
+		common.Environment.String(i.Labels[common.EnvironmentLabel]),
+		common.Namespace.String(i.Namespace),
 	}
 }
+
+// VerifyIdentity returns a conflict error if the workload instance retrieved by the name of the given workload and
+// version belongs to another workload or version, whose name collides with it
+func (w *KeptnWorkloadInstance) VerifyIdentity(workloadName string, version string) error {
+	return common.VerifyIdentity(w, GroupVersion.WithResource("keptnworkloadinstances").GroupResource(), workloadName, version)
+}
@@ -0,0 +1,30 @@
+package v1alpha1
+
+import (
+	"testing"
+	"time"
+
+	testrequire "github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestKeptnWorkloadInstance_GetRemainingPostDeploymentEvaluationDelay(t *testing.T) {
+	instance := func(deploymentEndTime time.Time, delay time.Duration) KeptnWorkloadInstance {
+		return KeptnWorkloadInstance{
+			Spec: KeptnWorkloadInstanceSpec{
+				KeptnWorkloadSpec: KeptnWorkloadSpec{PostDeploymentEvaluationDelay: metav1.Duration{Duration: delay}},
+			},
+			Status: KeptnWorkloadInstanceStatus{DeploymentEndTime: metav1.NewTime(deploymentEndTime)},
+		}
+	}
+
+	// the delay only starts once the deployment has finished
+	testrequire.Zero(t, instance(time.Time{}, time.Hour).GetRemainingPostDeploymentEvaluationDelay())
+	testrequire.LessOrEqual(t, instance(time.Now(), 0).GetRemainingPostDeploymentEvaluationDelay(), time.Duration(0))
+
+	remaining := instance(time.Now().Add(-10*time.Minute), time.Hour).GetRemainingPostDeploymentEvaluationDelay()
+	testrequire.Greater(t, remaining, 49*time.Minute)
+	testrequire.LessOrEqual(t, remaining, 50*time.Minute)
+
+	testrequire.LessOrEqual(t, instance(time.Now().Add(-2*time.Hour), time.Hour).GetRemainingPostDeploymentEvaluationDelay(), time.Duration(0))
+}
@@ -0,0 +1,117 @@
+package semconv
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/keptn/lifecycle-controller/operator/api/v1alpha1"
+	"github.com/keptn/lifecycle-controller/operator/api/v1alpha1/common"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+const (
+	// RuleUnknownKey is reported for attribute keys that are not declared in common.MetricsAttributeKeys
+	RuleUnknownKey = "unknown-key"
+	// RuleMissingDimension is reported if a metric lacks a shared dimension it is expected to carry
+	RuleMissingDimension = "missing-dimension"
+	// RuleDivergentKey is reported if the metrics of different resources represent the same dimension with different keys
+	RuleDivergentKey = "divergent-key"
+)
+
+// AttributeFinding is an inconsistency between the attributes of the metrics of different resources
+type AttributeFinding struct {
+	Rule      string
+	Source    string
+	Dimension common.MetricsDimension
+	Keys      []string
+}
+
+func (f AttributeFinding) String() string {
+	return fmt.Sprintf("%s: source=%s dimension=%s keys=%s", f.Rule, f.Source, f.Dimension, strings.Join(f.Keys, ","))
+}
+
+type metricsSource struct {
+	name       string
+	attributes []attribute.KeyValue
+	dimensions []common.MetricsDimension
+}
+
+// AuditMetricsAttributes checks that the metrics of all resources use the shared attribute keys of common,
+// so that dashboards can correlate the metrics of apps, workloads, tasks and evaluations
+func AuditMetricsAttributes() []AttributeFinding {
+	app := []common.MetricsDimension{common.DimensionApp, common.DimensionVersion, common.DimensionNamespace, common.DimensionEnvironment}
+	workload := []common.MetricsDimension{common.DimensionApp, common.DimensionWorkload, common.DimensionVersion, common.DimensionNamespace, common.DimensionEnvironment}
+	check := []common.MetricsDimension{common.DimensionApp, common.DimensionVersion, common.DimensionNamespace, common.DimensionPhase, common.DimensionEnvironment}
+
+	appVersion := v1alpha1.KeptnAppVersion{}
+	workloadInstance := v1alpha1.KeptnWorkloadInstance{}
+	task := v1alpha1.KeptnTask{}
+	evaluation := v1alpha1.KeptnEvaluation{}
+	provider := v1alpha1.KeptnEvaluationProvider{}
+
+	return auditSources(common.MetricsDimensionKeys, []metricsSource{
+		{name: "KeptnAppVersion.GetActiveMetricsAttributes", attributes: appVersion.GetActiveMetricsAttributes(), dimensions: app},
+		{name: "KeptnAppVersion.GetMetricsAttributes", attributes: appVersion.GetMetricsAttributes(), dimensions: app},
+		{name: "KeptnAppVersion.GetDurationMetricsAttributes", attributes: appVersion.GetDurationMetricsAttributes(), dimensions: app},
+		{name: "KeptnWorkloadInstance.GetActiveMetricsAttributes", attributes: workloadInstance.GetActiveMetricsAttributes(), dimensions: workload},
+		{name: "KeptnWorkloadInstance.GetMetricsAttributes", attributes: workloadInstance.GetMetricsAttributes(), dimensions: workload},
+		{name: "KeptnWorkloadInstance.GetIntervalMetricsAttributes", attributes: workloadInstance.GetIntervalMetricsAttributes(), dimensions: workload},
+		{name: "KeptnTask.GetActiveMetricsAttributes", attributes: task.GetActiveMetricsAttributes(), dimensions: check},
+		{name: "KeptnTask.GetMetricsAttributes", attributes: task.GetMetricsAttributes(), dimensions: check},
+		{name: "KeptnEvaluation.GetActiveMetricsAttributes", attributes: evaluation.GetActiveMetricsAttributes(), dimensions: check},
+		{name: "KeptnEvaluation.GetMetricsAttributes", attributes: evaluation.GetMetricsAttributes(), dimensions: check},
+		{name: "KeptnEvaluationProvider.GetMetricsAttributes", attributes: provider.GetMetricsAttributes(), dimensions: []common.MetricsDimension{common.DimensionNamespace}},
+	})
+}
+
+// auditSources checks the attributes of the sources against the given keys of the dimensions
+func auditSources(dimensionKeys map[common.MetricsDimension][]attribute.Key, sources []metricsSource) []AttributeFinding {
+	known := map[attribute.Key]bool{}
+	for _, key := range common.MetricsAttributeKeys {
+		known[key] = true
+	}
+
+	findings := []AttributeFinding{}
+	// keys used for each dimension over all sources
+	used := map[common.MetricsDimension]map[string]bool{}
+	for _, source := range sources {
+		keys := map[attribute.Key]bool{}
+		for _, kv := range source.attributes {
+			keys[kv.Key] = true
+			if !known[kv.Key] {
+				findings = append(findings, AttributeFinding{Rule: RuleUnknownKey, Source: source.name, Keys: []string{string(kv.Key)}})
+			}
+		}
+
+		for _, dimension := range source.dimensions {
+			found := false
+			for _, key := range dimensionKeys[dimension] {
+				if keys[key] {
+					found = true
+					if used[dimension] == nil {
+						used[dimension] = map[string]bool{}
+					}
+					used[dimension][string(key)] = true
+				}
+			}
+			if !found {
+				findings = append(findings, AttributeFinding{Rule: RuleMissingDimension, Source: source.name, Dimension: dimension})
+			}
+		}
+	}
+
+	// the versions of apps and workloads are different values, so only the other dimensions have to use a single key
+	for _, dimension := range []common.MetricsDimension{common.DimensionApp, common.DimensionWorkload, common.DimensionNamespace, common.DimensionPhase, common.DimensionEnvironment} {
+		if len(used[dimension]) <= 1 {
+			continue
+		}
+		keys := make([]string, 0, len(used[dimension]))
+		for key := range used[dimension] {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		findings = append(findings, AttributeFinding{Rule: RuleDivergentKey, Source: "*", Dimension: dimension, Keys: keys})
+	}
+	return findings
+}
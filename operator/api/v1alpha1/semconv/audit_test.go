@@ -0,0 +1,44 @@
+package semconv
+
+import (
+	"testing"
+
+	"github.com/keptn/lifecycle-controller/operator/api/v1alpha1/common"
+	testrequire "github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+func TestAuditSources(t *testing.T) {
+	dimensions := []common.MetricsDimension{common.DimensionApp, common.DimensionNamespace}
+	// the namespace is represented by the keys of the resources, which diverge
+	dimensionKeys := map[common.MetricsDimension][]attribute.Key{
+		common.DimensionApp:       {common.AppName},
+		common.DimensionNamespace: {common.AppNamespace, common.WorkloadNamespace},
+	}
+
+	findings := auditSources(dimensionKeys, []metricsSource{
+		{name: "app", attributes: []attribute.KeyValue{common.AppName.String("a"), common.AppNamespace.String("ns")}, dimensions: dimensions},
+		{name: "workload", attributes: []attribute.KeyValue{common.AppName.String("a"), common.WorkloadNamespace.String("ns")}, dimensions: dimensions},
+	})
+	testrequire.Len(t, findings, 1)
+	testrequire.Equal(t, RuleDivergentKey, findings[0].Rule)
+	testrequire.Equal(t, common.DimensionNamespace, findings[0].Dimension)
+	testrequire.Equal(t, []string{string(common.AppNamespace), string(common.WorkloadNamespace)}, findings[0].Keys)
+
+	findings = auditSources(dimensionKeys, []metricsSource{
+		{name: "task", attributes: []attribute.KeyValue{common.AppName.String("a"), attribute.String("app", "a")}, dimensions: dimensions},
+	})
+	testrequire.Len(t, findings, 2)
+	testrequire.Equal(t, RuleUnknownKey, findings[0].Rule)
+	testrequire.Equal(t, RuleMissingDimension, findings[1].Rule)
+	testrequire.Equal(t, common.DimensionNamespace, findings[1].Dimension)
+
+	findings = auditSources(dimensionKeys, []metricsSource{
+		{name: "app", attributes: []attribute.KeyValue{common.AppName.String("a"), common.AppNamespace.String("ns")}, dimensions: dimensions},
+	})
+	testrequire.Empty(t, findings)
+}
+
+func TestAuditMetricsAttributes(t *testing.T) {
+	testrequire.Empty(t, AuditMetricsAttributes())
+}
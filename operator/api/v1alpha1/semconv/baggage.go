@@ -0,0 +1,30 @@
+package semconv
+
+import (
+	"context"
+	"net/url"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/baggage"
+)
+
+// ContextWithDeploymentBaggage adds the given deployment attributes, e.g. the app, version and environment, to the baggage
+// of the context, so services instrumented with OpenTelemetry can read the deployment context during the rollout.
+// Attributes without value are skipped, as are attributes that are no valid baggage members.
+func ContextWithDeploymentBaggage(ctx context.Context, attributes ...attribute.KeyValue) context.Context {
+	bag := baggage.FromContext(ctx)
+	for _, kv := range attributes {
+		value := kv.Value.Emit()
+		if value == "" {
+			continue
+		}
+		member, err := baggage.NewMember(string(kv.Key), url.PathEscape(value))
+		if err != nil {
+			continue
+		}
+		if b, err := bag.SetMember(member); err == nil {
+			bag = b
+		}
+	}
+	return baggage.ContextWithBaggage(ctx, bag)
+}
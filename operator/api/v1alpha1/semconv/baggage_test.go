@@ -0,0 +1,29 @@
+package semconv
+
+import (
+	"context"
+	"testing"
+
+	"github.com/keptn/lifecycle-controller/operator/api/v1alpha1/common"
+	testrequire "github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/baggage"
+)
+
+func TestContextWithDeploymentBaggage(t *testing.T) {
+	ctx := ContextWithDeploymentBaggage(context.Background(),
+		common.AppName.String("my-app"),
+		common.AppVersion.String("1.0.0"),
+		common.Environment.String(""),
+	)
+	ctx = ContextWithDeploymentBaggage(ctx,
+		common.WorkloadName.String("my-workload"),
+		common.Environment.String("prod"),
+	)
+
+	bag := baggage.FromContext(ctx)
+	testrequire.Equal(t, "my-app", bag.Member(string(common.AppName)).Value())
+	testrequire.Equal(t, "1.0.0", bag.Member(string(common.AppVersion)).Value())
+	testrequire.Equal(t, "my-workload", bag.Member(string(common.WorkloadName)).Value())
+	testrequire.Equal(t, "prod", bag.Member(string(common.Environment)).Value())
+	testrequire.Len(t, bag.Members(), 4)
+}
@@ -16,6 +16,7 @@ func AddAttributeFromWorkloadInstance(s trace.Span, w v1alpha1.KeptnWorkloadInst
 	s.SetAttributes(common.AppName.String(w.Spec.AppName))
 	s.SetAttributes(common.WorkloadName.String(w.Spec.WorkloadName))
 	s.SetAttributes(common.WorkloadVersion.String(w.Spec.Version))
+	s.SetAttributes(common.Environment.String(w.Labels[common.EnvironmentLabel]))
 }
 
 func AddAttributeFromApp(s trace.Span, a v1alpha1.KeptnApp) {
@@ -27,6 +28,7 @@ func AddAttributeFromAppVersion(s trace.Span, a v1alpha1.KeptnAppVersion) {
 	s.SetAttributes(common.AppName.String(a.Spec.AppName))
 	s.SetAttributes(common.AppVersion.String(a.Spec.Version))
 	s.SetAttributes(common.WorkloadVersion.String(a.Spec.Version))
+	s.SetAttributes(common.Environment.String(a.Labels[common.EnvironmentLabel]))
 }
 
 func AddAttributeFromTask(s trace.Span, t v1alpha1.KeptnTask) {
@@ -36,6 +38,7 @@ func AddAttributeFromTask(s trace.Span, t v1alpha1.KeptnTask) {
 	s.SetAttributes(common.WorkloadVersion.String(t.Spec.WorkloadVersion))
 	s.SetAttributes(common.TaskName.String(t.Name))
 	s.SetAttributes(common.TaskType.String(string(t.Spec.Type)))
+	s.SetAttributes(common.Environment.String(t.Labels[common.EnvironmentLabel]))
 }
 
 func AddAttributeFromEvaluation(s trace.Span, t v1alpha1.KeptnEvaluation) {
@@ -45,6 +48,7 @@ func AddAttributeFromEvaluation(s trace.Span, t v1alpha1.KeptnEvaluation) {
 	s.SetAttributes(common.WorkloadVersion.String(t.Spec.WorkloadVersion))
 	s.SetAttributes(common.EvaluationName.String(t.Name))
 	s.SetAttributes(common.EvaluationType.String(string(t.Spec.Type)))
+	s.SetAttributes(common.Environment.String(t.Labels[common.EnvironmentLabel]))
 }
 
 func AddAttributeFromAnnotations(s trace.Span, annotations map[string]string) {
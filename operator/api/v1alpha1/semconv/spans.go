@@ -0,0 +1,45 @@
+package semconv
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// The deployment of an app version is recorded as a single trace with the following hierarchy:
+//
+//	appversion_deployment
+//	├── <short name of the app phase>, e.g. AppPreDeployTasks
+//	│   └── create_task / create_evaluation
+//	│       └── spans of the KeptnTask or KeptnEvaluation, e.g. job_execution of a KeptnTask
+//	│           └── spans of the function
+//	└── workloadinstance_deployment (one per workload)
+//	    └── <short name of the workload phase>, e.g. WorkloadPreDeployTasks
+//	        └── create_task / create_evaluation
+//	            └── spans of the KeptnTask or KeptnEvaluation, e.g. job_execution of a KeptnTask
+//	                └── spans of the function
+//
+// In addition, job_execution is linked to the span of the phase, since it is not a direct child of it.
+//
+// The span names do not contain the names of the resources, which are added as attributes instead.
+const (
+	AppVersionSpanName       = "appversion_deployment"
+	WorkloadInstanceSpanName = "workloadinstance_deployment"
+	CreateTaskSpanName       = "create_task"
+	CreateEvaluationSpanName = "create_evaluation"
+	JobSpanName              = "job_execution"
+)
+
+// TraceParent returns the span context of the context in the W3C traceparent format
+func TraceParent(ctx context.Context) string {
+	carrier := propagation.MapCarrier{}
+	propagation.TraceContext{}.Inject(ctx, carrier)
+	return carrier.Get("traceparent")
+}
+
+// SpanContextFromTraceParent parses a span context in the W3C traceparent format, returning an invalid span context if it cannot be parsed
+func SpanContextFromTraceParent(traceParent string) trace.SpanContext {
+	ctx := propagation.TraceContext{}.Extract(context.Background(), propagation.MapCarrier{"traceparent": traceParent})
+	return trace.SpanContextFromContext(ctx)
+}
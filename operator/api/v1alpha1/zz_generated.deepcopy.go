@@ -22,9 +22,46 @@ limitations under the License.
 package v1alpha1
 
 import (
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BurnRate) DeepCopyInto(out *BurnRate) {
+	*out = *in
+	out.Window = in.Window
+	out.LongWindow = in.LongWindow
+	out.ShortWindow = in.ShortWindow
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BurnRate.
+func (in *BurnRate) DeepCopy() *BurnRate {
+	if in == nil {
+		return nil
+	}
+	out := new(BurnRate)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ChangeRequestStatus) DeepCopyInto(out *ChangeRequestStatus) {
+	*out = *in
+	in.LastCheckTime.DeepCopyInto(&out.LastCheckTime)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ChangeRequestStatus.
+func (in *ChangeRequestStatus) DeepCopy() *ChangeRequestStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ChangeRequestStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ConfigMapReference) DeepCopyInto(out *ConfigMapReference) {
 	*out = *in
@@ -43,6 +80,28 @@ func (in *ConfigMapReference) DeepCopy() *ConfigMapReference {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ContainerSpec) DeepCopyInto(out *ContainerSpec) {
 	*out = *in
+	if in.Command != nil {
+		in, out := &in.Command, &out.Command
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Args != nil {
+		in, out := &in.Args, &out.Args
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Env != nil {
+		in, out := &in.Env, &out.Env
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.ImagePullSecrets != nil {
+		in, out := &in.ImagePullSecrets, &out.ImagePullSecrets
+		*out = make([]corev1.LocalObjectReference, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ContainerSpec.
@@ -75,6 +134,8 @@ func (in *EvaluationStatus) DeepCopy() *EvaluationStatus {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *EvaluationStatusItem) DeepCopyInto(out *EvaluationStatusItem) {
 	*out = *in
+	in.QueryStart.DeepCopyInto(&out.QueryStart)
+	in.QueryEnd.DeepCopyInto(&out.QueryEnd)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EvaluationStatusItem.
@@ -87,6 +148,51 @@ func (in *EvaluationStatusItem) DeepCopy() *EvaluationStatusItem {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FunctionDependencies) DeepCopyInto(out *FunctionDependencies) {
+	*out = *in
+	out.ConfigMapRef = in.ConfigMapRef
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FunctionDependencies.
+func (in *FunctionDependencies) DeepCopy() *FunctionDependencies {
+	if in == nil {
+		return nil
+	}
+	out := new(FunctionDependencies)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FunctionIdentity) DeepCopyInto(out *FunctionIdentity) {
+	*out = *in
+	if in.PodLabels != nil {
+		in, out := &in.PodLabels, &out.PodLabels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.PodAnnotations != nil {
+		in, out := &in.PodAnnotations, &out.PodAnnotations
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FunctionIdentity.
+func (in *FunctionIdentity) DeepCopy() *FunctionIdentity {
+	if in == nil {
+		return nil
+	}
+	out := new(FunctionIdentity)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *FunctionReference) DeepCopyInto(out *FunctionReference) {
 	*out = *in
@@ -111,6 +217,9 @@ func (in *FunctionSpec) DeepCopyInto(out *FunctionSpec) {
 	out.ConfigMapReference = in.ConfigMapReference
 	in.Parameters.DeepCopyInto(&out.Parameters)
 	out.SecureParameters = in.SecureParameters
+	in.Runner.DeepCopyInto(&out.Runner)
+	out.Dependencies = in.Dependencies
+	in.Identity.DeepCopyInto(&out.Identity)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FunctionSpec.
@@ -174,7 +283,7 @@ func (in *KeptnApp) DeepCopyInto(out *KeptnApp) {
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
 	in.Spec.DeepCopyInto(&out.Spec)
-	out.Status = in.Status
+	in.Status.DeepCopyInto(&out.Status)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KeptnApp.
@@ -255,6 +364,17 @@ func (in *KeptnAppSpec) DeepCopyInto(out *KeptnAppSpec) {
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.MinSucceededWorkloads != nil {
+		in, out := &in.MinSucceededWorkloads, &out.MinSucceededWorkloads
+		*out = new(intstr.IntOrString)
+		**out = **in
+	}
+	in.Timeouts.DeepCopyInto(&out.Timeouts)
+	if in.SkipPhases != nil {
+		in, out := &in.SkipPhases, &out.SkipPhases
+		*out = make([]SkippedPhase, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KeptnAppSpec.
@@ -270,6 +390,20 @@ func (in *KeptnAppSpec) DeepCopy() *KeptnAppSpec {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *KeptnAppStatus) DeepCopyInto(out *KeptnAppStatus) {
 	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]v1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Drift != nil {
+		in, out := &in.Drift, &out.Drift
+		*out = make([]WorkloadDrift, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KeptnAppStatus.
@@ -402,6 +536,19 @@ func (in *KeptnAppVersionStatus) DeepCopyInto(out *KeptnAppVersionStatus) {
 	}
 	in.StartTime.DeepCopyInto(&out.StartTime)
 	in.EndTime.DeepCopyInto(&out.EndTime)
+	in.PhaseStartTime.DeepCopyInto(&out.PhaseStartTime)
+	if in.Debug != nil {
+		in, out := &in.Debug, &out.Debug
+		*out = new(ReconcileDecision)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Events != nil {
+		in, out := &in.Events, &out.Events
+		*out = make([]LifecycleEvent, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KeptnAppVersionStatus.
@@ -415,26 +562,26 @@ func (in *KeptnAppVersionStatus) DeepCopy() *KeptnAppVersionStatus {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *KeptnEvaluation) DeepCopyInto(out *KeptnEvaluation) {
+func (in *KeptnConfig) DeepCopyInto(out *KeptnConfig) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	out.Spec = in.Spec
-	in.Status.DeepCopyInto(&out.Status)
+	in.Spec.DeepCopyInto(&out.Spec)
+	out.Status = in.Status
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KeptnEvaluation.
-func (in *KeptnEvaluation) DeepCopy() *KeptnEvaluation {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KeptnConfig.
+func (in *KeptnConfig) DeepCopy() *KeptnConfig {
 	if in == nil {
 		return nil
 	}
-	out := new(KeptnEvaluation)
+	out := new(KeptnConfig)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *KeptnEvaluation) DeepCopyObject() runtime.Object {
+func (in *KeptnConfig) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -442,58 +589,51 @@ func (in *KeptnEvaluation) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *KeptnEvaluationDefinition) DeepCopyInto(out *KeptnEvaluationDefinition) {
+func (in *KeptnConfigCardinality) DeepCopyInto(out *KeptnConfigCardinality) {
 	*out = *in
-	out.TypeMeta = in.TypeMeta
-	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	in.Spec.DeepCopyInto(&out.Spec)
-	out.Status = in.Status
+	if in.Attributes != nil {
+		in, out := &in.Attributes, &out.Attributes
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KeptnEvaluationDefinition.
-func (in *KeptnEvaluationDefinition) DeepCopy() *KeptnEvaluationDefinition {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KeptnConfigCardinality.
+func (in *KeptnConfigCardinality) DeepCopy() *KeptnConfigCardinality {
 	if in == nil {
 		return nil
 	}
-	out := new(KeptnEvaluationDefinition)
+	out := new(KeptnConfigCardinality)
 	in.DeepCopyInto(out)
 	return out
 }
 
-// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *KeptnEvaluationDefinition) DeepCopyObject() runtime.Object {
-	if c := in.DeepCopy(); c != nil {
-		return c
-	}
-	return nil
-}
-
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *KeptnEvaluationDefinitionList) DeepCopyInto(out *KeptnEvaluationDefinitionList) {
+func (in *KeptnConfigList) DeepCopyInto(out *KeptnConfigList) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ListMeta.DeepCopyInto(&out.ListMeta)
 	if in.Items != nil {
 		in, out := &in.Items, &out.Items
-		*out = make([]KeptnEvaluationDefinition, len(*in))
+		*out = make([]KeptnConfig, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KeptnEvaluationDefinitionList.
-func (in *KeptnEvaluationDefinitionList) DeepCopy() *KeptnEvaluationDefinitionList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KeptnConfigList.
+func (in *KeptnConfigList) DeepCopy() *KeptnConfigList {
 	if in == nil {
 		return nil
 	}
-	out := new(KeptnEvaluationDefinitionList)
+	out := new(KeptnConfigList)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *KeptnEvaluationDefinitionList) DeepCopyObject() runtime.Object {
+func (in *KeptnConfigList) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -501,93 +641,94 @@ func (in *KeptnEvaluationDefinitionList) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *KeptnEvaluationDefinitionSpec) DeepCopyInto(out *KeptnEvaluationDefinitionSpec) {
+func (in *KeptnConfigMetrics) DeepCopyInto(out *KeptnConfigMetrics) {
 	*out = *in
-	if in.Objectives != nil {
-		in, out := &in.Objectives, &out.Objectives
-		*out = make([]Objective, len(*in))
+	if in.DisabledInstruments != nil {
+		in, out := &in.DisabledInstruments, &out.DisabledInstruments
+		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	in.Cardinality.DeepCopyInto(&out.Cardinality)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KeptnEvaluationDefinitionSpec.
-func (in *KeptnEvaluationDefinitionSpec) DeepCopy() *KeptnEvaluationDefinitionSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KeptnConfigMetrics.
+func (in *KeptnConfigMetrics) DeepCopy() *KeptnConfigMetrics {
 	if in == nil {
 		return nil
 	}
-	out := new(KeptnEvaluationDefinitionSpec)
+	out := new(KeptnConfigMetrics)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *KeptnEvaluationDefinitionStatus) DeepCopyInto(out *KeptnEvaluationDefinitionStatus) {
+func (in *KeptnConfigQuotas) DeepCopyInto(out *KeptnConfigQuotas) {
 	*out = *in
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KeptnEvaluationDefinitionStatus.
-func (in *KeptnEvaluationDefinitionStatus) DeepCopy() *KeptnEvaluationDefinitionStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KeptnConfigQuotas.
+func (in *KeptnConfigQuotas) DeepCopy() *KeptnConfigQuotas {
 	if in == nil {
 		return nil
 	}
-	out := new(KeptnEvaluationDefinitionStatus)
+	out := new(KeptnConfigQuotas)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *KeptnEvaluationList) DeepCopyInto(out *KeptnEvaluationList) {
+func (in *KeptnConfigSpec) DeepCopyInto(out *KeptnConfigSpec) {
 	*out = *in
-	out.TypeMeta = in.TypeMeta
-	in.ListMeta.DeepCopyInto(&out.ListMeta)
-	if in.Items != nil {
-		in, out := &in.Items, &out.Items
-		*out = make([]KeptnEvaluation, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
-	}
+	in.Metrics.DeepCopyInto(&out.Metrics)
+	out.Quotas = in.Quotas
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KeptnEvaluationList.
-func (in *KeptnEvaluationList) DeepCopy() *KeptnEvaluationList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KeptnConfigSpec.
+func (in *KeptnConfigSpec) DeepCopy() *KeptnConfigSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(KeptnEvaluationList)
+	out := new(KeptnConfigSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
-// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *KeptnEvaluationList) DeepCopyObject() runtime.Object {
-	if c := in.DeepCopy(); c != nil {
-		return c
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KeptnConfigStatus) DeepCopyInto(out *KeptnConfigStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KeptnConfigStatus.
+func (in *KeptnConfigStatus) DeepCopy() *KeptnConfigStatus {
+	if in == nil {
+		return nil
 	}
-	return nil
+	out := new(KeptnConfigStatus)
+	in.DeepCopyInto(out)
+	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *KeptnEvaluationProvider) DeepCopyInto(out *KeptnEvaluationProvider) {
+func (in *KeptnDefinitionSource) DeepCopyInto(out *KeptnDefinitionSource) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
 	out.Spec = in.Spec
-	out.Status = in.Status
+	in.Status.DeepCopyInto(&out.Status)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KeptnEvaluationProvider.
-func (in *KeptnEvaluationProvider) DeepCopy() *KeptnEvaluationProvider {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KeptnDefinitionSource.
+func (in *KeptnDefinitionSource) DeepCopy() *KeptnDefinitionSource {
 	if in == nil {
 		return nil
 	}
-	out := new(KeptnEvaluationProvider)
+	out := new(KeptnDefinitionSource)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *KeptnEvaluationProvider) DeepCopyObject() runtime.Object {
+func (in *KeptnDefinitionSource) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -595,31 +736,31 @@ func (in *KeptnEvaluationProvider) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *KeptnEvaluationProviderList) DeepCopyInto(out *KeptnEvaluationProviderList) {
+func (in *KeptnDefinitionSourceList) DeepCopyInto(out *KeptnDefinitionSourceList) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ListMeta.DeepCopyInto(&out.ListMeta)
 	if in.Items != nil {
 		in, out := &in.Items, &out.Items
-		*out = make([]KeptnEvaluationProvider, len(*in))
+		*out = make([]KeptnDefinitionSource, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KeptnEvaluationProviderList.
-func (in *KeptnEvaluationProviderList) DeepCopy() *KeptnEvaluationProviderList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KeptnDefinitionSourceList.
+func (in *KeptnDefinitionSourceList) DeepCopy() *KeptnDefinitionSourceList {
 	if in == nil {
 		return nil
 	}
-	out := new(KeptnEvaluationProviderList)
+	out := new(KeptnDefinitionSourceList)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *KeptnEvaluationProviderList) DeepCopyObject() runtime.Object {
+func (in *KeptnDefinitionSourceList) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -627,77 +768,54 @@ func (in *KeptnEvaluationProviderList) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *KeptnEvaluationProviderSpec) DeepCopyInto(out *KeptnEvaluationProviderSpec) {
+func (in *KeptnDefinitionSourceSpec) DeepCopyInto(out *KeptnDefinitionSourceSpec) {
 	*out = *in
+	out.Interval = in.Interval
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KeptnEvaluationProviderSpec.
-func (in *KeptnEvaluationProviderSpec) DeepCopy() *KeptnEvaluationProviderSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KeptnDefinitionSourceSpec.
+func (in *KeptnDefinitionSourceSpec) DeepCopy() *KeptnDefinitionSourceSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(KeptnEvaluationProviderSpec)
+	out := new(KeptnDefinitionSourceSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *KeptnEvaluationProviderStatus) DeepCopyInto(out *KeptnEvaluationProviderStatus) {
+func (in *KeptnDefinitionSourceStatus) DeepCopyInto(out *KeptnDefinitionSourceStatus) {
 	*out = *in
-}
-
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KeptnEvaluationProviderStatus.
-func (in *KeptnEvaluationProviderStatus) DeepCopy() *KeptnEvaluationProviderStatus {
-	if in == nil {
-		return nil
+	if in.Definitions != nil {
+		in, out := &in.Definitions, &out.Definitions
+		*out = make([]string, len(*in))
+		copy(*out, *in)
 	}
-	out := new(KeptnEvaluationProviderStatus)
-	in.DeepCopyInto(out)
-	return out
-}
-
-// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *KeptnEvaluationSpec) DeepCopyInto(out *KeptnEvaluationSpec) {
-	*out = *in
-	out.RetryInterval = in.RetryInterval
-}
-
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KeptnEvaluationSpec.
-func (in *KeptnEvaluationSpec) DeepCopy() *KeptnEvaluationSpec {
-	if in == nil {
-		return nil
+	if in.LastSyncTime != nil {
+		in, out := &in.LastSyncTime, &out.LastSyncTime
+		*out = (*in).DeepCopy()
 	}
-	out := new(KeptnEvaluationSpec)
-	in.DeepCopyInto(out)
-	return out
-}
-
-// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *KeptnEvaluationStatus) DeepCopyInto(out *KeptnEvaluationStatus) {
-	*out = *in
-	if in.EvaluationStatus != nil {
-		in, out := &in.EvaluationStatus, &out.EvaluationStatus
-		*out = make(map[string]EvaluationStatusItem, len(*in))
-		for key, val := range *in {
-			(*out)[key] = val
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]v1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
-	in.StartTime.DeepCopyInto(&out.StartTime)
-	in.EndTime.DeepCopyInto(&out.EndTime)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KeptnEvaluationStatus.
-func (in *KeptnEvaluationStatus) DeepCopy() *KeptnEvaluationStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KeptnDefinitionSourceStatus.
+func (in *KeptnDefinitionSourceStatus) DeepCopy() *KeptnDefinitionSourceStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(KeptnEvaluationStatus)
+	out := new(KeptnDefinitionSourceStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *KeptnTask) DeepCopyInto(out *KeptnTask) {
+func (in *KeptnEvaluation) DeepCopyInto(out *KeptnEvaluation) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
@@ -705,18 +823,18 @@ func (in *KeptnTask) DeepCopyInto(out *KeptnTask) {
 	in.Status.DeepCopyInto(&out.Status)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KeptnTask.
-func (in *KeptnTask) DeepCopy() *KeptnTask {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KeptnEvaluation.
+func (in *KeptnEvaluation) DeepCopy() *KeptnEvaluation {
 	if in == nil {
 		return nil
 	}
-	out := new(KeptnTask)
+	out := new(KeptnEvaluation)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *KeptnTask) DeepCopyObject() runtime.Object {
+func (in *KeptnEvaluation) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -724,7 +842,7 @@ func (in *KeptnTask) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *KeptnTaskDefinition) DeepCopyInto(out *KeptnTaskDefinition) {
+func (in *KeptnEvaluationDefinition) DeepCopyInto(out *KeptnEvaluationDefinition) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
@@ -732,18 +850,18 @@ func (in *KeptnTaskDefinition) DeepCopyInto(out *KeptnTaskDefinition) {
 	out.Status = in.Status
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KeptnTaskDefinition.
-func (in *KeptnTaskDefinition) DeepCopy() *KeptnTaskDefinition {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KeptnEvaluationDefinition.
+func (in *KeptnEvaluationDefinition) DeepCopy() *KeptnEvaluationDefinition {
 	if in == nil {
 		return nil
 	}
-	out := new(KeptnTaskDefinition)
+	out := new(KeptnEvaluationDefinition)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *KeptnTaskDefinition) DeepCopyObject() runtime.Object {
+func (in *KeptnEvaluationDefinition) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -751,31 +869,31 @@ func (in *KeptnTaskDefinition) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *KeptnTaskDefinitionList) DeepCopyInto(out *KeptnTaskDefinitionList) {
+func (in *KeptnEvaluationDefinitionList) DeepCopyInto(out *KeptnEvaluationDefinitionList) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ListMeta.DeepCopyInto(&out.ListMeta)
 	if in.Items != nil {
 		in, out := &in.Items, &out.Items
-		*out = make([]KeptnTaskDefinition, len(*in))
+		*out = make([]KeptnEvaluationDefinition, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KeptnTaskDefinitionList.
-func (in *KeptnTaskDefinitionList) DeepCopy() *KeptnTaskDefinitionList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KeptnEvaluationDefinitionList.
+func (in *KeptnEvaluationDefinitionList) DeepCopy() *KeptnEvaluationDefinitionList {
 	if in == nil {
 		return nil
 	}
-	out := new(KeptnTaskDefinitionList)
+	out := new(KeptnEvaluationDefinitionList)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *KeptnTaskDefinitionList) DeepCopyObject() runtime.Object {
+func (in *KeptnEvaluationDefinitionList) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -783,13 +901,602 @@ func (in *KeptnTaskDefinitionList) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *KeptnTaskDefinitionSpec) DeepCopyInto(out *KeptnTaskDefinitionSpec) {
+func (in *KeptnEvaluationDefinitionSpec) DeepCopyInto(out *KeptnEvaluationDefinitionSpec) {
 	*out = *in
-	in.Function.DeepCopyInto(&out.Function)
-}
-
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KeptnTaskDefinitionSpec.
-func (in *KeptnTaskDefinitionSpec) DeepCopy() *KeptnTaskDefinitionSpec {
+	if in.Objectives != nil {
+		in, out := &in.Objectives, &out.Objectives
+		*out = make([]Objective, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KeptnEvaluationDefinitionSpec.
+func (in *KeptnEvaluationDefinitionSpec) DeepCopy() *KeptnEvaluationDefinitionSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(KeptnEvaluationDefinitionSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KeptnEvaluationDefinitionStatus) DeepCopyInto(out *KeptnEvaluationDefinitionStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KeptnEvaluationDefinitionStatus.
+func (in *KeptnEvaluationDefinitionStatus) DeepCopy() *KeptnEvaluationDefinitionStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(KeptnEvaluationDefinitionStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KeptnEvaluationList) DeepCopyInto(out *KeptnEvaluationList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]KeptnEvaluation, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KeptnEvaluationList.
+func (in *KeptnEvaluationList) DeepCopy() *KeptnEvaluationList {
+	if in == nil {
+		return nil
+	}
+	out := new(KeptnEvaluationList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *KeptnEvaluationList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KeptnEvaluationProvider) DeepCopyInto(out *KeptnEvaluationProvider) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	out.Spec = in.Spec
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KeptnEvaluationProvider.
+func (in *KeptnEvaluationProvider) DeepCopy() *KeptnEvaluationProvider {
+	if in == nil {
+		return nil
+	}
+	out := new(KeptnEvaluationProvider)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *KeptnEvaluationProvider) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KeptnEvaluationProviderList) DeepCopyInto(out *KeptnEvaluationProviderList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]KeptnEvaluationProvider, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KeptnEvaluationProviderList.
+func (in *KeptnEvaluationProviderList) DeepCopy() *KeptnEvaluationProviderList {
+	if in == nil {
+		return nil
+	}
+	out := new(KeptnEvaluationProviderList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *KeptnEvaluationProviderList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KeptnEvaluationProviderSpec) DeepCopyInto(out *KeptnEvaluationProviderSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KeptnEvaluationProviderSpec.
+func (in *KeptnEvaluationProviderSpec) DeepCopy() *KeptnEvaluationProviderSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(KeptnEvaluationProviderSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KeptnEvaluationProviderStatus) DeepCopyInto(out *KeptnEvaluationProviderStatus) {
+	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]v1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.LastAuthenticationTime != nil {
+		in, out := &in.LastAuthenticationTime, &out.LastAuthenticationTime
+		*out = (*in).DeepCopy()
+	}
+	if in.LastProbeTime != nil {
+		in, out := &in.LastProbeTime, &out.LastProbeTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KeptnEvaluationProviderStatus.
+func (in *KeptnEvaluationProviderStatus) DeepCopy() *KeptnEvaluationProviderStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(KeptnEvaluationProviderStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KeptnEvaluationSpec) DeepCopyInto(out *KeptnEvaluationSpec) {
+	*out = *in
+	out.RetryInterval = in.RetryInterval
+	if in.Deadline != nil {
+		in, out := &in.Deadline, &out.Deadline
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KeptnEvaluationSpec.
+func (in *KeptnEvaluationSpec) DeepCopy() *KeptnEvaluationSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(KeptnEvaluationSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KeptnEvaluationStatus) DeepCopyInto(out *KeptnEvaluationStatus) {
+	*out = *in
+	if in.EvaluationStatus != nil {
+		in, out := &in.EvaluationStatus, &out.EvaluationStatus
+		*out = make(map[string]EvaluationStatusItem, len(*in))
+		for key, val := range *in {
+			(*out)[key] = *val.DeepCopy()
+		}
+	}
+	in.StartTime.DeepCopyInto(&out.StartTime)
+	in.EndTime.DeepCopyInto(&out.EndTime)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KeptnEvaluationStatus.
+func (in *KeptnEvaluationStatus) DeepCopy() *KeptnEvaluationStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(KeptnEvaluationStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KeptnMetric) DeepCopyInto(out *KeptnMetric) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	out.Spec = in.Spec
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KeptnMetric.
+func (in *KeptnMetric) DeepCopy() *KeptnMetric {
+	if in == nil {
+		return nil
+	}
+	out := new(KeptnMetric)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *KeptnMetric) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KeptnMetricList) DeepCopyInto(out *KeptnMetricList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]KeptnMetric, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KeptnMetricList.
+func (in *KeptnMetricList) DeepCopy() *KeptnMetricList {
+	if in == nil {
+		return nil
+	}
+	out := new(KeptnMetricList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *KeptnMetricList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KeptnMetricSpec) DeepCopyInto(out *KeptnMetricSpec) {
+	*out = *in
+	out.FetchInterval = in.FetchInterval
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KeptnMetricSpec.
+func (in *KeptnMetricSpec) DeepCopy() *KeptnMetricSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(KeptnMetricSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KeptnMetricStatus) DeepCopyInto(out *KeptnMetricStatus) {
+	*out = *in
+	in.LastUpdated.DeepCopyInto(&out.LastUpdated)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KeptnMetricStatus.
+func (in *KeptnMetricStatus) DeepCopy() *KeptnMetricStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(KeptnMetricStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KeptnNamespaceStatus) DeepCopyInto(out *KeptnNamespaceStatus) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KeptnNamespaceStatus.
+func (in *KeptnNamespaceStatus) DeepCopy() *KeptnNamespaceStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(KeptnNamespaceStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *KeptnNamespaceStatus) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KeptnNamespaceStatusList) DeepCopyInto(out *KeptnNamespaceStatusList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]KeptnNamespaceStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KeptnNamespaceStatusList.
+func (in *KeptnNamespaceStatusList) DeepCopy() *KeptnNamespaceStatusList {
+	if in == nil {
+		return nil
+	}
+	out := new(KeptnNamespaceStatusList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *KeptnNamespaceStatusList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KeptnNamespaceStatusStatus) DeepCopyInto(out *KeptnNamespaceStatusStatus) {
+	*out = *in
+	if in.LastErrors != nil {
+		in, out := &in.LastErrors, &out.LastErrors
+		*out = make([]NamespaceError, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	in.LastUpdated.DeepCopyInto(&out.LastUpdated)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KeptnNamespaceStatusStatus.
+func (in *KeptnNamespaceStatusStatus) DeepCopy() *KeptnNamespaceStatusStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(KeptnNamespaceStatusStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KeptnNotificationRoute) DeepCopyInto(out *KeptnNotificationRoute) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KeptnNotificationRoute.
+func (in *KeptnNotificationRoute) DeepCopy() *KeptnNotificationRoute {
+	if in == nil {
+		return nil
+	}
+	out := new(KeptnNotificationRoute)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *KeptnNotificationRoute) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KeptnNotificationRouteList) DeepCopyInto(out *KeptnNotificationRouteList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]KeptnNotificationRoute, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KeptnNotificationRouteList.
+func (in *KeptnNotificationRouteList) DeepCopy() *KeptnNotificationRouteList {
+	if in == nil {
+		return nil
+	}
+	out := new(KeptnNotificationRouteList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *KeptnNotificationRouteList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KeptnNotificationRouteSpec) DeepCopyInto(out *KeptnNotificationRouteSpec) {
+	*out = *in
+	in.Match.DeepCopyInto(&out.Match)
+	if in.Sinks != nil {
+		in, out := &in.Sinks, &out.Sinks
+		*out = make([]NotificationSink, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KeptnNotificationRouteSpec.
+func (in *KeptnNotificationRouteSpec) DeepCopy() *KeptnNotificationRouteSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(KeptnNotificationRouteSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KeptnTask) DeepCopyInto(out *KeptnTask) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KeptnTask.
+func (in *KeptnTask) DeepCopy() *KeptnTask {
+	if in == nil {
+		return nil
+	}
+	out := new(KeptnTask)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *KeptnTask) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KeptnTaskDefinition) DeepCopyInto(out *KeptnTaskDefinition) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	out.Status = in.Status
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KeptnTaskDefinition.
+func (in *KeptnTaskDefinition) DeepCopy() *KeptnTaskDefinition {
+	if in == nil {
+		return nil
+	}
+	out := new(KeptnTaskDefinition)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *KeptnTaskDefinition) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KeptnTaskDefinitionList) DeepCopyInto(out *KeptnTaskDefinitionList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]KeptnTaskDefinition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KeptnTaskDefinitionList.
+func (in *KeptnTaskDefinitionList) DeepCopy() *KeptnTaskDefinitionList {
+	if in == nil {
+		return nil
+	}
+	out := new(KeptnTaskDefinitionList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *KeptnTaskDefinitionList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KeptnTaskDefinitionSpec) DeepCopyInto(out *KeptnTaskDefinitionSpec) {
+	*out = *in
+	if in.Duration != nil {
+		in, out := &in.Duration, &out.Duration
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	in.Function.DeepCopyInto(&out.Function)
+	if in.Python != nil {
+		in, out := &in.Python, &out.Python
+		*out = new(FunctionSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Container != nil {
+		in, out := &in.Container, &out.Container
+		*out = new(ContainerSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Retries != nil {
+		in, out := &in.Retries, &out.Retries
+		*out = new(int32)
+		**out = **in
+	}
+	if in.Backoff != nil {
+		in, out := &in.Backoff, &out.Backoff
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.Timeout != nil {
+		in, out := &in.Timeout, &out.Timeout
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.DependsOn != nil {
+		in, out := &in.DependsOn, &out.DependsOn
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KeptnTaskDefinitionSpec.
+func (in *KeptnTaskDefinitionSpec) DeepCopy() *KeptnTaskDefinitionSpec {
 	if in == nil {
 		return nil
 	}
@@ -852,6 +1559,25 @@ func (in *KeptnTaskSpec) DeepCopyInto(out *KeptnTaskSpec) {
 	out.Context = in.Context
 	in.Parameters.DeepCopyInto(&out.Parameters)
 	out.SecureParameters = in.SecureParameters
+	if in.Deadline != nil {
+		in, out := &in.Deadline, &out.Deadline
+		*out = (*in).DeepCopy()
+	}
+	if in.Retries != nil {
+		in, out := &in.Retries, &out.Retries
+		*out = new(int32)
+		**out = **in
+	}
+	if in.Backoff != nil {
+		in, out := &in.Backoff, &out.Backoff
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.Timeout != nil {
+		in, out := &in.Timeout, &out.Timeout
+		*out = new(v1.Duration)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KeptnTaskSpec.
@@ -869,6 +1595,11 @@ func (in *KeptnTaskStatus) DeepCopyInto(out *KeptnTaskStatus) {
 	*out = *in
 	in.StartTime.DeepCopyInto(&out.StartTime)
 	in.EndTime.DeepCopyInto(&out.EndTime)
+	if in.Lock != nil {
+		in, out := &in.Lock, &out.Lock
+		*out = new(TaskLockStatus)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KeptnTaskStatus.
@@ -887,7 +1618,7 @@ func (in *KeptnWorkload) DeepCopyInto(out *KeptnWorkload) {
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
 	in.Spec.DeepCopyInto(&out.Spec)
-	out.Status = in.Status
+	in.Status.DeepCopyInto(&out.Status)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KeptnWorkload.
@@ -1023,6 +1754,42 @@ func (in *KeptnWorkloadInstanceStatus) DeepCopyInto(out *KeptnWorkloadInstanceSt
 	}
 	in.StartTime.DeepCopyInto(&out.StartTime)
 	in.EndTime.DeepCopyInto(&out.EndTime)
+	in.PhaseStartTime.DeepCopyInto(&out.PhaseStartTime)
+	if in.MigrationTaskStatus != nil {
+		in, out := &in.MigrationTaskStatus, &out.MigrationTaskStatus
+		*out = make([]TaskStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	in.DeploymentEndTime.DeepCopyInto(&out.DeploymentEndTime)
+	if in.ChangeSummary != nil {
+		in, out := &in.ChangeSummary, &out.ChangeSummary
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.ResourceSummary != nil {
+		in, out := &in.ResourceSummary, &out.ResourceSummary
+		*out = new(ResourceSummary)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ChangeRequest != nil {
+		in, out := &in.ChangeRequest, &out.ChangeRequest
+		*out = new(ChangeRequestStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Debug != nil {
+		in, out := &in.Debug, &out.Debug
+		*out = new(ReconcileDecision)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]v1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KeptnWorkloadInstanceStatus.
@@ -1106,6 +1873,25 @@ func (in *KeptnWorkloadSpec) DeepCopyInto(out *KeptnWorkloadSpec) {
 		copy(*out, *in)
 	}
 	out.ResourceReference = in.ResourceReference
+	if in.MigrationTasks != nil {
+		in, out := &in.MigrationTasks, &out.MigrationTasks
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	out.PostDeploymentEvaluationDelay = in.PostDeploymentEvaluationDelay
+	if in.ContainerVersions != nil {
+		in, out := &in.ContainerVersions, &out.ContainerVersions
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	in.Timeouts.DeepCopyInto(&out.Timeouts)
+	if in.SkipPhases != nil {
+		in, out := &in.SkipPhases, &out.SkipPhases
+		*out = make([]SkippedPhase, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KeptnWorkloadSpec.
@@ -1121,6 +1907,13 @@ func (in *KeptnWorkloadSpec) DeepCopy() *KeptnWorkloadSpec {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *KeptnWorkloadStatus) DeepCopyInto(out *KeptnWorkloadStatus) {
 	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]v1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KeptnWorkloadStatus.
@@ -1133,9 +1926,104 @@ func (in *KeptnWorkloadStatus) DeepCopy() *KeptnWorkloadStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LifecycleEvent) DeepCopyInto(out *LifecycleEvent) {
+	*out = *in
+	in.FirstTimestamp.DeepCopyInto(&out.FirstTimestamp)
+	in.LastTimestamp.DeepCopyInto(&out.LastTimestamp)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LifecycleEvent.
+func (in *LifecycleEvent) DeepCopy() *LifecycleEvent {
+	if in == nil {
+		return nil
+	}
+	out := new(LifecycleEvent)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NamespaceError) DeepCopyInto(out *NamespaceError) {
+	*out = *in
+	in.Time.DeepCopyInto(&out.Time)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NamespaceError.
+func (in *NamespaceError) DeepCopy() *NamespaceError {
+	if in == nil {
+		return nil
+	}
+	out := new(NamespaceError)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NotificationMatch) DeepCopyInto(out *NotificationMatch) {
+	*out = *in
+	if in.Namespaces != nil {
+		in, out := &in.Namespaces, &out.Namespaces
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.AppSelector != nil {
+		in, out := &in.AppSelector, &out.AppSelector
+		*out = new(v1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Severities != nil {
+		in, out := &in.Severities, &out.Severities
+		*out = make([]NotificationSeverity, len(*in))
+		copy(*out, *in)
+	}
+	if in.Reasons != nil {
+		in, out := &in.Reasons, &out.Reasons
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NotificationMatch.
+func (in *NotificationMatch) DeepCopy() *NotificationMatch {
+	if in == nil {
+		return nil
+	}
+	out := new(NotificationMatch)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NotificationSink) DeepCopyInto(out *NotificationSink) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NotificationSink.
+func (in *NotificationSink) DeepCopy() *NotificationSink {
+	if in == nil {
+		return nil
+	}
+	out := new(NotificationSink)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Objective) DeepCopyInto(out *Objective) {
 	*out = *in
+	if in.Sources != nil {
+		in, out := &in.Sources, &out.Sources
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	out.Window = in.Window
+	out.Step = in.Step
+	if in.BurnRate != nil {
+		in, out := &in.BurnRate, &out.BurnRate
+		*out = new(BurnRate)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Objective.
@@ -1148,6 +2036,64 @@ func (in *Objective) DeepCopy() *Objective {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PhaseTimeouts) DeepCopyInto(out *PhaseTimeouts) {
+	*out = *in
+	if in.PreDeployment != nil {
+		in, out := &in.PreDeployment, &out.PreDeployment
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.Evaluation != nil {
+		in, out := &in.Evaluation, &out.Evaluation
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.Deployment != nil {
+		in, out := &in.Deployment, &out.Deployment
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.PostDeployment != nil {
+		in, out := &in.PostDeployment, &out.PostDeployment
+		*out = new(v1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PhaseTimeouts.
+func (in *PhaseTimeouts) DeepCopy() *PhaseTimeouts {
+	if in == nil {
+		return nil
+	}
+	out := new(PhaseTimeouts)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ReconcileDecision) DeepCopyInto(out *ReconcileDecision) {
+	*out = *in
+	if in.Inputs != nil {
+		in, out := &in.Inputs, &out.Inputs
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	in.Time.DeepCopyInto(&out.Time)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ReconcileDecision.
+func (in *ReconcileDecision) DeepCopy() *ReconcileDecision {
+	if in == nil {
+		return nil
+	}
+	out := new(ReconcileDecision)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ResourceReference) DeepCopyInto(out *ResourceReference) {
 	*out = *in
@@ -1163,6 +2109,69 @@ func (in *ResourceReference) DeepCopy() *ResourceReference {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ResourceSummary) DeepCopyInto(out *ResourceSummary) {
+	*out = *in
+	if in.Requests != nil {
+		in, out := &in.Requests, &out.Requests
+		*out = make(corev1.ResourceList, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val.DeepCopy()
+		}
+	}
+	if in.Limits != nil {
+		in, out := &in.Limits, &out.Limits
+		*out = make(corev1.ResourceList, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val.DeepCopy()
+		}
+	}
+	if in.RequestsDelta != nil {
+		in, out := &in.RequestsDelta, &out.RequestsDelta
+		*out = make(corev1.ResourceList, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val.DeepCopy()
+		}
+	}
+	if in.LimitsDelta != nil {
+		in, out := &in.LimitsDelta, &out.LimitsDelta
+		*out = make(corev1.ResourceList, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val.DeepCopy()
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ResourceSummary.
+func (in *ResourceSummary) DeepCopy() *ResourceSummary {
+	if in == nil {
+		return nil
+	}
+	out := new(ResourceSummary)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RunnerSpec) DeepCopyInto(out *RunnerSpec) {
+	*out = *in
+	if in.ImagePullSecrets != nil {
+		in, out := &in.ImagePullSecrets, &out.ImagePullSecrets
+		*out = make([]corev1.LocalObjectReference, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RunnerSpec.
+func (in *RunnerSpec) DeepCopy() *RunnerSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(RunnerSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *SecureParameters) DeepCopyInto(out *SecureParameters) {
 	*out = *in
@@ -1193,6 +2202,22 @@ func (in *TaskContext) DeepCopy() *TaskContext {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TaskLockStatus) DeepCopyInto(out *TaskLockStatus) {
+	*out = *in
+	in.TransitionTime.DeepCopyInto(&out.TransitionTime)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TaskLockStatus.
+func (in *TaskLockStatus) DeepCopy() *TaskLockStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(TaskLockStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *TaskParameters) DeepCopyInto(out *TaskParameters) {
 	*out = *in
@@ -1232,6 +2257,31 @@ func (in *TaskStatus) DeepCopy() *TaskStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *WorkloadDrift) DeepCopyInto(out *WorkloadDrift) {
+	*out = *in
+	if in.RunningVersions != nil {
+		in, out := &in.RunningVersions, &out.RunningVersions
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.RunningImages != nil {
+		in, out := &in.RunningImages, &out.RunningImages
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WorkloadDrift.
+func (in *WorkloadDrift) DeepCopy() *WorkloadDrift {
+	if in == nil {
+		return nil
+	}
+	out := new(WorkloadDrift)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *WorkloadStatus) DeepCopyInto(out *WorkloadStatus) {
 	*out = *in
@@ -0,0 +1,91 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	klcv1alpha1 "github.com/keptn/lifecycle-controller/operator/api/v1alpha1"
+	"github.com/keptn/lifecycle-controller/operator/importer"
+)
+
+// keptn-import converts a Keptn v1 shipyard or a simple app manifest to a KeptnApp and KeptnTaskDefinitions, which are
+// written to stdout and can be applied with kubectl. Warnings about parts that have to be migrated manually are written to stderr.
+func main() {
+	var format, file, app, namespace, version, workloads, stage, sequence string
+	flag.StringVar(&format, "format", "manifest", "The format of the input, either manifest or shipyard.")
+	flag.StringVar(&file, "file", "", "The file to convert.")
+	flag.StringVar(&namespace, "namespace", "", "The namespace of the created objects. Overrides the namespace of a manifest.")
+	flag.StringVar(&app, "app", "", "The name of the app a shipyard is converted to.")
+	flag.StringVar(&version, "version", "", "The version of the app a shipyard is converted to.")
+	flag.StringVar(&workloads, "workloads", "", "A comma separated list of <name>@<version> of the workloads of the app a shipyard is converted to.")
+	flag.StringVar(&stage, "stage", "", "The stage of the shipyard that is converted. Defaults to the first stage.")
+	flag.StringVar(&sequence, "sequence", "delivery", "The sequence of the stage that is converted.")
+	flag.Parse()
+
+	if file == "" {
+		exit(fmt.Errorf("--file has to be set"))
+	}
+	data, err := os.ReadFile(file)
+	if err != nil {
+		exit(fmt.Errorf("could not read %s: %w", file, err))
+	}
+
+	var result *importer.Result
+	switch format {
+	case "manifest":
+		result, err = importer.FromManifest(data)
+	case "shipyard":
+		var refs []klcv1alpha1.KeptnWorkloadRef
+		refs, err = parseWorkloads(workloads)
+		if err == nil {
+			result, err = importer.FromShipyard(data, importer.ShipyardOptions{
+				App:       app,
+				Namespace: namespace,
+				Version:   version,
+				Workloads: refs,
+				Stage:     stage,
+				Sequence:  sequence,
+			})
+		}
+	default:
+		err = fmt.Errorf("unknown format %s, expected manifest or shipyard", format)
+	}
+	if err != nil {
+		exit(err)
+	}
+
+	if namespace != "" {
+		result.App.Namespace = namespace
+		for i := range result.TaskDefinitions {
+			result.TaskDefinitions[i].Namespace = namespace
+		}
+	}
+	for _, warning := range result.Warnings {
+		fmt.Fprintln(os.Stderr, "WARNING: "+warning)
+	}
+	if err := result.Write(os.Stdout); err != nil {
+		exit(err)
+	}
+}
+
+func parseWorkloads(value string) ([]klcv1alpha1.KeptnWorkloadRef, error) {
+	var refs []klcv1alpha1.KeptnWorkloadRef
+	for _, workload := range strings.Split(value, ",") {
+		if workload = strings.TrimSpace(workload); workload == "" {
+			continue
+		}
+		name, version, ok := strings.Cut(workload, "@")
+		if !ok || name == "" || version == "" {
+			return nil, fmt.Errorf("invalid workload %s, expected <name>@<version>", workload)
+		}
+		refs = append(refs, klcv1alpha1.KeptnWorkloadRef{Name: name, Version: version})
+	}
+	return refs, nil
+}
+
+func exit(err error) {
+	fmt.Fprintln(os.Stderr, err)
+	os.Exit(1)
+}
@@ -0,0 +1,50 @@
+package apply
+
+import (
+	"context"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+)
+
+// FieldManager is the field manager of the resources generated by the operator
+const FieldManager = "keptn-lifecycle-operator"
+
+// Apply creates or updates a generated resource with server-side apply.
+// Only the fields set in obj are owned by the operator, so fields added by users are kept instead of being overwritten by the next update.
+// If a user changed a field the operator sets, the operator does not take the field back: the conflict is reported as
+// event on the owner and returned, so that the user can resolve it.
+func Apply(ctx context.Context, c client.Client, obj client.Object, recorder record.EventRecorder, owner runtime.Object) error {
+	gvk, err := apiutil.GVKForObject(obj, c.Scheme())
+	if err != nil {
+		return fmt.Errorf("could not determine kind of %s: %w", obj.GetName(), err)
+	}
+	obj.GetObjectKind().SetGroupVersionKind(gvk)
+	obj.SetResourceVersion("")
+	obj.SetManagedFields(nil)
+
+	err = c.Patch(ctx, obj, client.Apply, client.FieldOwner(FieldManager))
+	if !errors.IsConflict(err) {
+		return err
+	}
+	recorder.Event(owner, "Warning", "FieldConflict", fmt.Sprintf("Could not apply %s since fields have been changed by another manager / Namespace: %s, Name: %s, Conflict: %s", gvk.Kind, obj.GetNamespace(), obj.GetName(), err.Error()))
+	return fmt.Errorf("could not apply %s %s: %w", gvk.Kind, obj.GetName(), err)
+}
+
+// KeepAnnotations replaces the values of the annotations of obj by the ones of the existing resource, so that applying obj
+// does not change annotations that are only set on creation, e.g. the trace context
+func KeepAnnotations(obj client.Object, existing client.Object) {
+	annotations := obj.GetAnnotations()
+	for key := range annotations {
+		if value, ok := existing.GetAnnotations()[key]; ok {
+			annotations[key] = value
+		} else {
+			delete(annotations, key)
+		}
+	}
+	obj.SetAnnotations(annotations)
+}
@@ -0,0 +1,83 @@
+package apply
+
+import (
+	"context"
+	"testing"
+
+	testrequire "github.com/stretchr/testify/require"
+	batchv1 "k8s.io/api/batch/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+// patchClient records the patches instead of sending them, since the fake client does not support server-side apply
+type patchClient struct {
+	client.Client
+	err     error
+	patches []client.PatchOptions
+}
+
+func (c *patchClient) Patch(ctx context.Context, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+	if patch != client.Apply {
+		return c.Client.Patch(ctx, obj, patch, opts...)
+	}
+	options := client.PatchOptions{}
+	options.ApplyOptions(opts)
+	c.patches = append(c.patches, options)
+	return c.err
+}
+
+func newJob() *batchv1.Job {
+	return &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            "my-job",
+			Namespace:       "default",
+			ResourceVersion: "42",
+			ManagedFields:   []metav1.ManagedFieldsEntry{{Manager: "kubectl"}},
+		},
+	}
+}
+
+func TestApply(t *testing.T) {
+	c := &patchClient{Client: fake.NewClientBuilder().Build()}
+	recorder := record.NewFakeRecorder(10)
+	job := newJob()
+
+	err := Apply(context.TODO(), c, job, recorder, job)
+	testrequire.Nil(t, err)
+	testrequire.Len(t, c.patches, 1)
+	testrequire.Equal(t, FieldManager, c.patches[0].FieldManager)
+	testrequire.Nil(t, c.patches[0].Force)
+	testrequire.Equal(t, "Job", job.Kind)
+	testrequire.Empty(t, job.ResourceVersion)
+	testrequire.Empty(t, job.ManagedFields)
+	testrequire.Empty(t, recorder.Events)
+}
+
+func TestApplyConflict(t *testing.T) {
+	conflict := errors.NewConflict(schema.GroupResource{Group: "batch", Resource: "jobs"}, "my-job", nil)
+	c := &patchClient{Client: fake.NewClientBuilder().Build(), err: conflict}
+	recorder := record.NewFakeRecorder(10)
+	job := newJob()
+
+	err := Apply(context.TODO(), c, job, recorder, job)
+	testrequire.True(t, errors.IsConflict(err))
+	// the fields changed by another manager are not taken over
+	testrequire.Len(t, c.patches, 1)
+	testrequire.Nil(t, c.patches[0].Force)
+	testrequire.Contains(t, <-recorder.Events, "FieldConflict")
+}
+
+func TestKeepAnnotations(t *testing.T) {
+	job := newJob()
+	job.Annotations = map[string]string{"traceparent": "new", "added": "value"}
+	existing := newJob()
+	existing.Annotations = map[string]string{"traceparent": "old"}
+
+	KeepAnnotations(job, existing)
+	testrequire.Equal(t, map[string]string{"traceparent": "old"}, job.Annotations)
+}
@@ -0,0 +1,134 @@
+package deadline
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	klcv1alpha1 "github.com/keptn/lifecycle-controller/operator/api/v1alpha1"
+	"github.com/keptn/lifecycle-controller/operator/api/v1alpha1/common"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// Resolve returns the maximum duration of the app versions and workload instances of the namespace, which is set using
+// the keptn.sh/max-deployment-duration annotation of the namespace, e.g. 30m.
+// If the annotation is not set, the duration is 0 and the instances have no deadline.
+func Resolve(ctx context.Context, c client.Reader, namespace string) (time.Duration, error) {
+	ns := &corev1.Namespace{}
+	if err := c.Get(ctx, types.NamespacedName{Name: namespace}, ns); err != nil {
+		return 0, fmt.Errorf("could not fetch namespace %s: %w", namespace, err)
+	}
+	value, ok := ns.Annotations[common.MaxDeploymentDurationAnnotation]
+	if !ok {
+		return 0, nil
+	}
+	maxDuration, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, fmt.Errorf("could not parse %s of namespace %s: %w", common.MaxDeploymentDurationAnnotation, namespace, err)
+	}
+	return maxDuration, nil
+}
+
+// Exceeded returns whether an instance started at the given time runs longer than the maximum duration
+func Exceeded(startTime metav1.Time, maxDuration time.Duration, now time.Time) bool {
+	return maxDuration > 0 && !startTime.IsZero() && now.Sub(startTime.Time) > maxDuration
+}
+
+// Of returns the deadline of an instance of the namespace started at the given time, which is passed on to its
+// KeptnTasks and KeptnEvaluations so that they cannot run longer than the instance itself.
+// If the namespace has no maximum duration or the instance has not started yet, the deadline is nil.
+func Of(ctx context.Context, c client.Reader, namespace string, startTime metav1.Time) (*metav1.Time, error) {
+	maxDuration, err := Resolve(ctx, c, namespace)
+	if err != nil {
+		return nil, err
+	}
+	if maxDuration <= 0 || startTime.IsZero() {
+		return nil, nil
+	}
+	deadline := metav1.NewTime(startTime.Add(maxDuration))
+	return &deadline, nil
+}
+
+// Remaining returns the time left until the given deadline, which is 0 if the deadline has passed
+func Remaining(deadline metav1.Time, now time.Time) time.Duration {
+	if remaining := deadline.Sub(now); remaining > 0 {
+		return remaining
+	}
+	return 0
+}
+
+// PhaseTimeout returns the timeout of the given phase of an app version or workload instance, which is 0 if the phase
+// has no timeout
+func PhaseTimeout(timeouts klcv1alpha1.PhaseTimeouts, phase common.KeptnPhaseType) time.Duration {
+	var timeout *metav1.Duration
+	switch phase {
+	case common.PhaseAppPreDeployment, common.PhaseWorkloadPreDeployment:
+		timeout = timeouts.PreDeployment
+	case common.PhaseAppPreEvaluation, common.PhaseAppPostEvaluation, common.PhaseWorkloadPreEvaluation, common.PhaseWorkloadPostEvaluation:
+		timeout = timeouts.Evaluation
+	case common.PhaseAppDeployment, common.PhaseWorkloadDeployment:
+		timeout = timeouts.Deployment
+	case common.PhaseAppPostDeployment, common.PhaseWorkloadPostDeployment:
+		timeout = timeouts.PostDeployment
+	}
+	if timeout == nil {
+		return 0
+	}
+	return timeout.Duration
+}
+
+// FailPhase sets the first of the given states of the phases that has not succeeded to failed
+func FailPhase(states ...*common.KeptnState) {
+	for _, state := range states {
+		if !state.IsSucceeded() {
+			*state = common.StateFailed
+			return
+		}
+	}
+}
+
+// Cleanup deletes the KeptnTasks and KeptnEvaluations that have not completed yet, together with their Jobs,
+// and sets their states to failed
+func Cleanup(ctx context.Context, c client.Client, namespace string, tasks []klcv1alpha1.TaskStatus, evaluations []klcv1alpha1.EvaluationStatus) error {
+	return cleanup(ctx, c, namespace, tasks, evaluations, common.StateFailed)
+}
+
+// Cancel deletes the KeptnTasks and KeptnEvaluations that have not completed yet, together with their Jobs,
+// and sets their states to cancelled
+func Cancel(ctx context.Context, c client.Client, namespace string, tasks []klcv1alpha1.TaskStatus, evaluations []klcv1alpha1.EvaluationStatus) error {
+	return cleanup(ctx, c, namespace, tasks, evaluations, common.StateCancelled)
+}
+
+func cleanup(ctx context.Context, c client.Client, namespace string, tasks []klcv1alpha1.TaskStatus, evaluations []klcv1alpha1.EvaluationStatus, state common.KeptnState) error {
+	for i := range tasks {
+		if tasks[i].Status.IsCompleted() {
+			continue
+		}
+		if tasks[i].TaskName != "" {
+			task := &klcv1alpha1.KeptnTask{ObjectMeta: metav1.ObjectMeta{Name: tasks[i].TaskName, Namespace: namespace}}
+			if err := c.Delete(ctx, task, client.PropagationPolicy(metav1.DeletePropagationBackground)); err != nil && !errors.IsNotFound(err) {
+				return fmt.Errorf("could not delete KeptnTask %s: %w", tasks[i].TaskName, err)
+			}
+		}
+		tasks[i].Status = state
+		tasks[i].SetEndTime()
+	}
+	for i := range evaluations {
+		if evaluations[i].Status.IsCompleted() {
+			continue
+		}
+		if evaluations[i].EvaluationName != "" {
+			evaluation := &klcv1alpha1.KeptnEvaluation{ObjectMeta: metav1.ObjectMeta{Name: evaluations[i].EvaluationName, Namespace: namespace}}
+			if err := c.Delete(ctx, evaluation, client.PropagationPolicy(metav1.DeletePropagationBackground)); err != nil && !errors.IsNotFound(err) {
+				return fmt.Errorf("could not delete KeptnEvaluation %s: %w", evaluations[i].EvaluationName, err)
+			}
+		}
+		evaluations[i].Status = state
+		evaluations[i].SetEndTime()
+	}
+	return nil
+}
@@ -0,0 +1,137 @@
+package deadline
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	klcv1alpha1 "github.com/keptn/lifecycle-controller/operator/api/v1alpha1"
+	"github.com/keptn/lifecycle-controller/operator/api/v1alpha1/common"
+	testrequire "github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestResolve(t *testing.T) {
+	c := fake.NewClientBuilder().WithObjects(
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "podtato", Annotations: map[string]string{common.MaxDeploymentDurationAnnotation: "30m"}}},
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "invalid", Annotations: map[string]string{common.MaxDeploymentDurationAnnotation: "forever"}}},
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "other"}},
+	).Build()
+
+	maxDuration, err := Resolve(context.TODO(), c, "podtato")
+	testrequire.Nil(t, err)
+	testrequire.Equal(t, 30*time.Minute, maxDuration)
+
+	_, err = Resolve(context.TODO(), c, "invalid")
+	testrequire.NotNil(t, err)
+
+	maxDuration, err = Resolve(context.TODO(), c, "other")
+	testrequire.Nil(t, err)
+	testrequire.Zero(t, maxDuration)
+}
+
+func TestExceeded(t *testing.T) {
+	now := time.Now()
+	testrequire.True(t, Exceeded(metav1.NewTime(now.Add(-time.Hour)), 30*time.Minute, now))
+	testrequire.False(t, Exceeded(metav1.NewTime(now.Add(-time.Minute)), 30*time.Minute, now))
+	testrequire.False(t, Exceeded(metav1.NewTime(now.Add(-time.Hour)), 0, now))
+	testrequire.False(t, Exceeded(metav1.Time{}, 30*time.Minute, now))
+}
+
+func TestOf(t *testing.T) {
+	c := fake.NewClientBuilder().WithObjects(
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "podtato", Annotations: map[string]string{common.MaxDeploymentDurationAnnotation: "30m"}}},
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "other"}},
+	).Build()
+	startTime := metav1.NewTime(time.Date(2022, 11, 1, 12, 0, 0, 0, time.UTC))
+
+	deadline, err := Of(context.TODO(), c, "podtato", startTime)
+	testrequire.Nil(t, err)
+	testrequire.NotNil(t, deadline)
+	testrequire.True(t, deadline.Time.Equal(startTime.Add(30*time.Minute)))
+
+	deadline, err = Of(context.TODO(), c, "podtato", metav1.Time{})
+	testrequire.Nil(t, err)
+	testrequire.Nil(t, deadline)
+
+	deadline, err = Of(context.TODO(), c, "other", startTime)
+	testrequire.Nil(t, err)
+	testrequire.Nil(t, deadline)
+
+	_, err = Of(context.TODO(), c, "missing", startTime)
+	testrequire.NotNil(t, err)
+}
+
+func TestPhaseTimeout(t *testing.T) {
+	timeouts := klcv1alpha1.PhaseTimeouts{
+		PreDeployment: &metav1.Duration{Duration: 5 * time.Minute},
+		Evaluation:    &metav1.Duration{Duration: 10 * time.Minute},
+	}
+	testrequire.Equal(t, 5*time.Minute, PhaseTimeout(timeouts, common.PhaseAppPreDeployment))
+	testrequire.Equal(t, 5*time.Minute, PhaseTimeout(timeouts, common.PhaseWorkloadPreDeployment))
+	testrequire.Equal(t, 10*time.Minute, PhaseTimeout(timeouts, common.PhaseAppPostEvaluation))
+	testrequire.Zero(t, PhaseTimeout(timeouts, common.PhaseWorkloadDeployment))
+	testrequire.Zero(t, PhaseTimeout(timeouts, common.PhaseCompleted))
+}
+
+func TestRemaining(t *testing.T) {
+	now := time.Now()
+	testrequire.Equal(t, 10*time.Minute, Remaining(metav1.NewTime(now.Add(10*time.Minute)), now))
+	testrequire.Zero(t, Remaining(metav1.NewTime(now.Add(-time.Minute)), now))
+}
+
+func TestCleanup(t *testing.T) {
+	scheme := runtime.NewScheme()
+	testrequire.Nil(t, klcv1alpha1.AddToScheme(scheme))
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+		&klcv1alpha1.KeptnTask{ObjectMeta: metav1.ObjectMeta{Name: "running-task", Namespace: "default"}},
+		&klcv1alpha1.KeptnTask{ObjectMeta: metav1.ObjectMeta{Name: "succeeded-task", Namespace: "default"}},
+	).Build()
+
+	tasks := []klcv1alpha1.TaskStatus{
+		{TaskName: "running-task", Status: common.StateProgressing},
+		{TaskName: "succeeded-task", Status: common.StateSucceeded},
+	}
+	evaluations := []klcv1alpha1.EvaluationStatus{{Status: common.StatePending}}
+	testrequire.Nil(t, Cleanup(context.TODO(), c, "default", tasks, evaluations))
+
+	testrequire.Equal(t, common.StateFailed, tasks[0].Status)
+	testrequire.Equal(t, common.StateSucceeded, tasks[1].Status)
+	testrequire.Equal(t, common.StateFailed, evaluations[0].Status)
+
+	err := c.Get(context.TODO(), types.NamespacedName{Namespace: "default", Name: "running-task"}, &klcv1alpha1.KeptnTask{})
+	testrequire.True(t, errors.IsNotFound(err))
+	testrequire.Nil(t, c.Get(context.TODO(), types.NamespacedName{Namespace: "default", Name: "succeeded-task"}, &klcv1alpha1.KeptnTask{}))
+
+	succeeded, pending := common.StateSucceeded, common.StatePending
+	FailPhase(&succeeded, &pending)
+	testrequire.Equal(t, common.StateFailed, pending)
+}
+
+func TestCancel(t *testing.T) {
+	scheme := runtime.NewScheme()
+	testrequire.Nil(t, klcv1alpha1.AddToScheme(scheme))
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+		&klcv1alpha1.KeptnEvaluation{ObjectMeta: metav1.ObjectMeta{Name: "running-evaluation", Namespace: "default"}},
+	).Build()
+
+	tasks := []klcv1alpha1.TaskStatus{{TaskName: "deleted-task", Status: common.StatePending}}
+	evaluations := []klcv1alpha1.EvaluationStatus{
+		{EvaluationName: "running-evaluation", Status: common.StateProgressing},
+		{EvaluationName: "failed-evaluation", Status: common.StateFailed},
+	}
+	testrequire.Nil(t, Cancel(context.TODO(), c, "default", tasks, evaluations))
+
+	testrequire.Equal(t, common.StateCancelled, tasks[0].Status)
+	testrequire.Equal(t, common.StateCancelled, evaluations[0].Status)
+	testrequire.Equal(t, common.StateFailed, evaluations[1].Status)
+	testrequire.False(t, evaluations[0].EndTime.IsZero())
+
+	err := c.Get(context.TODO(), types.NamespacedName{Namespace: "default", Name: "running-evaluation"}, &klcv1alpha1.KeptnEvaluation{})
+	testrequire.True(t, errors.IsNotFound(err))
+}
@@ -0,0 +1,57 @@
+package debug
+
+import (
+	"sort"
+	"time"
+
+	klcv1alpha1 "github.com/keptn/lifecycle-controller/operator/api/v1alpha1"
+	"github.com/keptn/lifecycle-controller/operator/api/v1alpha1/common"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+// MaxLength is the maximum length of the branch, the error and the inputs of a decision, which keeps the status of
+// the resources small although the decision is recorded on every reconciliation
+const MaxLength = 512
+
+// MaxInputs is the maximum number of inputs of a decision
+const MaxInputs = 16
+
+// NewDecision returns the decision of a reconciler which took the given branch in the given phase
+func NewDecision(phase string, branch string) *klcv1alpha1.ReconcileDecision {
+	return &klcv1alpha1.ReconcileDecision{
+		Phase:  phase,
+		Branch: common.TruncateString(branch, MaxLength),
+	}
+}
+
+// Complete adds the inputs and the result of the reconciliation to the decision.
+// Inputs exceeding MaxInputs are dropped in alphabetical order of their keys.
+func Complete(decision *klcv1alpha1.ReconcileDecision, inputs map[string]string, result ctrl.Result, err error, now time.Time) {
+	keys := make([]string, 0, len(inputs))
+	for key := range inputs {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	if len(keys) > MaxInputs {
+		keys = keys[:MaxInputs]
+	}
+	decision.Inputs = make(map[string]string, len(keys))
+	for _, key := range keys {
+		decision.Inputs[key] = common.TruncateString(inputs[key], MaxLength)
+	}
+
+	switch {
+	case result.RequeueAfter > 0:
+		decision.RequeueAfter = result.RequeueAfter.String()
+	case result.Requeue || err != nil:
+		decision.RequeueAfter = "0s"
+	default:
+		decision.RequeueAfter = ""
+	}
+	decision.Error = ""
+	if err != nil {
+		decision.Error = common.TruncateString(err.Error(), MaxLength)
+	}
+	decision.Time = metav1.NewTime(now)
+}
@@ -0,0 +1,38 @@
+package debug
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	testrequire "github.com/stretchr/testify/require"
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+func TestComplete(t *testing.T) {
+	decision := NewDecision("WorkloadDeploy", "WorkloadDeployNotFinished: "+strings.Repeat("x", 2*MaxLength))
+	testrequire.Len(t, decision.Branch, MaxLength)
+
+	inputs := map[string]string{}
+	for i := 0; i < 2*MaxInputs; i++ {
+		inputs[fmt.Sprintf("input%02d", i)] = strings.Repeat("y", 2*MaxLength)
+	}
+	now := time.Now()
+	Complete(decision, inputs, ctrl.Result{Requeue: true, RequeueAfter: time.Minute}, nil, now)
+	testrequire.Len(t, decision.Inputs, MaxInputs)
+	testrequire.Contains(t, decision.Inputs, "input00")
+	testrequire.NotContains(t, decision.Inputs, fmt.Sprintf("input%02d", MaxInputs))
+	testrequire.Len(t, decision.Inputs["input00"], MaxLength)
+	testrequire.Equal(t, "1m0s", decision.RequeueAfter)
+	testrequire.Empty(t, decision.Error)
+	testrequire.Equal(t, now.Unix(), decision.Time.Unix())
+
+	Complete(decision, nil, ctrl.Result{}, fmt.Errorf("conflict"), now)
+	testrequire.Equal(t, "0s", decision.RequeueAfter)
+	testrequire.Equal(t, "conflict", decision.Error)
+	testrequire.Empty(t, decision.Inputs)
+
+	Complete(decision, nil, ctrl.Result{}, nil, now)
+	testrequire.Empty(t, decision.RequeueAfter)
+}
@@ -0,0 +1,104 @@
+package definitions
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	klcv1alpha1 "github.com/keptn/lifecycle-controller/operator/api/v1alpha1"
+	"github.com/keptn/lifecycle-controller/operator/controllers/reasons"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// ResolveCondition checks that the referenced KeptnTaskDefinitions and KeptnEvaluationDefinitions exist in the namespace
+// and returns the DefinitionsResolved condition, so that missing definitions are reported when an app or workload is created
+// instead of failing only when the tasks and evaluations of one of its instances are started
+func ResolveCondition(ctx context.Context, c client.Reader, namespace string, generation int64, tasks []string, evaluations []string) (metav1.Condition, error) {
+	missingTasks := []string{}
+	for _, name := range unique(tasks) {
+		found, err := exists(ctx, c, types.NamespacedName{Namespace: namespace, Name: name}, &klcv1alpha1.KeptnTaskDefinition{})
+		if err != nil {
+			return metav1.Condition{}, fmt.Errorf("could not retrieve KeptnTaskDefinition %s: %w", name, err)
+		}
+		if !found {
+			missingTasks = append(missingTasks, name)
+		}
+	}
+
+	missingEvaluations := []string{}
+	for _, name := range unique(evaluations) {
+		found, err := exists(ctx, c, types.NamespacedName{Namespace: namespace, Name: name}, &klcv1alpha1.KeptnEvaluationDefinition{})
+		if err != nil {
+			return metav1.Condition{}, fmt.Errorf("could not retrieve KeptnEvaluationDefinition %s: %w", name, err)
+		}
+		if !found {
+			missingEvaluations = append(missingEvaluations, name)
+		}
+	}
+
+	condition := metav1.Condition{
+		Type:               klcv1alpha1.DefinitionsResolved,
+		Status:             metav1.ConditionTrue,
+		ObservedGeneration: generation,
+		Reason:             reasons.DefinitionsResolved.Code,
+		Message:            reasons.DefinitionsResolved.Message(),
+	}
+	if len(missingTasks) == 0 && len(missingEvaluations) == 0 {
+		return condition, nil
+	}
+
+	messages := []string{}
+	if len(missingTasks) > 0 {
+		messages = append(messages, reasons.DefinitionsNotFound.Message("KeptnTaskDefinitions", strings.Join(missingTasks, ", ")))
+	}
+	if len(missingEvaluations) > 0 {
+		messages = append(messages, reasons.DefinitionsNotFound.Message("KeptnEvaluationDefinitions", strings.Join(missingEvaluations, ", ")))
+	}
+	condition.Status = metav1.ConditionFalse
+	condition.Reason = reasons.DefinitionsNotFound.Code
+	condition.Message = strings.Join(messages, "; ")
+	return condition, nil
+}
+
+// References returns whether the given definition is one of the referenced definitions
+func References(definition string, references ...[]string) bool {
+	for _, names := range references {
+		for _, name := range names {
+			if name == definition {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func exists(ctx context.Context, c client.Reader, name types.NamespacedName, obj client.Object) (bool, error) {
+	err := c.Get(ctx, name, obj)
+	if errors.IsNotFound(err) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+func unique(names []string) []string {
+	seen := map[string]bool{}
+	res := []string{}
+	for _, name := range names {
+		if name == "" || seen[name] {
+			continue
+		}
+		seen[name] = true
+		res = append(res, name)
+	}
+	return res
+}
+
+// ConditionChanged returns whether the condition differs from the one of the same type in conditions
+func ConditionChanged(conditions []metav1.Condition, condition metav1.Condition) bool {
+	current := meta.FindStatusCondition(conditions, condition.Type)
+	return current == nil || current.Status != condition.Status || current.Message != condition.Message || current.ObservedGeneration != condition.ObservedGeneration
+}
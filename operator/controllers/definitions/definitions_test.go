@@ -0,0 +1,37 @@
+package definitions
+
+import (
+	"context"
+	"testing"
+
+	klcv1alpha1 "github.com/keptn/lifecycle-controller/operator/api/v1alpha1"
+	"github.com/keptn/lifecycle-controller/operator/controllers/reasons"
+	testrequire "github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestResolveCondition(t *testing.T) {
+	scheme := runtime.NewScheme()
+	testrequire.Nil(t, klcv1alpha1.AddToScheme(scheme))
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+		&klcv1alpha1.KeptnTaskDefinition{ObjectMeta: metav1.ObjectMeta{Name: "notify", Namespace: "default"}},
+		&klcv1alpha1.KeptnEvaluationDefinition{ObjectMeta: metav1.ObjectMeta{Name: "slo", Namespace: "default"}},
+	).Build()
+
+	condition, err := ResolveCondition(context.TODO(), c, "default", 2, []string{"notify", "notify"}, []string{"slo"})
+	testrequire.Nil(t, err)
+	testrequire.Equal(t, metav1.ConditionTrue, condition.Status)
+	testrequire.Equal(t, int64(2), condition.ObservedGeneration)
+
+	condition, err = ResolveCondition(context.TODO(), c, "default", 2, []string{"notify", "smoke-test"}, []string{"slo", "latency"})
+	testrequire.Nil(t, err)
+	testrequire.Equal(t, metav1.ConditionFalse, condition.Status)
+	testrequire.Equal(t, reasons.DefinitionsNotFound.Code, condition.Reason)
+	testrequire.Equal(t, "KeptnTaskDefinitions not found: smoke-test; KeptnEvaluationDefinitions not found: latency", condition.Message)
+
+	condition, err = ResolveCondition(context.TODO(), c, "other", 1, []string{"notify"}, nil)
+	testrequire.Nil(t, err)
+	testrequire.Equal(t, metav1.ConditionFalse, condition.Status)
+}
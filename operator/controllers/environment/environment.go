@@ -0,0 +1,45 @@
+package environment
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/keptn/lifecycle-controller/operator/api/v1alpha1/common"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// Resolve returns the value of the given label of the namespace, which is the environment the namespace belongs to, e.g. environment=prod.
+// If no label is configured, the environment is empty.
+func Resolve(ctx context.Context, c client.Reader, namespace string, label string) (string, error) {
+	if label == "" {
+		return "", nil
+	}
+	ns := &corev1.Namespace{}
+	if err := c.Get(ctx, types.NamespacedName{Name: namespace}, ns); err != nil {
+		return "", fmt.Errorf("could not fetch namespace %s: %w", namespace, err)
+	}
+	return ns.Labels[label], nil
+}
+
+// IsProduction returns whether the namespace is annotated as production environment
+func IsProduction(ctx context.Context, c client.Reader, namespace string) (bool, error) {
+	ns := &corev1.Namespace{}
+	if err := c.Get(ctx, types.NamespacedName{Name: namespace}, ns); err != nil {
+		return false, fmt.Errorf("could not fetch namespace %s: %w", namespace, err)
+	}
+	return ns.GetAnnotations()[common.EnvironmentAnnotation] == common.EnvironmentProduction, nil
+}
+
+// Labels returns the given labels together with the label carrying the environment, if the environment is known
+func Labels(labels map[string]string, environment string) map[string]string {
+	if environment == "" {
+		return labels
+	}
+	if labels == nil {
+		labels = map[string]string{}
+	}
+	labels[common.EnvironmentLabel] = environment
+	return labels
+}
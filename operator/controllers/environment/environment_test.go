@@ -0,0 +1,38 @@
+package environment
+
+import (
+	"context"
+	"testing"
+
+	"github.com/keptn/lifecycle-controller/operator/api/v1alpha1/common"
+	testrequire "github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestResolve(t *testing.T) {
+	c := fake.NewClientBuilder().WithObjects(&corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{Name: "podtato", Labels: map[string]string{"environment": "prod"}},
+	}).Build()
+
+	environment, err := Resolve(context.TODO(), c, "podtato", "environment")
+	testrequire.Nil(t, err)
+	testrequire.Equal(t, "prod", environment)
+
+	environment, err = Resolve(context.TODO(), c, "podtato", "stage")
+	testrequire.Nil(t, err)
+	testrequire.Empty(t, environment)
+
+	environment, err = Resolve(context.TODO(), c, "other", "")
+	testrequire.Nil(t, err)
+	testrequire.Empty(t, environment)
+
+	_, err = Resolve(context.TODO(), c, "other", "environment")
+	testrequire.NotNil(t, err)
+}
+
+func TestLabels(t *testing.T) {
+	testrequire.Nil(t, Labels(nil, ""))
+	testrequire.Equal(t, map[string]string{common.EnvironmentLabel: "prod", "a": "b"}, Labels(map[string]string{"a": "b"}, "prod"))
+}
@@ -0,0 +1,80 @@
+package errors
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/keptn/lifecycle-controller/operator/controllers/reasons"
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+var (
+	// ErrAppVersionNotFound is returned if there is no KeptnAppVersion containing a workload instance yet
+	ErrAppVersionNotFound = errors.New("KeptnAppVersion not found")
+	// ErrTaskDefinitionMissing is returned if the KeptnTaskDefinition of a task does not exist
+	ErrTaskDefinitionMissing = errors.New("KeptnTaskDefinition not found")
+	// ErrProviderUnavailable is returned if a KeptnEvaluationProvider or the secret of its credentials cannot be retrieved
+	ErrProviderUnavailable = errors.New("KeptnEvaluationProvider unavailable")
+)
+
+// the reasons and requeue intervals of the sentinel errors, which only resolve once the missing object is created
+var policies = []struct {
+	sentinel     error
+	reason       string
+	requeueAfter time.Duration
+}{
+	{ErrAppVersionNotFound, reasons.AppVersionNotFound.Code, 10 * time.Second},
+	{ErrTaskDefinitionMissing, reasons.TaskDefinitionNotFound.Code, 30 * time.Second},
+	{ErrProviderUnavailable, reasons.ProviderUnavailable.Code, 30 * time.Second},
+}
+
+// Is reports whether err wraps the target error. It spares controllers that import the API errors of Kubernetes as
+// errors a second import of the standard library package.
+func Is(err error, target error) bool {
+	return errors.Is(err, target)
+}
+
+// Reason returns the reason of the sentinel error wrapped by err, which is used for events and metric labels.
+// It returns "Unknown" for other errors.
+func Reason(err error) string {
+	for _, policy := range policies {
+		if errors.Is(err, policy.sentinel) {
+			return policy.reason
+		}
+	}
+	return "Unknown"
+}
+
+// Result returns the result of a reconciliation that failed with err. Sentinel errors are retried after a fixed
+// interval without being returned, other errors are returned to be retried with backoff.
+func Result(err error) (ctrl.Result, error) {
+	for _, policy := range policies {
+		if errors.Is(err, policy.sentinel) {
+			return ctrl.Result{Requeue: true, RequeueAfter: policy.requeueAfter}, nil
+		}
+	}
+	return ctrl.Result{Requeue: true}, err
+}
+
+// Wrap annotates the cause with the sentinel error, so that errors.Is matches both of them
+func Wrap(sentinel error, cause error) error {
+	return &wrapped{sentinel: sentinel, cause: cause}
+}
+
+type wrapped struct {
+	sentinel error
+	cause    error
+}
+
+func (e *wrapped) Error() string {
+	return fmt.Sprintf("%s: %s", e.sentinel, e.cause)
+}
+
+func (e *wrapped) Is(target error) bool {
+	return target == e.sentinel
+}
+
+func (e *wrapped) Unwrap() error {
+	return e.cause
+}
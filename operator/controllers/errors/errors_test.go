@@ -0,0 +1,34 @@
+package errors
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	testrequire "github.com/stretchr/testify/require"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+func TestWrap(t *testing.T) {
+	cause := apierrors.NewNotFound(schema.GroupResource{Group: "lifecycle.keptn.sh", Resource: "keptntaskdefinitions"}, "my-task")
+	err := fmt.Errorf("could not retrieve task: %w", Wrap(ErrTaskDefinitionMissing, cause))
+
+	testrequire.True(t, errors.Is(err, ErrTaskDefinitionMissing))
+	testrequire.False(t, errors.Is(err, ErrProviderUnavailable))
+	testrequire.True(t, apierrors.IsNotFound(err))
+	testrequire.Equal(t, "TaskDefinitionNotFound", Reason(err))
+}
+
+func TestResult(t *testing.T) {
+	result, err := Result(fmt.Errorf("could not find AppVersion: %w", ErrAppVersionNotFound))
+	testrequire.Nil(t, err)
+	testrequire.Equal(t, 10*time.Second, result.RequeueAfter)
+
+	cause := errors.New("connection refused")
+	result, err = Result(cause)
+	testrequire.Equal(t, cause, err)
+	testrequire.True(t, result.Requeue)
+	testrequire.Equal(t, "Unknown", Reason(cause))
+}
@@ -0,0 +1,123 @@
+package evaluationpreset
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/go-logr/logr"
+	klcv1alpha1 "github.com/keptn/lifecycle-controller/operator/api/v1alpha1"
+	"github.com/keptn/lifecycle-controller/operator/api/v1alpha1/common"
+	"github.com/keptn/lifecycle-controller/operator/controllers/apply"
+	"github.com/keptn/lifecycle-controller/operator/controllers/selfmonitoring"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+)
+
+// EvaluationPresetReconciler installs the KeptnEvaluationDefinitions of the preset library listed in the
+// keptn.sh/evaluation-presets annotation of a namespace into the namespace, and deletes them once they are removed
+// from the annotation
+type EvaluationPresetReconciler struct {
+	client.Client
+	Scheme   *runtime.Scheme
+	Log      logr.Logger
+	Recorder record.EventRecorder
+}
+
+//+kubebuilder:rbac:groups=core,resources=namespaces,verbs=get;list;watch
+//+kubebuilder:rbac:groups=lifecycle.keptn.sh,resources=keptnevaluationdefinitions,verbs=get;list;watch;create;update;patch;delete
+
+// Reconcile applies the presets requested by the namespace. The source of the presets, which is prometheus by default,
+// can be changed with the keptn.sh/evaluation-presets-source annotation. Existing KeptnEvaluationDefinitions that have
+// not been installed from the library are never overwritten.
+func (r *EvaluationPresetReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	namespace := &corev1.Namespace{}
+	if err := r.Client.Get(ctx, req.NamespacedName, namespace); err != nil {
+		if errors.IsNotFound(err) {
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, fmt.Errorf("could not retrieve namespace %s: %w", req.Name, err)
+	}
+
+	requested := map[string]bool{}
+	if namespace.DeletionTimestamp.IsZero() {
+		for _, name := range parsePresets(namespace.Annotations[common.EvaluationPresetsAnnotation]) {
+			requested[name] = true
+			if err := r.install(ctx, namespace, name); err != nil {
+				return ctrl.Result{}, err
+			}
+		}
+	}
+
+	installed := &klcv1alpha1.KeptnEvaluationDefinitionList{}
+	if err := r.Client.List(ctx, installed, client.InNamespace(namespace.Name), client.HasLabels{common.EvaluationPresetLabel}); err != nil {
+		return ctrl.Result{}, fmt.Errorf("could not list KeptnEvaluationDefinitions: %w", err)
+	}
+	for i := range installed.Items {
+		definition := &installed.Items[i]
+		if requested[definition.Labels[common.EvaluationPresetLabel]] {
+			continue
+		}
+		if err := r.Client.Delete(ctx, definition); err != nil && !errors.IsNotFound(err) {
+			return ctrl.Result{}, fmt.Errorf("could not delete KeptnEvaluationDefinition %s: %w", definition.Name, err)
+		}
+		r.Recorder.Event(namespace, "Normal", "EvaluationPresetRemoved", fmt.Sprintf("Deleted KeptnEvaluationDefinition of preset / Namespace: %s, Name: %s", definition.Namespace, definition.Name))
+	}
+	return ctrl.Result{}, nil
+}
+
+// install applies the preset with the given name to the namespace
+func (r *EvaluationPresetReconciler) install(ctx context.Context, namespace *corev1.Namespace, name string) error {
+	definition, err := Get(name)
+	if err != nil {
+		return err
+	}
+	if definition == nil {
+		r.Recorder.Event(namespace, "Warning", "UnknownEvaluationPreset", fmt.Sprintf("Unknown evaluation preset %s, available presets are %v / Namespace: %s", name, Names(), namespace.Name))
+		return nil
+	}
+
+	existing := &klcv1alpha1.KeptnEvaluationDefinition{}
+	err = r.Client.Get(ctx, types.NamespacedName{Namespace: namespace.Name, Name: definition.Name}, existing)
+	if err != nil && !errors.IsNotFound(err) {
+		return fmt.Errorf("could not retrieve KeptnEvaluationDefinition %s: %w", definition.Name, err)
+	}
+	if err == nil && existing.Labels[common.EvaluationPresetLabel] != name {
+		r.Recorder.Event(namespace, "Warning", "EvaluationPresetConflict", fmt.Sprintf("Skipped evaluation preset %s since a KeptnEvaluationDefinition with the same name exists / Namespace: %s", name, namespace.Name))
+		return nil
+	}
+
+	definition.Namespace = namespace.Name
+	definition.Labels = map[string]string{common.EvaluationPresetLabel: name}
+	if source := namespace.Annotations[common.EvaluationPresetsSourceAnnotation]; source != "" {
+		definition.Spec.Source = source
+	}
+	if err := apply.Apply(ctx, r.Client, definition, r.Recorder, namespace); err != nil {
+		return fmt.Errorf("could not apply KeptnEvaluationDefinition %s: %w", definition.Name, err)
+	}
+	return nil
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *EvaluationPresetReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		Named("evaluationpreset").
+		For(&corev1.Namespace{}).
+		// installed presets that have been deleted or changed by users are restored
+		Watches(&source.Kind{Type: &klcv1alpha1.KeptnEvaluationDefinition{}}, handler.EnqueueRequestsFromMapFunc(r.getNamespaceOfPreset)).
+		Complete(selfmonitoring.Wrap("evaluationpreset", r))
+}
+
+func (r *EvaluationPresetReconciler) getNamespaceOfPreset(definition client.Object) []reconcile.Request {
+	if _, ok := definition.GetLabels()[common.EvaluationPresetLabel]; !ok {
+		return nil
+	}
+	return []reconcile.Request{{NamespacedName: types.NamespacedName{Name: definition.GetNamespace()}}}
+}
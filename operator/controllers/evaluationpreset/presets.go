@@ -0,0 +1,56 @@
+package evaluationpreset
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+
+	klcv1alpha1 "github.com/keptn/lifecycle-controller/operator/api/v1alpha1"
+	"k8s.io/apimachinery/pkg/util/yaml"
+)
+
+// presetFiles is the library of KeptnEvaluationDefinitions, one per file named like the preset
+//
+//go:embed presets/*.yaml
+var presetFiles embed.FS
+
+// Names returns the sorted names of the presets in the library
+func Names() []string {
+	entries, _ := presetFiles.ReadDir("presets")
+	names := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		names = append(names, strings.TrimSuffix(entry.Name(), ".yaml"))
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Get returns the KeptnEvaluationDefinition of the preset with the given name, or nil if there is no such preset
+func Get(name string) (*klcv1alpha1.KeptnEvaluationDefinition, error) {
+	if strings.Contains(name, "/") {
+		return nil, nil
+	}
+	data, err := presetFiles.ReadFile(path.Join("presets", name+".yaml"))
+	if err != nil {
+		return nil, nil
+	}
+	definition := &klcv1alpha1.KeptnEvaluationDefinition{}
+	if err := yaml.NewYAMLOrJSONDecoder(bytes.NewReader(data), len(data)).Decode(definition); err != nil {
+		return nil, fmt.Errorf("could not decode evaluation preset %s: %w", name, err)
+	}
+	return definition, nil
+}
+
+// parsePresets returns the names of the presets in the comma-separated list of the annotation of a namespace
+func parsePresets(annotation string) []string {
+	var names []string
+	for _, name := range strings.Split(annotation, ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			names = append(names, name)
+		}
+	}
+	return names
+}
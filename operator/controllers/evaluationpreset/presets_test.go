@@ -0,0 +1,42 @@
+package evaluationpreset
+
+import (
+	"testing"
+	"text/template"
+
+	testrequire "github.com/stretchr/testify/require"
+)
+
+func TestPresets(t *testing.T) {
+	names := Names()
+	testrequire.Equal(t, []string{"cpu-throttling", "http-5xx-ratio", "pod-restarts"}, names)
+
+	for _, name := range names {
+		definition, err := Get(name)
+		testrequire.Nil(t, err)
+		testrequire.NotNil(t, definition)
+		testrequire.Equal(t, name, definition.Name)
+		testrequire.Equal(t, "prometheus", definition.Spec.Source)
+		testrequire.NotEmpty(t, definition.Spec.Objectives)
+		for _, objective := range definition.Spec.Objectives {
+			testrequire.NotEmpty(t, objective.EvaluationTarget)
+			_, err := template.New("query").Parse(objective.Query)
+			testrequire.Nil(t, err)
+		}
+	}
+}
+
+func TestGetUnknownPreset(t *testing.T) {
+	definition, err := Get("apdex")
+	testrequire.Nil(t, err)
+	testrequire.Nil(t, definition)
+
+	definition, err = Get("../presets/pod-restarts")
+	testrequire.Nil(t, err)
+	testrequire.Nil(t, definition)
+}
+
+func TestParsePresets(t *testing.T) {
+	testrequire.Equal(t, []string{"pod-restarts", "http-5xx-ratio"}, parsePresets(" pod-restarts,,http-5xx-ratio "))
+	testrequire.Nil(t, parsePresets(""))
+}
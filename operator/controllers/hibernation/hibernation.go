@@ -0,0 +1,47 @@
+package hibernation
+
+import (
+	"context"
+	"fmt"
+
+	klcv1alpha1 "github.com/keptn/lifecycle-controller/operator/api/v1alpha1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+)
+
+// Idle returns whether the namespace has no active instances, i.e. no KeptnAppVersions and KeptnWorkloadInstances
+// that have not completed yet. Periodic work of the controllers, e.g. fetching the values of KeptnMetrics,
+// is skipped in idle namespaces until a new instance wakes them up.
+func Idle(ctx context.Context, c client.Reader, namespace string) (bool, error) {
+	appVersions := &klcv1alpha1.KeptnAppVersionList{}
+	if err := c.List(ctx, appVersions, client.InNamespace(namespace)); err != nil {
+		return false, fmt.Errorf("could not retrieve KeptnAppVersions of namespace %s: %w", namespace, err)
+	}
+	for i := range appVersions.Items {
+		if !appVersions.Items[i].IsEndTimeSet() {
+			return false, nil
+		}
+	}
+
+	workloadInstances := &klcv1alpha1.KeptnWorkloadInstanceList{}
+	if err := c.List(ctx, workloadInstances, client.InNamespace(namespace)); err != nil {
+		return false, fmt.Errorf("could not retrieve KeptnWorkloadInstances of namespace %s: %w", namespace, err)
+	}
+	for i := range workloadInstances.Items {
+		if !workloadInstances.Items[i].IsEndTimeSet() {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// WakeupPredicate passes the creation of instances, which wake up the hibernating objects of their namespace
+func WakeupPredicate() predicate.Predicate {
+	return predicate.Funcs{
+		CreateFunc:  func(event.CreateEvent) bool { return true },
+		UpdateFunc:  func(event.UpdateEvent) bool { return false },
+		DeleteFunc:  func(event.DeleteEvent) bool { return false },
+		GenericFunc: func(event.GenericEvent) bool { return false },
+	}
+}
@@ -0,0 +1,35 @@
+package hibernation
+
+import (
+	"context"
+	"testing"
+
+	klcv1alpha1 "github.com/keptn/lifecycle-controller/operator/api/v1alpha1"
+	testrequire "github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestIdle(t *testing.T) {
+	scheme := runtime.NewScheme()
+	testrequire.Nil(t, klcv1alpha1.AddToScheme(scheme))
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+		&klcv1alpha1.KeptnAppVersion{
+			ObjectMeta: metav1.ObjectMeta{Name: "completed", Namespace: "idle"},
+			Status:     klcv1alpha1.KeptnAppVersionStatus{EndTime: metav1.Now()},
+		},
+		&klcv1alpha1.KeptnAppVersion{
+			ObjectMeta: metav1.ObjectMeta{Name: "completed", Namespace: "active-app"},
+			Status:     klcv1alpha1.KeptnAppVersionStatus{EndTime: metav1.Now()},
+		},
+		&klcv1alpha1.KeptnAppVersion{ObjectMeta: metav1.ObjectMeta{Name: "running", Namespace: "active-app"}},
+		&klcv1alpha1.KeptnWorkloadInstance{ObjectMeta: metav1.ObjectMeta{Name: "running", Namespace: "active-workload"}},
+	).Build()
+
+	for namespace, want := range map[string]bool{"idle": true, "empty": true, "active-app": false, "active-workload": false} {
+		idle, err := Idle(context.TODO(), c, namespace)
+		testrequire.Nil(t, err)
+		testrequire.Equal(t, want, idle, namespace)
+	}
+}
@@ -0,0 +1,66 @@
+package history
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// RevisionAnnotation is set by Kubernetes on the ReplicaSets of a Deployment and contains the revision of the
+// Deployment they belong to
+const RevisionAnnotation = "deployment.kubernetes.io/revision"
+
+//+kubebuilder:rbac:groups=apps,resources=replicasets,verbs=get;list;watch
+
+// PreviousReplicaSet returns the ReplicaSet of the revision of a Deployment before the revision of the ReplicaSet with
+// the given UID, or nil if the ReplicaSet does not belong to a Deployment or the Deployment has no earlier revision
+func PreviousReplicaSet(ctx context.Context, c client.Reader, namespace string, uid types.UID) (*appsv1.ReplicaSet, error) {
+	replicaSets := &appsv1.ReplicaSetList{}
+	if err := c.List(ctx, replicaSets, client.InNamespace(namespace)); err != nil {
+		return nil, fmt.Errorf("could not list ReplicaSets: %w", err)
+	}
+	var current *appsv1.ReplicaSet
+	for i := range replicaSets.Items {
+		if replicaSets.Items[i].UID == uid {
+			current = &replicaSets.Items[i]
+		}
+	}
+	if current == nil {
+		return nil, nil
+	}
+	owner := metav1.GetControllerOf(current)
+	if owner == nil || owner.Kind != "Deployment" {
+		return nil, nil
+	}
+	return previous(replicaSets.Items, owner.UID, revision(current)), nil
+}
+
+// previous returns the ReplicaSet of the Deployment with the highest revision below the given one
+func previous(replicaSets []appsv1.ReplicaSet, deployment types.UID, before int64) *appsv1.ReplicaSet {
+	var res *appsv1.ReplicaSet
+	for i := range replicaSets {
+		replicaSet := &replicaSets[i]
+		owner := metav1.GetControllerOf(replicaSet)
+		if owner == nil || owner.UID != deployment {
+			continue
+		}
+		if r := revision(replicaSet); r > 0 && r < before && (res == nil || r > revision(res)) {
+			res = replicaSet
+		}
+	}
+	return res
+}
+
+// revision returns the revision of the Deployment a ReplicaSet belongs to, or 0 if it is unknown
+func revision(replicaSet *appsv1.ReplicaSet) int64 {
+	r, err := strconv.ParseInt(replicaSet.Annotations[RevisionAnnotation], 10, 64)
+	if err != nil {
+		return 0
+	}
+	return r
+}
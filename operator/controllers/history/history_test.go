@@ -0,0 +1,49 @@
+package history
+
+import (
+	"context"
+	"testing"
+
+	testrequire "github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestPreviousReplicaSet(t *testing.T) {
+	isController := true
+	replicaSet := func(name string, owner types.UID, revision string) *appsv1.ReplicaSet {
+		return &appsv1.ReplicaSet{ObjectMeta: metav1.ObjectMeta{
+			Name:            name,
+			Namespace:       "default",
+			UID:             types.UID(name),
+			Annotations:     map[string]string{RevisionAnnotation: revision},
+			OwnerReferences: []metav1.OwnerReference{{Kind: "Deployment", Name: string(owner), UID: owner, Controller: &isController}},
+		}}
+	}
+	c := fake.NewClientBuilder().WithObjects(
+		replicaSet("podinfo-1", "podinfo", "1"),
+		replicaSet("podinfo-2", "podinfo", "2"),
+		replicaSet("podinfo-4", "podinfo", "4"),
+		replicaSet("other-3", "other", "3"),
+		&appsv1.ReplicaSet{ObjectMeta: metav1.ObjectMeta{Name: "standalone", Namespace: "default", UID: "standalone"}},
+	).Build()
+
+	previous, err := PreviousReplicaSet(context.TODO(), c, "default", "podinfo-4")
+	testrequire.Nil(t, err)
+	testrequire.NotNil(t, previous)
+	testrequire.Equal(t, "podinfo-2", previous.Name)
+
+	previous, err = PreviousReplicaSet(context.TODO(), c, "default", "podinfo-1")
+	testrequire.Nil(t, err)
+	testrequire.Nil(t, previous)
+
+	previous, err = PreviousReplicaSet(context.TODO(), c, "default", "standalone")
+	testrequire.Nil(t, err)
+	testrequire.Nil(t, previous)
+
+	previous, err = PreviousReplicaSet(context.TODO(), c, "default", "unknown")
+	testrequire.Nil(t, err)
+	testrequire.Nil(t, previous)
+}
@@ -0,0 +1,210 @@
+package imagewarmer
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sort"
+
+	"github.com/go-logr/logr"
+	klcv1alpha1 "github.com/keptn/lifecycle-controller/operator/api/v1alpha1"
+	"github.com/keptn/lifecycle-controller/operator/controllers/apply"
+	"github.com/keptn/lifecycle-controller/operator/controllers/keptntask"
+	"github.com/keptn/lifecycle-controller/operator/controllers/selfmonitoring"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// DaemonSetName is the name of the DaemonSet pulling the runner images
+const DaemonSetName = "keptn-image-warmer"
+
+const helperMountPath = "/warmer"
+
+// ImageWarmerReconciler pre-pulls the runner images of the KeptnTaskDefinitions in use on all nodes with a DaemonSet,
+// so that the pre- and post-deployment tasks do not add the image pull latency to every deployment
+type ImageWarmerReconciler struct {
+	client.Client
+	Scheme   *runtime.Scheme
+	Log      logr.Logger
+	Recorder record.EventRecorder
+	// Namespace is the namespace of the operator, which the DaemonSet is created in
+	Namespace string
+	// HelperImage is a busybox image, whose statically linked binary runs in the runner images to pull them
+	HelperImage string
+	// PauseImage is the image of the container that keeps the pods of the DaemonSet running
+	PauseImage string
+}
+
+//+kubebuilder:rbac:groups=lifecycle.keptn.sh,resources=keptntaskdefinitions,verbs=get;list;watch
+//+kubebuilder:rbac:groups=apps,resources=daemonsets,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=core,resources=namespaces,verbs=get;list;watch
+
+// Reconcile updates the images pulled by the DaemonSet to the runner images of all KeptnTaskDefinitions.
+// The DaemonSet is deleted when there are no KeptnTaskDefinitions.
+func (r *ImageWarmerReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	definitions := &klcv1alpha1.KeptnTaskDefinitionList{}
+	if err := r.List(ctx, definitions); err != nil {
+		return ctrl.Result{}, fmt.Errorf("could not retrieve KeptnTaskDefinitions: %w", err)
+	}
+	namespaces := map[string]map[string]string{}
+	for _, definition := range definitions.Items {
+		if _, ok := namespaces[definition.Namespace]; ok {
+			continue
+		}
+		namespace := &corev1.Namespace{}
+		if err := r.Get(ctx, client.ObjectKey{Name: definition.Namespace}, namespace); err != nil {
+			return ctrl.Result{}, fmt.Errorf("could not retrieve namespace %s: %w", definition.Namespace, err)
+		}
+		namespaces[definition.Namespace] = namespace.Annotations
+	}
+	images, pullSecrets := runnerImages(definitions.Items, namespaces)
+
+	existing := &appsv1.DaemonSet{}
+	err := r.Get(ctx, client.ObjectKey{Namespace: r.Namespace, Name: DaemonSetName}, existing)
+	if err != nil && !errors.IsNotFound(err) {
+		return ctrl.Result{}, fmt.Errorf("could not retrieve DaemonSet %s: %w", DaemonSetName, err)
+	}
+	found := err == nil
+
+	if len(images) == 0 {
+		if !found {
+			return ctrl.Result{}, nil
+		}
+		r.Log.Info("No runner images in use, deleting image warmer")
+		if err := r.Delete(ctx, existing); err != nil && !errors.IsNotFound(err) {
+			return ctrl.Result{}, fmt.Errorf("could not delete DaemonSet %s: %w", DaemonSetName, err)
+		}
+		return ctrl.Result{}, nil
+	}
+
+	daemonSet := r.generateDaemonSet(images, pullSecrets)
+	if found && reflect.DeepEqual(podImages(existing), podImages(daemonSet)) && reflect.DeepEqual(existing.Spec.Template.Spec.ImagePullSecrets, daemonSet.Spec.Template.Spec.ImagePullSecrets) {
+		return ctrl.Result{}, nil
+	}
+	r.Log.Info("Updating images of image warmer", "images", images)
+	if err := apply.Apply(ctx, r.Client, daemonSet, r.Recorder, daemonSet); err != nil {
+		return ctrl.Result{}, fmt.Errorf("could not apply DaemonSet %s: %w", DaemonSetName, err)
+	}
+	return ctrl.Result{}, nil
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *ImageWarmerReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		Named("imagewarmer").
+		For(&klcv1alpha1.KeptnTaskDefinition{}).
+		Complete(selfmonitoring.Wrap("imagewarmer", r))
+}
+
+// runnerImages returns the sorted images of the containers running the tasks of the given definitions and the names
+// of their pull secrets, given the annotations of the namespaces of the definitions
+func runnerImages(definitions []klcv1alpha1.KeptnTaskDefinition, namespaces map[string]map[string]string) ([]string, []corev1.LocalObjectReference) {
+	seenImages := map[string]bool{}
+	seenSecrets := map[string]bool{}
+	images := []string{}
+	var pullSecrets []corev1.LocalObjectReference
+	for i, definition := range definitions {
+		var runner klcv1alpha1.RunnerSpec
+		if definition.Spec.Container != nil {
+			runner = klcv1alpha1.RunnerSpec{Image: definition.Spec.Container.Image, ImagePullSecrets: definition.Spec.Container.ImagePullSecrets}
+		} else if function, rt := keptntask.Function(&definitions[i]); !reflect.DeepEqual(function, klcv1alpha1.FunctionSpec{}) {
+			runner = rt.RunnerImage(function.Runner, namespaces[definition.Namespace])
+		} else {
+			continue
+		}
+		for _, secret := range runner.ImagePullSecrets {
+			if !seenSecrets[secret.Name] {
+				seenSecrets[secret.Name] = true
+				pullSecrets = append(pullSecrets, secret)
+			}
+		}
+		if runner.Image == "" || seenImages[runner.Image] {
+			continue
+		}
+		seenImages[runner.Image] = true
+		images = append(images, runner.Image)
+	}
+	sort.Strings(images)
+	sort.Slice(pullSecrets, func(i, j int) bool { return pullSecrets[i].Name < pullSecrets[j].Name })
+	return images, pullSecrets
+}
+
+// podImages returns the images of all containers of the pods of the given DaemonSet
+func podImages(daemonSet *appsv1.DaemonSet) []string {
+	images := []string{}
+	for _, container := range append(daemonSet.Spec.Template.Spec.InitContainers, daemonSet.Spec.Template.Spec.Containers...) {
+		images = append(images, container.Image)
+	}
+	return images
+}
+
+func (r *ImageWarmerReconciler) generateDaemonSet(images []string, pullSecrets []corev1.LocalObjectReference) *appsv1.DaemonSet {
+	labels := map[string]string{"app.kubernetes.io/name": DaemonSetName}
+	resources := corev1.ResourceRequirements{
+		Requests: corev1.ResourceList{
+			corev1.ResourceCPU:    resource.MustParse("5m"),
+			corev1.ResourceMemory: resource.MustParse("8Mi"),
+		},
+		Limits: corev1.ResourceList{
+			corev1.ResourceCPU:    resource.MustParse("50m"),
+			corev1.ResourceMemory: resource.MustParse("32Mi"),
+		},
+	}
+	mount := []corev1.VolumeMount{{Name: "helper", MountPath: helperMountPath}}
+
+	// the statically linked busybox binary is copied to a shared volume, so that it can be run in every runner image
+	// to make the kubelet pull the image, regardless of the binaries the image contains
+	initContainers := []corev1.Container{{
+		Name:         "copy-helper",
+		Image:        r.HelperImage,
+		Command:      []string{"cp", "/bin/busybox", helperMountPath + "/busybox"},
+		Resources:    resources,
+		VolumeMounts: mount,
+	}}
+	for i, image := range images {
+		initContainers = append(initContainers, corev1.Container{
+			Name:            fmt.Sprintf("pull-%d", i),
+			Image:           image,
+			ImagePullPolicy: corev1.PullIfNotPresent,
+			Command:         []string{helperMountPath + "/busybox", "true"},
+			Resources:       resources,
+			VolumeMounts:    mount,
+		})
+	}
+
+	return &appsv1.DaemonSet{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      DaemonSetName,
+			Namespace: r.Namespace,
+			Labels:    labels,
+		},
+		Spec: appsv1.DaemonSetSpec{
+			Selector: &metav1.LabelSelector{MatchLabels: labels},
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{Labels: labels},
+				Spec: corev1.PodSpec{
+					// the tasks are scheduled to the nodes without taints, so no taints are tolerated
+					NodeSelector:     map[string]string{corev1.LabelOSStable: "linux"},
+					ImagePullSecrets: pullSecrets,
+					InitContainers:   initContainers,
+					Containers: []corev1.Container{{
+						Name:      "pause",
+						Image:     r.PauseImage,
+						Resources: resources,
+					}},
+					Volumes: []corev1.Volume{{
+						Name:         "helper",
+						VolumeSource: corev1.VolumeSource{EmptyDir: &corev1.EmptyDirVolumeSource{}},
+					}},
+				},
+			},
+		},
+	}
+}
@@ -0,0 +1,62 @@
+package imagewarmer
+
+import (
+	"testing"
+
+	klcv1alpha1 "github.com/keptn/lifecycle-controller/operator/api/v1alpha1"
+	"github.com/keptn/lifecycle-controller/operator/api/v1alpha1/common"
+	testrequire "github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestImageWarmer_GenerateDaemonSet(t *testing.T) {
+	t.Setenv("FUNCTION_RUNNER_IMAGE", "ghcr.io/keptn/functions-runtime:v0.3.0")
+	definitions := []klcv1alpha1.KeptnTaskDefinition{
+		{ObjectMeta: metav1.ObjectMeta{Name: "notify"}, Spec: klcv1alpha1.KeptnTaskDefinitionSpec{
+			Function: klcv1alpha1.FunctionSpec{Inline: klcv1alpha1.Inline{Code: "console.log('deployed')"}},
+		}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "smoke-test"}, Spec: klcv1alpha1.KeptnTaskDefinitionSpec{
+			Function: klcv1alpha1.FunctionSpec{HttpReference: klcv1alpha1.HttpReference{Url: "https://example.com/smoke-test.ts"}},
+		}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "empty"}},
+	}
+	r := &ImageWarmerReconciler{Namespace: "keptn-lifecycle-controller-system", HelperImage: "busybox:1.36", PauseImage: "registry.k8s.io/pause:3.9"}
+
+	images, pullSecrets := runnerImages(definitions, nil)
+	testrequire.Equal(t, []string{"ghcr.io/keptn/functions-runtime:v0.3.0"}, images)
+	testrequire.Empty(t, pullSecrets)
+
+	daemonSet := r.generateDaemonSet(images, pullSecrets)
+	testrequire.Equal(t, "keptn-lifecycle-controller-system", daemonSet.Namespace)
+	testrequire.Equal(t, []string{"busybox:1.36", "ghcr.io/keptn/functions-runtime:v0.3.0", "registry.k8s.io/pause:3.9"}, podImages(daemonSet))
+	testrequire.Equal(t, []string{"/warmer/busybox", "true"}, daemonSet.Spec.Template.Spec.InitContainers[1].Command)
+}
+
+func TestImageWarmer_NoRunnerImages(t *testing.T) {
+	t.Setenv("FUNCTION_RUNNER_IMAGE", "ghcr.io/keptn/functions-runtime:v0.3.0")
+	images, _ := runnerImages([]klcv1alpha1.KeptnTaskDefinition{{ObjectMeta: metav1.ObjectMeta{Name: "empty"}}}, nil)
+	testrequire.Empty(t, images)
+}
+
+func TestImageWarmer_RunnerImageOverrides(t *testing.T) {
+	t.Setenv("FUNCTION_RUNNER_IMAGE", "ghcr.io/keptn/functions-runtime:v0.3.0")
+	function := klcv1alpha1.FunctionSpec{Inline: klcv1alpha1.Inline{Code: "console.log('deployed')"}}
+	definitions := []klcv1alpha1.KeptnTaskDefinition{
+		{ObjectMeta: metav1.ObjectMeta{Name: "notify", Namespace: "mirrored"}, Spec: klcv1alpha1.KeptnTaskDefinitionSpec{Function: function}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "notify", Namespace: "default"}, Spec: klcv1alpha1.KeptnTaskDefinitionSpec{Function: function}},
+	}
+	function.Runner = klcv1alpha1.RunnerSpec{Image: "registry.local/deno:1.0"}
+	definitions = append(definitions, klcv1alpha1.KeptnTaskDefinition{ObjectMeta: metav1.ObjectMeta{Name: "smoke-test", Namespace: "default"}, Spec: klcv1alpha1.KeptnTaskDefinitionSpec{Function: function}})
+	namespaces := map[string]map[string]string{
+		"mirrored": {
+			common.FunctionRunnerImageAnnotation:            "registry.local/keptn/functions-runtime:v0.3.0",
+			common.FunctionRunnerImagePullSecretsAnnotation: "registry-local",
+		},
+	}
+
+	images, pullSecrets := runnerImages(definitions, namespaces)
+
+	testrequire.Equal(t, []string{"ghcr.io/keptn/functions-runtime:v0.3.0", "registry.local/deno:1.0", "registry.local/keptn/functions-runtime:v0.3.0"}, images)
+	testrequire.Equal(t, []corev1.LocalObjectReference{{Name: "registry-local"}}, pullSecrets)
+}
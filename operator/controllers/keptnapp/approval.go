@@ -0,0 +1,20 @@
+package keptnapp
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/keptn/lifecycle-controller/operator/api/v1alpha1/common"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// namespaceRequiresApproval returns whether the keptn.sh/require-approval annotation of the namespace requires the app
+// versions of the namespace to be approved
+func (r *KeptnAppReconciler) namespaceRequiresApproval(ctx context.Context, namespace string) (bool, error) {
+	ns := &corev1.Namespace{}
+	if err := r.Client.Get(ctx, types.NamespacedName{Name: namespace}, ns); err != nil {
+		return false, fmt.Errorf("could not retrieve namespace %s: %w", namespace, err)
+	}
+	return ns.Annotations[common.RequireApprovalAnnotation] == "true", nil
+}
@@ -0,0 +1,67 @@
+package keptnapp
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"sort"
+	"strings"
+
+	klcv1alpha1 "github.com/keptn/lifecycle-controller/operator/api/v1alpha1"
+)
+
+// contentHash returns a hash of the semantic content of the spec of an app.
+// The order of workloads, tasks and evaluations, duplicate entries and surrounding whitespace do not change the hash,
+// since they do not change what is deployed and checked, e.g. if a GitOps tool re-applies a manifest in a different form.
+func contentHash(spec klcv1alpha1.KeptnAppSpec) string {
+	workloads := []string{}
+	for _, workload := range spec.Workloads {
+		ref := strings.TrimSpace(workload.Name) + "@" + strings.TrimSpace(workload.Version)
+		if workload.IsOptional() {
+			ref += "?"
+		}
+		workloads = append(workloads, ref)
+	}
+
+	minSucceededWorkloads := ""
+	if spec.MinSucceededWorkloads != nil {
+		minSucceededWorkloads = spec.MinSucceededWorkloads.String()
+	}
+
+	content, _ := json.Marshal(struct {
+		Version                   string   `json:"version"`
+		Workloads                 []string `json:"workloads"`
+		PreDeploymentTasks        []string `json:"preDeploymentTasks"`
+		PostDeploymentTasks       []string `json:"postDeploymentTasks"`
+		PreDeploymentEvaluations  []string `json:"preDeploymentEvaluations"`
+		PostDeploymentEvaluations []string `json:"postDeploymentEvaluations"`
+		AllowPartialDeployment    bool     `json:"allowPartialDeployment,omitempty"`
+		MinSucceededWorkloads     string   `json:"minSucceededWorkloads,omitempty"`
+	}{
+		Version:                   strings.TrimSpace(spec.Version),
+		Workloads:                 normalizeList(workloads),
+		PreDeploymentTasks:        normalizeList(spec.PreDeploymentTasks),
+		PostDeploymentTasks:       normalizeList(spec.PostDeploymentTasks),
+		PreDeploymentEvaluations:  normalizeList(spec.PreDeploymentEvaluations),
+		PostDeploymentEvaluations: normalizeList(spec.PostDeploymentEvaluations),
+		AllowPartialDeployment:    spec.AllowPartialDeployment,
+		MinSucceededWorkloads:     minSucceededWorkloads,
+	})
+	h := sha256.Sum256(content)
+	return hex.EncodeToString(h[:])[:16]
+}
+
+func normalizeList(items []string) []string {
+	seen := map[string]bool{}
+	res := []string{}
+	for _, item := range items {
+		item = strings.TrimSpace(item)
+		if item == "" || seen[item] {
+			continue
+		}
+		seen[item] = true
+		res = append(res, item)
+	}
+	sort.Strings(res)
+	return res
+}
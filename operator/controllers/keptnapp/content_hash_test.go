@@ -0,0 +1,41 @@
+package keptnapp
+
+import (
+	"testing"
+
+	klcv1alpha1 "github.com/keptn/lifecycle-controller/operator/api/v1alpha1"
+	testrequire "github.com/stretchr/testify/require"
+)
+
+func TestContentHash(t *testing.T) {
+	spec := klcv1alpha1.KeptnAppSpec{
+		Version: "1.3",
+		Workloads: []klcv1alpha1.KeptnWorkloadRef{
+			{Name: "podtato-head-left-arm", Version: "0.1.0"},
+			{Name: "podtato-head-left-leg", Version: "0.1.0"},
+		},
+		PostDeploymentTasks: []string{"notify", "smoke-test"},
+	}
+	reapplied := klcv1alpha1.KeptnAppSpec{
+		Version: "1.3 ",
+		Workloads: []klcv1alpha1.KeptnWorkloadRef{
+			{Name: "podtato-head-left-leg", Version: "0.1.0"},
+			{Name: "podtato-head-left-arm", Version: "0.1.0"},
+		},
+		PreDeploymentTasks:  []string{},
+		PostDeploymentTasks: []string{"smoke-test", "notify", "notify"},
+	}
+	testrequire.Equal(t, contentHash(spec), contentHash(reapplied))
+
+	changed := reapplied
+	changed.Workloads = []klcv1alpha1.KeptnWorkloadRef{
+		{Name: "podtato-head-left-leg", Version: "0.2.0"},
+		{Name: "podtato-head-left-arm", Version: "0.1.0"},
+	}
+	testrequire.NotEqual(t, contentHash(spec), contentHash(changed))
+
+	moved := spec
+	moved.PreDeploymentTasks = []string{"notify"}
+	moved.PostDeploymentTasks = []string{"smoke-test"}
+	testrequire.NotEqual(t, contentHash(spec), contentHash(moved))
+}
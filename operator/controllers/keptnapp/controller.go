@@ -27,17 +27,27 @@ import (
 	"go.opentelemetry.io/otel/propagation"
 	"go.opentelemetry.io/otel/trace"
 	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
 
 	"github.com/go-logr/logr"
 	klcv1alpha1 "github.com/keptn/lifecycle-controller/operator/api/v1alpha1"
+	"github.com/keptn/lifecycle-controller/operator/api/v1alpha1/common"
 	"github.com/keptn/lifecycle-controller/operator/api/v1alpha1/semconv"
+	"github.com/keptn/lifecycle-controller/operator/controllers/apply"
+	"github.com/keptn/lifecycle-controller/operator/controllers/definitions"
+	"github.com/keptn/lifecycle-controller/operator/controllers/environment"
+	"github.com/keptn/lifecycle-controller/operator/controllers/propagate"
+	"github.com/keptn/lifecycle-controller/operator/controllers/reasons"
+	"github.com/keptn/lifecycle-controller/operator/controllers/selfmonitoring"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -48,11 +58,18 @@ type KeptnAppReconciler struct {
 	Recorder record.EventRecorder
 	Log      logr.Logger
 	Tracer   trace.Tracer
+	// EnvironmentLabel is the label of the namespaces containing the environment the KeptnAppVersions are deployed to
+	EnvironmentLabel string
+	// Propagation selects the labels and annotations of the app copied to its KeptnAppVersions
+	Propagation propagate.Policy
 }
 
 //+kubebuilder:rbac:groups=lifecycle.keptn.sh,resources=keptnapps,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups=lifecycle.keptn.sh,resources=keptnapps/status,verbs=get;update;patch
 //+kubebuilder:rbac:groups=lifecycle.keptn.sh,resources=keptnapps/finalizers,verbs=update
+//+kubebuilder:rbac:groups=lifecycle.keptn.sh,resources=keptntaskdefinitions,verbs=get;list;watch
+//+kubebuilder:rbac:groups=lifecycle.keptn.sh,resources=keptnevaluationdefinitions,verbs=get;list;watch
+//+kubebuilder:rbac:groups=core,resources=namespaces,verbs=get;list;watch
 //+kubebuilder:rbac:groups=lifecycle.keptn.sh,resources=keptnappversion,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups=lifecycle.keptn.sh,resources=keptnappversion/status,verbs=get;update;patch
 //+kubebuilder:rbac:groups=lifecycle.keptn.sh,resources=keptnappversion/finalizers,verbs=update
@@ -88,18 +105,45 @@ func (r *KeptnAppReconciler) Reconcile(ctx context.Context, req ctrl.Request) (c
 
 	r.Log.Info("Reconciling Keptn App", "app", app.Name)
 
+	if err := r.updateDefinitionsCondition(ctx, app); err != nil {
+		r.Log.Error(err, "could not resolve definitions of App")
+		span.SetStatus(codes.Error, err.Error())
+		return ctrl.Result{}, err
+	}
+
+	hash := contentHash(app.Spec)
+	if app.Status.ContentHash == hash && app.Status.CurrentVersion != "" {
+		r.Log.Info("Content of Keptn App not changed, not creating a new AppVersion", "app", app.Name)
+		return ctrl.Result{}, nil
+	}
+
+	workloadsFound, err := r.updateWorkloadsCondition(ctx, app)
+	if err != nil {
+		r.Log.Error(err, "could not resolve workloads of App")
+		span.SetStatus(codes.Error, err.Error())
+		return ctrl.Result{}, err
+	}
+
 	appVersion := &klcv1alpha1.KeptnAppVersion{}
 
 	// Try to find the AppVersion
 	err = r.Get(ctx, types.NamespacedName{Namespace: app.Namespace, Name: app.GetAppVersionName()}, appVersion)
+	if err == nil {
+		err = appVersion.VerifyIdentity(app.Name, app.Spec.Version)
+	}
 	// If the app instance does not exist, create it
 	if errors.IsNotFound(err) {
+		if !workloadsFound {
+			// the app is reconciled again as soon as the KeptnWorkloads are created
+			r.Log.Info("Not all workloads of Keptn App exist, not creating a new AppVersion", "app", app.Name)
+			return ctrl.Result{}, nil
+		}
 		appVersion, err := r.createAppVersion(ctx, app)
 		if err != nil {
 			span.SetStatus(codes.Error, err.Error())
 			return reconcile.Result{}, err
 		}
-		err = r.Client.Create(ctx, appVersion)
+		err = apply.Apply(ctx, r.Client, appVersion, r.Recorder, app)
 		if err != nil {
 			r.Log.Error(err, "could not create AppVersion")
 			span.SetStatus(codes.Error, err.Error())
@@ -109,6 +153,7 @@ func (r *KeptnAppReconciler) Reconcile(ctx context.Context, req ctrl.Request) (c
 		r.Recorder.Event(app, "Normal", "AppVersionCreated", fmt.Sprintf("Created KeptnAppVersion / Namespace: %s, Name: %s ", appVersion.Namespace, appVersion.Name))
 
 		app.Status.CurrentVersion = app.Spec.Version
+		app.Status.ContentHash = hash
 		if err := r.Client.Status().Update(ctx, app); err != nil {
 			r.Log.Error(err, "could not update Current Version of App")
 			return ctrl.Result{}, err
@@ -121,6 +166,20 @@ func (r *KeptnAppReconciler) Reconcile(ctx context.Context, req ctrl.Request) (c
 		return ctrl.Result{}, err
 	}
 
+	if app.Status.ContentHash == "" {
+		// apps created before the content hash has been introduced
+		app.Status.ContentHash = hash
+		if err := r.Client.Status().Update(ctx, app); err != nil {
+			r.Log.Error(err, "could not update Content Hash of App")
+			return ctrl.Result{}, err
+		}
+		return ctrl.Result{}, nil
+	}
+	if app.Spec.InFlightChangePolicy == klcv1alpha1.InFlightChangeRerender && app.Spec.Version == appVersion.Spec.Version && !appVersion.IsEndTimeSet() {
+		return r.rerenderAppVersion(ctx, app, appVersion, hash)
+	}
+	r.Recorder.Event(app, "Warning", "AppVersionExists", fmt.Sprintf("Content of KeptnApp changed, but KeptnAppVersion already exists, increase the version to roll out the change / Namespace: %s, Name: %s ", appVersion.Namespace, appVersion.Name))
+
 	return ctrl.Result{}, nil
 }
 
@@ -128,18 +187,74 @@ func (r *KeptnAppReconciler) Reconcile(ctx context.Context, req ctrl.Request) (c
 func (r *KeptnAppReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	return ctrl.NewControllerManagedBy(mgr).
 		For(&klcv1alpha1.KeptnApp{}, builder.WithPredicates(predicate.GenerationChangedPredicate{})).
-		Complete(r)
+		Watches(&source.Kind{Type: &klcv1alpha1.KeptnTaskDefinition{}}, handler.EnqueueRequestsFromMapFunc(r.getAppsForDefinition)).
+		Watches(&source.Kind{Type: &klcv1alpha1.KeptnEvaluationDefinition{}}, handler.EnqueueRequestsFromMapFunc(r.getAppsForDefinition)).
+		Watches(&source.Kind{Type: &klcv1alpha1.KeptnWorkload{}}, handler.EnqueueRequestsFromMapFunc(r.getAppForWorkload)).
+		Complete(selfmonitoring.Wrap("keptnapp", r))
+}
+
+// updateDefinitionsCondition reports the task and evaluation definitions referenced by the app that do not exist
+func (r *KeptnAppReconciler) updateDefinitionsCondition(ctx context.Context, app *klcv1alpha1.KeptnApp) error {
+	tasks := append(append([]string{}, app.Spec.PreDeploymentTasks...), app.Spec.PostDeploymentTasks...)
+	evaluations := append(append([]string{}, app.Spec.PreDeploymentEvaluations...), app.Spec.PostDeploymentEvaluations...)
+	condition, err := definitions.ResolveCondition(ctx, r.Client, app.Namespace, app.Generation, tasks, evaluations)
+	if err != nil {
+		return err
+	}
+	if !definitions.ConditionChanged(app.Status.Conditions, condition) {
+		return nil
+	}
+	if condition.Status == metav1.ConditionFalse {
+		r.Recorder.AnnotatedEventf(app, reasons.DefinitionsNotFound.Annotations(), "Warning", condition.Reason, "%s / Namespace: %s, Name: %s ", condition.Message, app.Namespace, app.Name)
+	}
+	meta.SetStatusCondition(&app.Status.Conditions, condition)
+	return r.Client.Status().Update(ctx, app)
+}
+
+// getAppsForDefinition returns a request for each KeptnApp referencing the given task or evaluation definition
+func (r *KeptnAppReconciler) getAppsForDefinition(definition client.Object) []reconcile.Request {
+	apps := &klcv1alpha1.KeptnAppList{}
+	if err := r.Client.List(context.TODO(), apps, client.InNamespace(definition.GetNamespace())); err != nil {
+		r.Log.Error(err, "could not retrieve KeptnApps")
+		return nil
+	}
+
+	var requests []reconcile.Request
+	for _, app := range apps.Items {
+		if definitions.References(definition.GetName(), app.Spec.PreDeploymentTasks, app.Spec.PostDeploymentTasks, app.Spec.PreDeploymentEvaluations, app.Spec.PostDeploymentEvaluations) {
+			requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{Namespace: app.Namespace, Name: app.Name}})
+		}
+	}
+	return requests
+}
+
+// getAppForWorkload returns a request for the KeptnApp of the given KeptnWorkload
+func (r *KeptnAppReconciler) getAppForWorkload(workload client.Object) []reconcile.Request {
+	w, ok := workload.(*klcv1alpha1.KeptnWorkload)
+	if !ok || w.Spec.AppName == "" {
+		return nil
+	}
+	return []reconcile.Request{{NamespacedName: types.NamespacedName{Namespace: w.Namespace, Name: w.Spec.AppName}}}
 }
 
 func (r *KeptnAppReconciler) createAppVersion(ctx context.Context, app *klcv1alpha1.KeptnApp) (*klcv1alpha1.KeptnAppVersion, error) {
 	ctx, span := r.Tracer.Start(ctx, "create_app_version", trace.WithSpanKind(trace.SpanKindProducer))
 	defer span.End()
 
-	ctxAppTrace, spanAppTrace := r.Tracer.Start(ctx, "appversion_deployment", trace.WithNewRoot(), trace.WithSpanKind(trace.SpanKindServer))
+	env, err := environment.Resolve(ctx, r.Client, app.Namespace, r.EnvironmentLabel)
+	if err != nil {
+		r.Log.Error(err, "could not resolve environment of App")
+	}
+	// the baggage is propagated to the tasks and evaluations of the app version
+	ctx = semconv.ContextWithDeploymentBaggage(ctx, common.AppName.String(app.Name), common.AppVersion.String(app.Spec.Version), common.Environment.String(env))
+
+	ctxAppTrace, spanAppTrace := r.Tracer.Start(ctx, semconv.AppVersionSpanName, trace.WithNewRoot(), trace.WithSpanKind(trace.SpanKindServer))
 	defer spanAppTrace.End()
 
 	semconv.AddAttributeFromApp(span, *app)
 	semconv.AddAttributeFromApp(spanAppTrace, *app)
+	span.SetAttributes(common.Environment.String(env))
+	spanAppTrace.SetAttributes(common.Environment.String(env))
 
 	// create TraceContext
 	// follow up with a Keptn propagator that JSON-encoded the OTel map into our own key
@@ -147,6 +262,7 @@ func (r *KeptnAppReconciler) createAppVersion(ctx context.Context, app *klcv1alp
 	otel.GetTextMapPropagator().Inject(ctx, traceContextCarrier)
 	appTraceContextCarrier := propagation.MapCarrier{}
 	otel.GetTextMapPropagator().Inject(ctxAppTrace, appTraceContextCarrier)
+	traceContextCarrier[common.InstanceIdentityAnnotation] = common.Identity(app.Name, app.Spec.Version)
 
 	previousVersion := ""
 	if app.Spec.Version != app.Status.CurrentVersion {
@@ -158,6 +274,7 @@ func (r *KeptnAppReconciler) createAppVersion(ctx context.Context, app *klcv1alp
 			Annotations: traceContextCarrier,
 			Name:        app.GetAppVersionName(),
 			Namespace:   app.Namespace,
+			Labels:      environment.Labels(map[string]string{common.InstanceIdLabel: common.IdentityHash(app.Name, app.Spec.Version)}, env),
 		},
 		Spec: klcv1alpha1.KeptnAppVersionSpec{
 			KeptnAppSpec:    app.Spec,
@@ -166,7 +283,24 @@ func (r *KeptnAppReconciler) createAppVersion(ctx context.Context, app *klcv1alp
 			TraceId:         appTraceContextCarrier,
 		},
 	}
-	err := controllerutil.SetControllerReference(app, appVersion, r.Scheme)
+	r.Propagation.Copy(app.ObjectMeta, &appVersion.ObjectMeta)
+	// the annotation simulates failures of the versions of the app without changing its spec
+	if annotation, ok := app.Annotations[common.SimulateFailureAnnotation]; ok && appVersion.Spec.SimulateFailure == "" {
+		if failure := klcv1alpha1.SimulatedFailure(annotation); failure.IsValid() {
+			appVersion.Spec.SimulateFailure = failure
+		} else {
+			r.Log.Error(fmt.Errorf("unknown phase %s", annotation), "invalid simulated failure of KeptnApp "+app.Name)
+		}
+	}
+	// the annotation of the namespace makes the approval a promotion gate for all apps of the namespace
+	if !appVersion.Spec.RequireApproval {
+		required, err := r.namespaceRequiresApproval(ctx, app.Namespace)
+		if err != nil {
+			return nil, err
+		}
+		appVersion.Spec.RequireApproval = required
+	}
+	err = controllerutil.SetControllerReference(app, appVersion, r.Scheme)
 	if err != nil {
 		r.Log.Error(err, "could not set controller reference for AppVersion: "+appVersion.Name)
 	}
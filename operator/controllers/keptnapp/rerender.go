@@ -0,0 +1,63 @@
+package keptnapp
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+
+	klcv1alpha1 "github.com/keptn/lifecycle-controller/operator/api/v1alpha1"
+	"github.com/keptn/lifecycle-controller/operator/api/v1alpha1/common"
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+// rerenderAppVersion applies the changed workloads, tasks and evaluations of the app to the in-flight app version.
+// The phases of the app version that have already completed keep their workloads, tasks and evaluations.
+func (r *KeptnAppReconciler) rerenderAppVersion(ctx context.Context, app *klcv1alpha1.KeptnApp, appVersion *klcv1alpha1.KeptnAppVersion, hash string) (ctrl.Result, error) {
+	skipped := rerender(&appVersion.Spec.KeptnAppSpec, app.Spec, appVersion.Status)
+	if err := r.Client.Update(ctx, appVersion); err != nil {
+		r.Log.Error(err, "could not rerender AppVersion")
+		return ctrl.Result{}, err
+	}
+	r.Recorder.Event(app, "Normal", "AppVersionRerendered", fmt.Sprintf("Applied the changes of KeptnApp to the phases of KeptnAppVersion that have not completed yet / Namespace: %s, Name: %s ", appVersion.Namespace, appVersion.Name))
+	if len(skipped) > 0 {
+		r.Recorder.Event(app, "Warning", "AppVersionPhasesCompleted", fmt.Sprintf("Could not apply the changes of %s, since the phases of KeptnAppVersion have already completed / Namespace: %s, Name: %s ", strings.Join(skipped, ", "), appVersion.Namespace, appVersion.Name))
+	}
+
+	app.Status.ContentHash = hash
+	if err := r.Client.Status().Update(ctx, app); err != nil {
+		r.Log.Error(err, "could not update Content Hash of App")
+		return ctrl.Result{}, err
+	}
+	return ctrl.Result{}, nil
+}
+
+// rerender applies the desired workloads, tasks and evaluations to the spec of an app version, unless the phase they
+// belong to has already completed. It returns the names of the fields that could not be applied.
+func rerender(spec *klcv1alpha1.KeptnAppSpec, desired klcv1alpha1.KeptnAppSpec, status klcv1alpha1.KeptnAppVersionStatus) []string {
+	skipped := []string{}
+	apply := func(field string, state common.KeptnState, current *[]string, desired []string) {
+		if reflect.DeepEqual(*current, desired) {
+			return
+		}
+		if state.IsCompleted() {
+			skipped = append(skipped, field)
+			return
+		}
+		*current = desired
+	}
+	apply("preDeploymentTasks", status.PreDeploymentStatus, &spec.PreDeploymentTasks, desired.PreDeploymentTasks)
+	apply("preDeploymentEvaluations", status.PreDeploymentEvaluationStatus, &spec.PreDeploymentEvaluations, desired.PreDeploymentEvaluations)
+	apply("postDeploymentTasks", status.PostDeploymentStatus, &spec.PostDeploymentTasks, desired.PostDeploymentTasks)
+	apply("postDeploymentEvaluations", status.PostDeploymentEvaluationStatus, &spec.PostDeploymentEvaluations, desired.PostDeploymentEvaluations)
+
+	if !reflect.DeepEqual(spec.Workloads, desired.Workloads) {
+		if status.WorkloadOverallStatus.IsCompleted() {
+			skipped = append(skipped, "workloads")
+		} else {
+			spec.Workloads = desired.Workloads
+		}
+	}
+	spec.InFlightChangePolicy = desired.InFlightChangePolicy
+	return skipped
+}
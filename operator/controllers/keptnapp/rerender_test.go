@@ -0,0 +1,34 @@
+package keptnapp
+
+import (
+	"testing"
+
+	klcv1alpha1 "github.com/keptn/lifecycle-controller/operator/api/v1alpha1"
+	"github.com/keptn/lifecycle-controller/operator/api/v1alpha1/common"
+	testrequire "github.com/stretchr/testify/require"
+)
+
+func TestRerender(t *testing.T) {
+	spec := klcv1alpha1.KeptnAppSpec{
+		Version:             "1.0.0",
+		PreDeploymentTasks:  []string{"check-entry"},
+		PostDeploymentTasks: []string{"notify"},
+	}
+	desired := klcv1alpha1.KeptnAppSpec{
+		Version:              "1.0.0",
+		PreDeploymentTasks:   []string{"check-entry", "check-quota"},
+		PostDeploymentTasks:  []string{"notify", "load-test"},
+		InFlightChangePolicy: klcv1alpha1.InFlightChangeRerender,
+	}
+	status := klcv1alpha1.KeptnAppVersionStatus{
+		PreDeploymentStatus:  common.StateSucceeded,
+		PostDeploymentStatus: common.StatePending,
+	}
+
+	skipped := rerender(&spec, desired, status)
+
+	testrequire.Equal(t, []string{"preDeploymentTasks"}, skipped)
+	testrequire.Equal(t, []string{"check-entry"}, spec.PreDeploymentTasks)
+	testrequire.Equal(t, []string{"notify", "load-test"}, spec.PostDeploymentTasks)
+	testrequire.Equal(t, klcv1alpha1.InFlightChangeRerender, spec.InFlightChangePolicy)
+}
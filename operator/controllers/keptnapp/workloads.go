@@ -0,0 +1,133 @@
+package keptnapp
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	klcv1alpha1 "github.com/keptn/lifecycle-controller/operator/api/v1alpha1"
+	"github.com/keptn/lifecycle-controller/operator/api/v1alpha1/common"
+	"github.com/keptn/lifecycle-controller/operator/controllers/definitions"
+	"github.com/keptn/lifecycle-controller/operator/controllers/reasons"
+	appsv1 "k8s.io/api/apps/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+//+kubebuilder:rbac:groups=lifecycle.keptn.sh,resources=keptnworkloads,verbs=get;list;watch
+//+kubebuilder:rbac:groups=apps,resources=deployments;statefulsets;daemonsets,verbs=get;list;watch
+
+// updateWorkloadsCondition reports the workloads of the app which have neither a KeptnWorkload nor an annotated
+// Deployment, StatefulSet or DaemonSet, and returns whether all workloads have been found
+func (r *KeptnAppReconciler) updateWorkloadsCondition(ctx context.Context, app *klcv1alpha1.KeptnApp) (bool, error) {
+	missing, err := r.getMissingWorkloads(ctx, app)
+	if err != nil {
+		return false, err
+	}
+
+	condition := metav1.Condition{
+		Type:               klcv1alpha1.WorkloadsFound,
+		Status:             metav1.ConditionTrue,
+		ObservedGeneration: app.Generation,
+		Reason:             reasons.WorkloadsFound.Code,
+		Message:            reasons.WorkloadsFound.Message(),
+	}
+	if len(missing) > 0 {
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = reasons.WorkloadsNotFound.Code
+		condition.Message = reasons.WorkloadsNotFound.Message(strings.Join(missing, ", "))
+	}
+	if !definitions.ConditionChanged(app.Status.Conditions, condition) {
+		return len(missing) == 0, nil
+	}
+	if condition.Status == metav1.ConditionFalse {
+		r.Recorder.AnnotatedEventf(app, reasons.WorkloadsNotFound.Annotations(), "Warning", condition.Reason, "%s / Namespace: %s, Name: %s ", condition.Message, app.Namespace, app.Name)
+	}
+	meta.SetStatusCondition(&app.Status.Conditions, condition)
+	return len(missing) == 0, r.Client.Status().Update(ctx, app)
+}
+
+func (r *KeptnAppReconciler) getMissingWorkloads(ctx context.Context, app *klcv1alpha1.KeptnApp) ([]string, error) {
+	missing := []string{}
+	var annotated map[string]bool
+	for _, w := range app.Spec.Workloads {
+		workload := &klcv1alpha1.KeptnWorkload{}
+		err := r.Get(ctx, types.NamespacedName{Namespace: app.Namespace, Name: common.CreateResourceName(common.MaxK8sObjectLength, app.Name, w.Name)}, workload)
+		if err == nil {
+			continue
+		}
+		if !errors.IsNotFound(err) {
+			return nil, fmt.Errorf("could not retrieve KeptnWorkload %s: %w", w.Name, err)
+		}
+		// the KeptnWorkload is created by the webhook as soon as the first pod of the workload is created
+		if annotated == nil {
+			if annotated, err = r.getAnnotatedWorkloads(ctx, app); err != nil {
+				return nil, err
+			}
+		}
+		if !annotated[w.Name] {
+			missing = append(missing, w.Name)
+		}
+	}
+	return missing, nil
+}
+
+// getAnnotatedWorkloads returns the names of the workloads of the app whose Deployment, StatefulSet or DaemonSet
+// has a pod template with the Keptn annotations or labels
+func (r *KeptnAppReconciler) getAnnotatedWorkloads(ctx context.Context, app *klcv1alpha1.KeptnApp) (map[string]bool, error) {
+	var templates []metav1.ObjectMeta
+
+	deployments := &appsv1.DeploymentList{}
+	if err := r.List(ctx, deployments, client.InNamespace(app.Namespace)); err != nil {
+		return nil, fmt.Errorf("could not retrieve Deployments: %w", err)
+	}
+	for _, d := range deployments.Items {
+		templates = append(templates, d.Spec.Template.ObjectMeta)
+	}
+	statefulSets := &appsv1.StatefulSetList{}
+	if err := r.List(ctx, statefulSets, client.InNamespace(app.Namespace)); err != nil {
+		return nil, fmt.Errorf("could not retrieve StatefulSets: %w", err)
+	}
+	for _, s := range statefulSets.Items {
+		templates = append(templates, s.Spec.Template.ObjectMeta)
+	}
+	daemonSets := &appsv1.DaemonSetList{}
+	if err := r.List(ctx, daemonSets, client.InNamespace(app.Namespace)); err != nil {
+		return nil, fmt.Errorf("could not retrieve DaemonSets: %w", err)
+	}
+	for _, d := range daemonSets.Items {
+		templates = append(templates, d.Spec.Template.ObjectMeta)
+	}
+
+	workloads := map[string]bool{}
+	for _, template := range templates {
+		workload := getLabelOrAnnotation(template, common.WorkloadAnnotation, common.K8sRecommendedWorkloadAnnotations)
+		if workload == "" {
+			continue
+		}
+		// the webhook uses the workload name as app name if no app is set
+		appName := getLabelOrAnnotation(template, common.AppAnnotation, common.K8sRecommendedAppAnnotations)
+		if appName == "" {
+			appName = workload
+		}
+		if appName == app.Name {
+			workloads[workload] = true
+		}
+	}
+	return workloads, nil
+}
+
+func getLabelOrAnnotation(template metav1.ObjectMeta, primary string, secondary string) string {
+	for _, key := range []string{primary, secondary} {
+		if template.Annotations[key] != "" {
+			return template.Annotations[key]
+		}
+		if template.Labels[key] != "" {
+			return template.Labels[key]
+		}
+	}
+	return ""
+}
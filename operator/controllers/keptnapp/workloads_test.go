@@ -0,0 +1,51 @@
+package keptnapp
+
+import (
+	"context"
+	"testing"
+
+	klcv1alpha1 "github.com/keptn/lifecycle-controller/operator/api/v1alpha1"
+	"github.com/keptn/lifecycle-controller/operator/api/v1alpha1/common"
+	testrequire "github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestGetMissingWorkloads(t *testing.T) {
+	scheme := runtime.NewScheme()
+	testrequire.Nil(t, clientgoscheme.AddToScheme(scheme))
+	testrequire.Nil(t, klcv1alpha1.AddToScheme(scheme))
+	r := &KeptnAppReconciler{Client: fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+		&klcv1alpha1.KeptnWorkload{ObjectMeta: metav1.ObjectMeta{Name: "myapp-frontend", Namespace: "default"}},
+		&appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{Name: "backend", Namespace: "default"},
+			Spec: appsv1.DeploymentSpec{
+				Template: corev1.PodTemplateSpec{
+					ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{
+						common.AppAnnotation:      "myapp",
+						common.WorkloadAnnotation: "backend",
+					}},
+				},
+			},
+		},
+	).Build()}
+	app := &klcv1alpha1.KeptnApp{
+		ObjectMeta: metav1.ObjectMeta{Name: "myapp", Namespace: "default"},
+		Spec: klcv1alpha1.KeptnAppSpec{
+			Workloads: []klcv1alpha1.KeptnWorkloadRef{
+				{Name: "frontend", Version: "1.0.0"},
+				{Name: "backend", Version: "1.0.0"},
+				{Name: "fronted", Version: "1.0.0"},
+			},
+		},
+	}
+
+	missing, err := r.getMissingWorkloads(context.TODO(), app)
+
+	testrequire.Nil(t, err)
+	testrequire.Equal(t, []string{"fronted"}, missing)
+}
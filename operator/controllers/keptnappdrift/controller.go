@@ -0,0 +1,293 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package keptnappdrift
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/go-logr/logr"
+	klcv1alpha1 "github.com/keptn/lifecycle-controller/operator/api/v1alpha1"
+	"github.com/keptn/lifecycle-controller/operator/api/v1alpha1/common"
+	"github.com/keptn/lifecycle-controller/operator/controllers/definitions"
+	"github.com/keptn/lifecycle-controller/operator/controllers/reasons"
+	"github.com/keptn/lifecycle-controller/operator/controllers/selfmonitoring"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+)
+
+// KeptnAppDriftReconciler detects drift between the latest succeeded KeptnAppVersion of a KeptnApp and the pods
+// actually running for its workloads
+type KeptnAppDriftReconciler struct {
+	client.Client
+	Scheme   *runtime.Scheme
+	Log      logr.Logger
+	Recorder record.EventRecorder
+	// Interval is the interval in which the running pods are compared to the deployed versions
+	Interval time.Duration
+}
+
+//+kubebuilder:rbac:groups=lifecycle.keptn.sh,resources=keptnapps,verbs=get;list;watch
+//+kubebuilder:rbac:groups=lifecycle.keptn.sh,resources=keptnapps/status,verbs=get;update;patch
+//+kubebuilder:rbac:groups=lifecycle.keptn.sh,resources=keptnappversions;keptnworkloadinstances,verbs=get;list;watch
+//+kubebuilder:rbac:groups=core,resources=pods,verbs=get;list;watch
+
+// Reconcile compares the running pods of the workloads of a KeptnApp with its latest succeeded KeptnAppVersion.
+// A workload has drifted if pods of another version are running, e.g. after its image has been changed directly, or if
+// its pods do not belong to the ReplicaSet, StatefulSet or DaemonSet deployed by the KeptnWorkloadInstance, e.g. after
+// a manual rollout with the same version. Drift is reported in the VersionsInSync condition and the drift of the
+// status of the app, and as an event whenever it is detected or resolved.
+func (r *KeptnAppDriftReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	app := &klcv1alpha1.KeptnApp{}
+	if err := r.Client.Get(ctx, req.NamespacedName, app); err != nil {
+		if errors.IsNotFound(err) {
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, fmt.Errorf("could not retrieve KeptnApp: %w", err)
+	}
+
+	appVersion, err := r.getLatestSucceededAppVersion(ctx, app)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if appVersion == nil {
+		// nothing has been deployed yet
+		return ctrl.Result{RequeueAfter: r.Interval}, nil
+	}
+
+	drift, err := r.detectDrift(ctx, app, appVersion)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	condition := metav1.Condition{
+		Type:               klcv1alpha1.VersionsInSync,
+		Status:             metav1.ConditionTrue,
+		ObservedGeneration: app.Generation,
+		Reason:             reasons.InSync.Code,
+		Message:            reasons.InSync.Message(appVersion.Name),
+	}
+	if len(drift) > 0 {
+		workloads := make([]string, 0, len(drift))
+		for _, d := range drift {
+			workloads = append(workloads, fmt.Sprintf("%s (%s)", d.Workload, d.Reason))
+		}
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = reasons.DriftDetected.Code
+		condition.Message = reasons.DriftDetected.Message(appVersion.Name, strings.Join(workloads, ", "))
+	}
+
+	if !definitions.ConditionChanged(app.Status.Conditions, condition) && reflect.DeepEqual(app.Status.Drift, drift) {
+		return ctrl.Result{RequeueAfter: r.Interval}, nil
+	}
+	previous := meta.FindStatusCondition(app.Status.Conditions, klcv1alpha1.VersionsInSync)
+	if condition.Status == metav1.ConditionFalse && (previous == nil || previous.Status != metav1.ConditionFalse) {
+		r.recordEvent(app, reasons.VersionDriftDetected, condition.Message)
+	} else if condition.Status == metav1.ConditionTrue && previous != nil && previous.Status == metav1.ConditionFalse {
+		r.recordEvent(app, reasons.VersionDriftResolved, condition.Message)
+	}
+	meta.SetStatusCondition(&app.Status.Conditions, condition)
+	app.Status.Drift = drift
+	if err := r.Client.Status().Update(ctx, app); err != nil {
+		return ctrl.Result{}, fmt.Errorf("could not update status of KeptnApp: %w", err)
+	}
+	return ctrl.Result{RequeueAfter: r.Interval}, nil
+}
+
+// recordEvent records an event of the given reason, whose message is the message of the VersionsInSync condition
+func (r *KeptnAppDriftReconciler) recordEvent(app *klcv1alpha1.KeptnApp, reason reasons.Reason, message string) {
+	r.Recorder.AnnotatedEventf(app, reason.Annotations(), string(reason.Severity), reason.Code, "%s / Namespace: %s, Name: %s ", message, app.Namespace, app.Name)
+}
+
+// getLatestSucceededAppVersion returns the KeptnAppVersion of the app which succeeded last, or nil if none succeeded
+func (r *KeptnAppDriftReconciler) getLatestSucceededAppVersion(ctx context.Context, app *klcv1alpha1.KeptnApp) (*klcv1alpha1.KeptnAppVersion, error) {
+	appVersions := &klcv1alpha1.KeptnAppVersionList{}
+	if err := r.Client.List(ctx, appVersions, client.InNamespace(app.Namespace)); err != nil {
+		return nil, fmt.Errorf("could not retrieve KeptnAppVersions: %w", err)
+	}
+	var latest *klcv1alpha1.KeptnAppVersion
+	for i := range appVersions.Items {
+		candidate := &appVersions.Items[i]
+		if candidate.Spec.AppName != app.Name || candidate.Status.Status != common.StateSucceeded {
+			continue
+		}
+		if latest == nil || candidate.Status.EndTime.After(latest.Status.EndTime.Time) {
+			latest = candidate
+		}
+	}
+	return latest, nil
+}
+
+// detectDrift returns the workloads of the app version whose running pods have not been deployed by it
+func (r *KeptnAppDriftReconciler) detectDrift(ctx context.Context, app *klcv1alpha1.KeptnApp, appVersion *klcv1alpha1.KeptnAppVersion) ([]klcv1alpha1.WorkloadDrift, error) {
+	pods := &corev1.PodList{}
+	if err := r.Client.List(ctx, pods, client.InNamespace(app.Namespace)); err != nil {
+		return nil, fmt.Errorf("could not retrieve pods: %w", err)
+	}
+
+	var drift []klcv1alpha1.WorkloadDrift
+	for _, w := range appVersion.Spec.Workloads {
+		workloadName := common.CreateResourceName(common.MaxK8sObjectLength, app.Name, w.Name)
+		instance := &klcv1alpha1.KeptnWorkloadInstance{}
+		err := r.Client.Get(ctx, types.NamespacedName{Namespace: app.Namespace, Name: common.CreateInstanceName(workloadName, w.Version)}, instance)
+		if errors.IsNotFound(err) {
+			continue
+		} else if err != nil {
+			return nil, fmt.Errorf("could not retrieve KeptnWorkloadInstance of workload %s: %w", w.Name, err)
+		}
+		if err := instance.VerifyIdentity(workloadName, w.Version); err != nil {
+			return nil, fmt.Errorf("could not retrieve KeptnWorkloadInstance of workload %s: %w", w.Name, err)
+		}
+		if d := compare(w, instance.Spec.ResourceReference, workloadPods(pods.Items, app.Name, w.Name)); d != nil {
+			drift = append(drift, *d)
+		}
+	}
+	return drift, nil
+}
+
+// compare returns the drift of the running pods of a workload from the deployed version and resource, or nil if
+// there is none
+func compare(workload klcv1alpha1.KeptnWorkloadRef, deployed klcv1alpha1.ResourceReference, pods []corev1.Pod) *klcv1alpha1.WorkloadDrift {
+	drift := &klcv1alpha1.WorkloadDrift{Workload: workload.Name, DeployedVersion: workload.Version}
+	versions := map[string]bool{}
+	images := map[string]bool{}
+	replaced := false
+	for _, pod := range pods {
+		versions[podVersion(pod)] = true
+		for _, container := range pod.Spec.Containers {
+			images[container.Image] = true
+		}
+		if !isOwnedBy(pod, deployed) {
+			replaced = true
+		}
+	}
+	drift.RunningVersions = sortedKeys(versions)
+	drift.RunningImages = sortedKeys(images)
+
+	switch {
+	case len(pods) == 0:
+		drift.Reason = reasons.NotRunning.Code
+	case len(versions) > 1 || !versions[workload.Version]:
+		drift.Reason = reasons.VersionChanged.Code
+	case replaced:
+		drift.Reason = reasons.ResourceReplaced.Code
+	default:
+		return nil
+	}
+	return drift
+}
+
+// workloadPods returns the running pods of the workload of the app
+func workloadPods(pods []corev1.Pod, appName string, workloadName string) []corev1.Pod {
+	var result []corev1.Pod
+	for _, pod := range pods {
+		if pod.DeletionTimestamp != nil || pod.Status.Phase != corev1.PodRunning {
+			continue
+		}
+		workload := getLabelOrAnnotation(pod.ObjectMeta, common.WorkloadAnnotation, common.K8sRecommendedWorkloadAnnotations)
+		if workload == "" || workload != workloadName {
+			continue
+		}
+		// the webhook uses the workload name as app name if no app is set
+		podApp := getLabelOrAnnotation(pod.ObjectMeta, common.AppAnnotation, common.K8sRecommendedAppAnnotations)
+		if podApp == "" {
+			podApp = workload
+		}
+		if common.CreateResourceName(common.MaxK8sObjectLength, podApp) == appName {
+			result = append(result, pod)
+		}
+	}
+	return result
+}
+
+// podVersion returns the version the webhook annotated on the pod, which is normalized, or the version label of the pod
+func podVersion(pod corev1.Pod) string {
+	return getLabelOrAnnotation(pod.ObjectMeta, common.VersionAnnotation, common.K8sRecommendedVersionAnnotations)
+}
+
+func isOwnedBy(pod corev1.Pod, resource klcv1alpha1.ResourceReference) bool {
+	if pod.UID == resource.UID {
+		return true
+	}
+	for _, owner := range pod.OwnerReferences {
+		if owner.UID == resource.UID {
+			return true
+		}
+	}
+	return false
+}
+
+func getLabelOrAnnotation(meta metav1.ObjectMeta, primary string, secondary string) string {
+	for _, key := range []string{primary, secondary} {
+		if meta.Annotations[key] != "" {
+			return meta.Annotations[key]
+		}
+		if meta.Labels[key] != "" {
+			return meta.Labels[key]
+		}
+	}
+	return ""
+}
+
+func sortedKeys(values map[string]bool) []string {
+	if len(values) == 0 {
+		return nil
+	}
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *KeptnAppDriftReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		Named("keptnappdrift").
+		For(&klcv1alpha1.KeptnApp{}).
+		// pods replaced by a manual rollout are detected right away instead of after the interval
+		Watches(&source.Kind{Type: &corev1.Pod{}}, handler.EnqueueRequestsFromMapFunc(r.getAppForPod)).
+		Complete(selfmonitoring.Wrap("keptnappdrift", r))
+}
+
+// getAppForPod returns a request for the KeptnApp of the given pod, if it belongs to a workload
+func (r *KeptnAppDriftReconciler) getAppForPod(pod client.Object) []reconcile.Request {
+	workload := getLabelOrAnnotation(metav1.ObjectMeta{Labels: pod.GetLabels(), Annotations: pod.GetAnnotations()}, common.WorkloadAnnotation, common.K8sRecommendedWorkloadAnnotations)
+	if workload == "" {
+		return nil
+	}
+	appName := getLabelOrAnnotation(metav1.ObjectMeta{Labels: pod.GetLabels(), Annotations: pod.GetAnnotations()}, common.AppAnnotation, common.K8sRecommendedAppAnnotations)
+	if appName == "" {
+		appName = workload
+	}
+	return []reconcile.Request{{NamespacedName: types.NamespacedName{Namespace: pod.GetNamespace(), Name: common.CreateResourceName(common.MaxK8sObjectLength, appName)}}}
+}
@@ -0,0 +1,130 @@
+package keptnappdrift
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	klcv1alpha1 "github.com/keptn/lifecycle-controller/operator/api/v1alpha1"
+	"github.com/keptn/lifecycle-controller/operator/api/v1alpha1/common"
+	"github.com/keptn/lifecycle-controller/operator/controllers/reasons"
+	testrequire "github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func newPod(name string, workload string, version string, image string, owner types.UID) *corev1.Pod {
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: "default",
+			Annotations: map[string]string{
+				common.AppAnnotation:      "myapp",
+				common.WorkloadAnnotation: workload,
+				common.VersionAnnotation:  version,
+			},
+			OwnerReferences: []metav1.OwnerReference{{Kind: "ReplicaSet", Name: workload, UID: owner}},
+		},
+		Spec:   corev1.PodSpec{Containers: []corev1.Container{{Name: workload, Image: image}}},
+		Status: corev1.PodStatus{Phase: corev1.PodRunning},
+	}
+}
+
+func newWorkloadInstance(workload string, version string, owner types.UID) *klcv1alpha1.KeptnWorkloadInstance {
+	return &klcv1alpha1.KeptnWorkloadInstance{
+		ObjectMeta: metav1.ObjectMeta{Name: "myapp-" + workload + "-" + version, Namespace: "default"},
+		Spec: klcv1alpha1.KeptnWorkloadInstanceSpec{
+			KeptnWorkloadSpec: klcv1alpha1.KeptnWorkloadSpec{
+				AppName:           "myapp",
+				Version:           version,
+				ResourceReference: klcv1alpha1.ResourceReference{UID: owner, Kind: "ReplicaSet"},
+			},
+			WorkloadName: "myapp-" + workload,
+		},
+	}
+}
+
+func TestDetectDrift(t *testing.T) {
+	scheme := runtime.NewScheme()
+	testrequire.Nil(t, clientgoscheme.AddToScheme(scheme))
+	testrequire.Nil(t, klcv1alpha1.AddToScheme(scheme))
+	r := &KeptnAppDriftReconciler{Client: fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+		newWorkloadInstance("frontend", "1.0.0", "frontend-rs"),
+		newWorkloadInstance("backend", "1.0.0", "backend-rs"),
+		newWorkloadInstance("cache", "1.0.0", "cache-rs"),
+		newWorkloadInstance("db", "1.0.0", "db-rs"),
+		newPod("frontend-1", "frontend", "1.0.0", "frontend:1.0.0", "frontend-rs"),
+		// the image of the backend has been changed with kubectl set image
+		newPod("backend-1", "backend", "1.0.0", "backend:1.0.0", "backend-rs"),
+		newPod("backend-2", "backend", "1.1.0", "backend:1.1.0", "backend-rs-2"),
+		// the cache has been rolled out again outside of Keptn
+		newPod("cache-1", "cache", "1.0.0", "cache:1.0.0", "cache-rs-2"),
+	).Build()}
+	app := &klcv1alpha1.KeptnApp{ObjectMeta: metav1.ObjectMeta{Name: "myapp", Namespace: "default"}}
+	appVersion := &klcv1alpha1.KeptnAppVersion{
+		Spec: klcv1alpha1.KeptnAppVersionSpec{
+			KeptnAppSpec: klcv1alpha1.KeptnAppSpec{
+				Workloads: []klcv1alpha1.KeptnWorkloadRef{
+					{Name: "frontend", Version: "1.0.0"},
+					{Name: "backend", Version: "1.0.0"},
+					{Name: "cache", Version: "1.0.0"},
+					{Name: "db", Version: "1.0.0"},
+				},
+			},
+		},
+	}
+
+	drift, err := r.detectDrift(context.TODO(), app, appVersion)
+
+	testrequire.Nil(t, err)
+	testrequire.Equal(t, []klcv1alpha1.WorkloadDrift{
+		{
+			Workload:        "backend",
+			DeployedVersion: "1.0.0",
+			RunningVersions: []string{"1.0.0", "1.1.0"},
+			RunningImages:   []string{"backend:1.0.0", "backend:1.1.0"},
+			Reason:          reasons.VersionChanged.Code,
+		},
+		{
+			Workload:        "cache",
+			DeployedVersion: "1.0.0",
+			RunningVersions: []string{"1.0.0"},
+			RunningImages:   []string{"cache:1.0.0"},
+			Reason:          reasons.ResourceReplaced.Code,
+		},
+		{
+			Workload:        "db",
+			DeployedVersion: "1.0.0",
+			Reason:          reasons.NotRunning.Code,
+		},
+	}, drift)
+}
+
+func TestGetLatestSucceededAppVersion(t *testing.T) {
+	scheme := runtime.NewScheme()
+	testrequire.Nil(t, klcv1alpha1.AddToScheme(scheme))
+	now := metav1.Now()
+	objects := []client.Object{}
+	for name, status := range map[string]klcv1alpha1.KeptnAppVersionStatus{
+		"myapp-1": {Status: common.StateSucceeded, EndTime: metav1.NewTime(now.Add(-time.Minute))},
+		"myapp-2": {Status: common.StateSucceeded, EndTime: now},
+		"myapp-3": {Status: common.StateFailed, EndTime: metav1.NewTime(now.Add(time.Minute))},
+	} {
+		objects = append(objects, &klcv1alpha1.KeptnAppVersion{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+			Spec:       klcv1alpha1.KeptnAppVersionSpec{AppName: "myapp"},
+			Status:     status,
+		})
+	}
+	r := &KeptnAppDriftReconciler{Client: fake.NewClientBuilder().WithScheme(scheme).WithObjects(objects...).Build()}
+
+	latest, err := r.getLatestSucceededAppVersion(context.TODO(), &klcv1alpha1.KeptnApp{ObjectMeta: metav1.ObjectMeta{Name: "myapp", Namespace: "default"}})
+
+	testrequire.Nil(t, err)
+	testrequire.Equal(t, "myapp-2", latest.Name)
+}
@@ -19,24 +19,33 @@ package keptnappversion
 import (
 	"context"
 	"fmt"
-	"k8s.io/apimachinery/pkg/types"
 	"time"
 
 	"github.com/keptn/lifecycle-controller/operator/api/v1alpha1/semconv"
 	"go.opentelemetry.io/otel/codes"
 	"go.opentelemetry.io/otel/trace"
 	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/source"
 
 	"github.com/go-logr/logr"
 	"github.com/keptn/lifecycle-controller/operator/api/v1alpha1/common"
+	"github.com/keptn/lifecycle-controller/operator/metrics"
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/propagation"
 	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/tools/record"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	klcv1alpha1 "github.com/keptn/lifecycle-controller/operator/api/v1alpha1"
+	"github.com/keptn/lifecycle-controller/operator/controllers/debug"
+	"github.com/keptn/lifecycle-controller/operator/controllers/propagate"
+	"github.com/keptn/lifecycle-controller/operator/controllers/reasons"
+	"github.com/keptn/lifecycle-controller/operator/controllers/selfmonitoring"
+	"github.com/keptn/lifecycle-controller/operator/integrations/incident"
 	"k8s.io/apimachinery/pkg/runtime"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -49,14 +58,26 @@ type KeptnAppVersionReconciler struct {
 	Log         logr.Logger
 	Recorder    record.EventRecorder
 	Tracer      trace.Tracer
-	Meters      common.KeptnMeters
+	Meters      metrics.Meters
 	bindCRDSpan map[string]trace.Span
+	// IncidentManager opens incidents for failed deployments in production namespaces. It is optional.
+	IncidentManager incident.Manager
+	// DebugStatus records the last decision of the reconciler in status.debug
+	DebugStatus bool
+	// Propagation selects the labels and annotations of the app version copied to its KeptnTasks
+	Propagation propagate.Policy
+	// RollbackEnabled rolls back the workloads of app versions with spec.rollbackOnFailure if their post-deployment evaluations fail
+	RollbackEnabled bool
+	// StatusEventsLimit is the number of the last events of an app version mirrored in status.events. 0 disables it.
+	StatusEventsLimit int
 }
 
 //+kubebuilder:rbac:groups=lifecycle.keptn.sh,resources=keptnappversions,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups=lifecycle.keptn.sh,resources=keptnappversions/status,verbs=get;update;patch
 //+kubebuilder:rbac:groups=lifecycle.keptn.sh,resources=keptnappversions/finalizers,verbs=update
 //+kubebuilder:rbac:groups=lifecycle.keptn.sh,resources=keptnworkloadinstances/status,verbs=get;update;patch
+//+kubebuilder:rbac:groups=lifecycle.keptn.sh,resources=keptntasks;keptnevaluations,verbs=delete
+//+kubebuilder:rbac:groups=core,resources=namespaces,verbs=get;list;watch
 
 // Reconcile is part of the main kubernetes reconciliation loop which aims to
 // move the current state of the cluster closer to the desired state.
@@ -67,12 +88,12 @@ type KeptnAppVersionReconciler struct {
 //
 // For more details, check Reconcile and its Result here:
 // - https://pkg.go.dev/sigs.k8s.io/controller-runtime@v0.13.0/pkg/reconcile
-func (r *KeptnAppVersionReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+func (r *KeptnAppVersionReconciler) Reconcile(ctx context.Context, req ctrl.Request) (result ctrl.Result, err error) {
 
 	r.Log.Info("Searching for Keptn App Version")
 
 	appVersion := &klcv1alpha1.KeptnAppVersion{}
-	err := r.Get(ctx, req.NamespacedName, appVersion)
+	err = r.Get(ctx, req.NamespacedName, appVersion)
 	if errors.IsNotFound(err) {
 		return reconcile.Result{}, nil
 	}
@@ -82,6 +103,22 @@ func (r *KeptnAppVersionReconciler) Reconcile(ctx context.Context, req ctrl.Requ
 		return reconcile.Result{}, fmt.Errorf("could not fetch KeptnappVersion: %+v", err)
 	}
 
+	if r.DebugStatus {
+		appVersion.Status.Debug = nil
+		defer func() {
+			r.recordDecision(ctx, appVersion, result, err)
+		}()
+	} else if r.StatusEventsLimit > 0 {
+		events := append([]klcv1alpha1.LifecycleEvent{}, appVersion.Status.Events...)
+		defer r.persistEvents(ctx, appVersion, events)
+	}
+
+	if waiting, err := r.waitForPreviousVersions(ctx, appVersion); err != nil {
+		return ctrl.Result{}, err
+	} else if waiting {
+		return ctrl.Result{Requeue: true, RequeueAfter: 10 * time.Second}, nil
+	}
+
 	appVersion.SetStartTime()
 
 	traceContextCarrier := propagation.MapCarrier(appVersion.Annotations)
@@ -95,59 +132,80 @@ func (r *KeptnAppVersionReconciler) Reconcile(ctx context.Context, req ctrl.Requ
 
 	semconv.AddAttributeFromAppVersion(span, *appVersion)
 
+	if cancelled, err := r.reconcileAbort(ctx, ctxAppTrace, appVersion); cancelled {
+		return ctrl.Result{}, err
+	}
+	if exceeded, err := r.reconcileDeadline(ctx, ctxAppTrace, appVersion); exceeded {
+		return ctrl.Result{}, err
+	}
+
+	fastPath := r.skipEmptyPhases(appVersion)
+
 	phase := common.PhaseAppPreDeployment
 
-	if appVersion.Status.CurrentPhase == "" {
+	if appVersion.Status.CurrentPhase == "" && !fastPath {
 		r.unbindSpan(appVersion, phase.ShortName)
 		var spanAppTrace trace.Span
-		ctxAppTrace, spanAppTrace = r.getSpan(ctxAppTrace, appVersion, phase.ShortName)
+		_, spanAppTrace = r.getSpan(ctxAppTrace, appVersion, phase.ShortName)
 
 		semconv.AddAttributeFromAppVersion(spanAppTrace, *appVersion)
 		spanAppTrace.AddEvent("App Version Pre-Deployment Tasks started", trace.WithTimestamp(time.Now()))
-		r.recordEvent(phase, "Normal", appVersion, "Started", "have started")
+		r.recordEvent(phase, appVersion, reasons.Started)
 	}
 
 	if !appVersion.IsPreDeploymentSucceeded() {
-		reconcilePreDep := func() (common.KeptnState, error) {
-			return r.reconcilePrePostDeployment(ctx, appVersion, common.PreDeploymentCheckType)
+		reconcilePreDep := func(phaseCtx context.Context) (common.KeptnState, error) {
+			return r.reconcilePrePostDeployment(phaseCtx, appVersion, common.PreDeploymentCheckType)
 		}
 		return r.handlePhase(ctx, ctxAppTrace, appVersion, phase, span, appVersion.IsPreDeploymentFailed, reconcilePreDep)
 	}
 
 	phase = common.PhaseAppPreEvaluation
 	if !appVersion.IsPreDeploymentEvaluationSucceeded() {
-		reconcilePreEval := func() (common.KeptnState, error) {
-			return r.reconcilePrePostEvaluation(ctx, appVersion, common.PreDeploymentEvaluationCheckType)
+		reconcilePreEval := func(phaseCtx context.Context) (common.KeptnState, error) {
+			return r.reconcilePrePostEvaluation(phaseCtx, appVersion, common.PreDeploymentEvaluationCheckType)
 		}
 		return r.handlePhase(ctx, ctxAppTrace, appVersion, phase, span, appVersion.IsPreDeploymentEvaluationFailed, reconcilePreEval)
 	}
 
+	phase = common.PhaseAppApproval
+	if !appVersion.IsApproved() {
+		reconcileApproval := func(phaseCtx context.Context) (common.KeptnState, error) {
+			return r.reconcileApproval(phaseCtx, appVersion)
+		}
+		return r.handlePhase(ctx, ctxAppTrace, appVersion, phase, span, appVersion.IsApprovalFailed, reconcileApproval)
+	}
+
 	phase = common.PhaseAppDeployment
 	if !appVersion.AreWorkloadsSucceeded() {
-		reconcileAppDep := func() (common.KeptnState, error) {
-			return r.reconcileWorkloads(ctx, appVersion)
+		reconcileAppDep := func(phaseCtx context.Context) (common.KeptnState, error) {
+			return r.reconcileWorkloads(phaseCtx, appVersion)
 		}
-		return r.handlePhase(ctx, ctxAppTrace, appVersion, phase, span, appVersion.AreWorkloadsFailed, reconcileAppDep)
-
+		result, err := r.handlePhase(ctx, ctxAppTrace, appVersion, phase, span, appVersion.AreWorkloadsFailed, reconcileAppDep)
+		// on the fast path, the app version completes right away once its workload has succeeded
+		if !fastPath || err != nil || !appVersion.AreWorkloadsSucceeded() {
+			return result, err
+		}
+		r.skipEmptyPhases(appVersion)
 	}
 
 	phase = common.PhaseAppPostDeployment
 	if !appVersion.IsPostDeploymentSucceeded() {
-		reconcilePostDep := func() (common.KeptnState, error) {
-			return r.reconcilePrePostDeployment(ctx, appVersion, common.PostDeploymentCheckType)
+		reconcilePostDep := func(phaseCtx context.Context) (common.KeptnState, error) {
+			return r.reconcilePrePostDeployment(phaseCtx, appVersion, common.PostDeploymentCheckType)
 		}
 		return r.handlePhase(ctx, ctxAppTrace, appVersion, phase, span, appVersion.IsPostDeploymentFailed, reconcilePostDep)
 	}
 
 	phase = common.PhaseAppPostEvaluation
 	if !appVersion.IsPostDeploymentEvaluationCompleted() {
-		reconcilePostEval := func() (common.KeptnState, error) {
-			return r.reconcilePrePostEvaluation(ctx, appVersion, common.PostDeploymentEvaluationCheckType)
+		reconcilePostEval := func(phaseCtx context.Context) (common.KeptnState, error) {
+			return r.reconcilePrePostEvaluation(phaseCtx, appVersion, common.PostDeploymentEvaluationCheckType)
 		}
 		return r.handlePhase(ctx, ctxAppTrace, appVersion, phase, span, appVersion.IsPostDeploymentEvaluationFailed, reconcilePostEval)
 	}
 
-	r.recordEvent(phase, "Normal", appVersion, "Finished", "is finished")
+	r.recordEvent(phase, appVersion, reasons.Finished)
 	err = r.Client.Status().Update(ctx, appVersion)
 	if err != nil {
 		span.SetStatus(codes.Error, err.Error())
@@ -156,8 +214,12 @@ func (r *KeptnAppVersionReconciler) Reconcile(ctx context.Context, req ctrl.Requ
 
 	// AppVersion is completed at this place
 
-	if !appVersion.IsEndTimeSet() {
+	completed := !appVersion.IsEndTimeSet()
+	if completed {
 		appVersion.Status.CurrentPhase = common.PhaseCompleted.ShortName
+		if appVersion.Status.Status.IsSucceeded() && appVersion.Status.WorkloadOverallStatus.IsWarning() {
+			appVersion.Status.Status = common.StateWarning
+		}
 		appVersion.SetEndTime()
 	}
 
@@ -166,16 +228,20 @@ func (r *KeptnAppVersionReconciler) Reconcile(ctx context.Context, req ctrl.Requ
 		return ctrl.Result{Requeue: true}, err
 	}
 
+	if completed && !appVersion.Status.Status.IsFailed() {
+		r.resolveIncidents(ctx, appVersion)
+	}
+
 	attrs := appVersion.GetMetricsAttributes()
 
 	r.Log.Info("Increasing app count")
 
 	// metrics: increment app counter
-	r.Meters.AppCount.Add(ctx, 1, attrs...)
+	r.Meters.Add(ctx, metrics.AppCount, 1, attrs...)
 
 	// metrics: add app duration
 	duration := appVersion.Status.EndTime.Time.Sub(appVersion.Status.StartTime.Time)
-	r.Meters.AppDuration.Record(ctx, duration.Seconds(), attrs...)
+	r.Meters.Record(ctx, metrics.AppDuration, duration.Seconds(), attrs...)
 
 	return ctrl.Result{}, nil
 }
@@ -183,65 +249,121 @@ func (r *KeptnAppVersionReconciler) Reconcile(ctx context.Context, req ctrl.Requ
 // SetupWithManager sets up the controller with the Manager.
 func (r *KeptnAppVersionReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	return ctrl.NewControllerManagedBy(mgr).
-		For(&klcv1alpha1.KeptnAppVersion{}, builder.WithPredicates(predicate.GenerationChangedPredicate{})).
-		Complete(r)
+		// approvals and rejections are annotated on the app version
+		For(&klcv1alpha1.KeptnAppVersion{}, builder.WithPredicates(predicate.Or(predicate.GenerationChangedPredicate{}, predicate.AnnotationChangedPredicate{}))).
+		Watches(&source.Kind{Type: &klcv1alpha1.KeptnWorkloadInstance{}}, handler.EnqueueRequestsFromMapFunc(r.getFastPathAppVersionsForWorkloadInstance)).
+		Complete(selfmonitoring.Wrap("keptnappversion", r))
 }
 
-func (r *KeptnAppVersionReconciler) recordEvent(phase common.KeptnPhaseType, eventType string, appVersion *klcv1alpha1.KeptnAppVersion, shortReason string, longReason string) {
-	r.Recorder.Event(appVersion, eventType, fmt.Sprintf("%s%s", phase.ShortName, shortReason), fmt.Sprintf("%s %s / Namespace: %s, Name: %s, Version: %s ", phase.LongName, longReason, appVersion.Namespace, appVersion.Name, appVersion.Spec.Version))
+// getFastPathAppVersionsForWorkloadInstance returns requests for the app versions on the fast path the given
+// KeptnWorkloadInstance belongs to, so that they complete as soon as the workload has been deployed instead of after
+// the next requeue
+func (r *KeptnAppVersionReconciler) getFastPathAppVersionsForWorkloadInstance(instance client.Object) []reconcile.Request {
+	wli, ok := instance.(*klcv1alpha1.KeptnWorkloadInstance)
+	if !ok || !wli.Status.Status.IsCompleted() {
+		return nil
+	}
+	appVersions := &klcv1alpha1.KeptnAppVersionList{}
+	if err := r.Client.List(context.TODO(), appVersions, client.InNamespace(wli.Namespace)); err != nil {
+		r.Log.Error(err, "could not retrieve KeptnAppVersions")
+		return nil
+	}
+
+	var requests []reconcile.Request
+	for _, appVersion := range appVersions.Items {
+		if appVersion.Spec.AppName == wli.Spec.AppName && appVersion.HasFastPath() && !appVersion.IsEndTimeSet() {
+			requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{Namespace: appVersion.Namespace, Name: appVersion.Name}})
+		}
+	}
+	return requests
 }
 
-func (r *KeptnAppVersionReconciler) handlePhase(ctx context.Context, ctxAppTrace context.Context, appVersion *klcv1alpha1.KeptnAppVersion, phase common.KeptnPhaseType, span trace.Span, phaseFailed func() bool, reconcilePhase func() (common.KeptnState, error)) (ctrl.Result, error) {
+// phaseAppVersion is the phase of the events which concern the app version as a whole, so that their reason is the
+// code of the catalog without the prefix of a phase
+var phaseAppVersion = common.KeptnPhaseType{LongName: "AppVersion"}
+
+// recordEvent records an event of the given reason of the catalog for the phase, whose message is formatted with args
+func (r *KeptnAppVersionReconciler) recordEvent(phase common.KeptnPhaseType, appVersion *klcv1alpha1.KeptnAppVersion, reason reasons.Reason, args ...interface{}) {
+	longReason := reason.Message(args...)
+	r.annotatedEvent(appVersion, reason.Annotations(), string(reason.Severity), fmt.Sprintf("%s%s", phase.ShortName, reason.Code), fmt.Sprintf("%s %s / Namespace: %s, Name: %s, Version: %s ", phase.LongName, longReason, appVersion.Namespace, appVersion.Name, appVersion.Spec.Version))
+	if r.DebugStatus {
+		appVersion.Status.Debug = debug.NewDecision(phase.ShortName, fmt.Sprintf("%s%s: %s %s", phase.ShortName, reason.Code, phase.LongName, longReason))
+	}
+}
+
+func (r *KeptnAppVersionReconciler) handlePhase(ctx context.Context, ctxAppTrace context.Context, appVersion *klcv1alpha1.KeptnAppVersion, phase common.KeptnPhaseType, span trace.Span, phaseFailed func() bool, reconcilePhase func(phaseCtx context.Context) (common.KeptnState, error)) (ctrl.Result, error) {
 
 	oldStatus := appVersion.Status.Status
 	newStatus := oldStatus
 	statusUpdated := false
 
 	r.Log.Info(phase.LongName + " not finished")
-	ctxAppTrace, spanAppTrace := r.getSpan(ctxAppTrace, appVersion, phase.ShortName)
+	_, spanAppTrace := r.getSpan(ctxAppTrace, appVersion, phase.ShortName)
 
 	oldPhase := appVersion.Status.CurrentPhase
 	appVersion.Status.CurrentPhase = phase.ShortName
+	if oldPhase != phase.ShortName {
+		appVersion.Status.PhaseStartTime = metav1.NewTime(time.Now().UTC())
+	}
 	if phaseFailed() { //TODO eventually we should decide whether a task returns FAILED, currently we never have this status set
-		r.recordEvent(phase, "Warning", appVersion, "Failed", "has failed")
+		r.recordEvent(phase, appVersion, reasons.Failed)
 		return ctrl.Result{Requeue: true, RequeueAfter: 60 * time.Second}, nil
 	}
-	state, err := reconcilePhase()
+	if timedOut, err := r.reconcilePhaseTimeout(ctx, appVersion, phase, span, spanAppTrace); timedOut {
+		return ctrl.Result{}, err
+	}
+	if klcv1alpha1.SkipsPhase(appVersion.Spec.SkipPhases, phase) {
+		reconcilePhase = r.skipPhase(appVersion, phase)
+	}
+	if appVersion.Spec.SimulateFailure.Matches(phase) {
+		reconcilePhase = r.simulateFailure(appVersion, phase)
+	}
+	// spans started by the phase, e.g. for creating tasks, are children of the span of the phase
+	state, err := reconcilePhase(trace.ContextWithSpan(ctx, spanAppTrace))
 	if err != nil {
 		spanAppTrace.AddEvent(phase.LongName + " could not get reconciled")
-		r.recordEvent(phase, "Warning", appVersion, "ReconcileErrored", "could not get reconciled")
+		r.recordEvent(phase, appVersion, reasons.ReconcileErrored)
 		span.SetStatus(codes.Error, err.Error())
 		return ctrl.Result{Requeue: true}, err
 	}
 	if state.IsSucceeded() {
 		newStatus = common.StateSucceeded
 		spanAppTrace.AddEvent(phase.LongName + " has succeeded")
-		spanAppTrace.SetStatus(codes.Ok, "Succeeded")
+		spanAppTrace.SetStatus(codes.Ok, reasons.Succeeded.Code)
 		spanAppTrace.End()
 		r.unbindSpan(appVersion, phase.ShortName)
-		r.recordEvent(phase, "Normal", appVersion, "Succeeded", "has succeeded")
+		if state.IsWarning() {
+			r.recordEvent(phase, appVersion, reasons.SucceededWithWarnings)
+		} else {
+			r.recordEvent(phase, appVersion, reasons.Succeeded)
+		}
 	} else if state.IsFailed() {
 
 		appVersion.SetEndTime()
 		attrs := appVersion.GetMetricsAttributes()
-		r.Meters.AppCount.Add(ctx, 1, attrs...)
+		r.Meters.Add(ctx, metrics.AppCount, 1, attrs...)
 
 		newStatus = common.StateFailed
 
 		spanAppTrace.AddEvent(phase.LongName + " has failed")
-		spanAppTrace.SetStatus(codes.Error, "Failed")
+		spanAppTrace.SetStatus(codes.Error, reasons.Failed.Code)
 		spanAppTrace.End()
 		r.unbindSpan(appVersion, phase.ShortName)
 
-		r.recordEvent(phase, "Warning", appVersion, "Failed", "has failed")
+		r.recordEvent(phase, appVersion, reasons.Failed)
+
+		if phase == common.PhaseAppPostEvaluation {
+			r.openIncident(ctx, ctxAppTrace, appVersion)
+			r.rollbackOnFailure(ctx, appVersion)
+		}
 	} else {
 		newStatus = common.StateProgressing
-		r.recordEvent(phase, "Warning", appVersion, "NotFinished", "has not finished")
+		r.recordEvent(phase, appVersion, reasons.NotFinished)
 	}
 
 	// check if status changed
 	if oldPhase != appVersion.Status.CurrentPhase {
-		ctx, spanAppTrace = r.getSpan(ctxAppTrace, appVersion, appVersion.Status.CurrentPhase)
+		_, spanAppTrace = r.getSpan(ctxAppTrace, appVersion, appVersion.Status.CurrentPhase)
 		semconv.AddAttributeFromAppVersion(spanAppTrace, *appVersion)
 		statusUpdated = true
 	}
@@ -268,9 +390,13 @@ func (r *KeptnAppVersionReconciler) getSpan(ctx context.Context, appv *klcv1alph
 		r.bindCRDSpan = make(map[string]trace.Span)
 	}
 	if span, ok := r.bindCRDSpan[appvName]; ok {
-		return ctx, span
+		return trace.ContextWithSpan(ctx, span), span
 	}
 	ctx, span := r.Tracer.Start(ctx, phase, trace.WithSpanKind(trace.SpanKindConsumer))
+	// spans that are not recorded, e.g. if tracing is disabled, do not have to be kept until they end
+	if !span.IsRecording() {
+		return ctx, span
+	}
 	r.Log.Info("DEBUG: Created span " + appvName)
 	r.bindCRDSpan[appvName] = span
 	return ctx, span
@@ -279,78 +405,3 @@ func (r *KeptnAppVersionReconciler) getSpan(ctx context.Context, appv *klcv1alph
 func (r *KeptnAppVersionReconciler) unbindSpan(appv *klcv1alpha1.KeptnAppVersion, phase string) {
 	delete(r.bindCRDSpan, r.getSpanName(appv, phase))
 }
-
-func (r *KeptnAppVersionReconciler) GetActiveApps(ctx context.Context) ([]common.GaugeValue, error) {
-	appInstances := &klcv1alpha1.KeptnAppVersionList{}
-	err := r.List(ctx, appInstances)
-	if err != nil {
-		return nil, fmt.Errorf("could not retrieve app versions: %w", err)
-	}
-
-	res := []common.GaugeValue{}
-
-	for _, appInstance := range appInstances.Items {
-		gaugeValue := int64(0)
-		if !appInstance.IsEndTimeSet() {
-			gaugeValue = int64(1)
-		}
-		res = append(res, common.GaugeValue{
-			Value:      gaugeValue,
-			Attributes: appInstance.GetActiveMetricsAttributes(),
-		})
-	}
-
-	return res, nil
-}
-
-func (r *KeptnAppVersionReconciler) GetDeploymentInterval(ctx context.Context) ([]common.GaugeFloatValue, error) {
-	appInstances := &klcv1alpha1.KeptnAppVersionList{}
-	err := r.List(ctx, appInstances)
-	if err != nil {
-		return nil, fmt.Errorf("could not retrieve app versions: %w", err)
-	}
-
-	res := []common.GaugeFloatValue{}
-
-	for _, appInstance := range appInstances.Items {
-
-		if appInstance.Spec.PreviousVersion != "" {
-			previousAppVersion := &klcv1alpha1.KeptnAppVersion{}
-			appName := fmt.Sprintf("%s-%s", appInstance.Spec.AppName, appInstance.Spec.PreviousVersion)
-			err := r.Get(ctx, types.NamespacedName{Name: appName, Namespace: appInstance.Namespace}, previousAppVersion)
-			if err != nil {
-				r.Log.Error(err, "Previous App Version not found")
-			} else {
-				previousInterval := appInstance.Status.StartTime.Time.Sub(previousAppVersion.Status.EndTime.Time)
-				res = append(res, common.GaugeFloatValue{
-					Value:      previousInterval.Seconds(),
-					Attributes: appInstance.GetDurationMetricsAttributes(),
-				})
-			}
-		}
-	}
-
-	return res, nil
-}
-
-func (r *KeptnAppVersionReconciler) GetDeploymentDuration(ctx context.Context) ([]common.GaugeFloatValue, error) {
-	appInstances := &klcv1alpha1.KeptnAppVersionList{}
-	err := r.List(ctx, appInstances)
-	if err != nil {
-		return nil, fmt.Errorf("could not retrieve app versions: %w", err)
-	}
-
-	res := []common.GaugeFloatValue{}
-
-	for _, appInstance := range appInstances.Items {
-		if appInstance.IsEndTimeSet() {
-			duration := appInstance.Status.EndTime.Time.Sub(appInstance.Status.StartTime.Time)
-			res = append(res, common.GaugeFloatValue{
-				Value:      duration.Seconds(),
-				Attributes: appInstance.GetDurationMetricsAttributes(),
-			})
-		}
-	}
-
-	return res, nil
-}
@@ -0,0 +1,54 @@
+package keptnappversion
+
+import (
+	"context"
+	"fmt"
+
+	klcv1alpha1 "github.com/keptn/lifecycle-controller/operator/api/v1alpha1"
+	"github.com/keptn/lifecycle-controller/operator/api/v1alpha1/common"
+	"github.com/keptn/lifecycle-controller/operator/controllers/deadline"
+	"github.com/keptn/lifecycle-controller/operator/controllers/reasons"
+	"github.com/keptn/lifecycle-controller/operator/metrics"
+	"go.opentelemetry.io/otel/codes"
+)
+
+// reconcileAbort cancels the app version if it has been aborted before it completed, deletes its tasks and
+// evaluations that are still running and ends the span of its current phase. It returns whether the app version has
+// been cancelled, in which case it must not be reconciled any further.
+func (r *KeptnAppVersionReconciler) reconcileAbort(ctx context.Context, ctxAppTrace context.Context, appVersion *klcv1alpha1.KeptnAppVersion) (bool, error) {
+	if appVersion.Status.Status.IsCancelled() {
+		return true, nil
+	}
+	if !appVersion.IsAbortRequested() || appVersion.IsEndTimeSet() {
+		return false, nil
+	}
+
+	status := &appVersion.Status
+	if err := deadline.Cancel(ctx, r.Client, appVersion.Namespace, status.PreDeploymentTaskStatus, status.PreDeploymentEvaluationTaskStatus); err != nil {
+		return true, err
+	}
+	if err := deadline.Cancel(ctx, r.Client, appVersion.Namespace, status.PostDeploymentTaskStatus, status.PostDeploymentEvaluationTaskStatus); err != nil {
+		return true, err
+	}
+	for _, state := range []*common.KeptnState{&status.PreDeploymentStatus, &status.PreDeploymentEvaluationStatus, &status.WorkloadOverallStatus, &status.PostDeploymentStatus, &status.PostDeploymentEvaluationStatus} {
+		if !state.IsCompleted() {
+			*state = common.StateCancelled
+		}
+	}
+	status.Status = common.StateCancelled
+	status.Reason = fmt.Sprintf("aborted in phase %s", status.CurrentPhase)
+	appVersion.SetEndTime()
+
+	r.recordEvent(phaseAppVersion, appVersion, reasons.Aborted)
+	r.Meters.Add(ctx, metrics.AppCount, 1, appVersion.GetMetricsAttributes()...)
+
+	if status.CurrentPhase != "" {
+		_, spanPhase := r.getSpan(ctxAppTrace, appVersion, status.CurrentPhase)
+		spanPhase.AddEvent("Aborted")
+		spanPhase.SetStatus(codes.Error, reasons.Cancelled.Code)
+		spanPhase.End()
+		r.unbindSpan(appVersion, status.CurrentPhase)
+	}
+
+	return true, r.Client.Status().Update(ctx, appVersion)
+}
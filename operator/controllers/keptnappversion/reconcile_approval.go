@@ -0,0 +1,46 @@
+package keptnappversion
+
+import (
+	"context"
+	"fmt"
+
+	klcv1alpha1 "github.com/keptn/lifecycle-controller/operator/api/v1alpha1"
+	"github.com/keptn/lifecycle-controller/operator/api/v1alpha1/common"
+	"github.com/keptn/lifecycle-controller/operator/controllers/reasons"
+)
+
+// reconcileApproval waits until the app version has been approved with the keptn.sh/approved-by annotation, and fails
+// the app version if it has been rejected with the keptn.sh/rejected-by annotation instead
+func (r *KeptnAppVersionReconciler) reconcileApproval(ctx context.Context, appVersion *klcv1alpha1.KeptnAppVersion) (common.KeptnState, error) {
+	state, approver := getApprovalState(appVersion)
+	if state == appVersion.Status.ApprovalStatus {
+		return state, nil
+	}
+	appVersion.Status.ApprovalStatus = state
+	appVersion.Status.Approver = approver
+	switch {
+	case state.IsSucceeded():
+		r.recordEvent(common.PhaseAppApproval, appVersion, reasons.ApprovalGranted, approver)
+	case state.IsFailed():
+		appVersion.Status.Reason = fmt.Sprintf("rejected by %s", approver)
+		r.recordEvent(common.PhaseAppApproval, appVersion, reasons.ApprovalDenied, approver)
+	default:
+		r.recordEvent(common.PhaseAppApproval, appVersion, reasons.ApprovalRequested, common.ApprovedByAnnotation)
+	}
+	if err := r.Client.Status().Update(ctx, appVersion); err != nil {
+		return common.StateUnknown, err
+	}
+	return state, nil
+}
+
+// getApprovalState returns the state of the approval of the app version and the user who has approved or rejected it.
+// A rejection takes precedence over an approval.
+func getApprovalState(appVersion *klcv1alpha1.KeptnAppVersion) (common.KeptnState, string) {
+	if rejecter := appVersion.Annotations[common.RejectedByAnnotation]; rejecter != "" {
+		return common.StateFailed, rejecter
+	}
+	if approver := appVersion.Annotations[common.ApprovedByAnnotation]; approver != "" {
+		return common.StateSucceeded, approver
+	}
+	return common.StateProgressing, ""
+}
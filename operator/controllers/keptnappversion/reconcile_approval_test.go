@@ -0,0 +1,41 @@
+package keptnappversion
+
+import (
+	"testing"
+
+	klcv1alpha1 "github.com/keptn/lifecycle-controller/operator/api/v1alpha1"
+	"github.com/keptn/lifecycle-controller/operator/api/v1alpha1/common"
+	testrequire "github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestGetApprovalState(t *testing.T) {
+	appVersion := &klcv1alpha1.KeptnAppVersion{
+		ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{}},
+		Spec: klcv1alpha1.KeptnAppVersionSpec{
+			KeptnAppSpec: klcv1alpha1.KeptnAppSpec{
+				Workloads:       []klcv1alpha1.KeptnWorkloadRef{{Name: "podtato-head", Version: "1.0.0"}},
+				RequireApproval: true,
+			},
+		},
+	}
+	testrequire.False(t, appVersion.HasFastPath())
+	testrequire.False(t, appVersion.IsApproved())
+
+	state, approver := getApprovalState(appVersion)
+	testrequire.Equal(t, common.StateProgressing, state)
+	testrequire.Empty(t, approver)
+
+	appVersion.Annotations[common.ApprovedByAnnotation] = "alice"
+	state, approver = getApprovalState(appVersion)
+	testrequire.Equal(t, common.StateSucceeded, state)
+	testrequire.Equal(t, "alice", approver)
+
+	appVersion.Annotations[common.RejectedByAnnotation] = "bob"
+	state, approver = getApprovalState(appVersion)
+	testrequire.Equal(t, common.StateFailed, state)
+	testrequire.Equal(t, "bob", approver)
+
+	appVersion.Spec.RequireApproval = false
+	testrequire.True(t, appVersion.IsApproved())
+}
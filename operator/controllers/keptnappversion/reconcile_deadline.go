@@ -0,0 +1,55 @@
+package keptnappversion
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	klcv1alpha1 "github.com/keptn/lifecycle-controller/operator/api/v1alpha1"
+	"github.com/keptn/lifecycle-controller/operator/api/v1alpha1/common"
+	"github.com/keptn/lifecycle-controller/operator/controllers/deadline"
+	"github.com/keptn/lifecycle-controller/operator/controllers/reasons"
+	"github.com/keptn/lifecycle-controller/operator/metrics"
+	"go.opentelemetry.io/otel/codes"
+)
+
+// reconcileDeadline fails the app version if it runs longer than the maximum deployment duration of its namespace,
+// and deletes its tasks and evaluations that are still running. It returns whether the deadline has been exceeded.
+func (r *KeptnAppVersionReconciler) reconcileDeadline(ctx context.Context, ctxAppTrace context.Context, appVersion *klcv1alpha1.KeptnAppVersion) (bool, error) {
+	if appVersion.IsEndTimeSet() {
+		return false, nil
+	}
+	maxDuration, err := deadline.Resolve(ctx, r.Client, appVersion.Namespace)
+	if err != nil {
+		r.Log.Error(err, "could not resolve max deployment duration of AppVersion")
+		return false, nil
+	}
+	if !deadline.Exceeded(appVersion.Status.StartTime, maxDuration, time.Now()) {
+		return false, nil
+	}
+
+	status := &appVersion.Status
+	if err := deadline.Cleanup(ctx, r.Client, appVersion.Namespace, status.PreDeploymentTaskStatus, status.PreDeploymentEvaluationTaskStatus); err != nil {
+		return true, err
+	}
+	if err := deadline.Cleanup(ctx, r.Client, appVersion.Namespace, status.PostDeploymentTaskStatus, status.PostDeploymentEvaluationTaskStatus); err != nil {
+		return true, err
+	}
+	deadline.FailPhase(&status.PreDeploymentStatus, &status.PreDeploymentEvaluationStatus, &status.WorkloadOverallStatus, &status.PostDeploymentStatus, &status.PostDeploymentEvaluationStatus)
+	status.Status = common.StateFailed
+	status.Reason = fmt.Sprintf("exceeded the maximum deployment duration of %s", maxDuration)
+	appVersion.SetEndTime()
+
+	r.recordEvent(phaseAppVersion, appVersion, reasons.DeadlineExceeded)
+	r.Meters.Add(ctx, metrics.AppCount, 1, appVersion.GetMetricsAttributes()...)
+
+	if status.CurrentPhase != "" {
+		_, spanPhase := r.getSpan(ctxAppTrace, appVersion, status.CurrentPhase)
+		spanPhase.AddEvent("Deadline exceeded")
+		spanPhase.SetStatus(codes.Error, reasons.DeadlineExceeded.Code)
+		spanPhase.End()
+		r.unbindSpan(appVersion, status.CurrentPhase)
+	}
+
+	return true, r.Client.Status().Update(ctx, appVersion)
+}
@@ -0,0 +1,32 @@
+package keptnappversion
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	klcv1alpha1 "github.com/keptn/lifecycle-controller/operator/api/v1alpha1"
+	"github.com/keptn/lifecycle-controller/operator/controllers/debug"
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+// recordDecision stores the branch the reconciler took, the states of the phases and the requeue of the
+// reconciliation in status.debug. The branch is the last event recorded during the reconciliation.
+func (r *KeptnAppVersionReconciler) recordDecision(ctx context.Context, appVersion *klcv1alpha1.KeptnAppVersion, result ctrl.Result, err error) {
+	if appVersion.Status.Debug == nil {
+		appVersion.Status.Debug = debug.NewDecision(appVersion.Status.CurrentPhase, "no event recorded")
+	}
+	debug.Complete(appVersion.Status.Debug, map[string]string{
+		"preDeploymentStatus":            string(appVersion.Status.PreDeploymentStatus),
+		"preDeploymentEvaluationStatus":  string(appVersion.Status.PreDeploymentEvaluationStatus),
+		"workloadOverallStatus":          string(appVersion.Status.WorkloadOverallStatus),
+		"postDeploymentStatus":           string(appVersion.Status.PostDeploymentStatus),
+		"postDeploymentEvaluationStatus": string(appVersion.Status.PostDeploymentEvaluationStatus),
+		"status":                         string(appVersion.Status.Status),
+		"workloads":                      strconv.Itoa(len(appVersion.Spec.Workloads)),
+		"fastPath":                       strconv.FormatBool(appVersion.HasFastPath()),
+	}, result, err, time.Now())
+	if err := r.Client.Status().Update(ctx, appVersion); err != nil {
+		r.Log.Error(err, "could not record decision of reconciler", "appVersion", appVersion.Name)
+	}
+}
@@ -0,0 +1,55 @@
+package keptnappversion
+
+import (
+	"context"
+	"time"
+
+	klcv1alpha1 "github.com/keptn/lifecycle-controller/operator/api/v1alpha1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// annotatedEvent records an event for the app version with the given annotations, e.g. the code of its reason, and
+// mirrors it in status.events if StatusEventsLimit is set
+func (r *KeptnAppVersionReconciler) annotatedEvent(appVersion *klcv1alpha1.KeptnAppVersion, annotations map[string]string, eventType string, reason string, message string) {
+	r.Recorder.AnnotatedEventf(appVersion, annotations, eventType, reason, "%s", message)
+	if r.StatusEventsLimit > 0 {
+		appVersion.Status.Events = mirrorEvent(appVersion.Status.Events, eventType, reason, message, r.StatusEventsLimit, time.Now().UTC())
+	}
+}
+
+// persistEvents updates the status of the app version if events have been mirrored during the reconciliation,
+// since not every event is followed by an update of the status
+func (r *KeptnAppVersionReconciler) persistEvents(ctx context.Context, appVersion *klcv1alpha1.KeptnAppVersion, before []klcv1alpha1.LifecycleEvent) {
+	if equality.Semantic.DeepEqual(before, appVersion.Status.Events) {
+		return
+	}
+	if err := r.Client.Status().Update(ctx, appVersion); err != nil {
+		r.Log.Error(err, "could not mirror events in status", "appVersion", appVersion.Name)
+	}
+}
+
+// mirrorEvent appends an event to the list of events, keeping only the last limit events.
+// An event equal to the last one in the list only increases its count, like the event recorder aggregates them.
+func mirrorEvent(events []klcv1alpha1.LifecycleEvent, eventType string, reason string, message string, limit int, now time.Time) []klcv1alpha1.LifecycleEvent {
+	if n := len(events); n > 0 {
+		last := &events[n-1]
+		if last.Type == eventType && last.Reason == reason && last.Message == message {
+			last.Count++
+			last.LastTimestamp = metav1.NewTime(now)
+			return events
+		}
+	}
+	events = append(events, klcv1alpha1.LifecycleEvent{
+		Type:           eventType,
+		Reason:         reason,
+		Message:        message,
+		Count:          1,
+		FirstTimestamp: metav1.NewTime(now),
+		LastTimestamp:  metav1.NewTime(now),
+	})
+	if len(events) > limit {
+		events = events[len(events)-limit:]
+	}
+	return events
+}
@@ -0,0 +1,45 @@
+package keptnappversion
+
+import (
+	"testing"
+	"time"
+
+	klcv1alpha1 "github.com/keptn/lifecycle-controller/operator/api/v1alpha1"
+	"github.com/keptn/lifecycle-controller/operator/api/v1alpha1/common"
+	"github.com/keptn/lifecycle-controller/operator/controllers/reasons"
+	testrequire "github.com/stretchr/testify/require"
+	"k8s.io/client-go/tools/record"
+)
+
+func TestMirrorEvent(t *testing.T) {
+	now := time.Date(2022, 11, 8, 10, 12, 3, 0, time.UTC)
+	var events []klcv1alpha1.LifecycleEvent
+
+	events = mirrorEvent(events, "Normal", "AppPreDeployTasksFinished", "finished", 2, now)
+	events = mirrorEvent(events, "Warning", "AppDeployNotFinished", "not finished", 2, now)
+	events = mirrorEvent(events, "Warning", "AppDeployNotFinished", "not finished", 2, now.Add(time.Minute))
+
+	testrequire.Len(t, events, 2)
+	testrequire.Equal(t, int32(2), events[1].Count)
+	testrequire.True(t, events[1].FirstTimestamp.Time.Equal(now))
+	testrequire.True(t, events[1].LastTimestamp.Time.Equal(now.Add(time.Minute)))
+
+	events = mirrorEvent(events, "Normal", "AppDeployFinished", "finished", 2, now)
+
+	testrequire.Len(t, events, 2)
+	testrequire.Equal(t, "AppDeployNotFinished", events[0].Reason)
+	testrequire.Equal(t, "AppDeployFinished", events[1].Reason)
+}
+
+func TestEventNotMirroredByDefault(t *testing.T) {
+	r := &KeptnAppVersionReconciler{Recorder: record.NewFakeRecorder(10)}
+	appVersion := &klcv1alpha1.KeptnAppVersion{}
+
+	r.recordEvent(common.PhaseAppDeployment, appVersion, reasons.Finished)
+	testrequire.Empty(t, appVersion.Status.Events)
+
+	r.StatusEventsLimit = 5
+	r.recordEvent(common.PhaseAppDeployment, appVersion, reasons.Finished)
+	testrequire.Len(t, appVersion.Status.Events, 1)
+	testrequire.Equal(t, "AppDeployFinished", appVersion.Status.Events[0].Reason)
+}
@@ -0,0 +1,26 @@
+package keptnappversion
+
+import (
+	klcv1alpha1 "github.com/keptn/lifecycle-controller/operator/api/v1alpha1"
+	"github.com/keptn/lifecycle-controller/operator/api/v1alpha1/common"
+	"github.com/keptn/lifecycle-controller/operator/controllers/reasons"
+)
+
+// skipEmptyPhases marks the app-level phases of app versions on the fast path as succeeded, so that neither the
+// workload waits for the empty pre-deployment phases, nor the app version for its empty post-deployment phases.
+// It returns whether the app version is on the fast path.
+func (r *KeptnAppVersionReconciler) skipEmptyPhases(appVersion *klcv1alpha1.KeptnAppVersion) bool {
+	if !appVersion.HasFastPath() {
+		return false
+	}
+	if !appVersion.IsPreDeploymentSucceeded() || !appVersion.IsPreDeploymentEvaluationSucceeded() {
+		appVersion.Status.PreDeploymentStatus = common.StateSucceeded
+		appVersion.Status.PreDeploymentEvaluationStatus = common.StateSucceeded
+		r.recordEvent(common.PhaseAppPreDeployment, appVersion, reasons.SkippedSingleWorkload)
+	}
+	if appVersion.AreWorkloadsSucceeded() {
+		appVersion.Status.PostDeploymentStatus = common.StateSucceeded
+		appVersion.Status.PostDeploymentEvaluationStatus = common.StateSucceeded
+	}
+	return true
+}
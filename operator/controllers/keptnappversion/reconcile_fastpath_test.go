@@ -0,0 +1,51 @@
+package keptnappversion
+
+import (
+	"testing"
+
+	klcv1alpha1 "github.com/keptn/lifecycle-controller/operator/api/v1alpha1"
+	"github.com/keptn/lifecycle-controller/operator/api/v1alpha1/common"
+	testrequire "github.com/stretchr/testify/require"
+	"k8s.io/client-go/tools/record"
+)
+
+func TestSkipEmptyPhases(t *testing.T) {
+	r := &KeptnAppVersionReconciler{Recorder: record.NewFakeRecorder(10)}
+	appVersion := &klcv1alpha1.KeptnAppVersion{
+		Spec: klcv1alpha1.KeptnAppVersionSpec{
+			KeptnAppSpec: klcv1alpha1.KeptnAppSpec{
+				Workloads: []klcv1alpha1.KeptnWorkloadRef{{Name: "podtato-head", Version: "1.0.0"}},
+			},
+		},
+	}
+
+	// the app version has not started yet, e.g. since it waits for a previous version
+	testrequire.True(t, appVersion.HasFastPath())
+	testrequire.False(t, appVersion.HasStartedFastPath())
+
+	testrequire.True(t, r.skipEmptyPhases(appVersion))
+	testrequire.True(t, appVersion.IsPreDeploymentSucceeded())
+	testrequire.True(t, appVersion.IsPreDeploymentEvaluationSucceeded())
+	testrequire.False(t, appVersion.IsPostDeploymentSucceeded())
+
+	appVersion.Status.CurrentPhase = common.PhaseAppDeployment.ShortName
+	testrequire.True(t, appVersion.HasStartedFastPath())
+
+	appVersion.Status.WorkloadOverallStatus = common.StateSucceeded
+	testrequire.True(t, r.skipEmptyPhases(appVersion))
+	testrequire.True(t, appVersion.IsPostDeploymentSucceeded())
+	testrequire.True(t, appVersion.IsPostDeploymentEvaluationSucceeded())
+
+	withTasks := &klcv1alpha1.KeptnAppVersion{
+		Spec: klcv1alpha1.KeptnAppVersionSpec{
+			KeptnAppSpec: klcv1alpha1.KeptnAppSpec{
+				Workloads:           []klcv1alpha1.KeptnWorkloadRef{{Name: "podtato-head", Version: "1.0.0"}},
+				PostDeploymentTasks: []string{"notify"},
+			},
+		},
+	}
+	withTasks.Status.CurrentPhase = common.PhaseAppDeployment.ShortName
+	testrequire.False(t, withTasks.HasStartedFastPath())
+	testrequire.False(t, r.skipEmptyPhases(withTasks))
+	testrequire.False(t, withTasks.IsPreDeploymentSucceeded())
+}
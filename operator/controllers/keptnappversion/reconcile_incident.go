@@ -0,0 +1,88 @@
+package keptnappversion
+
+import (
+	"context"
+	"fmt"
+
+	klcv1alpha1 "github.com/keptn/lifecycle-controller/operator/api/v1alpha1"
+	"github.com/keptn/lifecycle-controller/operator/api/v1alpha1/common"
+	"github.com/keptn/lifecycle-controller/operator/controllers/environment"
+	"github.com/keptn/lifecycle-controller/operator/controllers/reasons"
+	"github.com/keptn/lifecycle-controller/operator/integrations/incident"
+	"go.opentelemetry.io/otel/trace"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// openIncident opens an incident for app versions in production namespaces that failed their post-deployment evaluation
+func (r *KeptnAppVersionReconciler) openIncident(ctx context.Context, ctxAppTrace context.Context, appVersion *klcv1alpha1.KeptnAppVersion) {
+	if r.IncidentManager == nil || !r.isProductionNamespace(ctx, appVersion.Namespace) {
+		return
+	}
+
+	phase := common.KeptnPhaseType{
+		ShortName: "Incident",
+		LongName:  "Incident",
+	}
+
+	newIncident := incident.Incident{
+		Key:              getIncidentKey(appVersion),
+		Summary:          fmt.Sprintf("Post-deployment evaluation of %s version %s in namespace %s failed", appVersion.Spec.AppName, appVersion.Spec.Version, appVersion.Namespace),
+		Source:           "keptn-lifecycle-controller",
+		TraceID:          trace.SpanContextFromContext(ctxAppTrace).TraceID().String(),
+		FailedObjectives: incident.FailedObjectives(ctx, r.Client, r.Log, appVersion.Namespace, appVersion.Status.PostDeploymentEvaluationTaskStatus),
+	}
+
+	if err := r.IncidentManager.Open(ctx, newIncident); err != nil {
+		r.Log.Error(err, "could not open incident")
+		r.recordEvent(phase, appVersion, reasons.IncidentOpenFailed)
+		return
+	}
+	appVersion.Status.IncidentKey = newIncident.Key
+	r.recordEvent(phase, appVersion, reasons.IncidentOpened)
+}
+
+// resolveIncidents resolves the incidents opened for earlier versions of the app once a version of it has been deployed
+// successfully. It is called after the status of the app version has been persisted, and removes the key of a resolved
+// incident from the status of the versions it has been opened for, so that it is resolved only once.
+func (r *KeptnAppVersionReconciler) resolveIncidents(ctx context.Context, appVersion *klcv1alpha1.KeptnAppVersion) {
+	if r.IncidentManager == nil {
+		return
+	}
+	appVersions := &klcv1alpha1.KeptnAppVersionList{}
+	if err := r.Client.List(ctx, appVersions, client.InNamespace(appVersion.Namespace)); err != nil {
+		r.Log.Error(err, "could not retrieve KeptnAppVersions")
+		return
+	}
+
+	resolved := map[string]bool{}
+	for i := range appVersions.Items {
+		failedVersion := &appVersions.Items[i]
+		key := failedVersion.Status.IncidentKey
+		if failedVersion.Spec.AppName != appVersion.Spec.AppName || key == "" {
+			continue
+		}
+		if !resolved[key] {
+			if err := r.IncidentManager.Resolve(ctx, key); err != nil {
+				r.Log.Error(err, "could not resolve incident")
+				return
+			}
+			resolved[key] = true
+		}
+		failedVersion.Status.IncidentKey = ""
+		if err := r.Client.Status().Update(ctx, failedVersion); err != nil {
+			r.Log.Error(err, "could not remove the resolved incident from the status")
+		}
+	}
+}
+
+func (r *KeptnAppVersionReconciler) isProductionNamespace(ctx context.Context, namespace string) bool {
+	production, err := environment.IsProduction(ctx, r.Client, namespace)
+	if err != nil {
+		r.Log.Error(err, "could not fetch namespace")
+	}
+	return production
+}
+
+func getIncidentKey(appVersion *klcv1alpha1.KeptnAppVersion) string {
+	return fmt.Sprintf("keptn/%s/%s", appVersion.Namespace, appVersion.Spec.AppName)
+}
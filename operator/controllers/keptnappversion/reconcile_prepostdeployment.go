@@ -2,7 +2,6 @@ package keptnappversion
 
 import (
 	"context"
-	"fmt"
 
 	"github.com/keptn/lifecycle-controller/operator/api/v1alpha1/semconv"
 	"go.opentelemetry.io/otel"
@@ -15,6 +14,11 @@ import (
 
 	klcv1alpha1 "github.com/keptn/lifecycle-controller/operator/api/v1alpha1"
 	"github.com/keptn/lifecycle-controller/operator/api/v1alpha1/common"
+	"github.com/keptn/lifecycle-controller/operator/controllers/apply"
+	"github.com/keptn/lifecycle-controller/operator/controllers/deadline"
+	"github.com/keptn/lifecycle-controller/operator/controllers/environment"
+	"github.com/keptn/lifecycle-controller/operator/controllers/reasons"
+	"github.com/keptn/lifecycle-controller/operator/controllers/taskgraph"
 )
 
 func (r *KeptnAppVersionReconciler) reconcilePrePostDeployment(ctx context.Context, appVersion *klcv1alpha1.KeptnAppVersion, checkType common.CheckType) (common.KeptnState, error) {
@@ -59,6 +63,12 @@ func (r *KeptnAppVersionReconciler) reconcileTasks(ctx context.Context, checkTyp
 		statuses = appVersion.Status.PostDeploymentTaskStatus
 	}
 
+	// tasks are only started once the tasks they depend on have succeeded
+	graph, err := taskgraph.Build(ctx, r.Client, appVersion.Namespace, tasks)
+	if err != nil {
+		return nil, common.StatusSummary{}, err
+	}
+
 	var summary common.StatusSummary
 	summary.Total = len(tasks)
 	// Check current state of the PrePostDeploymentTasks
@@ -76,7 +86,7 @@ func (r *KeptnAppVersionReconciler) reconcileTasks(ctx context.Context, checkTyp
 		taskExists := false
 
 		if oldstatus != taskStatus.Status {
-			r.recordEvent(phase, "Normal", appVersion, "TaskStatusChanged", fmt.Sprintf("task status changed from %s to %s", oldstatus, taskStatus.Status))
+			r.recordEvent(phase, appVersion, reasons.TaskStatusChanged, oldstatus, taskStatus.Status)
 		}
 
 		// Check if task has already succeeded or failed
@@ -98,6 +108,18 @@ func (r *KeptnAppVersionReconciler) reconcileTasks(ctx context.Context, checkTyp
 
 		// Create new Task if it does not exist
 		if !taskExists {
+			reason, failed := graph.Blocked(taskDefinitionName, taskgraph.States(statuses, newStatus))
+			if reason != "" {
+				if failed {
+					taskStatus.Status = common.StateFailed
+					taskStatus.SetEndTime()
+					r.recordEvent(phase, appVersion, reasons.TaskDependencyFailed, taskDefinitionName, reason)
+				}
+				taskStatus.Reason = reason
+				newStatus = append(newStatus, taskStatus)
+				continue
+			}
+			taskStatus.Reason = ""
 			taskName, err := r.createKeptnTask(ctx, appVersion.Namespace, appVersion, taskDefinitionName, checkType)
 			if err != nil {
 				return nil, summary, err
@@ -119,14 +141,15 @@ func (r *KeptnAppVersionReconciler) reconcileTasks(ctx context.Context, checkTyp
 		summary = common.UpdateStatusSummary(ns.Status, summary)
 	}
 	if common.GetOverallState(summary) != common.StateSucceeded {
-		r.recordEvent(phase, "Warning", appVersion, "NotFinished", "has not finished")
+		r.recordEvent(phase, appVersion, reasons.NotFinished)
 	}
 	return newStatus, summary, nil
 }
 
 func (r *KeptnAppVersionReconciler) createKeptnTask(ctx context.Context, namespace string, appVersion *klcv1alpha1.KeptnAppVersion, taskDefinition string, checkType common.CheckType) (string, error) {
 
-	ctx, span := r.Tracer.Start(ctx, "create_app_task", trace.WithSpanKind(trace.SpanKindProducer))
+	phaseTraceParent := semconv.TraceParent(ctx)
+	ctx, span := r.Tracer.Start(ctx, semconv.CreateTaskSpanName, trace.WithSpanKind(trace.SpanKindProducer))
 	defer span.End()
 
 	semconv.AddAttributeFromAppVersion(span, *appVersion)
@@ -135,17 +158,25 @@ func (r *KeptnAppVersionReconciler) createKeptnTask(ctx context.Context, namespa
 	// follow up with a Keptn propagator that JSON-encoded the OTel map into our own key
 	traceContextCarrier := propagation.MapCarrier{}
 	otel.GetTextMapPropagator().Inject(ctx, traceContextCarrier)
+	traceContextCarrier[common.PhaseTraceParentAnnotation] = phaseTraceParent
 
 	phase := common.KeptnPhaseType{
 		ShortName: "KeptnTaskCreate",
 		LongName:  "Keptn Task Create",
 	}
 
+	// the task must not outlive the deadline of the app version
+	taskDeadline, err := deadline.Of(ctx, r.Client, appVersion.Namespace, appVersion.Status.StartTime)
+	if err != nil {
+		r.Log.Error(err, "could not resolve deadline of KeptnTask")
+	}
+
 	newTask := &klcv1alpha1.KeptnTask{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:        common.GenerateTaskName(checkType, taskDefinition),
 			Namespace:   namespace,
 			Annotations: traceContextCarrier,
+			Labels:      environment.Labels(nil, appVersion.Labels[common.EnvironmentLabel]),
 		},
 		Spec: klcv1alpha1.KeptnTaskSpec{
 			AppVersion:       appVersion.Spec.Version,
@@ -154,19 +185,21 @@ func (r *KeptnAppVersionReconciler) createKeptnTask(ctx context.Context, namespa
 			Parameters:       klcv1alpha1.TaskParameters{},
 			SecureParameters: klcv1alpha1.SecureParameters{},
 			Type:             checkType,
+			Deadline:         taskDeadline,
 		},
 	}
-	err := controllerutil.SetControllerReference(appVersion, newTask, r.Scheme)
+	r.Propagation.Copy(appVersion.ObjectMeta, &newTask.ObjectMeta)
+	err = controllerutil.SetControllerReference(appVersion, newTask, r.Scheme)
 	if err != nil {
 		r.Log.Error(err, "could not set controller reference:")
 	}
-	err = r.Client.Create(ctx, newTask)
+	err = apply.Apply(ctx, r.Client, newTask, r.Recorder, appVersion)
 	if err != nil {
 		r.Log.Error(err, "could not create KeptnTask")
-		r.recordEvent(phase, "Warning", appVersion, "CreateFailed", "could not create KeptnTask")
+		r.recordEvent(phase, appVersion, reasons.CreateFailed, "KeptnTask")
 		return "", err
 	}
-	r.recordEvent(phase, "Normal", appVersion, "Created", "created")
+	r.recordEvent(phase, appVersion, reasons.ChecksCreated)
 
 	return newTask.Name, nil
 }
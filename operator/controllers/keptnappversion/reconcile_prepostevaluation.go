@@ -2,12 +2,15 @@ package keptnappversion
 
 import (
 	"context"
-	"fmt"
 	"time"
 
 	klcv1alpha1 "github.com/keptn/lifecycle-controller/operator/api/v1alpha1"
 	"github.com/keptn/lifecycle-controller/operator/api/v1alpha1/common"
 	"github.com/keptn/lifecycle-controller/operator/api/v1alpha1/semconv"
+	"github.com/keptn/lifecycle-controller/operator/controllers/apply"
+	"github.com/keptn/lifecycle-controller/operator/controllers/deadline"
+	"github.com/keptn/lifecycle-controller/operator/controllers/environment"
+	"github.com/keptn/lifecycle-controller/operator/controllers/reasons"
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/propagation"
 	"go.opentelemetry.io/otel/trace"
@@ -76,7 +79,7 @@ func (r *KeptnAppVersionReconciler) reconcileEvaluations(ctx context.Context, ch
 		evaluationExists := false
 
 		if oldstatus != evaluationStatus.Status {
-			r.recordEvent(phase, "Normal", appVersion, "EvaluationStatusChanged", fmt.Sprintf("evaluation status changed from %s to %s", oldstatus, evaluationStatus.Status))
+			r.recordEvent(phase, appVersion, reasons.EvaluationStatusChanged, oldstatus, evaluationStatus.Status)
 		}
 
 		// Check if evaluation has already succeeded or failed
@@ -119,14 +122,14 @@ func (r *KeptnAppVersionReconciler) reconcileEvaluations(ctx context.Context, ch
 		summary = common.UpdateStatusSummary(ns.Status, summary)
 	}
 	if common.GetOverallState(summary) != common.StateSucceeded {
-		r.recordEvent(phase, "Warning", appVersion, "NotFinished", "has not finished")
+		r.recordEvent(phase, appVersion, reasons.NotFinished)
 	}
 	return newStatus, summary, nil
 }
 
 func (r *KeptnAppVersionReconciler) createKeptnEvaluation(ctx context.Context, namespace string, appVersion *klcv1alpha1.KeptnAppVersion, evaluationDefinition string, checkType common.CheckType) (string, error) {
 
-	ctx, span := r.Tracer.Start(ctx, "create_app_evaluation", trace.WithSpanKind(trace.SpanKindProducer))
+	ctx, span := r.Tracer.Start(ctx, semconv.CreateEvaluationSpanName, trace.WithSpanKind(trace.SpanKindProducer))
 	defer span.End()
 
 	semconv.AddAttributeFromAppVersion(span, *appVersion)
@@ -141,11 +144,18 @@ func (r *KeptnAppVersionReconciler) createKeptnEvaluation(ctx context.Context, n
 		LongName:  "Keptn Evaluation Create",
 	}
 
+	// the evaluation must not outlive the deadline of the app version
+	evaluationDeadline, err := deadline.Of(ctx, r.Client, appVersion.Namespace, appVersion.Status.StartTime)
+	if err != nil {
+		r.Log.Error(err, "could not resolve deadline of KeptnEvaluation")
+	}
+
 	newEvaluation := &klcv1alpha1.KeptnEvaluation{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:        common.GenerateEvaluationName(checkType, evaluationDefinition),
 			Namespace:   namespace,
 			Annotations: traceContextCarrier,
+			Labels:      environment.Labels(nil, appVersion.Labels[common.EnvironmentLabel]),
 		},
 		Spec: klcv1alpha1.KeptnEvaluationSpec{
 			AppVersion:           appVersion.Spec.Version,
@@ -155,19 +165,20 @@ func (r *KeptnAppVersionReconciler) createKeptnEvaluation(ctx context.Context, n
 			RetryInterval: metav1.Duration{
 				Duration: 5 * time.Second,
 			},
+			Deadline: evaluationDeadline,
 		},
 	}
-	err := controllerutil.SetControllerReference(appVersion, newEvaluation, r.Scheme)
+	err = controllerutil.SetControllerReference(appVersion, newEvaluation, r.Scheme)
 	if err != nil {
 		r.Log.Error(err, "could not set controller reference:")
 	}
-	err = r.Client.Create(ctx, newEvaluation)
+	err = apply.Apply(ctx, r.Client, newEvaluation, r.Recorder, appVersion)
 	if err != nil {
 		r.Log.Error(err, "could not create KeptnEvaluation")
-		r.recordEvent(phase, "Warning", appVersion, "CreateFailed", "could not create KeptnEvaluation")
+		r.recordEvent(phase, appVersion, reasons.CreateFailed, "KeptnEvaluation")
 		return "", err
 	}
-	r.recordEvent(phase, "Normal", appVersion, "Created", "created")
+	r.recordEvent(phase, appVersion, reasons.ChecksCreated)
 
 	return newEvaluation.Name, nil
 }
@@ -0,0 +1,44 @@
+package keptnappversion
+
+import (
+	"context"
+
+	klcv1alpha1 "github.com/keptn/lifecycle-controller/operator/api/v1alpha1"
+	"github.com/keptn/lifecycle-controller/operator/api/v1alpha1/common"
+	"github.com/keptn/lifecycle-controller/operator/controllers/reasons"
+	"github.com/keptn/lifecycle-controller/operator/controllers/rollback"
+)
+
+// rollbackOnFailure rolls the workloads of the app version back to their previous versions if its post-deployment
+// evaluations failed and spec.rollbackOnFailure is set. Workloads without a previous version are left as they are.
+func (r *KeptnAppVersionReconciler) rollbackOnFailure(ctx context.Context, appVersion *klcv1alpha1.KeptnAppVersion) {
+	phase := common.PhaseAppPostEvaluation
+	if !appVersion.Spec.RollbackOnFailure {
+		return
+	}
+	if !r.RollbackEnabled {
+		r.recordEvent(phase, appVersion, reasons.RollbackDisabled)
+		return
+	}
+	for _, w := range appVersion.Spec.Workloads {
+		workloadInstance, err := r.getWorkloadInstance(ctx, appVersion, w)
+		if err != nil {
+			r.Log.Error(err, "could not get workload instance to roll back", "workload", w.Name)
+			continue
+		}
+		if workloadInstance.Spec.PreviousVersion == "" || workloadInstance.Status.RollbackInstance != "" {
+			continue
+		}
+		rollbackInstance, err := rollback.Rollback(ctx, r.Client, &workloadInstance)
+		if err != nil {
+			r.Log.Error(err, "could not roll back workload", "workload", w.Name)
+			r.recordEvent(phase, appVersion, reasons.RollbackFailed, "workload "+w.Name, err)
+			continue
+		}
+		workloadInstance.Status.RollbackInstance = rollbackInstance.Name
+		if err := r.Status().Update(ctx, &workloadInstance); err != nil {
+			r.Log.Error(err, "could not update status of workload instance", "workload", w.Name)
+		}
+		r.recordEvent(phase, appVersion, reasons.RolledBack, "workload "+w.Name, workloadInstance.Spec.PreviousVersion)
+	}
+}
@@ -0,0 +1,66 @@
+package keptnappversion
+
+import (
+	"context"
+	"fmt"
+
+	klcv1alpha1 "github.com/keptn/lifecycle-controller/operator/api/v1alpha1"
+	"github.com/keptn/lifecycle-controller/operator/controllers/reasons"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// waitForPreviousVersions keeps an app version from starting while an earlier version of the same app is still in
+// progress, unless the app allows parallel versions. It returns whether the app version has to wait.
+// Versions that have already started are never held back, and aborted versions are cancelled right away.
+func (r *KeptnAppVersionReconciler) waitForPreviousVersions(ctx context.Context, appVersion *klcv1alpha1.KeptnAppVersion) (bool, error) {
+	if appVersion.Spec.AllowParallelVersions || appVersion.Status.CurrentPhase != "" || appVersion.Status.Status.IsCompleted() || appVersion.IsAbortRequested() {
+		return false, nil
+	}
+
+	appVersions := &klcv1alpha1.KeptnAppVersionList{}
+	if err := r.Client.List(ctx, appVersions, client.InNamespace(appVersion.Namespace)); err != nil {
+		return false, fmt.Errorf("could not list KeptnAppVersions: %w", err)
+	}
+	previous := previousVersionInProgress(appVersions.Items, appVersion)
+	reason := ""
+	if previous != nil {
+		reason = fmt.Sprintf("waiting for app version %s to complete", previous.Name)
+	}
+	if appVersion.Status.Reason == reason {
+		return previous != nil, nil
+	}
+
+	appVersion.Status.Reason = reason
+	if previous != nil {
+		r.recordEvent(phaseAppVersion, appVersion, reasons.WaitingForPreviousVersion, previous.Name)
+	}
+	if err := r.Client.Status().Update(ctx, appVersion); err != nil {
+		return true, fmt.Errorf("could not update status of KeptnAppVersion %s: %w", appVersion.Name, err)
+	}
+	return previous != nil, nil
+}
+
+// previousVersionInProgress returns the oldest version of the app of the given app version that has been created
+// before it and has neither completed nor been deleted, or nil if there is none
+func previousVersionInProgress(appVersions []klcv1alpha1.KeptnAppVersion, appVersion *klcv1alpha1.KeptnAppVersion) *klcv1alpha1.KeptnAppVersion {
+	var res *klcv1alpha1.KeptnAppVersion
+	for i := range appVersions {
+		other := &appVersions[i]
+		if other.Spec.AppName != appVersion.Spec.AppName || other.DeletionTimestamp != nil || other.Status.Status.IsCompleted() {
+			continue
+		}
+		if createdBefore(other, appVersion) && (res == nil || createdBefore(other, res)) {
+			res = other
+		}
+	}
+	return res
+}
+
+// createdBefore returns whether an app version has been created before another one, ordering app versions created
+// within the same second by name
+func createdBefore(a *klcv1alpha1.KeptnAppVersion, b *klcv1alpha1.KeptnAppVersion) bool {
+	if !a.CreationTimestamp.Equal(&b.CreationTimestamp) {
+		return a.CreationTimestamp.Before(&b.CreationTimestamp)
+	}
+	return a.Name < b.Name
+}
@@ -0,0 +1,40 @@
+package keptnappversion
+
+import (
+	"testing"
+	"time"
+
+	klcv1alpha1 "github.com/keptn/lifecycle-controller/operator/api/v1alpha1"
+	"github.com/keptn/lifecycle-controller/operator/api/v1alpha1/common"
+	testrequire "github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestPreviousVersionInProgress(t *testing.T) {
+	created := time.Date(2022, 11, 8, 12, 0, 0, 0, time.UTC)
+	appVersion := func(name string, app string, minute int, status common.KeptnState) klcv1alpha1.KeptnAppVersion {
+		return klcv1alpha1.KeptnAppVersion{
+			ObjectMeta: metav1.ObjectMeta{Name: name, CreationTimestamp: metav1.NewTime(created.Add(time.Duration(minute) * time.Minute))},
+			Spec:       klcv1alpha1.KeptnAppVersionSpec{AppName: app},
+			Status:     klcv1alpha1.KeptnAppVersionStatus{Status: status},
+		}
+	}
+	appVersions := []klcv1alpha1.KeptnAppVersion{
+		appVersion("podtato-head-1.0.0", "podtato-head", 0, common.StateSucceeded),
+		appVersion("podtato-head-1.1.0", "podtato-head", 1, common.StateProgressing),
+		appVersion("podtato-head-1.2.0", "podtato-head", 2, common.StatePending),
+		appVersion("podtato-head-1.3.0", "podtato-head", 3, common.StatePending),
+		appVersion("other-1.0.0", "other", 0, common.StateProgressing),
+	}
+
+	previous := previousVersionInProgress(appVersions, &appVersions[3])
+	testrequire.NotNil(t, previous)
+	testrequire.Equal(t, "podtato-head-1.1.0", previous.Name)
+
+	testrequire.Nil(t, previousVersionInProgress(appVersions, &appVersions[1]))
+
+	appVersions[1].Status.Status = common.StateCancelled
+	previous = previousVersionInProgress(appVersions, &appVersions[3])
+	testrequire.NotNil(t, previous)
+	testrequire.Equal(t, "podtato-head-1.2.0", previous.Name)
+}
@@ -0,0 +1,23 @@
+package keptnappversion
+
+import (
+	"context"
+	"fmt"
+
+	klcv1alpha1 "github.com/keptn/lifecycle-controller/operator/api/v1alpha1"
+	"github.com/keptn/lifecycle-controller/operator/api/v1alpha1/common"
+	"github.com/keptn/lifecycle-controller/operator/controllers/deadline"
+	"github.com/keptn/lifecycle-controller/operator/controllers/reasons"
+)
+
+// simulateFailure returns a reconciliation of the given phase which fails it right away, without running its tasks,
+// evaluations or checks, since its failure is simulated
+func (r *KeptnAppVersionReconciler) simulateFailure(appVersion *klcv1alpha1.KeptnAppVersion, phase common.KeptnPhaseType) func(context.Context) (common.KeptnState, error) {
+	return func(context.Context) (common.KeptnState, error) {
+		status := &appVersion.Status
+		deadline.FailPhase(&status.PreDeploymentStatus, &status.PreDeploymentEvaluationStatus, &status.WorkloadOverallStatus, &status.PostDeploymentStatus, &status.PostDeploymentEvaluationStatus)
+		status.Reason = fmt.Sprintf("failure of %s is simulated with %s", phase.LongName, common.SimulateFailureAnnotation)
+		r.recordEvent(phase, appVersion, reasons.FailureSimulated)
+		return common.StateFailed, nil
+	}
+}
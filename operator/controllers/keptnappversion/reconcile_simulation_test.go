@@ -0,0 +1,43 @@
+package keptnappversion
+
+import (
+	"context"
+	"testing"
+
+	klcv1alpha1 "github.com/keptn/lifecycle-controller/operator/api/v1alpha1"
+	"github.com/keptn/lifecycle-controller/operator/api/v1alpha1/common"
+	testrequire "github.com/stretchr/testify/require"
+	"k8s.io/client-go/tools/record"
+)
+
+func TestSimulateFailure(t *testing.T) {
+	recorder := record.NewFakeRecorder(10)
+	r := &KeptnAppVersionReconciler{Recorder: recorder}
+	appVersion := &klcv1alpha1.KeptnAppVersion{
+		Spec: klcv1alpha1.KeptnAppVersionSpec{
+			KeptnAppSpec: klcv1alpha1.KeptnAppSpec{
+				Workloads:       []klcv1alpha1.KeptnWorkloadRef{{Name: "podtato-head", Version: "1.0.0"}},
+				SimulateFailure: klcv1alpha1.SimulatedFailurePostDeploymentEvaluation,
+			},
+		},
+		Status: klcv1alpha1.KeptnAppVersionStatus{
+			PreDeploymentStatus:           common.StateSucceeded,
+			PreDeploymentEvaluationStatus: common.StateSucceeded,
+			WorkloadOverallStatus:         common.StateSucceeded,
+			PostDeploymentStatus:          common.StateSucceeded,
+		},
+	}
+
+	// the app-level phases of an app version simulating a failure are not skipped
+	testrequire.False(t, appVersion.HasFastPath())
+	testrequire.False(t, appVersion.Spec.SimulateFailure.Matches(common.PhaseAppPostDeployment))
+	testrequire.True(t, appVersion.Spec.SimulateFailure.Matches(common.PhaseAppPostEvaluation))
+
+	state, err := r.simulateFailure(appVersion, common.PhaseAppPostEvaluation)(context.TODO())
+	testrequire.Nil(t, err)
+	testrequire.True(t, state.IsFailed())
+	testrequire.True(t, appVersion.IsPostDeploymentEvaluationFailed())
+	testrequire.True(t, appVersion.IsPostDeploymentSucceeded())
+	testrequire.NotEmpty(t, appVersion.Status.Reason)
+	testrequire.Contains(t, <-recorder.Events, "AppPostDeployEvaluationsFailureSimulated")
+}
@@ -0,0 +1,29 @@
+package keptnappversion
+
+import (
+	"context"
+
+	klcv1alpha1 "github.com/keptn/lifecycle-controller/operator/api/v1alpha1"
+	"github.com/keptn/lifecycle-controller/operator/api/v1alpha1/common"
+	"github.com/keptn/lifecycle-controller/operator/controllers/reasons"
+)
+
+// skipPhase returns a reconciliation of the given phase which lets it succeed right away, without running its tasks
+// or evaluations, since the phase is skipped
+func (r *KeptnAppVersionReconciler) skipPhase(appVersion *klcv1alpha1.KeptnAppVersion, phase common.KeptnPhaseType) func(context.Context) (common.KeptnState, error) {
+	return func(context.Context) (common.KeptnState, error) {
+		status := &appVersion.Status
+		switch phase {
+		case common.PhaseAppPreDeployment:
+			status.PreDeploymentStatus = common.StateSucceeded
+		case common.PhaseAppPreEvaluation:
+			status.PreDeploymentEvaluationStatus = common.StateSucceeded
+		case common.PhaseAppPostDeployment:
+			status.PostDeploymentStatus = common.StateSucceeded
+		case common.PhaseAppPostEvaluation:
+			status.PostDeploymentEvaluationStatus = common.StateSucceeded
+		}
+		r.recordEvent(phase, appVersion, reasons.Skipped)
+		return common.StateSucceeded, nil
+	}
+}
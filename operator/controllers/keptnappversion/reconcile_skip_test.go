@@ -0,0 +1,39 @@
+package keptnappversion
+
+import (
+	"context"
+	"testing"
+
+	klcv1alpha1 "github.com/keptn/lifecycle-controller/operator/api/v1alpha1"
+	"github.com/keptn/lifecycle-controller/operator/api/v1alpha1/common"
+	testrequire "github.com/stretchr/testify/require"
+	"k8s.io/client-go/tools/record"
+)
+
+func TestSkipPhase(t *testing.T) {
+	recorder := record.NewFakeRecorder(10)
+	r := &KeptnAppVersionReconciler{Recorder: recorder}
+	appVersion := &klcv1alpha1.KeptnAppVersion{
+		Spec: klcv1alpha1.KeptnAppVersionSpec{
+			KeptnAppSpec: klcv1alpha1.KeptnAppSpec{
+				PreDeploymentEvaluations: []string{"slo"},
+				SkipPhases:               []klcv1alpha1.SkippedPhase{"pre-deployment-evaluation", "deployment"},
+			},
+		},
+		Status: klcv1alpha1.KeptnAppVersionStatus{
+			PreDeploymentStatus: common.StateSucceeded,
+		},
+	}
+
+	testrequire.True(t, klcv1alpha1.SkipsPhase(appVersion.Spec.SkipPhases, common.PhaseAppPreEvaluation))
+	testrequire.False(t, klcv1alpha1.SkipsPhase(appVersion.Spec.SkipPhases, common.PhaseAppPreDeployment))
+	// the deployment itself cannot be skipped
+	testrequire.False(t, klcv1alpha1.SkipsPhase(appVersion.Spec.SkipPhases, common.PhaseAppDeployment))
+
+	state, err := r.skipPhase(appVersion, common.PhaseAppPreEvaluation)(context.TODO())
+	testrequire.Nil(t, err)
+	testrequire.True(t, state.IsSucceeded())
+	testrequire.True(t, appVersion.IsPreDeploymentEvaluationSucceeded())
+	testrequire.Empty(t, appVersion.Status.PreDeploymentEvaluationTaskStatus)
+	testrequire.Contains(t, <-recorder.Events, "AppPreDeployEvaluationsSkipped")
+}
@@ -0,0 +1,48 @@
+package keptnappversion
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	klcv1alpha1 "github.com/keptn/lifecycle-controller/operator/api/v1alpha1"
+	"github.com/keptn/lifecycle-controller/operator/api/v1alpha1/common"
+	"github.com/keptn/lifecycle-controller/operator/controllers/deadline"
+	"github.com/keptn/lifecycle-controller/operator/controllers/reasons"
+	"github.com/keptn/lifecycle-controller/operator/metrics"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// reconcilePhaseTimeout fails the app version if the given phase runs longer than its timeout, and deletes the tasks
+// and evaluations that are still running. The span of the phase is ended and the span of the app version is marked as
+// failed. It returns whether the timeout has been exceeded.
+func (r *KeptnAppVersionReconciler) reconcilePhaseTimeout(ctx context.Context, appVersion *klcv1alpha1.KeptnAppVersion, phase common.KeptnPhaseType, span trace.Span, spanPhase trace.Span) (bool, error) {
+	timeout := deadline.PhaseTimeout(appVersion.Spec.Timeouts, phase)
+	if !deadline.Exceeded(appVersion.Status.PhaseStartTime, timeout, time.Now()) {
+		return false, nil
+	}
+
+	status := &appVersion.Status
+	if err := deadline.Cleanup(ctx, r.Client, appVersion.Namespace, status.PreDeploymentTaskStatus, status.PreDeploymentEvaluationTaskStatus); err != nil {
+		return true, err
+	}
+	if err := deadline.Cleanup(ctx, r.Client, appVersion.Namespace, status.PostDeploymentTaskStatus, status.PostDeploymentEvaluationTaskStatus); err != nil {
+		return true, err
+	}
+	deadline.FailPhase(&status.PreDeploymentStatus, &status.PreDeploymentEvaluationStatus, &status.WorkloadOverallStatus, &status.PostDeploymentStatus, &status.PostDeploymentEvaluationStatus)
+	status.Status = common.StateFailed
+	status.Reason = fmt.Sprintf("%s exceeded the timeout of %s", phase.LongName, timeout)
+	appVersion.SetEndTime()
+
+	r.recordEvent(phase, appVersion, reasons.TimedOut, timeout)
+	r.Meters.Add(ctx, metrics.AppCount, 1, appVersion.GetMetricsAttributes()...)
+
+	spanPhase.AddEvent(phase.LongName + " has timed out")
+	spanPhase.SetStatus(codes.Error, reasons.TimedOut.Code)
+	spanPhase.End()
+	r.unbindSpan(appVersion, phase.ShortName)
+	span.SetStatus(codes.Error, reasons.TimedOut.Code)
+
+	return true, r.Client.Status().Update(ctx, appVersion)
+}
@@ -0,0 +1,102 @@
+package keptnappversion
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+	klcv1alpha1 "github.com/keptn/lifecycle-controller/operator/api/v1alpha1"
+	"github.com/keptn/lifecycle-controller/operator/api/v1alpha1/common"
+	"github.com/keptn/lifecycle-controller/operator/controllers/reasons"
+	"github.com/keptn/lifecycle-controller/operator/metrics"
+	testrequire "github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestKeptnAppVersionReconciler_ReconcilePhaseTimeout(t *testing.T) {
+	scheme := runtime.NewScheme()
+	testrequire.Nil(t, klcv1alpha1.AddToScheme(scheme))
+	spanRecorder := tracetest.NewSpanRecorder()
+	tracer := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(spanRecorder)).Tracer("test")
+	phase := common.PhaseAppPreDeployment
+
+	running := &klcv1alpha1.KeptnTask{ObjectMeta: metav1.ObjectMeta{Name: "pre-deployment-check", Namespace: "default"}}
+	appVersion := &klcv1alpha1.KeptnAppVersion{
+		ObjectMeta: metav1.ObjectMeta{Name: "podtato-head-1.0.0", Namespace: "default"},
+		Spec: klcv1alpha1.KeptnAppVersionSpec{
+			KeptnAppSpec: klcv1alpha1.KeptnAppSpec{
+				Version:  "1.0.0",
+				Timeouts: klcv1alpha1.PhaseTimeouts{PreDeployment: &metav1.Duration{Duration: time.Minute}},
+			},
+			AppName: "podtato-head",
+		},
+		Status: klcv1alpha1.KeptnAppVersionStatus{
+			CurrentPhase:            phase.ShortName,
+			PhaseStartTime:          metav1.NewTime(time.Now().Add(-2 * time.Minute)),
+			PreDeploymentStatus:     common.StateProgressing,
+			PreDeploymentTaskStatus: []klcv1alpha1.TaskStatus{{TaskName: running.Name, Status: common.StateProgressing}},
+		},
+	}
+	recorder := record.NewFakeRecorder(10)
+	meters := metrics.NewInMemoryMeters()
+	r := &KeptnAppVersionReconciler{
+		Client:   fake.NewClientBuilder().WithScheme(scheme).WithObjects(running).Build(),
+		Recorder: recorder,
+		Log:      logr.Discard(),
+		Tracer:   tracer,
+		Meters:   meters,
+	}
+
+	ctx, span := tracer.Start(context.TODO(), "reconcile_app_version")
+	_, spanPhase := r.getSpan(context.TODO(), appVersion, phase.ShortName)
+	// the app version is not stored, so only the status set in memory is checked
+	timedOut, _ := r.reconcilePhaseTimeout(ctx, appVersion, phase, span, spanPhase)
+	span.End()
+
+	testrequire.True(t, timedOut)
+	testrequire.Equal(t, common.StateFailed, appVersion.Status.Status)
+	testrequire.Equal(t, common.StateFailed, appVersion.Status.PreDeploymentStatus)
+	testrequire.Equal(t, common.StateFailed, appVersion.Status.PreDeploymentTaskStatus[0].Status)
+	testrequire.True(t, appVersion.IsEndTimeSet())
+	testrequire.True(t, errors.IsNotFound(r.Client.Get(context.TODO(), types.NamespacedName{Name: running.Name, Namespace: "default"}, &klcv1alpha1.KeptnTask{})))
+	testrequire.Contains(t, <-recorder.Events, phase.ShortName+reasons.TimedOut.Code)
+	testrequire.Equal(t, 1.0, meters.Sum(string(metrics.AppCount)))
+	testrequire.Empty(t, r.bindCRDSpan)
+
+	spans := map[string]sdktrace.ReadOnlySpan{}
+	for _, ended := range spanRecorder.Ended() {
+		spans[ended.Name()] = ended
+	}
+	testrequire.Len(t, spans, 2)
+	testrequire.Equal(t, codes.Error, spans[phase.ShortName].Status().Code)
+	testrequire.Equal(t, codes.Error, spans["reconcile_app_version"].Status().Code)
+	testrequire.Equal(t, reasons.TimedOut.Code, spans["reconcile_app_version"].Status().Description)
+}
+
+func TestKeptnAppVersionReconciler_ReconcilePhaseTimeoutNotExceeded(t *testing.T) {
+	phase := common.PhaseAppPreDeployment
+	appVersion := &klcv1alpha1.KeptnAppVersion{
+		Spec: klcv1alpha1.KeptnAppVersionSpec{
+			KeptnAppSpec: klcv1alpha1.KeptnAppSpec{Timeouts: klcv1alpha1.PhaseTimeouts{PreDeployment: &metav1.Duration{Duration: time.Hour}}},
+		},
+		Status: klcv1alpha1.KeptnAppVersionStatus{
+			PhaseStartTime:      metav1.NewTime(time.Now().Add(-2 * time.Minute)),
+			PreDeploymentStatus: common.StateProgressing,
+		},
+	}
+	r := &KeptnAppVersionReconciler{Recorder: record.NewFakeRecorder(10)}
+
+	timedOut, err := r.reconcilePhaseTimeout(context.TODO(), appVersion, phase, nil, nil)
+	testrequire.Nil(t, err)
+	testrequire.False(t, timedOut)
+	testrequire.Equal(t, common.StateProgressing, appVersion.Status.PreDeploymentStatus)
+}
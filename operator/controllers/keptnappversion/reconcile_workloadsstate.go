@@ -2,25 +2,33 @@ package keptnappversion
 
 import (
 	"context"
-	"fmt"
 
 	klcv1alpha1 "github.com/keptn/lifecycle-controller/operator/api/v1alpha1"
 	"github.com/keptn/lifecycle-controller/operator/api/v1alpha1/common"
+	"github.com/keptn/lifecycle-controller/operator/controllers/reasons"
 	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
 )
 
 func (r *KeptnAppVersionReconciler) reconcileWorkloads(ctx context.Context, appVersion *klcv1alpha1.KeptnAppVersion) (common.KeptnState, error) {
 	r.Log.Info("Reconciling Workloads")
-	var summary common.StatusSummary
-	summary.Total = len(appVersion.Spec.Workloads)
+	// optional workloads do not count towards the state of the deployment, their failure only results in a warning
+	var summary, optionalSummary common.StatusSummary
+	for _, w := range appVersion.Spec.Workloads {
+		if w.IsOptional() {
+			optionalSummary.Total++
+		} else {
+			summary.Total++
+		}
+	}
 
 	var newStatus []klcv1alpha1.WorkloadStatus
 	for _, w := range appVersion.Spec.Workloads {
 		r.Log.Info("Reconciling workload " + w.Name)
-		workload, err := r.getWorkloadInstance(ctx, getWorkloadInstanceName(appVersion.Namespace, appVersion.Spec.AppName, w.Name, w.Version))
+		workload, err := r.getWorkloadInstance(ctx, appVersion, w)
 		if err != nil && errors.IsNotFound(err) {
-			r.Recorder.Event(appVersion, "Warning", "WorkloadNotFound", fmt.Sprintf("Could not find KeptnWorkloadInstance / Namespace: %s, Name: %s ", appVersion.Namespace, w.Name))
+			r.recordEvent(phaseAppVersion, appVersion, reasons.WorkloadNotFound, w.Name)
 			workload.Status.Status = common.StatePending
 		} else if err != nil {
 			r.Log.Error(err, "Could not get workload")
@@ -32,10 +40,20 @@ func (r *KeptnAppVersionReconciler) reconcileWorkloads(ctx context.Context, appV
 			Workload: w,
 			Status:   workloadStatus,
 		})
-		summary = common.UpdateStatusSummary(workloadStatus, summary)
+		if w.IsOptional() {
+			optionalSummary = common.UpdateStatusSummary(workloadStatus, optionalSummary)
+		} else {
+			summary = common.UpdateStatusSummary(workloadStatus, summary)
+		}
 	}
 
 	overallState := common.GetOverallState(summary)
+	if appVersion.Spec.AllowPartialDeployment {
+		overallState = common.GetPartialOverallState(summary, getMinSucceededWorkloads(appVersion, summary.Total))
+	}
+	if overallState.IsSucceeded() {
+		overallState = common.GetOptionalOverallState(optionalSummary)
+	}
 	appVersion.Status.WorkloadOverallStatus = overallState
 	r.Log.Info("Overall state of workloads", "state", appVersion.Status.WorkloadOverallStatus)
 
@@ -47,12 +65,29 @@ func (r *KeptnAppVersionReconciler) reconcileWorkloads(ctx context.Context, appV
 	return overallState, err
 }
 
-func (r *KeptnAppVersionReconciler) getWorkloadInstance(ctx context.Context, workload types.NamespacedName) (klcv1alpha1.KeptnWorkloadInstance, error) {
+// getWorkloadInstance returns the workload instance of the workload of the app version, which must belong to the
+// workload and its version, since the names of other workloads or versions may collide with it
+func (r *KeptnAppVersionReconciler) getWorkloadInstance(ctx context.Context, appVersion *klcv1alpha1.KeptnAppVersion, workload klcv1alpha1.KeptnWorkloadRef) (klcv1alpha1.KeptnWorkloadInstance, error) {
+	workloadName := common.CreateResourceName(common.MaxK8sObjectLength, appVersion.Spec.AppName, workload.Name)
 	workloadInstance := &klcv1alpha1.KeptnWorkloadInstance{}
-	err := r.Get(ctx, workload, workloadInstance)
-	return *workloadInstance, err
+	err := r.Get(ctx, types.NamespacedName{Namespace: appVersion.Namespace, Name: common.CreateInstanceName(workloadName, workload.Version)}, workloadInstance)
+	if err != nil {
+		return *workloadInstance, err
+	}
+	return *workloadInstance, workloadInstance.VerifyIdentity(workloadName, workload.Version)
 }
 
-func getWorkloadInstanceName(namespace string, appName string, workloadName string, version string) types.NamespacedName {
-	return types.NamespacedName{Namespace: namespace, Name: appName + "-" + workloadName + "-" + version}
+// getMinSucceededWorkloads returns the number of the total critical workloads that have to succeed for a partial deployment
+func getMinSucceededWorkloads(appVersion *klcv1alpha1.KeptnAppVersion, total int) int {
+	if appVersion.Spec.MinSucceededWorkloads == nil {
+		return 1
+	}
+	min, err := intstr.GetScaledValueFromIntOrPercent(appVersion.Spec.MinSucceededWorkloads, total, true)
+	if err != nil || min < 1 {
+		return 1
+	}
+	if min > total {
+		return total
+	}
+	return min
 }
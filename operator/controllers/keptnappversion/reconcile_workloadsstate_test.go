@@ -0,0 +1,46 @@
+package keptnappversion
+
+import (
+	"context"
+	"testing"
+
+	klcv1alpha1 "github.com/keptn/lifecycle-controller/operator/api/v1alpha1"
+	"github.com/keptn/lifecycle-controller/operator/api/v1alpha1/common"
+	testrequire "github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestKeptnAppVersionReconciler_GetWorkloadInstance(t *testing.T) {
+	scheme := runtime.NewScheme()
+	testrequire.Nil(t, klcv1alpha1.AddToScheme(scheme))
+	instance := func(name string, workloadName string, version string) *klcv1alpha1.KeptnWorkloadInstance {
+		return &klcv1alpha1.KeptnWorkloadInstance{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: "default",
+				Labels:    map[string]string{common.InstanceIdLabel: common.IdentityHash(workloadName, version)},
+			},
+		}
+	}
+	r := &KeptnAppVersionReconciler{
+		Client: fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+			instance("shop-cart-1.0.0", "shop-cart", "1.0.0"),
+			// the instance of another workload, whose name collides with the one of the workload of the app version
+			instance("shop-cart-2.0.0", "shop-cart-2", "0.0"),
+		).Build(),
+	}
+	appVersion := &klcv1alpha1.KeptnAppVersion{
+		ObjectMeta: metav1.ObjectMeta{Name: "shop-1.0.0", Namespace: "default"},
+		Spec:       klcv1alpha1.KeptnAppVersionSpec{AppName: "shop"},
+	}
+
+	workloadInstance, err := r.getWorkloadInstance(context.TODO(), appVersion, klcv1alpha1.KeptnWorkloadRef{Name: "cart", Version: "1.0.0"})
+	testrequire.Nil(t, err)
+	testrequire.Equal(t, "shop-cart-1.0.0", workloadInstance.Name)
+
+	_, err = r.getWorkloadInstance(context.TODO(), appVersion, klcv1alpha1.KeptnWorkloadRef{Name: "cart", Version: "2.0.0"})
+	testrequire.True(t, errors.IsConflict(err))
+}
@@ -0,0 +1,271 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package keptndefinitionsource
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/go-logr/logr"
+	klcv1alpha1 "github.com/keptn/lifecycle-controller/operator/api/v1alpha1"
+	"github.com/keptn/lifecycle-controller/operator/controllers/apply"
+	"github.com/keptn/lifecycle-controller/operator/controllers/reasons"
+	"github.com/keptn/lifecycle-controller/operator/controllers/selfmonitoring"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/yaml"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+)
+
+const defaultInterval = 10 * time.Minute
+
+// definitionKinds are the kinds that are applied from an artifact
+var definitionKinds = map[string]bool{
+	"KeptnTaskDefinition":       true,
+	"KeptnEvaluationDefinition": true,
+}
+
+// KeptnDefinitionSourceReconciler reconciles a KeptnDefinitionSource object
+type KeptnDefinitionSourceReconciler struct {
+	client.Client
+	Scheme   *runtime.Scheme
+	Log      logr.Logger
+	Recorder record.EventRecorder
+	Registry *Registry
+}
+
+//+kubebuilder:rbac:groups=lifecycle.keptn.sh,resources=keptndefinitionsources,verbs=get;list;watch
+//+kubebuilder:rbac:groups=lifecycle.keptn.sh,resources=keptndefinitionsources/status,verbs=get;update;patch
+//+kubebuilder:rbac:groups=lifecycle.keptn.sh,resources=keptntaskdefinitions,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=lifecycle.keptn.sh,resources=keptnevaluationdefinitions,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=core,resources=secrets,verbs=get;list;watch
+
+// Reconcile pulls the artifact of a KeptnDefinitionSource and applies the KeptnTaskDefinitions and
+// KeptnEvaluationDefinitions it contains to the namespace of the source. Definitions which have been removed from the
+// artifact are deleted. The artifact is pulled again periodically, so that a tag moved to a new artifact is picked up
+// and changes made to the definitions in the cluster are reverted.
+func (r *KeptnDefinitionSourceReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	r.Log.Info("Reconciling KeptnDefinitionSource")
+
+	source := &klcv1alpha1.KeptnDefinitionSource{}
+	if err := r.Client.Get(ctx, req.NamespacedName, source); err != nil {
+		if apierrors.IsNotFound(err) {
+			r.Log.Info("KeptnDefinitionSource resource not found. Ignoring since object must be deleted")
+			return ctrl.Result{}, nil
+		}
+		r.Log.Error(err, "Failed to get the KeptnDefinitionSource")
+		return ctrl.Result{Requeue: true, RequeueAfter: 30 * time.Second}, nil
+	}
+
+	interval := source.Spec.Interval.Duration
+	if interval <= 0 {
+		interval = defaultInterval
+	}
+
+	digest, definitions, reason, err := r.sync(ctx, source)
+	condition := metav1.Condition{
+		Type:               klcv1alpha1.DefinitionsSynced,
+		Status:             metav1.ConditionTrue,
+		ObservedGeneration: source.Generation,
+		Reason:             reason.Code,
+		Message:            reason.Message(len(definitions), digest),
+	}
+	if err != nil {
+		condition.Status = metav1.ConditionFalse
+		condition.Message = reason.Message(err)
+		r.Recorder.AnnotatedEventf(source, reason.Annotations(), string(reason.Severity), reason.Code, "%s", condition.Message)
+	} else {
+		if digest != source.Status.Digest {
+			r.Recorder.Event(source, "Normal", "DefinitionsSynced", fmt.Sprintf("Applied %d definitions of %s / Namespace: %s, Name: %s", len(definitions), digest, source.Namespace, source.Name))
+		}
+		now := metav1.Now()
+		source.Status.Digest = digest
+		source.Status.Definitions = definitions
+		source.Status.LastSyncTime = &now
+	}
+	meta.SetStatusCondition(&source.Status.Conditions, condition)
+
+	if err := r.Client.Status().Update(ctx, source); err != nil {
+		r.Log.Error(err, "could not update status of KeptnDefinitionSource")
+		return ctrl.Result{Requeue: true}, err
+	}
+	return ctrl.Result{RequeueAfter: interval}, nil
+}
+
+// sync pulls the artifact and applies its definitions. It returns the digest of the artifact, the applied definitions
+// and the reason of the DefinitionsSynced condition, which is the reason it failed if an error is returned.
+func (r *KeptnDefinitionSourceReconciler) sync(ctx context.Context, source *klcv1alpha1.KeptnDefinitionSource) (string, []string, reasons.Reason, error) {
+	ref, err := ParseReference(source.Spec.URL, source.Spec.Tag, source.Spec.Digest, source.Spec.Insecure)
+	if err != nil {
+		return "", nil, reasons.InvalidURL, err
+	}
+	credentials, err := r.credentials(ctx, source, ref.Host)
+	if err != nil {
+		return "", nil, reasons.SecretNotFound, err
+	}
+	digest, files, err := r.Registry.Pull(ctx, ref, credentials)
+	if err != nil {
+		return "", nil, reasons.PullFailed, err
+	}
+	objects, err := Decode(files)
+	if err != nil {
+		return "", nil, reasons.InvalidArtifact, err
+	}
+
+	applied := map[string]bool{}
+	var definitions []string
+	for _, obj := range objects {
+		definition := newDefinition(obj, source)
+		if err := controllerutil.SetControllerReference(source, definition, r.Scheme); err != nil {
+			return "", nil, reasons.ApplyFailed, fmt.Errorf("could not set controller reference: %w", err)
+		}
+		if err := apply.Apply(ctx, r.Client, definition, r.Recorder, source); err != nil {
+			return "", nil, reasons.ApplyFailed, fmt.Errorf("could not apply %s %s: %w", definition.GetKind(), definition.GetName(), err)
+		}
+		name := definition.GetKind() + "/" + definition.GetName()
+		applied[name] = true
+		definitions = append(definitions, name)
+	}
+
+	if err := r.prune(ctx, source, applied); err != nil {
+		return "", nil, reasons.PruneFailed, err
+	}
+	return digest, definitions, reasons.Synced, nil
+}
+
+// credentials returns the credentials of the registry from the docker config secret of the source
+func (r *KeptnDefinitionSourceReconciler) credentials(ctx context.Context, source *klcv1alpha1.KeptnDefinitionSource, host string) (*Credentials, error) {
+	if source.Spec.SecretName == "" {
+		return nil, nil
+	}
+	secret := &corev1.Secret{}
+	if err := r.Client.Get(ctx, types.NamespacedName{Namespace: source.Namespace, Name: source.Spec.SecretName}, secret); err != nil {
+		return nil, fmt.Errorf("could not retrieve secret %s: %w", source.Spec.SecretName, err)
+	}
+	return CredentialsFromDockerConfig(secret.Data[corev1.DockerConfigJsonKey], host)
+}
+
+// prune deletes the definitions created from the source which are not part of its artifact anymore
+func (r *KeptnDefinitionSourceReconciler) prune(ctx context.Context, source *klcv1alpha1.KeptnDefinitionSource, applied map[string]bool) error {
+	selector := client.MatchingLabels{klcv1alpha1.DefinitionSourceLabel: source.Name}
+
+	taskDefinitions := &klcv1alpha1.KeptnTaskDefinitionList{}
+	if err := r.Client.List(ctx, taskDefinitions, client.InNamespace(source.Namespace), selector); err != nil {
+		return fmt.Errorf("could not list KeptnTaskDefinitions: %w", err)
+	}
+	for i := range taskDefinitions.Items {
+		if err := r.pruneDefinition(ctx, source, &taskDefinitions.Items[i], "KeptnTaskDefinition", applied); err != nil {
+			return err
+		}
+	}
+
+	evaluationDefinitions := &klcv1alpha1.KeptnEvaluationDefinitionList{}
+	if err := r.Client.List(ctx, evaluationDefinitions, client.InNamespace(source.Namespace), selector); err != nil {
+		return fmt.Errorf("could not list KeptnEvaluationDefinitions: %w", err)
+	}
+	for i := range evaluationDefinitions.Items {
+		if err := r.pruneDefinition(ctx, source, &evaluationDefinitions.Items[i], "KeptnEvaluationDefinition", applied); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *KeptnDefinitionSourceReconciler) pruneDefinition(ctx context.Context, source *klcv1alpha1.KeptnDefinitionSource, definition client.Object, kind string, applied map[string]bool) error {
+	if applied[kind+"/"+definition.GetName()] || !metav1.IsControlledBy(definition, source) {
+		return nil
+	}
+	if err := r.Client.Delete(ctx, definition); err != nil && !apierrors.IsNotFound(err) {
+		return fmt.Errorf("could not delete %s %s: %w", kind, definition.GetName(), err)
+	}
+	r.Recorder.Event(source, "Normal", "DefinitionPruned", fmt.Sprintf("Deleted %s removed from the artifact / Namespace: %s, Name: %s", kind, definition.GetNamespace(), definition.GetName()))
+	return nil
+}
+
+// Decode returns the definitions of the YAML and JSON files of an artifact. Artifacts containing other resources are
+// rejected, so that a source cannot be used to create arbitrary resources with the permissions of the operator.
+func Decode(files [][]byte) ([]*unstructured.Unstructured, error) {
+	var objects []*unstructured.Unstructured
+	for _, file := range files {
+		decoder := yaml.NewYAMLOrJSONDecoder(bytes.NewReader(file), 4096)
+		for {
+			obj := &unstructured.Unstructured{}
+			if err := decoder.Decode(&obj.Object); err != nil {
+				if errors.Is(err, io.EOF) {
+					break
+				}
+				return nil, fmt.Errorf("could not decode definition: %w", err)
+			}
+			if len(obj.Object) == 0 {
+				continue
+			}
+			gvk := obj.GroupVersionKind()
+			if gvk.Group != klcv1alpha1.GroupVersion.Group || !definitionKinds[gvk.Kind] {
+				return nil, fmt.Errorf("artifact contains %s %s, only KeptnTaskDefinitions and KeptnEvaluationDefinitions are supported", obj.GetAPIVersion()+"/"+gvk.Kind, obj.GetName())
+			}
+			if obj.GetName() == "" {
+				return nil, fmt.Errorf("artifact contains a %s without name", gvk.Kind)
+			}
+			objects = append(objects, obj)
+		}
+	}
+	return objects, nil
+}
+
+// newDefinition returns the definition to apply to the namespace of the source. Only the name, labels, annotations
+// and spec of the definition in the artifact are used.
+func newDefinition(obj *unstructured.Unstructured, source *klcv1alpha1.KeptnDefinitionSource) *unstructured.Unstructured {
+	definition := &unstructured.Unstructured{Object: map[string]interface{}{}}
+	definition.SetGroupVersionKind(obj.GroupVersionKind())
+	definition.SetName(obj.GetName())
+	definition.SetNamespace(source.Namespace)
+	labels := obj.GetLabels()
+	if labels == nil {
+		labels = map[string]string{}
+	}
+	labels[klcv1alpha1.DefinitionSourceLabel] = source.Name
+	definition.SetLabels(labels)
+	if annotations := obj.GetAnnotations(); len(annotations) > 0 {
+		definition.SetAnnotations(annotations)
+	}
+	if spec, ok := obj.Object["spec"]; ok {
+		definition.Object["spec"] = spec
+	}
+	return definition
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *KeptnDefinitionSourceReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		// predicate disabling the auto reconciliation after updating the object status
+		For(&klcv1alpha1.KeptnDefinitionSource{}, builder.WithPredicates(predicate.GenerationChangedPredicate{})).
+		Complete(selfmonitoring.Wrap("keptndefinitionsource", r))
+}
@@ -0,0 +1,330 @@
+package keptndefinitionsource
+
+import (
+	"archive/tar"
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+)
+
+const (
+	// maxBlobSize limits the size of the manifest and of each layer of an artifact
+	maxBlobSize = 10 << 20
+
+	manifestMediaTypes = "application/vnd.oci.image.manifest.v1+json, application/vnd.docker.distribution.manifest.v2+json"
+)
+
+// Reference is a tag or digest of an artifact in an OCI repository
+type Reference struct {
+	Scheme     string
+	Host       string
+	Repository string
+	// Tag is either the tag or the digest of the artifact
+	Tag string
+}
+
+// ParseReference returns the Reference to the artifact of the oci:// URL with the given tag or digest. The digest takes
+// precedence over the tag.
+func ParseReference(artifactURL string, tag string, digest string, insecure bool) (Reference, error) {
+	if !strings.HasPrefix(artifactURL, "oci://") {
+		return Reference{}, fmt.Errorf("url %s is not an oci:// url", artifactURL)
+	}
+	host, repository, found := strings.Cut(strings.TrimPrefix(artifactURL, "oci://"), "/")
+	if !found || host == "" || repository == "" {
+		return Reference{}, fmt.Errorf("url %s does not contain a registry and a repository", artifactURL)
+	}
+	ref := Reference{Scheme: "https", Host: host, Repository: strings.Trim(repository, "/"), Tag: tag}
+	if insecure {
+		ref.Scheme = "http"
+	}
+	if digest != "" {
+		ref.Tag = digest
+	}
+	if ref.Tag == "" {
+		ref.Tag = "latest"
+	}
+	return ref, nil
+}
+
+func (ref Reference) pinned() bool {
+	return strings.HasPrefix(ref.Tag, "sha256:")
+}
+
+func (ref Reference) url(kind string, name string) string {
+	return fmt.Sprintf("%s://%s/v2/%s/%s/%s", ref.Scheme, ref.Host, ref.Repository, kind, name)
+}
+
+// Credentials authenticate against a registry
+type Credentials struct {
+	Username string
+	Password string
+}
+
+// CredentialsFromDockerConfig returns the credentials of the registry host from a .dockerconfigjson
+func CredentialsFromDockerConfig(dockerConfig []byte, host string) (*Credentials, error) {
+	config := struct {
+		Auths map[string]struct {
+			Username string `json:"username"`
+			Password string `json:"password"`
+			Auth     string `json:"auth"`
+		} `json:"auths"`
+	}{}
+	if err := json.Unmarshal(dockerConfig, &config); err != nil {
+		return nil, fmt.Errorf("could not parse docker config: %w", err)
+	}
+	for registry, auth := range config.Auths {
+		if registry != host && strings.TrimSuffix(strings.TrimPrefix(strings.TrimPrefix(registry, "https://"), "http://"), "/") != host {
+			continue
+		}
+		if auth.Auth != "" {
+			decoded, err := base64.StdEncoding.DecodeString(auth.Auth)
+			if err != nil {
+				return nil, fmt.Errorf("could not decode auth of registry %s: %w", registry, err)
+			}
+			username, password, _ := strings.Cut(string(decoded), ":")
+			return &Credentials{Username: username, Password: password}, nil
+		}
+		return &Credentials{Username: auth.Username, Password: auth.Password}, nil
+	}
+	return nil, fmt.Errorf("docker config does not contain credentials of registry %s", host)
+}
+
+type manifest struct {
+	Layers []descriptor `json:"layers"`
+}
+
+type descriptor struct {
+	MediaType string `json:"mediaType"`
+	Digest    string `json:"digest"`
+}
+
+// Registry pulls artifacts via the OCI distribution API
+type Registry struct {
+	HTTPClient *http.Client
+}
+
+// Pull returns the digest of the artifact and the content of the YAML and JSON files of its layers. Layers are either
+// (gzipped) tarballs, such as the ones pushed by flux push artifact, or single files. The digests of the manifest and
+// all layers are verified.
+func (r *Registry) Pull(ctx context.Context, ref Reference, credentials *Credentials) (string, [][]byte, error) {
+	session := &registrySession{httpClient: r.HTTPClient, credentials: credentials}
+
+	body, err := session.get(ctx, ref.url("manifests", ref.Tag), manifestMediaTypes)
+	if err != nil {
+		return "", nil, fmt.Errorf("could not retrieve manifest of %s/%s:%s: %w", ref.Host, ref.Repository, ref.Tag, err)
+	}
+	digest := digestOf(body)
+	if ref.pinned() && digest != ref.Tag {
+		return "", nil, fmt.Errorf("digest %s of the manifest does not match %s", digest, ref.Tag)
+	}
+	m := manifest{}
+	if err := json.Unmarshal(body, &m); err != nil {
+		return "", nil, fmt.Errorf("could not parse manifest: %w", err)
+	}
+
+	var files [][]byte
+	for _, layer := range m.Layers {
+		blob, err := session.get(ctx, ref.url("blobs", layer.Digest), "")
+		if err != nil {
+			return "", nil, fmt.Errorf("could not retrieve layer %s: %w", layer.Digest, err)
+		}
+		if digestOf(blob) != layer.Digest {
+			return "", nil, fmt.Errorf("digest of layer %s does not match its content", layer.Digest)
+		}
+		layerFiles, err := extract(blob)
+		if err != nil {
+			return "", nil, fmt.Errorf("could not extract layer %s: %w", layer.Digest, err)
+		}
+		files = append(files, layerFiles...)
+	}
+	return digest, files, nil
+}
+
+func digestOf(content []byte) string {
+	sum := sha256.Sum256(content)
+	return "sha256:" + hex.EncodeToString(sum[:])
+}
+
+// extract returns the YAML and JSON files of a tarball, or the layer itself if it is not a tarball
+func extract(blob []byte) ([][]byte, error) {
+	reader := bufio.NewReader(bytes.NewReader(blob))
+	if magic, err := reader.Peek(2); err == nil && magic[0] == 0x1f && magic[1] == 0x8b {
+		gzipReader, err := gzip.NewReader(reader)
+		if err != nil {
+			return nil, err
+		}
+		content, err := io.ReadAll(io.LimitReader(gzipReader, maxBlobSize+1))
+		if err != nil {
+			return nil, err
+		}
+		if len(content) > maxBlobSize {
+			return nil, fmt.Errorf("layer exceeds %d bytes", maxBlobSize)
+		}
+		blob = content
+	}
+
+	tarReader := tar.NewReader(bytes.NewReader(blob))
+	var files [][]byte
+	for entries := 0; ; entries++ {
+		header, err := tarReader.Next()
+		if errors.Is(err, io.EOF) {
+			return files, nil
+		}
+		if err != nil {
+			if entries == 0 {
+				// not a tarball
+				return [][]byte{blob}, nil
+			}
+			return nil, err
+		}
+		if header.Typeflag != tar.TypeReg {
+			continue
+		}
+		switch strings.ToLower(path.Ext(header.Name)) {
+		case ".yaml", ".yml", ".json":
+			content, err := io.ReadAll(tarReader)
+			if err != nil {
+				return nil, err
+			}
+			files = append(files, content)
+		}
+	}
+}
+
+// registrySession sends requests to a registry, authenticating with the token or basic auth challenge of the registry
+type registrySession struct {
+	httpClient    *http.Client
+	credentials   *Credentials
+	authorization string
+}
+
+func (s *registrySession) get(ctx context.Context, requestURL string, accept string) ([]byte, error) {
+	resp, err := s.do(ctx, requestURL, accept)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode == http.StatusUnauthorized && s.authorization == "" {
+		challenge := resp.Header.Get("WWW-Authenticate")
+		resp.Body.Close()
+		if err := s.authorize(ctx, challenge); err != nil {
+			return nil, err
+		}
+		if resp, err = s.do(ctx, requestURL, accept); err != nil {
+			return nil, err
+		}
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected response status %s", resp.Status)
+	}
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxBlobSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(body) > maxBlobSize {
+		return nil, fmt.Errorf("response exceeds %d bytes", maxBlobSize)
+	}
+	return body, nil
+}
+
+func (s *registrySession) do(ctx context.Context, requestURL string, accept string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, requestURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	if accept != "" {
+		req.Header.Set("Accept", accept)
+	}
+	if s.authorization != "" {
+		req.Header.Set("Authorization", s.authorization)
+	}
+	return s.httpClient.Do(req)
+}
+
+// authorize answers the challenge of the registry, either with the credentials or with a token retrieved from the
+// token service of the registry, which hands out anonymous tokens for public repositories
+func (s *registrySession) authorize(ctx context.Context, challenge string) error {
+	scheme, params, _ := strings.Cut(challenge, " ")
+	switch strings.ToLower(scheme) {
+	case "basic":
+		if s.credentials == nil {
+			return fmt.Errorf("registry requires credentials")
+		}
+		s.authorization = "Basic " + base64.StdEncoding.EncodeToString([]byte(s.credentials.Username+":"+s.credentials.Password))
+		return nil
+	case "bearer":
+	default:
+		return fmt.Errorf("unsupported authentication challenge %q", challenge)
+	}
+
+	values := parseChallenge(params)
+	tokenURL, err := url.Parse(values["realm"])
+	if err != nil || tokenURL.Host == "" {
+		return fmt.Errorf("invalid token realm %q", values["realm"])
+	}
+	query := tokenURL.Query()
+	for _, key := range []string{"service", "scope"} {
+		if values[key] != "" {
+			query.Set(key, values[key])
+		}
+	}
+	tokenURL.RawQuery = query.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, tokenURL.String(), nil)
+	if err != nil {
+		return err
+	}
+	if s.credentials != nil {
+		req.SetBasicAuth(s.credentials.Username, s.credentials.Password)
+	}
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("could not retrieve token: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("could not retrieve token: unexpected response status %s", resp.Status)
+	}
+	token := struct {
+		Token       string `json:"token"`
+		AccessToken string `json:"access_token"`
+	}{}
+	if err := json.NewDecoder(resp.Body).Decode(&token); err != nil {
+		return fmt.Errorf("could not parse token: %w", err)
+	}
+	if token.Token == "" {
+		token.Token = token.AccessToken
+	}
+	s.authorization = "Bearer " + token.Token
+	return nil
+}
+
+// parseChallenge parses the parameters of a challenge like realm="https://ghcr.io/token",service="ghcr.io"
+func parseChallenge(params string) map[string]string {
+	values := map[string]string{}
+	for params != "" {
+		var key, value string
+		key, params, _ = strings.Cut(params, "=")
+		key = strings.ToLower(strings.TrimSpace(key))
+		if strings.HasPrefix(params, `"`) {
+			value, params, _ = strings.Cut(params[1:], `"`)
+			params = strings.TrimPrefix(params, ",")
+		} else {
+			value, params, _ = strings.Cut(params, ",")
+		}
+		values[key] = value
+	}
+	return values
+}
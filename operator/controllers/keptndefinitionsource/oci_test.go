@@ -0,0 +1,136 @@
+package keptndefinitionsource
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	testrequire "github.com/stretchr/testify/require"
+)
+
+const taskDefinitionYAML = `apiVersion: lifecycle.keptn.sh/v1alpha1
+kind: KeptnTaskDefinition
+metadata:
+  name: slack-notification
+  namespace: catalog
+spec:
+  function:
+    httpRef:
+      url: https://example.com/slack.ts
+---
+apiVersion: lifecycle.keptn.sh/v1alpha1
+kind: KeptnEvaluationDefinition
+metadata:
+  name: error-rate
+spec:
+  source: prometheus
+`
+
+func newLayer(t *testing.T, files map[string]string) []byte {
+	buf := &bytes.Buffer{}
+	gzipWriter := gzip.NewWriter(buf)
+	tarWriter := tar.NewWriter(gzipWriter)
+	for name, content := range files {
+		testrequire.Nil(t, tarWriter.WriteHeader(&tar.Header{Name: name, Mode: 0644, Size: int64(len(content)), Typeflag: tar.TypeReg}))
+		_, err := tarWriter.Write([]byte(content))
+		testrequire.Nil(t, err)
+	}
+	testrequire.Nil(t, tarWriter.Close())
+	testrequire.Nil(t, gzipWriter.Close())
+	return buf.Bytes()
+}
+
+// newRegistry returns a registry serving a single artifact, which requires a token of its token service
+func newRegistry(t *testing.T, layer []byte) (*httptest.Server, string) {
+	layerDigest := digestOf(layer)
+	manifestBody, err := json.Marshal(map[string]interface{}{
+		"schemaVersion": 2,
+		"layers":        []map[string]interface{}{{"mediaType": "application/vnd.cncf.flux.content.v1.tar+gzip", "digest": layerDigest}},
+	})
+	testrequire.Nil(t, err)
+
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/token" {
+			testrequire.Equal(t, "repository:acme/gates:pull", r.URL.Query().Get("scope"))
+			user, password, _ := r.BasicAuth()
+			testrequire.Equal(t, "keptn:s3cr3t", user+":"+password)
+			w.Write([]byte(`{"token":"abc"}`))
+			return
+		}
+		if r.Header.Get("Authorization") != "Bearer abc" {
+			w.Header().Set("WWW-Authenticate", `Bearer realm="`+server.URL+`/token",service="registry",scope="repository:acme/gates:pull"`)
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		switch r.URL.Path {
+		case "/v2/acme/gates/manifests/v1.0.0", "/v2/acme/gates/manifests/" + digestOf(manifestBody):
+			w.Write(manifestBody)
+		case "/v2/acme/gates/blobs/" + layerDigest:
+			w.Write(layer)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	return server, digestOf(manifestBody)
+}
+
+func TestRegistry_Pull(t *testing.T) {
+	layer := newLayer(t, map[string]string{"gates/definitions.yaml": taskDefinitionYAML, "README.md": "# Gates"})
+	server, digest := newRegistry(t, layer)
+	defer server.Close()
+	registry := &Registry{HTTPClient: server.Client()}
+	credentials := &Credentials{Username: "keptn", Password: "s3cr3t"}
+	url := "oci://" + strings.TrimPrefix(server.URL, "http://") + "/acme/gates"
+
+	ref, err := ParseReference(url, "v1.0.0", "", true)
+	testrequire.Nil(t, err)
+	pulledDigest, files, err := registry.Pull(context.TODO(), ref, credentials)
+	testrequire.Nil(t, err)
+	testrequire.Equal(t, digest, pulledDigest)
+	testrequire.Equal(t, [][]byte{[]byte(taskDefinitionYAML)}, files)
+
+	objects, err := Decode(files)
+	testrequire.Nil(t, err)
+	testrequire.Len(t, objects, 2)
+	testrequire.Equal(t, "KeptnTaskDefinition", objects[0].GetKind())
+	testrequire.Equal(t, "error-rate", objects[1].GetName())
+
+	ref, err = ParseReference(url, "v1.0.0", digest, true)
+	testrequire.Nil(t, err)
+	pulledDigest, _, err = registry.Pull(context.TODO(), ref, credentials)
+	testrequire.Nil(t, err)
+	testrequire.Equal(t, digest, pulledDigest)
+
+	ref, err = ParseReference(url, "", "sha256:4b825dc642cb6eb9a060e54bf8d69288fbee4904b825dc642cb6eb9a060e54bf", true)
+	testrequire.Nil(t, err)
+	_, _, err = registry.Pull(context.TODO(), ref, credentials)
+	testrequire.NotNil(t, err)
+}
+
+func TestDecode(t *testing.T) {
+	_, err := Decode([][]byte{[]byte(`{"apiVersion":"rbac.authorization.k8s.io/v1","kind":"ClusterRoleBinding","metadata":{"name":"admin"}}`)})
+	testrequire.EqualError(t, err, "artifact contains rbac.authorization.k8s.io/v1/ClusterRoleBinding admin, only KeptnTaskDefinitions and KeptnEvaluationDefinitions are supported")
+
+	objects, err := Decode([][]byte{[]byte("---\n# no definitions\n")})
+	testrequire.Nil(t, err)
+	testrequire.Empty(t, objects)
+}
+
+func TestCredentialsFromDockerConfig(t *testing.T) {
+	config := `{"auths":{"https://ghcr.io":{"auth":"` + base64.StdEncoding.EncodeToString([]byte("keptn:s3cr3t")) + `"}}}`
+
+	credentials, err := CredentialsFromDockerConfig([]byte(config), "ghcr.io")
+	testrequire.Nil(t, err)
+	testrequire.Equal(t, &Credentials{Username: "keptn", Password: "s3cr3t"}, credentials)
+
+	_, err = CredentialsFromDockerConfig([]byte(config), "quay.io")
+	testrequire.NotNil(t, err)
+}
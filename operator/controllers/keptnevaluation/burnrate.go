@@ -0,0 +1,81 @@
+package keptnevaluation
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	klcv1alpha1 "github.com/keptn/lifecycle-controller/operator/api/v1alpha1"
+	"github.com/keptn/lifecycle-controller/operator/api/v1alpha1/common"
+	"github.com/prometheus/common/model"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// evaluateBurnRate computes the error budget burn rate of the SLO of the objective over its long and short window.
+// The objective fails if the burn rates of both windows exceed the maximum burn rate.
+func (r *KeptnEvaluationReconciler) evaluateBurnRate(ctx context.Context, evaluation *klcv1alpha1.KeptnEvaluation, objective klcv1alpha1.Objective, defaultSource string, providers map[string]klcv1alpha1.KeptnEvaluationProvider) *klcv1alpha1.EvaluationStatusItem {
+	target, err := objective.BurnRate.GetTarget()
+	if err != nil {
+		return &klcv1alpha1.EvaluationStatusItem{Status: common.StateFailed, Query: r.Redactor.Query(objective.Query, objective.Secure), Message: err.Error()}
+	}
+	maxBurnRate, err := objective.BurnRate.GetMaxBurnRate()
+	if err != nil {
+		return &klcv1alpha1.EvaluationStatusItem{Status: common.StateFailed, Query: r.Redactor.Query(objective.Query, objective.Secure), Message: err.Error()}
+	}
+
+	long, short := objective.BurnRate.GetWindows()
+	windows := []time.Duration{long, short}
+	results := make([]*klcv1alpha1.EvaluationStatusItem, 0, len(windows))
+	for _, window := range windows {
+		windowObjective := objective
+		windowObjective.Query, err = r.renderQuery(ctx, evaluation, objective.Query, window)
+		if err != nil {
+			return &klcv1alpha1.EvaluationStatusItem{Status: common.StateFailed, Query: r.Redactor.Query(objective.Query, objective.Secure), Message: err.Error()}
+		}
+		// the burn rate exceeds the maximum if the error ratio exceeds the maximum share of the error budget
+		windowObjective.EvaluationTarget = fmt.Sprintf("<= %g", maxBurnRate*(1-target))
+		windowObjective.Window = metav1.Duration{}
+		results = append(results, r.evaluateObjective(ctx, windowObjective, nil, defaultSource, providers))
+	}
+	return combineBurnRates(target, maxBurnRate, windows, results)
+}
+
+// combineBurnRates merges the results of the windows into a single status item with the burn rate of the long window as value
+func combineBurnRates(target float64, maxBurnRate float64, windows []time.Duration, results []*klcv1alpha1.EvaluationStatusItem) *klcv1alpha1.EvaluationStatusItem {
+	combined := &klcv1alpha1.EvaluationStatusItem{
+		Status:     common.StateFailed,
+		Query:      results[0].Query,
+		QueryStart: results[0].QueryStart,
+		QueryEnd:   results[0].QueryEnd,
+	}
+
+	responses := make([]string, 0, len(results))
+	details := make([]string, 0, len(results))
+	exceeded := 0
+	for i, result := range results {
+		window := model.Duration(windows[i]).String()
+		responses = append(responses, fmt.Sprintf("%s: %s", window, result.Response))
+		errorRatio, err := strconv.ParseFloat(result.Value, 64)
+		if err != nil {
+			combined.Message = fmt.Sprintf("could not compute burn rate over %s: %s", window, result.Message)
+			return combined
+		}
+		burnRate := errorRatio / (1 - target)
+		if i == 0 {
+			combined.Value = fmt.Sprintf("%.4f", burnRate)
+		}
+		if !result.Status.IsSucceeded() {
+			exceeded++
+		}
+		details = append(details, fmt.Sprintf("burn rate over %s: %.2f", window, burnRate))
+	}
+
+	if exceeded < len(results) {
+		combined.Status = common.StateSucceeded
+	}
+	combined.Response = common.TruncateString(strings.Join(responses, "\n"), maxResponseLength)
+	combined.Message = fmt.Sprintf("%s, maximum burn rate: %g", strings.Join(details, ", "), maxBurnRate)
+	return combined
+}
@@ -0,0 +1,78 @@
+package keptnevaluation
+
+import (
+	"testing"
+	"time"
+
+	klcv1alpha1 "github.com/keptn/lifecycle-controller/operator/api/v1alpha1"
+	"github.com/keptn/lifecycle-controller/operator/api/v1alpha1/common"
+	testrequire "github.com/stretchr/testify/require"
+)
+
+func TestGetMaxBurnRate(t *testing.T) {
+	maxBurnRate, err := klcv1alpha1.BurnRate{Target: "99.9"}.GetMaxBurnRate()
+	testrequire.Nil(t, err)
+	testrequire.InDelta(t, 14.4, maxBurnRate, 1e-9)
+
+	maxBurnRate, err = klcv1alpha1.BurnRate{Target: "99.9", MaxBurnRate: "6"}.GetMaxBurnRate()
+	testrequire.Nil(t, err)
+	testrequire.Equal(t, 6.0, maxBurnRate)
+
+	_, err = klcv1alpha1.BurnRate{Target: "100"}.GetTarget()
+	testrequire.NotNil(t, err)
+}
+
+func TestCombineBurnRates(t *testing.T) {
+	windows := []time.Duration{time.Hour, 5 * time.Minute}
+
+	tests := []struct {
+		name    string
+		results []*klcv1alpha1.EvaluationStatusItem
+		want    common.KeptnState
+		value   string
+	}{
+		{
+			name: "within budget",
+			results: []*klcv1alpha1.EvaluationStatusItem{
+				{Value: "0.001", Status: common.StateSucceeded},
+				{Value: "0.002", Status: common.StateSucceeded},
+			},
+			want:  common.StateSucceeded,
+			value: "1.0000",
+		},
+		{
+			name: "short spike",
+			results: []*klcv1alpha1.EvaluationStatusItem{
+				{Value: "0.001", Status: common.StateSucceeded},
+				{Value: "0.05", Status: common.StateFailed},
+			},
+			want:  common.StateSucceeded,
+			value: "1.0000",
+		},
+		{
+			name: "both windows exceeded",
+			results: []*klcv1alpha1.EvaluationStatusItem{
+				{Value: "0.02", Status: common.StateFailed},
+				{Value: "0.05", Status: common.StateFailed},
+			},
+			want:  common.StateFailed,
+			value: "20.0000",
+		},
+		{
+			name: "no value",
+			results: []*klcv1alpha1.EvaluationStatusItem{
+				{Value: "", Status: common.StateFailed, Message: "No values in query result"},
+				{Value: "0.05", Status: common.StateFailed},
+			},
+			want:  common.StateFailed,
+			value: "",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			combined := combineBurnRates(0.999, 14.4, windows, tt.results)
+			testrequire.Equal(t, tt.want, combined.Status)
+			testrequire.Equal(t, tt.value, combined.Value)
+		})
+	}
+}
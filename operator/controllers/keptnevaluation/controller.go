@@ -21,8 +21,6 @@ import (
 	"fmt"
 	"time"
 
-	"math"
-	"net/http"
 	"strconv"
 
 	promapi "github.com/prometheus/client_golang/api"
@@ -33,6 +31,7 @@ import (
 	"go.opentelemetry.io/otel/propagation"
 	"go.opentelemetry.io/otel/trace"
 	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/tools/record"
@@ -45,6 +44,13 @@ import (
 	klcv1alpha1 "github.com/keptn/lifecycle-controller/operator/api/v1alpha1"
 	"github.com/keptn/lifecycle-controller/operator/api/v1alpha1/common"
 	"github.com/keptn/lifecycle-controller/operator/api/v1alpha1/semconv"
+	"github.com/keptn/lifecycle-controller/operator/controllers/deadline"
+	controllererrors "github.com/keptn/lifecycle-controller/operator/controllers/errors"
+	"github.com/keptn/lifecycle-controller/operator/controllers/keptnevaluationprovider"
+	"github.com/keptn/lifecycle-controller/operator/controllers/reasons"
+	"github.com/keptn/lifecycle-controller/operator/controllers/selfmonitoring"
+	"github.com/keptn/lifecycle-controller/operator/metrics"
+	"github.com/keptn/lifecycle-controller/operator/redaction"
 )
 
 // KeptnEvaluationReconciler reconciles a KeptnEvaluation object
@@ -53,8 +59,12 @@ type KeptnEvaluationReconciler struct {
 	Scheme   *runtime.Scheme
 	Recorder record.EventRecorder
 	Log      logr.Logger
-	Meters   common.KeptnMeters
+	Meters   metrics.Meters
 	Tracer   trace.Tracer
+	// ProviderClients caches the clients used to query the KeptnEvaluationProviders
+	ProviderClients *keptnevaluationprovider.ClientCache
+	// Redactor hides sensitive values of the queries in the status, logs and spans of the evaluations
+	Redactor *redaction.Redactor
 }
 
 //+kubebuilder:rbac:groups=lifecycle.keptn.sh,resources=keptnevaluations,verbs=get;list;watch;create;update;patch;delete
@@ -62,6 +72,10 @@ type KeptnEvaluationReconciler struct {
 //+kubebuilder:rbac:groups=lifecycle.keptn.sh,resources=keptnevaluations/finalizers,verbs=update
 //+kubebuilder:rbac:groups=lifecycle.keptn.sh,resources=keptnevaluationproviders,verbs=get;list;watch
 //+kubebuilder:rbac:groups=lifecycle.keptn.sh,resources=keptnevaluationdefinitions,verbs=get;list;watch
+//+kubebuilder:rbac:groups=lifecycle.keptn.sh,resources=keptnmetrics,verbs=get;list;watch
+//+kubebuilder:rbac:groups=lifecycle.keptn.sh,resources=keptnworkloadinstances,verbs=get;list;watch
+//+kubebuilder:rbac:groups=core,resources=pods,verbs=get;list;watch
+//+kubebuilder:rbac:groups=apps,resources=replicasets,verbs=get;list;watch
 
 // Reconcile is part of the main kubernetes reconciliation loop which aims to
 // move the current state of the cluster closer to the desired state.
@@ -97,7 +111,7 @@ func (r *KeptnEvaluationReconciler) Reconcile(ctx context.Context, req ctrl.Requ
 	evaluation.SetStartTime()
 
 	if evaluation.Status.RetryCount >= evaluation.Spec.Retries {
-		r.recordEvent("Warning", evaluation, "ReconcileTimeOut", "retryCount exceeded")
+		r.recordEvent(evaluation, reasons.RetryCountExceeded)
 		err := fmt.Errorf("retryCount for evaluation exceeded")
 		span.SetStatus(codes.Error, err.Error())
 		evaluation.Status.OverallStatus = common.StateFailed
@@ -105,13 +119,27 @@ func (r *KeptnEvaluationReconciler) Reconcile(ctx context.Context, req ctrl.Requ
 		return ctrl.Result{}, nil
 	}
 
+	if !evaluation.Status.OverallStatus.IsCompleted() && evaluation.Spec.Deadline != nil && deadline.Remaining(*evaluation.Spec.Deadline, time.Now()) == 0 {
+		r.recordEvent(evaluation, reasons.DeadlineExceeded)
+		err := fmt.Errorf("deadline for evaluation exceeded")
+		span.SetStatus(codes.Error, err.Error())
+		evaluation.Status.OverallStatus = common.StateFailed
+		r.updateFinishedEvaluationMetrics(ctx, evaluation, span)
+		return ctrl.Result{}, nil
+	}
+
 	if !evaluation.Status.OverallStatus.IsSucceeded() {
 		namespacedDefinition := types.NamespacedName{
 			Namespace: req.NamespacedName.Namespace,
 			Name:      evaluation.Spec.EvaluationDefinition,
 		}
-		evaluationDefinition, evaluationProvider, err := r.fetchDefinitionAndProvider(ctx, namespacedDefinition)
+		evaluationDefinition, evaluationProviders, err := r.fetchDefinitionAndProviders(ctx, namespacedDefinition)
 		if err != nil {
+			if controllererrors.Is(err, controllererrors.ErrProviderUnavailable) {
+				r.recordEvent(evaluation, reasons.ProviderUnavailable)
+				r.Log.Info(err.Error())
+				return controllererrors.Result(err)
+			}
 			if errors.IsNotFound(err) {
 				r.Log.Info(err.Error() + ", ignoring error since object must be deleted")
 				return ctrl.Result{Requeue: true, RequeueAfter: 30 * time.Second}, nil
@@ -137,7 +165,36 @@ func (r *KeptnEvaluationReconciler) Reconcile(ctx context.Context, req ctrl.Requ
 				newStatus[query.Name] = evaluation.Status.EvaluationStatus[query.Name]
 				continue
 			}
-			statusItem := r.queryEvaluation(query, *evaluationProvider)
+			if query.KeptnMetric != "" {
+				statusItem := r.evaluateMetric(ctx, evaluation, query)
+				statusSummary = common.UpdateStatusSummary(statusItem.Status, statusSummary)
+				newStatus[query.Name] = *statusItem
+				continue
+			}
+			if query.BurnRate != nil {
+				statusItem := r.evaluateBurnRate(ctx, evaluation, query, evaluationDefinition.Spec.Source, evaluationProviders)
+				statusSummary = common.UpdateStatusSummary(statusItem.Status, statusSummary)
+				newStatus[query.Name] = *statusItem
+				continue
+			}
+			renderedQuery, err := r.renderQuery(ctx, evaluation, query.Query, query.Window.Duration)
+			if err != nil {
+				r.Log.Error(err, "Could not render query of objective "+query.Name)
+				statusItem := &klcv1alpha1.EvaluationStatusItem{Status: common.StateFailed, Query: r.Redactor.Query(query.Query, query.Secure), Message: err.Error()}
+				statusSummary = common.UpdateStatusSummary(statusItem.Status, statusSummary)
+				newStatus[query.Name] = *statusItem
+				continue
+			}
+			query.Query = renderedQuery
+			previous, err := r.getPreviousValue(ctx, evaluation, query)
+			if err != nil {
+				r.Log.Error(err, "Could not retrieve the previous value of objective "+query.Name)
+				statusItem := &klcv1alpha1.EvaluationStatusItem{Status: common.StateFailed, Query: r.Redactor.Query(query.Query, query.Secure), Message: err.Error()}
+				statusSummary = common.UpdateStatusSummary(statusItem.Status, statusSummary)
+				newStatus[query.Name] = *statusItem
+				continue
+			}
+			statusItem := r.evaluateObjective(ctx, query, previous, evaluationDefinition.Spec.Source, evaluationProviders)
 			statusSummary = common.UpdateStatusSummary(statusItem.Status, statusSummary)
 			newStatus[query.Name] = *statusItem
 		}
@@ -156,14 +213,21 @@ func (r *KeptnEvaluationReconciler) Reconcile(ctx context.Context, req ctrl.Requ
 		// Evaluation is uncompleted, update status anyway this avoids updating twice in case of completion
 		err := r.Client.Status().Update(ctx, evaluation)
 		if err != nil {
-			r.recordEvent("Warning", evaluation, "ReconcileErrored", "could not update status")
+			r.recordEvent(evaluation, reasons.ReconcileErrored)
 			span.SetStatus(codes.Error, err.Error())
 			return ctrl.Result{Requeue: true}, err
 		}
 
-		r.recordEvent("Normal", evaluation, "NotFinished", "has not finished")
+		r.recordEvent(evaluation, reasons.NotFinished)
 
-		return ctrl.Result{Requeue: true, RequeueAfter: evaluation.Spec.RetryInterval.Duration}, nil
+		// the next retry must not happen after the deadline, but fail the evaluation at the latest when it has passed
+		retryInterval := evaluation.Spec.RetryInterval.Duration
+		if evaluation.Spec.Deadline != nil {
+			if remaining := deadline.Remaining(*evaluation.Spec.Deadline, time.Now()); remaining < retryInterval {
+				retryInterval = remaining
+			}
+		}
+		return ctrl.Result{Requeue: true, RequeueAfter: retryInterval}, nil
 
 	}
 
@@ -176,14 +240,14 @@ func (r *KeptnEvaluationReconciler) Reconcile(ctx context.Context, req ctrl.Requ
 }
 
 func (r *KeptnEvaluationReconciler) updateFinishedEvaluationMetrics(ctx context.Context, evaluation *klcv1alpha1.KeptnEvaluation, span trace.Span) error {
-	r.recordEvent("Normal", evaluation, string(evaluation.Status.OverallStatus), "the evaluation has "+string(evaluation.Status.OverallStatus))
+	r.recordEvent(evaluation, reasons.ForState(evaluation.Status.OverallStatus))
 
 	evaluation.SetEndTime()
 
 	err := r.Client.Status().Update(ctx, evaluation)
 	if err != nil {
 		span.SetStatus(codes.Error, err.Error())
-		r.recordEvent("Warning", evaluation, "ReconcileErrored", "could not update status")
+		r.recordEvent(evaluation, reasons.ReconcileErrored)
 		return err
 	}
 
@@ -192,11 +256,11 @@ func (r *KeptnEvaluationReconciler) updateFinishedEvaluationMetrics(ctx context.
 	r.Log.Info("Increasing evaluation count")
 
 	// metrics: increment evaluation counter
-	r.Meters.EvaluationCount.Add(ctx, 1, attrs...)
+	r.Meters.Add(ctx, metrics.EvaluationCount, 1, attrs...)
 
 	// metrics: add evaluation duration
 	duration := evaluation.Status.EndTime.Time.Sub(evaluation.Status.StartTime.Time)
-	r.Meters.EvaluationDuration.Record(ctx, duration.Seconds(), attrs...)
+	r.Meters.Record(ctx, metrics.EvaluationDuration, duration.Seconds(), attrs...)
 	return nil
 }
 
@@ -204,47 +268,99 @@ func (r *KeptnEvaluationReconciler) updateFinishedEvaluationMetrics(ctx context.
 func (r *KeptnEvaluationReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	return ctrl.NewControllerManagedBy(mgr).
 		For(&klcv1alpha1.KeptnEvaluation{}, builder.WithPredicates(predicate.GenerationChangedPredicate{})).
-		Complete(r)
+		Complete(selfmonitoring.Wrap("keptnevaluation", r))
 }
 
-func (r *KeptnEvaluationReconciler) fetchDefinitionAndProvider(ctx context.Context, namespacedDefinition types.NamespacedName) (*klcv1alpha1.KeptnEvaluationDefinition, *klcv1alpha1.KeptnEvaluationProvider, error) {
+// fetchDefinitionAndProviders returns the evaluation definition and the providers of its objectives. It fails if the
+// provider of an objective with a single source is unavailable, while unavailable providers of objectives with multiple
+// sources are left out.
+func (r *KeptnEvaluationReconciler) fetchDefinitionAndProviders(ctx context.Context, namespacedDefinition types.NamespacedName) (*klcv1alpha1.KeptnEvaluationDefinition, map[string]klcv1alpha1.KeptnEvaluationProvider, error) {
 	evaluationDefinition := &klcv1alpha1.KeptnEvaluationDefinition{}
 
 	if err := r.Client.Get(ctx, namespacedDefinition, evaluationDefinition); err != nil {
 		return nil, nil, err
 	}
 
-	namespacedProvider := types.NamespacedName{
-		Namespace: namespacedDefinition.Namespace,
-		Name:      evaluationDefinition.Spec.Source,
-	}
+	evaluationProviders := map[string]klcv1alpha1.KeptnEvaluationProvider{}
+	for _, objective := range evaluationDefinition.Spec.Objectives {
+		if objective.KeptnMetric != "" {
+			continue
+		}
+		sources := objective.GetSources(evaluationDefinition.Spec.Source)
+		for _, source := range sources {
+			if _, ok := evaluationProviders[source]; ok {
+				continue
+			}
+			namespacedProvider := types.NamespacedName{
+				Namespace: namespacedDefinition.Namespace,
+				Name:      source,
+			}
 
-	evaluationProvider := &klcv1alpha1.KeptnEvaluationProvider{}
+			evaluationProvider := &klcv1alpha1.KeptnEvaluationProvider{}
 
-	if err := r.Client.Get(ctx, namespacedProvider, evaluationProvider); err != nil {
-		return nil, nil, err
+			if err := r.Client.Get(ctx, namespacedProvider, evaluationProvider); err != nil {
+				err = fmt.Errorf("could not retrieve KeptnEvaluationProvider %s: %w", source, controllererrors.Wrap(controllererrors.ErrProviderUnavailable, err))
+				if len(sources) == 1 {
+					return nil, nil, err
+				}
+				// the provider is missing from the map, so that the quorum policy of the objective decides whether it
+				// passes without it
+				r.Log.Info(err.Error())
+				continue
+			}
+			evaluationProviders[source] = *evaluationProvider
+		}
 	}
 
-	return evaluationDefinition, evaluationProvider, nil
+	return evaluationDefinition, evaluationProviders, nil
 }
 
-func (r *KeptnEvaluationReconciler) queryEvaluation(objective klcv1alpha1.Objective, provider klcv1alpha1.KeptnEvaluationProvider) *klcv1alpha1.EvaluationStatusItem {
+func (r *KeptnEvaluationReconciler) queryEvaluation(ctx context.Context, objective klcv1alpha1.Objective, previous *float64, provider klcv1alpha1.KeptnEvaluationProvider) *klcv1alpha1.EvaluationStatusItem {
 	query := &klcv1alpha1.EvaluationStatusItem{
 		Value:  "",
 		Status: common.StateFailed, //setting status per default to failed
 	}
 
 	queryTime := time.Now().UTC()
-	r.Log.Info("Running query: /api/v1/query?query=" + objective.Query + "&time=" + queryTime.String())
+	query.Query = r.Redactor.Query(objective.Query, objective.Secure)
+	r.Log.Info("Running query: /api/v1/query?query=" + query.Query + "&time=" + queryTime.String())
+
+	query.QueryStart = metav1.NewTime(queryTime)
+	query.QueryEnd = metav1.NewTime(queryTime)
+	defer r.addQueryEvent(ctx, objective, provider, query)
 
-	client, err := promapi.NewClient(promapi.Config{Address: provider.Spec.TargetServer, Client: &http.Client{}})
+	ctx, span := r.Tracer.Start(ctx, "query_provider", trace.WithSpanKind(trace.SpanKindClient))
+	defer span.End()
+	span.SetAttributes(
+		common.EvaluationObjective.String(objective.Name),
+		common.ProviderName.String(provider.Name),
+		common.EvaluationQuery.String(query.Query),
+	)
+
+	httpClient, err := r.ProviderClients.Get(ctx, r.Client, &provider)
+	if err != nil {
+		query.Message = err.Error()
+		return query
+	}
+
+	if provider.IsDynatrace() {
+		return r.queryDynatraceEvaluation(ctx, httpClient, provider, objective, previous, queryTime, query)
+	}
+
+	client, err := promapi.NewClient(promapi.Config{Address: provider.Spec.TargetServer, Client: httpClient})
 	api := prometheus.NewAPI(client)
+
+	if objective.Window.Duration > 0 {
+		return r.queryWindowEvaluation(ctx, api, objective, previous, queryTime, query)
+	}
+
 	result, w, err := api.Query(
-		context.Background(),
+		ctx,
 		objective.Query,
 		queryTime,
 		[]prometheus.Option{}...,
 	)
+	query.Response = r.getResponse(objective, resultString(result))
 
 	if err != nil {
 		query.Message = err.Error()
@@ -276,7 +392,7 @@ func (r *KeptnEvaluationReconciler) queryEvaluation(objective klcv1alpha1.Object
 	}
 
 	query.Value = resultVector[0].Value.String()
-	check, err := r.checkValue(objective, query)
+	check, err := r.checkValue(objective, previous, query)
 
 	if err != nil {
 		query.Message = err.Error()
@@ -288,59 +404,34 @@ func (r *KeptnEvaluationReconciler) queryEvaluation(objective klcv1alpha1.Object
 	return query
 }
 
-func (r *KeptnEvaluationReconciler) checkValue(objective klcv1alpha1.Objective, query *klcv1alpha1.EvaluationStatusItem) (bool, error) {
+// checkValue compares the value of the query to the evaluation target of the objective.
+// Relative targets pass if there is no value of the previous version to compare to.
+func (r *KeptnEvaluationReconciler) checkValue(objective klcv1alpha1.Objective, previous *float64, query *klcv1alpha1.EvaluationStatusItem) (bool, error) {
 
 	if len(query.Value) == 0 || len(objective.EvaluationTarget) == 0 {
 		return false, fmt.Errorf("no values")
 	}
 
-	eval := objective.EvaluationTarget[1:]
-	sign := objective.EvaluationTarget[:1]
-
-	resultValue, err := strconv.ParseFloat(query.Value, 64)
-	if err != nil || math.IsNaN(resultValue) {
+	target, err := common.ParseTarget(objective.EvaluationTarget)
+	if err != nil {
 		return false, err
 	}
 
-	compareValue, err := strconv.ParseFloat(eval, 64)
-	if err != nil || math.IsNaN(compareValue) {
+	resultValue, err := strconv.ParseFloat(query.Value, 64)
+	if err != nil {
 		return false, err
 	}
 
-	// choose comparator
-	switch sign {
-	case ">":
-		return resultValue > compareValue, nil
-	case "<":
-		return resultValue < compareValue, nil
-	default:
-		return false, fmt.Errorf("invalid operator")
+	if !target.Relative {
+		return target.Check(resultValue, 0)
 	}
-}
-
-func (r *KeptnEvaluationReconciler) recordEvent(eventType string, evaluation *klcv1alpha1.KeptnEvaluation, shortReason string, longReason string) {
-	r.Recorder.Event(evaluation, eventType, shortReason, fmt.Sprintf("%s / Namespace: %s, Name: %s, WorkloadVersion: %s ", longReason, evaluation.Namespace, evaluation.Name, evaluation.Spec.WorkloadVersion))
-}
-
-func (r *KeptnEvaluationReconciler) GetActiveEvaluations(ctx context.Context) ([]common.GaugeValue, error) {
-	evaluations := &klcv1alpha1.KeptnEvaluationList{}
-	err := r.List(ctx, evaluations)
-	if err != nil {
-		return nil, fmt.Errorf("could not retrieve workload instances: %w", err)
-	}
-
-	res := []common.GaugeValue{}
-
-	for _, evaluation := range evaluations.Items {
-		gaugeValue := int64(0)
-		if !evaluation.IsEndTimeSet() {
-			gaugeValue = int64(1)
-		}
-		res = append(res, common.GaugeValue{
-			Value:      gaugeValue,
-			Attributes: evaluation.GetActiveMetricsAttributes(),
-		})
+	if previous == nil {
+		query.Message = "no value of the previous version to compare to"
+		return true, nil
 	}
+	return target.Check(resultValue, *previous)
+}
 
-	return res, nil
+func (r *KeptnEvaluationReconciler) recordEvent(evaluation *klcv1alpha1.KeptnEvaluation, reason reasons.Reason, args ...interface{}) {
+	r.Recorder.AnnotatedEventf(evaluation, reason.Annotations(), string(reason.Severity), reason.Code, "%s / Namespace: %s, Name: %s, WorkloadVersion: %s ", reason.Message(args...), evaluation.Namespace, evaluation.Name, evaluation.Spec.WorkloadVersion)
 }
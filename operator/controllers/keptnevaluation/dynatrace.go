@@ -0,0 +1,58 @@
+package keptnevaluation
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	klcv1alpha1 "github.com/keptn/lifecycle-controller/operator/api/v1alpha1"
+	"github.com/keptn/lifecycle-controller/operator/api/v1alpha1/common"
+	"github.com/keptn/lifecycle-controller/operator/controllers/keptnevaluationprovider"
+	"github.com/prometheus/common/model"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// queryDynatraceEvaluation runs the metric selector of the objective against a Dynatrace provider and compares its latest
+// value, or the aggregated values within the window of the objective, to the evaluation target
+func (r *KeptnEvaluationReconciler) queryDynatraceEvaluation(ctx context.Context, httpClient *http.Client, provider klcv1alpha1.KeptnEvaluationProvider, objective klcv1alpha1.Objective, previous *float64, queryTime time.Time, query *klcv1alpha1.EvaluationStatusItem) *klcv1alpha1.EvaluationStatusItem {
+	var value float64
+	if objective.Window.Duration > 0 {
+		step := windowStep(objective)
+		from := queryTime.Add(-objective.Window.Duration)
+		query.QueryStart = metav1.NewTime(from)
+
+		values, response, err := keptnevaluationprovider.QueryDynatrace(ctx, httpClient, &provider, objective.Query, from, queryTime, keptnevaluationprovider.DynatraceResolution(step))
+		query.Response = r.getResponse(objective, response)
+		if err != nil {
+			query.Message = err.Error()
+			return query
+		}
+		samples := make([]model.SamplePair, 0, len(values))
+		for _, v := range values {
+			samples = append(samples, model.SamplePair{Value: model.SampleValue(v)})
+		}
+		if value, err = aggregate(objective.Aggregation, samples); err != nil {
+			query.Message = err.Error()
+			return query
+		}
+	} else {
+		latest, response, err := keptnevaluationprovider.LatestDynatraceValue(ctx, httpClient, &provider, objective.Query, queryTime)
+		query.Response = r.getResponse(objective, response)
+		if err != nil {
+			query.Message = err.Error()
+			return query
+		}
+		value = latest
+	}
+
+	query.Value = model.SampleValue(value).String()
+	check, err := r.checkValue(objective, previous, query)
+	if err != nil {
+		query.Message = err.Error()
+		r.Log.Error(err, "Could not check query result")
+	}
+	if check {
+		query.Status = common.StateSucceeded
+	}
+	return query
+}
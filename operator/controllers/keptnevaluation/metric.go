@@ -0,0 +1,47 @@
+package keptnevaluation
+
+import (
+	"context"
+	"fmt"
+
+	klcv1alpha1 "github.com/keptn/lifecycle-controller/operator/api/v1alpha1"
+	"github.com/keptn/lifecycle-controller/operator/api/v1alpha1/common"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// evaluateMetric compares the cached value of the KeptnMetric of the objective to the evaluation target without querying the provider
+func (r *KeptnEvaluationReconciler) evaluateMetric(ctx context.Context, evaluation *klcv1alpha1.KeptnEvaluation, objective klcv1alpha1.Objective) *klcv1alpha1.EvaluationStatusItem {
+	query := &klcv1alpha1.EvaluationStatusItem{
+		Value:  "",
+		Status: common.StateFailed, //setting status per default to failed
+	}
+
+	metric := &klcv1alpha1.KeptnMetric{}
+	if err := r.Client.Get(ctx, types.NamespacedName{Namespace: evaluation.Namespace, Name: objective.KeptnMetric}, metric); err != nil {
+		query.Message = fmt.Sprintf("could not retrieve KeptnMetric %s: %s", objective.KeptnMetric, err.Error())
+		return query
+	}
+	query.Query = r.Redactor.Query(metric.Spec.Query, objective.Secure)
+	query.QueryStart = metric.Status.LastUpdated
+	query.QueryEnd = metric.Status.LastUpdated
+	if metric.Status.Value == "" {
+		query.Message = fmt.Sprintf("KeptnMetric %s has no value yet", metric.Name)
+		return query
+	}
+	query.Value = metric.Status.Value
+
+	previous, err := r.getPreviousValue(ctx, evaluation, objective)
+	if err != nil {
+		query.Message = err.Error()
+		return query
+	}
+	check, err := r.checkValue(objective, previous, query)
+	if err != nil {
+		query.Message = err.Error()
+		r.Log.Error(err, "Could not check value of KeptnMetric "+metric.Name)
+	}
+	if check {
+		query.Status = common.StateSucceeded
+	}
+	return query
+}
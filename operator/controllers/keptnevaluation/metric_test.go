@@ -0,0 +1,54 @@
+package keptnevaluation
+
+import (
+	"context"
+	"testing"
+
+	"github.com/go-logr/logr"
+	klcv1alpha1 "github.com/keptn/lifecycle-controller/operator/api/v1alpha1"
+	"github.com/keptn/lifecycle-controller/operator/api/v1alpha1/common"
+	"github.com/keptn/lifecycle-controller/operator/redaction"
+	testrequire "github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestKeptnEvaluationReconciler_EvaluateMetric(t *testing.T) {
+	scheme := runtime.NewScheme()
+	testrequire.Nil(t, klcv1alpha1.AddToScheme(scheme))
+
+	metric := &klcv1alpha1.KeptnMetric{
+		ObjectMeta: metav1.ObjectMeta{Name: "error-rate", Namespace: "default"},
+		Spec:       klcv1alpha1.KeptnMetricSpec{Provider: "prometheus", Query: "sum(rate(http_errors[5m]))"},
+		Status:     klcv1alpha1.KeptnMetricStatus{Value: "0.5"},
+	}
+	pending := &klcv1alpha1.KeptnMetric{
+		ObjectMeta: metav1.ObjectMeta{Name: "latency", Namespace: "default"},
+		Spec:       klcv1alpha1.KeptnMetricSpec{Provider: "prometheus", Query: "http_latency"},
+	}
+	r := &KeptnEvaluationReconciler{
+		Client: fake.NewClientBuilder().WithScheme(scheme).WithObjects(metric, pending).Build(),
+		Log:    logr.Discard(),
+	}
+	evaluation := &klcv1alpha1.KeptnEvaluation{ObjectMeta: metav1.ObjectMeta{Name: "post-eval", Namespace: "default"}}
+
+	result := r.evaluateMetric(context.TODO(), evaluation, klcv1alpha1.Objective{Name: "errors", KeptnMetric: "error-rate", EvaluationTarget: "<1"})
+	testrequire.Equal(t, common.StateSucceeded, result.Status)
+	testrequire.Equal(t, "0.5", result.Value)
+	testrequire.Equal(t, metric.Spec.Query, result.Query)
+
+	result = r.evaluateMetric(context.TODO(), evaluation, klcv1alpha1.Objective{Name: "errors", KeptnMetric: "error-rate", EvaluationTarget: "<0.1"})
+	testrequire.Equal(t, common.StateFailed, result.Status)
+
+	result = r.evaluateMetric(context.TODO(), evaluation, klcv1alpha1.Objective{Name: "errors", KeptnMetric: "error-rate", EvaluationTarget: "<1", Secure: true})
+	testrequire.Equal(t, common.StateSucceeded, result.Status)
+	testrequire.Equal(t, redaction.Placeholder, result.Query)
+
+	result = r.evaluateMetric(context.TODO(), evaluation, klcv1alpha1.Objective{Name: "latency", KeptnMetric: "latency", EvaluationTarget: "<1"})
+	testrequire.Equal(t, common.StateFailed, result.Status)
+	testrequire.Contains(t, result.Message, "no value yet")
+
+	result = r.evaluateMetric(context.TODO(), evaluation, klcv1alpha1.Objective{Name: "missing", KeptnMetric: "missing", EvaluationTarget: "<1"})
+	testrequire.Equal(t, common.StateFailed, result.Status)
+}
@@ -0,0 +1,64 @@
+package keptnevaluation
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	klcv1alpha1 "github.com/keptn/lifecycle-controller/operator/api/v1alpha1"
+	"github.com/keptn/lifecycle-controller/operator/api/v1alpha1/common"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// getPreviousValue returns the value of the objective in the latest succeeded evaluation of another version of the same workload or app.
+// It returns nil if the evaluation target of the objective is not relative or if no previous value exists.
+func (r *KeptnEvaluationReconciler) getPreviousValue(ctx context.Context, evaluation *klcv1alpha1.KeptnEvaluation, objective klcv1alpha1.Objective) (*float64, error) {
+	target, err := common.ParseTarget(objective.EvaluationTarget)
+	if err != nil {
+		return nil, err
+	}
+	if !target.Relative {
+		return nil, nil
+	}
+
+	evaluations := &klcv1alpha1.KeptnEvaluationList{}
+	if err := r.Client.List(ctx, evaluations, client.InNamespace(evaluation.Namespace)); err != nil {
+		return nil, fmt.Errorf("could not retrieve evaluations: %w", err)
+	}
+
+	var latest *klcv1alpha1.KeptnEvaluation
+	for i := range evaluations.Items {
+		candidate := &evaluations.Items[i]
+		if !isPreviousEvaluation(evaluation, candidate) {
+			continue
+		}
+		if _, ok := candidate.Status.EvaluationStatus[objective.Name]; !ok {
+			continue
+		}
+		if latest == nil || candidate.Status.EndTime.After(latest.Status.EndTime.Time) {
+			latest = candidate
+		}
+	}
+	if latest == nil {
+		return nil, nil
+	}
+
+	value, err := strconv.ParseFloat(latest.Status.EvaluationStatus[objective.Name].Value, 64)
+	if err != nil {
+		return nil, fmt.Errorf("could not parse value of objective %s in evaluation %s: %w", objective.Name, latest.Name, err)
+	}
+	return &value, nil
+}
+
+// isPreviousEvaluation returns whether the candidate is a succeeded evaluation of the same definition and check type for another version
+func isPreviousEvaluation(evaluation *klcv1alpha1.KeptnEvaluation, candidate *klcv1alpha1.KeptnEvaluation) bool {
+	if !candidate.Status.OverallStatus.IsSucceeded() ||
+		candidate.Spec.EvaluationDefinition != evaluation.Spec.EvaluationDefinition ||
+		candidate.Spec.Type != evaluation.Spec.Type {
+		return false
+	}
+	if evaluation.Spec.Workload != "" {
+		return candidate.Spec.Workload == evaluation.Spec.Workload && candidate.Spec.WorkloadVersion != evaluation.Spec.WorkloadVersion
+	}
+	return candidate.Spec.Workload == "" && candidate.Spec.AppName == evaluation.Spec.AppName && candidate.Spec.AppVersion != evaluation.Spec.AppVersion
+}
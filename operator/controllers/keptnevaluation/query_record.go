@@ -0,0 +1,40 @@
+package keptnevaluation
+
+import (
+	"context"
+	"time"
+
+	klcv1alpha1 "github.com/keptn/lifecycle-controller/operator/api/v1alpha1"
+	"github.com/keptn/lifecycle-controller/operator/api/v1alpha1/common"
+	"github.com/prometheus/common/model"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const maxResponseLength = 1024
+
+// addQueryEvent adds the query sent to a provider and its response as an event to the span of the evaluation,
+// so that a failed evaluation can be reproduced in the UI of the provider
+func (r *KeptnEvaluationReconciler) addQueryEvent(ctx context.Context, objective klcv1alpha1.Objective, provider klcv1alpha1.KeptnEvaluationProvider, query *klcv1alpha1.EvaluationStatusItem) {
+	trace.SpanFromContext(ctx).AddEvent("query", trace.WithAttributes(
+		common.EvaluationObjective.String(objective.Name),
+		common.ProviderName.String(provider.Name),
+		common.EvaluationQuery.String(query.Query),
+		common.EvaluationQueryStart.String(query.QueryStart.UTC().Format(time.RFC3339)),
+		common.EvaluationQueryEnd.String(query.QueryEnd.UTC().Format(time.RFC3339)),
+		common.EvaluationResponse.String(query.Response),
+		common.EvaluationStatus.String(string(query.Status)),
+	))
+}
+
+// getResponse redacts the response of a provider the same way as the query of the objective, since it may contain the
+// labels the query matched on, and truncates it
+func (r *KeptnEvaluationReconciler) getResponse(objective klcv1alpha1.Objective, response string) string {
+	return common.TruncateString(r.Redactor.Query(response, objective.Secure), maxResponseLength)
+}
+
+func resultString(result model.Value) string {
+	if result == nil {
+		return ""
+	}
+	return result.String()
+}
@@ -0,0 +1,84 @@
+package keptnevaluation
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-logr/logr"
+	klcv1alpha1 "github.com/keptn/lifecycle-controller/operator/api/v1alpha1"
+	"github.com/keptn/lifecycle-controller/operator/api/v1alpha1/common"
+	"github.com/keptn/lifecycle-controller/operator/controllers/keptnevaluationprovider"
+	testrequire "github.com/stretchr/testify/require"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestKeptnEvaluationReconciler_QueryEvaluationRedactsResponse(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"status":"success","data":{"resultType":"vector","result":[{"metric":{"job":"api","token":"s3cr3t"},"value":[1700000000,"1"]}]}}`))
+	}))
+	defer server.Close()
+	provider := klcv1alpha1.KeptnEvaluationProvider{
+		ObjectMeta: metav1.ObjectMeta{Name: "prometheus", Namespace: "default"},
+		Spec:       klcv1alpha1.KeptnEvaluationProviderSpec{TargetServer: server.URL},
+	}
+
+	tests := []struct {
+		name         string
+		secure       bool
+		wantQuery    string
+		wantResponse string
+	}{
+		{
+			name:         "sensitive labels",
+			wantQuery:    `up{token="***"}`,
+			wantResponse: `{job="api", token="***"} => 1 @[1700000000]`,
+		},
+		{
+			name:         "secure objective",
+			secure:       true,
+			wantQuery:    "***",
+			wantResponse: "***",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			spanRecorder := tracetest.NewSpanRecorder()
+			tracer := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(spanRecorder)).Tracer("test")
+			r := &KeptnEvaluationReconciler{
+				Log:             logr.Discard(),
+				Tracer:          tracer,
+				ProviderClients: keptnevaluationprovider.NewClientCache(),
+			}
+			objective := klcv1alpha1.Objective{Name: "up", Query: `up{token="s3cr3t"}`, EvaluationTarget: ">0", Secure: tt.secure}
+
+			ctx, span := tracer.Start(context.TODO(), "evaluation")
+			query := r.queryEvaluation(ctx, objective, nil, provider)
+			span.End()
+
+			testrequire.Equal(t, common.StateSucceeded, query.Status)
+			testrequire.Equal(t, "1", query.Value)
+			testrequire.Equal(t, tt.wantQuery, query.Query)
+			testrequire.Equal(t, tt.wantResponse, query.Response)
+
+			var events []sdktrace.Event
+			for _, ended := range spanRecorder.Ended() {
+				if ended.Name() == "evaluation" {
+					events = ended.Events()
+				}
+			}
+			testrequire.Len(t, events, 1)
+			attributes := map[string]string{}
+			for _, attribute := range events[0].Attributes {
+				attributes[string(attribute.Key)] = attribute.Value.AsString()
+			}
+			testrequire.Equal(t, tt.wantQuery, attributes[string(common.EvaluationQuery)])
+			testrequire.Equal(t, tt.wantResponse, attributes[string(common.EvaluationResponse)])
+			testrequire.Equal(t, string(common.StateSucceeded), attributes[string(common.EvaluationStatus)])
+		})
+	}
+}
@@ -0,0 +1,82 @@
+package keptnevaluation
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strings"
+	"text/template"
+	"time"
+
+	klcv1alpha1 "github.com/keptn/lifecycle-controller/operator/api/v1alpha1"
+	"github.com/keptn/lifecycle-controller/operator/api/v1alpha1/common"
+	"github.com/keptn/lifecycle-controller/operator/controllers/podselector"
+	"github.com/prometheus/common/model"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// QueryVariables are the values that can be used in the query of an objective, e.g. {{.Workload}}
+type QueryVariables struct {
+	App        string
+	AppVersion string
+	Workload   string
+	Version    string
+	Namespace  string
+	// PodSelector is a regular expression matching the names of the pods of the workload version
+	PodSelector string
+	// Window is the time range of the objective in the duration format of Prometheus, e.g. 5m
+	Window string
+}
+
+// renderQuery replaces the template variables in a query with the values of the evaluation and the given window
+func (r *KeptnEvaluationReconciler) renderQuery(ctx context.Context, evaluation *klcv1alpha1.KeptnEvaluation, query string, window time.Duration) (string, error) {
+	if !strings.Contains(query, "{{") {
+		return query, nil
+	}
+
+	tmpl, err := template.New("query").Option("missingkey=error").Parse(query)
+	if err != nil {
+		return "", fmt.Errorf("could not parse query template: %w", err)
+	}
+
+	variables := QueryVariables{
+		App:        evaluation.Spec.AppName,
+		AppVersion: evaluation.Spec.AppVersion,
+		Workload:   evaluation.Spec.Workload,
+		Version:    evaluation.Spec.WorkloadVersion,
+		Namespace:  evaluation.Namespace,
+		Window:     model.Duration(window).String(),
+	}
+	if strings.Contains(query, ".PodSelector") {
+		if variables.PodSelector, err = r.getPodSelector(ctx, evaluation); err != nil {
+			return "", err
+		}
+	}
+
+	var rendered bytes.Buffer
+	if err := tmpl.Execute(&rendered, variables); err != nil {
+		return "", fmt.Errorf("could not render query template: %w", err)
+	}
+	return rendered.String(), nil
+}
+
+// getPodSelector returns a regular expression matching the names of the pods of the evaluated workload version
+func (r *KeptnEvaluationReconciler) getPodSelector(ctx context.Context, evaluation *klcv1alpha1.KeptnEvaluation) (string, error) {
+	if evaluation.Spec.Workload == "" {
+		return "", fmt.Errorf("PodSelector can only be used in evaluations of workloads")
+	}
+
+	workloadInstance := &klcv1alpha1.KeptnWorkloadInstance{}
+	name := common.CreateInstanceName(evaluation.Spec.Workload, evaluation.Spec.WorkloadVersion)
+	if err := r.Client.Get(ctx, types.NamespacedName{Namespace: evaluation.Namespace, Name: name}, workloadInstance); err != nil {
+		return "", fmt.Errorf("could not retrieve KeptnWorkloadInstance %s: %w", name, err)
+	}
+	if err := workloadInstance.VerifyIdentity(evaluation.Spec.Workload, evaluation.Spec.WorkloadVersion); err != nil {
+		return "", fmt.Errorf("could not retrieve KeptnWorkloadInstance %s: %w", name, err)
+	}
+	pattern, err := podselector.Pattern(ctx, r.Client, workloadInstance.Spec.ResourceReference, evaluation.Namespace)
+	if err != nil {
+		return "", fmt.Errorf("could not find the pods of KeptnWorkloadInstance %s: %w", name, err)
+	}
+	return pattern, nil
+}
@@ -0,0 +1,50 @@
+package keptnevaluation
+
+import (
+	"context"
+	"testing"
+
+	klcv1alpha1 "github.com/keptn/lifecycle-controller/operator/api/v1alpha1"
+	testrequire "github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestKeptnEvaluationReconciler_RenderQuery(t *testing.T) {
+	scheme := runtime.NewScheme()
+	testrequire.Nil(t, klcv1alpha1.AddToScheme(scheme))
+	testrequire.Nil(t, appsv1.AddToScheme(scheme))
+
+	workloadInstance := &klcv1alpha1.KeptnWorkloadInstance{
+		ObjectMeta: metav1.ObjectMeta{Name: "myapp-frontend-1.0.0", Namespace: "default"},
+		Spec: klcv1alpha1.KeptnWorkloadInstanceSpec{
+			KeptnWorkloadSpec: klcv1alpha1.KeptnWorkloadSpec{
+				ResourceReference: klcv1alpha1.ResourceReference{UID: "rs-uid", Kind: "ReplicaSet"},
+			},
+		},
+	}
+	replicaSet := &appsv1.ReplicaSet{ObjectMeta: metav1.ObjectMeta{Name: "frontend-5d8f7c", Namespace: "default", UID: "rs-uid"}}
+	r := &KeptnEvaluationReconciler{Client: fake.NewClientBuilder().WithScheme(scheme).WithObjects(workloadInstance, replicaSet).Build()}
+
+	evaluation := &klcv1alpha1.KeptnEvaluation{
+		ObjectMeta: metav1.ObjectMeta{Name: "post-eval", Namespace: "default"},
+		Spec: klcv1alpha1.KeptnEvaluationSpec{
+			AppName:         "myapp",
+			Workload:        "myapp-frontend",
+			WorkloadVersion: "1.0.0",
+		},
+	}
+
+	query, err := r.renderQuery(context.TODO(), evaluation, `http_errors{app="{{.App}}",workload="{{.Workload}}",version="{{.Version}}",namespace="{{.Namespace}}",pod=~"{{.PodSelector}}"}`, 0)
+	testrequire.Nil(t, err)
+	testrequire.Equal(t, `http_errors{app="myapp",workload="myapp-frontend",version="1.0.0",namespace="default",pod=~"frontend-5d8f7c-[a-z0-9]{5}"}`, query)
+
+	_, err = r.renderQuery(context.TODO(), evaluation, `http_errors{app="{{.Unknown}}"}`, 0)
+	testrequire.NotNil(t, err)
+
+	evaluation.Spec.Workload = ""
+	_, err = r.renderQuery(context.TODO(), evaluation, `http_errors{pod=~"{{.PodSelector}}"}`, 0)
+	testrequire.NotNil(t, err)
+}
@@ -0,0 +1,73 @@
+package keptnevaluation
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	klcv1alpha1 "github.com/keptn/lifecycle-controller/operator/api/v1alpha1"
+	"github.com/keptn/lifecycle-controller/operator/api/v1alpha1/common"
+)
+
+// evaluateObjective runs the query of the objective against each of its providers and combines the results according to its quorum policy.
+// Unavailable providers count as failed.
+func (r *KeptnEvaluationReconciler) evaluateObjective(ctx context.Context, objective klcv1alpha1.Objective, previous *float64, defaultSource string, providers map[string]klcv1alpha1.KeptnEvaluationProvider) *klcv1alpha1.EvaluationStatusItem {
+	sources := objective.GetSources(defaultSource)
+	if len(sources) == 1 {
+		return r.queryEvaluation(ctx, objective, previous, providers[sources[0]])
+	}
+
+	results := make([]*klcv1alpha1.EvaluationStatusItem, 0, len(sources))
+	for _, source := range sources {
+		provider, ok := providers[source]
+		if !ok {
+			results = append(results, &klcv1alpha1.EvaluationStatusItem{
+				Status:  common.StateFailed,
+				Query:   r.Redactor.Query(objective.Query, objective.Secure),
+				Message: fmt.Sprintf("KeptnEvaluationProvider %s is unavailable", source),
+			})
+			continue
+		}
+		results = append(results, r.queryEvaluation(ctx, objective, previous, provider))
+	}
+	return combineResults(objective.QuorumPolicy, sources, results)
+}
+
+// combineResults merges the results of multiple providers into a single status item.
+// The value is taken from the first provider that returned one.
+func combineResults(policy klcv1alpha1.QuorumPolicy, sources []string, results []*klcv1alpha1.EvaluationStatusItem) *klcv1alpha1.EvaluationStatusItem {
+	if policy == "" {
+		policy = klcv1alpha1.QuorumAll
+	}
+
+	combined := &klcv1alpha1.EvaluationStatusItem{
+		Status:     common.StateFailed,
+		Query:      results[0].Query,
+		QueryStart: results[0].QueryStart,
+		QueryEnd:   results[0].QueryEnd,
+	}
+	responses := make([]string, 0, len(results))
+	passed := 0
+	details := make([]string, 0, len(results))
+	for i, result := range results {
+		if combined.Value == "" {
+			combined.Value = result.Value
+		}
+		responses = append(responses, fmt.Sprintf("%s: %s", sources[i], result.Response))
+		if result.Status.IsSucceeded() {
+			passed++
+			details = append(details, fmt.Sprintf("%s: passed with value %s", sources[i], result.Value))
+		} else if result.Message != "" {
+			details = append(details, fmt.Sprintf("%s: failed: %s", sources[i], result.Message))
+		} else {
+			details = append(details, fmt.Sprintf("%s: failed with value %s", sources[i], result.Value))
+		}
+	}
+
+	if policy.IsReached(passed, len(results)) {
+		combined.Status = common.StateSucceeded
+	}
+	combined.Response = common.TruncateString(strings.Join(responses, "\n"), maxResponseLength)
+	combined.Message = fmt.Sprintf("%d of %d providers passed, quorum policy %s: %s", passed, len(results), policy, strings.Join(details, "; "))
+	return combined
+}
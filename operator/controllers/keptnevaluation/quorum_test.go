@@ -0,0 +1,95 @@
+package keptnevaluation
+
+import (
+	"context"
+	"testing"
+
+	"github.com/go-logr/logr"
+	klcv1alpha1 "github.com/keptn/lifecycle-controller/operator/api/v1alpha1"
+	"github.com/keptn/lifecycle-controller/operator/api/v1alpha1/common"
+	controllererrors "github.com/keptn/lifecycle-controller/operator/controllers/errors"
+	testrequire "github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestCombineResults(t *testing.T) {
+	sources := []string{"prometheus-eu", "prometheus-us", "prometheus-ap"}
+	results := []*klcv1alpha1.EvaluationStatusItem{
+		{Value: "", Status: common.StateFailed, Message: "connection refused"},
+		{Value: "0.4", Status: common.StateSucceeded},
+		{Value: "0.2", Status: common.StateSucceeded},
+	}
+
+	tests := []struct {
+		policy klcv1alpha1.QuorumPolicy
+		want   common.KeptnState
+	}{
+		{policy: "", want: common.StateFailed},
+		{policy: klcv1alpha1.QuorumAll, want: common.StateFailed},
+		{policy: klcv1alpha1.QuorumAny, want: common.StateSucceeded},
+		{policy: klcv1alpha1.QuorumMajority, want: common.StateSucceeded},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.policy), func(t *testing.T) {
+			combined := combineResults(tt.policy, sources, results)
+			testrequire.Equal(t, tt.want, combined.Status)
+			testrequire.Equal(t, "0.4", combined.Value)
+			testrequire.Contains(t, combined.Message, "2 of 3 providers passed")
+			testrequire.Contains(t, combined.Message, "prometheus-eu: failed: connection refused")
+		})
+	}
+}
+
+func TestQuorumPolicy_IsReached(t *testing.T) {
+	testrequire.False(t, klcv1alpha1.QuorumMajority.IsReached(1, 2))
+	testrequire.True(t, klcv1alpha1.QuorumMajority.IsReached(2, 3))
+	testrequire.False(t, klcv1alpha1.QuorumAny.IsReached(0, 2))
+	testrequire.True(t, klcv1alpha1.QuorumAll.IsReached(2, 2))
+}
+
+func TestKeptnEvaluationReconciler_FetchDefinitionAndProviders(t *testing.T) {
+	scheme := runtime.NewScheme()
+	testrequire.Nil(t, klcv1alpha1.AddToScheme(scheme))
+
+	quorumDefinition := &klcv1alpha1.KeptnEvaluationDefinition{
+		ObjectMeta: metav1.ObjectMeta{Name: "quorum", Namespace: "default"},
+		Spec: klcv1alpha1.KeptnEvaluationDefinitionSpec{
+			Source:     "prometheus-eu",
+			Objectives: []klcv1alpha1.Objective{{Name: "errors", Query: "errors", Sources: []string{"prometheus-eu", "prometheus-us"}, QuorumPolicy: klcv1alpha1.QuorumAny}},
+		},
+	}
+	singleDefinition := &klcv1alpha1.KeptnEvaluationDefinition{
+		ObjectMeta: metav1.ObjectMeta{Name: "single", Namespace: "default"},
+		Spec: klcv1alpha1.KeptnEvaluationDefinitionSpec{
+			Source:     "prometheus-us",
+			Objectives: []klcv1alpha1.Objective{{Name: "errors", Query: "errors"}},
+		},
+	}
+	provider := &klcv1alpha1.KeptnEvaluationProvider{ObjectMeta: metav1.ObjectMeta{Name: "prometheus-eu", Namespace: "default"}}
+	r := &KeptnEvaluationReconciler{
+		Client: fake.NewClientBuilder().WithScheme(scheme).WithObjects(quorumDefinition, singleDefinition, provider).Build(),
+		Log:    logr.Discard(),
+	}
+
+	// the missing provider of an objective with multiple sources is left out
+	_, providers, err := r.fetchDefinitionAndProviders(context.TODO(), types.NamespacedName{Name: "quorum", Namespace: "default"})
+	testrequire.Nil(t, err)
+	testrequire.Len(t, providers, 1)
+	testrequire.Contains(t, providers, "prometheus-eu")
+
+	_, _, err = r.fetchDefinitionAndProviders(context.TODO(), types.NamespacedName{Name: "single", Namespace: "default"})
+	testrequire.True(t, controllererrors.Is(err, controllererrors.ErrProviderUnavailable))
+}
+
+func TestKeptnEvaluationReconciler_EvaluateObjectiveWithUnavailableProviders(t *testing.T) {
+	r := &KeptnEvaluationReconciler{Log: logr.Discard()}
+	objective := klcv1alpha1.Objective{Name: "errors", Query: "errors", Sources: []string{"prometheus-eu", "prometheus-us"}, QuorumPolicy: klcv1alpha1.QuorumAny}
+
+	result := r.evaluateObjective(context.TODO(), objective, nil, "", map[string]klcv1alpha1.KeptnEvaluationProvider{})
+	testrequire.Equal(t, common.StateFailed, result.Status)
+	testrequire.Contains(t, result.Message, "0 of 2 providers passed")
+	testrequire.Contains(t, result.Message, "prometheus-us: failed: KeptnEvaluationProvider prometheus-us is unavailable")
+}
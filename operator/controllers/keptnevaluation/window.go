@@ -0,0 +1,125 @@
+package keptnevaluation
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	klcv1alpha1 "github.com/keptn/lifecycle-controller/operator/api/v1alpha1"
+	"github.com/keptn/lifecycle-controller/operator/api/v1alpha1/common"
+	prometheus "github.com/prometheus/client_golang/api/prometheus/v1"
+	"github.com/prometheus/common/model"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// minWindowStep is the smallest resolution of the samples within a window, since providers reject a step of zero
+const minWindowStep = time.Second
+
+// windowStep returns the step of the objective, which defaults to a tenth of its window, but is at least minWindowStep
+func windowStep(objective klcv1alpha1.Objective) time.Duration {
+	step := objective.Step.Duration
+	if step <= 0 {
+		step = objective.Window.Duration / 10
+	}
+	if step < minWindowStep {
+		step = minWindowStep
+	}
+	return step
+}
+
+// queryWindowEvaluation computes the query over the window of the objective and compares the aggregated samples to the evaluation target
+func (r *KeptnEvaluationReconciler) queryWindowEvaluation(ctx context.Context, api prometheus.API, objective klcv1alpha1.Objective, previous *float64, queryTime time.Time, query *klcv1alpha1.EvaluationStatusItem) *klcv1alpha1.EvaluationStatusItem {
+	step := windowStep(objective)
+	queryRange := prometheus.Range{
+		Start: queryTime.Add(-objective.Window.Duration),
+		End:   queryTime,
+		Step:  step,
+	}
+	r.Log.Info(fmt.Sprintf("Running query: /api/v1/query_range?query=%s&start=%s&end=%s&step=%s", query.Query, queryRange.Start, queryRange.End, step))
+
+	query.QueryStart = metav1.NewTime(queryRange.Start)
+	query.QueryEnd = metav1.NewTime(queryRange.End)
+
+	result, w, err := api.QueryRange(ctx, objective.Query, queryRange)
+	query.Response = r.getResponse(objective, resultString(result))
+	if err != nil {
+		query.Message = err.Error()
+		return query
+	}
+
+	if len(w) != 0 {
+		query.Message = w[0]
+		r.Log.Info("Prometheus API returned warnings: " + w[0])
+	}
+
+	resultMatrix, ok := result.(model.Matrix)
+	if !ok {
+		query.Message = "could not cast result"
+		return query
+	}
+
+	// same as for instant queries, the query has to return exactly one series
+	if len(resultMatrix) == 0 || len(resultMatrix[0].Values) == 0 {
+		r.Log.Info("No values in query result")
+		query.Message = "No values in query result"
+		return query
+	} else if len(resultMatrix) > 1 {
+		r.Log.Info("Too many values in the query result")
+		query.Message = "Too many values in the query result"
+		return query
+	}
+
+	value, err := aggregate(objective.Aggregation, resultMatrix[0].Values)
+	if err != nil {
+		query.Message = err.Error()
+		return query
+	}
+	query.Value = model.SampleValue(value).String()
+	check, err := r.checkValue(objective, previous, query)
+
+	if err != nil {
+		query.Message = err.Error()
+		r.Log.Error(err, "Could not check query result")
+	}
+	if check {
+		query.Status = common.StateSucceeded
+	}
+	return query
+}
+
+// aggregate combines the samples of a series using the given aggregation: avg, min, max or a percentile like p90
+func aggregate(aggregation string, samples []model.SamplePair) (float64, error) {
+	values := make([]float64, 0, len(samples))
+	for _, sample := range samples {
+		values = append(values, float64(sample.Value))
+	}
+	sort.Float64s(values)
+
+	switch aggregation {
+	case "", "avg":
+		sum := 0.0
+		for _, value := range values {
+			sum += value
+		}
+		return sum / float64(len(values)), nil
+	case "min":
+		return values[0], nil
+	case "max":
+		return values[len(values)-1], nil
+	}
+
+	if !strings.HasPrefix(aggregation, "p") {
+		return 0, fmt.Errorf("unknown aggregation %s", aggregation)
+	}
+	percentile, err := strconv.ParseFloat(aggregation[1:], 64)
+	if err != nil || percentile <= 0 || percentile > 100 {
+		return 0, fmt.Errorf("unknown aggregation %s", aggregation)
+	}
+	// nearest-rank method
+	rank := int(math.Ceil(percentile / 100 * float64(len(values))))
+	return values[rank-1], nil
+}
@@ -0,0 +1,59 @@
+package keptnevaluation
+
+import (
+	"testing"
+	"time"
+
+	klcv1alpha1 "github.com/keptn/lifecycle-controller/operator/api/v1alpha1"
+	"github.com/prometheus/common/model"
+	testrequire "github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestAggregate(t *testing.T) {
+	samples := []model.SamplePair{}
+	for i := 10; i >= 1; i-- {
+		samples = append(samples, model.SamplePair{Timestamp: model.Time(i), Value: model.SampleValue(i)})
+	}
+
+	tests := []struct {
+		aggregation string
+		want        float64
+	}{
+		{aggregation: "", want: 5.5},
+		{aggregation: "avg", want: 5.5},
+		{aggregation: "min", want: 1},
+		{aggregation: "max", want: 10},
+		{aggregation: "p90", want: 9},
+		{aggregation: "p95", want: 10},
+	}
+	for _, tt := range tests {
+		t.Run(tt.aggregation, func(t *testing.T) {
+			value, err := aggregate(tt.aggregation, samples)
+			testrequire.Nil(t, err)
+			testrequire.Equal(t, tt.want, value)
+		})
+	}
+
+	_, err := aggregate("median", samples)
+	testrequire.NotNil(t, err)
+}
+
+func TestWindowStep(t *testing.T) {
+	tests := []struct {
+		window time.Duration
+		step   time.Duration
+		want   time.Duration
+	}{
+		{window: 5 * time.Minute, want: 30 * time.Second},
+		{window: 5 * time.Minute, step: time.Minute, want: time.Minute},
+		// a tenth of windows shorter than 10ns is zero
+		{window: 5 * time.Nanosecond, want: minWindowStep},
+		{window: 5 * time.Second, want: minWindowStep},
+		{window: 5 * time.Minute, step: time.Millisecond, want: minWindowStep},
+	}
+	for _, tt := range tests {
+		objective := klcv1alpha1.Objective{Window: metav1.Duration{Duration: tt.window}, Step: metav1.Duration{Duration: tt.step}}
+		testrequire.Equal(t, tt.want, windowStep(objective))
+	}
+}
@@ -0,0 +1,101 @@
+package keptnevaluationprovider
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	klcv1alpha1 "github.com/keptn/lifecycle-controller/operator/api/v1alpha1"
+	controllererrors "github.com/keptn/lifecycle-controller/operator/controllers/errors"
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// The keys of the secret of a provider. Either a token or a user and password can be used.
+const (
+	SecretKeyUser     = "user"
+	SecretKeyPassword = "password"
+	SecretKeyToken    = "token"
+)
+
+// SecretLabel marks the secrets of providers, which are watched so that changed credentials are used right away
+const SecretLabel = "keptn.sh/evaluation-provider-secret"
+
+// SecretSelector selects the secrets watched by the controller. Other secrets are neither watched nor cached.
+var SecretSelector = labels.SelectorFromSet(labels.Set{SecretLabel: "true"})
+
+// ClientCache holds the HTTP clients used to query the KeptnEvaluationProviders, including their credentials.
+// Entries are invalidated by the KeptnEvaluationProvider controller whenever a provider or its secret changes.
+type ClientCache struct {
+	mu      sync.Mutex
+	clients map[types.NamespacedName]*http.Client
+}
+
+func NewClientCache() *ClientCache {
+	return &ClientCache{clients: map[types.NamespacedName]*http.Client{}}
+}
+
+// Get returns the client for the given provider, creating it with the credentials of the referenced secret if it is not cached yet.
+// Each request of the client is traced as child span of the span in the context of the request.
+func (c *ClientCache) Get(ctx context.Context, reader client.Reader, provider *klcv1alpha1.KeptnEvaluationProvider) (*http.Client, error) {
+	name := types.NamespacedName{Namespace: provider.Namespace, Name: provider.Name}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if httpClient, ok := c.clients[name]; ok {
+		return httpClient, nil
+	}
+
+	var transport http.RoundTripper = http.DefaultTransport
+	if provider.Spec.SecretName != "" {
+		secret := &corev1.Secret{}
+		if err := reader.Get(ctx, types.NamespacedName{Namespace: provider.Namespace, Name: provider.Spec.SecretName}, secret); err != nil {
+			return nil, fmt.Errorf("could not retrieve secret %s of provider %s: %w", provider.Spec.SecretName, provider.Name, controllererrors.Wrap(controllererrors.ErrProviderUnavailable, err))
+		}
+		auth := &authTransport{
+			user:     string(secret.Data[SecretKeyUser]),
+			password: string(secret.Data[SecretKeyPassword]),
+			token:    string(secret.Data[SecretKeyToken]),
+			scheme:   "Bearer",
+		}
+		if provider.IsDynatrace() {
+			// Dynatrace expects API tokens in its own authorization scheme
+			auth.scheme = "Api-Token"
+		}
+		transport = auth
+	}
+	httpClient := &http.Client{Timeout: 30 * time.Second, Transport: otelhttp.NewTransport(transport)}
+	c.clients[name] = httpClient
+	return httpClient, nil
+}
+
+// Invalidate removes the client of the given provider, so that it is created with the current credentials on its next use
+func (c *ClientCache) Invalidate(name types.NamespacedName) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.clients, name)
+}
+
+// authTransport adds the credentials of a provider to each request
+type authTransport struct {
+	user     string
+	password string
+	token    string
+	// scheme is the authorization scheme of the token, e.g. Bearer
+	scheme string
+}
+
+func (t *authTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context())
+	if t.token != "" {
+		req.Header.Set("Authorization", t.scheme+" "+t.token)
+	} else if t.user != "" {
+		req.SetBasicAuth(t.user, t.password)
+	}
+	return http.DefaultTransport.RoundTrip(req)
+}
@@ -0,0 +1,108 @@
+package keptnevaluationprovider
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/keptn/lifecycle-controller/operator/api/v1alpha1"
+	testrequire "github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestClientCache_Get(t *testing.T) {
+	var authorization string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		authorization = r.Header.Get("Authorization")
+	}))
+	defer server.Close()
+
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "prometheus-credentials", Namespace: "default"},
+		Data:       map[string][]byte{"token": []byte("first")},
+	}
+	k8sClient := fake.NewClientBuilder().WithObjects(secret).Build()
+	provider := &v1alpha1.KeptnEvaluationProvider{
+		ObjectMeta: metav1.ObjectMeta{Name: "prometheus", Namespace: "default"},
+		Spec:       v1alpha1.KeptnEvaluationProviderSpec{TargetServer: server.URL, SecretName: "prometheus-credentials"},
+	}
+	cache := NewClientCache()
+
+	httpClient, err := cache.Get(context.TODO(), k8sClient, provider)
+	testrequire.Nil(t, err)
+	_, err = httpClient.Get(server.URL)
+	testrequire.Nil(t, err)
+	testrequire.Equal(t, "Bearer first", authorization)
+
+	// the cached client keeps the old credentials until it is invalidated
+	secret.Data = map[string][]byte{"token": []byte("second")}
+	testrequire.Nil(t, k8sClient.Update(context.TODO(), secret))
+	cachedClient, err := cache.Get(context.TODO(), k8sClient, provider)
+	testrequire.Nil(t, err)
+	testrequire.Same(t, httpClient, cachedClient)
+
+	cache.Invalidate(types.NamespacedName{Name: "prometheus", Namespace: "default"})
+	httpClient, err = cache.Get(context.TODO(), k8sClient, provider)
+	testrequire.Nil(t, err)
+	_, err = httpClient.Get(server.URL)
+	testrequire.Nil(t, err)
+	testrequire.Equal(t, "Bearer second", authorization)
+}
+
+func TestClientCache_GetPropagatesTraceContext(t *testing.T) {
+	var traceParent string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		traceParent = r.Header.Get("traceparent")
+	}))
+	defer server.Close()
+
+	spanRecorder := tracetest.NewSpanRecorder()
+	tracerProvider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(spanRecorder))
+	previousTracerProvider, previousPropagator := otel.GetTracerProvider(), otel.GetTextMapPropagator()
+	defer func() {
+		otel.SetTracerProvider(previousTracerProvider)
+		otel.SetTextMapPropagator(previousPropagator)
+	}()
+	otel.SetTracerProvider(tracerProvider)
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	provider := &v1alpha1.KeptnEvaluationProvider{
+		ObjectMeta: metav1.ObjectMeta{Name: "prometheus", Namespace: "default"},
+		Spec:       v1alpha1.KeptnEvaluationProviderSpec{TargetServer: server.URL},
+	}
+
+	httpClient, err := NewClientCache().Get(context.TODO(), fake.NewClientBuilder().Build(), provider)
+	testrequire.Nil(t, err)
+	ctx, span := tracerProvider.Tracer("test").Start(context.TODO(), "evaluation")
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, server.URL, nil)
+	testrequire.Nil(t, err)
+	resp, err := httpClient.Do(req)
+	testrequire.Nil(t, err)
+	// the client span ends once the body of the response is closed
+	testrequire.Nil(t, resp.Body.Close())
+	span.End()
+
+	// the provider receives the context of the client span, which is a child of the span of the evaluation
+	testrequire.Contains(t, traceParent, span.SpanContext().TraceID().String())
+	testrequire.Len(t, spanRecorder.Ended(), 2)
+	clientSpan := spanRecorder.Ended()[0]
+	testrequire.Equal(t, span.SpanContext().SpanID(), clientSpan.Parent().SpanID())
+	testrequire.Contains(t, traceParent, clientSpan.SpanContext().SpanID().String())
+}
+
+func TestClientCache_GetMissingSecret(t *testing.T) {
+	provider := &v1alpha1.KeptnEvaluationProvider{
+		ObjectMeta: metav1.ObjectMeta{Name: "prometheus", Namespace: "default"},
+		Spec:       v1alpha1.KeptnEvaluationProviderSpec{TargetServer: "http://prometheus", SecretName: "missing"},
+	}
+
+	_, err := NewClientCache().Get(context.TODO(), fake.NewClientBuilder().Build(), provider)
+	testrequire.NotNil(t, err)
+}
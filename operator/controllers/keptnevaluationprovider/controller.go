@@ -0,0 +1,222 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package keptnevaluationprovider
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/go-logr/logr"
+	klcv1alpha1 "github.com/keptn/lifecycle-controller/operator/api/v1alpha1"
+	"github.com/keptn/lifecycle-controller/operator/controllers/hibernation"
+	"github.com/keptn/lifecycle-controller/operator/controllers/reasons"
+	"github.com/keptn/lifecycle-controller/operator/controllers/selfmonitoring"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+)
+
+// KeptnEvaluationProviderReconciler reconciles a KeptnEvaluationProvider object
+type KeptnEvaluationProviderReconciler struct {
+	client.Client
+	Scheme          *runtime.Scheme
+	Log             logr.Logger
+	Recorder        record.EventRecorder
+	ProviderClients *ClientCache
+	// ProbeInterval is the interval in which the connectivity of the providers is checked
+	ProbeInterval time.Duration
+	// Hibernate disables the periodic probes in namespaces without active instances
+	Hibernate bool
+}
+
+//+kubebuilder:rbac:groups=lifecycle.keptn.sh,resources=keptnevaluationproviders,verbs=get;list;watch
+//+kubebuilder:rbac:groups=lifecycle.keptn.sh,resources=keptnevaluationproviders/status,verbs=get;update;patch
+//+kubebuilder:rbac:groups=core,resources=secrets,verbs=get;list;watch
+//+kubebuilder:rbac:groups=lifecycle.keptn.sh,resources=keptnappversions,verbs=get;list;watch
+//+kubebuilder:rbac:groups=lifecycle.keptn.sh,resources=keptnworkloadinstances,verbs=get;list;watch
+
+// Reconcile invalidates the cached client of a KeptnEvaluationProvider whenever the provider or its secret changes,
+// and probes whether the provider is reachable and accepts the current credentials.
+// The probe is repeated periodically, so that misconfigured providers are detected before they are used in an evaluation.
+// If hibernation is enabled, the probe is only repeated while the namespace has active instances.
+func (r *KeptnEvaluationProviderReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	r.Log.Info("Reconciling KeptnEvaluationProvider")
+
+	r.ProviderClients.Invalidate(req.NamespacedName)
+
+	provider := &klcv1alpha1.KeptnEvaluationProvider{}
+	if err := r.Client.Get(ctx, req.NamespacedName, provider); err != nil {
+		if errors.IsNotFound(err) {
+			r.Log.Info("KeptnEvaluationProvider resource not found. Ignoring since object must be deleted")
+			return ctrl.Result{}, nil
+		}
+		r.Log.Error(err, "Failed to get the KeptnEvaluationProvider")
+		return ctrl.Result{Requeue: true, RequeueAfter: 30 * time.Second}, nil
+	}
+
+	reachable, authenticated := r.probe(ctx, provider)
+	now := metav1.Now()
+	provider.Status.LastProbeTime = &now
+
+	if reachable.Status != metav1.ConditionTrue {
+		r.Recorder.AnnotatedEventf(provider, map[string]string{reasons.CodeAnnotation: reachable.Reason}, "Warning", reachable.Reason, "%s", reachable.Message)
+	}
+	meta.SetStatusCondition(&provider.Status.Conditions, reachable)
+
+	if provider.Spec.SecretName == "" {
+		meta.RemoveStatusCondition(&provider.Status.Conditions, klcv1alpha1.ProviderAuthenticated)
+	} else {
+		if authenticated.Status == metav1.ConditionTrue {
+			provider.Status.LastAuthenticationTime = &now
+		} else if authenticated.Status == metav1.ConditionFalse {
+			r.Recorder.AnnotatedEventf(provider, map[string]string{reasons.CodeAnnotation: authenticated.Reason}, "Warning", authenticated.Reason, "%s", authenticated.Message)
+		}
+		meta.SetStatusCondition(&provider.Status.Conditions, authenticated)
+	}
+
+	if err := r.Client.Status().Update(ctx, provider); err != nil {
+		r.Log.Error(err, "could not update status of KeptnEvaluationProvider")
+		return ctrl.Result{Requeue: true}, err
+	}
+
+	if r.Hibernate {
+		idle, err := hibernation.Idle(ctx, r.Client, provider.Namespace)
+		if err != nil {
+			r.Log.Error(err, "could not check whether namespace is idle")
+		} else if idle {
+			r.Log.Info("Namespace has no active instances, hibernating KeptnEvaluationProvider " + provider.Name)
+			return ctrl.Result{}, nil
+		}
+	}
+	return ctrl.Result{RequeueAfter: r.ProbeInterval}, nil
+}
+
+// probe runs the query "up" against the provider, or lists a metric of a Dynatrace provider, and returns whether it is
+// reachable and accepts the credentials of its secret
+func (r *KeptnEvaluationProviderReconciler) probe(ctx context.Context, provider *klcv1alpha1.KeptnEvaluationProvider) (metav1.Condition, metav1.Condition) {
+	reachable := newCondition(klcv1alpha1.ProviderReachable, provider.Generation)
+	authenticated := newCondition(klcv1alpha1.ProviderAuthenticated, provider.Generation)
+
+	httpClient, err := r.ProviderClients.Get(ctx, r.Client, provider)
+	if err != nil {
+		setCondition(&reachable, metav1.ConditionUnknown, reasons.SecretNotFound, err)
+		setCondition(&authenticated, metav1.ConditionFalse, reasons.SecretNotFound, err)
+		return reachable, authenticated
+	}
+
+	probeURL := strings.TrimSuffix(provider.Spec.TargetServer, "/") + "/api/v1/query?query=up"
+	if provider.IsDynatrace() {
+		probeURL = strings.TrimSuffix(provider.Spec.TargetServer, "/") + DynatraceProbePath
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, probeURL, nil)
+	if err != nil {
+		setCondition(&reachable, metav1.ConditionFalse, reasons.InvalidTargetServer, err)
+		setCondition(&authenticated, metav1.ConditionUnknown, reasons.InvalidTargetServer, err)
+		return reachable, authenticated
+	}
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		setCondition(&reachable, metav1.ConditionFalse, reasons.Unreachable, err)
+		setCondition(&authenticated, metav1.ConditionUnknown, reasons.Unreachable, err)
+		return reachable, authenticated
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusForbidden {
+		setCondition(&reachable, metav1.ConditionTrue, reasons.Reachable, provider.Spec.TargetServer)
+		setCondition(&authenticated, metav1.ConditionFalse, reasons.Unauthorized, provider.Spec.SecretName, resp.Status)
+		return reachable, authenticated
+	}
+	if resp.StatusCode >= 300 {
+		setCondition(&reachable, metav1.ConditionFalse, reasons.UnexpectedResponse, resp.Status)
+		setCondition(&authenticated, metav1.ConditionUnknown, reasons.UnexpectedResponse, resp.Status)
+		return reachable, authenticated
+	}
+
+	setCondition(&reachable, metav1.ConditionTrue, reasons.Reachable, provider.Spec.TargetServer)
+	setCondition(&authenticated, metav1.ConditionTrue, reasons.Authenticated, provider.Spec.SecretName)
+	return reachable, authenticated
+}
+
+func newCondition(conditionType string, generation int64) metav1.Condition {
+	return metav1.Condition{Type: conditionType, ObservedGeneration: generation}
+}
+
+func setCondition(condition *metav1.Condition, status metav1.ConditionStatus, reason reasons.Reason, args ...interface{}) {
+	condition.Status = status
+	condition.Reason = reason.Code
+	condition.Message = reason.Message(args...)
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *KeptnEvaluationProviderReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	b := ctrl.NewControllerManagedBy(mgr).
+		// predicate disabling the auto reconciliation after updating the object status
+		For(&klcv1alpha1.KeptnEvaluationProvider{}, builder.WithPredicates(predicate.GenerationChangedPredicate{})).
+		Watches(&source.Kind{Type: &corev1.Secret{}}, handler.EnqueueRequestsFromMapFunc(r.getProvidersForSecret))
+	if r.Hibernate {
+		// new instances wake up the hibernating providers of their namespace
+		b = b.Watches(&source.Kind{Type: &klcv1alpha1.KeptnAppVersion{}}, handler.EnqueueRequestsFromMapFunc(r.getProvidersForInstance), builder.WithPredicates(hibernation.WakeupPredicate())).
+			Watches(&source.Kind{Type: &klcv1alpha1.KeptnWorkloadInstance{}}, handler.EnqueueRequestsFromMapFunc(r.getProvidersForInstance), builder.WithPredicates(hibernation.WakeupPredicate()))
+	}
+	return b.Complete(selfmonitoring.Wrap("keptnevaluationprovider", r))
+}
+
+// getProvidersForInstance returns a request for each KeptnEvaluationProvider in the namespace of the given instance
+func (r *KeptnEvaluationProviderReconciler) getProvidersForInstance(instance client.Object) []reconcile.Request {
+	providers := &klcv1alpha1.KeptnEvaluationProviderList{}
+	if err := r.Client.List(context.TODO(), providers, client.InNamespace(instance.GetNamespace())); err != nil {
+		r.Log.Error(err, "could not retrieve KeptnEvaluationProviders")
+		return nil
+	}
+
+	var requests []reconcile.Request
+	for _, provider := range providers.Items {
+		requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{Namespace: provider.Namespace, Name: provider.Name}})
+	}
+	return requests
+}
+
+// getProvidersForSecret returns a request for each KeptnEvaluationProvider referencing the given secret
+func (r *KeptnEvaluationProviderReconciler) getProvidersForSecret(secret client.Object) []reconcile.Request {
+	providers := &klcv1alpha1.KeptnEvaluationProviderList{}
+	if err := r.Client.List(context.TODO(), providers, client.InNamespace(secret.GetNamespace())); err != nil {
+		r.Log.Error(err, "could not retrieve KeptnEvaluationProviders")
+		return nil
+	}
+
+	var requests []reconcile.Request
+	for _, provider := range providers.Items {
+		if provider.Spec.SecretName == secret.GetName() {
+			requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{Namespace: provider.Namespace, Name: provider.Name}})
+		}
+	}
+	return requests
+}
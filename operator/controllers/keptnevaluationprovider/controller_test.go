@@ -0,0 +1,54 @@
+package keptnevaluationprovider
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/keptn/lifecycle-controller/operator/api/v1alpha1"
+	testrequire "github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestKeptnEvaluationProviderReconciler_Probe(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer valid" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		testrequire.Equal(t, "up", r.URL.Query().Get("query"))
+	}))
+	defer server.Close()
+
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "prometheus-credentials", Namespace: "default"},
+		Data:       map[string][]byte{"token": []byte("invalid")},
+	}
+	provider := &v1alpha1.KeptnEvaluationProvider{
+		ObjectMeta: metav1.ObjectMeta{Name: "prometheus", Namespace: "default"},
+		Spec:       v1alpha1.KeptnEvaluationProviderSpec{TargetServer: server.URL, SecretName: "prometheus-credentials"},
+	}
+	k8sClient := fake.NewClientBuilder().WithObjects(secret).Build()
+	r := &KeptnEvaluationProviderReconciler{Client: k8sClient, ProviderClients: NewClientCache()}
+
+	reachable, authenticated := r.probe(context.TODO(), provider)
+	testrequire.Equal(t, metav1.ConditionTrue, reachable.Status)
+	testrequire.Equal(t, metav1.ConditionFalse, authenticated.Status)
+	testrequire.Equal(t, "Unauthorized", authenticated.Reason)
+
+	secret.Data = map[string][]byte{"token": []byte("valid")}
+	testrequire.Nil(t, k8sClient.Update(context.TODO(), secret))
+	r.ProviderClients = NewClientCache()
+
+	reachable, authenticated = r.probe(context.TODO(), provider)
+	testrequire.Equal(t, metav1.ConditionTrue, reachable.Status)
+	testrequire.Equal(t, metav1.ConditionTrue, authenticated.Status)
+
+	provider.Spec.TargetServer = "http://127.0.0.1:1"
+	reachable, _ = r.probe(context.TODO(), provider)
+	testrequire.Equal(t, metav1.ConditionFalse, reachable.Status)
+	testrequire.Equal(t, "Unreachable", reachable.Reason)
+}
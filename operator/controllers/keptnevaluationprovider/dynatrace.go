@@ -0,0 +1,121 @@
+package keptnevaluationprovider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	klcv1alpha1 "github.com/keptn/lifecycle-controller/operator/api/v1alpha1"
+)
+
+const (
+	// DynatraceProbePath lists a single metric of the environment, which requires the same scope as the queries
+	DynatraceProbePath = "/api/v2/metrics?pageSize=1"
+	// dynatraceQueryPath is the path of the metrics API v2 the metric selectors are run against
+	dynatraceQueryPath = "/api/v2/metrics/query"
+	// dynatraceLatestRange is the time range the latest value of a metric is looked up in, since the latest data
+	// points of Dynatrace usually have no value yet
+	dynatraceLatestRange = 5 * time.Minute
+	// maxDynatraceResponseSize limits the size of the responses read from Dynatrace
+	maxDynatraceResponseSize = 1 << 20
+)
+
+type dynatraceResponse struct {
+	Result []dynatraceMetricData `json:"result"`
+	Error  *dynatraceError       `json:"error,omitempty"`
+}
+
+type dynatraceMetricData struct {
+	MetricID string            `json:"metricId"`
+	Data     []dynatraceSeries `json:"data"`
+}
+
+type dynatraceSeries struct {
+	Timestamps []int64    `json:"timestamps"`
+	Values     []*float64 `json:"values"`
+}
+
+type dynatraceError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+// QueryDynatrace runs the metric selector against the metrics API v2 of the Dynatrace environment of the provider and
+// returns the values of the single series it selects between from and to at the given resolution, e.g. 1m, together
+// with the response of Dynatrace. Data points without a value are skipped.
+func QueryDynatrace(ctx context.Context, httpClient *http.Client, provider *klcv1alpha1.KeptnEvaluationProvider, selector string, from time.Time, to time.Time, resolution string) ([]float64, string, error) {
+	params := url.Values{}
+	params.Set("metricSelector", selector)
+	params.Set("from", strconv.FormatInt(from.UnixMilli(), 10))
+	params.Set("to", strconv.FormatInt(to.UnixMilli(), 10))
+	params.Set("resolution", resolution)
+	queryURL := strings.TrimSuffix(provider.Spec.TargetServer, "/") + dynatraceQueryPath + "?" + params.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, queryURL, nil)
+	if err != nil {
+		return nil, "", fmt.Errorf("could not create query for provider %s: %w", provider.Name, err)
+	}
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return nil, "", fmt.Errorf("could not run query against provider %s: %w", provider.Name, err)
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxDynatraceResponseSize))
+	if err != nil {
+		return nil, "", fmt.Errorf("could not read response of provider %s: %w", provider.Name, err)
+	}
+	response := string(body)
+
+	result := dynatraceResponse{}
+	parseErr := json.Unmarshal(body, &result)
+	if resp.StatusCode != http.StatusOK {
+		if parseErr == nil && result.Error != nil && result.Error.Message != "" {
+			return nil, response, fmt.Errorf("provider %s returned %s: %s", provider.Name, resp.Status, result.Error.Message)
+		}
+		return nil, response, fmt.Errorf("provider %s returned %s", provider.Name, resp.Status)
+	}
+	if parseErr != nil {
+		return nil, response, fmt.Errorf("could not parse response of provider %s: %w", provider.Name, parseErr)
+	}
+
+	// like a PromQL query, the metric selector has to select exactly one series
+	if len(result.Result) > 1 || (len(result.Result) == 1 && len(result.Result[0].Data) > 1) {
+		return nil, response, fmt.Errorf("too many values in the query result")
+	}
+	var values []float64
+	if len(result.Result) == 1 && len(result.Result[0].Data) == 1 {
+		for _, value := range result.Result[0].Data[0].Values {
+			if value != nil {
+				values = append(values, *value)
+			}
+		}
+	}
+	if len(values) == 0 {
+		return nil, response, fmt.Errorf("no values in query result")
+	}
+	return values, response, nil
+}
+
+// LatestDynatraceValue returns the latest value of the single series selected by the metric selector at the given time
+func LatestDynatraceValue(ctx context.Context, httpClient *http.Client, provider *klcv1alpha1.KeptnEvaluationProvider, selector string, at time.Time) (float64, string, error) {
+	values, response, err := QueryDynatrace(ctx, httpClient, provider, selector, at.Add(-dynatraceLatestRange), at, "1m")
+	if err != nil {
+		return 0, response, err
+	}
+	return values[len(values)-1], response, nil
+}
+
+// DynatraceResolution returns the resolution of the metrics API closest to the given step, which is at least a minute
+func DynatraceResolution(step time.Duration) string {
+	minutes := int64(step / time.Minute)
+	if minutes < 1 {
+		minutes = 1
+	}
+	return fmt.Sprintf("%dm", minutes)
+}
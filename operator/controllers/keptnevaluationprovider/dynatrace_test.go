@@ -0,0 +1,71 @@
+package keptnevaluationprovider
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/keptn/lifecycle-controller/operator/api/v1alpha1"
+	testrequire "github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestQueryDynatrace(t *testing.T) {
+	var request *http.Request
+	response := `{"totalCount":1,"resolution":"1m","result":[{"metricId":"builtin:service.response.time:avg","data":[{"dimensions":[],"timestamps":[1,2,3],"values":[250.5,null,300]}]}]}`
+	status := http.StatusOK
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		request = r
+		w.WriteHeader(status)
+		_, _ = w.Write([]byte(response))
+	}))
+	defer server.Close()
+
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "dynatrace-credentials", Namespace: "default"},
+		Data:       map[string][]byte{"token": []byte("dt0c01.abc")},
+	}
+	provider := &v1alpha1.KeptnEvaluationProvider{
+		ObjectMeta: metav1.ObjectMeta{Name: "dynatrace", Namespace: "default"},
+		Spec:       v1alpha1.KeptnEvaluationProviderSpec{Type: v1alpha1.ProviderTypeDynatrace, TargetServer: server.URL + "/", SecretName: "dynatrace-credentials"},
+	}
+	httpClient, err := NewClientCache().Get(context.TODO(), fake.NewClientBuilder().WithObjects(secret).Build(), provider)
+	testrequire.Nil(t, err)
+
+	to := time.Date(2022, 11, 8, 12, 0, 0, 0, time.UTC)
+	values, body, err := QueryDynatrace(context.TODO(), httpClient, provider, "builtin:service.response.time:avg", to.Add(-10*time.Minute), to, "1m")
+	testrequire.Nil(t, err)
+	testrequire.Equal(t, []float64{250.5, 300}, values)
+	testrequire.Equal(t, response, body)
+	testrequire.Equal(t, "Api-Token dt0c01.abc", request.Header.Get("Authorization"))
+	testrequire.Equal(t, "/api/v2/metrics/query", request.URL.Path)
+	testrequire.Equal(t, "builtin:service.response.time:avg", request.URL.Query().Get("metricSelector"))
+	testrequire.Equal(t, "1667908200000", request.URL.Query().Get("from"))
+	testrequire.Equal(t, "1667908800000", request.URL.Query().Get("to"))
+
+	value, _, err := LatestDynatraceValue(context.TODO(), httpClient, provider, "builtin:service.response.time:avg", to)
+	testrequire.Nil(t, err)
+	testrequire.Equal(t, 300.0, value)
+
+	response = `{"result":[{"metricId":"builtin:service.response.time:avg","data":[{"values":[1]},{"values":[2]}]}]}`
+	_, _, err = QueryDynatrace(context.TODO(), httpClient, provider, "builtin:service.response.time:avg", to.Add(-10*time.Minute), to, "1m")
+	testrequire.EqualError(t, err, "too many values in the query result")
+
+	response = `{"result":[{"metricId":"builtin:service.response.time:avg","data":[{"values":[null]}]}]}`
+	_, _, err = QueryDynatrace(context.TODO(), httpClient, provider, "builtin:service.response.time:avg", to.Add(-10*time.Minute), to, "1m")
+	testrequire.EqualError(t, err, "no values in query result")
+
+	status, response = http.StatusBadRequest, `{"error":{"code":400,"message":"The metric selector is invalid"}}`
+	_, _, err = QueryDynatrace(context.TODO(), httpClient, provider, "builtin:unknown", to.Add(-10*time.Minute), to, "1m")
+	testrequire.EqualError(t, err, "provider dynatrace returned 400 Bad Request: The metric selector is invalid")
+}
+
+func TestDynatraceResolution(t *testing.T) {
+	testrequire.Equal(t, "1m", DynatraceResolution(10*time.Second))
+	testrequire.Equal(t, "6m", DynatraceResolution(6*time.Minute))
+	testrequire.Equal(t, "90m", DynatraceResolution(90*time.Minute+30*time.Second))
+}
@@ -0,0 +1,178 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package keptnmetric
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/go-logr/logr"
+	klcv1alpha1 "github.com/keptn/lifecycle-controller/operator/api/v1alpha1"
+	controllererrors "github.com/keptn/lifecycle-controller/operator/controllers/errors"
+	"github.com/keptn/lifecycle-controller/operator/controllers/hibernation"
+	"github.com/keptn/lifecycle-controller/operator/controllers/keptnevaluationprovider"
+	"github.com/keptn/lifecycle-controller/operator/controllers/selfmonitoring"
+	promapi "github.com/prometheus/client_golang/api"
+	prometheus "github.com/prometheus/client_golang/api/prometheus/v1"
+	"github.com/prometheus/common/model"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+)
+
+// KeptnMetricReconciler reconciles a KeptnMetric object
+type KeptnMetricReconciler struct {
+	client.Client
+	Scheme   *runtime.Scheme
+	Log      logr.Logger
+	Recorder record.EventRecorder
+	// ProviderClients caches the clients used to query the KeptnEvaluationProviders
+	ProviderClients *keptnevaluationprovider.ClientCache
+	// Hibernate disables fetching the values periodically in namespaces without active instances
+	Hibernate bool
+}
+
+//+kubebuilder:rbac:groups=lifecycle.keptn.sh,resources=keptnmetrics,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=lifecycle.keptn.sh,resources=keptnmetrics/status,verbs=get;update;patch
+//+kubebuilder:rbac:groups=lifecycle.keptn.sh,resources=keptnmetrics/finalizers,verbs=update
+//+kubebuilder:rbac:groups=lifecycle.keptn.sh,resources=keptnevaluationproviders,verbs=get;list;watch
+//+kubebuilder:rbac:groups=lifecycle.keptn.sh,resources=keptnappversions,verbs=get;list;watch
+//+kubebuilder:rbac:groups=lifecycle.keptn.sh,resources=keptnworkloadinstances,verbs=get;list;watch
+
+// Reconcile fetches the value of the query of a KeptnMetric from its provider and stores it in the status of the metric.
+// The value is fetched again after the fetch interval of the metric, so that it can be read without querying the provider.
+// If hibernation is enabled, the value is only fetched again while the namespace has active instances.
+func (r *KeptnMetricReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	r.Log.Info("Reconciling KeptnMetric")
+
+	metric := &klcv1alpha1.KeptnMetric{}
+	if err := r.Client.Get(ctx, req.NamespacedName, metric); err != nil {
+		if errors.IsNotFound(err) {
+			r.Log.Info("KeptnMetric resource not found. Ignoring since object must be deleted")
+			return ctrl.Result{}, nil
+		}
+		r.Log.Error(err, "Failed to get the KeptnMetric")
+		return ctrl.Result{Requeue: true, RequeueAfter: 30 * time.Second}, nil
+	}
+
+	value, err := r.fetchValue(ctx, metric)
+	if err != nil {
+		r.Log.Error(err, "Could not fetch value of KeptnMetric "+metric.Name)
+		reason := "FetchFailed"
+		if controllererrors.Is(err, controllererrors.ErrProviderUnavailable) {
+			reason = controllererrors.Reason(err)
+		}
+		r.Recorder.Event(metric, "Warning", reason, err.Error())
+		metric.Status.Message = err.Error()
+	} else {
+		metric.Status.Value = value
+		metric.Status.LastUpdated = metav1.NewTime(time.Now().UTC())
+		metric.Status.Message = ""
+	}
+
+	if err := r.Client.Status().Update(ctx, metric); err != nil {
+		r.Log.Error(err, "could not update status of KeptnMetric")
+		return ctrl.Result{Requeue: true}, err
+	}
+
+	if r.Hibernate {
+		idle, err := hibernation.Idle(ctx, r.Client, metric.Namespace)
+		if err != nil {
+			r.Log.Error(err, "could not check whether namespace is idle")
+		} else if idle {
+			r.Log.Info("Namespace has no active instances, hibernating KeptnMetric " + metric.Name)
+			return ctrl.Result{}, nil
+		}
+	}
+	return ctrl.Result{RequeueAfter: metric.Spec.FetchInterval.Duration}, nil
+}
+
+// fetchValue runs the query of the metric against its provider, which has to return exactly one value
+func (r *KeptnMetricReconciler) fetchValue(ctx context.Context, metric *klcv1alpha1.KeptnMetric) (string, error) {
+	provider := &klcv1alpha1.KeptnEvaluationProvider{}
+	if err := r.Client.Get(ctx, types.NamespacedName{Namespace: metric.Namespace, Name: metric.Spec.Provider}, provider); err != nil {
+		return "", fmt.Errorf("could not retrieve KeptnEvaluationProvider %s: %w", metric.Spec.Provider, controllererrors.Wrap(controllererrors.ErrProviderUnavailable, err))
+	}
+
+	httpClient, err := r.ProviderClients.Get(ctx, r.Client, provider)
+	if err != nil {
+		return "", err
+	}
+	if provider.IsDynatrace() {
+		value, _, err := keptnevaluationprovider.LatestDynatraceValue(ctx, httpClient, provider, metric.Spec.Query, time.Now().UTC())
+		if err != nil {
+			return "", fmt.Errorf("could not run query: %w", err)
+		}
+		return model.SampleValue(value).String(), nil
+	}
+	client, err := promapi.NewClient(promapi.Config{Address: provider.Spec.TargetServer, Client: httpClient})
+	if err != nil {
+		return "", fmt.Errorf("could not create client for provider %s: %w", provider.Name, err)
+	}
+
+	result, _, err := prometheus.NewAPI(client).Query(ctx, metric.Spec.Query, time.Now().UTC())
+	if err != nil {
+		return "", fmt.Errorf("could not run query: %w", err)
+	}
+	resultVector, ok := result.(model.Vector)
+	if !ok {
+		return "", fmt.Errorf("could not cast result")
+	}
+	if len(resultVector) == 0 {
+		return "", fmt.Errorf("no values in query result")
+	} else if len(resultVector) > 1 {
+		return "", fmt.Errorf("too many values in the query result")
+	}
+	return resultVector[0].Value.String(), nil
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *KeptnMetricReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	b := ctrl.NewControllerManagedBy(mgr).
+		For(&klcv1alpha1.KeptnMetric{}, builder.WithPredicates(predicate.GenerationChangedPredicate{}))
+	if r.Hibernate {
+		// new instances wake up the hibernating metrics of their namespace
+		b = b.Watches(&source.Kind{Type: &klcv1alpha1.KeptnAppVersion{}}, handler.EnqueueRequestsFromMapFunc(r.getMetricsForInstance), builder.WithPredicates(hibernation.WakeupPredicate())).
+			Watches(&source.Kind{Type: &klcv1alpha1.KeptnWorkloadInstance{}}, handler.EnqueueRequestsFromMapFunc(r.getMetricsForInstance), builder.WithPredicates(hibernation.WakeupPredicate()))
+	}
+	return b.Complete(selfmonitoring.Wrap("keptnmetric", r))
+}
+
+// getMetricsForInstance returns a request for each KeptnMetric in the namespace of the given instance
+func (r *KeptnMetricReconciler) getMetricsForInstance(instance client.Object) []reconcile.Request {
+	metrics := &klcv1alpha1.KeptnMetricList{}
+	if err := r.Client.List(context.TODO(), metrics, client.InNamespace(instance.GetNamespace())); err != nil {
+		r.Log.Error(err, "could not retrieve KeptnMetrics")
+		return nil
+	}
+
+	var requests []reconcile.Request
+	for _, metric := range metrics.Items {
+		requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{Namespace: metric.Namespace, Name: metric.Name}})
+	}
+	return requests
+}
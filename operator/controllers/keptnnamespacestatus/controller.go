@@ -0,0 +1,221 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package keptnnamespacestatus
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/go-logr/logr"
+	klcv1alpha1 "github.com/keptn/lifecycle-controller/operator/api/v1alpha1"
+	"github.com/keptn/lifecycle-controller/operator/api/v1alpha1/common"
+	"github.com/keptn/lifecycle-controller/operator/controllers/selfmonitoring"
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+)
+
+const podMutatingWebhookName = "mpod.keptn.sh"
+const schedulerName = "keptn-scheduler"
+const maxErrors = 5
+
+// KeptnNamespaceStatusReconciler maintains a KeptnNamespaceStatus for each namespace enabled for the Lifecycle Controller
+type KeptnNamespaceStatusReconciler struct {
+	client.Client
+	// APIReader reads the events of the namespace without caching the events of the whole cluster
+	APIReader client.Reader
+	Scheme    *runtime.Scheme
+	Log       logr.Logger
+	// SchedulerNamespace is the namespace the Keptn scheduler is deployed to
+	SchedulerNamespace string
+	// Interval is the interval in which the status is updated
+	Interval time.Duration
+}
+
+//+kubebuilder:rbac:groups=lifecycle.keptn.sh,resources=keptnnamespacestatuses,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=lifecycle.keptn.sh,resources=keptnnamespacestatuses/status,verbs=get;update;patch
+//+kubebuilder:rbac:groups=lifecycle.keptn.sh,resources=keptnapps,verbs=get;list;watch
+//+kubebuilder:rbac:groups=lifecycle.keptn.sh,resources=keptnworkloads,verbs=get;list;watch
+//+kubebuilder:rbac:groups=lifecycle.keptn.sh,resources=keptnappversions,verbs=get;list;watch
+//+kubebuilder:rbac:groups=lifecycle.keptn.sh,resources=keptnworkloadinstances,verbs=get;list;watch
+//+kubebuilder:rbac:groups=core,resources=namespaces,verbs=get;list;watch
+//+kubebuilder:rbac:groups=core,resources=events,verbs=list
+//+kubebuilder:rbac:groups=apps,resources=deployments,verbs=get;list;watch
+//+kubebuilder:rbac:groups=admissionregistration.k8s.io,resources=mutatingwebhookconfigurations,verbs=get;list;watch
+
+// Reconcile creates or updates the KeptnNamespaceStatus of a namespace that is enabled for the Lifecycle Controller,
+// and deletes it once the namespace is no longer enabled. The status is updated periodically.
+func (r *KeptnNamespaceStatusReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	r.Log.Info("Reconciling KeptnNamespaceStatus")
+
+	namespace := &corev1.Namespace{}
+	if err := r.Client.Get(ctx, req.NamespacedName, namespace); err != nil {
+		if errors.IsNotFound(err) {
+			// the KeptnNamespaceStatus is deleted together with its namespace
+			return ctrl.Result{}, nil
+		}
+		r.Log.Error(err, "Failed to get the Namespace")
+		return ctrl.Result{Requeue: true, RequeueAfter: 30 * time.Second}, nil
+	}
+
+	nsStatus := &klcv1alpha1.KeptnNamespaceStatus{}
+	err := r.Client.Get(ctx, types.NamespacedName{Name: namespace.Name}, nsStatus)
+	if err != nil && !errors.IsNotFound(err) {
+		return ctrl.Result{}, fmt.Errorf("could not fetch KeptnNamespaceStatus: %w", err)
+	}
+	exists := err == nil
+
+	if namespace.Annotations[common.NamespaceEnabledAnnotation] != "enabled" || !namespace.DeletionTimestamp.IsZero() {
+		if exists {
+			if err := r.Client.Delete(ctx, nsStatus); err != nil && !errors.IsNotFound(err) {
+				return ctrl.Result{}, fmt.Errorf("could not delete KeptnNamespaceStatus: %w", err)
+			}
+		}
+		return ctrl.Result{}, nil
+	}
+
+	if !exists {
+		nsStatus = &klcv1alpha1.KeptnNamespaceStatus{ObjectMeta: metav1.ObjectMeta{Name: namespace.Name}}
+		if err := controllerutil.SetControllerReference(namespace, nsStatus, r.Scheme); err != nil {
+			return ctrl.Result{}, fmt.Errorf("could not set owner of KeptnNamespaceStatus: %w", err)
+		}
+		if err := r.Client.Create(ctx, nsStatus); err != nil {
+			return ctrl.Result{}, fmt.Errorf("could not create KeptnNamespaceStatus: %w", err)
+		}
+	}
+
+	status, err := r.summarize(ctx, namespace.Name)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	nsStatus.Status = status
+	if err := r.Client.Status().Update(ctx, nsStatus); err != nil {
+		return ctrl.Result{}, fmt.Errorf("could not update status of KeptnNamespaceStatus: %w", err)
+	}
+	return ctrl.Result{RequeueAfter: r.Interval}, nil
+}
+
+// summarize collects the status of the Lifecycle Controller in the namespace
+func (r *KeptnNamespaceStatusReconciler) summarize(ctx context.Context, namespace string) (klcv1alpha1.KeptnNamespaceStatusStatus, error) {
+	status := klcv1alpha1.KeptnNamespaceStatusStatus{LastUpdated: metav1.NewTime(time.Now().UTC())}
+
+	webhooks := &admissionregistrationv1.MutatingWebhookConfigurationList{}
+	if err := r.Client.List(ctx, webhooks); err != nil {
+		return status, fmt.Errorf("could not retrieve MutatingWebhookConfigurations: %w", err)
+	}
+	for _, configuration := range webhooks.Items {
+		for _, webhook := range configuration.Webhooks {
+			if webhook.Name == podMutatingWebhookName {
+				status.WebhookActive = true
+			}
+		}
+	}
+
+	scheduler := &appsv1.Deployment{}
+	err := r.Client.Get(ctx, types.NamespacedName{Namespace: r.SchedulerNamespace, Name: schedulerName}, scheduler)
+	if err != nil && !errors.IsNotFound(err) {
+		return status, fmt.Errorf("could not fetch the Deployment of the scheduler: %w", err)
+	}
+	status.SchedulerGating = err == nil && scheduler.Status.ReadyReplicas > 0
+
+	apps := &klcv1alpha1.KeptnAppList{}
+	if err := r.Client.List(ctx, apps, client.InNamespace(namespace)); err != nil {
+		return status, fmt.Errorf("could not retrieve KeptnApps: %w", err)
+	}
+	status.Apps = len(apps.Items)
+
+	workloads := &klcv1alpha1.KeptnWorkloadList{}
+	if err := r.Client.List(ctx, workloads, client.InNamespace(namespace)); err != nil {
+		return status, fmt.Errorf("could not retrieve KeptnWorkloads: %w", err)
+	}
+	status.Workloads = len(workloads.Items)
+
+	appVersions := &klcv1alpha1.KeptnAppVersionList{}
+	if err := r.Client.List(ctx, appVersions, client.InNamespace(namespace)); err != nil {
+		return status, fmt.Errorf("could not retrieve KeptnAppVersions: %w", err)
+	}
+	for _, appVersion := range appVersions.Items {
+		if appVersion.Status.EndTime.IsZero() {
+			status.ActiveAppVersions++
+		}
+	}
+
+	workloadInstances := &klcv1alpha1.KeptnWorkloadInstanceList{}
+	if err := r.Client.List(ctx, workloadInstances, client.InNamespace(namespace)); err != nil {
+		return status, fmt.Errorf("could not retrieve KeptnWorkloadInstances: %w", err)
+	}
+	for _, workloadInstance := range workloadInstances.Items {
+		if workloadInstance.Status.EndTime.IsZero() {
+			status.ActiveWorkloadInstances++
+		}
+	}
+
+	events := &corev1.EventList{}
+	if err := r.APIReader.List(ctx, events, client.InNamespace(namespace)); err != nil {
+		return status, fmt.Errorf("could not retrieve Events: %w", err)
+	}
+	status.LastErrors = lastErrors(events.Items)
+	return status, nil
+}
+
+// lastErrors returns the most recent warnings of Keptn resources, latest first
+func lastErrors(events []corev1.Event) []klcv1alpha1.NamespaceError {
+	var errs []klcv1alpha1.NamespaceError
+	for _, event := range events {
+		if event.Type != corev1.EventTypeWarning || !strings.HasPrefix(event.InvolvedObject.APIVersion, klcv1alpha1.GroupVersion.Group+"/") {
+			continue
+		}
+		eventTime := event.LastTimestamp
+		if eventTime.IsZero() {
+			eventTime = metav1.NewTime(event.EventTime.Time)
+		}
+		errs = append(errs, klcv1alpha1.NamespaceError{
+			Object:  event.InvolvedObject.Kind + "/" + event.InvolvedObject.Name,
+			Reason:  event.Reason,
+			Message: event.Message,
+			Time:    eventTime,
+		})
+	}
+	sort.SliceStable(errs, func(i, j int) bool {
+		return errs[j].Time.Before(&errs[i].Time)
+	})
+	if len(errs) > maxErrors {
+		errs = errs[:maxErrors]
+	}
+	return errs
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *KeptnNamespaceStatusReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&corev1.Namespace{}).
+		// the status is updated periodically, so only the creation and deletion of a KeptnNamespaceStatus are reconciled
+		Owns(&klcv1alpha1.KeptnNamespaceStatus{}, builder.WithPredicates(predicate.GenerationChangedPredicate{})).
+		Complete(selfmonitoring.Wrap("keptnnamespacestatus", r))
+}
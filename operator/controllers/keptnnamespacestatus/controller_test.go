@@ -0,0 +1,83 @@
+package keptnnamespacestatus
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+	klcv1alpha1 "github.com/keptn/lifecycle-controller/operator/api/v1alpha1"
+	testrequire "github.com/stretchr/testify/require"
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestKeptnNamespaceStatusReconciler_Summarize(t *testing.T) {
+	scheme := runtime.NewScheme()
+	testrequire.Nil(t, clientgoscheme.AddToScheme(scheme))
+	testrequire.Nil(t, klcv1alpha1.AddToScheme(scheme))
+
+	now := time.Now()
+	k8sClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+		&admissionregistrationv1.MutatingWebhookConfiguration{
+			ObjectMeta: metav1.ObjectMeta{Name: "klc-mutating-webhook-configuration"},
+			Webhooks:   []admissionregistrationv1.MutatingWebhook{{Name: podMutatingWebhookName}},
+		},
+		&appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{Name: schedulerName, Namespace: "keptn-lifecycle-controller-system"},
+			Status:     appsv1.DeploymentStatus{ReadyReplicas: 1},
+		},
+		&klcv1alpha1.KeptnWorkload{ObjectMeta: metav1.ObjectMeta{Name: "app-workload", Namespace: "podtato"}},
+		&klcv1alpha1.KeptnWorkloadInstance{ObjectMeta: metav1.ObjectMeta{Name: "app-workload-1.0.0", Namespace: "podtato"}},
+		&klcv1alpha1.KeptnWorkloadInstance{
+			ObjectMeta: metav1.ObjectMeta{Name: "app-workload-0.9.0", Namespace: "podtato"},
+			Status:     klcv1alpha1.KeptnWorkloadInstanceStatus{EndTime: metav1.NewTime(now)},
+		},
+		&klcv1alpha1.KeptnWorkload{ObjectMeta: metav1.ObjectMeta{Name: "other-workload", Namespace: "other"}},
+		&corev1.Event{
+			ObjectMeta:     metav1.ObjectMeta{Name: "event-1", Namespace: "podtato"},
+			InvolvedObject: corev1.ObjectReference{APIVersion: "lifecycle.keptn.sh/v1alpha1", Kind: "KeptnTask", Name: "pre-check"},
+			Type:           corev1.EventTypeWarning,
+			Reason:         "TaskDefinitionNotFound",
+			LastTimestamp:  metav1.NewTime(now.Add(-time.Minute)),
+		},
+		&corev1.Event{
+			ObjectMeta:     metav1.ObjectMeta{Name: "event-2", Namespace: "podtato"},
+			InvolvedObject: corev1.ObjectReference{APIVersion: "lifecycle.keptn.sh/v1alpha1", Kind: "KeptnEvaluation", Name: "pre-eval"},
+			Type:           corev1.EventTypeWarning,
+			Reason:         "DeadlineExceeded",
+			LastTimestamp:  metav1.NewTime(now),
+		},
+		&corev1.Event{
+			ObjectMeta:     metav1.ObjectMeta{Name: "event-3", Namespace: "podtato"},
+			InvolvedObject: corev1.ObjectReference{APIVersion: "v1", Kind: "Pod", Name: "app-pod"},
+			Type:           corev1.EventTypeWarning,
+			Reason:         "BackOff",
+			LastTimestamp:  metav1.NewTime(now),
+		},
+	).Build()
+
+	r := &KeptnNamespaceStatusReconciler{
+		Client:             k8sClient,
+		APIReader:          k8sClient,
+		Scheme:             scheme,
+		Log:                logr.Discard(),
+		SchedulerNamespace: "keptn-lifecycle-controller-system",
+	}
+	status, err := r.summarize(context.TODO(), "podtato")
+	testrequire.Nil(t, err)
+
+	testrequire.True(t, status.WebhookActive)
+	testrequire.True(t, status.SchedulerGating)
+	testrequire.Equal(t, 0, status.Apps)
+	testrequire.Equal(t, 1, status.Workloads)
+	testrequire.Equal(t, 1, status.ActiveWorkloadInstances)
+	testrequire.Len(t, status.LastErrors, 2)
+	testrequire.Equal(t, "KeptnEvaluation/pre-eval", status.LastErrors[0].Object)
+	testrequire.Equal(t, "TaskDefinitionNotFound", status.LastErrors[1].Reason)
+}
@@ -0,0 +1,78 @@
+package keptntask
+
+import (
+	"context"
+	"sort"
+
+	klcv1alpha1 "github.com/keptn/lifecycle-controller/operator/api/v1alpha1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+)
+
+func (r *KeptnTaskReconciler) createContainerJob(ctx context.Context, task *klcv1alpha1.KeptnTask, definition *klcv1alpha1.KeptnTaskDefinition) (string, error) {
+	params := FunctionExecutionParams{
+		Runner: klcv1alpha1.RunnerSpec{
+			Image:            definition.Spec.Container.Image,
+			ImagePullSecrets: definition.Spec.Container.ImagePullSecrets,
+		},
+	}
+	if err := r.setTaskParams(task, &params); err != nil {
+		return "", err
+	}
+	params.Retries, _ = retryPolicy(task, definition)
+	params.Timeout = taskTimeout(task, definition)
+
+	return r.startJob(ctx, task, params, func(task *klcv1alpha1.KeptnTask, params FunctionExecutionParams) (*batchv1.Job, error) {
+		return r.generateContainerJob(task, definition.Spec.Container, params)
+	})
+}
+
+// generateContainerJob returns the Job running the container of a task. Besides the environment variables passed to
+// functions, the context of the task is passed in separate KEPTN_ variables, so that it can be used in the command
+// and the arguments of the container, e.g. $(KEPTN_WORKLOAD_VERSION).
+func (r *KeptnTaskReconciler) generateContainerJob(task *klcv1alpha1.KeptnTask, spec *klcv1alpha1.ContainerSpec, params FunctionExecutionParams) (*batchv1.Job, error) {
+	job := r.newJob(task, params)
+	r.Propagation.Copy(task.ObjectMeta, &job.Spec.Template.ObjectMeta)
+
+	envVars, err := taskEnvVars(params)
+	if err != nil {
+		return job, err
+	}
+	envVars = append(envVars,
+		corev1.EnvVar{Name: "KEPTN_TASK_NAME", Value: task.Name},
+		corev1.EnvVar{Name: "KEPTN_NAMESPACE", Value: task.Namespace},
+		corev1.EnvVar{Name: "KEPTN_OBJECT_TYPE", Value: params.Context.ObjectType},
+		corev1.EnvVar{Name: "KEPTN_APP_NAME", Value: params.Context.AppName},
+		corev1.EnvVar{Name: "KEPTN_APP_VERSION", Value: params.Context.AppVersion},
+		corev1.EnvVar{Name: "KEPTN_WORKLOAD_NAME", Value: params.Context.WorkloadName},
+		corev1.EnvVar{Name: "KEPTN_WORKLOAD_VERSION", Value: params.Context.WorkloadVersion},
+	)
+
+	// the environment variables of the container take precedence over the ones set by Keptn
+	containerEnv := make([]string, 0, len(spec.Env))
+	for name := range spec.Env {
+		containerEnv = append(containerEnv, name)
+	}
+	sort.Strings(containerEnv)
+	filtered := envVars[:0]
+	for _, envVar := range envVars {
+		if _, ok := spec.Env[envVar.Name]; !ok {
+			filtered = append(filtered, envVar)
+		}
+	}
+	envVars = filtered
+	for _, name := range containerEnv {
+		envVars = append(envVars, corev1.EnvVar{Name: name, Value: spec.Env[name]})
+	}
+
+	job.Spec.Template.Spec.Containers = []corev1.Container{
+		{
+			Name:    "keptn-task",
+			Image:   spec.Image,
+			Command: spec.Command,
+			Args:    spec.Args,
+			Env:     envVars,
+		},
+	}
+	return job, nil
+}
@@ -0,0 +1,62 @@
+package keptntask
+
+import (
+	"testing"
+
+	"github.com/go-logr/logr"
+	klcv1alpha1 "github.com/keptn/lifecycle-controller/operator/api/v1alpha1"
+	testrequire "github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+func TestGenerateContainerJob(t *testing.T) {
+	scheme := runtime.NewScheme()
+	testrequire.Nil(t, klcv1alpha1.AddToScheme(scheme))
+	r := &KeptnTaskReconciler{Scheme: scheme, Log: logr.Discard()}
+	task := &klcv1alpha1.KeptnTask{ObjectMeta: metav1.ObjectMeta{Name: "pre-deployment-check", Namespace: "default"}}
+	spec := &klcv1alpha1.ContainerSpec{
+		Image:            "curlimages/curl:8.1.2",
+		Command:          []string{"sh", "-c"},
+		Args:             []string{"curl -f http://checkout/$(KEPTN_WORKLOAD_VERSION)/health"},
+		Env:              map[string]string{"KEPTN_NAMESPACE": "shop", "RETRIES": "3"},
+		ImagePullSecrets: []corev1.LocalObjectReference{{Name: "registry"}},
+	}
+	params := FunctionExecutionParams{
+		Runner:     klcv1alpha1.RunnerSpec{Image: spec.Image, ImagePullSecrets: spec.ImagePullSecrets},
+		Parameters: map[string]string{"url": "http://checkout"},
+		Context: klcv1alpha1.TaskContext{
+			AppName:         "shop",
+			WorkloadName:    "checkout",
+			WorkloadVersion: "1.2.0",
+			ObjectType:      "Workload",
+		},
+		Retries: 2,
+	}
+
+	job, err := r.generateContainerJob(task, spec, params)
+
+	testrequire.Nil(t, err)
+	testrequire.Equal(t, []corev1.LocalObjectReference{{Name: "registry"}}, job.Spec.Template.Spec.ImagePullSecrets)
+	testrequire.Equal(t, int32(0), *job.Spec.BackoffLimit)
+	testrequire.Len(t, job.Spec.Template.Spec.Containers, 1)
+	container := job.Spec.Template.Spec.Containers[0]
+	testrequire.Equal(t, "curlimages/curl:8.1.2", container.Image)
+	testrequire.Equal(t, spec.Command, container.Command)
+	testrequire.Equal(t, spec.Args, container.Args)
+
+	env := map[string]string{}
+	for _, envVar := range container.Env {
+		_, duplicate := env[envVar.Name]
+		testrequire.False(t, duplicate, envVar.Name)
+		env[envVar.Name] = envVar.Value
+	}
+	testrequire.Equal(t, `{"url":"http://checkout"}`, env["DATA"])
+	testrequire.Contains(t, env["CONTEXT"], `"workloadVersion":"1.2.0"`)
+	testrequire.Equal(t, "pre-deployment-check", env["KEPTN_TASK_NAME"])
+	testrequire.Equal(t, "checkout", env["KEPTN_WORKLOAD_NAME"])
+	testrequire.Equal(t, "1.2.0", env["KEPTN_WORKLOAD_VERSION"])
+	testrequire.Equal(t, "shop", env["KEPTN_NAMESPACE"])
+	testrequire.Equal(t, "3", env["RETRIES"])
+}
@@ -23,8 +23,12 @@ import (
 
 	"github.com/go-logr/logr"
 	klcv1alpha1 "github.com/keptn/lifecycle-controller/operator/api/v1alpha1"
-	"github.com/keptn/lifecycle-controller/operator/api/v1alpha1/common"
 	"github.com/keptn/lifecycle-controller/operator/api/v1alpha1/semconv"
+	controllererrors "github.com/keptn/lifecycle-controller/operator/controllers/errors"
+	"github.com/keptn/lifecycle-controller/operator/controllers/propagate"
+	"github.com/keptn/lifecycle-controller/operator/controllers/reasons"
+	"github.com/keptn/lifecycle-controller/operator/controllers/selfmonitoring"
+	"github.com/keptn/lifecycle-controller/operator/metrics"
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/codes"
 	"go.opentelemetry.io/otel/propagation"
@@ -45,15 +49,29 @@ type KeptnTaskReconciler struct {
 	Scheme   *runtime.Scheme
 	Recorder record.EventRecorder
 	Log      logr.Logger
-	Meters   common.KeptnMeters
+	Meters   metrics.Meters
 	Tracer   trace.Tracer
+	// Propagation selects the labels and annotations of the task copied to its Jobs and their pods
+	Propagation propagate.Policy
+	// MaxRunningJobs limits the number of running task Jobs per namespace, unless the namespace sets its own limit.
+	// Zero disables the limit.
+	MaxRunningJobs int
+	// APIReader reads the Leases and Jobs of exactly-once tasks from the API server instead of the cache
+	APIReader client.Reader
+	// Identity identifies the replica of the operator in the Leases of exactly-once tasks
+	Identity string
+
+	jobSpans map[string]*jobSpan
 }
 
 //+kubebuilder:rbac:groups=lifecycle.keptn.sh,resources=keptntasks,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups=lifecycle.keptn.sh,resources=keptntasks/status,verbs=get;update;patch
 //+kubebuilder:rbac:groups=lifecycle.keptn.sh,resources=keptntasks/finalizers,verbs=update
-//+kubebuilder:rbac:groups=batch,resources=jobs,verbs=create;get;update;list;watch
+//+kubebuilder:rbac:groups=batch,resources=jobs,verbs=create;get;update;patch;list;watch
+//+kubebuilder:rbac:groups=core,resources=pods,verbs=get;list;watch
+//+kubebuilder:rbac:groups=core,resources=namespaces,verbs=get;list;watch
 //+kubebuilder:rbac:groups=batch,resources=jobs/status,verbs=get;list
+//+kubebuilder:rbac:groups=coordination.k8s.io,resources=leases,verbs=get;create;update
 
 func (r *KeptnTaskReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
 	r.Log.Info("Reconciling KeptnTask")
@@ -63,6 +81,7 @@ func (r *KeptnTaskReconciler) Reconcile(ctx context.Context, req ctrl.Request) (
 		if errors.IsNotFound(err) {
 			// taking down all associated K8s resources is handled by K8s
 			r.Log.Info("KeptnTask resource not found. Ignoring since object must be deleted")
+			r.unbindJobSpans(req.NamespacedName)
 			return ctrl.Result{}, nil
 		}
 		r.Log.Error(err, "Failed to get the KeptnTask")
@@ -85,29 +104,64 @@ func (r *KeptnTaskReconciler) Reconcile(ctx context.Context, req ctrl.Request) (
 		return ctrl.Result{Requeue: true}, err
 	}
 
-	jobExists, err := r.JobExists(ctx, *task, req.Namespace)
-	if err != nil {
-		r.Log.Error(err, "Could not check if job is running")
-		span.SetStatus(codes.Error, err.Error())
-		return ctrl.Result{Requeue: true, RequeueAfter: 30 * time.Second}, nil
-	}
-
-	if !jobExists {
-		err = r.createJob(ctx, req, task)
+	if duration, isWaitTask := r.getWaitDuration(ctx, task); isWaitTask {
+		if remaining := r.reconcileWait(task, duration, time.Now()); remaining > 0 {
+			if err := r.Client.Status().Update(ctx, task); err != nil {
+				span.SetStatus(codes.Error, err.Error())
+				return ctrl.Result{Requeue: true}, err
+			}
+			return ctrl.Result{Requeue: true, RequeueAfter: remaining}, nil
+		}
+	} else {
+		jobExists, err := r.JobExists(ctx, *task, req.Namespace)
 		if err != nil {
+			r.Log.Error(err, "Could not check if job is running")
 			span.SetStatus(codes.Error, err.Error())
-			return ctrl.Result{Requeue: true}, err
+			return ctrl.Result{Requeue: true, RequeueAfter: 30 * time.Second}, nil
 		}
-		return ctrl.Result{Requeue: true, RequeueAfter: 10 * time.Second}, nil
-	}
 
-	if !task.Status.Status.IsCompleted() {
-		err := r.updateJob(ctx, req, task)
-		if err != nil {
-			span.SetStatus(codes.Error, err.Error())
-			return ctrl.Result{Requeue: true, RequeueAfter: 10 * time.Second}, err
+		if !jobExists && !task.Status.Status.IsCompleted() {
+			throttled, err := r.throttleJob(ctx, task)
+			if err != nil {
+				r.Log.Error(err, "Could not check the number of running jobs")
+				span.SetStatus(codes.Error, err.Error())
+				return ctrl.Result{Requeue: true, RequeueAfter: 30 * time.Second}, nil
+			}
+			if throttled {
+				return ctrl.Result{Requeue: true, RequeueAfter: 10 * time.Second}, nil
+			}
+			if task.Spec.ExactlyOnce {
+				locked, err := r.lockJob(ctx, task, time.Now())
+				if err != nil {
+					r.Log.Error(err, "Could not lock the job of the task")
+					span.SetStatus(codes.Error, err.Error())
+					return ctrl.Result{Requeue: true, RequeueAfter: 10 * time.Second}, nil
+				}
+				if !locked {
+					return ctrl.Result{Requeue: true, RequeueAfter: 10 * time.Second}, nil
+				}
+			}
+			err = r.createJob(ctx, req, task)
+			if task.Spec.ExactlyOnce {
+				if err := r.unlockJob(ctx, task, time.Now()); err != nil {
+					r.Log.Error(err, "Could not unlock the job of the task")
+				}
+			}
+			if err != nil {
+				span.SetStatus(codes.Error, err.Error())
+				return controllererrors.Result(err)
+			}
+			return ctrl.Result{Requeue: true, RequeueAfter: 10 * time.Second}, nil
+		}
+
+		if !task.Status.Status.IsCompleted() {
+			err := r.updateJob(ctx, req, task)
+			if err != nil {
+				span.SetStatus(codes.Error, err.Error())
+				return ctrl.Result{Requeue: true, RequeueAfter: 10 * time.Second}, err
+			}
+			return ctrl.Result{Requeue: true, RequeueAfter: 10 * time.Second}, nil
 		}
-		return ctrl.Result{Requeue: true, RequeueAfter: 10 * time.Second}, nil
 	}
 
 	r.Log.Info("Finished Reconciling KeptnTask")
@@ -126,11 +180,11 @@ func (r *KeptnTaskReconciler) Reconcile(ctx context.Context, req ctrl.Request) (
 	r.Log.Info("Increasing task count")
 
 	// metrics: increment task counter
-	r.Meters.TaskCount.Add(ctx, 1, attrs...)
+	r.Meters.Add(ctx, metrics.TaskCount, 1, attrs...)
 
 	// metrics: add task duration
 	duration := task.Status.EndTime.Time.Sub(task.Status.StartTime.Time)
-	r.Meters.TaskDuration.Record(ctx, duration.Seconds(), attrs...)
+	r.Meters.Record(ctx, metrics.TaskDuration, duration.Seconds(), attrs...)
 
 	return ctrl.Result{}, nil
 }
@@ -141,7 +195,7 @@ func (r *KeptnTaskReconciler) SetupWithManager(mgr ctrl.Manager) error {
 		// predicate disabling the auto reconciliation after updating the object status
 		For(&klcv1alpha1.KeptnTask{}, builder.WithPredicates(predicate.GenerationChangedPredicate{})).
 		Owns(&batchv1.Job{}).
-		Complete(r)
+		Complete(selfmonitoring.Wrap("keptntask", r))
 }
 
 func (r *KeptnTaskReconciler) JobExists(ctx context.Context, task klcv1alpha1.KeptnTask, namespace string) (bool, error) {
@@ -167,25 +221,7 @@ func (r *KeptnTaskReconciler) JobExists(ctx context.Context, task klcv1alpha1.Ke
 	return false, nil
 }
 
-func (r *KeptnTaskReconciler) GetActiveTasks(ctx context.Context) ([]common.GaugeValue, error) {
-	tasks := &klcv1alpha1.KeptnTaskList{}
-	err := r.List(ctx, tasks)
-	if err != nil {
-		return nil, fmt.Errorf("could not retrieve workload instances: %w", err)
-	}
-
-	res := []common.GaugeValue{}
-
-	for _, task := range tasks.Items {
-		gaugeValue := int64(0)
-		if !task.IsEndTimeSet() {
-			gaugeValue = int64(1)
-		}
-		res = append(res, common.GaugeValue{
-			Value:      gaugeValue,
-			Attributes: task.GetActiveMetricsAttributes(),
-		})
-	}
-
-	return res, nil
+// recordEvent records an event of the given reason of the catalog for the task, whose message is formatted with args
+func (r *KeptnTaskReconciler) recordEvent(task *klcv1alpha1.KeptnTask, reason reasons.Reason, args ...interface{}) {
+	r.Recorder.AnnotatedEventf(task, reason.Annotations(), string(reason.Severity), reason.Code, "%s / Namespace: %s, TaskName: %s ", reason.Message(args...), task.Namespace, task.Name)
 }
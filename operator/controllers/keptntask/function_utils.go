@@ -3,11 +3,15 @@ package keptntask
 import (
 	"encoding/json"
 	"fmt"
+	"math"
 	"math/rand"
 	"os"
+	"strings"
+	"time"
 
 	klcv1alpha1 "github.com/keptn/lifecycle-controller/operator/api/v1alpha1"
 	"github.com/keptn/lifecycle-controller/operator/api/v1alpha1/common"
+	"github.com/keptn/lifecycle-controller/operator/controllers/deadline"
 	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -20,9 +24,148 @@ type FunctionExecutionParams struct {
 	SecureParameters string
 	URL              string
 	Context          klcv1alpha1.TaskContext
+	TraceParent      string
+	Baggage          string
+	Runner           klcv1alpha1.RunnerSpec
+	Dependencies     klcv1alpha1.FunctionDependencies
+	Identity         klcv1alpha1.FunctionIdentity
+	Runtime          Runtime
+	Deadline         *metav1.Time
+	Retries          int32
+	Timeout          time.Duration
+}
+
+const (
+	dependenciesVolume = "function-dependencies"
+	// vendorBundleKey is the key of the tarball of the vendor directory in the ConfigMap of the dependencies
+	vendorBundleKey  = "vendor.tar.gz"
+	vendorBundlePath = "/var/vendor-bundle"
+	vendorPath       = "/var/vendor"
+)
+
+// Runtime is the runtime executing the code of a function
+type Runtime string
+
+const (
+	// RuntimeDeno executes the functions of task definitions in the function runner
+	RuntimeDeno Runtime = "deno"
+	// RuntimePython executes the Python scripts of task definitions in the Python runner
+	RuntimePython Runtime = "python"
+)
+
+// Function returns the function of a task definition and the runtime executing it, which is the Python runtime if the
+// task definition contains a Python script
+func Function(definition *klcv1alpha1.KeptnTaskDefinition) (klcv1alpha1.FunctionSpec, Runtime) {
+	if definition.Spec.Python != nil {
+		return *definition.Spec.Python, RuntimePython
+	}
+	return definition.Spec.Function, RuntimeDeno
+}
+
+// RunnerImage returns the image running the code of the runtime and its pull secrets. The Python runner is either set
+// in the task definition or by the PYTHON_RUNNER_IMAGE of the operator.
+func (rt Runtime) RunnerImage(runner klcv1alpha1.RunnerSpec, namespaceAnnotations map[string]string) klcv1alpha1.RunnerSpec {
+	if rt != RuntimePython {
+		return RunnerImage(runner, namespaceAnnotations)
+	}
+	if runner.Image == "" {
+		runner.Image = os.Getenv("PYTHON_RUNNER_IMAGE")
+	}
+	return runner
+}
+
+// scriptPath returns the path the code of a function is mounted at, whose extension tells the runtime its language
+func (rt Runtime) scriptPath() string {
+	if rt == RuntimePython {
+		return "/var/data/function.py"
+	}
+	return "/var/data/function.ts"
+}
+
+// RunnerImage returns the image of the function runner and its pull secrets. The runner of the task definition takes
+// precedence over the one annotated on the namespace, which takes precedence over the FUNCTION_RUNNER_IMAGE of the operator.
+func RunnerImage(runner klcv1alpha1.RunnerSpec, namespaceAnnotations map[string]string) klcv1alpha1.RunnerSpec {
+	if runner.Image != "" {
+		return runner
+	}
+	image := namespaceAnnotations[common.FunctionRunnerImageAnnotation]
+	if image == "" {
+		return klcv1alpha1.RunnerSpec{Image: os.Getenv("FUNCTION_RUNNER_IMAGE"), ImagePullSecrets: runner.ImagePullSecrets}
+	}
+	pullSecrets := runner.ImagePullSecrets
+	for _, name := range strings.Split(namespaceAnnotations[common.FunctionRunnerImagePullSecretsAnnotation], ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			pullSecrets = append(pullSecrets, corev1.LocalObjectReference{Name: name})
+		}
+	}
+	return klcv1alpha1.RunnerSpec{Image: image, ImagePullSecrets: pullSecrets}
 }
 
 func (r *KeptnTaskReconciler) generateFunctionJob(task *klcv1alpha1.KeptnTask, params FunctionExecutionParams) (*batchv1.Job, error) {
+	job := r.newJob(task, params)
+	job.Spec.Template.ObjectMeta.Labels = copyMap(params.Identity.PodLabels)
+	job.Spec.Template.ObjectMeta.Annotations = copyMap(params.Identity.PodAnnotations)
+	job.Spec.Template.Spec.ServiceAccountName = params.Identity.ServiceAccountName
+	r.Propagation.Copy(task.ObjectMeta, &job.Spec.Template.ObjectMeta)
+
+	container := corev1.Container{
+		Name:  "keptn-function-runner",
+		Image: params.Runner.Image,
+	}
+
+	envVars, err := taskEnvVars(params)
+	if err != nil {
+		return job, err
+	}
+
+	// Mount the function code if a ConfigMap is provided
+	// The ConfigMap might be provided manually or created by the TaskDefinition controller
+	if params.ConfigMap != "" {
+		envVars = append(envVars, corev1.EnvVar{Name: "SCRIPT", Value: params.Runtime.scriptPath()})
+
+		job.Spec.Template.Spec.Volumes = append(job.Spec.Template.Spec.Volumes,
+			corev1.Volume{
+				Name: "function-mount",
+				VolumeSource: corev1.VolumeSource{
+					ConfigMap: &corev1.ConfigMapVolumeSource{
+						LocalObjectReference: corev1.LocalObjectReference{
+							Name: params.ConfigMap,
+						},
+					},
+				},
+			},
+		)
+		container.VolumeMounts = append(container.VolumeMounts,
+			corev1.VolumeMount{
+				Name:      "function-mount",
+				ReadOnly:  true,
+				MountPath: params.Runtime.scriptPath(),
+				SubPath:   "code",
+			},
+		)
+	} else {
+		envVars = append(envVars, corev1.EnvVar{Name: "SCRIPT", Value: params.URL})
+	}
+
+	if params.Runtime == RuntimePython && params.Dependencies != (klcv1alpha1.FunctionDependencies{}) {
+		return job, fmt.Errorf("dependencies cannot be vendored for Python scripts")
+	}
+	dependencyEnvVars, err := addDependencies(job, &container, params)
+	if err != nil {
+		return job, err
+	}
+	envVars = append(envVars, dependencyEnvVars...)
+
+	container.Env = envVars
+	job.Spec.Template.Spec.Containers = []corev1.Container{
+		container,
+	}
+	return job, nil
+}
+
+// newJob returns the Job executing a task, which is terminated by kubernetes once it exceeds the timeout of the task or
+// the deadline of the task has passed
+func (r *KeptnTaskReconciler) newJob(task *klcv1alpha1.KeptnTask, params FunctionExecutionParams) *batchv1.Job {
 	randomId := rand.Intn(99999-10000) + 10000
 	jobId := fmt.Sprintf("klc-%s-%d", common.TruncateString(task.Name, common.MaxTaskNameLength), randomId)
 	job := &batchv1.Job{
@@ -34,37 +177,71 @@ func (r *KeptnTaskReconciler) generateFunctionJob(task *klcv1alpha1.KeptnTask, p
 		Spec: batchv1.JobSpec{
 			Template: corev1.PodTemplateSpec{
 				Spec: corev1.PodSpec{
-					RestartPolicy: "OnFailure",
+					RestartPolicy:    "OnFailure",
+					ImagePullSecrets: params.Runner.ImagePullSecrets,
 				},
 			},
 		},
 	}
+	r.Propagation.Copy(task.ObjectMeta, &job.ObjectMeta)
 	err := controllerutil.SetControllerReference(task, job, r.Scheme)
 	if err != nil {
 		r.Log.Error(err, "could not set controller reference:")
 	}
-
-	container := corev1.Container{
-		Name:  "keptn-function-runner",
-		Image: os.Getenv("FUNCTION_RUNNER_IMAGE"),
+	if params.Deadline != nil || params.Timeout > 0 {
+		activeDeadline := params.Timeout
+		if params.Deadline != nil {
+			if remaining := deadline.Remaining(*params.Deadline, time.Now()); activeDeadline <= 0 || remaining < activeDeadline {
+				activeDeadline = remaining
+			}
+		}
+		activeDeadlineSeconds := int64(math.Ceil(activeDeadline.Seconds()))
+		if activeDeadlineSeconds < 1 {
+			activeDeadlineSeconds = 1
+		}
+		job.Spec.ActiveDeadlineSeconds = &activeDeadlineSeconds
+	}
+	// failed pods of tasks with retries fail the Job, which is then started again after the backoff of the task
+	if params.Retries > 0 {
+		backoffLimit := int32(0)
+		job.Spec.BackoffLimit = &backoffLimit
 	}
+	return job
+}
 
+// taskEnvVars returns the environment variables passing the parameters, the context and the deadline of a task to
+// the container executing it
+func taskEnvVars(params FunctionExecutionParams) ([]corev1.EnvVar, error) {
 	var envVars []corev1.EnvVar
 
 	if len(params.Parameters) > 0 {
 		jsonParams, err := json.Marshal(params.Parameters)
 		if err != nil {
-			return job, fmt.Errorf("could not marshal parameters")
+			return nil, fmt.Errorf("could not marshal parameters")
 		}
 		envVars = append(envVars, corev1.EnvVar{Name: "DATA", Value: string(jsonParams)})
 	}
 
 	jsonParams, err := json.Marshal(params.Context)
 	if err != nil {
-		return job, fmt.Errorf("could not marshal parameters")
+		return nil, fmt.Errorf("could not marshal parameters")
 	}
 	envVars = append(envVars, corev1.EnvVar{Name: "CONTEXT", Value: string(jsonParams)})
 
+	// spans of the function are children of the span of the execution of the job
+	if params.TraceParent != "" {
+		envVars = append(envVars, corev1.EnvVar{Name: "TRACEPARENT", Value: params.TraceParent})
+	}
+	// the deployment context, e.g. the app, version and environment, is passed on to the services called by the function
+	if params.Baggage != "" {
+		envVars = append(envVars, corev1.EnvVar{Name: "BAGGAGE", Value: params.Baggage})
+	}
+
+	// the function can use the deadline to limit its own calls, e.g. the timeouts of requests
+	if params.Deadline != nil {
+		envVars = append(envVars, corev1.EnvVar{Name: "KEPTN_DEADLINE", Value: params.Deadline.UTC().Format(time.RFC3339)})
+	}
+
 	if params.SecureParameters != "" {
 		envVars = append(envVars, corev1.EnvVar{
 			Name: "SECURE_DATA",
@@ -76,53 +253,95 @@ func (r *KeptnTaskReconciler) generateFunctionJob(task *klcv1alpha1.KeptnTask, p
 			},
 		})
 	}
+	return envVars, nil
+}
 
-	// Mount the function code if a ConfigMap is provided
-	// The ConfigMap might be provided manually or created by the TaskDefinition controller
-	if params.ConfigMap != "" {
-		envVars = append(envVars, corev1.EnvVar{Name: "SCRIPT", Value: "/var/data/function.ts"})
+// copyMap returns a copy of the given labels or annotations, so that the ones of the cached task definition are not changed
+func copyMap(m map[string]string) map[string]string {
+	if len(m) == 0 {
+		return nil
+	}
+	c := make(map[string]string, len(m))
+	for k, v := range m {
+		c[k] = v
+	}
+	return c
+}
 
-		job.Spec.Template.Spec.Volumes = []corev1.Volume{
-			{
-				Name: "function-mount",
-				VolumeSource: corev1.VolumeSource{
-					ConfigMap: &corev1.ConfigMapVolumeSource{
-						LocalObjectReference: corev1.LocalObjectReference{
-							Name: params.ConfigMap,
-						},
-					},
-				},
-			},
-		}
-		container.VolumeMounts = []corev1.VolumeMount{
-			{
-				Name:      "function-mount",
-				ReadOnly:  true,
-				MountPath: "/var/data/function.ts",
-				SubPath:   "code",
-			},
+// addDependencies provides the vendored dependencies of the function to the runner container and returns the
+// environment variables telling the runtime where to find them. The runtime verifies the digest of a ConfigMap bundle
+// before extracting it, while images are pinned by the digest of their reference.
+func addDependencies(job *batchv1.Job, container *corev1.Container, params FunctionExecutionParams) ([]corev1.EnvVar, error) {
+	dependencies := params.Dependencies
+	var envVars []corev1.EnvVar
+	if dependencies.NoRemote {
+		if params.ConfigMap == "" {
+			return nil, fmt.Errorf("function %s cannot be fetched if remote modules are disallowed", params.URL)
 		}
-	} else {
-		envVars = append(envVars, corev1.EnvVar{Name: "SCRIPT", Value: params.URL})
+		envVars = append(envVars, corev1.EnvVar{Name: "NO_REMOTE", Value: "true"})
 	}
 
-	container.Env = envVars
-	job.Spec.Template.Spec.Containers = []corev1.Container{
-		container,
+	switch {
+	case dependencies.ConfigMapRef.Name != "" && dependencies.Image != "":
+		return nil, fmt.Errorf("dependencies have to be provided either by a ConfigMap or by an image")
+	case dependencies.ConfigMapRef.Name != "":
+		if dependencies.Digest == "" {
+			return nil, fmt.Errorf("digest of the dependencies in ConfigMap %s is missing", dependencies.ConfigMapRef.Name)
+		}
+		job.Spec.Template.Spec.Volumes = append(job.Spec.Template.Spec.Volumes, corev1.Volume{
+			Name: dependenciesVolume,
+			VolumeSource: corev1.VolumeSource{
+				ConfigMap: &corev1.ConfigMapVolumeSource{
+					LocalObjectReference: corev1.LocalObjectReference{Name: dependencies.ConfigMapRef.Name},
+					Items:                []corev1.KeyToPath{{Key: vendorBundleKey, Path: vendorBundleKey}},
+				},
+			},
+		})
+		container.VolumeMounts = append(container.VolumeMounts, corev1.VolumeMount{
+			Name:      dependenciesVolume,
+			ReadOnly:  true,
+			MountPath: vendorBundlePath,
+		})
+		envVars = append(envVars,
+			corev1.EnvVar{Name: "VENDOR_BUNDLE", Value: vendorBundlePath + "/" + vendorBundleKey},
+			corev1.EnvVar{Name: "VENDOR_DIGEST", Value: dependencies.Digest},
+		)
+	case dependencies.Image != "":
+		if !strings.Contains(dependencies.Image, "@sha256:") {
+			return nil, fmt.Errorf("image %s of the dependencies is not pinned by its digest", dependencies.Image)
+		}
+		// the vendor directory is copied from the image into a volume shared with the runner container
+		job.Spec.Template.Spec.Volumes = append(job.Spec.Template.Spec.Volumes, corev1.Volume{
+			Name:         dependenciesVolume,
+			VolumeSource: corev1.VolumeSource{EmptyDir: &corev1.EmptyDirVolumeSource{}},
+		})
+		job.Spec.Template.Spec.InitContainers = append(job.Spec.Template.Spec.InitContainers, corev1.Container{
+			Name:         "keptn-function-dependencies",
+			Image:        dependencies.Image,
+			Command:      []string{"cp", "-r", "/vendor/.", vendorPath},
+			VolumeMounts: []corev1.VolumeMount{{Name: dependenciesVolume, MountPath: vendorPath}},
+		})
+		container.VolumeMounts = append(container.VolumeMounts, corev1.VolumeMount{
+			Name:      dependenciesVolume,
+			ReadOnly:  true,
+			MountPath: vendorPath,
+		})
+		envVars = append(envVars, corev1.EnvVar{Name: "VENDOR_DIR", Value: vendorPath})
 	}
-	return job, nil
+	return envVars, nil
 }
 
 func (r *KeptnTaskReconciler) parseFunctionTaskDefinition(definition *klcv1alpha1.KeptnTaskDefinition) (FunctionExecutionParams, bool, error) {
-	params := FunctionExecutionParams{}
+	function, runtime := Function(definition)
+	params := FunctionExecutionParams{Runtime: runtime}
 
 	// Firstly check if this task definition has a parent object
 	hasParent := false
-	if definition.Spec.Function.FunctionReference != (klcv1alpha1.FunctionReference{}) {
+	if function.FunctionReference != (klcv1alpha1.FunctionReference{}) {
 		hasParent = true
 	}
 
-	if definition.Status.Function.ConfigMap != "" && definition.Spec.Function.HttpReference.Url != "" {
+	if definition.Status.Function.ConfigMap != "" && function.HttpReference.Url != "" {
 		r.Log.Info(fmt.Sprintf("The JobDefinition contains a ConfigMap and a HTTP Reference, ConfigMap is used / Namespace: %s, Name: %s  ", definition.Namespace, definition.Name))
 	}
 
@@ -131,20 +350,24 @@ func (r *KeptnTaskReconciler) parseFunctionTaskDefinition(definition *klcv1alpha
 		params.ConfigMap = definition.Status.Function.ConfigMap
 	} else {
 		// If not, check if it has an HTTP reference. If this is also not the case and the object has no parent, something is wrong
-		if definition.Spec.Function.HttpReference.Url == "" && !hasParent {
+		if function.HttpReference.Url == "" && !hasParent {
 			return params, false, fmt.Errorf("No ConfigMap specified or HTTP source specified in TaskDefinition) / Namespace: %s, Name: %s ", definition.Namespace, definition.Name)
 		}
-		params.URL = definition.Spec.Function.HttpReference.Url
+		params.URL = function.HttpReference.Url
 	}
 
 	// Check if there are parameters provided
-	if len(definition.Spec.Function.Parameters.Inline) > 0 {
-		params.Parameters = definition.Spec.Function.Parameters.Inline
+	if len(function.Parameters.Inline) > 0 {
+		params.Parameters = function.Parameters.Inline
 	}
 
+	params.Runner = function.Runner
+	params.Dependencies = function.Dependencies
+	params.Identity = function.Identity
+
 	// Check if there is a secret for secret params provided
-	if definition.Spec.Function.SecureParameters.Secret != "" {
-		params.SecureParameters = definition.Spec.Function.SecureParameters.Secret
+	if function.SecureParameters.Secret != "" {
+		params.SecureParameters = function.SecureParameters.Secret
 	}
 	return params, hasParent, nil
 }
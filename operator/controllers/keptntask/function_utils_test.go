@@ -0,0 +1,174 @@
+package keptntask
+
+import (
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+	klcv1alpha1 "github.com/keptn/lifecycle-controller/operator/api/v1alpha1"
+	"github.com/keptn/lifecycle-controller/operator/controllers/propagate"
+	testrequire "github.com/stretchr/testify/require"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+const testDigest = "sha256:4b825dc642cb6eb9a060e54bf8d69288fbee4904b825dc642cb6eb9a060e54bf"
+
+func TestAddDependencies(t *testing.T) {
+	tests := []struct {
+		name         string
+		params       FunctionExecutionParams
+		wantEnv      []corev1.EnvVar
+		wantVolume   bool
+		wantInit     bool
+		wantErrorMsg string
+	}{
+		{
+			name:   "no dependencies",
+			params: FunctionExecutionParams{ConfigMap: "function"},
+		},
+		{
+			name: "configmap",
+			params: FunctionExecutionParams{ConfigMap: "function", Dependencies: klcv1alpha1.FunctionDependencies{
+				ConfigMapRef: klcv1alpha1.ConfigMapReference{Name: "slack-deps"},
+				Digest:       testDigest,
+				NoRemote:     true,
+			}},
+			wantEnv: []corev1.EnvVar{
+				{Name: "NO_REMOTE", Value: "true"},
+				{Name: "VENDOR_BUNDLE", Value: "/var/vendor-bundle/vendor.tar.gz"},
+				{Name: "VENDOR_DIGEST", Value: testDigest},
+			},
+			wantVolume: true,
+		},
+		{
+			name: "image",
+			params: FunctionExecutionParams{URL: "https://example.com/slack.ts", Dependencies: klcv1alpha1.FunctionDependencies{
+				Image: "registry.internal/slack-deps@" + testDigest,
+			}},
+			wantEnv:    []corev1.EnvVar{{Name: "VENDOR_DIR", Value: "/var/vendor"}},
+			wantVolume: true,
+			wantInit:   true,
+		},
+		{
+			name: "configmap without digest",
+			params: FunctionExecutionParams{ConfigMap: "function", Dependencies: klcv1alpha1.FunctionDependencies{
+				ConfigMapRef: klcv1alpha1.ConfigMapReference{Name: "slack-deps"},
+			}},
+			wantErrorMsg: "digest of the dependencies in ConfigMap slack-deps is missing",
+		},
+		{
+			name: "image with tag",
+			params: FunctionExecutionParams{ConfigMap: "function", Dependencies: klcv1alpha1.FunctionDependencies{
+				Image: "registry.internal/slack-deps:latest",
+			}},
+			wantErrorMsg: "image registry.internal/slack-deps:latest of the dependencies is not pinned by its digest",
+		},
+		{
+			name: "remote function without remote modules",
+			params: FunctionExecutionParams{URL: "https://example.com/slack.ts", Dependencies: klcv1alpha1.FunctionDependencies{
+				NoRemote: true,
+			}},
+			wantErrorMsg: "function https://example.com/slack.ts cannot be fetched if remote modules are disallowed",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			job := &batchv1.Job{}
+			container := &corev1.Container{}
+
+			envVars, err := addDependencies(job, container, tt.params)
+
+			if tt.wantErrorMsg != "" {
+				testrequire.EqualError(t, err, tt.wantErrorMsg)
+				return
+			}
+			testrequire.Nil(t, err)
+			testrequire.Equal(t, tt.wantEnv, envVars)
+			testrequire.Equal(t, tt.wantVolume, len(job.Spec.Template.Spec.Volumes) == 1)
+			testrequire.Equal(t, tt.wantVolume, len(container.VolumeMounts) == 1)
+			testrequire.Equal(t, tt.wantInit, len(job.Spec.Template.Spec.InitContainers) == 1)
+		})
+	}
+}
+
+func TestGenerateFunctionJobIdentity(t *testing.T) {
+	scheme := runtime.NewScheme()
+	testrequire.Nil(t, klcv1alpha1.AddToScheme(scheme))
+	r := &KeptnTaskReconciler{
+		Scheme:      scheme,
+		Log:         logr.Discard(),
+		Propagation: propagate.Policy{Labels: []string{"team", "azure.workload.identity/use"}},
+	}
+	task := &klcv1alpha1.KeptnTask{ObjectMeta: metav1.ObjectMeta{
+		Name:      "pre-deployment-check",
+		Namespace: "default",
+		Labels:    map[string]string{"team": "checkout", "azure.workload.identity/use": "false"},
+	}}
+	identity := klcv1alpha1.FunctionIdentity{
+		ServiceAccountName: "deployment-checks",
+		PodLabels:          map[string]string{"azure.workload.identity/use": "true"},
+		PodAnnotations:     map[string]string{"eks.amazonaws.com/sts-regional-endpoints": "true"},
+	}
+
+	job, err := r.generateFunctionJob(task, FunctionExecutionParams{ConfigMap: "function", Identity: identity})
+
+	testrequire.Nil(t, err)
+	testrequire.Equal(t, "deployment-checks", job.Spec.Template.Spec.ServiceAccountName)
+	testrequire.Equal(t, map[string]string{"azure.workload.identity/use": "true", "team": "checkout"}, job.Spec.Template.Labels)
+	testrequire.Equal(t, map[string]string{"eks.amazonaws.com/sts-regional-endpoints": "true"}, job.Spec.Template.Annotations)
+	testrequire.Equal(t, map[string]string{"azure.workload.identity/use": "true"}, identity.PodLabels)
+}
+
+func TestGenerateFunctionJobActiveDeadline(t *testing.T) {
+	scheme := runtime.NewScheme()
+	testrequire.Nil(t, klcv1alpha1.AddToScheme(scheme))
+	r := &KeptnTaskReconciler{Scheme: scheme, Log: logr.Discard()}
+	task := &klcv1alpha1.KeptnTask{ObjectMeta: metav1.ObjectMeta{Name: "pre-deployment-check", Namespace: "default"}}
+
+	job, err := r.generateFunctionJob(task, FunctionExecutionParams{ConfigMap: "function"})
+	testrequire.Nil(t, err)
+	testrequire.Nil(t, job.Spec.ActiveDeadlineSeconds)
+
+	job, err = r.generateFunctionJob(task, FunctionExecutionParams{ConfigMap: "function", Timeout: 5 * time.Minute})
+	testrequire.Nil(t, err)
+	testrequire.Equal(t, int64(300), *job.Spec.ActiveDeadlineSeconds)
+
+	// the deadline of the task takes precedence if it passes before the timeout
+	deadline := metav1.NewTime(time.Now().Add(time.Minute))
+	job, err = r.generateFunctionJob(task, FunctionExecutionParams{ConfigMap: "function", Timeout: 5 * time.Minute, Deadline: &deadline})
+	testrequire.Nil(t, err)
+	testrequire.LessOrEqual(t, *job.Spec.ActiveDeadlineSeconds, int64(60))
+}
+
+func TestGenerateFunctionJobPython(t *testing.T) {
+	t.Setenv("PYTHON_RUNNER_IMAGE", "ghcr.io/keptn/python-runtime:v0.3.0")
+	scheme := runtime.NewScheme()
+	testrequire.Nil(t, klcv1alpha1.AddToScheme(scheme))
+	r := &KeptnTaskReconciler{Scheme: scheme, Log: logr.Discard()}
+	task := &klcv1alpha1.KeptnTask{ObjectMeta: metav1.ObjectMeta{Name: "pre-deployment-check", Namespace: "default"}}
+	definition := &klcv1alpha1.KeptnTaskDefinition{
+		ObjectMeta: metav1.ObjectMeta{Name: "check", Namespace: "default"},
+		Spec:       klcv1alpha1.KeptnTaskDefinitionSpec{Python: &klcv1alpha1.FunctionSpec{Inline: klcv1alpha1.Inline{Code: "print('hello')"}}},
+		Status:     klcv1alpha1.KeptnTaskDefinitionStatus{Function: klcv1alpha1.FunctionStatus{ConfigMap: "keptnfn-check"}},
+	}
+
+	params, hasParent, err := r.parseFunctionTaskDefinition(definition)
+	testrequire.Nil(t, err)
+	testrequire.False(t, hasParent)
+	testrequire.Equal(t, RuntimePython, params.Runtime)
+	params.Runner = params.Runtime.RunnerImage(params.Runner, nil)
+
+	job, err := r.generateFunctionJob(task, params)
+	testrequire.Nil(t, err)
+	container := job.Spec.Template.Spec.Containers[0]
+	testrequire.Equal(t, "ghcr.io/keptn/python-runtime:v0.3.0", container.Image)
+	testrequire.Equal(t, "/var/data/function.py", container.VolumeMounts[0].MountPath)
+	testrequire.Contains(t, container.Env, corev1.EnvVar{Name: "SCRIPT", Value: "/var/data/function.py"})
+
+	params.Dependencies = klcv1alpha1.FunctionDependencies{Image: "registry.internal/deps@" + testDigest}
+	_, err = r.generateFunctionJob(task, params)
+	testrequire.EqualError(t, err, "dependencies cannot be vendored for Python scripts")
+}
@@ -0,0 +1,129 @@
+package keptntask
+
+import (
+	"context"
+	"time"
+
+	klcv1alpha1 "github.com/keptn/lifecycle-controller/operator/api/v1alpha1"
+	"github.com/keptn/lifecycle-controller/operator/api/v1alpha1/common"
+	"github.com/keptn/lifecycle-controller/operator/api/v1alpha1/semconv"
+	"github.com/keptn/lifecycle-controller/operator/controllers/reasons"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// jobSpan is the span of the execution of the Job of a KeptnTask, which records when the Job has been created,
+// when it started running and when it finished, so that the time spent in the queue can be told from the execution time
+type jobSpan struct {
+	span    trace.Span
+	task    types.NamespacedName
+	running bool
+}
+
+// startJobSpan starts the span of the execution of a Job as child of the span of the KeptnTask.
+// Since the span of the phase the KeptnTask has been created in is not its direct parent, the span is linked to it.
+func (r *KeptnTaskReconciler) startJobSpan(ctx context.Context, task *klcv1alpha1.KeptnTask, startTime time.Time) (context.Context, trace.Span) {
+	opts := []trace.SpanStartOption{trace.WithSpanKind(trace.SpanKindConsumer), trace.WithTimestamp(startTime)}
+	if phaseSpanContext := semconv.SpanContextFromTraceParent(task.Annotations[common.PhaseTraceParentAnnotation]); phaseSpanContext.IsValid() {
+		opts = append(opts, trace.WithLinks(trace.Link{SpanContext: phaseSpanContext}))
+	}
+	ctx, span := r.Tracer.Start(ctx, semconv.JobSpanName, opts...)
+	semconv.AddAttributeFromTask(span, *task)
+	span.AddEvent("created", trace.WithTimestamp(startTime))
+	return ctx, span
+}
+
+// getJobStartTime returns the time the first container of the pods of the Job has been started
+func (r *KeptnTaskReconciler) getJobStartTime(ctx context.Context, job *batchv1.Job) (time.Time, bool) {
+	pods := &corev1.PodList{}
+	if err := r.Client.List(ctx, pods, client.InNamespace(job.Namespace), client.MatchingLabels{"job-name": job.Name}); err != nil {
+		r.Log.Error(err, "could not retrieve pods of job")
+		return time.Time{}, false
+	}
+	var startTime time.Time
+	for _, pod := range pods.Items {
+		for _, status := range pod.Status.ContainerStatuses {
+			var started time.Time
+			if status.State.Running != nil {
+				started = status.State.Running.StartedAt.Time
+			} else if status.State.Terminated != nil {
+				started = status.State.Terminated.StartedAt.Time
+			}
+			if !started.IsZero() && (startTime.IsZero() || started.Before(startTime)) {
+				startTime = started
+			}
+		}
+	}
+	return startTime, !startTime.IsZero()
+}
+
+func (r *KeptnTaskReconciler) bindJobSpan(task *klcv1alpha1.KeptnTask, jobName string, span trace.Span) {
+	if !span.IsRecording() {
+		return
+	}
+	if r.jobSpans == nil {
+		r.jobSpans = map[string]*jobSpan{}
+	}
+	r.jobSpans[jobName] = &jobSpan{span: span, task: types.NamespacedName{Namespace: task.Namespace, Name: task.Name}}
+}
+
+// unbindJobSpans ends the spans of the Jobs of a deleted KeptnTask, so they are not kept until the operator is restarted
+func (r *KeptnTaskReconciler) unbindJobSpans(task types.NamespacedName) {
+	for jobName, s := range r.jobSpans {
+		if s.task == task {
+			s.span.SetStatus(codes.Error, reasons.TaskDeleted.Code)
+			s.span.End()
+			delete(r.jobSpans, jobName)
+		}
+	}
+}
+
+// updateJobSpan records the progress of the Job in its span and ends the span once the Job has finished.
+// If the operator has been restarted since the Job has been created, the span is started again at the creation time of the Job.
+func (r *KeptnTaskReconciler) updateJobSpan(ctx context.Context, task *klcv1alpha1.KeptnTask, job *batchv1.Job) {
+	s, ok := r.jobSpans[job.Name]
+	if !ok {
+		_, span := r.startJobSpan(ctx, task, job.CreationTimestamp.Time)
+		if !span.IsRecording() {
+			return
+		}
+		r.bindJobSpan(task, job.Name, span)
+		s = r.jobSpans[job.Name]
+	}
+	s.span.SetAttributes(attribute.String("job.name", job.Name))
+
+	if !s.running {
+		if startTime, ok := r.getJobStartTime(ctx, job); ok {
+			s.running = true
+			s.span.AddEvent("running", trace.WithTimestamp(startTime))
+		}
+	}
+
+	if job.Status.Succeeded > 0 {
+		endTime := time.Now()
+		if job.Status.CompletionTime != nil {
+			endTime = job.Status.CompletionTime.Time
+		}
+		s.span.AddEvent("finished", trace.WithTimestamp(endTime))
+		s.span.SetStatus(codes.Ok, reasons.Succeeded.Code)
+		s.span.End(trace.WithTimestamp(endTime))
+		delete(r.jobSpans, job.Name)
+		return
+	}
+
+	for _, condition := range job.Status.Conditions {
+		if condition.Type == batchv1.JobFailed && condition.Status == corev1.ConditionTrue {
+			endTime := condition.LastTransitionTime.Time
+			s.span.AddEvent("failed", trace.WithTimestamp(endTime))
+			s.span.SetStatus(codes.Error, condition.Reason)
+			s.span.End(trace.WithTimestamp(endTime))
+			delete(r.jobSpans, job.Name)
+			return
+		}
+	}
+}
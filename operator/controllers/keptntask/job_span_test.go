@@ -0,0 +1,99 @@
+package keptntask
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+	klcv1alpha1 "github.com/keptn/lifecycle-controller/operator/api/v1alpha1"
+	"github.com/keptn/lifecycle-controller/operator/api/v1alpha1/common"
+	"github.com/keptn/lifecycle-controller/operator/api/v1alpha1/semconv"
+	testrequire "github.com/stretchr/testify/require"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestKeptnTaskReconciler_JobSpan(t *testing.T) {
+	spanRecorder := tracetest.NewSpanRecorder()
+	tracer := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(spanRecorder)).Tracer("test")
+
+	phaseCtx, phaseSpan := tracer.Start(context.TODO(), "WorkloadPreDeployTasks")
+	phaseSpan.End()
+
+	created := time.Now().Add(-time.Minute).Truncate(time.Second)
+	started := created.Add(20 * time.Second)
+	finished := started.Add(10 * time.Second)
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "klc-task-12345-abcde", Namespace: "default", Labels: map[string]string{"job-name": "klc-task-12345"}},
+		Status: corev1.PodStatus{ContainerStatuses: []corev1.ContainerStatus{
+			{State: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{StartedAt: metav1.NewTime(started)}}},
+		}},
+	}
+	r := &KeptnTaskReconciler{
+		Client: fake.NewClientBuilder().WithObjects(pod).Build(),
+		Log:    logr.Discard(),
+		Tracer: tracer,
+	}
+	task := &klcv1alpha1.KeptnTask{
+		ObjectMeta: metav1.ObjectMeta{Name: "task", Namespace: "default", Annotations: map[string]string{
+			common.PhaseTraceParentAnnotation: semconv.TraceParent(phaseCtx),
+		}},
+	}
+	job := &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{Name: "klc-task-12345", Namespace: "default", CreationTimestamp: metav1.NewTime(created)},
+		Status:     batchv1.JobStatus{Succeeded: 1, CompletionTime: &metav1.Time{Time: finished}},
+	}
+
+	r.updateJobSpan(context.TODO(), task, job)
+
+	spans := spanRecorder.Ended()
+	testrequire.Len(t, spans, 2)
+	jobSpan := spans[1]
+	testrequire.Equal(t, semconv.JobSpanName, jobSpan.Name())
+	testrequire.Equal(t, created, jobSpan.StartTime())
+	testrequire.Equal(t, finished, jobSpan.EndTime())
+	testrequire.Len(t, jobSpan.Links(), 1)
+	testrequire.Equal(t, phaseSpan.SpanContext().SpanID(), jobSpan.Links()[0].SpanContext.SpanID())
+
+	events := jobSpan.Events()
+	testrequire.Len(t, events, 3)
+	testrequire.Equal(t, "running", events[1].Name)
+	testrequire.Equal(t, started, events[1].Time)
+	testrequire.Empty(t, r.jobSpans)
+}
+
+func TestKeptnTaskReconciler_JobSpanCleanup(t *testing.T) {
+	spanRecorder := tracetest.NewSpanRecorder()
+	tracer := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(spanRecorder)).Tracer("test")
+
+	r := &KeptnTaskReconciler{
+		Client: fake.NewClientBuilder().Build(),
+		Log:    logr.Discard(),
+		Tracer: tracer,
+	}
+	failedTask := &klcv1alpha1.KeptnTask{ObjectMeta: metav1.ObjectMeta{Name: "failed-task", Namespace: "default"}}
+	deletedTask := &klcv1alpha1.KeptnTask{ObjectMeta: metav1.ObjectMeta{Name: "deleted-task", Namespace: "default"}}
+
+	_, span := r.startJobSpan(context.TODO(), deletedTask, time.Now())
+	r.bindJobSpan(deletedTask, "klc-deleted-task-12345", span)
+
+	failed := &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{Name: "klc-failed-task-12345", Namespace: "default", CreationTimestamp: metav1.Now()},
+		Status: batchv1.JobStatus{Conditions: []batchv1.JobCondition{
+			{Type: batchv1.JobFailed, Status: corev1.ConditionTrue, Reason: "BackoffLimitExceeded", LastTransitionTime: metav1.Now()},
+		}},
+	}
+	r.updateJobSpan(context.TODO(), failedTask, failed)
+	testrequire.Len(t, r.jobSpans, 1)
+	testrequire.Len(t, spanRecorder.Ended(), 1)
+
+	r.unbindJobSpans(types.NamespacedName{Namespace: "default", Name: "deleted-task"})
+	testrequire.Empty(t, r.jobSpans)
+	testrequire.Len(t, spanRecorder.Ended(), 2)
+}
@@ -4,12 +4,19 @@ import (
 	"context"
 	"fmt"
 	"reflect"
+	"time"
 
 	"github.com/keptn/lifecycle-controller/operator/api/v1alpha1/common"
 
 	"github.com/imdario/mergo"
 	klcv1alpha1 "github.com/keptn/lifecycle-controller/operator/api/v1alpha1"
+	"github.com/keptn/lifecycle-controller/operator/api/v1alpha1/semconv"
+	"github.com/keptn/lifecycle-controller/operator/controllers/apply"
+	"github.com/keptn/lifecycle-controller/operator/controllers/reasons"
+	"go.opentelemetry.io/otel/baggage"
+	"go.opentelemetry.io/otel/codes"
 	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/types"
 	ctrl "sigs.k8s.io/controller-runtime"
 )
@@ -18,11 +25,16 @@ func (r *KeptnTaskReconciler) createJob(ctx context.Context, req ctrl.Request, t
 	jobName := ""
 	definition, err := r.getTaskDefinition(ctx, task.Spec.TaskDefinition, req.Namespace)
 	if err != nil {
-		r.Recorder.Event(task, "Warning", "TaskDefinitionNotFound", fmt.Sprintf("Could not find KeptnTaskDefinition / Namespace: %s, Name: %s ", task.Namespace, task.Spec.TaskDefinition))
+		r.recordEvent(task, reasons.TaskDefinitionNotFound)
 		return err
 	}
 
-	if !reflect.DeepEqual(definition.Spec.Function, klcv1alpha1.FunctionSpec{}) {
+	if definition.Spec.Container != nil {
+		jobName, err = r.createContainerJob(ctx, task, definition)
+		if err != nil {
+			return err
+		}
+	} else if function, _ := Function(definition); !reflect.DeepEqual(function, klcv1alpha1.FunctionSpec{}) {
 		jobName, err = r.createFunctionJob(ctx, req, task, definition)
 		if err != nil {
 			return err
@@ -30,7 +42,11 @@ func (r *KeptnTaskReconciler) createJob(ctx context.Context, req ctrl.Request, t
 	}
 
 	task.Status.JobName = jobName
+	if jobName != "" {
+		task.Status.Attempts++
+	}
 	task.Status.Status = common.StatePending
+	task.Status.Reason = ""
 	err = r.Client.Status().Update(ctx, task)
 	if err != nil {
 		r.Log.Error(err, "could not update KeptnTask status reference for: "+task.Name)
@@ -46,22 +62,44 @@ func (r *KeptnTaskReconciler) createFunctionJob(ctx context.Context, req ctrl.Re
 		return "", err
 	}
 	if hasParent {
-		parentDefinition, err := r.getTaskDefinition(ctx, definition.Spec.Function.FunctionReference.Name, req.Namespace)
+		function, _ := Function(definition)
+		parentDefinition, err := r.getTaskDefinition(ctx, function.FunctionReference.Name, req.Namespace)
 		if err != nil {
-			r.Recorder.Event(task, "Warning", "TaskDefinitionNotFound", fmt.Sprintf("Could not find KeptnTaskDefinition / Namespace: %s, Name: %s ", task.Namespace, task.Spec.TaskDefinition))
+			r.recordEvent(task, reasons.TaskDefinitionNotFound)
 			return "", err
 		}
 		parentJobParams, _, err = r.parseFunctionTaskDefinition(parentDefinition)
 		if err != nil {
 			return "", err
 		}
+		// the code of the parent is executed by its runtime, unless the task definition has its own code
+		if params.ConfigMap == "" && params.URL == "" {
+			params.Runtime = ""
+		}
 		err = mergo.Merge(&params, parentJobParams)
 		if err != nil {
-			r.Recorder.Event(task, "Warning", "TaskDefinitionMergeFailure", fmt.Sprintf("Could not merge KeptnTaskDefinition / Namespace: %s, Name: %s ", task.Namespace, task.Spec.TaskDefinition))
+			r.recordEvent(task, reasons.TaskDefinitionMergeFailure, task.Spec.TaskDefinition)
 			return "", err
 		}
 	}
 
+	if err := r.setTaskParams(task, &params); err != nil {
+		return "", err
+	}
+
+	namespace := &corev1.Namespace{}
+	if err := r.Client.Get(ctx, types.NamespacedName{Name: task.Namespace}, namespace); err != nil {
+		return "", fmt.Errorf("could not retrieve namespace %s: %w", task.Namespace, err)
+	}
+	params.Runner = params.Runtime.RunnerImage(params.Runner, namespace.Annotations)
+	params.Retries, _ = retryPolicy(task, definition)
+	params.Timeout = taskTimeout(task, definition)
+
+	return r.startJob(ctx, task, params, r.generateFunctionJob)
+}
+
+// setTaskParams adds the context, the parameters and the deadline of the task to the parameters of its execution
+func (r *KeptnTaskReconciler) setTaskParams(task *klcv1alpha1.KeptnTask, params *FunctionExecutionParams) error {
 	taskContext := klcv1alpha1.TaskContext{}
 
 	if task.Spec.Workload != "" {
@@ -78,52 +116,97 @@ func (r *KeptnTaskReconciler) createFunctionJob(ctx context.Context, req ctrl.Re
 	params.Context = taskContext
 
 	if len(task.Spec.Parameters.Inline) > 0 {
-		err = mergo.Merge(&params.Parameters, task.Spec.Parameters.Inline)
+		err := mergo.Merge(&params.Parameters, task.Spec.Parameters.Inline)
 		if err != nil {
-			r.Recorder.Event(task, "Warning", "TaskDefinitionMergeFailure", fmt.Sprintf("Could not merge KeptnTaskDefinition / Namespace: %s, Name: %s ", task.Namespace, task.Spec.TaskDefinition))
-			return "", err
+			r.recordEvent(task, reasons.TaskDefinitionMergeFailure, task.Spec.TaskDefinition)
+			return err
 		}
 	}
 
 	if task.Spec.SecureParameters.Secret != "" {
 		params.SecureParameters = task.Spec.SecureParameters.Secret
 	}
+	params.Deadline = task.Spec.Deadline
+	return nil
+}
+
+// startJob creates the Job generated for the task, whose execution is recorded in a span passed on to the Job
+func (r *KeptnTaskReconciler) startJob(ctx context.Context, task *klcv1alpha1.KeptnTask, params FunctionExecutionParams, generate func(*klcv1alpha1.KeptnTask, FunctionExecutionParams) (*batchv1.Job, error)) (string, error) {
+	ctxJob, jobSpan := r.startJobSpan(ctx, task, time.Now())
+	params.TraceParent = semconv.TraceParent(ctxJob)
+	params.Baggage = baggage.FromContext(ctxJob).String()
 
-	job, err := r.generateFunctionJob(task, params)
+	job, err := generate(task, params)
 	if err != nil {
+		jobSpan.End()
 		return "", err
 	}
-	err = r.Client.Create(ctx, job)
+	err = apply.Apply(ctx, r.Client, job, r.Recorder, task)
 	if err != nil {
+		jobSpan.SetStatus(codes.Error, err.Error())
+		jobSpan.End()
 		r.Log.Error(err, "could not create job")
-		r.Recorder.Event(task, "Warning", "JobNotCreated", fmt.Sprintf("Could not create Job / Namespace: %s, Name: %s ", task.Namespace, task.Name))
+		r.recordEvent(task, reasons.JobNotCreated)
 		return job.Name, err
 	}
 
-	r.Recorder.Event(task, "Normal", "JobCreated", fmt.Sprintf("Created Job / Namespace: %s, Name: %s ", task.Namespace, task.Name))
+	r.bindJobSpan(task, job.Name, jobSpan)
+	r.recordEvent(task, reasons.JobCreated, job.Name)
 	return job.Name, nil
 }
 
 func (r *KeptnTaskReconciler) updateJob(ctx context.Context, req ctrl.Request, task *klcv1alpha1.KeptnTask) error {
 	job, err := r.getJob(ctx, task.Status.JobName, req.Namespace)
 	if err != nil {
+		r.recordEvent(task, reasons.JobReferenceRemoved, task.Status.JobName)
 		task.Status.JobName = ""
-		r.Recorder.Event(task, "Warning", "JobReferenceRemoved", fmt.Sprintf("Removed Job Reference as Job could not be found / Namespace: %s, TaskName: %s ", task.Namespace, task.Name))
 		err = r.Client.Status().Update(ctx, task)
 		if err != nil {
 			r.Log.Error(err, "could not remove job reference for: "+task.Name)
 		}
 		return err
 	}
+	r.updateJobSpan(ctx, task, job)
 	if job.Status.Succeeded > 0 {
 		task.Status.Status = common.StateSucceeded
 		err = r.Client.Status().Update(ctx, task)
 		if err != nil {
 			r.Log.Error(err, "could not update job status for: "+task.Name)
 		}
+		return nil
 	}
-	return nil
+	now := time.Now()
+	failedAt, failed := jobFailed(job)
+	if !failed {
+		return nil
+	}
+	// a Job terminated before the deadline of the task has passed has exceeded the timeout of the task
+	timedOut := task.Spec.Deadline == nil || now.Before(task.Spec.Deadline.Time)
+	if jobDeadlineExceeded(job) && !timedOut {
+		task.Status.Status = common.StateFailed
+		r.recordEvent(task, reasons.DeadlineExceeded)
+		err = r.Client.Status().Update(ctx, task)
+		if err != nil {
+			r.Log.Error(err, "could not update job status for: "+task.Name)
+		}
+		return nil
+	}
+	if jobDeadlineExceeded(job) {
+		r.recordEvent(task, reasons.TimeoutExceeded)
+	}
+	return r.retryJob(ctx, req, task, failedAt, now)
 }
+
+// jobDeadlineExceeded returns whether the job has been terminated by kubernetes because of its active deadline
+func jobDeadlineExceeded(job *batchv1.Job) bool {
+	for _, condition := range job.Status.Conditions {
+		if condition.Type == batchv1.JobFailed && condition.Status == corev1.ConditionTrue && condition.Reason == "DeadlineExceeded" {
+			return true
+		}
+	}
+	return false
+}
+
 func (r *KeptnTaskReconciler) getJob(ctx context.Context, jobName string, namespace string) (*batchv1.Job, error) {
 	job := &batchv1.Job{}
 	err := r.Client.Get(ctx, types.NamespacedName{Name: jobName, Namespace: namespace}, job)
@@ -0,0 +1,207 @@
+package keptntask
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	klcv1alpha1 "github.com/keptn/lifecycle-controller/operator/api/v1alpha1"
+	"github.com/keptn/lifecycle-controller/operator/api/v1alpha1/common"
+	"github.com/keptn/lifecycle-controller/operator/controllers/reasons"
+	batchv1 "k8s.io/api/batch/v1"
+	coordinationv1 "k8s.io/api/coordination/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+// leaseDuration is the time after which the Lease of an exactly-once task can be taken over from a replica which has
+// not released it, e.g. because it has crashed while creating the Job
+const leaseDuration = 30 * time.Second
+
+// lockedJobReason is the reason of an exactly-once task which has been failed since its Job has been lost
+const lockedJobReason = "the Job of the exactly-once task has been lost, it is not started again"
+
+// leaseName returns the name of the Lease of the task
+func leaseName(task *klcv1alpha1.KeptnTask) string {
+	return common.CreateResourceName(common.MaxK8sObjectLength, "klc-task", task.Name)
+}
+
+// reader returns the reader used to look up Leases and Jobs of exactly-once tasks, which bypasses the cache so that
+// the Lease and the Jobs created by other replicas are seen immediately
+func (r *KeptnTaskReconciler) reader() client.Reader {
+	if r.APIReader != nil {
+		return r.APIReader
+	}
+	return r.Client
+}
+
+// lockJob acquires the Lease of an exactly-once task before its Job is created and records the state of the Lease in
+// the status of the task. It returns false if another replica holds the Lease, if a Job of the task has been created
+// already, which the task adopts, or if the Job of the task has been lost, in which case the task fails.
+func (r *KeptnTaskReconciler) lockJob(ctx context.Context, task *klcv1alpha1.KeptnTask, now time.Time) (bool, error) {
+	lease, err := r.acquireLease(ctx, task, now)
+	if err != nil {
+		return false, err
+	}
+	if lease.Spec.HolderIdentity == nil || *lease.Spec.HolderIdentity != r.Identity {
+		holder := ""
+		if lease.Spec.HolderIdentity != nil {
+			holder = *lease.Spec.HolderIdentity
+		}
+		if r.setLockState(task, klcv1alpha1.TaskLockWaiting, holder, now) {
+			return false, r.updateStatus(ctx, task)
+		}
+		return false, nil
+	}
+	r.setLockState(task, klcv1alpha1.TaskLockAcquired, r.Identity, now)
+
+	// the status of the task might not contain the Job another replica has created yet
+	job, err := r.findJob(ctx, task)
+	if err != nil {
+		return false, err
+	}
+	switch {
+	case job != nil:
+		task.Status.JobName = job.Name
+		if task.Status.Attempts < 1 {
+			task.Status.Attempts = 1
+		}
+		r.recordEvent(task, reasons.JobAdopted, job.Name)
+	case task.Status.Attempts > 0:
+		task.Status.Status = common.StateFailed
+		task.Status.Reason = lockedJobReason
+		r.recordEvent(task, reasons.JobLost)
+	default:
+		return true, nil
+	}
+	return false, r.unlockJob(ctx, task, now)
+}
+
+// unlockJob releases the Lease of an exactly-once task once its Job has been created
+func (r *KeptnTaskReconciler) unlockJob(ctx context.Context, task *klcv1alpha1.KeptnTask, now time.Time) error {
+	lease := &coordinationv1.Lease{}
+	err := r.reader().Get(ctx, types.NamespacedName{Name: leaseName(task), Namespace: task.Namespace}, lease)
+	if err != nil && !errors.IsNotFound(err) {
+		return fmt.Errorf("could not retrieve Lease of KeptnTask %s: %w", task.Name, err)
+	}
+	if err == nil && lease.Spec.HolderIdentity != nil && *lease.Spec.HolderIdentity == r.Identity {
+		lease.Spec.HolderIdentity = nil
+		if err := r.Client.Update(ctx, lease); err != nil && !errors.IsConflict(err) {
+			return fmt.Errorf("could not release Lease of KeptnTask %s: %w", task.Name, err)
+		}
+	}
+	r.setLockState(task, klcv1alpha1.TaskLockReleased, r.Identity, now)
+	return r.updateStatus(ctx, task)
+}
+
+// acquireLease creates the Lease of the task, or takes it over if it has been released or has expired, and returns it.
+// The Lease is held by another replica if its holder differs from the identity of the reconciler.
+func (r *KeptnTaskReconciler) acquireLease(ctx context.Context, task *klcv1alpha1.KeptnTask, now time.Time) (*coordinationv1.Lease, error) {
+	lease := &coordinationv1.Lease{}
+	err := r.reader().Get(ctx, types.NamespacedName{Name: leaseName(task), Namespace: task.Namespace}, lease)
+	if errors.IsNotFound(err) {
+		lease = r.newLease(task, now)
+		if err := controllerutil.SetControllerReference(task, lease, r.Scheme); err != nil {
+			return nil, fmt.Errorf("could not set owner of Lease of KeptnTask %s: %w", task.Name, err)
+		}
+		err = r.Client.Create(ctx, lease)
+		if errors.IsAlreadyExists(err) {
+			// another replica has created the Lease in the meantime
+			return r.acquireLease(ctx, task, now)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("could not create Lease of KeptnTask %s: %w", task.Name, err)
+		}
+		return lease, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("could not retrieve Lease of KeptnTask %s: %w", task.Name, err)
+	}
+
+	holder := lease.Spec.HolderIdentity
+	if holder != nil && *holder != "" && *holder != r.Identity && !leaseExpired(lease, now) {
+		return lease, nil
+	}
+	if holder == nil || *holder != r.Identity {
+		transitions := int32(1)
+		if lease.Spec.LeaseTransitions != nil {
+			transitions = *lease.Spec.LeaseTransitions + 1
+		}
+		lease.Spec.LeaseTransitions = &transitions
+		lease.Spec.AcquireTime = &metav1.MicroTime{Time: now}
+	}
+	identity, seconds := r.Identity, int32(leaseDuration.Seconds())
+	lease.Spec.HolderIdentity = &identity
+	lease.Spec.LeaseDurationSeconds = &seconds
+	lease.Spec.RenewTime = &metav1.MicroTime{Time: now}
+	// the update fails with a conflict if another replica has taken over the Lease since it has been read
+	if err := r.Client.Update(ctx, lease); errors.IsConflict(err) {
+		return r.acquireLease(ctx, task, now)
+	} else if err != nil {
+		return nil, fmt.Errorf("could not acquire Lease of KeptnTask %s: %w", task.Name, err)
+	}
+	return lease, nil
+}
+
+func (r *KeptnTaskReconciler) newLease(task *klcv1alpha1.KeptnTask, now time.Time) *coordinationv1.Lease {
+	identity, seconds := r.Identity, int32(leaseDuration.Seconds())
+	return &coordinationv1.Lease{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      leaseName(task),
+			Namespace: task.Namespace,
+			Labels:    createKeptnLabels(*task),
+		},
+		Spec: coordinationv1.LeaseSpec{
+			HolderIdentity:       &identity,
+			LeaseDurationSeconds: &seconds,
+			AcquireTime:          &metav1.MicroTime{Time: now},
+			RenewTime:            &metav1.MicroTime{Time: now},
+		},
+	}
+}
+
+// leaseExpired returns whether the holder of the Lease has not renewed it within its duration
+func leaseExpired(lease *coordinationv1.Lease, now time.Time) bool {
+	if lease.Spec.RenewTime == nil || lease.Spec.LeaseDurationSeconds == nil {
+		return true
+	}
+	expiry := lease.Spec.RenewTime.Add(time.Duration(*lease.Spec.LeaseDurationSeconds) * time.Second)
+	return !now.Before(expiry)
+}
+
+// findJob returns a Job of the task from the API server, or nil if there is none
+func (r *KeptnTaskReconciler) findJob(ctx context.Context, task *klcv1alpha1.KeptnTask) (*batchv1.Job, error) {
+	jobList := &batchv1.JobList{}
+	if err := r.reader().List(ctx, jobList, client.InNamespace(task.Namespace), client.MatchingLabels(createKeptnLabels(*task))); err != nil {
+		return nil, fmt.Errorf("could not list Jobs of KeptnTask %s: %w", task.Name, err)
+	}
+	if len(jobList.Items) == 0 {
+		return nil, nil
+	}
+	return &jobList.Items[0], nil
+}
+
+// setLockState records the state of the Lease in the status of the task and returns whether it has changed
+func (r *KeptnTaskReconciler) setLockState(task *klcv1alpha1.KeptnTask, state klcv1alpha1.TaskLockState, holder string, now time.Time) bool {
+	lock := task.Status.Lock
+	if lock != nil && lock.State == state && lock.Holder == holder {
+		return false
+	}
+	task.Status.Lock = &klcv1alpha1.TaskLockStatus{
+		LeaseName:      leaseName(task),
+		Holder:         holder,
+		State:          state,
+		TransitionTime: metav1.NewTime(now.UTC()),
+	}
+	return true
+}
+
+func (r *KeptnTaskReconciler) updateStatus(ctx context.Context, task *klcv1alpha1.KeptnTask) error {
+	if err := r.Client.Status().Update(ctx, task); err != nil {
+		return fmt.Errorf("could not update status of KeptnTask %s: %w", task.Name, err)
+	}
+	return nil
+}
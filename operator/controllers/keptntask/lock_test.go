@@ -0,0 +1,99 @@
+package keptntask
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+	klcv1alpha1 "github.com/keptn/lifecycle-controller/operator/api/v1alpha1"
+	"github.com/keptn/lifecycle-controller/operator/api/v1alpha1/common"
+	testrequire "github.com/stretchr/testify/require"
+	batchv1 "k8s.io/api/batch/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestKeptnTaskReconciler_acquireLease(t *testing.T) {
+	scheme := runtime.NewScheme()
+	testrequire.Nil(t, clientgoscheme.AddToScheme(scheme))
+	testrequire.Nil(t, klcv1alpha1.AddToScheme(scheme))
+
+	task := &klcv1alpha1.KeptnTask{
+		ObjectMeta: metav1.ObjectMeta{Name: "migration-schema-1", Namespace: "default", UID: "uid"},
+		Spec:       klcv1alpha1.KeptnTaskSpec{AppName: "app", Workload: "app-db", WorkloadVersion: "1.0", ExactlyOnce: true},
+	}
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(task).Build()
+	first := &KeptnTaskReconciler{Client: c, Scheme: scheme, Log: logr.Discard(), Recorder: record.NewFakeRecorder(10), Identity: "first"}
+	second := &KeptnTaskReconciler{Client: c, Scheme: scheme, Log: logr.Discard(), Recorder: record.NewFakeRecorder(10), Identity: "second"}
+	now := time.Date(2022, 11, 8, 12, 0, 0, 0, time.UTC)
+
+	// the first replica creates the lease
+	lease, err := first.acquireLease(context.TODO(), task, now)
+	testrequire.Nil(t, err)
+	testrequire.Equal(t, "first", *lease.Spec.HolderIdentity)
+	testrequire.Equal(t, "klc-task-migration-schema-1", lease.Name)
+	testrequire.Len(t, lease.OwnerReferences, 1)
+
+	// the second replica waits while the lease is held
+	lease, err = second.acquireLease(context.TODO(), task, now.Add(leaseDuration/2))
+	testrequire.Nil(t, err)
+	testrequire.Equal(t, "first", *lease.Spec.HolderIdentity)
+
+	// the holder renews its lease
+	lease, err = first.acquireLease(context.TODO(), task, now.Add(leaseDuration/2))
+	testrequire.Nil(t, err)
+	testrequire.Equal(t, "first", *lease.Spec.HolderIdentity)
+	testrequire.Nil(t, lease.Spec.LeaseTransitions)
+
+	// the second replica takes over the lease once it has expired
+	lease, err = second.acquireLease(context.TODO(), task, now.Add(2*leaseDuration))
+	testrequire.Nil(t, err)
+	testrequire.Equal(t, "second", *lease.Spec.HolderIdentity)
+	testrequire.Equal(t, int32(1), *lease.Spec.LeaseTransitions)
+
+	lease, err = first.acquireLease(context.TODO(), task, now.Add(2*leaseDuration))
+	testrequire.Nil(t, err)
+	testrequire.Equal(t, "second", *lease.Spec.HolderIdentity)
+}
+
+func TestKeptnTaskReconciler_findJob(t *testing.T) {
+	scheme := runtime.NewScheme()
+	testrequire.Nil(t, clientgoscheme.AddToScheme(scheme))
+	testrequire.Nil(t, klcv1alpha1.AddToScheme(scheme))
+
+	task := &klcv1alpha1.KeptnTask{
+		ObjectMeta: metav1.ObjectMeta{Name: "migration-schema-1", Namespace: "default"},
+		Spec:       klcv1alpha1.KeptnTaskSpec{AppName: "app", Workload: "app-db", WorkloadVersion: "1.0", ExactlyOnce: true},
+	}
+	other := &batchv1.Job{ObjectMeta: metav1.ObjectMeta{Name: "other", Namespace: "default", Labels: map[string]string{common.TaskNameAnnotation: "other"}}}
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(task, other).Build()
+	r := &KeptnTaskReconciler{Client: c, Scheme: scheme, Log: logr.Discard(), Identity: "first"}
+
+	job, err := r.findJob(context.TODO(), task)
+	testrequire.Nil(t, err)
+	testrequire.Nil(t, job)
+
+	// a Job created by another replica is found although the task does not reference it
+	created := &batchv1.Job{ObjectMeta: metav1.ObjectMeta{Name: "klc-migration-schema-1-12345", Namespace: "default", Labels: createKeptnLabels(*task)}}
+	testrequire.Nil(t, c.Create(context.TODO(), created))
+	job, err = r.findJob(context.TODO(), task)
+	testrequire.Nil(t, err)
+	testrequire.Equal(t, created.Name, job.Name)
+}
+
+func TestLeaseExpired(t *testing.T) {
+	now := time.Date(2022, 11, 8, 12, 0, 0, 0, time.UTC)
+	r := &KeptnTaskReconciler{Identity: "first"}
+	lease := r.newLease(&klcv1alpha1.KeptnTask{ObjectMeta: metav1.ObjectMeta{Name: "task"}}, now)
+
+	testrequire.False(t, leaseExpired(lease, now))
+	testrequire.False(t, leaseExpired(lease, now.Add(leaseDuration-time.Second)))
+	testrequire.True(t, leaseExpired(lease, now.Add(leaseDuration)))
+
+	lease.Spec.RenewTime = nil
+	testrequire.True(t, leaseExpired(lease, now))
+}
@@ -0,0 +1,52 @@
+package keptntask
+
+import (
+	"context"
+	"time"
+
+	klcv1alpha1 "github.com/keptn/lifecycle-controller/operator/api/v1alpha1"
+	"github.com/keptn/lifecycle-controller/operator/api/v1alpha1/common"
+	"github.com/keptn/lifecycle-controller/operator/controllers/reasons"
+)
+
+// getWaitDuration returns the duration of the task and true if the task is defined by a task definition of type wait.
+// Tasks which already have a Job are never wait tasks.
+func (r *KeptnTaskReconciler) getWaitDuration(ctx context.Context, task *klcv1alpha1.KeptnTask) (time.Duration, bool) {
+	if task.Status.JobName != "" {
+		return 0, false
+	}
+	definition, err := r.getTaskDefinition(ctx, task.Spec.TaskDefinition, task.Namespace)
+	if err != nil || definition.Spec.Type != klcv1alpha1.TaskTypeWait {
+		return 0, false
+	}
+	if definition.Spec.Duration == nil {
+		return 0, true
+	}
+	return definition.Spec.Duration.Duration, true
+}
+
+// reconcileWait completes a task of type wait once its duration has passed since the start of the task, or fails it if
+// its deadline passes before. It returns the time until the task has to be reconciled again, zero if it is completed.
+func (r *KeptnTaskReconciler) reconcileWait(task *klcv1alpha1.KeptnTask, duration time.Duration, now time.Time) time.Duration {
+	if task.Status.Status.IsCompleted() {
+		return 0
+	}
+	end := task.Status.StartTime.Add(duration)
+	if task.Spec.Deadline != nil && task.Spec.Deadline.Time.Before(end) {
+		if !now.Before(task.Spec.Deadline.Time) {
+			task.Status.Status = common.StateFailed
+			r.recordEvent(task, reasons.DeadlineExceeded)
+			return 0
+		}
+		end = task.Spec.Deadline.Time
+	}
+	if now.Before(end) {
+		if task.Status.Status != common.StateProgressing {
+			task.Status.Status = common.StateProgressing
+			r.recordEvent(task, reasons.WaitStarted, duration)
+		}
+		return end.Sub(now)
+	}
+	task.Status.Status = common.StateSucceeded
+	return 0
+}
@@ -0,0 +1,80 @@
+package keptntask
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+	klcv1alpha1 "github.com/keptn/lifecycle-controller/operator/api/v1alpha1"
+	"github.com/keptn/lifecycle-controller/operator/api/v1alpha1/common"
+	testrequire "github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestKeptnTaskReconciler_getWaitDuration(t *testing.T) {
+	scheme := runtime.NewScheme()
+	testrequire.Nil(t, klcv1alpha1.AddToScheme(scheme))
+	r := &KeptnTaskReconciler{
+		Client: fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+			&klcv1alpha1.KeptnTaskDefinition{
+				ObjectMeta: metav1.ObjectMeta{Name: "soak", Namespace: "default"},
+				Spec:       klcv1alpha1.KeptnTaskDefinitionSpec{Type: klcv1alpha1.TaskTypeWait, Duration: &metav1.Duration{Duration: 5 * time.Minute}},
+			},
+			&klcv1alpha1.KeptnTaskDefinition{
+				ObjectMeta: metav1.ObjectMeta{Name: "notify", Namespace: "default"},
+				Spec:       klcv1alpha1.KeptnTaskDefinitionSpec{Function: klcv1alpha1.FunctionSpec{HttpReference: klcv1alpha1.HttpReference{Url: "https://example.com/notify.ts"}}},
+			},
+		).Build(),
+		Log: logr.Discard(),
+	}
+
+	task := &klcv1alpha1.KeptnTask{
+		ObjectMeta: metav1.ObjectMeta{Name: "task", Namespace: "default"},
+		Spec:       klcv1alpha1.KeptnTaskSpec{TaskDefinition: "soak"},
+	}
+	duration, isWaitTask := r.getWaitDuration(context.TODO(), task)
+	testrequire.True(t, isWaitTask)
+	testrequire.Equal(t, 5*time.Minute, duration)
+
+	task.Spec.TaskDefinition = "notify"
+	_, isWaitTask = r.getWaitDuration(context.TODO(), task)
+	testrequire.False(t, isWaitTask)
+
+	task.Spec.TaskDefinition = "missing"
+	_, isWaitTask = r.getWaitDuration(context.TODO(), task)
+	testrequire.False(t, isWaitTask)
+}
+
+func TestKeptnTaskReconciler_reconcileWait(t *testing.T) {
+	started := time.Date(2022, 11, 8, 12, 0, 0, 0, time.UTC)
+	deadline := metav1.NewTime(started.Add(3 * time.Minute))
+	tests := []struct {
+		name          string
+		now           time.Time
+		deadline      *metav1.Time
+		wantStatus    common.KeptnState
+		wantRemaining time.Duration
+	}{
+		{"waiting", started.Add(time.Minute), nil, common.StateProgressing, 4 * time.Minute},
+		{"done", started.Add(5 * time.Minute), nil, common.StateSucceeded, 0},
+		{"waiting until deadline", started.Add(time.Minute), &deadline, common.StateProgressing, 2 * time.Minute},
+		{"deadline exceeded", started.Add(3 * time.Minute), &deadline, common.StateFailed, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &KeptnTaskReconciler{Recorder: record.NewFakeRecorder(10), Log: logr.Discard()}
+			task := &klcv1alpha1.KeptnTask{
+				Spec:   klcv1alpha1.KeptnTaskSpec{Deadline: tt.deadline},
+				Status: klcv1alpha1.KeptnTaskStatus{Status: common.StatePending, StartTime: metav1.NewTime(started)},
+			}
+
+			remaining := r.reconcileWait(task, 5*time.Minute, tt.now)
+			testrequire.Equal(t, tt.wantStatus, task.Status.Status)
+			testrequire.Equal(t, tt.wantRemaining, remaining)
+		})
+	}
+}
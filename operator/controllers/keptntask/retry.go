@@ -0,0 +1,108 @@
+package keptntask
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	klcv1alpha1 "github.com/keptn/lifecycle-controller/operator/api/v1alpha1"
+	"github.com/keptn/lifecycle-controller/operator/api/v1alpha1/common"
+	"github.com/keptn/lifecycle-controller/operator/controllers/reasons"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+const (
+	// defaultRetryBackoff is the delay before the first retry of a failed Job if neither the task nor its definition set one
+	defaultRetryBackoff = 10 * time.Second
+	// maxRetryDelay limits the delay between two attempts of a task
+	maxRetryDelay = time.Hour
+)
+
+// retryPolicy returns the number of retries of a failed Job of the task and the delay before the first retry.
+// The values of the task take precedence over the ones of its definition. Exactly-once tasks are never retried.
+func retryPolicy(task *klcv1alpha1.KeptnTask, definition *klcv1alpha1.KeptnTaskDefinition) (int32, time.Duration) {
+	var retries int32
+	backoff := defaultRetryBackoff
+	if definition.Spec.Retries != nil {
+		retries = *definition.Spec.Retries
+	}
+	if task.Spec.Retries != nil {
+		retries = *task.Spec.Retries
+	}
+	if task.Spec.ExactlyOnce {
+		retries = 0
+	}
+	if definition.Spec.Backoff != nil {
+		backoff = definition.Spec.Backoff.Duration
+	}
+	if task.Spec.Backoff != nil {
+		backoff = task.Spec.Backoff.Duration
+	}
+	return retries, backoff
+}
+
+// retryDelay returns the delay before the next attempt of a task after the given number of failed attempts,
+// which doubles the backoff with each attempt up to maxRetryDelay
+func retryDelay(backoff time.Duration, attempts int32) time.Duration {
+	delay := backoff
+	for i := int32(1); i < attempts && delay < maxRetryDelay; i++ {
+		delay *= 2
+	}
+	if delay > maxRetryDelay {
+		return maxRetryDelay
+	}
+	return delay
+}
+
+// jobFailed returns the time the Job has failed at, and false if it has not failed
+func jobFailed(job *batchv1.Job) (time.Time, bool) {
+	for _, condition := range job.Status.Conditions {
+		if condition.Type == batchv1.JobFailed && condition.Status == corev1.ConditionTrue {
+			return condition.LastTransitionTime.Time, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// retryJob starts a new Job for a task whose Job has failed once the backoff has passed, or fails the task if it has
+// no retries left or the next attempt would start after its deadline
+func (r *KeptnTaskReconciler) retryJob(ctx context.Context, req ctrl.Request, task *klcv1alpha1.KeptnTask, failedAt time.Time, now time.Time) error {
+	definition, err := r.getTaskDefinition(ctx, task.Spec.TaskDefinition, req.Namespace)
+	if err != nil {
+		return err
+	}
+	retries, backoff := retryPolicy(task, definition)
+	// tasks created before attempts have been counted have started one Job
+	if task.Status.Attempts < 1 {
+		task.Status.Attempts = 1
+	}
+
+	retryAt := failedAt.Add(retryDelay(backoff, task.Status.Attempts))
+	if task.Status.Attempts > retries || (task.Spec.Deadline != nil && !retryAt.Before(task.Spec.Deadline.Time)) {
+		task.Status.Status = common.StateFailed
+		task.Status.Reason = ""
+		r.recordEvent(task, reasons.JobFailed, task.Status.Attempts)
+		if err := r.Client.Status().Update(ctx, task); err != nil {
+			return fmt.Errorf("could not update status of KeptnTask %s: %w", task.Name, err)
+		}
+		return nil
+	}
+
+	if now.Before(retryAt) {
+		reason := fmt.Sprintf("Job %s has failed, attempt %d of %d starts at %s", task.Status.JobName, task.Status.Attempts+1, retries+1, retryAt.UTC().Format(time.RFC3339))
+		if task.Status.Reason != reason {
+			task.Status.Reason = reason
+			if err := r.Client.Status().Update(ctx, task); err != nil {
+				return fmt.Errorf("could not update status of KeptnTask %s: %w", task.Name, err)
+			}
+		}
+		return nil
+	}
+	if throttled, err := r.throttleJob(ctx, task); err != nil || throttled {
+		return err
+	}
+	r.recordEvent(task, reasons.JobRetried, task.Status.Attempts+1, retries+1, task.Status.JobName)
+	return r.createJob(ctx, req, task)
+}
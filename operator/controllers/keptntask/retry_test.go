@@ -0,0 +1,148 @@
+package keptntask
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+	klcv1alpha1 "github.com/keptn/lifecycle-controller/operator/api/v1alpha1"
+	"github.com/keptn/lifecycle-controller/operator/api/v1alpha1/common"
+	testrequire "github.com/stretchr/testify/require"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestRetryPolicy(t *testing.T) {
+	three, one := int32(3), int32(1)
+	definition := &klcv1alpha1.KeptnTaskDefinition{}
+	task := &klcv1alpha1.KeptnTask{}
+
+	retries, backoff := retryPolicy(task, definition)
+	testrequire.Equal(t, int32(0), retries)
+	testrequire.Equal(t, defaultRetryBackoff, backoff)
+
+	definition.Spec.Retries = &three
+	definition.Spec.Backoff = &metav1.Duration{Duration: time.Minute}
+	retries, backoff = retryPolicy(task, definition)
+	testrequire.Equal(t, int32(3), retries)
+	testrequire.Equal(t, time.Minute, backoff)
+
+	task.Spec.Retries = &one
+	task.Spec.Backoff = &metav1.Duration{Duration: time.Second}
+	retries, backoff = retryPolicy(task, definition)
+	testrequire.Equal(t, int32(1), retries)
+	testrequire.Equal(t, time.Second, backoff)
+
+	task.Spec.ExactlyOnce = true
+	retries, _ = retryPolicy(task, definition)
+	testrequire.Equal(t, int32(0), retries)
+}
+
+func TestRetryDelay(t *testing.T) {
+	testrequire.Equal(t, 10*time.Second, retryDelay(10*time.Second, 1))
+	testrequire.Equal(t, 20*time.Second, retryDelay(10*time.Second, 2))
+	testrequire.Equal(t, 40*time.Second, retryDelay(10*time.Second, 3))
+	testrequire.Equal(t, maxRetryDelay, retryDelay(10*time.Second, 100))
+}
+
+func TestJobFailed(t *testing.T) {
+	failedAt := metav1.NewTime(time.Date(2022, 11, 8, 12, 0, 0, 0, time.UTC))
+	job := &batchv1.Job{}
+
+	_, failed := jobFailed(job)
+	testrequire.False(t, failed)
+
+	job.Status.Conditions = []batchv1.JobCondition{{Type: batchv1.JobFailed, Status: corev1.ConditionFalse, LastTransitionTime: failedAt}}
+	_, failed = jobFailed(job)
+	testrequire.False(t, failed)
+
+	job.Status.Conditions = []batchv1.JobCondition{{Type: batchv1.JobFailed, Status: corev1.ConditionTrue, Reason: "BackoffLimitExceeded", LastTransitionTime: failedAt}}
+	at, failed := jobFailed(job)
+	testrequire.True(t, failed)
+	testrequire.True(t, at.Equal(failedAt.Time))
+}
+
+func TestKeptnTaskReconciler_UpdateJobDeadlineExceeded(t *testing.T) {
+	scheme := runtime.NewScheme()
+	testrequire.Nil(t, clientgoscheme.AddToScheme(scheme))
+	testrequire.Nil(t, klcv1alpha1.AddToScheme(scheme))
+	one := int32(1)
+
+	// the Job has been terminated by its active deadline, which is the timeout of the task
+	job := &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{Name: "klc-task-12345", Namespace: "default"},
+		Status: batchv1.JobStatus{Conditions: []batchv1.JobCondition{
+			{Type: batchv1.JobFailed, Status: corev1.ConditionTrue, Reason: "DeadlineExceeded", LastTransitionTime: metav1.NewTime(time.Now())},
+		}},
+	}
+	definition := &klcv1alpha1.KeptnTaskDefinition{
+		ObjectMeta: metav1.ObjectMeta{Name: "check", Namespace: "default"},
+		Spec:       klcv1alpha1.KeptnTaskDefinitionSpec{Retries: &one, Backoff: &metav1.Duration{Duration: time.Hour}},
+	}
+	newTask := func(deadline time.Time) *klcv1alpha1.KeptnTask {
+		return &klcv1alpha1.KeptnTask{
+			ObjectMeta: metav1.ObjectMeta{Name: "task", Namespace: "default"},
+			Spec:       klcv1alpha1.KeptnTaskSpec{TaskDefinition: definition.Name, Deadline: &metav1.Time{Time: deadline}},
+			Status:     klcv1alpha1.KeptnTaskStatus{JobName: job.Name, Attempts: 1, Status: common.StateProgressing},
+		}
+	}
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Name: "task", Namespace: "default"}}
+
+	tests := []struct {
+		name     string
+		deadline time.Time
+		event    string
+		failed   bool
+	}{
+		{
+			// the Job has exceeded the timeout of the task, which is retried before the deadline of the task
+			name:     "timeout",
+			deadline: time.Now().Add(24 * time.Hour),
+			event:    "TimeoutExceeded",
+			failed:   false,
+		},
+		{
+			// the Job has been terminated since the deadline of the task has passed, so the task is not retried
+			name:     "task deadline",
+			deadline: time.Now().Add(-time.Minute),
+			event:    "DeadlineExceeded",
+			failed:   true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			task := newTask(tt.deadline)
+			recorder := record.NewFakeRecorder(10)
+			r := &KeptnTaskReconciler{
+				Client:   fake.NewClientBuilder().WithScheme(scheme).WithObjects(job, definition, task.DeepCopy()).Build(),
+				Log:      logr.Discard(),
+				Recorder: recorder,
+				Tracer:   sdktrace.NewTracerProvider().Tracer("test"),
+			}
+
+			// only the status set in memory is checked, since updating the status depends on the status subresource
+			_ = r.updateJob(context.TODO(), req, task)
+			testrequire.Equal(t, tt.failed, task.Status.Status.IsFailed())
+			testrequire.Contains(t, <-recorder.Events, tt.event)
+			if tt.failed {
+				return
+			}
+			testrequire.True(t, strings.HasPrefix(task.Status.Reason, "Job klc-task-12345 has failed, attempt 2 of 2"), task.Status.Reason)
+
+			// the timeout is reported again while the task waits for its next attempt
+			_ = r.updateJob(context.TODO(), req, task)
+			testrequire.False(t, task.Status.Status.IsFailed())
+			testrequire.Contains(t, <-recorder.Events, tt.event)
+		})
+	}
+}
@@ -2,14 +2,20 @@ package keptntask
 
 import (
 	"context"
+	"fmt"
+
 	klcv1alpha1 "github.com/keptn/lifecycle-controller/operator/api/v1alpha1"
+	controllererrors "github.com/keptn/lifecycle-controller/operator/controllers/errors"
+	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/types"
 )
 
 func (r *KeptnTaskReconciler) getTaskDefinition(ctx context.Context, definitionName string, namespace string) (*klcv1alpha1.KeptnTaskDefinition, error) {
 	definition := &klcv1alpha1.KeptnTaskDefinition{}
 	err := r.Client.Get(ctx, types.NamespacedName{Name: definitionName, Namespace: namespace}, definition)
-	if err != nil {
+	if errors.IsNotFound(err) {
+		return definition, fmt.Errorf("could not retrieve KeptnTaskDefinition %s: %w", definitionName, controllererrors.Wrap(controllererrors.ErrTaskDefinitionMissing, err))
+	} else if err != nil {
 		return definition, err
 	}
 	return definition, nil
@@ -0,0 +1,106 @@
+package keptntask
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	klcv1alpha1 "github.com/keptn/lifecycle-controller/operator/api/v1alpha1"
+	"github.com/keptn/lifecycle-controller/operator/api/v1alpha1/common"
+	"github.com/keptn/lifecycle-controller/operator/controllers/reasons"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// throttleJob returns whether the Job of the task has to wait since the maximum number of running task Jobs in the
+// namespace has been reached. Waiting tasks get their Job in the order they have been created.
+func (r *KeptnTaskReconciler) throttleJob(ctx context.Context, task *klcv1alpha1.KeptnTask) (bool, error) {
+	limit, err := r.maxRunningJobs(ctx, task.Namespace)
+	if err != nil || limit <= 0 {
+		return false, err
+	}
+
+	jobs := &batchv1.JobList{}
+	if err := r.Client.List(ctx, jobs, client.InNamespace(task.Namespace), client.HasLabels{common.TaskNameAnnotation}); err != nil {
+		return false, fmt.Errorf("could not list Jobs: %w", err)
+	}
+	tasks := &klcv1alpha1.KeptnTaskList{}
+	if err := r.Client.List(ctx, tasks, client.InNamespace(task.Namespace)); err != nil {
+		return false, fmt.Errorf("could not list KeptnTasks: %w", err)
+	}
+
+	if countRunningJobs(jobs.Items)+countWaitingBefore(tasks.Items, task) < limit {
+		return false, nil
+	}
+	reason := throttledReason(limit)
+	if task.Status.Reason != reason {
+		if task.Status.Reason == "" {
+			r.recordEvent(task, reasons.JobThrottled, limit)
+		}
+		task.Status.Status = common.StatePending
+		task.Status.Reason = reason
+		if err := r.Client.Status().Update(ctx, task); err != nil {
+			return true, fmt.Errorf("could not update status of KeptnTask %s: %w", task.Name, err)
+		}
+	}
+	return true, nil
+}
+
+func throttledReason(limit int) string {
+	return fmt.Sprintf("waiting for one of the %d running task Jobs in the namespace to complete", limit)
+}
+
+// maxRunningJobs returns the maximum number of running task Jobs in the namespace. The annotation of the namespace
+// takes precedence over the limit of the operator.
+func (r *KeptnTaskReconciler) maxRunningJobs(ctx context.Context, namespace string) (int, error) {
+	ns := &corev1.Namespace{}
+	if err := r.Client.Get(ctx, types.NamespacedName{Name: namespace}, ns); err != nil {
+		return 0, fmt.Errorf("could not retrieve namespace %s: %w", namespace, err)
+	}
+	value, ok := ns.Annotations[common.MaxRunningTaskJobsAnnotation]
+	if !ok {
+		return r.MaxRunningJobs, nil
+	}
+	limit, err := strconv.Atoi(value)
+	if err != nil {
+		r.Log.Error(err, "invalid maximum number of running task Jobs annotated on namespace "+namespace)
+		return r.MaxRunningJobs, nil
+	}
+	return limit, nil
+}
+
+// countRunningJobs returns the number of the given Jobs that have neither completed nor failed
+func countRunningJobs(jobs []batchv1.Job) int {
+	running := 0
+	for i := range jobs {
+		if !jobFinished(&jobs[i]) {
+			running++
+		}
+	}
+	return running
+}
+
+func jobFinished(job *batchv1.Job) bool {
+	for _, condition := range job.Status.Conditions {
+		if (condition.Type == batchv1.JobComplete || condition.Type == batchv1.JobFailed) && condition.Status == corev1.ConditionTrue {
+			return true
+		}
+	}
+	return false
+}
+
+// countWaitingBefore returns the number of throttled tasks that have been created before the given task
+func countWaitingBefore(tasks []klcv1alpha1.KeptnTask, task *klcv1alpha1.KeptnTask) int {
+	waiting := 0
+	for _, t := range tasks {
+		if t.Name == task.Name || t.Status.Reason == "" || t.Status.JobName != "" || t.Status.Status.IsCompleted() {
+			continue
+		}
+		if t.CreationTimestamp.Before(&task.CreationTimestamp) || (t.CreationTimestamp.Equal(&task.CreationTimestamp) && t.Name < task.Name) {
+			waiting++
+		}
+	}
+	return waiting
+}
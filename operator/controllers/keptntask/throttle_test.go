@@ -0,0 +1,75 @@
+package keptntask
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+	klcv1alpha1 "github.com/keptn/lifecycle-controller/operator/api/v1alpha1"
+	"github.com/keptn/lifecycle-controller/operator/api/v1alpha1/common"
+	testrequire "github.com/stretchr/testify/require"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestKeptnTaskReconciler_throttleJob(t *testing.T) {
+	scheme := runtime.NewScheme()
+	testrequire.Nil(t, clientgoscheme.AddToScheme(scheme))
+	testrequire.Nil(t, klcv1alpha1.AddToScheme(scheme))
+
+	created := metav1.NewTime(time.Date(2022, 11, 8, 12, 0, 0, 0, time.UTC))
+	later := metav1.NewTime(created.Add(time.Minute))
+	taskJob := func(name string, finished bool) *batchv1.Job {
+		job := &batchv1.Job{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default", Labels: map[string]string{common.TaskNameAnnotation: name}}}
+		if finished {
+			job.Status.Conditions = []batchv1.JobCondition{{Type: batchv1.JobComplete, Status: corev1.ConditionTrue}}
+		}
+		return job
+	}
+	waiting := &klcv1alpha1.KeptnTask{
+		ObjectMeta: metav1.ObjectMeta{Name: "waiting", Namespace: "default", CreationTimestamp: created},
+		Status:     klcv1alpha1.KeptnTaskStatus{Status: common.StatePending, Reason: "waiting"},
+	}
+	task := &klcv1alpha1.KeptnTask{
+		ObjectMeta: metav1.ObjectMeta{Name: "task", Namespace: "default", CreationTimestamp: later},
+		Status:     klcv1alpha1.KeptnTaskStatus{Status: common.StatePending, Reason: throttledReason(3)},
+	}
+	namespace := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "default", Annotations: map[string]string{common.MaxRunningTaskJobsAnnotation: "3"}}}
+
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+		namespace, waiting, task,
+		taskJob("running-1", false), taskJob("running-2", false), taskJob("finished", true),
+		&batchv1.Job{ObjectMeta: metav1.ObjectMeta{Name: "other", Namespace: "default"}},
+	).Build()
+	r := &KeptnTaskReconciler{Client: c, Log: logr.Discard(), Recorder: record.NewFakeRecorder(10), MaxRunningJobs: 1}
+
+	// two running jobs and an older waiting task exhaust the limit of the namespace
+	throttled, err := r.throttleJob(context.TODO(), task)
+	testrequire.Nil(t, err)
+	testrequire.True(t, throttled)
+	testrequire.Equal(t, throttledReason(3), task.Status.Reason)
+
+	// the older waiting task gets the last slot
+	throttled, err = r.throttleJob(context.TODO(), waiting)
+	testrequire.Nil(t, err)
+	testrequire.False(t, throttled)
+
+	// without an annotation, the limit of the operator applies
+	namespace.Annotations = nil
+	testrequire.Nil(t, c.Update(context.TODO(), namespace))
+	task.Status.Reason = throttledReason(1)
+	throttled, err = r.throttleJob(context.TODO(), task)
+	testrequire.Nil(t, err)
+	testrequire.True(t, throttled)
+
+	r.MaxRunningJobs = 0
+	throttled, err = r.throttleJob(context.TODO(), waiting)
+	testrequire.Nil(t, err)
+	testrequire.False(t, throttled)
+}
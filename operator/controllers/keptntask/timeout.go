@@ -0,0 +1,19 @@
+package keptntask
+
+import (
+	"time"
+
+	klcv1alpha1 "github.com/keptn/lifecycle-controller/operator/api/v1alpha1"
+)
+
+// taskTimeout returns how long a Job of the task may run before it is terminated, which is 0 if it has no timeout.
+// The timeout of the task takes precedence over the one of its definition.
+func taskTimeout(task *klcv1alpha1.KeptnTask, definition *klcv1alpha1.KeptnTaskDefinition) time.Duration {
+	if task.Spec.Timeout != nil {
+		return task.Spec.Timeout.Duration
+	}
+	if definition.Spec.Timeout != nil {
+		return definition.Spec.Timeout.Duration
+	}
+	return 0
+}
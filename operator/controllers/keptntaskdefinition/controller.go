@@ -26,6 +26,8 @@ import (
 	"reflect"
 	"time"
 
+	"github.com/keptn/lifecycle-controller/operator/controllers/keptntask"
+	"github.com/keptn/lifecycle-controller/operator/controllers/selfmonitoring"
 	"k8s.io/apimachinery/pkg/runtime"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -59,8 +61,8 @@ func (r *KeptnTaskDefinitionReconciler) Reconcile(ctx context.Context, req ctrl.
 		return ctrl.Result{Requeue: true, RequeueAfter: 30 * time.Second}, nil
 	}
 
-	if !reflect.DeepEqual(definition.Spec.Function, klcv1alpha1.FunctionSpec{}) {
-		err := r.reconcileFunction(ctx, req, definition)
+	if function, _ := keptntask.Function(definition); !reflect.DeepEqual(function, klcv1alpha1.FunctionSpec{}) {
+		err := r.reconcileFunction(ctx, req, definition, function)
 		if err != nil {
 			return ctrl.Result{}, nil
 		}
@@ -74,5 +76,5 @@ func (r *KeptnTaskDefinitionReconciler) SetupWithManager(mgr ctrl.Manager) error
 	return ctrl.NewControllerManagedBy(mgr).
 		For(&klcv1alpha1.KeptnTaskDefinition{}).
 		Owns(&corev1.ConfigMap{}).
-		Complete(r)
+		Complete(selfmonitoring.Wrap("keptntaskdefinition", r))
 }
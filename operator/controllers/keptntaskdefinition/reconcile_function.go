@@ -13,15 +13,16 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 )
 
-func (r *KeptnTaskDefinitionReconciler) reconcileFunction(ctx context.Context, req ctrl.Request, definition *klcv1alpha1.KeptnTaskDefinition) error {
-	if definition.Spec.Function.Inline != (klcv1alpha1.Inline{}) {
-		err := r.reconcileFunctionInline(ctx, req, definition)
+// reconcileFunction provides the code of the function or Python script of the task definition in a ConfigMap
+func (r *KeptnTaskDefinitionReconciler) reconcileFunction(ctx context.Context, req ctrl.Request, definition *klcv1alpha1.KeptnTaskDefinition, function klcv1alpha1.FunctionSpec) error {
+	if function.Inline != (klcv1alpha1.Inline{}) {
+		err := r.reconcileFunctionInline(ctx, req, definition, function)
 		if err != nil {
 			return err
 		}
 	}
-	if definition.Spec.Function.ConfigMapReference != (klcv1alpha1.ConfigMapReference{}) {
-		err := r.reconcileFunctionConfigMap(ctx, req, definition)
+	if function.ConfigMapReference != (klcv1alpha1.ConfigMapReference{}) {
+		err := r.reconcileFunctionConfigMap(ctx, req, definition, function)
 		if err != nil {
 			return err
 		}
@@ -29,9 +30,8 @@ func (r *KeptnTaskDefinitionReconciler) reconcileFunction(ctx context.Context, r
 	return nil
 }
 
-func (r *KeptnTaskDefinitionReconciler) reconcileFunctionInline(ctx context.Context, req ctrl.Request, definition *klcv1alpha1.KeptnTaskDefinition) error {
+func (r *KeptnTaskDefinitionReconciler) reconcileFunctionInline(ctx context.Context, req ctrl.Request, definition *klcv1alpha1.KeptnTaskDefinition, functionSpec klcv1alpha1.FunctionSpec) error {
 	cmIsNew := false
-	functionSpec := definition.Spec.Function
 	functionName := "keptnfn-" + definition.Name
 
 	cm, err := r.getFunctionConfigMap(ctx, functionName, req.Namespace)
@@ -86,9 +86,9 @@ func (r *KeptnTaskDefinitionReconciler) reconcileFunctionInline(ctx context.Cont
 	return nil
 }
 
-func (r *KeptnTaskDefinitionReconciler) reconcileFunctionConfigMap(ctx context.Context, req ctrl.Request, definition *klcv1alpha1.KeptnTaskDefinition) error {
-	if definition.Spec.Function.ConfigMapReference.Name != definition.Status.Function.ConfigMap {
-		definition.Status.Function.ConfigMap = definition.Spec.Function.ConfigMapReference.Name
+func (r *KeptnTaskDefinitionReconciler) reconcileFunctionConfigMap(ctx context.Context, req ctrl.Request, definition *klcv1alpha1.KeptnTaskDefinition, function klcv1alpha1.FunctionSpec) error {
+	if function.ConfigMapReference.Name != definition.Status.Function.ConfigMap {
+		definition.Status.Function.ConfigMap = function.ConfigMapReference.Name
 		err := r.Client.Status().Update(ctx, definition)
 		if err != nil {
 			r.Log.Error(err, "could not update configmap status reference for: "+definition.Name)
@@ -20,6 +20,10 @@ import (
 	"context"
 	"fmt"
 	"github.com/keptn/lifecycle-controller/operator/api/v1alpha1/semconv"
+	"github.com/keptn/lifecycle-controller/operator/controllers/definitions"
+	"github.com/keptn/lifecycle-controller/operator/controllers/environment"
+	"github.com/keptn/lifecycle-controller/operator/controllers/propagate"
+	"github.com/keptn/lifecycle-controller/operator/controllers/reasons"
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/codes"
 	"go.opentelemetry.io/otel/propagation"
@@ -29,13 +33,19 @@ import (
 
 	"github.com/go-logr/logr"
 	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/tools/record"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
 
 	klcv1alpha1 "github.com/keptn/lifecycle-controller/operator/api/v1alpha1"
+	"github.com/keptn/lifecycle-controller/operator/api/v1alpha1/common"
+	"github.com/keptn/lifecycle-controller/operator/controllers/apply"
+	"github.com/keptn/lifecycle-controller/operator/controllers/selfmonitoring"
 	"k8s.io/apimachinery/pkg/runtime"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -48,11 +58,19 @@ type KeptnWorkloadReconciler struct {
 	Recorder record.EventRecorder
 	Log      logr.Logger
 	Tracer   trace.Tracer
+	// EnvironmentLabel is the label of the namespaces containing the environment the KeptnWorkloadInstances are deployed to
+	EnvironmentLabel string
+	// Propagation selects the labels and annotations of the app of the workload copied to its KeptnWorkloadInstances
+	Propagation propagate.Policy
 }
 
 //+kubebuilder:rbac:groups=lifecycle.keptn.sh,resources=keptnworkloads,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups=lifecycle.keptn.sh,resources=keptnworkloads/status,verbs=get;update;patch
 //+kubebuilder:rbac:groups=lifecycle.keptn.sh,resources=keptnworkloads/finalizers,verbs=update
+//+kubebuilder:rbac:groups=lifecycle.keptn.sh,resources=keptntaskdefinitions,verbs=get;list;watch
+//+kubebuilder:rbac:groups=lifecycle.keptn.sh,resources=keptnevaluationdefinitions,verbs=get;list;watch
+//+kubebuilder:rbac:groups=lifecycle.keptn.sh,resources=keptnapps,verbs=get;list;watch
+//+kubebuilder:rbac:groups=core,resources=namespaces,verbs=get;list;watch
 //+kubebuilder:rbac:groups=lifecycle.keptn.sh,resources=keptnworkloadinstances,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups=lifecycle.keptn.sh,resources=keptnworkloadinstances/status,verbs=get;update;patch
 //+kubebuilder:rbac:groups=lifecycle.keptn.sh,resources=keptnworkloadinstances/finalizers,verbs=update
@@ -88,18 +106,35 @@ func (r *KeptnWorkloadReconciler) Reconcile(ctx context.Context, req ctrl.Reques
 
 	r.Log.Info("Reconciling Keptn Workload", "workload", workload.Name)
 
+	if err := r.updateDefinitionsCondition(ctx, workload); err != nil {
+		r.Log.Error(err, "could not resolve definitions of Workload")
+		span.SetStatus(codes.Error, err.Error())
+		return ctrl.Result{}, err
+	}
+
 	workloadInstance := &klcv1alpha1.KeptnWorkloadInstance{}
 
 	// Try to find the workload instance
 	err = r.Get(ctx, types.NamespacedName{Namespace: workload.Namespace, Name: workload.GetWorkloadInstanceName()}, workloadInstance)
+	if err == nil {
+		err = workloadInstance.VerifyIdentity(workload.Name, workload.Spec.Version)
+	}
 	// If the workload instance does not exist, create it
 	if errors.IsNotFound(err) {
+		if workload.Status.CurrentVersion == "" {
+			importedVersion, err := r.importPreviousVersion(ctx, workload)
+			if err != nil {
+				// the workload is deployed without a previous version
+				r.Log.Error(err, "could not import previous version of Workload")
+			}
+			workload.Status.CurrentVersion = importedVersion
+		}
 		workloadInstance, err := r.createWorkloadInstance(ctx, workload)
 		if err != nil {
 			span.SetStatus(codes.Error, err.Error())
 			return reconcile.Result{}, err
 		}
-		err = r.Client.Create(ctx, workloadInstance)
+		err = apply.Apply(ctx, r.Client, workloadInstance, r.Recorder, workload)
 		if err != nil {
 			r.Log.Error(err, "could not create Workload Instance")
 			span.SetStatus(codes.Error, err.Error())
@@ -120,14 +155,77 @@ func (r *KeptnWorkloadReconciler) Reconcile(ctx context.Context, req ctrl.Reques
 		return ctrl.Result{}, err
 	}
 
+	if workload.Status.CurrentVersion != workload.Spec.Version {
+		if err := r.restoreWorkloadInstance(ctx, workload, workloadInstance); err != nil {
+			span.SetStatus(codes.Error, err.Error())
+			return ctrl.Result{}, err
+		}
+	}
+
 	return ctrl.Result{}, nil
 }
 
+// restoreWorkloadInstance makes an earlier workload instance the current one again when the workload returns to its
+// version, e.g. because the Deployment was rolled back to the ReplicaSet of that version
+func (r *KeptnWorkloadReconciler) restoreWorkloadInstance(ctx context.Context, workload *klcv1alpha1.KeptnWorkload, workloadInstance *klcv1alpha1.KeptnWorkloadInstance) error {
+	if workloadInstance.Spec.ResourceReference != workload.Spec.ResourceReference {
+		workloadInstance.Spec.ResourceReference = workload.Spec.ResourceReference
+		if err := r.Client.Update(ctx, workloadInstance); err != nil {
+			r.Log.Error(err, "could not update Resource Reference of Workload Instance")
+			return err
+		}
+	}
+	r.Recorder.Event(workload, "Normal", "WorkloadInstanceRestored", fmt.Sprintf("Restored KeptnWorkloadInstance of version %s / Namespace: %s, Name: %s ", workload.Spec.Version, workloadInstance.Namespace, workloadInstance.Name))
+	workload.Status.CurrentVersion = workload.Spec.Version
+	if err := r.Client.Status().Update(ctx, workload); err != nil {
+		r.Log.Error(err, "could not update Current Version of Workload")
+		return err
+	}
+	return nil
+}
+
 // SetupWithManager sets up the controller with the Manager.
 func (r *KeptnWorkloadReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	return ctrl.NewControllerManagedBy(mgr).
 		For(&klcv1alpha1.KeptnWorkload{}, builder.WithPredicates(predicate.GenerationChangedPredicate{})).
-		Complete(r)
+		Watches(&source.Kind{Type: &klcv1alpha1.KeptnTaskDefinition{}}, handler.EnqueueRequestsFromMapFunc(r.getWorkloadsForDefinition)).
+		Watches(&source.Kind{Type: &klcv1alpha1.KeptnEvaluationDefinition{}}, handler.EnqueueRequestsFromMapFunc(r.getWorkloadsForDefinition)).
+		Complete(selfmonitoring.Wrap("keptnworkload", r))
+}
+
+// updateDefinitionsCondition reports the task and evaluation definitions referenced by the workload that do not exist
+func (r *KeptnWorkloadReconciler) updateDefinitionsCondition(ctx context.Context, workload *klcv1alpha1.KeptnWorkload) error {
+	tasks := append(append(append([]string{}, workload.Spec.PreDeploymentTasks...), workload.Spec.MigrationTasks...), workload.Spec.PostDeploymentTasks...)
+	evaluations := append(append([]string{}, workload.Spec.PreDeploymentEvaluations...), workload.Spec.PostDeploymentEvaluations...)
+	condition, err := definitions.ResolveCondition(ctx, r.Client, workload.Namespace, workload.Generation, tasks, evaluations)
+	if err != nil {
+		return err
+	}
+	if !definitions.ConditionChanged(workload.Status.Conditions, condition) {
+		return nil
+	}
+	if condition.Status == metav1.ConditionFalse {
+		r.Recorder.AnnotatedEventf(workload, reasons.DefinitionsNotFound.Annotations(), "Warning", condition.Reason, "%s / Namespace: %s, Name: %s ", condition.Message, workload.Namespace, workload.Name)
+	}
+	meta.SetStatusCondition(&workload.Status.Conditions, condition)
+	return r.Client.Status().Update(ctx, workload)
+}
+
+// getWorkloadsForDefinition returns a request for each KeptnWorkload referencing the given task or evaluation definition
+func (r *KeptnWorkloadReconciler) getWorkloadsForDefinition(definition client.Object) []reconcile.Request {
+	workloads := &klcv1alpha1.KeptnWorkloadList{}
+	if err := r.Client.List(context.TODO(), workloads, client.InNamespace(definition.GetNamespace())); err != nil {
+		r.Log.Error(err, "could not retrieve KeptnWorkloads")
+		return nil
+	}
+
+	var requests []reconcile.Request
+	for _, workload := range workloads.Items {
+		if definitions.References(definition.GetName(), workload.Spec.PreDeploymentTasks, workload.Spec.MigrationTasks, workload.Spec.PostDeploymentTasks, workload.Spec.PreDeploymentEvaluations, workload.Spec.PostDeploymentEvaluations) {
+			requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{Namespace: workload.Namespace, Name: workload.Name}})
+		}
+	}
+	return requests
 }
 
 func (r *KeptnWorkloadReconciler) createWorkloadInstance(ctx context.Context, workload *klcv1alpha1.KeptnWorkload) (*klcv1alpha1.KeptnWorkloadInstance, error) {
@@ -136,10 +234,18 @@ func (r *KeptnWorkloadReconciler) createWorkloadInstance(ctx context.Context, wo
 
 	semconv.AddAttributeFromWorkload(span, *workload)
 
+	env, err := environment.Resolve(ctx, r.Client, workload.Namespace, r.EnvironmentLabel)
+	if err != nil {
+		r.Log.Error(err, "could not resolve environment of Workload")
+	}
+	span.SetAttributes(common.Environment.String(env))
+	ctx = semconv.ContextWithDeploymentBaggage(ctx, common.AppName.String(workload.Spec.AppName), common.WorkloadName.String(workload.Name), common.WorkloadVersion.String(workload.Spec.Version), common.Environment.String(env))
+
 	// create TraceContext
 	// follow up with a Keptn propagator that JSON-encoded the OTel map into our own key
 	traceContextCarrier := propagation.MapCarrier{}
 	otel.GetTextMapPropagator().Inject(ctx, traceContextCarrier)
+	traceContextCarrier[common.InstanceIdentityAnnotation] = common.Identity(workload.Spec.AppName, workload.Name, workload.Spec.Version)
 
 	previousVersion := ""
 	if workload.Spec.Version != workload.Status.CurrentVersion {
@@ -151,6 +257,7 @@ func (r *KeptnWorkloadReconciler) createWorkloadInstance(ctx context.Context, wo
 			Annotations: traceContextCarrier,
 			Name:        workload.GetWorkloadInstanceName(),
 			Namespace:   workload.Namespace,
+			Labels:      environment.Labels(map[string]string{common.InstanceIdLabel: common.IdentityHash(workload.Name, workload.Spec.Version)}, env),
 		},
 		Spec: klcv1alpha1.KeptnWorkloadInstanceSpec{
 			KeptnWorkloadSpec: workload.Spec,
@@ -158,7 +265,15 @@ func (r *KeptnWorkloadReconciler) createWorkloadInstance(ctx context.Context, wo
 			PreviousVersion:   previousVersion,
 		},
 	}
-	err := controllerutil.SetControllerReference(workload, workloadInstance, r.Scheme)
+	if !r.Propagation.IsEmpty() {
+		app := &klcv1alpha1.KeptnApp{}
+		if err := r.Client.Get(ctx, types.NamespacedName{Name: workload.Spec.AppName, Namespace: workload.Namespace}, app); err != nil {
+			r.Log.Error(err, "could not fetch App to propagate its labels and annotations to WorkloadInstance: "+workloadInstance.Name)
+		} else {
+			r.Propagation.Copy(app.ObjectMeta, &workloadInstance.ObjectMeta)
+		}
+	}
+	err = controllerutil.SetControllerReference(workload, workloadInstance, r.Scheme)
 	if err != nil {
 		r.Log.Error(err, "could not set controller reference for WorkloadInstance: "+workloadInstance.Name)
 	}
@@ -0,0 +1,95 @@
+package keptnworkload
+
+import (
+	"context"
+	"fmt"
+
+	klcv1alpha1 "github.com/keptn/lifecycle-controller/operator/api/v1alpha1"
+	"github.com/keptn/lifecycle-controller/operator/api/v1alpha1/common"
+	"github.com/keptn/lifecycle-controller/operator/controllers/apply"
+	"github.com/keptn/lifecycle-controller/operator/controllers/environment"
+	"github.com/keptn/lifecycle-controller/operator/controllers/history"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+// importPreviousVersion creates a completed KeptnWorkloadInstance for the earlier revision of the Deployment of a newly
+// adopted workload, whose version the webhook annotated on the workload, so that the first deployment managed by the
+// lifecycle controller has a previous version to be compared with.
+// It returns the imported version, or an empty string if there is nothing to import.
+func (r *KeptnWorkloadReconciler) importPreviousVersion(ctx context.Context, workload *klcv1alpha1.KeptnWorkload) (string, error) {
+	version := workload.Annotations[common.ImportedVersionAnnotation]
+	if version == "" || version == workload.Spec.Version || workload.Spec.ResourceReference.Kind != "ReplicaSet" {
+		return "", nil
+	}
+	replicaSet, err := history.PreviousReplicaSet(ctx, r.Client, workload.Namespace, workload.Spec.ResourceReference.UID)
+	if err != nil || replicaSet == nil {
+		return "", err
+	}
+
+	env, err := environment.Resolve(ctx, r.Client, workload.Namespace, r.EnvironmentLabel)
+	if err != nil {
+		r.Log.Error(err, "could not resolve environment of Workload")
+	}
+	spec := *workload.Spec.DeepCopy()
+	spec.Version = version
+	spec.ResourceReference = klcv1alpha1.ResourceReference{UID: replicaSet.UID, Kind: "ReplicaSet"}
+	spec.PreDeploymentTasks = nil
+	spec.PostDeploymentTasks = nil
+	spec.MigrationTasks = nil
+	spec.PreDeploymentEvaluations = nil
+	spec.PostDeploymentEvaluations = nil
+
+	workloadInstance := &klcv1alpha1.KeptnWorkloadInstance{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        common.CreateInstanceName(workload.Name, version),
+			Namespace:   workload.Namespace,
+			Labels:      environment.Labels(map[string]string{common.InstanceIdLabel: common.IdentityHash(workload.Name, version)}, env),
+			Annotations: map[string]string{common.ImportedAnnotation: "true", common.InstanceIdentityAnnotation: common.Identity(workload.Spec.AppName, workload.Name, version)},
+		},
+		Spec: klcv1alpha1.KeptnWorkloadInstanceSpec{
+			KeptnWorkloadSpec: spec,
+			WorkloadName:      workload.Name,
+		},
+	}
+	if err := controllerutil.SetControllerReference(workload, workloadInstance, r.Scheme); err != nil {
+		return "", fmt.Errorf("could not set controller reference for imported WorkloadInstance %s: %w", workloadInstance.Name, err)
+	}
+
+	// an import that failed before its status has been set is repeated, whereas a version deployed with the lifecycle
+	// controller is kept
+	existing := &klcv1alpha1.KeptnWorkloadInstance{}
+	if err := r.Client.Get(ctx, client.ObjectKeyFromObject(workloadInstance), existing); err == nil {
+		if err := existing.VerifyIdentity(workload.Name, version); err != nil {
+			return "", fmt.Errorf("could not import WorkloadInstance %s: %w", workloadInstance.Name, err)
+		}
+		if existing.Annotations[common.ImportedAnnotation] != "true" {
+			return version, nil
+		}
+	} else if !errors.IsNotFound(err) {
+		return "", fmt.Errorf("could not retrieve imported WorkloadInstance %s: %w", workloadInstance.Name, err)
+	}
+	if err := apply.Apply(ctx, r.Client, workloadInstance, r.Recorder, workload); err != nil {
+		return "", fmt.Errorf("could not create imported WorkloadInstance %s: %w", workloadInstance.Name, err)
+	}
+
+	// the earlier revision has been rolled out when its ReplicaSet was created
+	workloadInstance.Status = klcv1alpha1.KeptnWorkloadInstanceStatus{
+		PreDeploymentStatus:            common.StateSucceeded,
+		PreDeploymentEvaluationStatus:  common.StateSucceeded,
+		DeploymentStatus:               common.StateSucceeded,
+		PostDeploymentStatus:           common.StateSucceeded,
+		PostDeploymentEvaluationStatus: common.StateSucceeded,
+		Status:                         common.StateSucceeded,
+		CurrentPhase:                   common.PhaseCompleted.ShortName,
+		StartTime:                      replicaSet.CreationTimestamp,
+		EndTime:                        replicaSet.CreationTimestamp,
+	}
+	if err := r.Client.Status().Update(ctx, workloadInstance); err != nil {
+		return "", fmt.Errorf("could not update status of imported WorkloadInstance %s: %w", workloadInstance.Name, err)
+	}
+	r.Recorder.Event(workload, "Normal", "WorkloadInstanceImported", fmt.Sprintf("Imported KeptnWorkloadInstance of version %s from ReplicaSet %s / Namespace: %s, Name: %s ", version, replicaSet.Name, workloadInstance.Namespace, workloadInstance.Name))
+	return version, nil
+}
@@ -19,6 +19,7 @@ package keptnworkloadinstance
 import (
 	"context"
 	"fmt"
+	"strings"
 	"time"
 
 	"golang.org/x/mod/semver"
@@ -31,15 +32,32 @@ import (
 
 	"github.com/go-logr/logr"
 	"github.com/google/uuid"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/tools/record"
 	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/predicate"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
 
 	klcv1alpha1 "github.com/keptn/lifecycle-controller/operator/api/v1alpha1"
 	"github.com/keptn/lifecycle-controller/operator/api/v1alpha1/common"
+	"github.com/keptn/lifecycle-controller/operator/controllers/deadline"
+	"github.com/keptn/lifecycle-controller/operator/controllers/debug"
+	controllererrors "github.com/keptn/lifecycle-controller/operator/controllers/errors"
+	"github.com/keptn/lifecycle-controller/operator/controllers/propagate"
+	"github.com/keptn/lifecycle-controller/operator/controllers/reasons"
+	"github.com/keptn/lifecycle-controller/operator/controllers/selfmonitoring"
+	"github.com/keptn/lifecycle-controller/operator/integrations/cost"
+	"github.com/keptn/lifecycle-controller/operator/integrations/energy"
+	"github.com/keptn/lifecycle-controller/operator/integrations/incident"
+	"github.com/keptn/lifecycle-controller/operator/integrations/jira"
+	"github.com/keptn/lifecycle-controller/operator/integrations/servicenow"
+	"github.com/keptn/lifecycle-controller/operator/metrics"
 	"k8s.io/apimachinery/pkg/runtime"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -51,9 +69,28 @@ type KeptnWorkloadInstanceReconciler struct {
 	Scheme      *runtime.Scheme
 	Recorder    record.EventRecorder
 	Log         logr.Logger
-	Meters      common.KeptnMeters
+	Meters      metrics.Meters
 	Tracer      trace.Tracer
 	bindCRDSpan map[string]trace.Span
+	// IssueTracker receives the outcome of deployments referencing an issue. It is optional.
+	IssueTracker *jira.Client
+	// CostEstimator projects the resource cost of new versions. It is optional.
+	CostEstimator *cost.Estimator
+	// MaxHourlyCostIncrease fails the pre-deployment phase if the projected hourly cost increases by more than this amount. Zero disables the check.
+	MaxHourlyCostIncrease float64
+	// EnergyMeter measures the power consumption of workloads before and after a deployment. It is optional.
+	EnergyMeter *energy.Meter
+	// ChangeManager checks the ServiceNow change requests gating the deployment of workloads. It is optional.
+	ChangeManager *servicenow.Client
+	// DebugStatus records the last decision of the reconciler in status.debug
+	DebugStatus bool
+	// Propagation selects the labels and annotations of the workload instance copied to its KeptnTasks
+	Propagation propagate.Policy
+	// RollbackEnabled rolls back workloads with spec.rollbackOnFailure if their post-deployment evaluations fail
+	RollbackEnabled bool
+	// IncidentManager opens incidents for workloads in production namespaces that failed their post-deployment
+	// evaluations. It is optional.
+	IncidentManager incident.Manager
 }
 
 //+kubebuilder:rbac:groups=lifecycle.keptn.sh,resources=keptnworkloadinstances,verbs=get;list;watch;create;update;patch;delete
@@ -62,6 +99,8 @@ type KeptnWorkloadInstanceReconciler struct {
 //+kubebuilder:rbac:groups=lifecycle.keptn.sh,resources=keptntasks,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups=lifecycle.keptn.sh,resources=keptntasks/status,verbs=get;update;patch
 //+kubebuilder:rbac:groups=lifecycle.keptn.sh,resources=keptntasks/finalizers,verbs=update
+//+kubebuilder:rbac:groups=lifecycle.keptn.sh,resources=keptnevaluations,verbs=delete
+//+kubebuilder:rbac:groups=core,resources=namespaces,verbs=get;list;watch
 //+kubebuilder:rbac:groups=core,resources=events,verbs=create;watch;patch
 //+kubebuilder:rbac:groups=core,resources=pods,verbs=get;list;watch
 //+kubebuilder:rbac:groups=apps,resources=replicasets;deployments;statefulsets,verbs=get;list;watch
@@ -75,12 +114,12 @@ type KeptnWorkloadInstanceReconciler struct {
 //
 // For more details, check Reconcile and its Result here:
 // - https://pkg.go.dev/sigs.k8s.io/controller-runtime@v0.12.2/pkg/reconcile
-func (r *KeptnWorkloadInstanceReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+func (r *KeptnWorkloadInstanceReconciler) Reconcile(ctx context.Context, req ctrl.Request) (result ctrl.Result, err error) {
 	r.Log.Info("Searching for Keptn Workload Instance")
 
 	//retrieve workload instance
 	workloadInstance := &klcv1alpha1.KeptnWorkloadInstance{}
-	err := r.Get(ctx, req.NamespacedName, workloadInstance)
+	err = r.Get(ctx, req.NamespacedName, workloadInstance)
 	if errors.IsNotFound(err) {
 		return reconcile.Result{}, nil
 	}
@@ -90,6 +129,18 @@ func (r *KeptnWorkloadInstanceReconciler) Reconcile(ctx context.Context, req ctr
 		return reconcile.Result{}, fmt.Errorf("could not fetch KeptnWorkloadInstance: %+v", err)
 	}
 
+	// imported workload instances only serve as the previous version of the first deployment of a workload
+	if workloadInstance.IsImported() {
+		return reconcile.Result{}, nil
+	}
+
+	if r.DebugStatus {
+		workloadInstance.Status.Debug = nil
+		defer func() {
+			r.recordDecision(ctx, workloadInstance, result, err)
+		}()
+	}
+
 	//setup otel
 	traceContextCarrier := propagation.MapCarrier(workloadInstance.Annotations)
 	ctx = otel.GetTextMapPropagator().Extract(ctx, traceContextCarrier)
@@ -107,33 +158,75 @@ func (r *KeptnWorkloadInstanceReconciler) Reconcile(ctx context.Context, req ctr
 	found, appVersion, err := r.getAppVersionForWorkloadInstance(ctx, workloadInstance)
 	if err != nil {
 		span.SetStatus(codes.Error, err.Error())
-		r.recordEvent(phase, "Warning", workloadInstance, "GetAppVersionFailed", "has failed since app could not be retrieved")
+		r.recordEvent(phase, workloadInstance, reasons.GetAppVersionFailed)
 		return reconcile.Result{Requeue: true, RequeueAfter: 10 * time.Second}, fmt.Errorf("could not fetch AppVersion for KeptnWorkloadInstance: %+v", err)
 	} else if !found {
-		span.SetStatus(codes.Error, "app could not be found")
-		r.recordEvent(phase, "Warning", workloadInstance, "AppVersionNotFound", "has failed since app could not be found")
-		return reconcile.Result{Requeue: true, RequeueAfter: 10 * time.Second}, fmt.Errorf("could not find AppVersion for KeptnWorkloadInstance")
+		err = fmt.Errorf("could not find AppVersion for KeptnWorkloadInstance: %w", controllererrors.ErrAppVersionNotFound)
+		span.SetStatus(codes.Error, err.Error())
+		r.recordEvent(phase, workloadInstance, reasons.AppVersionNotFound)
+		r.Log.Info(err.Error())
+		return controllererrors.Result(err)
 	}
 
 	appTraceContextCarrier := propagation.MapCarrier(appVersion.Spec.TraceId)
 	ctxAppTrace := otel.GetTextMapPropagator().Extract(context.TODO(), appTraceContextCarrier)
+	// the tasks and evaluations of the workload instance carry the baggage of the workload in addition to the one of the app
+	ctxAppTrace = semconv.ContextWithDeploymentBaggage(ctxAppTrace,
+		common.WorkloadName.String(workloadInstance.Spec.WorkloadName),
+		common.WorkloadVersion.String(workloadInstance.Spec.Version),
+		common.Environment.String(workloadInstance.Labels[common.EnvironmentLabel]),
+	)
+
+	if exceeded, err := r.reconcileDeadline(ctx, ctxAppTrace, workloadInstance); exceeded {
+		return ctrl.Result{}, err
+	}
 
+	// the phases of the workload instance do not proceed once its app version has been aborted
+	if appVersion.Status.Status.IsCancelled() {
+		if workloadInstance.IsEndTimeSet() {
+			return ctrl.Result{}, nil
+		}
+		// the migration of the workload is only unlocked once its running task has completed
+		if requeue, err := r.reconcileAbort(ctx, ctxAppTrace, workloadInstance); requeue || err != nil {
+			return ctrl.Result{Requeue: true, RequeueAfter: 10 * time.Second}, err
+		}
+		return ctrl.Result{}, nil
+	}
+
+	// the workload of an app on the fast path does not wait for the app-level phases, which are skipped, but it waits
+	// while the app version is held back by a previous version
 	appPreEvalStatus := appVersion.Status.PreDeploymentEvaluationStatus
-	if !appPreEvalStatus.IsSucceeded() {
+	if !appPreEvalStatus.IsSucceeded() && !appVersion.HasStartedFastPath() {
 		if appPreEvalStatus.IsFailed() {
-			r.recordEvent(phase, "Warning", workloadInstance, "Failed", "has failed since app has failed")
+			r.recordEvent(phase, workloadInstance, reasons.AppFailed)
 			return ctrl.Result{Requeue: true, RequeueAfter: 60 * time.Second}, nil
 		}
-		r.recordEvent(phase, "Normal", workloadInstance, "NotFinished", "Pre evaluations tasks for app not finished")
+		r.recordEvent(phase, workloadInstance, reasons.AppNotFinished)
+		return ctrl.Result{Requeue: true, RequeueAfter: 20 * time.Second}, nil
+	}
+	if !appVersion.IsApproved() {
+		if appVersion.IsApprovalFailed() {
+			r.recordEvent(common.PhaseAppApproval, workloadInstance, reasons.AppRejected)
+			return ctrl.Result{Requeue: true, RequeueAfter: 60 * time.Second}, nil
+		}
+		r.recordEvent(common.PhaseAppApproval, workloadInstance, reasons.AppApprovalPending)
 		return ctrl.Result{Requeue: true, RequeueAfter: 20 * time.Second}, nil
 	}
 
+	// the phases of the workload instance are children of its span in the trace of the app version
+	if !workloadInstance.IsEndTimeSet() {
+		var spanWorkloadTrace trace.Span
+		ctxAppTrace, spanWorkloadTrace = r.getSpan(ctxAppTrace, workloadInstance, semconv.WorkloadInstanceSpanName)
+		semconv.AddAttributeFromWorkloadInstance(spanWorkloadTrace, *workloadInstance)
+	}
+
 	//Wait for pre-deployment checks of Workload
 	phase = common.PhaseWorkloadPreDeployment
 	saveState := false
 	//Set state to progressing if not already set
 	if workloadInstance.Status.PreDeploymentStatus == common.StatePending {
 		workloadInstance.Status.PreDeploymentStatus = common.StateProgressing
+		r.compareToPreviousVersion(ctx, span, workloadInstance)
 		saveState = true
 	}
 	// set the App trace id if not already set
@@ -149,15 +242,15 @@ func (r *KeptnWorkloadInstanceReconciler) Reconcile(ctx context.Context, req ctr
 	if appVersion.Status.CurrentPhase == "" {
 		r.unbindSpan(workloadInstance, phase.ShortName)
 		var spanAppTrace trace.Span
-		ctxAppTrace, spanAppTrace = r.getSpan(ctxAppTrace, workloadInstance, phase.ShortName)
+		_, spanAppTrace = r.getSpan(ctxAppTrace, workloadInstance, phase.ShortName)
 		semconv.AddAttributeFromAppVersion(spanAppTrace, appVersion)
 		spanAppTrace.AddEvent("WorkloadInstance Pre-Deployment Tasks started", trace.WithTimestamp(time.Now()))
-		r.recordEvent(phase, "Normal", workloadInstance, "Started", "have started")
+		r.recordEvent(phase, workloadInstance, reasons.Started)
 	}
 
 	if !workloadInstance.IsPreDeploymentSucceeded() {
-		reconcilePre := func() (common.KeptnState, error) {
-			return r.reconcilePrePostDeployment(ctx, workloadInstance, common.PreDeploymentCheckType)
+		reconcilePre := func(phaseCtx context.Context) (common.KeptnState, error) {
+			return r.reconcilePrePostDeployment(phaseCtx, workloadInstance, common.PreDeploymentCheckType)
 		}
 		return r.handlePhase(ctx, ctxAppTrace, workloadInstance, phase, span, workloadInstance.IsPreDeploymentFailed, reconcilePre)
 	}
@@ -173,12 +266,35 @@ func (r *KeptnWorkloadInstanceReconciler) Reconcile(ctx context.Context, req ctr
 		}
 	}
 	if !workloadInstance.IsPreDeploymentEvaluationSucceeded() {
-		reconcilePreEval := func() (common.KeptnState, error) {
-			return r.reconcilePrePostEvaluation(ctx, workloadInstance, common.PreDeploymentEvaluationCheckType)
+		reconcilePreEval := func(phaseCtx context.Context) (common.KeptnState, error) {
+			return r.reconcilePrePostEvaluation(phaseCtx, workloadInstance, common.PreDeploymentEvaluationCheckType)
 		}
 		return r.handlePhase(ctx, ctxAppTrace, workloadInstance, phase, span, workloadInstance.IsPreDeploymentEvaluationFailed, reconcilePreEval)
 	}
 
+	//Wait for migration tasks of Workload
+	phase = common.PhaseWorkloadMigration
+	// workloads without migration tasks proceed to the deployment right away
+	if !workloadInstance.HasMigrationTasks() && !workloadInstance.IsMigrationSucceeded() {
+		workloadInstance.Status.MigrationStatus = common.StateSucceeded
+		if err := r.Status().Update(ctx, workloadInstance); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+	//Set state to progressing if not already set
+	if workloadInstance.Status.MigrationStatus == common.StatePending {
+		workloadInstance.Status.MigrationStatus = common.StateProgressing
+		if err := r.Status().Update(ctx, workloadInstance); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+	if !workloadInstance.IsMigrationSucceeded() {
+		reconcileMigration := func(phaseCtx context.Context) (common.KeptnState, error) {
+			return r.reconcileMigration(phaseCtx, workloadInstance)
+		}
+		return r.handlePhase(ctx, ctxAppTrace, workloadInstance, phase, span, workloadInstance.IsMigrationFailed, reconcileMigration)
+	}
+
 	//Wait for deployment of Workload
 	phase = common.PhaseWorkloadDeployment
 	//Set state to progressing if not already set
@@ -189,10 +305,27 @@ func (r *KeptnWorkloadInstanceReconciler) Reconcile(ctx context.Context, req ctr
 		}
 	}
 	if !workloadInstance.IsDeploymentSucceeded() {
-		reconcileWorkloadInstance := func() (common.KeptnState, error) {
-			return r.reconcileDeployment(ctx, workloadInstance)
+		reconcileWorkloadInstance := func(phaseCtx context.Context) (common.KeptnState, error) {
+			return r.reconcileDeployment(phaseCtx, workloadInstance)
+		}
+		result, err := r.handlePhase(ctx, ctxAppTrace, workloadInstance, phase, span, workloadInstance.IsDeploymentFailed, reconcileWorkloadInstance)
+		if err != nil || workloadInstance.IsDeploymentFailed() {
+			return result, err
+		}
+		if workloadInstance.IsDeploymentSucceeded() {
+			// the post-deployment phase starts right away
+			return ctrl.Result{Requeue: true}, nil
 		}
-		return r.handlePhase(ctx, ctxAppTrace, workloadInstance, phase, span, workloadInstance.IsDeploymentFailed, reconcileWorkloadInstance)
+		if workloadInstance.Status.DeploymentStatus.IsPaused() {
+			// resuming the rollout of the Deployment requeues the workload instance, unless the deployment times out before
+			if timeout := deadline.PhaseTimeout(workloadInstance.Spec.Timeouts, phase); timeout > 0 {
+				phaseDeadline := metav1.NewTime(workloadInstance.Status.PhaseStartTime.Add(timeout))
+				return ctrl.Result{Requeue: true, RequeueAfter: deadline.Remaining(phaseDeadline, time.Now())}, nil
+			}
+			return ctrl.Result{}, nil
+		}
+		// changes of the readiness of the pods and ReplicaSet requeue the workload instance, polling is only a fallback
+		return ctrl.Result{Requeue: true, RequeueAfter: DeploymentResyncPeriod}, nil
 	}
 
 	//Wait for post-deployment checks of Workload
@@ -200,13 +333,14 @@ func (r *KeptnWorkloadInstanceReconciler) Reconcile(ctx context.Context, req ctr
 	//Set state to progressing if not already set
 	if workloadInstance.Status.PostDeploymentStatus == common.StatePending {
 		workloadInstance.Status.PostDeploymentStatus = common.StateProgressing
+		r.measurePower(ctx, span, workloadInstance)
 		if err := r.Status().Update(ctx, workloadInstance); err != nil {
 			return ctrl.Result{}, err
 		}
 	}
 	if !workloadInstance.IsPostDeploymentSucceeded() {
-		reconcilePostDeployment := func() (common.KeptnState, error) {
-			return r.reconcilePrePostDeployment(ctx, workloadInstance, common.PostDeploymentCheckType)
+		reconcilePostDeployment := func(phaseCtx context.Context) (common.KeptnState, error) {
+			return r.reconcilePrePostDeployment(phaseCtx, workloadInstance, common.PostDeploymentCheckType)
 		}
 		return r.handlePhase(ctx, ctxAppTrace, workloadInstance, phase, span, workloadInstance.IsPostDeploymentFailed, reconcilePostDeployment)
 	}
@@ -221,17 +355,26 @@ func (r *KeptnWorkloadInstanceReconciler) Reconcile(ctx context.Context, req ctr
 		}
 	}
 	if !workloadInstance.IsPostDeploymentEvaluationSucceeded() {
-		reconcilePostEval := func() (common.KeptnState, error) {
-			return r.reconcilePrePostEvaluation(ctx, workloadInstance, common.PostDeploymentEvaluationCheckType)
+		if result, delayed := r.delayPostEvaluation(workloadInstance, phase); delayed {
+			return result, nil
+		}
+		reconcilePostEval := func(phaseCtx context.Context) (common.KeptnState, error) {
+			return r.reconcilePrePostEvaluation(phaseCtx, workloadInstance, common.PostDeploymentEvaluationCheckType)
 		}
 		return r.handlePhase(ctx, ctxAppTrace, workloadInstance, phase, span, workloadInstance.IsPostDeploymentEvaluationFailed, reconcilePostEval)
 	}
 
 	// WorkloadInstance is completed at this place
+	commentIssue := false
+	completed := false
 	if !workloadInstance.IsEndTimeSet() {
 		workloadInstance.Status.CurrentPhase = common.PhaseCompleted.ShortName
 		workloadInstance.Status.Status = common.StateSucceeded
 		workloadInstance.SetEndTime()
+		r.summarizeResources(ctx, span, workloadInstance)
+		r.endWorkloadInstanceSpan(workloadInstance, codes.Ok, "Succeeded")
+		commentIssue = r.markIssueComment(workloadInstance)
+		completed = true
 	}
 
 	err = r.Client.Status().Update(ctx, workloadInstance)
@@ -239,95 +382,99 @@ func (r *KeptnWorkloadInstanceReconciler) Reconcile(ctx context.Context, req ctr
 		span.SetStatus(codes.Error, err.Error())
 		return ctrl.Result{Requeue: true}, err
 	}
+	if commentIssue {
+		r.commentOnIssue(ctx, ctxAppTrace, workloadInstance)
+	}
+	if completed {
+		r.resolveIncidents(ctx, workloadInstance)
+	}
 
 	attrs := workloadInstance.GetMetricsAttributes()
 
 	r.Log.Info("Increasing deployment count")
 	// metrics: increment deployment counter
-	r.Meters.DeploymentCount.Add(ctx, 1, attrs...)
+	r.Meters.Add(ctx, metrics.DeploymentCount, 1, attrs...)
 
 	// metrics: add deployment duration
 	duration := workloadInstance.Status.EndTime.Time.Sub(workloadInstance.Status.StartTime.Time)
-	r.Meters.DeploymentDuration.Record(ctx, duration.Seconds(), attrs...)
+	r.Meters.Record(ctx, metrics.DeploymentDuration, duration.Seconds(), attrs...)
 
-	r.recordEvent(phase, "Normal", workloadInstance, "Finished", "is finished")
+	r.recordEvent(phase, workloadInstance, reasons.Finished)
 
 	return ctrl.Result{}, nil
 }
 
-func (r *KeptnWorkloadInstanceReconciler) GetActiveDeployments(ctx context.Context) ([]common.GaugeValue, error) {
-	workloadInstances := &klcv1alpha1.KeptnWorkloadInstanceList{}
-	err := r.List(ctx, workloadInstances)
-	if err != nil {
-		return nil, fmt.Errorf("could not retrieve workload instances: %w", err)
-	}
-
-	res := []common.GaugeValue{}
-
-	for _, workloadInstance := range workloadInstances.Items {
-		gaugeValue := int64(0)
-		if !workloadInstance.IsEndTimeSet() {
-			gaugeValue = int64(1)
-		}
-		res = append(res, common.GaugeValue{
-			Value:      gaugeValue,
-			Attributes: workloadInstance.GetActiveMetricsAttributes(),
-		})
-	}
-
-	return res, nil
-}
-
-func (r *KeptnWorkloadInstanceReconciler) handlePhase(ctx context.Context, ctxAppTrace context.Context, workloadInstance *klcv1alpha1.KeptnWorkloadInstance, phase common.KeptnPhaseType, span trace.Span, phaseFailed func() bool, reconcilePhase func() (common.KeptnState, error)) (ctrl.Result, error) {
+func (r *KeptnWorkloadInstanceReconciler) handlePhase(ctx context.Context, ctxAppTrace context.Context, workloadInstance *klcv1alpha1.KeptnWorkloadInstance, phase common.KeptnPhaseType, span trace.Span, phaseFailed func() bool, reconcilePhase func(phaseCtx context.Context) (common.KeptnState, error)) (ctrl.Result, error) {
 	r.Log.Info(phase.LongName + " not finished")
 	overallStateUpdated := false
+	commentIssue := false
 	oldstate := workloadInstance.Status.Status
 	oldPhase := workloadInstance.Status.CurrentPhase
 	workloadInstance.Status.CurrentPhase = phase.ShortName
+	if oldPhase != phase.ShortName {
+		workloadInstance.Status.PhaseStartTime = metav1.NewTime(time.Now().UTC())
+	}
 
-	ctxAppTrace, spanAppTrace := r.getSpan(ctxAppTrace, workloadInstance, phase.ShortName)
+	_, spanAppTrace := r.getSpan(ctxAppTrace, workloadInstance, phase.ShortName)
 
 	if phaseFailed() { //TODO eventually we should decide whether a task returns FAILED, currently we never have this status set
-		r.recordEvent(phase, "Warning", workloadInstance, "Failed", "has failed")
+		r.recordEvent(phase, workloadInstance, reasons.Failed)
 		return ctrl.Result{Requeue: true, RequeueAfter: 60 * time.Second}, nil
 	}
-	state, err := reconcilePhase()
+	if timedOut, err := r.reconcilePhaseTimeout(ctx, ctxAppTrace, workloadInstance, phase, spanAppTrace); timedOut {
+		return ctrl.Result{}, err
+	}
+	if klcv1alpha1.SkipsPhase(workloadInstance.Spec.SkipPhases, phase) {
+		reconcilePhase = r.skipPhase(workloadInstance, phase)
+	}
+	if workloadInstance.Spec.SimulateFailure.Matches(phase) {
+		reconcilePhase = r.simulateFailure(workloadInstance, phase)
+	}
+	// spans started by the phase, e.g. for creating tasks, are children of the span of the phase
+	state, err := reconcilePhase(trace.ContextWithSpan(ctx, spanAppTrace))
 	if err != nil {
 		spanAppTrace.AddEvent(phase.LongName + " could not get reconciled")
-		r.recordEvent(phase, "Warning", workloadInstance, "ReconcileErrored", "could not get reconciled")
+		r.recordEvent(phase, workloadInstance, reasons.ReconcileErrored)
 		span.SetStatus(codes.Error, err.Error())
 		return ctrl.Result{Requeue: true}, err
 	}
 	if state.IsSucceeded() {
 		spanAppTrace.AddEvent(phase.LongName + " has succeeded")
-		spanAppTrace.SetStatus(codes.Ok, "Succeeded")
+		spanAppTrace.SetStatus(codes.Ok, reasons.Succeeded.Code)
 		spanAppTrace.End()
 		r.unbindSpan(workloadInstance, phase.ShortName)
-		r.recordEvent(phase, "Normal", workloadInstance, "Succeeded", "has succeeded")
+		r.recordEvent(phase, workloadInstance, reasons.Succeeded)
 	} else if state.IsFailed() {
-		r.recordEvent(phase, "Warning", workloadInstance, "Failed", "has failed")
+		r.recordEvent(phase, workloadInstance, reasons.Failed)
 		workloadInstance.Status.Status = common.StateFailed
 		workloadInstance.SetEndTime()
 
 		attrs := workloadInstance.GetMetricsAttributes()
-		r.Meters.DeploymentCount.Add(ctx, 1, attrs...)
+		r.Meters.Add(ctx, metrics.DeploymentCount, 1, attrs...)
 
 		spanAppTrace.AddEvent(phase.LongName + " has failed")
-		spanAppTrace.SetStatus(codes.Error, "Failed")
+		spanAppTrace.SetStatus(codes.Error, reasons.Failed.Code)
 		spanAppTrace.End()
 		r.unbindSpan(workloadInstance, phase.ShortName)
+		r.endWorkloadInstanceSpan(workloadInstance, codes.Error, "Failed")
 
+		commentIssue = r.markIssueComment(workloadInstance)
+		r.rollbackOnFailure(ctx, workloadInstance)
+		r.openIncident(ctx, ctxAppTrace, workloadInstance)
 		overallStateUpdated = true
 	} else {
 		if oldstate != common.StateProgressing {
 			workloadInstance.Status.Status = common.StateProgressing
 			overallStateUpdated = true
 		}
-		spanAppTrace.AddEvent(phase.LongName + " not finished")
-		r.recordEvent(phase, "Warning", workloadInstance, "NotFinished", "has not finished")
+		// pausing and resuming is recorded by the phase itself
+		if !state.IsPaused() {
+			spanAppTrace.AddEvent(phase.LongName + " not finished")
+			r.recordEvent(phase, workloadInstance, reasons.NotFinished)
+		}
 	}
 	if oldPhase != workloadInstance.Status.CurrentPhase {
-		ctxAppTrace, spanAppTrace = r.getSpan(ctxAppTrace, workloadInstance, workloadInstance.Status.CurrentPhase)
+		_, spanAppTrace = r.getSpan(ctxAppTrace, workloadInstance, workloadInstance.Status.CurrentPhase)
 		semconv.AddAttributeFromWorkloadInstance(spanAppTrace, *workloadInstance)
 		overallStateUpdated = true
 	}
@@ -335,6 +482,8 @@ func (r *KeptnWorkloadInstanceReconciler) handlePhase(ctx context.Context, ctxAp
 	if overallStateUpdated {
 		if err := r.Status().Update(ctx, workloadInstance); err != nil {
 			r.Log.Error(err, "could not update status")
+		} else if commentIssue {
+			r.commentOnIssue(ctx, ctxAppTrace, workloadInstance)
 		}
 	}
 	return ctrl.Result{Requeue: true, RequeueAfter: 5 * time.Second}, nil
@@ -345,7 +494,58 @@ func (r *KeptnWorkloadInstanceReconciler) SetupWithManager(mgr ctrl.Manager) err
 	return ctrl.NewControllerManagedBy(mgr).
 		// predicate disabling the auto reconciliation after updating the object status
 		For(&klcv1alpha1.KeptnWorkloadInstance{}, builder.WithPredicates(predicate.GenerationChangedPredicate{})).
-		Complete(r)
+		Watches(&source.Kind{Type: &appsv1.ReplicaSet{}}, handler.EnqueueRequestsFromMapFunc(r.getWorkloadInstancesForResource), builder.WithPredicates(replicaSetReadinessChanged())).
+		Watches(&source.Kind{Type: &corev1.Pod{}}, handler.EnqueueRequestsFromMapFunc(r.getWorkloadInstancesForResource), builder.WithPredicates(podReadinessChanged())).
+		Watches(&source.Kind{Type: &appsv1.Deployment{}}, handler.EnqueueRequestsFromMapFunc(r.getWorkloadInstancesForDeployment), builder.WithPredicates(deploymentPausedChanged())).
+		Complete(selfmonitoring.Wrap("keptnworkloadinstance", r))
+}
+
+// getWorkloadInstancesForResource returns requests for the workload instances referencing the given ReplicaSet or Pod,
+// or the ReplicaSet owning the given Pod, whose deployment phase has not completed yet
+func (r *KeptnWorkloadInstanceReconciler) getWorkloadInstancesForResource(resource client.Object) []reconcile.Request {
+	uids := map[types.UID]bool{resource.GetUID(): true}
+	for _, owner := range resource.GetOwnerReferences() {
+		uids[owner.UID] = true
+	}
+	return r.getDeployingWorkloadInstances(resource.GetNamespace(), uids)
+}
+
+// getWorkloadInstancesForDeployment returns requests for the workload instances referencing a ReplicaSet of the given
+// Deployment, whose deployment phase has not completed yet
+func (r *KeptnWorkloadInstanceReconciler) getWorkloadInstancesForDeployment(deployment client.Object) []reconcile.Request {
+	replicaSets := &appsv1.ReplicaSetList{}
+	if err := r.Client.List(context.TODO(), replicaSets, client.InNamespace(deployment.GetNamespace())); err != nil {
+		r.Log.Error(err, "could not retrieve ReplicaSets")
+		return nil
+	}
+
+	uids := map[types.UID]bool{}
+	for _, rs := range replicaSets.Items {
+		for _, owner := range rs.OwnerReferences {
+			if owner.UID == deployment.GetUID() {
+				uids[rs.UID] = true
+			}
+		}
+	}
+	return r.getDeployingWorkloadInstances(deployment.GetNamespace(), uids)
+}
+
+// getDeployingWorkloadInstances returns requests for the workload instances referencing one of the given resources,
+// whose deployment phase has not completed yet
+func (r *KeptnWorkloadInstanceReconciler) getDeployingWorkloadInstances(namespace string, uids map[types.UID]bool) []reconcile.Request {
+	workloadInstances := &klcv1alpha1.KeptnWorkloadInstanceList{}
+	if err := r.Client.List(context.TODO(), workloadInstances, client.InNamespace(namespace)); err != nil {
+		r.Log.Error(err, "could not retrieve KeptnWorkloadInstances")
+		return nil
+	}
+
+	var requests []reconcile.Request
+	for _, wli := range workloadInstances.Items {
+		if uids[wli.Spec.ResourceReference.UID] && !wli.IsDeploymentCompleted() && !wli.IsEndTimeSet() {
+			requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{Namespace: wli.Namespace, Name: wli.Name}})
+		}
+	}
+	return requests
 }
 
 func (r *KeptnWorkloadInstanceReconciler) generateSuffix() string {
@@ -353,12 +553,32 @@ func (r *KeptnWorkloadInstanceReconciler) generateSuffix() string {
 	return uid[:10]
 }
 
-func (r *KeptnWorkloadInstanceReconciler) recordEvent(phase common.KeptnPhaseType, eventType string, workloadInstance *klcv1alpha1.KeptnWorkloadInstance, shortReason string, longReason string) {
-	r.Recorder.Event(workloadInstance, eventType, fmt.Sprintf("%s%s", phase.ShortName, shortReason), fmt.Sprintf("%s %s / Namespace: %s, Name: %s, Version: %s ", phase.LongName, longReason, workloadInstance.Namespace, workloadInstance.Name, workloadInstance.Spec.Version))
+// delayPostEvaluation returns the result requeueing the workload instance once the post-deployment evaluation delay has
+// passed since the deployment succeeded, and whether the post-deployment evaluations still have to wait for it
+func (r *KeptnWorkloadInstanceReconciler) delayPostEvaluation(workloadInstance *klcv1alpha1.KeptnWorkloadInstance, phase common.KeptnPhaseType) (ctrl.Result, bool) {
+	delay := workloadInstance.GetRemainingPostDeploymentEvaluationDelay()
+	if delay <= 0 || len(workloadInstance.Status.PostDeploymentEvaluationTaskStatus) > 0 || klcv1alpha1.SkipsPhase(workloadInstance.Spec.SkipPhases, phase) {
+		return ctrl.Result{}, false
+	}
+	r.recordEvent(phase, workloadInstance, reasons.Delayed, delay.Round(time.Second))
+	return ctrl.Result{Requeue: true, RequeueAfter: delay}, true
+}
+
+// phaseWorkloadInstance is the phase of the events which concern the workload instance as a whole, so that their reason
+// is the code of the catalog without the prefix of a phase
+var phaseWorkloadInstance = common.KeptnPhaseType{LongName: "WorkloadInstance"}
+
+// recordEvent records an event of the given reason of the catalog for the phase, whose message is formatted with args
+func (r *KeptnWorkloadInstanceReconciler) recordEvent(phase common.KeptnPhaseType, workloadInstance *klcv1alpha1.KeptnWorkloadInstance, reason reasons.Reason, args ...interface{}) {
+	longReason := reason.Message(args...)
+	r.Recorder.AnnotatedEventf(workloadInstance, reason.Annotations(), string(reason.Severity), fmt.Sprintf("%s%s", phase.ShortName, reason.Code), "%s %s / Namespace: %s, Name: %s, Version: %s ", phase.LongName, longReason, workloadInstance.Namespace, workloadInstance.Name, workloadInstance.Spec.Version)
+	if r.DebugStatus {
+		workloadInstance.Status.Debug = debug.NewDecision(phase.ShortName, fmt.Sprintf("%s%s: %s %s", phase.ShortName, reason.Code, phase.LongName, longReason))
+	}
 }
 
 func GetAppVersionName(namespace string, appName string, version string) types.NamespacedName {
-	return types.NamespacedName{Namespace: namespace, Name: appName + "-" + version}
+	return types.NamespacedName{Namespace: namespace, Name: common.CreateInstanceName(appName, version)}
 }
 
 func (r *KeptnWorkloadInstanceReconciler) getAppVersion(ctx context.Context, appName types.NamespacedName) (*klcv1alpha1.KeptnAppVersion, error) {
@@ -370,7 +590,10 @@ func (r *KeptnWorkloadInstanceReconciler) getAppVersion(ctx context.Context, app
 
 	appVersion := &klcv1alpha1.KeptnAppVersion{}
 	err = r.Get(ctx, GetAppVersionName(appName.Namespace, appName.Name, app.Spec.Version), appVersion)
-	return appVersion, err
+	if err != nil {
+		return appVersion, err
+	}
+	return appVersion, appVersion.VerifyIdentity(appName.Name, app.Spec.Version)
 }
 
 func (r *KeptnWorkloadInstanceReconciler) getAppVersionForWorkloadInstance(ctx context.Context, wli *klcv1alpha1.KeptnWorkloadInstance) (bool, klcv1alpha1.KeptnAppVersion, error) {
@@ -382,14 +605,10 @@ func (r *KeptnWorkloadInstanceReconciler) getAppVersionForWorkloadInstance(ctx c
 	for _, app := range apps.Items {
 		if app.Spec.AppName == wli.Spec.AppName {
 			for _, appWorkload := range app.Spec.Workloads {
-				workloadName := fmt.Sprintf("%s-%s", app.Spec.AppName, appWorkload.Name)
+				workloadName := common.CreateResourceName(common.MaxK8sObjectLength, app.Spec.AppName, appWorkload.Name)
 				if appWorkload.Version == wli.Spec.Version && workloadName == wli.Spec.WorkloadName {
-					if latestVersion.Spec.Version == "" {
+					if latestVersion.Spec.Version == "" || compareVersions(latestVersion.Spec.Version, app.Spec.Version) < 0 {
 						latestVersion = app
-					} else {
-						if semver.Compare(latestVersion.Spec.Version, app.Spec.Version) < 0 {
-							latestVersion = app
-						}
 					}
 				}
 			}
@@ -403,22 +622,59 @@ func (r *KeptnWorkloadInstanceReconciler) getAppVersionForWorkloadInstance(ctx c
 	return true, latestVersion, nil
 }
 
+// compareVersions compares two versions of an app as semantic versions, which may omit the "v" prefix, e.g. 1.2.0.
+// Other versions are lower than semantic versions and compared lexically, so that the order does not depend on the
+// order in which the app versions are listed.
+func compareVersions(a string, b string) int {
+	semverA, semverB := canonicalVersion(a), canonicalVersion(b)
+	validA, validB := semver.IsValid(semverA), semver.IsValid(semverB)
+	switch {
+	case validA && validB:
+		if c := semver.Compare(semverA, semverB); c != 0 {
+			return c
+		}
+	case validA:
+		return 1
+	case validB:
+		return -1
+	}
+	return strings.Compare(a, b)
+}
+
+func canonicalVersion(version string) string {
+	if strings.HasPrefix(version, "v") {
+		return version
+	}
+	return "v" + version
+}
+
 func (r *KeptnWorkloadInstanceReconciler) getSpan(ctx context.Context, wli *klcv1alpha1.KeptnWorkloadInstance, phase string) (context.Context, trace.Span) {
 	wliName := r.getSpanName(wli, phase)
-	spanName := fmt.Sprintf("%s/%s", wli.Spec.WorkloadName, phase)
 
 	if r.bindCRDSpan == nil {
 		r.bindCRDSpan = make(map[string]trace.Span)
 	}
 	if span, ok := r.bindCRDSpan[wliName]; ok {
-		return ctx, span
+		return trace.ContextWithSpan(ctx, span), span
 	}
 	r.Log.Info("DEBUG: Start Span: " + wliName)
-	ctx, span := r.Tracer.Start(ctx, spanName, trace.WithSpanKind(trace.SpanKindConsumer))
-	r.bindCRDSpan[wliName] = span
+	ctx, span := r.Tracer.Start(ctx, phase, trace.WithSpanKind(trace.SpanKindConsumer))
+	// spans that are not recorded, e.g. if tracing is disabled, do not have to be kept until they end
+	if span.IsRecording() {
+		r.bindCRDSpan[wliName] = span
+	}
 	return ctx, span
 }
 
+// endWorkloadInstanceSpan ends the span of the workload instance once all of its phases are finished
+func (r *KeptnWorkloadInstanceReconciler) endWorkloadInstanceSpan(wli *klcv1alpha1.KeptnWorkloadInstance, code codes.Code, description string) {
+	if span, ok := r.bindCRDSpan[r.getSpanName(wli, semconv.WorkloadInstanceSpanName)]; ok {
+		span.SetStatus(code, description)
+		span.End()
+		r.unbindSpan(wli, semconv.WorkloadInstanceSpanName)
+	}
+}
+
 func (r *KeptnWorkloadInstanceReconciler) unbindSpan(wli *klcv1alpha1.KeptnWorkloadInstance, phase string) {
 	delete(r.bindCRDSpan, r.getSpanName(wli, phase))
 }
@@ -426,51 +682,3 @@ func (r *KeptnWorkloadInstanceReconciler) unbindSpan(wli *klcv1alpha1.KeptnWorkl
 func (r *KeptnWorkloadInstanceReconciler) getSpanName(wli *klcv1alpha1.KeptnWorkloadInstance, phase string) string {
 	return fmt.Sprintf("%s.%s.%s.%s.%s", wli.Spec.TraceId, wli.Spec.AppName, wli.Spec.WorkloadName, wli.Spec.Version, phase)
 }
-
-func (r *KeptnWorkloadInstanceReconciler) GetDeploymentInterval(ctx context.Context) ([]common.GaugeFloatValue, error) {
-	workloadInstances := &klcv1alpha1.KeptnWorkloadInstanceList{}
-	err := r.List(ctx, workloadInstances)
-	if err != nil {
-		return nil, fmt.Errorf("could not retrieve workload instances: %w", err)
-	}
-
-	res := []common.GaugeFloatValue{}
-	for _, workloadInstance := range workloadInstances.Items {
-		if workloadInstance.Spec.PreviousVersion != "" {
-			previousWorkloadInstance := &klcv1alpha1.KeptnWorkloadInstance{}
-			err := r.Get(ctx, types.NamespacedName{Name: fmt.Sprintf("%s-%s", workloadInstance.Spec.WorkloadName, workloadInstance.Spec.PreviousVersion), Namespace: workloadInstance.Namespace}, previousWorkloadInstance)
-			if err != nil {
-				r.Log.Error(err, "Previous WorkloadInstance not found")
-			} else if workloadInstance.IsEndTimeSet() {
-				previousInterval := workloadInstance.Status.StartTime.Time.Sub(previousWorkloadInstance.Status.EndTime.Time)
-				res = append(res, common.GaugeFloatValue{
-					Value:      previousInterval.Seconds(),
-					Attributes: workloadInstance.GetIntervalMetricsAttributes(),
-				})
-			}
-		}
-	}
-	return res, nil
-}
-
-func (r *KeptnWorkloadInstanceReconciler) GetDeploymentDuration(ctx context.Context) ([]common.GaugeFloatValue, error) {
-	workloadInstances := &klcv1alpha1.KeptnWorkloadInstanceList{}
-	err := r.List(ctx, workloadInstances)
-	if err != nil {
-		return nil, fmt.Errorf("could not retrieve workload instances: %w", err)
-	}
-
-	res := []common.GaugeFloatValue{}
-
-	for _, workloadInstance := range workloadInstances.Items {
-		if workloadInstance.IsEndTimeSet() {
-			duration := workloadInstance.Status.EndTime.Time.Sub(workloadInstance.Status.StartTime.Time)
-			res = append(res, common.GaugeFloatValue{
-				Value:      duration.Seconds(),
-				Attributes: workloadInstance.GetIntervalMetricsAttributes(),
-			})
-		}
-	}
-
-	return res, nil
-}
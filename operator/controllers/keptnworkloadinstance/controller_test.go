@@ -2,14 +2,38 @@ package keptnworkloadinstance
 
 import (
 	"context"
+	"net/http"
+	"net/http/httptest"
 	"testing"
+	"time"
+	"unicode/utf8"
 
+	"github.com/go-logr/logr"
+	"github.com/go-logr/logr/funcr"
 	"github.com/keptn/lifecycle-controller/operator/api/v1alpha1"
+	"github.com/keptn/lifecycle-controller/operator/api/v1alpha1/common"
+	"github.com/keptn/lifecycle-controller/operator/api/v1alpha1/semconv"
+	"github.com/keptn/lifecycle-controller/operator/controllers/reasons"
+	"github.com/keptn/lifecycle-controller/operator/integrations/cost"
+	"github.com/keptn/lifecycle-controller/operator/integrations/incident"
+	"github.com/keptn/lifecycle-controller/operator/integrations/jira"
+	"github.com/keptn/lifecycle-controller/operator/integrations/servicenow"
+	"github.com/keptn/lifecycle-controller/operator/metrics"
 	testrequire "github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+	appsv1 "k8s.io/api/apps/v1"
 	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/event"
 )
 
 func TestKeptnWorkloadInstanceReconciler_IsPodRunning(t *testing.T) {
@@ -37,6 +61,80 @@ func TestKeptnWorkloadInstanceReconciler_IsPodRunning(t *testing.T) {
 
 }
 
+func TestDiffPodSpecs(t *testing.T) {
+	previous := v1.PodSpec{
+		Containers: []v1.Container{
+			{
+				Name:  "app",
+				Image: "app:1.0",
+				Env:   []v1.EnvVar{{Name: "LOG_LEVEL", Value: "info"}, {Name: "OLD", Value: "x"}},
+				Resources: v1.ResourceRequirements{
+					Limits: v1.ResourceList{v1.ResourceCPU: resource.MustParse("100m")},
+				},
+			},
+			{Name: "sidecar", Image: "sidecar:1.0"},
+		},
+	}
+	current := v1.PodSpec{
+		Containers: []v1.Container{
+			{
+				Name:  "app",
+				Image: "app:1.1",
+				Env:   []v1.EnvVar{{Name: "LOG_LEVEL", Value: "debug"}, {Name: "NEW", Value: "y"}},
+				Resources: v1.ResourceRequirements{
+					Limits: v1.ResourceList{v1.ResourceCPU: resource.MustParse("200m")},
+				},
+			},
+		},
+	}
+
+	changes := diffPodSpecs(previous, current)
+	testrequire.Equal(t, []string{
+		"container app: cpu limit changed from 100m to 200m",
+		"container app: env LOG_LEVEL changed",
+		"container app: env NEW added",
+		"container app: env OLD removed",
+		"container app: image changed from app:1.0 to app:1.1",
+		"container sidecar: removed",
+	}, changes)
+
+	testrequire.Empty(t, diffPodSpecs(current, current))
+}
+
+func TestNewResourceSummary(t *testing.T) {
+	podSpec := v1.PodSpec{
+		Containers: []v1.Container{
+			{
+				Name: "app",
+				Resources: v1.ResourceRequirements{
+					Requests: v1.ResourceList{v1.ResourceCPU: resource.MustParse("250m"), v1.ResourceMemory: resource.MustParse("128Mi")},
+					Limits:   v1.ResourceList{v1.ResourceCPU: resource.MustParse("1"), v1.ResourceMemory: resource.MustParse("256Mi")},
+				},
+			},
+			{
+				Name: "sidecar",
+				Resources: v1.ResourceRequirements{
+					Requests: v1.ResourceList{v1.ResourceCPU: resource.MustParse("50m")},
+				},
+			},
+		},
+	}
+
+	summary := newResourceSummary(podSpec, 3)
+	testrequire.Equal(t, int32(3), summary.Replicas)
+	testrequire.Equal(t, "900m", summary.Requests.Cpu().String())
+	testrequire.Equal(t, "384Mi", summary.Requests.Memory().String())
+	testrequire.Equal(t, "3", summary.Limits.Cpu().String())
+	testrequire.Equal(t, "768Mi", summary.Limits.Memory().String())
+
+	previous := newResourceSummary(podSpec, 2)
+	delta := diffResourceLists(previous.Requests, summary.Requests)
+	testrequire.Equal(t, 0.3, cores(delta))
+	testrequire.Equal(t, int64(128<<20), bytes(delta))
+	removed := diffResourceLists(summary.Limits, v1.ResourceList{})
+	testrequire.Equal(t, int64(-768<<20), bytes(removed))
+}
+
 func makeNominatedPod(podName string, nodeName string, phase v1.PodPhase) v1.Pod {
 	return v1.Pod{
 		ObjectMeta: metav1.ObjectMeta{
@@ -50,3 +148,822 @@ func makeNominatedPod(podName string, nodeName string, phase v1.PodPhase) v1.Pod
 		},
 	}
 }
+
+func TestKeptnWorkloadInstanceReconciler_ArePodsReady(t *testing.T) {
+	meshed := makeNominatedPod("pod1", "node1", v1.PodRunning)
+	meshed.OwnerReferences = []metav1.OwnerReference{{UID: types.UID("rs1")}}
+	meshed.Spec.Containers = []v1.Container{{Name: "app"}, {Name: "istio-proxy"}}
+	meshed.Status.Conditions = []v1.PodCondition{
+		{Type: v1.PodInitialized, Status: v1.ConditionTrue},
+		{Type: v1.ContainersReady, Status: v1.ConditionFalse},
+		{Type: v1.PodReady, Status: v1.ConditionFalse},
+	}
+	meshed.Status.ContainerStatuses = []v1.ContainerStatus{{Name: "app", Ready: true}, {Name: "istio-proxy", Ready: false}}
+
+	r := &KeptnWorkloadInstanceReconciler{
+		Client: fake.NewClientBuilder().WithLists(&v1.PodList{Items: []v1.Pod{meshed}}).Build(),
+	}
+	rs := v1alpha1.ResourceReference{UID: types.UID("rs1"), Kind: "ReplicaSet"}
+
+	ready, err := r.arePodsReady(context.TODO(), rs, "node1", v1alpha1.ReadinessCheckContainersReady, 1)
+	testrequire.Nil(t, err)
+	testrequire.False(t, ready)
+
+	ready, err = r.arePodsReady(context.TODO(), rs, "node1", v1alpha1.ReadinessCheckPodReady, 1)
+	testrequire.Nil(t, err)
+	testrequire.False(t, ready)
+
+	meshed.Status.Conditions[1].Status = v1.ConditionTrue
+	meshed.Status.Conditions[2].Status = v1.ConditionTrue
+	meshed.Status.ContainerStatuses[1].Ready = true
+	r.Client = fake.NewClientBuilder().WithLists(&v1.PodList{Items: []v1.Pod{meshed}}).Build()
+
+	ready, err = r.arePodsReady(context.TODO(), rs, "node1", v1alpha1.ReadinessCheckContainersReady, 1)
+	testrequire.Nil(t, err)
+	testrequire.True(t, ready)
+
+	ready, err = r.arePodsReady(context.TODO(), rs, "node1", v1alpha1.ReadinessCheckPodReady, 2)
+	testrequire.Nil(t, err)
+	testrequire.False(t, ready)
+}
+
+func TestKeptnWorkloadInstanceReconciler_GetWorkloadInstancesForResource(t *testing.T) {
+	scheme := runtime.NewScheme()
+	testrequire.Nil(t, v1alpha1.AddToScheme(scheme))
+	deploying := &v1alpha1.KeptnWorkloadInstance{
+		ObjectMeta: metav1.ObjectMeta{Name: "podtato-head-frontend-0.2.0", Namespace: "default"},
+		Spec: v1alpha1.KeptnWorkloadInstanceSpec{
+			KeptnWorkloadSpec: v1alpha1.KeptnWorkloadSpec{ResourceReference: v1alpha1.ResourceReference{UID: "rs2", Kind: "ReplicaSet"}},
+		},
+		Status: v1alpha1.KeptnWorkloadInstanceStatus{DeploymentStatus: common.StateProgressing},
+	}
+	deployed := &v1alpha1.KeptnWorkloadInstance{
+		ObjectMeta: metav1.ObjectMeta{Name: "podtato-head-frontend-0.1.0", Namespace: "default"},
+		Spec: v1alpha1.KeptnWorkloadInstanceSpec{
+			KeptnWorkloadSpec: v1alpha1.KeptnWorkloadSpec{ResourceReference: v1alpha1.ResourceReference{UID: "rs1", Kind: "ReplicaSet"}},
+		},
+		Status: v1alpha1.KeptnWorkloadInstanceStatus{DeploymentStatus: common.StateSucceeded},
+	}
+	r := &KeptnWorkloadInstanceReconciler{
+		Client: fake.NewClientBuilder().WithScheme(scheme).WithObjects(deploying, deployed).Build(),
+		Log:    logr.Discard(),
+	}
+
+	pod := makeNominatedPod("frontend-abcde", "default", v1.PodRunning)
+	pod.OwnerReferences = []metav1.OwnerReference{{Kind: "ReplicaSet", UID: "rs2"}}
+	requests := r.getWorkloadInstancesForResource(&pod)
+	testrequire.Len(t, requests, 1)
+	testrequire.Equal(t, "podtato-head-frontend-0.2.0", requests[0].Name)
+
+	pod.OwnerReferences = []metav1.OwnerReference{{Kind: "ReplicaSet", UID: "rs1"}}
+	testrequire.Empty(t, r.getWorkloadInstancesForResource(&pod))
+}
+
+func TestKeptnWorkloadInstanceReconciler_ReconcileMigration(t *testing.T) {
+	scheme := runtime.NewScheme()
+	testrequire.Nil(t, v1alpha1.AddToScheme(scheme))
+	makeInstance := func(version string) *v1alpha1.KeptnWorkloadInstance {
+		return &v1alpha1.KeptnWorkloadInstance{
+			ObjectMeta: metav1.ObjectMeta{Name: "podtato-head-frontend-" + version, Namespace: "default"},
+			Spec: v1alpha1.KeptnWorkloadInstanceSpec{
+				KeptnWorkloadSpec: v1alpha1.KeptnWorkloadSpec{Version: version, MigrationTasks: []string{"migrate-schema", "backfill"}},
+				WorkloadName:      "podtato-head-frontend",
+			},
+			Status: v1alpha1.KeptnWorkloadInstanceStatus{MigrationStatus: common.StateProgressing},
+		}
+	}
+	migrating := makeInstance("0.1.0")
+	migrating.Status.MigrationTaskStatus = []v1alpha1.TaskStatus{{TaskDefinitionName: "migrate-schema", TaskName: "migration-migrate-schema-12345", Status: common.StateProgressing}}
+	workloadInstance := makeInstance("0.2.0")
+	r := &KeptnWorkloadInstanceReconciler{
+		Client:   fake.NewClientBuilder().WithScheme(scheme).WithObjects(migrating, workloadInstance).Build(),
+		Scheme:   scheme,
+		Recorder: record.NewFakeRecorder(100),
+		Log:      logr.Discard(),
+		Tracer:   trace.NewNoopTracerProvider().Tracer("test"),
+	}
+	ctx := context.TODO()
+
+	reconcileMigration := func() common.KeptnState {
+		newStatus, summary, err := r.reconcileMigrationTasks(ctx, workloadInstance, false)
+		testrequire.Nil(t, err)
+		workloadInstance.Status.MigrationTaskStatus = newStatus
+		return common.GetOverallState(summary)
+	}
+
+	// the migration waits while another version of the workload is migrating
+	testrequire.Equal(t, common.StatePending, reconcileMigration())
+	testrequire.Len(t, workloadInstance.Status.MigrationTaskStatus, 2)
+	testrequire.Empty(t, workloadInstance.Status.MigrationTaskStatus[0].TaskName)
+	testrequire.Contains(t, workloadInstance.Status.MigrationTaskStatus[0].Reason, "0.1.0")
+
+	// the migration tasks run one after the other and are not retried
+	migrating.Status.MigrationStatus = common.StateSucceeded
+	testrequire.Nil(t, r.Client.Update(ctx, migrating))
+	reconcileMigration()
+	first := &v1alpha1.KeptnTask{}
+	testrequire.Nil(t, r.Client.Get(ctx, types.NamespacedName{Namespace: "default", Name: workloadInstance.Status.MigrationTaskStatus[0].TaskName}, first))
+	testrequire.Equal(t, common.MigrationCheckType, first.Spec.Type)
+	testrequire.Equal(t, int32(0), *first.Spec.Retries)
+	testrequire.Empty(t, workloadInstance.Status.MigrationTaskStatus[1].TaskName)
+
+	first.Status.Status = common.StateSucceeded
+	testrequire.Nil(t, r.Client.Update(ctx, first))
+	reconcileMigration()
+	testrequire.NotEmpty(t, workloadInstance.Status.MigrationTaskStatus[1].TaskName)
+
+	// a deleted migration task fails the migration instead of being started again
+	second := &v1alpha1.KeptnTask{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: workloadInstance.Status.MigrationTaskStatus[1].TaskName}}
+	testrequire.Nil(t, r.Client.Delete(ctx, second))
+	testrequire.Equal(t, common.StateFailed, reconcileMigration())
+	testrequire.Equal(t, common.StateSucceeded, workloadInstance.Status.MigrationTaskStatus[0].Status)
+	testrequire.Equal(t, common.StateFailed, workloadInstance.Status.MigrationTaskStatus[1].Status)
+}
+
+func TestMigrationLockHolder(t *testing.T) {
+	makeInstance := func(name string, created time.Time, state common.KeptnState, started bool) v1alpha1.KeptnWorkloadInstance {
+		instance := v1alpha1.KeptnWorkloadInstance{
+			ObjectMeta: metav1.ObjectMeta{Name: name, CreationTimestamp: metav1.NewTime(created)},
+			Spec: v1alpha1.KeptnWorkloadInstanceSpec{
+				KeptnWorkloadSpec: v1alpha1.KeptnWorkloadSpec{MigrationTasks: []string{"migrate-schema"}},
+			},
+			Status: v1alpha1.KeptnWorkloadInstanceStatus{MigrationStatus: state},
+		}
+		if started {
+			instance.Status.MigrationTaskStatus = []v1alpha1.TaskStatus{{TaskDefinitionName: "migrate-schema", TaskName: "migration-migrate-schema-12345"}}
+		}
+		return instance
+	}
+	now := time.Now()
+
+	testrequire.Nil(t, migrationLockHolder([]v1alpha1.KeptnWorkloadInstance{makeInstance("done", now, common.StateSucceeded, true)}))
+	holder := migrationLockHolder([]v1alpha1.KeptnWorkloadInstance{
+		makeInstance("newer", now, common.StateProgressing, false),
+		makeInstance("older", now.Add(-time.Hour), common.StateProgressing, false),
+	})
+	testrequire.Equal(t, "older", holder.Name)
+	// an instance which has started its migration keeps the lock
+	holder = migrationLockHolder([]v1alpha1.KeptnWorkloadInstance{
+		makeInstance("older", now.Add(-time.Hour), common.StateProgressing, false),
+		makeInstance("newer", now, common.StateProgressing, true),
+	})
+	testrequire.Equal(t, "newer", holder.Name)
+}
+
+func TestKeptnWorkloadInstanceReconciler_IsDeploymentPaused(t *testing.T) {
+	scheme := runtime.NewScheme()
+	testrequire.Nil(t, v1alpha1.AddToScheme(scheme))
+	testrequire.Nil(t, appsv1.AddToScheme(scheme))
+	deployment := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: "frontend", Namespace: "default", UID: "deployment1"},
+		Spec:       appsv1.DeploymentSpec{Paused: true},
+	}
+	replicaSet := &appsv1.ReplicaSet{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            "frontend-5f7b",
+			Namespace:       "default",
+			UID:             "rs2",
+			OwnerReferences: []metav1.OwnerReference{{Kind: "Deployment", Name: "frontend", UID: "deployment1"}},
+		},
+	}
+	deploying := &v1alpha1.KeptnWorkloadInstance{
+		ObjectMeta: metav1.ObjectMeta{Name: "podtato-head-frontend-0.2.0", Namespace: "default"},
+		Spec: v1alpha1.KeptnWorkloadInstanceSpec{
+			KeptnWorkloadSpec: v1alpha1.KeptnWorkloadSpec{ResourceReference: v1alpha1.ResourceReference{UID: "rs2", Kind: "ReplicaSet"}},
+		},
+		Status: v1alpha1.KeptnWorkloadInstanceStatus{DeploymentStatus: common.StateDeploymentPaused},
+	}
+	r := &KeptnWorkloadInstanceReconciler{
+		Client: fake.NewClientBuilder().WithScheme(scheme).WithObjects(deployment, replicaSet, deploying).Build(),
+		Log:    logr.Discard(),
+	}
+
+	paused, err := r.isDeploymentPaused(context.TODO(), deploying.Spec.ResourceReference, "default")
+	testrequire.Nil(t, err)
+	testrequire.True(t, paused)
+
+	paused, err = r.isDeploymentPaused(context.TODO(), v1alpha1.ResourceReference{UID: "rs1", Kind: "ReplicaSet"}, "default")
+	testrequire.Nil(t, err)
+	testrequire.False(t, paused)
+
+	requests := r.getWorkloadInstancesForDeployment(deployment)
+	testrequire.Len(t, requests, 1)
+	testrequire.Equal(t, "podtato-head-frontend-0.2.0", requests[0].Name)
+
+	resumed := deployment.DeepCopy()
+	resumed.Spec.Paused = false
+	predicate := deploymentPausedChanged()
+	testrequire.True(t, predicate.Update(event.UpdateEvent{ObjectOld: deployment, ObjectNew: resumed}))
+	testrequire.False(t, predicate.Update(event.UpdateEvent{ObjectOld: resumed, ObjectNew: resumed}))
+}
+
+func TestKeptnWorkloadInstanceReconciler_ReconcileChangeRequest(t *testing.T) {
+	state := servicenow.StateScheduled
+	checks := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		checks++
+		_, _ = w.Write([]byte(`{"result":[{"number":"CHG0030001","state":"` + state + `","approval":"approved","end_date":"2099-01-01 00:00:00"}]}`))
+	}))
+	defer server.Close()
+	r := &KeptnWorkloadInstanceReconciler{
+		Recorder:      record.NewFakeRecorder(100),
+		Log:           logr.Discard(),
+		ChangeManager: servicenow.NewClient(server.URL, "bot", "secret"),
+	}
+	workloadInstance := &v1alpha1.KeptnWorkloadInstance{
+		Spec: v1alpha1.KeptnWorkloadInstanceSpec{
+			KeptnWorkloadSpec: v1alpha1.KeptnWorkloadSpec{ChangeRequest: "CHG0030001"},
+		},
+	}
+	now := time.Now()
+
+	testrequire.Equal(t, common.StateProgressing, r.reconcileChangeRequest(context.TODO(), workloadInstance, now))
+	testrequire.Equal(t, 1, checks)
+
+	// the change request is not checked again before the backoff has passed
+	state = servicenow.StateImplement
+	testrequire.Equal(t, common.StateProgressing, r.reconcileChangeRequest(context.TODO(), workloadInstance, now.Add(ChangeRequestMinBackoff/2)))
+	testrequire.Equal(t, 1, checks)
+
+	testrequire.Equal(t, common.StateSucceeded, r.reconcileChangeRequest(context.TODO(), workloadInstance, now.Add(ChangeRequestMinBackoff)))
+	testrequire.Equal(t, 2, checks)
+	testrequire.Equal(t, int32(2), workloadInstance.Status.ChangeRequest.Checks)
+
+	canceled := &v1alpha1.KeptnWorkloadInstance{
+		Spec: v1alpha1.KeptnWorkloadInstanceSpec{
+			KeptnWorkloadSpec: v1alpha1.KeptnWorkloadSpec{ChangeRequest: "CHG0030001"},
+		},
+	}
+	state = servicenow.StateCanceled
+	testrequire.Equal(t, common.StateFailed, r.reconcileChangeRequest(context.TODO(), canceled, now))
+	testrequire.Contains(t, canceled.Status.ChangeRequest.Reason, "canceled")
+
+	r.ChangeManager = nil
+	unconfigured := canceled.DeepCopy()
+	unconfigured.Status.ChangeRequest = nil
+	testrequire.Equal(t, common.StateFailed, r.reconcileChangeRequest(context.TODO(), unconfigured, now))
+}
+
+func TestKeptnWorkloadInstanceReconciler_SkipPreDeploymentChangeRequest(t *testing.T) {
+	state := servicenow.StateScheduled
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"result":[{"number":"CHG0030001","state":"` + state + `","approval":"approved","end_date":"2099-01-01 00:00:00"}]}`))
+	}))
+	defer server.Close()
+	scheme := runtime.NewScheme()
+	testrequire.Nil(t, v1alpha1.AddToScheme(scheme))
+	r := &KeptnWorkloadInstanceReconciler{
+		Client:        fake.NewClientBuilder().WithScheme(scheme).Build(),
+		Recorder:      record.NewFakeRecorder(100),
+		Log:           logr.Discard(),
+		ChangeManager: servicenow.NewClient(server.URL, "bot", "secret"),
+	}
+	newWorkloadInstance := func() *v1alpha1.KeptnWorkloadInstance {
+		return &v1alpha1.KeptnWorkloadInstance{
+			ObjectMeta: metav1.ObjectMeta{Name: "some-wi", Namespace: "default"},
+			Spec: v1alpha1.KeptnWorkloadInstanceSpec{
+				KeptnWorkloadSpec: v1alpha1.KeptnWorkloadSpec{
+					ChangeRequest: "CHG0030001",
+					SkipPhases:    []v1alpha1.SkippedPhase{v1alpha1.SkippedPhase(v1alpha1.SimulatedFailurePreDeployment)},
+				},
+			},
+		}
+	}
+	phase := common.PhaseWorkloadPreDeployment
+
+	// the workload instance is not stored, so only the status set in memory is checked
+	scheduled := newWorkloadInstance()
+	result, _ := r.skipPhase(scheduled, phase)(context.TODO())
+	testrequire.Equal(t, common.StateProgressing, result)
+	testrequire.Equal(t, common.StateProgressing, scheduled.Status.PreDeploymentStatus)
+	testrequire.Equal(t, int32(1), scheduled.Status.ChangeRequest.Checks)
+
+	state = servicenow.StateImplement
+	implement := newWorkloadInstance()
+	result, err := r.skipPhase(implement, phase)(context.TODO())
+	testrequire.Nil(t, err)
+	testrequire.Equal(t, common.StateSucceeded, result)
+	testrequire.Equal(t, common.StateSucceeded, implement.Status.PreDeploymentStatus)
+}
+
+func TestChangeRequestBackoff(t *testing.T) {
+	testrequire.Equal(t, ChangeRequestMinBackoff, changeRequestBackoff(1))
+	testrequire.Equal(t, 4*ChangeRequestMinBackoff, changeRequestBackoff(3))
+	testrequire.Equal(t, ChangeRequestMaxBackoff, changeRequestBackoff(100))
+}
+
+func TestKeptnWorkloadInstanceReconciler_MarkIssueComment(t *testing.T) {
+	r := &KeptnWorkloadInstanceReconciler{}
+	wi := &v1alpha1.KeptnWorkloadInstance{Spec: v1alpha1.KeptnWorkloadInstanceSpec{KeptnWorkloadSpec: v1alpha1.KeptnWorkloadSpec{Issue: "KEPTN-1"}}}
+	testrequire.False(t, r.markIssueComment(wi))
+	testrequire.False(t, wi.Status.IssueCommented)
+
+	r.IssueTracker = jira.NewClient("https://jira.example.com", "user", "token", "")
+	testrequire.True(t, r.markIssueComment(wi))
+	testrequire.True(t, wi.Status.IssueCommented)
+	// the comment is posted only once, even if the workload instance is reconciled again
+	testrequire.False(t, r.markIssueComment(wi))
+
+	noIssue := &v1alpha1.KeptnWorkloadInstance{}
+	testrequire.False(t, r.markIssueComment(noIssue))
+}
+
+func TestKeptnWorkloadInstanceReconciler_EstimateCostDelta(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"status":"success","data":{"resultType":"vector","result":[{"metric":{},"value":[1666000000,"1"]}]}}`))
+	}))
+	defer server.Close()
+	estimator, err := cost.NewOpenCostEstimator(server.URL)
+	testrequire.Nil(t, err)
+
+	podWithCPU := func(name string, cpu string) *v1.Pod {
+		return &v1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default", UID: types.UID(name)},
+			Spec: v1.PodSpec{Containers: []v1.Container{{
+				Name:      "app",
+				Resources: v1.ResourceRequirements{Requests: v1.ResourceList{v1.ResourceCPU: resource.MustParse(cpu)}},
+			}}},
+		}
+	}
+	previous := &v1alpha1.KeptnWorkloadInstance{Spec: v1alpha1.KeptnWorkloadInstanceSpec{
+		KeptnWorkloadSpec: v1alpha1.KeptnWorkloadSpec{ResourceReference: v1alpha1.ResourceReference{UID: "previous", Kind: "Pod"}},
+	}}
+	current := &v1alpha1.KeptnWorkloadInstance{
+		ObjectMeta: metav1.ObjectMeta{Name: "my-workload-1.0.0", Namespace: "default"},
+		Spec: v1alpha1.KeptnWorkloadInstanceSpec{
+			KeptnWorkloadSpec: v1alpha1.KeptnWorkloadSpec{ResourceReference: v1alpha1.ResourceReference{UID: "current", Kind: "Pod"}},
+		},
+	}
+
+	meters := metrics.NewInMemoryMeters()
+	r := &KeptnWorkloadInstanceReconciler{
+		Client:                fake.NewClientBuilder().WithObjects(podWithCPU("previous", "1"), podWithCPU("current", "3")).Build(),
+		Recorder:              record.NewFakeRecorder(10),
+		Log:                   logr.Discard(),
+		Meters:                meters,
+		CostEstimator:         estimator,
+		MaxHourlyCostIncrease: 1,
+	}
+	r.estimateCostDelta(context.TODO(), trace.SpanFromContext(context.TODO()), current, previous)
+
+	testrequire.Equal(t, "2.0000", current.Status.HourlyCostDelta)
+	testrequire.Equal(t, common.StateFailed, current.Status.PreDeploymentStatus)
+	testrequire.Equal(t, common.StateFailed, current.Status.Status)
+	// the rejected deployment is counted like a failed one
+	testrequire.Equal(t, 1.0, meters.Sum(string(metrics.DeploymentCount)))
+}
+
+func TestKeptnWorkloadInstanceReconciler_EstimateCostDeltaWithoutPreviousPod(t *testing.T) {
+	var logged []string
+	r := &KeptnWorkloadInstanceReconciler{
+		Client:        fake.NewClientBuilder().Build(),
+		Log:           funcr.New(func(_, args string) { logged = append(logged, args) }, funcr.Options{}),
+		CostEstimator: &cost.Estimator{},
+	}
+	previous := &v1alpha1.KeptnWorkloadInstance{Spec: v1alpha1.KeptnWorkloadInstanceSpec{
+		KeptnWorkloadSpec: v1alpha1.KeptnWorkloadSpec{ResourceReference: v1alpha1.ResourceReference{UID: "previous", Kind: "Pod"}},
+	}}
+	current := &v1alpha1.KeptnWorkloadInstance{ObjectMeta: metav1.ObjectMeta{Name: "my-workload-1.0.0", Namespace: "default"}}
+
+	r.estimateCostDelta(context.TODO(), trace.SpanFromContext(context.TODO()), current, previous)
+
+	testrequire.Empty(t, current.Status.HourlyCostDelta)
+	// a pod that is gone is not an error, so it is not logged as one
+	testrequire.Len(t, logged, 1)
+	testrequire.Contains(t, logged[0], "Skipping the cost estimation")
+	testrequire.NotContains(t, logged[0], `"error"`)
+}
+
+func TestPodReadinessChanged(t *testing.T) {
+	pending := makeNominatedPod("pod1", "node1", v1.PodPending)
+	running := makeNominatedPod("pod1", "node1", v1.PodRunning)
+	ready := makeNominatedPod("pod1", "node1", v1.PodRunning)
+	ready.Status.Conditions = []v1.PodCondition{{Type: v1.PodReady, Status: v1.ConditionTrue}}
+	relabeled := ready.DeepCopy()
+	relabeled.Labels = map[string]string{"foo": "bar"}
+
+	predicate := podReadinessChanged()
+	testrequire.True(t, predicate.Update(event.UpdateEvent{ObjectOld: &pending, ObjectNew: &running}))
+	testrequire.True(t, predicate.Update(event.UpdateEvent{ObjectOld: &running, ObjectNew: &ready}))
+	testrequire.False(t, predicate.Update(event.UpdateEvent{ObjectOld: &ready, ObjectNew: relabeled}))
+}
+
+func TestKeptnWorkloadInstanceReconciler_SpanHierarchy(t *testing.T) {
+	spanRecorder := tracetest.NewSpanRecorder()
+	tracer := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(spanRecorder)).Tracer("test")
+	phase := common.PhaseWorkloadPreDeployment
+	workloadInstance := &v1alpha1.KeptnWorkloadInstance{
+		ObjectMeta: metav1.ObjectMeta{Name: "podtato-head-frontend-0.1.0", Namespace: "default"},
+		Spec: v1alpha1.KeptnWorkloadInstanceSpec{
+			KeptnWorkloadSpec: v1alpha1.KeptnWorkloadSpec{AppName: "podtato-head", Version: "0.1.0"},
+			WorkloadName:      "podtato-head-frontend",
+		},
+		Status: v1alpha1.KeptnWorkloadInstanceStatus{CurrentPhase: phase.ShortName},
+	}
+	r := &KeptnWorkloadInstanceReconciler{
+		Client:   fake.NewClientBuilder().Build(),
+		Recorder: record.NewFakeRecorder(100),
+		Log:      logr.Discard(),
+		Tracer:   tracer,
+	}
+
+	ctxAppTrace, appSpan := tracer.Start(context.TODO(), semconv.AppVersionSpanName)
+	ctxWorkloadTrace, _ := r.getSpan(ctxAppTrace, workloadInstance, semconv.WorkloadInstanceSpanName)
+	_, err := r.handlePhase(context.TODO(), ctxWorkloadTrace, workloadInstance, phase, trace.SpanFromContext(context.TODO()), func() bool { return false }, func(phaseCtx context.Context) (common.KeptnState, error) {
+		_, taskSpan := tracer.Start(phaseCtx, semconv.CreateTaskSpanName)
+		taskSpan.End()
+		return common.StateSucceeded, nil
+	})
+	testrequire.Nil(t, err)
+	r.endWorkloadInstanceSpan(workloadInstance, codes.Ok, "Succeeded")
+	appSpan.End()
+
+	spans := map[string]sdktrace.ReadOnlySpan{}
+	for _, span := range spanRecorder.Ended() {
+		spans[span.Name()] = span
+	}
+	testrequire.Len(t, spans, 4)
+	testrequire.Equal(t, spans[semconv.AppVersionSpanName].SpanContext().SpanID(), spans[semconv.WorkloadInstanceSpanName].Parent().SpanID())
+	testrequire.Equal(t, spans[semconv.WorkloadInstanceSpanName].SpanContext().SpanID(), spans[phase.ShortName].Parent().SpanID())
+	testrequire.Equal(t, spans[phase.ShortName].SpanContext().SpanID(), spans[semconv.CreateTaskSpanName].Parent().SpanID())
+	testrequire.Equal(t, codes.Ok, spans[semconv.WorkloadInstanceSpanName].Status().Code)
+}
+
+func TestKeptnWorkloadInstanceReconciler_TracingDisabled(t *testing.T) {
+	phase := common.PhaseWorkloadPreDeployment
+	workloadInstance := &v1alpha1.KeptnWorkloadInstance{
+		ObjectMeta: metav1.ObjectMeta{Name: "podtato-head-frontend-0.1.0", Namespace: "default"},
+		Spec: v1alpha1.KeptnWorkloadInstanceSpec{
+			KeptnWorkloadSpec: v1alpha1.KeptnWorkloadSpec{AppName: "podtato-head", Version: "0.1.0"},
+			WorkloadName:      "podtato-head-frontend",
+		},
+		Status: v1alpha1.KeptnWorkloadInstanceStatus{CurrentPhase: phase.ShortName},
+	}
+	r := &KeptnWorkloadInstanceReconciler{
+		Client:   fake.NewClientBuilder().Build(),
+		Recorder: record.NewFakeRecorder(100),
+		Log:      logr.Discard(),
+		Tracer:   trace.NewNoopTracerProvider().Tracer("test"),
+	}
+
+	_, err := r.handlePhase(context.TODO(), context.TODO(), workloadInstance, phase, trace.SpanFromContext(context.TODO()), func() bool { return false }, func(phaseCtx context.Context) (common.KeptnState, error) {
+		return common.StateProgressing, nil
+	})
+	testrequire.Nil(t, err)
+	testrequire.Empty(t, r.bindCRDSpan)
+}
+
+func TestCompareVersions(t *testing.T) {
+	testrequire.Equal(t, -1, compareVersions("1.2.0", "1.10.0"))
+	testrequire.Equal(t, 1, compareVersions("v2.0.0", "1.10.0"))
+	testrequire.Equal(t, 0, compareVersions("1.0.0", "1.0.0"))
+	testrequire.Equal(t, 1, compareVersions("1.0.0", "latest"))
+	testrequire.Equal(t, -1, compareVersions("abc", "abd"))
+}
+
+func FuzzCompareVersions(f *testing.F) {
+	for _, seed := range [][2]string{{"1.2.0", "1.10.0"}, {"v1.0.0", "1.0.0"}, {"1.0", "1.0.0"}, {"1.0.0-rc.1", "1.0.0"}, {"latest", "1.0.0"}, {"", "v"}} {
+		f.Add(seed[0], seed[1])
+	}
+	f.Fuzz(func(t *testing.T, a string, b string) {
+		testrequire.Equal(t, 0, compareVersions(a, a))
+		testrequire.Equal(t, -compareVersions(a, b), compareVersions(b, a))
+	})
+}
+
+func FuzzKeptnWorkloadInstanceReconciler_GetAppVersionForWorkloadInstance(f *testing.F) {
+	for _, seed := range [][2]string{{"1.2.0", "1.10.0"}, {"0.1.0", "v0.2.0"}, {"1.0", "1.0.0"}, {"abc", "1.0.0"}, {"2", "10"}} {
+		f.Add(seed[0], seed[1])
+	}
+	scheme := runtime.NewScheme()
+	testrequire.Nil(f, v1alpha1.AddToScheme(scheme))
+	workloadInstance := &v1alpha1.KeptnWorkloadInstance{
+		ObjectMeta: metav1.ObjectMeta{Name: "podtato-head-frontend-0.1.0", Namespace: "default"},
+		Spec: v1alpha1.KeptnWorkloadInstanceSpec{
+			KeptnWorkloadSpec: v1alpha1.KeptnWorkloadSpec{AppName: "podtato-head", Version: "0.1.0"},
+			WorkloadName:      "podtato-head-frontend",
+		},
+	}
+	appVersion := func(name string, version string) *v1alpha1.KeptnAppVersion {
+		return &v1alpha1.KeptnAppVersion{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+			Spec: v1alpha1.KeptnAppVersionSpec{
+				AppName: "podtato-head",
+				KeptnAppSpec: v1alpha1.KeptnAppSpec{
+					Version:   version,
+					Workloads: []v1alpha1.KeptnWorkloadRef{{Name: "frontend", Version: "0.1.0"}},
+				},
+			},
+		}
+	}
+
+	f.Fuzz(func(t *testing.T, a string, b string) {
+		// app versions without a version are ignored, and versions read from the API server are valid UTF-8
+		if a == "" || b == "" || !utf8.ValidString(a) || !utf8.ValidString(b) {
+			return
+		}
+		// the selected version must not depend on the order in which the app versions are listed
+		var selected []string
+		for _, versions := range [][2]string{{a, b}, {b, a}} {
+			r := &KeptnWorkloadInstanceReconciler{
+				Client: fake.NewClientBuilder().WithScheme(scheme).WithObjects(appVersion("first", versions[0]), appVersion("second", versions[1])).Build(),
+				Log:    logr.Discard(),
+			}
+			found, latest, err := r.getAppVersionForWorkloadInstance(context.TODO(), workloadInstance)
+			testrequire.Nil(t, err)
+			testrequire.True(t, found)
+			selected = append(selected, latest.Spec.Version)
+		}
+		testrequire.Equal(t, selected[0], selected[1])
+		testrequire.GreaterOrEqual(t, compareVersions(selected[0], a), 0)
+		testrequire.GreaterOrEqual(t, compareVersions(selected[0], b), 0)
+	})
+}
+
+func TestKeptnWorkloadInstanceReconciler_RollbackOnFailureEvent(t *testing.T) {
+	recorder := record.NewFakeRecorder(10)
+	r := &KeptnWorkloadInstanceReconciler{Recorder: recorder}
+	wi := &v1alpha1.KeptnWorkloadInstance{
+		Spec:   v1alpha1.KeptnWorkloadInstanceSpec{KeptnWorkloadSpec: v1alpha1.KeptnWorkloadSpec{RollbackOnFailure: true}},
+		Status: v1alpha1.KeptnWorkloadInstanceStatus{PostDeploymentEvaluationStatus: common.StateFailed},
+	}
+
+	r.rollbackOnFailure(context.TODO(), wi)
+	event := <-recorder.Events
+	testrequire.Contains(t, event, common.PhaseWorkloadPostEvaluation.ShortName)
+	testrequire.Contains(t, event, common.PhaseWorkloadPostEvaluation.LongName)
+}
+
+func TestKeptnWorkloadInstanceReconciler_ReconcilePhaseTimeout(t *testing.T) {
+	scheme := runtime.NewScheme()
+	testrequire.Nil(t, v1alpha1.AddToScheme(scheme))
+	spanRecorder := tracetest.NewSpanRecorder()
+	tracer := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(spanRecorder)).Tracer("test")
+	phase := common.PhaseWorkloadPostEvaluation
+
+	running := &v1alpha1.KeptnEvaluation{ObjectMeta: metav1.ObjectMeta{Name: "post-deployment-check", Namespace: "default"}}
+	workloadInstance := &v1alpha1.KeptnWorkloadInstance{
+		ObjectMeta: metav1.ObjectMeta{Name: "podtato-head-frontend-0.1.0", Namespace: "default"},
+		Spec: v1alpha1.KeptnWorkloadInstanceSpec{
+			KeptnWorkloadSpec: v1alpha1.KeptnWorkloadSpec{
+				AppName:  "podtato-head",
+				Version:  "0.1.0",
+				Timeouts: v1alpha1.PhaseTimeouts{Evaluation: &metav1.Duration{Duration: time.Minute}},
+			},
+			WorkloadName: "podtato-head-frontend",
+		},
+		Status: v1alpha1.KeptnWorkloadInstanceStatus{
+			CurrentPhase:                       phase.ShortName,
+			PhaseStartTime:                     metav1.NewTime(time.Now().Add(-2 * time.Minute)),
+			PreDeploymentStatus:                common.StateSucceeded,
+			PreDeploymentEvaluationStatus:      common.StateSucceeded,
+			MigrationStatus:                    common.StateSucceeded,
+			DeploymentStatus:                   common.StateSucceeded,
+			PostDeploymentStatus:               common.StateSucceeded,
+			PostDeploymentEvaluationStatus:     common.StateProgressing,
+			PostDeploymentEvaluationTaskStatus: []v1alpha1.EvaluationStatus{{EvaluationName: running.Name, Status: common.StateProgressing}},
+		},
+	}
+	recorder := record.NewFakeRecorder(10)
+	meters := metrics.NewInMemoryMeters()
+	r := &KeptnWorkloadInstanceReconciler{
+		Client:   fake.NewClientBuilder().WithScheme(scheme).WithObjects(running).Build(),
+		Recorder: recorder,
+		Log:      logr.Discard(),
+		Tracer:   tracer,
+		Meters:   meters,
+	}
+
+	ctxWorkloadTrace, _ := r.getSpan(context.TODO(), workloadInstance, semconv.WorkloadInstanceSpanName)
+	_, spanPhase := r.getSpan(ctxWorkloadTrace, workloadInstance, phase.ShortName)
+	// the workload instance is not stored, so only the status set in memory is checked
+	timedOut, _ := r.reconcilePhaseTimeout(context.TODO(), ctxWorkloadTrace, workloadInstance, phase, spanPhase)
+
+	testrequire.True(t, timedOut)
+	testrequire.Equal(t, common.StateFailed, workloadInstance.Status.Status)
+	testrequire.Equal(t, common.StateFailed, workloadInstance.Status.PostDeploymentEvaluationStatus)
+	testrequire.Equal(t, common.StateFailed, workloadInstance.Status.PostDeploymentEvaluationTaskStatus[0].Status)
+	testrequire.True(t, workloadInstance.IsEndTimeSet())
+	testrequire.True(t, errors.IsNotFound(r.Client.Get(context.TODO(), types.NamespacedName{Name: running.Name, Namespace: "default"}, &v1alpha1.KeptnEvaluation{})))
+	testrequire.Contains(t, <-recorder.Events, phase.ShortName+reasons.TimedOut.Code)
+	testrequire.Equal(t, 1.0, meters.Sum(string(metrics.DeploymentCount)))
+	testrequire.Empty(t, r.bindCRDSpan)
+
+	spans := map[string]sdktrace.ReadOnlySpan{}
+	for _, ended := range spanRecorder.Ended() {
+		spans[ended.Name()] = ended
+	}
+	testrequire.Len(t, spans, 2)
+	testrequire.Equal(t, codes.Error, spans[phase.ShortName].Status().Code)
+	testrequire.Equal(t, codes.Error, spans[semconv.WorkloadInstanceSpanName].Status().Code)
+	testrequire.Equal(t, reasons.TimedOut.Code, spans[semconv.WorkloadInstanceSpanName].Status().Description)
+}
+
+func TestKeptnWorkloadInstanceReconciler_ReconcileAbort(t *testing.T) {
+	scheme := runtime.NewScheme()
+	testrequire.Nil(t, v1alpha1.AddToScheme(scheme))
+	spanRecorder := tracetest.NewSpanRecorder()
+	tracer := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(spanRecorder)).Tracer("test")
+	phase := common.PhaseWorkloadPreDeployment
+
+	running := &v1alpha1.KeptnTask{ObjectMeta: metav1.ObjectMeta{Name: "pre-deployment-check", Namespace: "default"}}
+	workloadInstance := &v1alpha1.KeptnWorkloadInstance{
+		ObjectMeta: metav1.ObjectMeta{Name: "podtato-head-frontend-0.1.0", Namespace: "default"},
+		Spec: v1alpha1.KeptnWorkloadInstanceSpec{
+			KeptnWorkloadSpec: v1alpha1.KeptnWorkloadSpec{AppName: "podtato-head", Version: "0.1.0"},
+			WorkloadName:      "podtato-head-frontend",
+		},
+		Status: v1alpha1.KeptnWorkloadInstanceStatus{
+			CurrentPhase:            phase.ShortName,
+			PreDeploymentStatus:     common.StateProgressing,
+			PreDeploymentTaskStatus: []v1alpha1.TaskStatus{{TaskName: running.Name, Status: common.StateProgressing}},
+			MigrationStatus:         common.StateSucceeded,
+		},
+	}
+	meters := metrics.NewInMemoryMeters()
+	r := &KeptnWorkloadInstanceReconciler{
+		Client:   fake.NewClientBuilder().WithScheme(scheme).WithObjects(running).Build(),
+		Recorder: record.NewFakeRecorder(10),
+		Log:      logr.Discard(),
+		Tracer:   tracer,
+		Meters:   meters,
+	}
+
+	ctxWorkloadTrace, _ := r.getSpan(context.TODO(), workloadInstance, semconv.WorkloadInstanceSpanName)
+	r.getSpan(ctxWorkloadTrace, workloadInstance, phase.ShortName)
+	// the workload instance is not stored, so only the status set in memory is checked
+	requeue, _ := r.reconcileAbort(context.TODO(), ctxWorkloadTrace, workloadInstance)
+
+	testrequire.False(t, requeue)
+	testrequire.Equal(t, common.StateCancelled, workloadInstance.Status.Status)
+	testrequire.Equal(t, common.StateCancelled, workloadInstance.Status.PreDeploymentStatus)
+	testrequire.Equal(t, common.StateCancelled, workloadInstance.Status.PreDeploymentTaskStatus[0].Status)
+	testrequire.Equal(t, common.StateCancelled, workloadInstance.Status.PostDeploymentEvaluationStatus)
+	testrequire.Equal(t, common.StateSucceeded, workloadInstance.Status.MigrationStatus)
+	testrequire.True(t, workloadInstance.IsEndTimeSet())
+	testrequire.True(t, errors.IsNotFound(r.Client.Get(context.TODO(), types.NamespacedName{Name: running.Name, Namespace: "default"}, &v1alpha1.KeptnTask{})))
+	testrequire.Equal(t, 1.0, meters.Sum(string(metrics.DeploymentCount)))
+	testrequire.Empty(t, r.bindCRDSpan)
+
+	spans := map[string]sdktrace.ReadOnlySpan{}
+	for _, ended := range spanRecorder.Ended() {
+		spans[ended.Name()] = ended
+	}
+	testrequire.Len(t, spans, 2)
+	testrequire.Equal(t, codes.Error, spans[phase.ShortName].Status().Code)
+	testrequire.Equal(t, reasons.Cancelled.Code, spans[semconv.WorkloadInstanceSpanName].Status().Description)
+}
+
+func TestKeptnWorkloadInstanceReconciler_ReconcileDeploymentEndTime(t *testing.T) {
+	scheme := runtime.NewScheme()
+	testrequire.Nil(t, v1alpha1.AddToScheme(scheme))
+	testrequire.Nil(t, v1.AddToScheme(scheme))
+	testrequire.Nil(t, appsv1.AddToScheme(scheme))
+	pod := makeNominatedPod("pod1", "default", v1.PodRunning)
+	r := &KeptnWorkloadInstanceReconciler{
+		Client:   fake.NewClientBuilder().WithScheme(scheme).WithLists(&v1.PodList{Items: []v1.Pod{pod}}).Build(),
+		Recorder: record.NewFakeRecorder(10),
+	}
+	workloadInstance := &v1alpha1.KeptnWorkloadInstance{
+		ObjectMeta: metav1.ObjectMeta{Name: "my-workload-1.0.0", Namespace: "default"},
+		Spec: v1alpha1.KeptnWorkloadInstanceSpec{
+			KeptnWorkloadSpec: v1alpha1.KeptnWorkloadSpec{ResourceReference: v1alpha1.ResourceReference{UID: "pod1", Kind: "Pod"}},
+		},
+	}
+
+	// the workload instance is not stored, so only the status set in memory is checked
+	_, _ = r.reconcileDeployment(context.TODO(), workloadInstance)
+	testrequire.Equal(t, common.StateSucceeded, workloadInstance.Status.DeploymentStatus)
+	testrequire.False(t, workloadInstance.Status.DeploymentEndTime.IsZero())
+
+	// the delay of the post-deployment evaluations is not restarted when the deployment is checked again
+	endTime := metav1.NewTime(time.Now().Add(-time.Hour))
+	workloadInstance.Status.DeploymentEndTime = endTime
+	_, _ = r.reconcileDeployment(context.TODO(), workloadInstance)
+	testrequire.Equal(t, endTime, workloadInstance.Status.DeploymentEndTime)
+}
+
+func TestKeptnWorkloadInstanceReconciler_DelayPostEvaluation(t *testing.T) {
+	phase := common.PhaseWorkloadPostEvaluation
+	newWorkloadInstance := func() *v1alpha1.KeptnWorkloadInstance {
+		return &v1alpha1.KeptnWorkloadInstance{
+			ObjectMeta: metav1.ObjectMeta{Name: "my-workload-1.0.0", Namespace: "default"},
+			Spec: v1alpha1.KeptnWorkloadInstanceSpec{
+				KeptnWorkloadSpec: v1alpha1.KeptnWorkloadSpec{PostDeploymentEvaluationDelay: metav1.Duration{Duration: time.Hour}},
+			},
+			Status: v1alpha1.KeptnWorkloadInstanceStatus{DeploymentEndTime: metav1.NewTime(time.Now().Add(-10 * time.Minute))},
+		}
+	}
+	recorder := record.NewFakeRecorder(10)
+	r := &KeptnWorkloadInstanceReconciler{Recorder: recorder}
+
+	result, delayed := r.delayPostEvaluation(newWorkloadInstance(), phase)
+	testrequire.True(t, delayed)
+	testrequire.True(t, result.Requeue)
+	testrequire.Greater(t, result.RequeueAfter, 49*time.Minute)
+	testrequire.LessOrEqual(t, result.RequeueAfter, 50*time.Minute)
+	testrequire.Contains(t, <-recorder.Events, phase.ShortName+reasons.Delayed.Code)
+
+	elapsed := newWorkloadInstance()
+	elapsed.Status.DeploymentEndTime = metav1.NewTime(time.Now().Add(-2 * time.Hour))
+	started := newWorkloadInstance()
+	started.Status.PostDeploymentEvaluationTaskStatus = []v1alpha1.EvaluationStatus{{EvaluationDefinitionName: "my-evaluation"}}
+	skipped := newWorkloadInstance()
+	skipped.Spec.SkipPhases = []v1alpha1.SkippedPhase{v1alpha1.SkippedPhase(v1alpha1.SimulatedFailurePostDeploymentEvaluation)}
+	for _, workloadInstance := range []*v1alpha1.KeptnWorkloadInstance{elapsed, started, skipped} {
+		result, delayed = r.delayPostEvaluation(workloadInstance, phase)
+		testrequire.False(t, delayed)
+		testrequire.Zero(t, result)
+	}
+	testrequire.Empty(t, recorder.Events)
+}
+
+// fakeIncidentManager records the incidents instead of sending them to an incident management tool
+type fakeIncidentManager struct {
+	opened   []incident.Incident
+	resolved []string
+}
+
+func (m *fakeIncidentManager) Open(_ context.Context, newIncident incident.Incident) error {
+	m.opened = append(m.opened, newIncident)
+	return nil
+}
+
+func (m *fakeIncidentManager) Resolve(_ context.Context, key string) error {
+	m.resolved = append(m.resolved, key)
+	return nil
+}
+
+func TestKeptnWorkloadInstanceReconciler_RollbackOpensIncident(t *testing.T) {
+	scheme := runtime.NewScheme()
+	testrequire.Nil(t, v1alpha1.AddToScheme(scheme))
+	testrequire.Nil(t, v1.AddToScheme(scheme))
+	testrequire.Nil(t, appsv1.AddToScheme(scheme))
+	phase := common.PhaseWorkloadPostEvaluation
+
+	isController := true
+	replicaSet := func(name string) *appsv1.ReplicaSet {
+		return &appsv1.ReplicaSet{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:            name,
+				Namespace:       "default",
+				UID:             types.UID(name),
+				OwnerReferences: []metav1.OwnerReference{{APIVersion: "apps/v1", Kind: "Deployment", Name: "podinfo", UID: "podinfo", Controller: &isController}},
+			},
+		}
+	}
+	workloadInstance := func(version string, previousVersion string, replicaSet string) *v1alpha1.KeptnWorkloadInstance {
+		return &v1alpha1.KeptnWorkloadInstance{
+			ObjectMeta: metav1.ObjectMeta{Name: common.CreateInstanceName("podinfo-podinfo", version), Namespace: "default"},
+			Spec: v1alpha1.KeptnWorkloadInstanceSpec{
+				KeptnWorkloadSpec: v1alpha1.KeptnWorkloadSpec{
+					AppName:           "podinfo",
+					Version:           version,
+					ResourceReference: v1alpha1.ResourceReference{UID: types.UID(replicaSet), Kind: "ReplicaSet"},
+					RollbackOnFailure: true,
+				},
+				WorkloadName:    "podinfo-podinfo",
+				PreviousVersion: previousVersion,
+			},
+		}
+	}
+	failed := workloadInstance("2.0", "1.0", "podinfo-2")
+	failed.Status.PostDeploymentEvaluationStatus = common.StateFailed
+	incidentManager := &fakeIncidentManager{}
+	r := &KeptnWorkloadInstanceReconciler{
+		Client: fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+			&v1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "default", Annotations: map[string]string{common.EnvironmentAnnotation: common.EnvironmentProduction}}},
+			&appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: "podinfo", Namespace: "default", UID: "podinfo"}},
+			replicaSet("podinfo-1"), replicaSet("podinfo-2"), workloadInstance("1.0", "", "podinfo-1"),
+		).Build(),
+		Recorder:        record.NewFakeRecorder(10),
+		Log:             logr.Discard(),
+		Tracer:          trace.NewNoopTracerProvider().Tracer("test"),
+		Meters:          metrics.NewInMemoryMeters(),
+		RollbackEnabled: true,
+		IncidentManager: incidentManager,
+	}
+
+	// the workload instance is not stored, so only the status set in memory is checked
+	_, _ = r.handlePhase(context.TODO(), context.TODO(), failed, phase, trace.SpanFromContext(context.TODO()), func() bool { return false }, func(context.Context) (common.KeptnState, error) {
+		return common.StateFailed, nil
+	})
+
+	testrequire.NotEmpty(t, failed.Status.RollbackInstance)
+	testrequire.Len(t, incidentManager.opened, 1)
+	testrequire.Equal(t, "keptn/default/podinfo/podinfo-podinfo", incidentManager.opened[0].Key)
+	testrequire.Contains(t, incidentManager.opened[0].Summary, "rolled back to version 1.0")
+	testrequire.Equal(t, incidentManager.opened[0].Key, failed.Status.IncidentKey)
+
+	// the rollback does not resolve the incident, but the next version of the workload does
+	rollbackInstance := &v1alpha1.KeptnWorkloadInstance{}
+	testrequire.Nil(t, r.Client.Get(context.TODO(), types.NamespacedName{Name: failed.Status.RollbackInstance, Namespace: "default"}, rollbackInstance))
+	failed.ResourceVersion = ""
+	testrequire.Nil(t, r.Client.Create(context.TODO(), failed))
+	r.resolveIncidents(context.TODO(), rollbackInstance)
+	testrequire.Empty(t, incidentManager.resolved)
+
+	r.resolveIncidents(context.TODO(), workloadInstance("3.0", "2.0", "podinfo-3"))
+	testrequire.Equal(t, []string{failed.Status.IncidentKey}, incidentManager.resolved)
+}
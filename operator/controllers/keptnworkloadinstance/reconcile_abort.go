@@ -0,0 +1,52 @@
+package keptnworkloadinstance
+
+import (
+	"context"
+	"fmt"
+
+	klcv1alpha1 "github.com/keptn/lifecycle-controller/operator/api/v1alpha1"
+	"github.com/keptn/lifecycle-controller/operator/api/v1alpha1/common"
+	"github.com/keptn/lifecycle-controller/operator/controllers/deadline"
+	"github.com/keptn/lifecycle-controller/operator/controllers/reasons"
+	"github.com/keptn/lifecycle-controller/operator/metrics"
+	"go.opentelemetry.io/otel/codes"
+)
+
+// reconcileAbort cancels the workload instance once its app version has been cancelled, deletes its tasks and
+// evaluations that are still running and ends its spans. The migration of the workload is only cancelled once its
+// running task has completed, so it returns whether the workload instance has to be reconciled again.
+func (r *KeptnWorkloadInstanceReconciler) reconcileAbort(ctx context.Context, ctxAppTrace context.Context, workloadInstance *klcv1alpha1.KeptnWorkloadInstance) (bool, error) {
+	phase := common.PhaseAppPreEvaluation
+	r.recordEvent(phase, workloadInstance, reasons.Cancelled)
+	if running, err := r.cancelMigration(ctx, workloadInstance); running || err != nil {
+		return true, err
+	}
+
+	status := &workloadInstance.Status
+	if err := deadline.Cancel(ctx, r.Client, workloadInstance.Namespace, status.PreDeploymentTaskStatus, status.PreDeploymentEvaluationTaskStatus); err != nil {
+		return true, err
+	}
+	if err := deadline.Cancel(ctx, r.Client, workloadInstance.Namespace, status.PostDeploymentTaskStatus, status.PostDeploymentEvaluationTaskStatus); err != nil {
+		return true, err
+	}
+	for _, state := range []*common.KeptnState{&status.PreDeploymentStatus, &status.PreDeploymentEvaluationStatus, &status.MigrationStatus, &status.DeploymentStatus, &status.PostDeploymentStatus, &status.PostDeploymentEvaluationStatus} {
+		if !state.IsCompleted() {
+			*state = common.StateCancelled
+		}
+	}
+	status.Status = common.StateCancelled
+	status.Reason = fmt.Sprintf("app version aborted in phase %s", status.CurrentPhase)
+	workloadInstance.SetEndTime()
+	r.Meters.Add(ctx, metrics.DeploymentCount, 1, workloadInstance.GetMetricsAttributes()...)
+
+	if status.CurrentPhase != "" {
+		_, spanPhase := r.getSpan(ctxAppTrace, workloadInstance, status.CurrentPhase)
+		spanPhase.AddEvent("Aborted")
+		spanPhase.SetStatus(codes.Error, reasons.Cancelled.Code)
+		spanPhase.End()
+		r.unbindSpan(workloadInstance, status.CurrentPhase)
+	}
+	r.endWorkloadInstanceSpan(workloadInstance, codes.Error, reasons.Cancelled.Code)
+
+	return false, r.Client.Status().Update(ctx, workloadInstance)
+}
@@ -0,0 +1,93 @@
+package keptnworkloadinstance
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	klcv1alpha1 "github.com/keptn/lifecycle-controller/operator/api/v1alpha1"
+	"github.com/keptn/lifecycle-controller/operator/api/v1alpha1/common"
+	"github.com/keptn/lifecycle-controller/operator/controllers/reasons"
+	"github.com/keptn/lifecycle-controller/operator/integrations/servicenow"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// ChangeRequestMinBackoff is the delay before a pending change request is checked again the first time
+	ChangeRequestMinBackoff = 10 * time.Second
+	// ChangeRequestMaxBackoff is the maximum delay between two checks of a pending change request
+	ChangeRequestMaxBackoff = 5 * time.Minute
+)
+
+// reconcileChangeRequest gates the pre-deployment phase on the ServiceNow change request of the workload instance.
+// A pending change request is checked again with an exponential backoff, until it is in the Implement state, or has
+// been rejected, canceled or closed, or has expired.
+func (r *KeptnWorkloadInstanceReconciler) reconcileChangeRequest(ctx context.Context, workloadInstance *klcv1alpha1.KeptnWorkloadInstance, now time.Time) common.KeptnState {
+	number := workloadInstance.Spec.ChangeRequest
+	if number == "" {
+		return common.StateSucceeded
+	}
+	status := workloadInstance.Status.ChangeRequest
+	if status == nil || status.Number != number {
+		status = &klcv1alpha1.ChangeRequestStatus{Number: number, Status: common.StateProgressing}
+		workloadInstance.Status.ChangeRequest = status
+	}
+	if status.Status.IsCompleted() {
+		return status.Status
+	}
+
+	if r.ChangeManager == nil {
+		r.failChangeRequest(workloadInstance, fmt.Sprintf("change request %s cannot be checked since no ServiceNow instance is configured", number))
+		return status.Status
+	}
+	if status.Checks > 0 && now.Before(status.LastCheckTime.Add(changeRequestBackoff(status.Checks))) {
+		return status.Status
+	}
+
+	status.Checks++
+	status.LastCheckTime = metav1.NewTime(now)
+	changeRequest, err := r.ChangeManager.GetChangeRequest(ctx, number)
+	if err != nil {
+		r.Log.Error(err, "could not check change request", "changeRequest", number)
+		status.Reason = err.Error()
+		return status.Status
+	}
+	if changeRequest == nil {
+		r.failChangeRequest(workloadInstance, fmt.Sprintf("change request %s does not exist", number))
+		return status.Status
+	}
+
+	verdict, reason := changeRequest.Check(now)
+	switch verdict {
+	case servicenow.VerdictAllowed:
+		status.Status = common.StateSucceeded
+		status.Reason = reason
+		r.recordEvent(common.PhaseWorkloadPreDeployment, workloadInstance, reasons.ChangeRequestApproved, reason)
+	case servicenow.VerdictDenied:
+		r.failChangeRequest(workloadInstance, reason)
+	default:
+		if status.Reason != reason {
+			r.recordEvent(common.PhaseWorkloadPreDeployment, workloadInstance, reasons.ChangeRequestPending, reason)
+		}
+		status.Reason = reason
+	}
+	return status.Status
+}
+
+func (r *KeptnWorkloadInstanceReconciler) failChangeRequest(workloadInstance *klcv1alpha1.KeptnWorkloadInstance, reason string) {
+	workloadInstance.Status.ChangeRequest.Status = common.StateFailed
+	workloadInstance.Status.ChangeRequest.Reason = reason
+	r.recordEvent(common.PhaseWorkloadPreDeployment, workloadInstance, reasons.ChangeRequestDenied, reason)
+}
+
+// changeRequestBackoff returns the delay after the given number of checks of a pending change request
+func changeRequestBackoff(checks int32) time.Duration {
+	backoff := ChangeRequestMinBackoff
+	for i := int32(1); i < checks && backoff < ChangeRequestMaxBackoff; i++ {
+		backoff *= 2
+	}
+	if backoff > ChangeRequestMaxBackoff {
+		return ChangeRequestMaxBackoff
+	}
+	return backoff
+}
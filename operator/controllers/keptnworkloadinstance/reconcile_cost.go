@@ -0,0 +1,59 @@
+package keptnworkloadinstance
+
+import (
+	"context"
+	"fmt"
+
+	klcv1alpha1 "github.com/keptn/lifecycle-controller/operator/api/v1alpha1"
+	"github.com/keptn/lifecycle-controller/operator/api/v1alpha1/common"
+	"github.com/keptn/lifecycle-controller/operator/controllers/reasons"
+	"github.com/keptn/lifecycle-controller/operator/metrics"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// estimateCostDelta records the projected change of the hourly resource cost compared to the previous version.
+// If the increase exceeds MaxHourlyCostIncrease, the pre-deployment phase of the workload instance is failed.
+func (r *KeptnWorkloadInstanceReconciler) estimateCostDelta(ctx context.Context, span trace.Span, workloadInstance *klcv1alpha1.KeptnWorkloadInstance, previousInstance *klcv1alpha1.KeptnWorkloadInstance) {
+	if r.CostEstimator == nil {
+		return
+	}
+
+	previousSpec, previousReplicas, err := r.getPodSpec(ctx, previousInstance.Spec.ResourceReference, workloadInstance.Namespace)
+	if err != nil {
+		r.Log.Error(err, "could not retrieve the pod spec of the previous version")
+		return
+	}
+	if previousSpec == nil {
+		r.Log.Info("Skipping the cost estimation, since the pod spec of the previous version has not been found")
+		return
+	}
+	currentSpec, currentReplicas, err := r.getPodSpec(ctx, workloadInstance.Spec.ResourceReference, workloadInstance.Namespace)
+	if err != nil {
+		r.Log.Error(err, "could not retrieve the pod spec of the workload")
+		return
+	}
+	if currentSpec == nil {
+		r.Log.Info("Skipping the cost estimation, since the pod spec of the workload has not been found")
+		return
+	}
+
+	prices, err := r.CostEstimator.GetPrices(ctx)
+	if err != nil {
+		r.Log.Error(err, "could not retrieve resource prices")
+		return
+	}
+
+	delta := prices.HourlyCost(*currentSpec, currentReplicas) - prices.HourlyCost(*previousSpec, previousReplicas)
+	workloadInstance.Status.HourlyCostDelta = fmt.Sprintf("%.4f", delta)
+	span.SetAttributes(common.WorkloadCostDelta.Float64(delta))
+	r.Meters.Record(ctx, metrics.CostDelta, delta, workloadInstance.GetIntervalMetricsAttributes()...)
+
+	if r.MaxHourlyCostIncrease > 0 && delta > r.MaxHourlyCostIncrease {
+		r.recordEvent(common.PhaseWorkloadPreDeployment, workloadInstance, reasons.CostIncreaseExceeded, delta, r.MaxHourlyCostIncrease)
+		workloadInstance.Status.PreDeploymentStatus = common.StateFailed
+		workloadInstance.Status.Status = common.StateFailed
+		workloadInstance.SetEndTime()
+		// the rejected deployment has finished, like one that failed in a phase
+		r.Meters.Add(ctx, metrics.DeploymentCount, 1, workloadInstance.GetMetricsAttributes()...)
+	}
+}
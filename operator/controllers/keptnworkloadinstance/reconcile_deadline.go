@@ -0,0 +1,59 @@
+package keptnworkloadinstance
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	klcv1alpha1 "github.com/keptn/lifecycle-controller/operator/api/v1alpha1"
+	"github.com/keptn/lifecycle-controller/operator/api/v1alpha1/common"
+	"github.com/keptn/lifecycle-controller/operator/controllers/deadline"
+	"github.com/keptn/lifecycle-controller/operator/controllers/reasons"
+	"github.com/keptn/lifecycle-controller/operator/metrics"
+	"go.opentelemetry.io/otel/codes"
+)
+
+// reconcileDeadline fails the workload instance if it runs longer than the maximum deployment duration of its namespace,
+// and deletes its tasks and evaluations that are still running. It returns whether the deadline has been exceeded.
+func (r *KeptnWorkloadInstanceReconciler) reconcileDeadline(ctx context.Context, ctxAppTrace context.Context, workloadInstance *klcv1alpha1.KeptnWorkloadInstance) (bool, error) {
+	if workloadInstance.IsEndTimeSet() {
+		return false, nil
+	}
+	maxDuration, err := deadline.Resolve(ctx, r.Client, workloadInstance.Namespace)
+	if err != nil {
+		r.Log.Error(err, "could not resolve max deployment duration of WorkloadInstance")
+		return false, nil
+	}
+	if !deadline.Exceeded(workloadInstance.Status.StartTime, maxDuration, time.Now()) {
+		return false, nil
+	}
+
+	status := &workloadInstance.Status
+	if err := deadline.Cleanup(ctx, r.Client, workloadInstance.Namespace, status.PreDeploymentTaskStatus, status.PreDeploymentEvaluationTaskStatus); err != nil {
+		return true, err
+	}
+	if err := deadline.Cleanup(ctx, r.Client, workloadInstance.Namespace, status.MigrationTaskStatus, nil); err != nil {
+		return true, err
+	}
+	if err := deadline.Cleanup(ctx, r.Client, workloadInstance.Namespace, status.PostDeploymentTaskStatus, status.PostDeploymentEvaluationTaskStatus); err != nil {
+		return true, err
+	}
+	deadline.FailPhase(&status.PreDeploymentStatus, &status.PreDeploymentEvaluationStatus, &status.MigrationStatus, &status.DeploymentStatus, &status.PostDeploymentStatus, &status.PostDeploymentEvaluationStatus)
+	status.Status = common.StateFailed
+	status.Reason = fmt.Sprintf("exceeded the maximum deployment duration of %s", maxDuration)
+	workloadInstance.SetEndTime()
+
+	r.recordEvent(phaseWorkloadInstance, workloadInstance, reasons.DeadlineExceeded)
+	r.Meters.Add(ctx, metrics.DeploymentCount, 1, workloadInstance.GetMetricsAttributes()...)
+
+	if status.CurrentPhase != "" {
+		_, spanPhase := r.getSpan(ctxAppTrace, workloadInstance, status.CurrentPhase)
+		spanPhase.AddEvent("Deadline exceeded")
+		spanPhase.SetStatus(codes.Error, reasons.DeadlineExceeded.Code)
+		spanPhase.End()
+		r.unbindSpan(workloadInstance, status.CurrentPhase)
+	}
+	r.endWorkloadInstanceSpan(workloadInstance, codes.Error, reasons.DeadlineExceeded.Code)
+
+	return true, r.Client.Status().Update(ctx, workloadInstance)
+}
@@ -0,0 +1,31 @@
+package keptnworkloadinstance
+
+import (
+	"context"
+	"time"
+
+	klcv1alpha1 "github.com/keptn/lifecycle-controller/operator/api/v1alpha1"
+	"github.com/keptn/lifecycle-controller/operator/controllers/debug"
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+// recordDecision stores the branch the reconciler took, the states of the phases and the requeue of the
+// reconciliation in status.debug. The branch is the last event recorded during the reconciliation.
+func (r *KeptnWorkloadInstanceReconciler) recordDecision(ctx context.Context, workloadInstance *klcv1alpha1.KeptnWorkloadInstance, result ctrl.Result, err error) {
+	if workloadInstance.Status.Debug == nil {
+		workloadInstance.Status.Debug = debug.NewDecision(workloadInstance.Status.CurrentPhase, "no event recorded")
+	}
+	debug.Complete(workloadInstance.Status.Debug, map[string]string{
+		"preDeploymentStatus":            string(workloadInstance.Status.PreDeploymentStatus),
+		"preDeploymentEvaluationStatus":  string(workloadInstance.Status.PreDeploymentEvaluationStatus),
+		"deploymentStatus":               string(workloadInstance.Status.DeploymentStatus),
+		"postDeploymentStatus":           string(workloadInstance.Status.PostDeploymentStatus),
+		"postDeploymentEvaluationStatus": string(workloadInstance.Status.PostDeploymentEvaluationStatus),
+		"status":                         string(workloadInstance.Status.Status),
+		"resourceReference":              workloadInstance.Spec.ResourceReference.Kind + "/" + string(workloadInstance.Spec.ResourceReference.UID),
+		"readinessCheck":                 string(workloadInstance.Spec.ReadinessCheck),
+	}, result, err, time.Now())
+	if err := r.Client.Status().Update(ctx, workloadInstance); err != nil {
+		r.Log.Error(err, "could not record decision of reconciler", "workloadInstance", workloadInstance.Name)
+	}
+}
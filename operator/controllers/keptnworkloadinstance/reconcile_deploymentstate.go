@@ -2,36 +2,78 @@ package keptnworkloadinstance
 
 import (
 	"context"
+	"reflect"
+	"time"
+
 	klcv1alpha1 "github.com/keptn/lifecycle-controller/operator/api/v1alpha1"
 	"github.com/keptn/lifecycle-controller/operator/api/v1alpha1/common"
+	"github.com/keptn/lifecycle-controller/operator/controllers/reasons"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
 )
 
+// DeploymentResyncPeriod is the interval in which the deployment state is checked although neither the pods nor the
+// ReplicaSet of the workload changed their readiness
+const DeploymentResyncPeriod = time.Minute
+
 func (r *KeptnWorkloadInstanceReconciler) reconcileDeployment(ctx context.Context, workloadInstance *klcv1alpha1.KeptnWorkloadInstance) (common.KeptnState, error) {
+	readinessCheck := workloadInstance.Spec.ReadinessCheck
 	if workloadInstance.Spec.ResourceReference.Kind == "Pod" {
 
 		isPodRunning, err := r.isPodRunning(ctx, workloadInstance.Spec.ResourceReference, workloadInstance.Namespace)
 		if err != nil {
 			return common.StateUnknown, err
 		}
-		if isPodRunning {
+		if isPodRunning && !requiresPodReadiness(readinessCheck) {
 			workloadInstance.Status.DeploymentStatus = common.StateSucceeded
+		} else if isPodRunning {
+			isPodReady, err := r.arePodsReady(ctx, workloadInstance.Spec.ResourceReference, workloadInstance.Namespace, readinessCheck, 1)
+			if err != nil {
+				return common.StateUnknown, err
+			}
+			if isPodReady {
+				workloadInstance.Status.DeploymentStatus = common.StateSucceeded
+			}
 		}
 	}
 
 	isReplicaRunning, count, err := r.isReplicaSetRunning(ctx, workloadInstance.Spec.ResourceReference, workloadInstance.Namespace)
+	if err == nil && isReplicaRunning && requiresPodReadiness(readinessCheck) {
+		// readyReplicas declares success as soon as the app container is ready, before injected sidecars or long init containers are
+		isReplicaRunning, err = r.arePodsReady(ctx, workloadInstance.Spec.ResourceReference, workloadInstance.Namespace, readinessCheck, count)
+	}
 	if err != nil {
 		return common.StateUnknown, err
 	}
+	paused := false
+	if !isReplicaRunning && workloadInstance.Spec.ResourceReference.Kind == "ReplicaSet" {
+		paused, err = r.isDeploymentPaused(ctx, workloadInstance.Spec.ResourceReference, workloadInstance.Namespace)
+		if err != nil {
+			return common.StateUnknown, err
+		}
+	}
+	wasPaused := workloadInstance.Status.DeploymentStatus.IsPaused()
 	if isReplicaRunning {
 		workloadInstance.Status.DeploymentStatus = common.StateSucceeded
-	} else if count > 0 {
+	} else if paused {
+		workloadInstance.Status.DeploymentStatus = common.StateDeploymentPaused
+	} else if count > 0 || wasPaused {
 		workloadInstance.Status.DeploymentStatus = common.StateProgressing
 	}
+	if paused && !wasPaused {
+		r.recordEvent(common.PhaseWorkloadDeployment, workloadInstance, reasons.Paused)
+	} else if wasPaused && !paused {
+		r.recordEvent(common.PhaseWorkloadDeployment, workloadInstance, reasons.Resumed)
+	}
+
+	if workloadInstance.IsDeploymentSucceeded() && workloadInstance.Status.DeploymentEndTime.IsZero() {
+		workloadInstance.Status.DeploymentEndTime = v1.Now()
+	}
 
 	err = r.Client.Status().Update(ctx, workloadInstance)
 	if err != nil {
@@ -61,6 +103,30 @@ func (r *KeptnWorkloadInstanceReconciler) isReplicaSetRunning(ctx context.Contex
 
 }
 
+// isDeploymentPaused checks whether the rollout of the Deployment owning the referenced ReplicaSet is paused
+func (r *KeptnWorkloadInstanceReconciler) isDeploymentPaused(ctx context.Context, resource klcv1alpha1.ResourceReference, namespace string) (bool, error) {
+	replica := &appsv1.ReplicaSetList{}
+	if err := r.Client.List(ctx, replica, client.InNamespace(namespace)); err != nil {
+		return false, err
+	}
+	for _, re := range replica.Items {
+		if re.UID != resource.UID {
+			continue
+		}
+		for _, owner := range re.OwnerReferences {
+			if owner.Kind != "Deployment" {
+				continue
+			}
+			dep := appsv1.Deployment{}
+			if err := r.Client.Get(ctx, types.NamespacedName{Name: owner.Name, Namespace: namespace}, &dep); err != nil {
+				return false, client.IgnoreNotFound(err)
+			}
+			return dep.Spec.Paused, nil
+		}
+	}
+	return false, nil
+}
+
 func (r *KeptnWorkloadInstanceReconciler) isPodRunning(ctx context.Context, resource klcv1alpha1.ResourceReference, namespace string) (bool, error) {
 	podList := &corev1.PodList{}
 	if err := r.Client.List(ctx, podList, client.InNamespace(namespace)); err != nil {
@@ -99,3 +165,126 @@ func (r *KeptnWorkloadInstanceReconciler) getDesiredReplicas(ctx context.Context
 	return *replicas, nil
 
 }
+
+func requiresPodReadiness(check klcv1alpha1.ReadinessCheck) bool {
+	return check == klcv1alpha1.ReadinessCheckPodReady || check == klcv1alpha1.ReadinessCheckContainersReady
+}
+
+// arePodsReady checks that at least the expected number of pods of the referenced Pod or ReplicaSet are ready according to the readiness check
+func (r *KeptnWorkloadInstanceReconciler) arePodsReady(ctx context.Context, resource klcv1alpha1.ResourceReference, namespace string, check klcv1alpha1.ReadinessCheck, expected int32) (bool, error) {
+	podList := &corev1.PodList{}
+	if err := r.Client.List(ctx, podList, client.InNamespace(namespace)); err != nil {
+		return false, err
+	}
+	ready := int32(0)
+	for _, p := range podList.Items {
+		if !isOwnedBy(p, resource) {
+			continue
+		}
+		if !isPodReady(p, check) {
+			return false, nil
+		}
+		ready++
+	}
+	return ready >= expected, nil
+}
+
+func isOwnedBy(pod corev1.Pod, resource klcv1alpha1.ResourceReference) bool {
+	if pod.UID == resource.UID {
+		return true
+	}
+	for _, owner := range pod.OwnerReferences {
+		if owner.UID == resource.UID {
+			return true
+		}
+	}
+	return false
+}
+
+func isPodReady(pod corev1.Pod, check klcv1alpha1.ReadinessCheck) bool {
+	switch check {
+	case klcv1alpha1.ReadinessCheckPodReady:
+		return isPodConditionTrue(pod, corev1.PodReady)
+	case klcv1alpha1.ReadinessCheckContainersReady:
+		if !isPodConditionTrue(pod, corev1.PodInitialized) || !isPodConditionTrue(pod, corev1.ContainersReady) {
+			return false
+		}
+		if len(pod.Status.ContainerStatuses) < len(pod.Spec.Containers) {
+			return false
+		}
+		for _, status := range pod.Status.ContainerStatuses {
+			if !status.Ready {
+				return false
+			}
+		}
+		return true
+	default:
+		return pod.Status.Phase == corev1.PodRunning
+	}
+}
+
+func isPodConditionTrue(pod corev1.Pod, conditionType corev1.PodConditionType) bool {
+	for _, condition := range pod.Status.Conditions {
+		if condition.Type == conditionType {
+			return condition.Status == corev1.ConditionTrue
+		}
+	}
+	return false
+}
+
+// podReadinessChanged filters the updates of pods which do not change their readiness according to any readiness check
+func podReadinessChanged() predicate.Predicate {
+	return predicate.Funcs{
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			oldPod, ok := e.ObjectOld.(*corev1.Pod)
+			if !ok {
+				return false
+			}
+			newPod, ok := e.ObjectNew.(*corev1.Pod)
+			if !ok {
+				return false
+			}
+			for _, check := range []klcv1alpha1.ReadinessCheck{klcv1alpha1.ReadinessCheckReadyReplicas, klcv1alpha1.ReadinessCheckPodReady, klcv1alpha1.ReadinessCheckContainersReady} {
+				if isPodReady(*oldPod, check) != isPodReady(*newPod, check) {
+					return true
+				}
+			}
+			return false
+		},
+	}
+}
+
+// deploymentPausedChanged filters the updates of Deployments which neither pause nor resume their rollout
+func deploymentPausedChanged() predicate.Predicate {
+	return predicate.Funcs{
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			oldDeployment, ok := e.ObjectOld.(*appsv1.Deployment)
+			if !ok {
+				return false
+			}
+			newDeployment, ok := e.ObjectNew.(*appsv1.Deployment)
+			if !ok {
+				return false
+			}
+			return oldDeployment.Spec.Paused != newDeployment.Spec.Paused
+		},
+	}
+}
+
+// replicaSetReadinessChanged filters the updates of ReplicaSets which change neither their ready nor their desired replicas
+func replicaSetReadinessChanged() predicate.Predicate {
+	return predicate.Funcs{
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			oldReplicaSet, ok := e.ObjectOld.(*appsv1.ReplicaSet)
+			if !ok {
+				return false
+			}
+			newReplicaSet, ok := e.ObjectNew.(*appsv1.ReplicaSet)
+			if !ok {
+				return false
+			}
+			return oldReplicaSet.Status.ReadyReplicas != newReplicaSet.Status.ReadyReplicas ||
+				!reflect.DeepEqual(oldReplicaSet.Spec.Replicas, newReplicaSet.Spec.Replicas)
+		},
+	}
+}
@@ -0,0 +1,181 @@
+package keptnworkloadinstance
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	klcv1alpha1 "github.com/keptn/lifecycle-controller/operator/api/v1alpha1"
+	"github.com/keptn/lifecycle-controller/operator/api/v1alpha1/common"
+	"go.opentelemetry.io/otel/trace"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// compareToPreviousVersion records the changes and the cost delta of the workload compared to its previous version
+func (r *KeptnWorkloadInstanceReconciler) compareToPreviousVersion(ctx context.Context, span trace.Span, workloadInstance *klcv1alpha1.KeptnWorkloadInstance) {
+	previousInstance, err := r.getPreviousWorkloadInstance(ctx, workloadInstance)
+	if err != nil {
+		r.Log.Error(err, "could not retrieve the previous version of the workload")
+		return
+	} else if previousInstance == nil {
+		return
+	}
+
+	changes, err := r.getChangeSummary(ctx, workloadInstance, previousInstance)
+	if err != nil {
+		r.Log.Error(err, "could not compute changes compared to the previous version")
+	}
+	workloadInstance.Status.ChangeSummary = changes
+	span.SetAttributes(common.WorkloadChanges.StringSlice(changes))
+
+	r.estimateCostDelta(ctx, span, workloadInstance, previousInstance)
+	r.measurePreviousPower(ctx, span, workloadInstance, previousInstance)
+}
+
+// getChangeSummary computes what changed in the pod spec of the workload compared to its previous version
+func (r *KeptnWorkloadInstanceReconciler) getChangeSummary(ctx context.Context, workloadInstance *klcv1alpha1.KeptnWorkloadInstance, previousInstance *klcv1alpha1.KeptnWorkloadInstance) ([]string, error) {
+	previousSpec, _, err := r.getPodSpec(ctx, previousInstance.Spec.ResourceReference, workloadInstance.Namespace)
+	if err != nil || previousSpec == nil {
+		return nil, err
+	}
+	currentSpec, _, err := r.getPodSpec(ctx, workloadInstance.Spec.ResourceReference, workloadInstance.Namespace)
+	if err != nil || currentSpec == nil {
+		return nil, err
+	}
+	return diffPodSpecs(*previousSpec, *currentSpec), nil
+}
+
+// getPreviousWorkloadInstance returns the workload instance of the previous version, or nil if there is none
+func (r *KeptnWorkloadInstanceReconciler) getPreviousWorkloadInstance(ctx context.Context, workloadInstance *klcv1alpha1.KeptnWorkloadInstance) (*klcv1alpha1.KeptnWorkloadInstance, error) {
+	if workloadInstance.Spec.PreviousVersion == "" {
+		return nil, nil
+	}
+
+	previousInstance := &klcv1alpha1.KeptnWorkloadInstance{}
+	previousName := common.CreateInstanceName(workloadInstance.Spec.WorkloadName, workloadInstance.Spec.PreviousVersion)
+	err := r.Client.Get(ctx, types.NamespacedName{Namespace: workloadInstance.Namespace, Name: previousName}, previousInstance)
+	if errors.IsNotFound(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	if err := previousInstance.VerifyIdentity(workloadInstance.Spec.WorkloadName, workloadInstance.Spec.PreviousVersion); err != nil {
+		return nil, err
+	}
+	return previousInstance, nil
+}
+
+// getPodSpec returns the pod spec and the desired number of replicas of the referenced ReplicaSet or Pod
+func (r *KeptnWorkloadInstanceReconciler) getPodSpec(ctx context.Context, resource klcv1alpha1.ResourceReference, namespace string) (*corev1.PodSpec, int32, error) {
+	if resource.Kind == "ReplicaSet" {
+		replicaSets := &appsv1.ReplicaSetList{}
+		if err := r.Client.List(ctx, replicaSets, client.InNamespace(namespace)); err != nil {
+			return nil, 0, err
+		}
+		for _, rs := range replicaSets.Items {
+			if rs.UID == resource.UID {
+				replicas := int32(1)
+				if rs.Spec.Replicas != nil {
+					replicas = *rs.Spec.Replicas
+				}
+				return &rs.Spec.Template.Spec, replicas, nil
+			}
+		}
+		return nil, 0, nil
+	}
+
+	pods := &corev1.PodList{}
+	if err := r.Client.List(ctx, pods, client.InNamespace(namespace)); err != nil {
+		return nil, 0, err
+	}
+	for _, p := range pods.Items {
+		if p.UID == resource.UID {
+			return &p.Spec, 1, nil
+		}
+	}
+	return nil, 0, nil
+}
+
+// diffPodSpecs summarizes the changes of images, environment variables and resources between two pod specs.
+// The values of environment variables are not part of the summary, since they might contain sensitive data.
+func diffPodSpecs(previous corev1.PodSpec, current corev1.PodSpec) []string {
+	changes := []string{}
+	previousContainers := map[string]corev1.Container{}
+	for _, c := range previous.Containers {
+		previousContainers[c.Name] = c
+	}
+
+	for _, c := range current.Containers {
+		old, ok := previousContainers[c.Name]
+		if !ok {
+			changes = append(changes, fmt.Sprintf("container %s: added with image %s", c.Name, c.Image))
+			continue
+		}
+		delete(previousContainers, c.Name)
+
+		if old.Image != c.Image {
+			changes = append(changes, fmt.Sprintf("container %s: image changed from %s to %s", c.Name, old.Image, c.Image))
+		}
+		changes = append(changes, diffEnv(c.Name, old.Env, c.Env)...)
+		changes = append(changes, diffResources(c.Name, "request", old.Resources.Requests, c.Resources.Requests)...)
+		changes = append(changes, diffResources(c.Name, "limit", old.Resources.Limits, c.Resources.Limits)...)
+	}
+
+	for name := range previousContainers {
+		changes = append(changes, fmt.Sprintf("container %s: removed", name))
+	}
+	sort.Strings(changes)
+	return changes
+}
+
+func diffEnv(container string, previous []corev1.EnvVar, current []corev1.EnvVar) []string {
+	changes := []string{}
+	previousEnv := map[string]corev1.EnvVar{}
+	for _, e := range previous {
+		previousEnv[e.Name] = e
+	}
+	for _, e := range current {
+		old, ok := previousEnv[e.Name]
+		if !ok {
+			changes = append(changes, fmt.Sprintf("container %s: env %s added", container, e.Name))
+			continue
+		}
+		delete(previousEnv, e.Name)
+		if old.Value != e.Value || !equalEnvSource(old.ValueFrom, e.ValueFrom) {
+			changes = append(changes, fmt.Sprintf("container %s: env %s changed", container, e.Name))
+		}
+	}
+	for name := range previousEnv {
+		changes = append(changes, fmt.Sprintf("container %s: env %s removed", container, name))
+	}
+	return changes
+}
+
+func equalEnvSource(a *corev1.EnvVarSource, b *corev1.EnvVarSource) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return a.String() == b.String()
+}
+
+func diffResources(container string, kind string, previous corev1.ResourceList, current corev1.ResourceList) []string {
+	changes := []string{}
+	for name, quantity := range current {
+		old, ok := previous[name]
+		if !ok {
+			changes = append(changes, fmt.Sprintf("container %s: %s %s set to %s", container, name, kind, quantity.String()))
+		} else if old.Cmp(quantity) != 0 {
+			changes = append(changes, fmt.Sprintf("container %s: %s %s changed from %s to %s", container, name, kind, old.String(), quantity.String()))
+		}
+	}
+	for name, quantity := range previous {
+		if _, ok := current[name]; !ok {
+			changes = append(changes, fmt.Sprintf("container %s: %s %s of %s removed", container, name, kind, quantity.String()))
+		}
+	}
+	return changes
+}
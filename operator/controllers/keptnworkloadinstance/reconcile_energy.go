@@ -0,0 +1,49 @@
+package keptnworkloadinstance
+
+import (
+	"context"
+	"fmt"
+
+	klcv1alpha1 "github.com/keptn/lifecycle-controller/operator/api/v1alpha1"
+	"github.com/keptn/lifecycle-controller/operator/api/v1alpha1/common"
+	"github.com/keptn/lifecycle-controller/operator/controllers/podselector"
+	"github.com/keptn/lifecycle-controller/operator/metrics"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// measurePreviousPower records the power consumption of the previous version before the new version is deployed
+func (r *KeptnWorkloadInstanceReconciler) measurePreviousPower(ctx context.Context, span trace.Span, workloadInstance *klcv1alpha1.KeptnWorkloadInstance, previousInstance *klcv1alpha1.KeptnWorkloadInstance) {
+	if r.EnergyMeter == nil {
+		return
+	}
+	power, err := r.getPower(ctx, previousInstance)
+	if err != nil {
+		r.Log.Error(err, "could not measure the power consumption of the previous version")
+		return
+	}
+	workloadInstance.Status.PreviousPowerConsumption = fmt.Sprintf("%.2f", power)
+	span.SetAttributes(common.WorkloadPreviousPower.Float64(power))
+}
+
+// measurePower records the power consumption of the workload once it has been deployed
+func (r *KeptnWorkloadInstanceReconciler) measurePower(ctx context.Context, span trace.Span, workloadInstance *klcv1alpha1.KeptnWorkloadInstance) {
+	if r.EnergyMeter == nil {
+		return
+	}
+	power, err := r.getPower(ctx, workloadInstance)
+	if err != nil {
+		r.Log.Error(err, "could not measure the power consumption of the workload")
+		return
+	}
+	workloadInstance.Status.PowerConsumption = fmt.Sprintf("%.2f", power)
+	span.SetAttributes(common.WorkloadPower.Float64(power))
+	r.Meters.Record(ctx, metrics.PowerConsumption, power, workloadInstance.GetActiveMetricsAttributes()...)
+}
+
+func (r *KeptnWorkloadInstanceReconciler) getPower(ctx context.Context, workloadInstance *klcv1alpha1.KeptnWorkloadInstance) (float64, error) {
+	pattern, err := podselector.Pattern(ctx, r.Client, workloadInstance.Spec.ResourceReference, workloadInstance.Namespace)
+	if err != nil {
+		return 0, err
+	}
+	return r.EnergyMeter.GetPower(ctx, workloadInstance.Namespace, pattern)
+}
@@ -0,0 +1,93 @@
+package keptnworkloadinstance
+
+import (
+	"context"
+	"fmt"
+
+	klcv1alpha1 "github.com/keptn/lifecycle-controller/operator/api/v1alpha1"
+	"github.com/keptn/lifecycle-controller/operator/api/v1alpha1/common"
+	"github.com/keptn/lifecycle-controller/operator/controllers/environment"
+	"github.com/keptn/lifecycle-controller/operator/controllers/reasons"
+	"github.com/keptn/lifecycle-controller/operator/integrations/incident"
+	"go.opentelemetry.io/otel/trace"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// openIncident opens an incident for workload instances in production namespaces that failed their post-deployment
+// evaluation. It is called after the workload has been rolled back, so that the incident tells the on-call team about
+// the rollback.
+func (r *KeptnWorkloadInstanceReconciler) openIncident(ctx context.Context, ctxAppTrace context.Context, workloadInstance *klcv1alpha1.KeptnWorkloadInstance) {
+	if r.IncidentManager == nil || !workloadInstance.IsPostDeploymentEvaluationFailed() || workloadInstance.Status.IncidentKey != "" {
+		return
+	}
+	production, err := environment.IsProduction(ctx, r.Client, workloadInstance.Namespace)
+	if err != nil {
+		r.Log.Error(err, "could not fetch namespace")
+		return
+	}
+	if !production {
+		return
+	}
+
+	phase := common.KeptnPhaseType{
+		ShortName: "Incident",
+		LongName:  "Incident",
+	}
+
+	summary := fmt.Sprintf("Post-deployment evaluation of workload %s version %s in namespace %s failed", workloadInstance.Spec.WorkloadName, workloadInstance.Spec.Version, workloadInstance.Namespace)
+	if workloadInstance.Status.RollbackInstance != "" {
+		summary += fmt.Sprintf(", rolled back to version %s", workloadInstance.Spec.PreviousVersion)
+	}
+	newIncident := incident.Incident{
+		Key:              getIncidentKey(workloadInstance),
+		Summary:          summary,
+		Source:           "keptn-lifecycle-controller",
+		TraceID:          trace.SpanContextFromContext(ctxAppTrace).TraceID().String(),
+		FailedObjectives: incident.FailedObjectives(ctx, r.Client, r.Log, workloadInstance.Namespace, workloadInstance.Status.PostDeploymentEvaluationTaskStatus),
+	}
+
+	if err := r.IncidentManager.Open(ctx, newIncident); err != nil {
+		r.Log.Error(err, "could not open incident")
+		r.recordEvent(phase, workloadInstance, reasons.IncidentOpenFailed)
+		return
+	}
+	workloadInstance.Status.IncidentKey = newIncident.Key
+	r.recordEvent(phase, workloadInstance, reasons.IncidentOpened)
+}
+
+// resolveIncidents resolves the incidents opened for earlier versions of the workload once a version of it has been
+// deployed successfully. Rollbacks do not resolve them, since the version that failed has not been fixed yet.
+func (r *KeptnWorkloadInstanceReconciler) resolveIncidents(ctx context.Context, workloadInstance *klcv1alpha1.KeptnWorkloadInstance) {
+	if r.IncidentManager == nil || workloadInstance.Annotations[common.RollbackOfAnnotation] != "" {
+		return
+	}
+	workloadInstances := &klcv1alpha1.KeptnWorkloadInstanceList{}
+	if err := r.Client.List(ctx, workloadInstances, client.InNamespace(workloadInstance.Namespace)); err != nil {
+		r.Log.Error(err, "could not retrieve KeptnWorkloadInstances")
+		return
+	}
+
+	resolved := map[string]bool{}
+	for i := range workloadInstances.Items {
+		failedInstance := &workloadInstances.Items[i]
+		key := failedInstance.Status.IncidentKey
+		if failedInstance.Spec.WorkloadName != workloadInstance.Spec.WorkloadName || key == "" {
+			continue
+		}
+		if !resolved[key] {
+			if err := r.IncidentManager.Resolve(ctx, key); err != nil {
+				r.Log.Error(err, "could not resolve incident")
+				return
+			}
+			resolved[key] = true
+		}
+		failedInstance.Status.IncidentKey = ""
+		if err := r.Client.Status().Update(ctx, failedInstance); err != nil {
+			r.Log.Error(err, "could not remove the resolved incident from the status")
+		}
+	}
+}
+
+func getIncidentKey(workloadInstance *klcv1alpha1.KeptnWorkloadInstance) string {
+	return fmt.Sprintf("keptn/%s/%s/%s", workloadInstance.Namespace, workloadInstance.Spec.AppName, workloadInstance.Spec.WorkloadName)
+}
@@ -0,0 +1,43 @@
+package keptnworkloadinstance
+
+import (
+	"context"
+
+	klcv1alpha1 "github.com/keptn/lifecycle-controller/operator/api/v1alpha1"
+	"github.com/keptn/lifecycle-controller/operator/controllers/reasons"
+	"github.com/keptn/lifecycle-controller/operator/integrations/jira"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// markIssueComment records in the status of a finished workload instance that its outcome is commented on the issue
+// referenced by the keptn.sh/issue annotation, and returns whether the comment has to be posted once the status has
+// been persisted
+func (r *KeptnWorkloadInstanceReconciler) markIssueComment(workloadInstance *klcv1alpha1.KeptnWorkloadInstance) bool {
+	if r.IssueTracker == nil || workloadInstance.Spec.Issue == "" || workloadInstance.Status.IssueCommented {
+		return false
+	}
+	workloadInstance.Status.IssueCommented = true
+	return true
+}
+
+// commentOnIssue reports the outcome of a finished workload instance to the issue referenced by the keptn.sh/issue
+// annotation. It is only called after markIssueComment has been persisted.
+func (r *KeptnWorkloadInstanceReconciler) commentOnIssue(ctx context.Context, ctxAppTrace context.Context, workloadInstance *klcv1alpha1.KeptnWorkloadInstance) {
+
+	outcome := jira.DeploymentOutcome{
+		Issue:     workloadInstance.Spec.Issue,
+		Namespace: workloadInstance.Namespace,
+		Workload:  workloadInstance.Spec.WorkloadName,
+		Version:   workloadInstance.Spec.Version,
+		Status:    string(workloadInstance.Status.Status),
+		Duration:  workloadInstance.Status.EndTime.Sub(workloadInstance.Status.StartTime.Time),
+	}
+	if spanContext := trace.SpanContextFromContext(ctxAppTrace); spanContext.HasTraceID() {
+		outcome.TraceID = spanContext.TraceID().String()
+	}
+
+	if err := r.IssueTracker.CommentDeploymentOutcome(ctx, outcome); err != nil {
+		r.Log.Error(err, "could not comment on issue "+workloadInstance.Spec.Issue)
+		r.recordEvent(phaseWorkloadInstance, workloadInstance, reasons.IssueNotUpdated, workloadInstance.Spec.Issue)
+	}
+}
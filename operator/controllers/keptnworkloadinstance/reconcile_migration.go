@@ -0,0 +1,187 @@
+package keptnworkloadinstance
+
+import (
+	"context"
+	"fmt"
+
+	klcv1alpha1 "github.com/keptn/lifecycle-controller/operator/api/v1alpha1"
+	"github.com/keptn/lifecycle-controller/operator/api/v1alpha1/common"
+	"github.com/keptn/lifecycle-controller/operator/controllers/reasons"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// reconcileMigration runs the migration tasks of the workload instance one after the other in the order of the spec.
+// The tasks only start once no other version of the workload is migrating, and a task which has failed or has been
+// deleted is not started again, so that each migration is applied at most once.
+func (r *KeptnWorkloadInstanceReconciler) reconcileMigration(ctx context.Context, workloadInstance *klcv1alpha1.KeptnWorkloadInstance) (common.KeptnState, error) {
+	newStatus, summary, err := r.reconcileMigrationTasks(ctx, workloadInstance, false)
+	if err != nil {
+		return common.StateUnknown, err
+	}
+	overallState := common.GetOverallState(summary)
+	if overallState == common.StatePending {
+		// the migration keeps the lock of the workload while it waits for it
+		overallState = common.StateProgressing
+	}
+	workloadInstance.Status.MigrationStatus = overallState
+	workloadInstance.Status.MigrationTaskStatus = newStatus
+
+	if err := r.Client.Status().Update(ctx, workloadInstance); err != nil {
+		return common.StateUnknown, err
+	}
+	return overallState, nil
+}
+
+// cancelMigration cancels the migration tasks of the workload instance which have not started yet, since its app version
+// has been cancelled. It returns whether a migration task is still running, which keeps the lock of the workload.
+func (r *KeptnWorkloadInstanceReconciler) cancelMigration(ctx context.Context, workloadInstance *klcv1alpha1.KeptnWorkloadInstance) (bool, error) {
+	if workloadInstance.Status.MigrationStatus != common.StateProgressing {
+		return false, nil
+	}
+	newStatus, _, err := r.reconcileMigrationTasks(ctx, workloadInstance, true)
+	if err != nil {
+		return true, err
+	}
+	running := false
+	for _, ts := range newStatus {
+		running = running || !ts.Status.IsCompleted()
+	}
+	if !running {
+		workloadInstance.Status.MigrationStatus = common.StateCancelled
+	}
+	workloadInstance.Status.MigrationTaskStatus = newStatus
+	return running, r.Client.Status().Update(ctx, workloadInstance)
+}
+
+func (r *KeptnWorkloadInstanceReconciler) reconcileMigrationTasks(ctx context.Context, workloadInstance *klcv1alpha1.KeptnWorkloadInstance, cancelled bool) ([]klcv1alpha1.TaskStatus, common.StatusSummary, error) {
+	phase := common.PhaseWorkloadMigration
+	statuses := workloadInstance.Status.MigrationTaskStatus
+
+	// the reason the next migration task is not started yet, and the reason the remaining ones are not started at all
+	waiting, aborted := "", ""
+	abortedState := common.StateFailed
+	if cancelled {
+		aborted, abortedState = "the app version has been cancelled", common.StateCancelled
+	} else if !migrationStarted(statuses) {
+		holder, err := r.getMigrationLockHolder(ctx, workloadInstance)
+		if err != nil {
+			return nil, common.StatusSummary{}, err
+		}
+		if holder != nil && holder.Name != workloadInstance.Name {
+			r.recordEvent(phase, workloadInstance, reasons.MigrationLocked, holder.Spec.Version)
+			waiting = reasons.MigrationLocked.Message(holder.Spec.Version)
+		}
+	}
+
+	var summary common.StatusSummary
+	summary.Total = len(workloadInstance.Spec.MigrationTasks)
+	var newStatus []klcv1alpha1.TaskStatus
+	for _, taskDefinitionName := range workloadInstance.Spec.MigrationTasks {
+		taskStatus := GetTaskStatus(taskDefinitionName, statuses)
+		oldStatus := taskStatus.Status
+
+		switch {
+		case taskStatus.Status.IsCompleted():
+		case aborted != "" && taskStatus.TaskName == "":
+			taskStatus.Status = abortedState
+			taskStatus.Reason = aborted
+			taskStatus.SetEndTime()
+			r.recordEvent(phase, workloadInstance, reasons.TaskDependencyFailed, taskDefinitionName, aborted)
+		case taskStatus.TaskName == "" && waiting != "":
+			taskStatus.Reason = waiting
+		case taskStatus.TaskName == "":
+			taskName, err := r.createKeptnTask(ctx, workloadInstance.Namespace, workloadInstance, taskDefinitionName, common.MigrationCheckType)
+			if err != nil {
+				return nil, summary, err
+			}
+			taskStatus.TaskName = taskName
+			taskStatus.Reason = ""
+			taskStatus.SetStartTime()
+		default:
+			task := &klcv1alpha1.KeptnTask{}
+			err := r.Client.Get(ctx, types.NamespacedName{Name: taskStatus.TaskName, Namespace: workloadInstance.Namespace}, task)
+			if errors.IsNotFound(err) {
+				// the migration might have been applied partially, so that it is not started again
+				taskStatus.Status = common.StateFailed
+				taskStatus.Reason = reasons.TaskDeleted.Message()
+				r.recordEvent(phase, workloadInstance, reasons.TaskDeleted)
+			} else if err != nil {
+				return nil, summary, err
+			} else {
+				taskStatus.Status = task.Status.Status
+			}
+			if taskStatus.Status.IsCompleted() {
+				taskStatus.SetEndTime()
+			}
+		}
+
+		if oldStatus != taskStatus.Status {
+			r.recordEvent(phase, workloadInstance, reasons.TaskStatusChanged, oldStatus, taskStatus.Status)
+		}
+		if taskStatus.Status.IsCompleted() && !taskStatus.Status.IsSucceeded() && aborted == "" {
+			aborted = fmt.Sprintf("migration task %s has failed", taskDefinitionName)
+		} else if !taskStatus.Status.IsCompleted() && waiting == "" {
+			waiting = fmt.Sprintf("waits for migration task %s", taskDefinitionName)
+		}
+		newStatus = append(newStatus, taskStatus)
+	}
+
+	for _, ts := range newStatus {
+		summary = common.UpdateStatusSummary(ts.Status, summary)
+	}
+	return newStatus, summary, nil
+}
+
+// getMigrationLockHolder returns the workload instance of the same workload which is allowed to run its migration tasks
+func (r *KeptnWorkloadInstanceReconciler) getMigrationLockHolder(ctx context.Context, workloadInstance *klcv1alpha1.KeptnWorkloadInstance) (*klcv1alpha1.KeptnWorkloadInstance, error) {
+	instances := &klcv1alpha1.KeptnWorkloadInstanceList{}
+	if err := r.Client.List(ctx, instances, client.InNamespace(workloadInstance.Namespace)); err != nil {
+		return nil, fmt.Errorf("could not list KeptnWorkloadInstances: %w", err)
+	}
+	candidates := []klcv1alpha1.KeptnWorkloadInstance{*workloadInstance}
+	for _, instance := range instances.Items {
+		if instance.Spec.WorkloadName == workloadInstance.Spec.WorkloadName && instance.Name != workloadInstance.Name {
+			candidates = append(candidates, instance)
+		}
+	}
+	return migrationLockHolder(candidates), nil
+}
+
+// migrationLockHolder returns the workload instance among the given ones of a workload whose migration is running.
+// An instance which has started a migration task holds the lock, otherwise the oldest instance waiting for it gets it.
+func migrationLockHolder(instances []klcv1alpha1.KeptnWorkloadInstance) *klcv1alpha1.KeptnWorkloadInstance {
+	var holder *klcv1alpha1.KeptnWorkloadInstance
+	for i := range instances {
+		instance := &instances[i]
+		if !instance.HasMigrationTasks() || instance.Status.MigrationStatus != common.StateProgressing {
+			continue
+		}
+		if holder == nil || precedesInMigration(instance, holder) {
+			holder = instance
+		}
+	}
+	return holder
+}
+
+func precedesInMigration(instance *klcv1alpha1.KeptnWorkloadInstance, other *klcv1alpha1.KeptnWorkloadInstance) bool {
+	started, otherStarted := migrationStarted(instance.Status.MigrationTaskStatus), migrationStarted(other.Status.MigrationTaskStatus)
+	if started != otherStarted {
+		return started
+	}
+	if !instance.CreationTimestamp.Equal(&other.CreationTimestamp) {
+		return instance.CreationTimestamp.Before(&other.CreationTimestamp)
+	}
+	return instance.Name < other.Name
+}
+
+// migrationStarted returns whether a KeptnTask has been created for one of the given migration tasks
+func migrationStarted(statuses []klcv1alpha1.TaskStatus) bool {
+	for _, status := range statuses {
+		if status.TaskName != "" {
+			return true
+		}
+	}
+	return false
+}
@@ -2,11 +2,16 @@ package keptnworkloadinstance
 
 import (
 	"context"
-	"fmt"
+	"time"
 
 	klcv1alpha1 "github.com/keptn/lifecycle-controller/operator/api/v1alpha1"
 	"github.com/keptn/lifecycle-controller/operator/api/v1alpha1/common"
 	"github.com/keptn/lifecycle-controller/operator/api/v1alpha1/semconv"
+	"github.com/keptn/lifecycle-controller/operator/controllers/apply"
+	"github.com/keptn/lifecycle-controller/operator/controllers/deadline"
+	"github.com/keptn/lifecycle-controller/operator/controllers/environment"
+	"github.com/keptn/lifecycle-controller/operator/controllers/reasons"
+	"github.com/keptn/lifecycle-controller/operator/controllers/taskgraph"
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/propagation"
 	"go.opentelemetry.io/otel/trace"
@@ -22,6 +27,10 @@ func (r *KeptnWorkloadInstanceReconciler) reconcilePrePostDeployment(ctx context
 		return common.StateUnknown, err
 	}
 	overallState := common.GetOverallState(state)
+	if checkType == common.PreDeploymentCheckType && overallState.IsSucceeded() {
+		// the pods are only scheduled once the change request of the workload allows the deployment
+		overallState = r.reconcileChangeRequest(ctx, workloadInstance, time.Now())
+	}
 
 	switch checkType {
 	case common.PreDeploymentCheckType:
@@ -50,7 +59,8 @@ func (r *KeptnWorkloadInstanceReconciler) getKeptnTask(ctx context.Context, task
 }
 
 func (r *KeptnWorkloadInstanceReconciler) createKeptnTask(ctx context.Context, namespace string, workloadInstance *klcv1alpha1.KeptnWorkloadInstance, taskDefinition string, checkType common.CheckType) (string, error) {
-	ctx, span := r.Tracer.Start(ctx, fmt.Sprintf("create_%s_deployment_task", checkType), trace.WithSpanKind(trace.SpanKindProducer))
+	phaseTraceParent := semconv.TraceParent(ctx)
+	ctx, span := r.Tracer.Start(ctx, semconv.CreateTaskSpanName, trace.WithSpanKind(trace.SpanKindProducer))
 	defer span.End()
 
 	semconv.AddAttributeFromWorkloadInstance(span, *workloadInstance)
@@ -59,11 +69,25 @@ func (r *KeptnWorkloadInstanceReconciler) createKeptnTask(ctx context.Context, n
 	// follow up with a Keptn propagator that JSON-encoded the OTel map into our own key
 	traceContextCarrier := propagation.MapCarrier{}
 	otel.GetTextMapPropagator().Inject(ctx, traceContextCarrier)
+	traceContextCarrier[common.PhaseTraceParentAnnotation] = phaseTraceParent
+
+	phase := common.KeptnPhaseType{
+		ShortName: "KeptnTaskCreate",
+		LongName:  "Keptn Task Create",
+	}
+
+	// the task must not outlive the deadline of the workload instance
+	taskDeadline, err := deadline.Of(ctx, r.Client, workloadInstance.Namespace, workloadInstance.Status.StartTime)
+	if err != nil {
+		r.Log.Error(err, "could not resolve deadline of KeptnTask")
+	}
+
 	newTask := &klcv1alpha1.KeptnTask{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:        common.GenerateTaskName(checkType, taskDefinition),
 			Namespace:   namespace,
 			Annotations: traceContextCarrier,
+			Labels:      environment.Labels(nil, workloadInstance.Labels[common.EnvironmentLabel]),
 		},
 		Spec: klcv1alpha1.KeptnTaskSpec{
 			AppName:          workloadInstance.Spec.AppName,
@@ -73,19 +97,26 @@ func (r *KeptnWorkloadInstanceReconciler) createKeptnTask(ctx context.Context, n
 			Parameters:       klcv1alpha1.TaskParameters{},
 			SecureParameters: klcv1alpha1.SecureParameters{},
 			Type:             checkType,
+			Deadline:         taskDeadline,
 		},
 	}
-	err := controllerutil.SetControllerReference(workloadInstance, newTask, r.Scheme)
+	if checkType == common.MigrationCheckType {
+		// a failed migration might have been applied partially, so that its Job is not started again
+		newTask.Spec.Retries = new(int32)
+		newTask.Spec.ExactlyOnce = true
+	}
+	r.Propagation.Copy(workloadInstance.ObjectMeta, &newTask.ObjectMeta)
+	err = controllerutil.SetControllerReference(workloadInstance, newTask, r.Scheme)
 	if err != nil {
 		r.Log.Error(err, "could not set controller reference:")
 	}
-	err = r.Client.Create(ctx, newTask)
+	err = apply.Apply(ctx, r.Client, newTask, r.Recorder, workloadInstance)
 	if err != nil {
 		r.Log.Error(err, "could not create KeptnTask")
-		r.Recorder.Event(workloadInstance, "Warning", "KeptnTaskNotCreated", fmt.Sprintf("Could not create KeptnTask / Namespace: %s, Name: %s ", newTask.Namespace, newTask.Name))
+		r.recordEvent(phase, workloadInstance, reasons.CreateFailed, "KeptnTask")
 		return "", err
 	}
-	r.Recorder.Event(workloadInstance, "Normal", "KeptnTaskCreated", fmt.Sprintf("Created KeptnTask / Namespace: %s, Name: %s ", newTask.Namespace, newTask.Name))
+	r.recordEvent(phase, workloadInstance, reasons.ChecksCreated)
 
 	return newTask.Name, nil
 }
@@ -108,6 +139,12 @@ func (r *KeptnWorkloadInstanceReconciler) reconcileTasks(ctx context.Context, ch
 		statuses = workloadInstance.Status.PostDeploymentTaskStatus
 	}
 
+	// tasks are only started once the tasks they depend on have succeeded
+	graph, err := taskgraph.Build(ctx, r.Client, workloadInstance.Namespace, tasks)
+	if err != nil {
+		return nil, common.StatusSummary{}, err
+	}
+
 	var summary common.StatusSummary
 	summary.Total = len(tasks)
 	// Check current state of the PrePostDeploymentTasks
@@ -126,7 +163,7 @@ func (r *KeptnWorkloadInstanceReconciler) reconcileTasks(ctx context.Context, ch
 		taskExists := false
 
 		if oldstatus != taskStatus.Status {
-			r.recordEvent(phase, "Normal", workloadInstance, "TaskStatusChanged", fmt.Sprintf("task status changed from %s to %s", oldstatus, taskStatus.Status))
+			r.recordEvent(phase, workloadInstance, reasons.TaskStatusChanged, oldstatus, taskStatus.Status)
 		}
 
 		// Check if task has already succeeded or failed
@@ -148,6 +185,18 @@ func (r *KeptnWorkloadInstanceReconciler) reconcileTasks(ctx context.Context, ch
 
 		// Create new Task if it does not exist
 		if !taskExists {
+			reason, failed := graph.Blocked(taskDefinitionName, taskgraph.States(statuses, newStatus))
+			if reason != "" {
+				if failed {
+					taskStatus.Status = common.StateFailed
+					taskStatus.SetEndTime()
+					r.recordEvent(phase, workloadInstance, reasons.TaskDependencyFailed, taskDefinitionName, reason)
+				}
+				taskStatus.Reason = reason
+				newStatus = append(newStatus, taskStatus)
+				continue
+			}
+			taskStatus.Reason = ""
 			taskName, err := r.createKeptnTask(ctx, workloadInstance.Namespace, workloadInstance, taskDefinitionName, checkType)
 			if err != nil {
 				return nil, summary, err
@@ -169,7 +218,7 @@ func (r *KeptnWorkloadInstanceReconciler) reconcileTasks(ctx context.Context, ch
 		summary = common.UpdateStatusSummary(ns.Status, summary)
 	}
 	if common.GetOverallState(summary) != common.StateSucceeded {
-		r.Recorder.Event(workloadInstance, "Warning", "TasksNotFinished", fmt.Sprintf("Tasks have not finished / Namespace: %s, Name: %s, Summary: %v ", workloadInstance.Namespace, workloadInstance.Name, summary))
+		r.recordEvent(phase, workloadInstance, reasons.NotFinished)
 	}
 	return newStatus, summary, nil
 }
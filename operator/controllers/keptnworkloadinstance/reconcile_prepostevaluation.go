@@ -2,12 +2,15 @@ package keptnworkloadinstance
 
 import (
 	"context"
-	"fmt"
 	"time"
 
 	klcv1alpha1 "github.com/keptn/lifecycle-controller/operator/api/v1alpha1"
 	"github.com/keptn/lifecycle-controller/operator/api/v1alpha1/common"
 	"github.com/keptn/lifecycle-controller/operator/api/v1alpha1/semconv"
+	"github.com/keptn/lifecycle-controller/operator/controllers/apply"
+	"github.com/keptn/lifecycle-controller/operator/controllers/deadline"
+	"github.com/keptn/lifecycle-controller/operator/controllers/environment"
+	"github.com/keptn/lifecycle-controller/operator/controllers/reasons"
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/propagation"
 	"go.opentelemetry.io/otel/trace"
@@ -76,7 +79,7 @@ func (r *KeptnWorkloadInstanceReconciler) reconcileEvaluations(ctx context.Conte
 		evaluationExists := false
 
 		if oldstatus != evaluationStatus.Status {
-			r.recordEvent(phase, "Normal", workloadInstance, "EvaluationStatusChanged", fmt.Sprintf("evaluation status changed from %s to %s", oldstatus, evaluationStatus.Status))
+			r.recordEvent(phase, workloadInstance, reasons.EvaluationStatusChanged, oldstatus, evaluationStatus.Status)
 		}
 
 		// Check if evaluation has already succeeded or failed
@@ -119,14 +122,14 @@ func (r *KeptnWorkloadInstanceReconciler) reconcileEvaluations(ctx context.Conte
 		summary = common.UpdateStatusSummary(ns.Status, summary)
 	}
 	if common.GetOverallState(summary) != common.StateSucceeded {
-		r.recordEvent(phase, "Warning", workloadInstance, "NotFinished", "has not finished")
+		r.recordEvent(phase, workloadInstance, reasons.NotFinished)
 	}
 	return newStatus, summary, nil
 }
 
 func (r *KeptnWorkloadInstanceReconciler) createKeptnEvaluation(ctx context.Context, namespace string, workloadInstance *klcv1alpha1.KeptnWorkloadInstance, evaluationDefinition string, checkType common.CheckType) (string, error) {
 
-	ctx, span := r.Tracer.Start(ctx, "create_workload_evaluation", trace.WithSpanKind(trace.SpanKindProducer))
+	ctx, span := r.Tracer.Start(ctx, semconv.CreateEvaluationSpanName, trace.WithSpanKind(trace.SpanKindProducer))
 	defer span.End()
 
 	semconv.AddAttributeFromWorkloadInstance(span, *workloadInstance)
@@ -141,13 +144,21 @@ func (r *KeptnWorkloadInstanceReconciler) createKeptnEvaluation(ctx context.Cont
 		LongName:  "Keptn Evaluation Create",
 	}
 
+	// the evaluation must not outlive the deadline of the workload instance
+	evaluationDeadline, err := deadline.Of(ctx, r.Client, workloadInstance.Namespace, workloadInstance.Status.StartTime)
+	if err != nil {
+		r.Log.Error(err, "could not resolve deadline of KeptnEvaluation")
+	}
+
 	newEvaluation := &klcv1alpha1.KeptnEvaluation{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:        common.GenerateEvaluationName(checkType, evaluationDefinition),
 			Namespace:   namespace,
 			Annotations: traceContextCarrier,
+			Labels:      environment.Labels(nil, workloadInstance.Labels[common.EnvironmentLabel]),
 		},
 		Spec: klcv1alpha1.KeptnEvaluationSpec{
+			AppName:              workloadInstance.Spec.AppName,
 			WorkloadVersion:      workloadInstance.Spec.Version,
 			Workload:             workloadInstance.Spec.WorkloadName,
 			EvaluationDefinition: evaluationDefinition,
@@ -155,19 +166,20 @@ func (r *KeptnWorkloadInstanceReconciler) createKeptnEvaluation(ctx context.Cont
 			RetryInterval: metav1.Duration{
 				Duration: 5 * time.Second,
 			},
+			Deadline: evaluationDeadline,
 		},
 	}
-	err := controllerutil.SetControllerReference(workloadInstance, newEvaluation, r.Scheme)
+	err = controllerutil.SetControllerReference(workloadInstance, newEvaluation, r.Scheme)
 	if err != nil {
 		r.Log.Error(err, "could not set controller reference:")
 	}
-	err = r.Client.Create(ctx, newEvaluation)
+	err = apply.Apply(ctx, r.Client, newEvaluation, r.Recorder, workloadInstance)
 	if err != nil {
 		r.Log.Error(err, "could not create KeptnEvaluation")
-		r.recordEvent(phase, "Warning", workloadInstance, "CreateFailed", "could not create KeptnEvaluation")
+		r.recordEvent(phase, workloadInstance, reasons.CreateFailed, "KeptnEvaluation")
 		return "", err
 	}
-	r.recordEvent(phase, "Normal", workloadInstance, "Created", "created")
+	r.recordEvent(phase, workloadInstance, reasons.ChecksCreated)
 
 	return newEvaluation.Name, nil
 }
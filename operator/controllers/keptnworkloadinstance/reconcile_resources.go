@@ -0,0 +1,111 @@
+package keptnworkloadinstance
+
+import (
+	"context"
+
+	klcv1alpha1 "github.com/keptn/lifecycle-controller/operator/api/v1alpha1"
+	"github.com/keptn/lifecycle-controller/operator/api/v1alpha1/common"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+// summarizeResources records the resource footprint of the workload once it has been deployed, together with the
+// difference compared to the footprint recorded for the previous version
+func (r *KeptnWorkloadInstanceReconciler) summarizeResources(ctx context.Context, span trace.Span, workloadInstance *klcv1alpha1.KeptnWorkloadInstance) {
+	podSpec, replicas, err := r.getPodSpec(ctx, workloadInstance.Spec.ResourceReference, workloadInstance.Namespace)
+	if err != nil {
+		r.Log.Error(err, "could not retrieve the pod spec of the workload")
+		return
+	}
+	if podSpec == nil {
+		r.Log.Info("Skipping the resource summary, since the pod spec of the workload has not been found")
+		return
+	}
+	summary := newResourceSummary(*podSpec, replicas)
+
+	previousInstance, err := r.getPreviousWorkloadInstance(ctx, workloadInstance)
+	if err != nil {
+		r.Log.Error(err, "could not retrieve the previous version of the workload")
+	} else if previousInstance != nil && previousInstance.Status.ResourceSummary != nil {
+		// the summary of the previous version is used, since its ReplicaSet has been scaled down by now
+		previous := previousInstance.Status.ResourceSummary
+		summary.ReplicasDelta = summary.Replicas - previous.Replicas
+		summary.RequestsDelta = diffResourceLists(previous.Requests, summary.Requests)
+		summary.LimitsDelta = diffResourceLists(previous.Limits, summary.Limits)
+		span.SetAttributes(
+			common.WorkloadReplicasDelta.Int64(int64(summary.ReplicasDelta)),
+			common.WorkloadCPUDelta.Float64(cores(summary.RequestsDelta)),
+			common.WorkloadMemoryDelta.Int64(bytes(summary.RequestsDelta)),
+		)
+	}
+
+	workloadInstance.Status.ResourceSummary = summary
+	span.SetAttributes(resourceSummaryAttributes(summary)...)
+}
+
+// newResourceSummary sums up the requests and limits of the containers of all replicas of the pod spec
+func newResourceSummary(podSpec corev1.PodSpec, replicas int32) *klcv1alpha1.ResourceSummary {
+	summary := &klcv1alpha1.ResourceSummary{
+		Replicas: replicas,
+		Requests: corev1.ResourceList{},
+		Limits:   corev1.ResourceList{},
+	}
+	for _, c := range podSpec.Containers {
+		addResources(summary.Requests, c.Resources.Requests, replicas)
+		addResources(summary.Limits, c.Resources.Limits, replicas)
+	}
+	return summary
+}
+
+func addResources(total corev1.ResourceList, resources corev1.ResourceList, replicas int32) {
+	for name, quantity := range resources {
+		sum := total[name]
+		sum.Add(multiply(quantity, replicas))
+		total[name] = sum
+	}
+}
+
+func multiply(quantity resource.Quantity, n int32) resource.Quantity {
+	if quantity.MilliValue()%1000 != 0 {
+		return *resource.NewMilliQuantity(quantity.MilliValue()*int64(n), quantity.Format)
+	}
+	return *resource.NewQuantity(quantity.Value()*int64(n), quantity.Format)
+}
+
+// diffResourceLists returns the differences of the resources in current compared to previous
+func diffResourceLists(previous corev1.ResourceList, current corev1.ResourceList) corev1.ResourceList {
+	delta := corev1.ResourceList{}
+	for name, quantity := range current {
+		diff := quantity.DeepCopy()
+		diff.Sub(previous[name])
+		delta[name] = diff
+	}
+	for name, quantity := range previous {
+		if _, ok := current[name]; !ok {
+			diff := resource.Quantity{Format: quantity.Format}
+			diff.Sub(quantity)
+			delta[name] = diff
+		}
+	}
+	return delta
+}
+
+func resourceSummaryAttributes(summary *klcv1alpha1.ResourceSummary) []attribute.KeyValue {
+	return []attribute.KeyValue{
+		common.WorkloadReplicas.Int64(int64(summary.Replicas)),
+		common.WorkloadCPURequests.Float64(cores(summary.Requests)),
+		common.WorkloadCPULimits.Float64(cores(summary.Limits)),
+		common.WorkloadMemoryRequests.Int64(bytes(summary.Requests)),
+		common.WorkloadMemoryLimits.Int64(bytes(summary.Limits)),
+	}
+}
+
+func cores(resources corev1.ResourceList) float64 {
+	return float64(resources.Cpu().MilliValue()) / 1000
+}
+
+func bytes(resources corev1.ResourceList) int64 {
+	return resources.Memory().Value()
+}
@@ -0,0 +1,31 @@
+package keptnworkloadinstance
+
+import (
+	"context"
+
+	klcv1alpha1 "github.com/keptn/lifecycle-controller/operator/api/v1alpha1"
+	"github.com/keptn/lifecycle-controller/operator/api/v1alpha1/common"
+	"github.com/keptn/lifecycle-controller/operator/controllers/reasons"
+	"github.com/keptn/lifecycle-controller/operator/controllers/rollback"
+)
+
+// rollbackOnFailure rolls the workload back to its previous version if its post-deployment evaluations failed and
+// spec.rollbackOnFailure is set. The workload instance of the rollback is recorded in status.rollbackInstance.
+func (r *KeptnWorkloadInstanceReconciler) rollbackOnFailure(ctx context.Context, workloadInstance *klcv1alpha1.KeptnWorkloadInstance) {
+	phase := common.PhaseWorkloadPostEvaluation
+	if !workloadInstance.Spec.RollbackOnFailure || workloadInstance.Status.RollbackInstance != "" || !workloadInstance.IsPostDeploymentEvaluationFailed() {
+		return
+	}
+	if !r.RollbackEnabled {
+		r.recordEvent(phase, workloadInstance, reasons.RollbackDisabled)
+		return
+	}
+	rollbackInstance, err := rollback.Rollback(ctx, r.Client, workloadInstance)
+	if err != nil {
+		r.Log.Error(err, "could not roll back workload", "workload", workloadInstance.Spec.WorkloadName)
+		r.recordEvent(phase, workloadInstance, reasons.RollbackFailed, "the workload instance", err)
+		return
+	}
+	workloadInstance.Status.RollbackInstance = rollbackInstance.Name
+	r.recordEvent(phase, workloadInstance, reasons.RolledBack, "the workload instance", workloadInstance.Spec.PreviousVersion)
+}
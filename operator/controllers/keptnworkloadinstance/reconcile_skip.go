@@ -0,0 +1,36 @@
+package keptnworkloadinstance
+
+import (
+	"context"
+	"time"
+
+	klcv1alpha1 "github.com/keptn/lifecycle-controller/operator/api/v1alpha1"
+	"github.com/keptn/lifecycle-controller/operator/api/v1alpha1/common"
+	"github.com/keptn/lifecycle-controller/operator/controllers/reasons"
+)
+
+// skipPhase returns a reconciliation of the given phase which lets it succeed right away, without running its tasks
+// or evaluations, since the phase is skipped. A skipped pre-deployment phase still waits for the change request of the
+// workload instance, since skipping the tasks must not bypass the change management.
+func (r *KeptnWorkloadInstanceReconciler) skipPhase(workloadInstance *klcv1alpha1.KeptnWorkloadInstance, phase common.KeptnPhaseType) func(context.Context) (common.KeptnState, error) {
+	return func(ctx context.Context) (common.KeptnState, error) {
+		status := &workloadInstance.Status
+		// the evaluations of workload instances are reconciled in the evaluation phases of the app
+		switch phase {
+		case common.PhaseWorkloadPreDeployment:
+			state := r.reconcileChangeRequest(ctx, workloadInstance, time.Now())
+			status.PreDeploymentStatus = state
+			if !state.IsSucceeded() {
+				return state, r.Client.Status().Update(ctx, workloadInstance)
+			}
+		case common.PhaseWorkloadPreEvaluation, common.PhaseAppPreEvaluation:
+			status.PreDeploymentEvaluationStatus = common.StateSucceeded
+		case common.PhaseWorkloadPostDeployment:
+			status.PostDeploymentStatus = common.StateSucceeded
+		case common.PhaseWorkloadPostEvaluation, common.PhaseAppPostEvaluation:
+			status.PostDeploymentEvaluationStatus = common.StateSucceeded
+		}
+		r.recordEvent(phase, workloadInstance, reasons.Skipped)
+		return common.StateSucceeded, nil
+	}
+}
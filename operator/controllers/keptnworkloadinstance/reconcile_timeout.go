@@ -0,0 +1,57 @@
+package keptnworkloadinstance
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	klcv1alpha1 "github.com/keptn/lifecycle-controller/operator/api/v1alpha1"
+	"github.com/keptn/lifecycle-controller/operator/api/v1alpha1/common"
+	"github.com/keptn/lifecycle-controller/operator/controllers/deadline"
+	"github.com/keptn/lifecycle-controller/operator/controllers/reasons"
+	"github.com/keptn/lifecycle-controller/operator/metrics"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// reconcilePhaseTimeout fails the workload instance if the given phase runs longer than its timeout, and deletes the
+// tasks and evaluations that are still running. It returns whether the timeout has been exceeded.
+func (r *KeptnWorkloadInstanceReconciler) reconcilePhaseTimeout(ctx context.Context, ctxAppTrace context.Context, workloadInstance *klcv1alpha1.KeptnWorkloadInstance, phase common.KeptnPhaseType, spanPhase trace.Span) (bool, error) {
+	timeout := deadline.PhaseTimeout(workloadInstance.Spec.Timeouts, phase)
+	if !deadline.Exceeded(workloadInstance.Status.PhaseStartTime, timeout, time.Now()) {
+		return false, nil
+	}
+
+	status := &workloadInstance.Status
+	if err := deadline.Cleanup(ctx, r.Client, workloadInstance.Namespace, status.PreDeploymentTaskStatus, status.PreDeploymentEvaluationTaskStatus); err != nil {
+		return true, err
+	}
+	if err := deadline.Cleanup(ctx, r.Client, workloadInstance.Namespace, status.MigrationTaskStatus, nil); err != nil {
+		return true, err
+	}
+	if err := deadline.Cleanup(ctx, r.Client, workloadInstance.Namespace, status.PostDeploymentTaskStatus, status.PostDeploymentEvaluationTaskStatus); err != nil {
+		return true, err
+	}
+	deadline.FailPhase(&status.PreDeploymentStatus, &status.PreDeploymentEvaluationStatus, &status.MigrationStatus, &status.DeploymentStatus, &status.PostDeploymentStatus, &status.PostDeploymentEvaluationStatus)
+	status.Status = common.StateFailed
+	status.Reason = fmt.Sprintf("%s exceeded the timeout of %s", phase.LongName, timeout)
+	workloadInstance.SetEndTime()
+
+	r.recordEvent(phase, workloadInstance, reasons.TimedOut, timeout)
+	r.Meters.Add(ctx, metrics.DeploymentCount, 1, workloadInstance.GetMetricsAttributes()...)
+
+	spanPhase.AddEvent(phase.LongName + " has timed out")
+	spanPhase.SetStatus(codes.Error, reasons.TimedOut.Code)
+	spanPhase.End()
+	r.unbindSpan(workloadInstance, phase.ShortName)
+	r.endWorkloadInstanceSpan(workloadInstance, codes.Error, reasons.TimedOut.Code)
+
+	commentIssue := r.markIssueComment(workloadInstance)
+	if err := r.Client.Status().Update(ctx, workloadInstance); err != nil {
+		return true, err
+	}
+	if commentIssue {
+		r.commentOnIssue(ctx, ctxAppTrace, workloadInstance)
+	}
+	return true, nil
+}
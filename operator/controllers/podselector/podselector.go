@@ -0,0 +1,50 @@
+package podselector
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+
+	klcv1alpha1 "github.com/keptn/lifecycle-controller/operator/api/v1alpha1"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// replicaSetPodSuffix matches the random suffix of the names of the pods created by a ReplicaSet
+const replicaSetPodSuffix = "-[a-z0-9]{5}"
+
+// Pattern returns a regular expression matching the names of the pods of the referenced ReplicaSet or Pod. The
+// backslashes of the regular expression are escaped, so that it can be placed in a string literal of a PromQL query,
+// e.g. pod=~"<pattern>", without PromQL interpreting them as escape sequences.
+func Pattern(ctx context.Context, c client.Reader, resource klcv1alpha1.ResourceReference, namespace string) (string, error) {
+	if resource.Kind == "ReplicaSet" {
+		replicaSets := &appsv1.ReplicaSetList{}
+		if err := c.List(ctx, replicaSets, client.InNamespace(namespace)); err != nil {
+			return "", fmt.Errorf("could not list ReplicaSets: %w", err)
+		}
+		for _, rs := range replicaSets.Items {
+			if rs.UID == resource.UID {
+				return quote(rs.Name) + replicaSetPodSuffix, nil
+			}
+		}
+		return "", fmt.Errorf("could not find ReplicaSet with UID %s", resource.UID)
+	}
+
+	pods := &corev1.PodList{}
+	if err := c.List(ctx, pods, client.InNamespace(namespace)); err != nil {
+		return "", fmt.Errorf("could not list Pods: %w", err)
+	}
+	for _, pod := range pods.Items {
+		if pod.UID == resource.UID {
+			return quote(pod.Name), nil
+		}
+	}
+	return "", fmt.Errorf("could not find Pod with UID %s", resource.UID)
+}
+
+// quote escapes the metacharacters of the name for the regular expression, and the resulting backslashes for PromQL
+func quote(name string) string {
+	return strings.ReplaceAll(regexp.QuoteMeta(name), `\`, `\\`)
+}
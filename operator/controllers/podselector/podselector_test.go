@@ -0,0 +1,31 @@
+package podselector
+
+import (
+	"context"
+	"testing"
+
+	klcv1alpha1 "github.com/keptn/lifecycle-controller/operator/api/v1alpha1"
+	testrequire "github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestPattern(t *testing.T) {
+	pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "my.pod", Namespace: "default", UID: "pod-uid"}}
+	rs := &appsv1.ReplicaSet{ObjectMeta: metav1.ObjectMeta{Name: "my.app-6b474476c4", Namespace: "default", UID: "rs-uid"}}
+	c := fake.NewClientBuilder().WithObjects(pod, rs).Build()
+
+	// the backslashes escaping the dots are escaped themselves in the string literal of the PromQL query
+	pattern, err := Pattern(context.TODO(), c, klcv1alpha1.ResourceReference{UID: "pod-uid", Kind: "Pod"}, "default")
+	testrequire.Nil(t, err)
+	testrequire.Equal(t, `my\\.pod`, pattern)
+
+	pattern, err = Pattern(context.TODO(), c, klcv1alpha1.ResourceReference{UID: "rs-uid", Kind: "ReplicaSet"}, "default")
+	testrequire.Nil(t, err)
+	testrequire.Equal(t, `my\\.app-6b474476c4-[a-z0-9]{5}`, pattern)
+
+	_, err = Pattern(context.TODO(), c, klcv1alpha1.ResourceReference{UID: "unknown", Kind: "ReplicaSet"}, "default")
+	testrequire.NotNil(t, err)
+}
@@ -0,0 +1,49 @@
+package propagate
+
+import (
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Policy selects the labels and annotations of a KeptnApp that are copied to the KeptnAppVersions, KeptnWorkloadInstances,
+// KeptnTasks and Jobs generated for it, e.g. team or cost-center labels.
+// Keys ending with * select all keys with the given prefix, e.g. compliance.example.com/*.
+type Policy struct {
+	Labels      []string
+	Annotations []string
+}
+
+// IsEmpty returns whether the policy does not select any labels or annotations
+func (p Policy) IsEmpty() bool {
+	return len(p.Labels) == 0 && len(p.Annotations) == 0
+}
+
+// Copy copies the selected labels and annotations of the source to the target.
+// Labels and annotations the target already has are not overwritten.
+func (p Policy) Copy(source metav1.ObjectMeta, target *metav1.ObjectMeta) {
+	target.Labels = copySelected(p.Labels, source.Labels, target.Labels)
+	target.Annotations = copySelected(p.Annotations, source.Annotations, target.Annotations)
+}
+
+func copySelected(keys []string, source map[string]string, target map[string]string) map[string]string {
+	for key, value := range source {
+		if _, ok := target[key]; ok || !selected(keys, key) {
+			continue
+		}
+		if target == nil {
+			target = map[string]string{}
+		}
+		target[key] = value
+	}
+	return target
+}
+
+func selected(keys []string, key string) bool {
+	for _, k := range keys {
+		if k == key || (strings.HasSuffix(k, "*") && strings.HasPrefix(key, strings.TrimSuffix(k, "*"))) {
+			return true
+		}
+	}
+	return false
+}
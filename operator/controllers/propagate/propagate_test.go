@@ -0,0 +1,38 @@
+package propagate
+
+import (
+	"testing"
+
+	testrequire "github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestPolicy_Copy(t *testing.T) {
+	policy := Policy{
+		Labels:      []string{"team", "cost-center"},
+		Annotations: []string{"compliance.example.com/*"},
+	}
+	source := metav1.ObjectMeta{
+		Labels: map[string]string{"team": "checkout", "cost-center": "4711", "app": "podtato-head", "environment": "prod"},
+		Annotations: map[string]string{
+			"compliance.example.com/pci": "true",
+			"compliance.example.com/sox": "false",
+			"owner":                      "jane",
+		},
+	}
+	target := metav1.ObjectMeta{
+		Labels: map[string]string{"environment": "staging", "cost-center": "0815"},
+	}
+
+	policy.Copy(source, &target)
+
+	testrequire.Equal(t, map[string]string{"team": "checkout", "cost-center": "0815", "environment": "staging"}, target.Labels)
+	testrequire.Equal(t, map[string]string{"compliance.example.com/pci": "true", "compliance.example.com/sox": "false"}, target.Annotations)
+}
+
+func TestPolicy_CopyEmpty(t *testing.T) {
+	target := metav1.ObjectMeta{}
+	Policy{}.Copy(metav1.ObjectMeta{Labels: map[string]string{"team": "checkout"}}, &target)
+	testrequire.Nil(t, target.Labels)
+	testrequire.Nil(t, target.Annotations)
+}
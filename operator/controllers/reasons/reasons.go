@@ -0,0 +1,207 @@
+package reasons
+
+import (
+	"fmt"
+
+	"github.com/keptn/lifecycle-controller/operator/api/v1alpha1/common"
+)
+
+// CodeAnnotation is the annotation of the events of the lifecycle holding the code of their reason, since the reason of
+// these events is prefixed with the phase, e.g. AppPreDeployTasksFailed
+const CodeAnnotation = "keptn.sh/reason-code"
+
+// Severity is the severity of a reason, which is the type of the events recorded with it
+type Severity string
+
+const (
+	Normal  Severity = "Normal"
+	Warning Severity = "Warning"
+)
+
+// Reason is an entry of the catalog of reasons used by the events, conditions and span statuses of the operator.
+// Automation can match on its code, which does not change, while its message is meant to be read by humans.
+type Reason struct {
+	// Code is the stable CamelCase identifier of the reason, which matches its name, e.g. AppVersionNotFound. It must not
+	// be a severity, since automation matching on the code could not tell both apart.
+	Code string
+	// Severity is the type of the events recorded with the reason
+	Severity Severity
+	// Template is the message of the reason, formatted with the arguments of an occurrence
+	Template string
+}
+
+// Message returns the message of an occurrence of the reason with the given arguments
+func (r Reason) Message(args ...interface{}) string {
+	if len(args) == 0 {
+		return r.Template
+	}
+	return fmt.Sprintf(r.Template, args...)
+}
+
+// Annotations returns the annotations of events recorded with the reason
+func (r Reason) Annotations() map[string]string {
+	return map[string]string{CodeAnnotation: r.Code}
+}
+
+// reasons of the phases of app versions and workload instances
+var (
+	Started               = Reason{"Started", Normal, "have started"}
+	Finished              = Reason{"Finished", Normal, "is finished"}
+	NotFinished           = Reason{"NotFinished", Warning, "has not finished"}
+	Succeeded             = Reason{"Succeeded", Normal, "has succeeded"}
+	SucceededWithWarnings = Reason{"SucceededWithWarnings", Warning, "has succeeded, but optional workloads have failed"}
+	Failed                = Reason{"Failed", Warning, "has failed"}
+	Cancelled             = Reason{"Cancelled", Warning, "has been cancelled since app has been aborted"}
+	ReconcileErrored      = Reason{"ReconcileErrored", Warning, "could not get reconciled"}
+	Skipped               = Reason{"Skipped", Normal, "have been skipped"}
+	SkippedSingleWorkload = Reason{"SkippedSingleWorkload", Normal, "have been skipped since the app has a single workload without app-level tasks and evaluations"}
+	Delayed               = Reason{"Delayed", Normal, "will start in %s"}
+	TimedOut              = Reason{"TimedOut", Warning, "has failed since it runs longer than %s"}
+	DeadlineExceeded      = Reason{"DeadlineExceeded", Warning, "has failed since its deadline has passed"}
+	FailureSimulated      = Reason{"FailureSimulated", Warning, "fails since its failure is simulated"}
+	Paused                = Reason{"Paused", Normal, "is paused since the rollout of the Deployment is paused"}
+	Resumed               = Reason{"Resumed", Normal, "is resumed since the rollout of the Deployment is resumed"}
+	MigrationLocked       = Reason{"MigrationLocked", Normal, "waits since the migration of version %s of the workload is running"}
+)
+
+// reasons of app versions and workload instances as a whole, which are recorded without the prefix of a phase
+var (
+	Aborted                   = Reason{"Aborted", Warning, "has been cancelled"}
+	WaitingForPreviousVersion = Reason{"WaitingForPreviousVersion", Normal, "waits for AppVersion %s to complete"}
+	WorkloadNotFound          = Reason{"WorkloadNotFound", Warning, "could not find KeptnWorkloadInstance %s"}
+	IssueNotUpdated           = Reason{"IssueNotUpdated", Warning, "could not comment on issue %s"}
+)
+
+// reasons of the tasks and evaluations of app versions and workload instances
+var (
+	ChecksCreated           = Reason{"ChecksCreated", Normal, "created"}
+	CreateFailed            = Reason{"CreateFailed", Warning, "could not create %s"}
+	TaskStatusChanged       = Reason{"TaskStatusChanged", Normal, "task status changed from %s to %s"}
+	TaskDependencyFailed    = Reason{"TaskDependencyFailed", Warning, "task %s cannot be started: %s"}
+	EvaluationStatusChanged = Reason{"EvaluationStatusChanged", Normal, "evaluation status changed from %s to %s"}
+	EvaluationWarning       = Reason{"EvaluationWarning", Warning, "has succeeded with warnings"}
+	RetryCountExceeded      = Reason{"RetryCountExceeded", Warning, "has failed since its retry count has been exceeded"}
+	ProviderUnavailable     = Reason{"ProviderUnavailable", Warning, "has failed since provider is unavailable"}
+	TaskDefinitionNotFound  = Reason{"TaskDefinitionNotFound", Warning, "has failed since the task definition could not be found"}
+	TaskDeleted             = Reason{"TaskDeleted", Warning, "KeptnTask has been deleted"}
+)
+
+// reasons of the Jobs of tasks
+var (
+	TaskDefinitionMergeFailure = Reason{"TaskDefinitionMergeFailure", Warning, "could not merge KeptnTaskDefinition %s"}
+	JobNotCreated              = Reason{"JobNotCreated", Warning, "could not create Job"}
+	JobCreated                 = Reason{"JobCreated", Normal, "created Job %s"}
+	JobReferenceRemoved        = Reason{"JobReferenceRemoved", Warning, "removed the reference of Job %s since it could not be found"}
+	TimeoutExceeded            = Reason{"TimeoutExceeded", Warning, "Job has been terminated since it has exceeded the timeout of the task"}
+	JobFailed                  = Reason{"JobFailed", Warning, "Job has failed after %d attempts"}
+	JobRetried                 = Reason{"JobRetried", Normal, "starting attempt %d of %d since Job %s has failed"}
+	JobAdopted                 = Reason{"JobAdopted", Normal, "adopted Job %s created by another replica"}
+	JobLost                    = Reason{"JobLost", Warning, "Job of the exactly-once task has been lost"}
+	JobThrottled               = Reason{"JobThrottled", Normal, "Job is not created since %d task Jobs are running"}
+	WaitStarted                = Reason{"WaitStarted", Normal, "waiting for %s"}
+)
+
+// reasons of workload instances waiting for their app version
+var (
+	GetAppVersionFailed = Reason{"GetAppVersionFailed", Warning, "has failed since app could not be retrieved"}
+	AppVersionNotFound  = Reason{"AppVersionNotFound", Warning, "has failed since app could not be found"}
+	AppFailed           = Reason{"AppFailed", Warning, "has failed since app has failed"}
+	AppNotFinished      = Reason{"AppNotFinished", Normal, "has not started since the pre-deployment evaluations of the app have not finished"}
+	AppRejected         = Reason{"AppRejected", Warning, "has failed since app has been rejected"}
+	AppApprovalPending  = Reason{"AppApprovalPending", Normal, "has not been given for app yet"}
+)
+
+// reasons of approvals, change requests, cost limits, incidents and rollbacks
+var (
+	ApprovalRequested     = Reason{"ApprovalRequested", Normal, "is required, approve with the %s annotation"}
+	ApprovalGranted       = Reason{"ApprovalGranted", Normal, "has been given by %s"}
+	ApprovalDenied        = Reason{"ApprovalDenied", Warning, "has been denied by %s"}
+	ChangeRequestApproved = Reason{"ChangeRequestApproved", Normal, "allows the deployment since %s"}
+	ChangeRequestPending  = Reason{"ChangeRequestPending", Normal, "waits since %s"}
+	ChangeRequestDenied   = Reason{"ChangeRequestDenied", Warning, "has failed since %s"}
+	CostIncreaseExceeded  = Reason{"CostIncreaseExceeded", Warning, "has failed since the hourly cost increases by %.4f, which exceeds the limit of %.4f"}
+	IncidentOpened        = Reason{"IncidentOpened", Normal, "has been opened"}
+	IncidentOpenFailed    = Reason{"IncidentOpenFailed", Warning, "could not be opened"}
+	RollbackDisabled      = Reason{"RollbackDisabled", Warning, "is not rolled back since the Rollback feature gate is disabled"}
+	RollbackFailed        = Reason{"RollbackFailed", Warning, "could not roll back %s: %s"}
+	RolledBack            = Reason{"RolledBack", Normal, "has rolled back %s to version %s"}
+)
+
+// reasons of the conditions of apps and workloads
+var (
+	DefinitionsResolved  = Reason{"DefinitionsResolved", Normal, "all task and evaluation definitions exist"}
+	DefinitionsNotFound  = Reason{"DefinitionsNotFound", Warning, "%s not found: %s"}
+	WorkloadsFound       = Reason{"WorkloadsFound", Normal, "all workloads exist"}
+	WorkloadsNotFound    = Reason{"WorkloadsNotFound", Warning, "workloads not found: %s"}
+	InSync               = Reason{"InSync", Normal, "the running pods of all workloads have been deployed by %s"}
+	DriftDetected        = Reason{"DriftDetected", Warning, "workloads differ from %s: %s"}
+	VersionDriftDetected = Reason{"VersionDriftDetected", Warning, DriftDetected.Template}
+	VersionDriftResolved = Reason{"VersionDriftResolved", Normal, InSync.Template}
+	VersionChanged       = Reason{"VersionChanged", Warning, "pods of another version are running"}
+	ResourceReplaced     = Reason{"ResourceReplaced", Warning, "pods of another resource are running"}
+	NotRunning           = Reason{"NotRunning", Warning, "no pods are running"}
+)
+
+// reasons of the conditions of evaluation providers and definition sources
+var (
+	Reachable           = Reason{"Reachable", Normal, "the provider is reachable at %s"}
+	Unreachable         = Reason{"Unreachable", Warning, "the provider is unreachable: %v"}
+	Authenticated       = Reason{"Authenticated", Normal, "the credentials of secret %s have been accepted"}
+	Unauthorized        = Reason{"Unauthorized", Warning, "provider rejected the credentials of secret %s with status %s"}
+	UnexpectedResponse  = Reason{"UnexpectedResponse", Warning, "provider responded to the query up with status %s"}
+	InvalidTargetServer = Reason{"InvalidTargetServer", Warning, "the target server is invalid: %v"}
+	SecretNotFound      = Reason{"SecretNotFound", Warning, "the secret of the credentials could not be retrieved: %v"}
+	Synced              = Reason{"Synced", Normal, "applied %d definitions of %s"}
+	InvalidURL          = Reason{"InvalidURL", Warning, "the URL of the artifact is invalid: %v"}
+	PullFailed          = Reason{"PullFailed", Warning, "the artifact could not be pulled: %v"}
+	InvalidArtifact     = Reason{"InvalidArtifact", Warning, "the artifact does not contain valid definitions: %v"}
+	ApplyFailed         = Reason{"ApplyFailed", Warning, "the definitions could not be applied: %v"}
+	PruneFailed         = Reason{"PruneFailed", Warning, "the definitions removed from the artifact could not be deleted: %v"}
+)
+
+// reasons of spans which are not the phases of a deployment
+var (
+	InvalidAnnotations = Reason{"InvalidAnnotations", Warning, "the Keptn annotations of the pod are invalid"}
+	MarshalFailed      = Reason{"MarshalFailed", Warning, "the mutated pod could not be marshalled"}
+)
+
+// All is the catalog of all reasons
+var All = []Reason{
+	Started, Finished, NotFinished, Succeeded, SucceededWithWarnings, Failed, Cancelled, ReconcileErrored, Skipped,
+	SkippedSingleWorkload, Delayed, TimedOut, DeadlineExceeded, FailureSimulated, Paused, Resumed, MigrationLocked,
+	Aborted, WaitingForPreviousVersion, WorkloadNotFound, IssueNotUpdated,
+	ChecksCreated, CreateFailed, TaskStatusChanged, TaskDependencyFailed, EvaluationStatusChanged, EvaluationWarning,
+	RetryCountExceeded, ProviderUnavailable, TaskDefinitionNotFound, TaskDeleted,
+	TaskDefinitionMergeFailure, JobNotCreated, JobCreated, JobReferenceRemoved, TimeoutExceeded, JobFailed, JobRetried,
+	JobAdopted, JobLost, JobThrottled, WaitStarted,
+	GetAppVersionFailed, AppVersionNotFound, AppFailed, AppNotFinished, AppRejected, AppApprovalPending,
+	ApprovalRequested, ApprovalGranted, ApprovalDenied, ChangeRequestApproved, ChangeRequestPending, ChangeRequestDenied,
+	CostIncreaseExceeded, IncidentOpened, IncidentOpenFailed, RollbackDisabled, RollbackFailed, RolledBack,
+	DefinitionsResolved, DefinitionsNotFound, WorkloadsFound, WorkloadsNotFound, InSync, DriftDetected,
+	VersionDriftDetected, VersionDriftResolved, VersionChanged, ResourceReplaced, NotRunning,
+	Reachable, Unreachable, Authenticated, Unauthorized, UnexpectedResponse, InvalidTargetServer, SecretNotFound,
+	Synced, InvalidURL, PullFailed, InvalidArtifact, ApplyFailed, PruneFailed,
+	InvalidAnnotations, MarshalFailed,
+}
+
+// Lookup returns the reason of the given code
+func Lookup(code string) (Reason, bool) {
+	for _, reason := range All {
+		if reason.Code == code {
+			return reason, true
+		}
+	}
+	return Reason{}, false
+}
+
+// ForState returns the reason of a completed evaluation or task of the given state
+func ForState(state common.KeptnState) Reason {
+	switch {
+	case state.IsWarning():
+		return EvaluationWarning
+	case state.IsSucceeded():
+		return Succeeded
+	default:
+		return Failed
+	}
+}
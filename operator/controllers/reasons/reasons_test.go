@@ -0,0 +1,50 @@
+package reasons
+
+import (
+	"testing"
+
+	"github.com/keptn/lifecycle-controller/operator/api/v1alpha1/common"
+	testrequire "github.com/stretchr/testify/require"
+)
+
+func TestCatalog(t *testing.T) {
+	codes := map[string]bool{}
+	for _, reason := range All {
+		testrequire.NotEmpty(t, reason.Code)
+		testrequire.NotEmpty(t, reason.Template, reason.Code)
+		testrequire.Contains(t, []Severity{Normal, Warning}, reason.Severity, reason.Code)
+		testrequire.False(t, codes[reason.Code], "duplicate code %s", reason.Code)
+		testrequire.NotContains(t, []string{string(Normal), string(Warning)}, reason.Code, "code %s is a severity", reason.Code)
+		codes[reason.Code] = true
+	}
+
+	reason, ok := Lookup("AppVersionNotFound")
+	testrequire.True(t, ok)
+	testrequire.Equal(t, AppVersionNotFound, reason)
+	reason, ok = Lookup("EvaluationWarning")
+	testrequire.True(t, ok)
+	testrequire.Equal(t, EvaluationWarning, reason)
+	reason, ok = Lookup("RetryCountExceeded")
+	testrequire.True(t, ok)
+	testrequire.Equal(t, RetryCountExceeded, reason)
+	reason, ok = Lookup("JobRetried")
+	testrequire.True(t, ok)
+	testrequire.Equal(t, JobRetried, reason)
+	_, ok = Lookup("Warning")
+	testrequire.False(t, ok)
+	_, ok = Lookup("Unknown")
+	testrequire.False(t, ok)
+}
+
+func TestMessage(t *testing.T) {
+	testrequire.Equal(t, "has failed since app could not be retrieved", GetAppVersionFailed.Message())
+	testrequire.Equal(t, "has failed since it runs longer than 5m0s", TimedOut.Message("5m0s"))
+	testrequire.Equal(t, "KeptnTaskDefinitions not found: a, b", DefinitionsNotFound.Message("KeptnTaskDefinitions", "a, b"))
+	testrequire.Equal(t, map[string]string{CodeAnnotation: "TaskDependencyFailed"}, TaskDependencyFailed.Annotations())
+}
+
+func TestForState(t *testing.T) {
+	testrequire.Equal(t, Succeeded, ForState(common.StateSucceeded))
+	testrequire.Equal(t, EvaluationWarning, ForState(common.StateWarning))
+	testrequire.Equal(t, Failed, ForState(common.StateFailed))
+}
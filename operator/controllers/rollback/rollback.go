@@ -0,0 +1,202 @@
+package rollback
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	klcv1alpha1 "github.com/keptn/lifecycle-controller/operator/api/v1alpha1"
+	"github.com/keptn/lifecycle-controller/operator/api/v1alpha1/common"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// ErrNoPreviousVersion is returned if the failed workload instance has no previous version to roll back to
+var ErrNoPreviousVersion = errors.New("no previous version to roll back to")
+
+//+kubebuilder:rbac:groups=apps,resources=deployments;statefulsets,verbs=get;list;watch;patch
+//+kubebuilder:rbac:groups=apps,resources=replicasets;controllerrevisions,verbs=get;list;watch
+//+kubebuilder:rbac:groups=lifecycle.keptn.sh,resources=keptnworkloadinstances,verbs=get;list;watch;create
+
+// Rollback restores the pod template of the Deployment or StatefulSet of the failed workload instance to the one of
+// its previous version, like kubectl rollout undo, and creates a KeptnWorkloadInstance tracking the rollback.
+// It returns the workload instance of the rollback.
+func Rollback(ctx context.Context, c client.Client, failed *klcv1alpha1.KeptnWorkloadInstance) (*klcv1alpha1.KeptnWorkloadInstance, error) {
+	if failed.Spec.PreviousVersion == "" {
+		return nil, ErrNoPreviousVersion
+	}
+	previous := &klcv1alpha1.KeptnWorkloadInstance{}
+	previousName := common.CreateInstanceName(failed.Spec.WorkloadName, failed.Spec.PreviousVersion)
+	if err := c.Get(ctx, types.NamespacedName{Namespace: failed.Namespace, Name: previousName}, previous); err != nil {
+		return nil, fmt.Errorf("could not retrieve KeptnWorkloadInstance %s of the previous version: %w", previousName, err)
+	}
+	if err := previous.VerifyIdentity(failed.Spec.WorkloadName, failed.Spec.PreviousVersion); err != nil {
+		return nil, fmt.Errorf("could not retrieve KeptnWorkloadInstance %s of the previous version: %w", previousName, err)
+	}
+
+	var reference klcv1alpha1.ResourceReference
+	var err error
+	switch failed.Spec.ResourceReference.Kind {
+	case "ReplicaSet":
+		reference, err = rollbackDeployment(ctx, c, failed, previous)
+	case "StatefulSet":
+		reference, err = rollbackStatefulSet(ctx, c, failed)
+	default:
+		err = fmt.Errorf("rollback of %s is not supported", failed.Spec.ResourceReference.Kind)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return createRollbackInstance(ctx, c, failed, previous, reference)
+}
+
+// rollbackDeployment restores the pod template of the ReplicaSet of the previous version in the Deployment owning the
+// ReplicaSet of the failed version. The Deployment scales up the ReplicaSet of the previous version again.
+func rollbackDeployment(ctx context.Context, c client.Client, failed *klcv1alpha1.KeptnWorkloadInstance, previous *klcv1alpha1.KeptnWorkloadInstance) (klcv1alpha1.ResourceReference, error) {
+	replicaSets := &appsv1.ReplicaSetList{}
+	if err := c.List(ctx, replicaSets, client.InNamespace(failed.Namespace)); err != nil {
+		return klcv1alpha1.ResourceReference{}, fmt.Errorf("could not list ReplicaSets: %w", err)
+	}
+	var current, previousReplicaSet *appsv1.ReplicaSet
+	for i := range replicaSets.Items {
+		switch replicaSets.Items[i].UID {
+		case failed.Spec.ResourceReference.UID:
+			current = &replicaSets.Items[i]
+		case previous.Spec.ResourceReference.UID:
+			previousReplicaSet = &replicaSets.Items[i]
+		}
+	}
+	if current == nil || previousReplicaSet == nil {
+		return klcv1alpha1.ResourceReference{}, fmt.Errorf("could not find the ReplicaSets of the failed and of the previous version")
+	}
+	owner := metav1.GetControllerOf(current)
+	if owner == nil || owner.Kind != "Deployment" {
+		return klcv1alpha1.ResourceReference{}, fmt.Errorf("ReplicaSet %s is not owned by a Deployment", current.Name)
+	}
+
+	deployment := &appsv1.Deployment{}
+	if err := c.Get(ctx, types.NamespacedName{Namespace: failed.Namespace, Name: owner.Name}, deployment); err != nil {
+		return klcv1alpha1.ResourceReference{}, fmt.Errorf("could not retrieve Deployment %s: %w", owner.Name, err)
+	}
+	template := previousReplicaSet.Spec.Template.DeepCopy()
+	delete(template.Labels, appsv1.DefaultDeploymentUniqueLabelKey)
+
+	patch := client.MergeFrom(deployment.DeepCopy())
+	deployment.Spec.Template = *template
+	if err := c.Patch(ctx, deployment, patch); err != nil {
+		return klcv1alpha1.ResourceReference{}, fmt.Errorf("could not roll back Deployment %s: %w", deployment.Name, err)
+	}
+	return previous.Spec.ResourceReference, nil
+}
+
+// rollbackStatefulSet restores the pod template of the latest revision of the StatefulSet before its current one
+func rollbackStatefulSet(ctx context.Context, c client.Client, failed *klcv1alpha1.KeptnWorkloadInstance) (klcv1alpha1.ResourceReference, error) {
+	statefulSets := &appsv1.StatefulSetList{}
+	if err := c.List(ctx, statefulSets, client.InNamespace(failed.Namespace)); err != nil {
+		return klcv1alpha1.ResourceReference{}, fmt.Errorf("could not list StatefulSets: %w", err)
+	}
+	var statefulSet *appsv1.StatefulSet
+	for i := range statefulSets.Items {
+		if statefulSets.Items[i].UID == failed.Spec.ResourceReference.UID {
+			statefulSet = &statefulSets.Items[i]
+		}
+	}
+	if statefulSet == nil {
+		return klcv1alpha1.ResourceReference{}, fmt.Errorf("could not find the StatefulSet of the failed version")
+	}
+
+	revisions := &appsv1.ControllerRevisionList{}
+	if err := c.List(ctx, revisions, client.InNamespace(failed.Namespace)); err != nil {
+		return klcv1alpha1.ResourceReference{}, fmt.Errorf("could not list ControllerRevisions: %w", err)
+	}
+	revision := previousRevision(revisions.Items, statefulSet)
+	if revision == nil {
+		return klcv1alpha1.ResourceReference{}, fmt.Errorf("StatefulSet %s has no previous revision", statefulSet.Name)
+	}
+	template, err := revisionTemplate(revision)
+	if err != nil {
+		return klcv1alpha1.ResourceReference{}, err
+	}
+
+	patch := client.MergeFrom(statefulSet.DeepCopy())
+	statefulSet.Spec.Template = *template
+	if err := c.Patch(ctx, statefulSet, patch); err != nil {
+		return klcv1alpha1.ResourceReference{}, fmt.Errorf("could not roll back StatefulSet %s: %w", statefulSet.Name, err)
+	}
+	return failed.Spec.ResourceReference, nil
+}
+
+// previousRevision returns the latest revision of the StatefulSet before its update revision, or nil if there is none
+func previousRevision(revisions []appsv1.ControllerRevision, statefulSet *appsv1.StatefulSet) *appsv1.ControllerRevision {
+	var previous *appsv1.ControllerRevision
+	for i := range revisions {
+		revision := &revisions[i]
+		owner := metav1.GetControllerOf(revision)
+		if owner == nil || owner.UID != statefulSet.UID || revision.Name == statefulSet.Status.UpdateRevision {
+			continue
+		}
+		if previous == nil || revision.Revision > previous.Revision {
+			previous = revision
+		}
+	}
+	return previous
+}
+
+// revisionTemplate returns the pod template stored in a revision of a StatefulSet, which is a patch of its spec
+func revisionTemplate(revision *appsv1.ControllerRevision) (*corev1.PodTemplateSpec, error) {
+	data := struct {
+		Spec struct {
+			Template corev1.PodTemplateSpec `json:"template"`
+		} `json:"spec"`
+	}{}
+	if err := json.Unmarshal(revision.Data.Raw, &data); err != nil {
+		return nil, fmt.Errorf("could not decode ControllerRevision %s: %w", revision.Name, err)
+	}
+	return &data.Spec.Template, nil
+}
+
+// createRollbackInstance creates the workload instance tracking the rollback to the previous version. It runs the
+// post-deployment checks of the previous version, but neither its pre-deployment checks nor another rollback.
+func createRollbackInstance(ctx context.Context, c client.Client, failed *klcv1alpha1.KeptnWorkloadInstance, previous *klcv1alpha1.KeptnWorkloadInstance, reference klcv1alpha1.ResourceReference) (*klcv1alpha1.KeptnWorkloadInstance, error) {
+	spec := *previous.Spec.KeptnWorkloadSpec.DeepCopy()
+	spec.ResourceReference = reference
+	spec.PreDeploymentTasks = nil
+	spec.PreDeploymentEvaluations = nil
+	spec.ChangeRequest = ""
+	spec.SimulateFailure = ""
+	spec.RollbackOnFailure = false
+
+	labels := map[string]string{}
+	for key, value := range failed.Labels {
+		labels[key] = value
+	}
+	labels[common.InstanceIdLabel] = common.IdentityHash(failed.Spec.WorkloadName, failed.Spec.Version, "rollback")
+	rollbackInstance := &klcv1alpha1.KeptnWorkloadInstance{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            common.CreateResourceName(common.MaxK8sObjectLength, failed.Spec.WorkloadName, failed.Spec.Version, "rollback"),
+			Namespace:       failed.Namespace,
+			Labels:          labels,
+			Annotations:     map[string]string{common.RollbackOfAnnotation: failed.Name, common.InstanceIdentityAnnotation: common.Identity(failed.Spec.AppName, failed.Spec.WorkloadName, failed.Spec.Version, "rollback")},
+			OwnerReferences: failed.OwnerReferences,
+		},
+		Spec: klcv1alpha1.KeptnWorkloadInstanceSpec{
+			KeptnWorkloadSpec: spec,
+			WorkloadName:      failed.Spec.WorkloadName,
+			PreviousVersion:   failed.Spec.Version,
+			TraceId:           failed.Spec.TraceId,
+		},
+	}
+	err := c.Create(ctx, rollbackInstance)
+	if apierrors.IsAlreadyExists(err) {
+		err = c.Get(ctx, client.ObjectKeyFromObject(rollbackInstance), rollbackInstance)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("could not create KeptnWorkloadInstance %s: %w", rollbackInstance.Name, err)
+	}
+	return rollbackInstance, nil
+}
@@ -0,0 +1,122 @@
+package rollback
+
+import (
+	"context"
+	"testing"
+
+	klcv1alpha1 "github.com/keptn/lifecycle-controller/operator/api/v1alpha1"
+	"github.com/keptn/lifecycle-controller/operator/api/v1alpha1/common"
+	testrequire "github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestRollback_Deployment(t *testing.T) {
+	scheme := runtime.NewScheme()
+	testrequire.Nil(t, clientgoscheme.AddToScheme(scheme))
+	testrequire.Nil(t, klcv1alpha1.AddToScheme(scheme))
+
+	isController := true
+	template := func(image string, hash string) corev1.PodTemplateSpec {
+		return corev1.PodTemplateSpec{
+			ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"app": "podinfo", appsv1.DefaultDeploymentUniqueLabelKey: hash}},
+			Spec:       corev1.PodSpec{Containers: []corev1.Container{{Name: "podinfo", Image: image}}},
+		}
+	}
+	replicaSet := func(name string, image string) *appsv1.ReplicaSet {
+		return &appsv1.ReplicaSet{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:            name,
+				Namespace:       "default",
+				UID:             types.UID(name),
+				OwnerReferences: []metav1.OwnerReference{{APIVersion: "apps/v1", Kind: "Deployment", Name: "podinfo", UID: "podinfo", Controller: &isController}},
+			},
+			Spec: appsv1.ReplicaSetSpec{Template: template(image, name)},
+		}
+	}
+	workloadInstance := func(version string, previousVersion string, replicaSet string) *klcv1alpha1.KeptnWorkloadInstance {
+		return &klcv1alpha1.KeptnWorkloadInstance{
+			ObjectMeta: metav1.ObjectMeta{Name: common.CreateInstanceName("podinfo-podinfo", version), Namespace: "default"},
+			Spec: klcv1alpha1.KeptnWorkloadInstanceSpec{
+				KeptnWorkloadSpec: klcv1alpha1.KeptnWorkloadSpec{
+					AppName:                   "podinfo",
+					Version:                   version,
+					ResourceReference:         klcv1alpha1.ResourceReference{UID: types.UID(replicaSet), Kind: "ReplicaSet"},
+					PreDeploymentTasks:        []string{"check"},
+					PostDeploymentEvaluations: []string{"slo"},
+					RollbackOnFailure:         true,
+				},
+				WorkloadName:    "podinfo-podinfo",
+				PreviousVersion: previousVersion,
+			},
+		}
+	}
+	deployment := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: "podinfo", Namespace: "default", UID: "podinfo"},
+		Spec:       appsv1.DeploymentSpec{Template: template("podinfo:2.0", "")},
+	}
+	previous := workloadInstance("1.0", "", "podinfo-1")
+	failed := workloadInstance("2.0", "1.0", "podinfo-2")
+
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+		deployment, replicaSet("podinfo-1", "podinfo:1.0"), replicaSet("podinfo-2", "podinfo:2.0"), previous, failed,
+	).Build()
+
+	rollbackInstance, err := Rollback(context.TODO(), c, failed)
+	testrequire.Nil(t, err)
+	testrequire.Equal(t, types.UID("podinfo-1"), rollbackInstance.Spec.ResourceReference.UID)
+	testrequire.Equal(t, "1.0", rollbackInstance.Spec.Version)
+	testrequire.Equal(t, "2.0", rollbackInstance.Spec.PreviousVersion)
+	testrequire.Empty(t, rollbackInstance.Spec.PreDeploymentTasks)
+	testrequire.Equal(t, []string{"slo"}, rollbackInstance.Spec.PostDeploymentEvaluations)
+	testrequire.False(t, rollbackInstance.Spec.RollbackOnFailure)
+	testrequire.Equal(t, failed.Name, rollbackInstance.Annotations[common.RollbackOfAnnotation])
+
+	testrequire.Nil(t, c.Get(context.TODO(), types.NamespacedName{Namespace: "default", Name: "podinfo"}, deployment))
+	testrequire.Equal(t, "podinfo:1.0", deployment.Spec.Template.Spec.Containers[0].Image)
+	testrequire.NotContains(t, deployment.Spec.Template.Labels, appsv1.DefaultDeploymentUniqueLabelKey)
+
+	// rolling back again returns the existing workload instance of the rollback
+	again, err := Rollback(context.TODO(), c, failed)
+	testrequire.Nil(t, err)
+	testrequire.Equal(t, rollbackInstance.Name, again.Name)
+
+	_, err = Rollback(context.TODO(), c, previous)
+	testrequire.ErrorIs(t, err, ErrNoPreviousVersion)
+}
+
+func TestPreviousRevision(t *testing.T) {
+	isController := true
+	statefulSet := &appsv1.StatefulSet{
+		ObjectMeta: metav1.ObjectMeta{Name: "db", UID: "db"},
+		Status:     appsv1.StatefulSetStatus{UpdateRevision: "db-3"},
+	}
+	revision := func(name string, owner types.UID, number int64, image string) appsv1.ControllerRevision {
+		return appsv1.ControllerRevision{
+			ObjectMeta: metav1.ObjectMeta{Name: name, OwnerReferences: []metav1.OwnerReference{{Kind: "StatefulSet", Name: string(owner), UID: owner, Controller: &isController}}},
+			Revision:   number,
+			Data:       runtime.RawExtension{Raw: []byte(`{"spec":{"template":{"spec":{"containers":[{"name":"db","image":"` + image + `"}]}}}}`)},
+		}
+	}
+	revisions := []appsv1.ControllerRevision{
+		revision("db-1", "db", 1, "db:1"),
+		revision("db-3", "db", 3, "db:3"),
+		revision("db-2", "db", 2, "db:2"),
+		revision("other-4", "other", 4, "other:4"),
+	}
+
+	previous := previousRevision(revisions, statefulSet)
+	testrequire.NotNil(t, previous)
+	testrequire.Equal(t, "db-2", previous.Name)
+
+	template, err := revisionTemplate(previous)
+	testrequire.Nil(t, err)
+	testrequire.Equal(t, "db:2", template.Spec.Containers[0].Image)
+
+	testrequire.Nil(t, previousRevision(revisions[1:2], statefulSet))
+}
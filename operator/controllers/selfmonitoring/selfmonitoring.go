@@ -0,0 +1,95 @@
+package selfmonitoring
+
+import (
+	"context"
+	"math/rand"
+	"net/http"
+	"sync/atomic"
+
+	"github.com/keptn/lifecycle-controller/operator/api/v1alpha1/common"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// SampleRatio is the share of the reconciliations that are traced, between 0 (none) and 1 (all).
+// It is set from the RECONCILE_TRACE_SAMPLE_RATIO environment variable before the controllers are started.
+var SampleRatio float64
+
+const (
+	OutcomeSuccess      = "success"
+	OutcomeRequeue      = "requeue"
+	OutcomeRequeueAfter = "requeue_after"
+	OutcomeError        = "error"
+)
+
+type apiCallsKey struct{}
+
+type reconciler struct {
+	controller string
+	reconciler reconcile.Reconciler
+}
+
+// Wrap returns a reconciler recording a span for every sampled reconciliation of the given controller.
+// The spans are the roots of their own traces, so they are kept apart from the traces of the deployments.
+func Wrap(controller string, r reconcile.Reconciler) reconcile.Reconciler {
+	return &reconciler{controller: controller, reconciler: r}
+}
+
+func (r *reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	if SampleRatio <= 0 || rand.Float64() >= SampleRatio {
+		return r.reconciler.Reconcile(ctx, req)
+	}
+
+	// the span is not passed to the reconciler, which would make it the parent of the spans of the deployments
+	_, span := otel.Tracer("keptn/operator/selfmonitoring").Start(ctx, "reconcile_"+r.controller, trace.WithNewRoot())
+	defer span.End()
+
+	var apiCalls int64
+	result, err := r.reconciler.Reconcile(context.WithValue(ctx, apiCallsKey{}, &apiCalls), req)
+
+	outcome := Outcome(result, err)
+	span.SetAttributes(
+		common.ReconcileController.String(r.controller),
+		common.ReconcileKey.String(req.String()),
+		common.ReconcileOutcome.String(outcome),
+		common.ReconcileAPICalls.Int64(atomic.LoadInt64(&apiCalls)),
+	)
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+	}
+	return result, err
+}
+
+// Outcome returns how a reconciliation with the given result and error ended
+func Outcome(result ctrl.Result, err error) string {
+	switch {
+	case err != nil:
+		return OutcomeError
+	case result.RequeueAfter > 0:
+		return OutcomeRequeueAfter
+	case result.Requeue:
+		return OutcomeRequeue
+	default:
+		return OutcomeSuccess
+	}
+}
+
+// CountAPICalls wraps the transport of the clients, counting the requests sent to the API server during a traced
+// reconciliation. Reads served from the cache of the manager do not reach the API server and are not counted.
+func CountAPICalls(rt http.RoundTripper) http.RoundTripper {
+	return &countingRoundTripper{next: rt}
+}
+
+type countingRoundTripper struct {
+	next http.RoundTripper
+}
+
+func (t *countingRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	if apiCalls, ok := req.Context().Value(apiCallsKey{}).(*int64); ok {
+		atomic.AddInt64(apiCalls, 1)
+	}
+	return t.next.RoundTrip(req)
+}
@@ -0,0 +1,53 @@
+package selfmonitoring
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	testrequire "github.com/stretchr/testify/require"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+func TestOutcome(t *testing.T) {
+	testrequire.Equal(t, OutcomeSuccess, Outcome(ctrl.Result{}, nil))
+	testrequire.Equal(t, OutcomeRequeue, Outcome(ctrl.Result{Requeue: true}, nil))
+	testrequire.Equal(t, OutcomeRequeueAfter, Outcome(ctrl.Result{Requeue: true, RequeueAfter: time.Second}, nil))
+	testrequire.Equal(t, OutcomeError, Outcome(ctrl.Result{RequeueAfter: time.Second}, fmt.Errorf("failed")))
+}
+
+func TestCountAPICalls(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+	httpClient := &http.Client{Transport: CountAPICalls(http.DefaultTransport)}
+
+	var apiCalls int64
+	inner := reconcile.Func(func(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+		for i := 0; i < 3; i++ {
+			httpReq, err := http.NewRequestWithContext(ctx, http.MethodGet, server.URL, nil)
+			testrequire.Nil(t, err)
+			resp, err := httpClient.Do(httpReq)
+			testrequire.Nil(t, err)
+			resp.Body.Close()
+		}
+		apiCalls = *ctx.Value(apiCallsKey{}).(*int64)
+		return ctrl.Result{}, nil
+	})
+
+	SampleRatio = 1
+	defer func() { SampleRatio = 0 }()
+	_, err := Wrap("test", inner).Reconcile(context.TODO(), ctrl.Request{})
+	testrequire.Nil(t, err)
+	testrequire.Equal(t, int64(3), apiCalls)
+
+	// requests outside of a traced reconciliation are not counted
+	httpReq, err := http.NewRequest(http.MethodGet, server.URL, nil)
+	testrequire.Nil(t, err)
+	resp, err := httpClient.Do(httpReq)
+	testrequire.Nil(t, err)
+	resp.Body.Close()
+}
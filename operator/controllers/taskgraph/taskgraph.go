@@ -0,0 +1,103 @@
+package taskgraph
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	klcv1alpha1 "github.com/keptn/lifecycle-controller/operator/api/v1alpha1"
+	"github.com/keptn/lifecycle-controller/operator/api/v1alpha1/common"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// Graph maps the task definitions of a pre- or post-deployment check to the task definitions of the same check they
+// depend on
+type Graph map[string][]string
+
+// Build returns the dependencies of the given task definitions of a check, declared by the dependsOn field of the
+// KeptnTaskDefinitions in the namespace. Dependencies outside of the check and missing definitions are ignored.
+func Build(ctx context.Context, c client.Reader, namespace string, tasks []string) (Graph, error) {
+	graph := Graph{}
+	for _, name := range tasks {
+		definition := &klcv1alpha1.KeptnTaskDefinition{}
+		err := c.Get(ctx, types.NamespacedName{Namespace: namespace, Name: name}, definition)
+		if errors.IsNotFound(err) {
+			continue
+		} else if err != nil {
+			return nil, fmt.Errorf("could not retrieve KeptnTaskDefinition %s: %w", name, err)
+		}
+		for _, dependency := range definition.Spec.DependsOn {
+			if dependency != name && contains(tasks, dependency) && !contains(graph[name], dependency) {
+				graph[name] = append(graph[name], dependency)
+			}
+		}
+	}
+	return graph, nil
+}
+
+// Cycle returns a cycle of dependencies leading from the given task back to itself, e.g. [a b a], or nil if the task is
+// not part of a cycle
+func (g Graph) Cycle(task string) []string {
+	visited := map[string]bool{}
+	var visit func(path []string) []string
+	visit = func(path []string) []string {
+		for _, dependency := range g[path[len(path)-1]] {
+			if dependency == task {
+				return append(path, dependency)
+			}
+			if visited[dependency] {
+				continue
+			}
+			visited[dependency] = true
+			if cycle := visit(append(path, dependency)); cycle != nil {
+				return cycle
+			}
+		}
+		return nil
+	}
+	return visit([]string{task})
+}
+
+// Blocked returns the reason why the given task cannot be started yet, given the states of the tasks of the check, and
+// whether the task is failed because one of its dependencies has failed or it is part of a cycle.
+// The reason is empty if all dependencies of the task have succeeded.
+func (g Graph) Blocked(task string, states map[string]common.KeptnState) (string, bool) {
+	if cycle := g.Cycle(task); cycle != nil {
+		return "dependency cycle " + strings.Join(cycle, " -> "), true
+	}
+	waiting := []string{}
+	for _, dependency := range g[task] {
+		switch state := states[dependency]; {
+		case state.IsFailed() || state.IsCancelled():
+			return "dependency " + dependency + " has " + strings.ToLower(string(state)), true
+		case !state.IsSucceeded():
+			waiting = append(waiting, dependency)
+		}
+	}
+	if len(waiting) == 0 {
+		return "", false
+	}
+	return "waiting for " + strings.Join(waiting, ", "), false
+}
+
+// States returns the states of the tasks of a check by their task definition, where later statuses take precedence
+func States(statuses ...[]klcv1alpha1.TaskStatus) map[string]common.KeptnState {
+	states := map[string]common.KeptnState{}
+	for _, list := range statuses {
+		for _, status := range list {
+			states[status.TaskDefinitionName] = status.Status
+		}
+	}
+	return states
+}
+
+func contains(names []string, name string) bool {
+	for _, n := range names {
+		if n == name {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,64 @@
+package taskgraph
+
+import (
+	"context"
+	"testing"
+
+	klcv1alpha1 "github.com/keptn/lifecycle-controller/operator/api/v1alpha1"
+	"github.com/keptn/lifecycle-controller/operator/api/v1alpha1/common"
+	testrequire "github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func definition(name string, dependsOn ...string) *klcv1alpha1.KeptnTaskDefinition {
+	return &klcv1alpha1.KeptnTaskDefinition{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+		Spec:       klcv1alpha1.KeptnTaskDefinitionSpec{DependsOn: dependsOn},
+	}
+}
+
+func TestBuild(t *testing.T) {
+	scheme := runtime.NewScheme()
+	testrequire.Nil(t, klcv1alpha1.AddToScheme(scheme))
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+		definition("migrate"),
+		definition("smoke-test", "migrate", "notify", "smoke-test", "migrate"),
+		definition("notify", "other-check"),
+	).Build()
+
+	graph, err := Build(context.TODO(), c, "default", []string{"migrate", "smoke-test", "notify", "missing"})
+	testrequire.Nil(t, err)
+	testrequire.Equal(t, Graph{"smoke-test": {"migrate", "notify"}}, graph)
+}
+
+func TestBlocked(t *testing.T) {
+	graph := Graph{"smoke-test": {"migrate", "notify"}, "a": {"b"}, "b": {"c"}, "c": {"a"}, "d": {"a"}}
+
+	reason, failed := graph.Blocked("smoke-test", map[string]common.KeptnState{"migrate": common.StateSucceeded})
+	testrequire.Equal(t, "waiting for notify", reason)
+	testrequire.False(t, failed)
+
+	reason, failed = graph.Blocked("smoke-test", map[string]common.KeptnState{"migrate": common.StateSucceeded, "notify": common.StateWarning})
+	testrequire.Empty(t, reason)
+	testrequire.False(t, failed)
+
+	reason, failed = graph.Blocked("smoke-test", map[string]common.KeptnState{"migrate": common.StateFailed})
+	testrequire.Equal(t, "dependency migrate has failed", reason)
+	testrequire.True(t, failed)
+
+	reason, failed = graph.Blocked("a", nil)
+	testrequire.Equal(t, "dependency cycle a -> b -> c -> a", reason)
+	testrequire.True(t, failed)
+
+	// tasks depending on a cycle wait until the tasks of the cycle have failed
+	testrequire.Nil(t, graph.Cycle("d"))
+	reason, failed = graph.Blocked("d", States([]klcv1alpha1.TaskStatus{{TaskDefinitionName: "a", Status: common.StatePending}}, []klcv1alpha1.TaskStatus{{TaskDefinitionName: "a", Status: common.StateFailed}}))
+	testrequire.Equal(t, "dependency a has failed", reason)
+	testrequire.True(t, failed)
+
+	reason, failed = graph.Blocked("migrate", nil)
+	testrequire.Empty(t, reason)
+	testrequire.False(t, failed)
+}
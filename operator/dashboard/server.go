@@ -0,0 +1,105 @@
+package dashboard
+
+import (
+	"context"
+	"embed"
+	"encoding/json"
+	"io/fs"
+	"net/http"
+	"time"
+
+	"github.com/go-logr/logr"
+	klcv1alpha1 "github.com/keptn/lifecycle-controller/operator/api/v1alpha1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+//go:embed static
+var staticFiles embed.FS
+
+// Server serves a minimal read-only web UI that visualizes app versions, their phases, task and evaluation results
+// and durations based on the Keptn CRDs. It is meant for teams that do not have a tracing backend available.
+type Server struct {
+	Client      client.Reader
+	Log         logr.Logger
+	BindAddress string
+}
+
+// Start runs the dashboard until the context is cancelled. It implements the manager.Runnable interface.
+func (s *Server) Start(ctx context.Context) error {
+	srv := &http.Server{
+		Addr:              s.BindAddress,
+		Handler:           s.Handler(),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := srv.Shutdown(shutdownCtx); err != nil {
+			s.Log.Error(err, "could not shut down dashboard")
+		}
+	}()
+
+	s.Log.Info("serving dashboard at " + s.BindAddress)
+	if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+		return err
+	}
+	return nil
+}
+
+// NeedLeaderElection returns false, since the dashboard is read-only and can be served by every replica
+func (s *Server) NeedLeaderElection() bool {
+	return false
+}
+
+// Handler returns the http.Handler serving the UI and its JSON API
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/v1/timelines", s.handleTimelines)
+
+	static, err := fs.Sub(staticFiles, "static")
+	if err != nil {
+		// the static folder is embedded at build time, so this cannot happen at runtime
+		panic(err)
+	}
+	mux.Handle("/", http.FileServer(http.FS(static)))
+	return mux
+}
+
+func (s *Server) handleTimelines(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	opts := []client.ListOption{}
+	if namespace := r.URL.Query().Get("namespace"); namespace != "" {
+		opts = append(opts, client.InNamespace(namespace))
+	}
+
+	appVersions := &klcv1alpha1.KeptnAppVersionList{}
+	if err := s.Client.List(r.Context(), appVersions, opts...); err != nil {
+		s.Log.Error(err, "could not retrieve app versions")
+		http.Error(w, "could not retrieve app versions", http.StatusInternalServerError)
+		return
+	}
+
+	workloadInstances := &klcv1alpha1.KeptnWorkloadInstanceList{}
+	if err := s.Client.List(r.Context(), workloadInstances, opts...); err != nil {
+		s.Log.Error(err, "could not retrieve workload instances")
+		http.Error(w, "could not retrieve workload instances", http.StatusInternalServerError)
+		return
+	}
+
+	timelines := []AppVersionTimeline{}
+	for _, appVersion := range appVersions.Items {
+		timelines = append(timelines, NewAppVersionTimeline(appVersion, workloadInstances.Items))
+	}
+	SortTimelines(timelines)
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(timelines); err != nil {
+		s.Log.Error(err, "could not encode timelines")
+	}
+}
@@ -0,0 +1,196 @@
+package dashboard
+
+import (
+	"sort"
+	"time"
+
+	klcv1alpha1 "github.com/keptn/lifecycle-controller/operator/api/v1alpha1"
+	"github.com/keptn/lifecycle-controller/operator/api/v1alpha1/common"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// AppVersionTimeline is the view model of a KeptnAppVersion rendered by the dashboard
+type AppVersionTimeline struct {
+	Name            string              `json:"name"`
+	Namespace       string              `json:"namespace"`
+	AppName         string              `json:"appName"`
+	Version         string              `json:"version"`
+	PreviousVersion string              `json:"previousVersion,omitempty"`
+	CurrentPhase    string              `json:"currentPhase"`
+	Status          common.KeptnState   `json:"status"`
+	StartTime       *time.Time          `json:"startTime,omitempty"`
+	EndTime         *time.Time          `json:"endTime,omitempty"`
+	DurationSeconds float64             `json:"durationSeconds"`
+	Phases          []PhaseTimeline     `json:"phases"`
+	Workloads       []WorkloadTimeline  `json:"workloads"`
+	Checks          []CheckTimelineItem `json:"checks"`
+}
+
+// WorkloadTimeline is the view model of a KeptnWorkloadInstance rendered by the dashboard
+type WorkloadTimeline struct {
+	Name            string              `json:"name"`
+	WorkloadName    string              `json:"workloadName"`
+	Version         string              `json:"version"`
+	CurrentPhase    string              `json:"currentPhase"`
+	Status          common.KeptnState   `json:"status"`
+	StartTime       *time.Time          `json:"startTime,omitempty"`
+	EndTime         *time.Time          `json:"endTime,omitempty"`
+	DurationSeconds float64             `json:"durationSeconds"`
+	Phases          []PhaseTimeline     `json:"phases"`
+	Checks          []CheckTimelineItem `json:"checks"`
+}
+
+// PhaseTimeline holds the state of a single lifecycle phase
+type PhaseTimeline struct {
+	Name   string            `json:"name"`
+	Status common.KeptnState `json:"status"`
+}
+
+// CheckTimelineItem holds the result of a single task or evaluation run during a phase
+type CheckTimelineItem struct {
+	Kind            string            `json:"kind"`
+	CheckType       common.CheckType  `json:"checkType"`
+	Definition      string            `json:"definition"`
+	Name            string            `json:"name"`
+	Status          common.KeptnState `json:"status"`
+	StartTime       *time.Time        `json:"startTime,omitempty"`
+	EndTime         *time.Time        `json:"endTime,omitempty"`
+	DurationSeconds float64           `json:"durationSeconds"`
+}
+
+// NewAppVersionTimeline builds the timeline of an app version and the workload instances belonging to it
+func NewAppVersionTimeline(appVersion klcv1alpha1.KeptnAppVersion, workloadInstances []klcv1alpha1.KeptnWorkloadInstance) AppVersionTimeline {
+	start, end, duration := timeRange(appVersion.Status.StartTime, appVersion.Status.EndTime)
+	timeline := AppVersionTimeline{
+		Name:            appVersion.Name,
+		Namespace:       appVersion.Namespace,
+		AppName:         appVersion.Spec.AppName,
+		Version:         appVersion.Spec.Version,
+		PreviousVersion: appVersion.Spec.PreviousVersion,
+		CurrentPhase:    appVersion.Status.CurrentPhase,
+		Status:          appVersion.Status.Status,
+		StartTime:       start,
+		EndTime:         end,
+		DurationSeconds: duration,
+		Phases: []PhaseTimeline{
+			{Name: common.PhaseAppPreDeployment.ShortName, Status: appVersion.Status.PreDeploymentStatus},
+			{Name: common.PhaseAppPreEvaluation.ShortName, Status: appVersion.Status.PreDeploymentEvaluationStatus},
+		},
+		Workloads: []WorkloadTimeline{},
+	}
+	if appVersion.Spec.RequireApproval {
+		timeline.Phases = append(timeline.Phases, PhaseTimeline{Name: common.PhaseAppApproval.ShortName, Status: appVersion.Status.ApprovalStatus})
+	}
+	timeline.Phases = append(timeline.Phases,
+		PhaseTimeline{Name: common.PhaseAppDeployment.ShortName, Status: appVersion.Status.WorkloadOverallStatus},
+		PhaseTimeline{Name: common.PhaseAppPostDeployment.ShortName, Status: appVersion.Status.PostDeploymentStatus},
+		PhaseTimeline{Name: common.PhaseAppPostEvaluation.ShortName, Status: appVersion.Status.PostDeploymentEvaluationStatus},
+	)
+
+	timeline.Checks = append(timeline.Checks, taskItems(common.PreDeploymentCheckType, appVersion.Status.PreDeploymentTaskStatus)...)
+	timeline.Checks = append(timeline.Checks, evaluationItems(common.PreDeploymentEvaluationCheckType, appVersion.Status.PreDeploymentEvaluationTaskStatus)...)
+	timeline.Checks = append(timeline.Checks, taskItems(common.PostDeploymentCheckType, appVersion.Status.PostDeploymentTaskStatus)...)
+	timeline.Checks = append(timeline.Checks, evaluationItems(common.PostDeploymentEvaluationCheckType, appVersion.Status.PostDeploymentEvaluationTaskStatus)...)
+
+	for _, w := range appVersion.Spec.Workloads {
+		workloadName := common.CreateResourceName(common.MaxK8sObjectLength, appVersion.Spec.AppName, w.Name)
+		for _, wi := range workloadInstances {
+			if wi.Namespace == appVersion.Namespace && wi.Spec.WorkloadName == workloadName && wi.Spec.Version == w.Version {
+				timeline.Workloads = append(timeline.Workloads, NewWorkloadTimeline(wi))
+			}
+		}
+	}
+	return timeline
+}
+
+// NewWorkloadTimeline builds the timeline of a single workload instance
+func NewWorkloadTimeline(workloadInstance klcv1alpha1.KeptnWorkloadInstance) WorkloadTimeline {
+	start, end, duration := timeRange(workloadInstance.Status.StartTime, workloadInstance.Status.EndTime)
+	timeline := WorkloadTimeline{
+		Name:            workloadInstance.Name,
+		WorkloadName:    workloadInstance.Spec.WorkloadName,
+		Version:         workloadInstance.Spec.Version,
+		CurrentPhase:    workloadInstance.Status.CurrentPhase,
+		Status:          workloadInstance.Status.Status,
+		StartTime:       start,
+		EndTime:         end,
+		DurationSeconds: duration,
+		Phases: []PhaseTimeline{
+			{Name: common.PhaseWorkloadPreDeployment.ShortName, Status: workloadInstance.Status.PreDeploymentStatus},
+			{Name: common.PhaseWorkloadPreEvaluation.ShortName, Status: workloadInstance.Status.PreDeploymentEvaluationStatus},
+			{Name: common.PhaseWorkloadMigration.ShortName, Status: workloadInstance.Status.MigrationStatus},
+			{Name: common.PhaseWorkloadDeployment.ShortName, Status: workloadInstance.Status.DeploymentStatus},
+			{Name: common.PhaseWorkloadPostDeployment.ShortName, Status: workloadInstance.Status.PostDeploymentStatus},
+			{Name: common.PhaseWorkloadPostEvaluation.ShortName, Status: workloadInstance.Status.PostDeploymentEvaluationStatus},
+		},
+	}
+	timeline.Checks = append(timeline.Checks, taskItems(common.PreDeploymentCheckType, workloadInstance.Status.PreDeploymentTaskStatus)...)
+	timeline.Checks = append(timeline.Checks, evaluationItems(common.PreDeploymentEvaluationCheckType, workloadInstance.Status.PreDeploymentEvaluationTaskStatus)...)
+	timeline.Checks = append(timeline.Checks, taskItems(common.MigrationCheckType, workloadInstance.Status.MigrationTaskStatus)...)
+	timeline.Checks = append(timeline.Checks, taskItems(common.PostDeploymentCheckType, workloadInstance.Status.PostDeploymentTaskStatus)...)
+	timeline.Checks = append(timeline.Checks, evaluationItems(common.PostDeploymentEvaluationCheckType, workloadInstance.Status.PostDeploymentEvaluationTaskStatus)...)
+	return timeline
+}
+
+// SortTimelines orders the timelines with the most recently started app version first
+func SortTimelines(timelines []AppVersionTimeline) {
+	sort.SliceStable(timelines, func(i, j int) bool {
+		if timelines[i].StartTime == nil {
+			return false
+		}
+		if timelines[j].StartTime == nil {
+			return true
+		}
+		return timelines[i].StartTime.After(*timelines[j].StartTime)
+	})
+}
+
+func taskItems(checkType common.CheckType, statuses []klcv1alpha1.TaskStatus) []CheckTimelineItem {
+	items := []CheckTimelineItem{}
+	for _, s := range statuses {
+		start, end, duration := timeRange(s.StartTime, s.EndTime)
+		items = append(items, CheckTimelineItem{
+			Kind:            "KeptnTask",
+			CheckType:       checkType,
+			Definition:      s.TaskDefinitionName,
+			Name:            s.TaskName,
+			Status:          s.Status,
+			StartTime:       start,
+			EndTime:         end,
+			DurationSeconds: duration,
+		})
+	}
+	return items
+}
+
+func evaluationItems(checkType common.CheckType, statuses []klcv1alpha1.EvaluationStatus) []CheckTimelineItem {
+	items := []CheckTimelineItem{}
+	for _, s := range statuses {
+		start, end, duration := timeRange(s.StartTime, s.EndTime)
+		items = append(items, CheckTimelineItem{
+			Kind:            "KeptnEvaluation",
+			CheckType:       checkType,
+			Definition:      s.EvaluationDefinitionName,
+			Name:            s.EvaluationName,
+			Status:          s.Status,
+			StartTime:       start,
+			EndTime:         end,
+			DurationSeconds: duration,
+		})
+	}
+	return items
+}
+
+// timeRange converts the start and end times of a CRD status into pointers, so unset times are omitted in the JSON output.
+// The duration of still running items is computed up to now.
+func timeRange(startTime metav1.Time, endTime metav1.Time) (*time.Time, *time.Time, float64) {
+	if startTime.IsZero() {
+		return nil, nil, 0
+	}
+	start := startTime.Time
+	if endTime.IsZero() {
+		return &start, nil, time.Since(start).Seconds()
+	}
+	end := endTime.Time
+	return &start, &end, end.Sub(start).Seconds()
+}
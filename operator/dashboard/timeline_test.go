@@ -0,0 +1,74 @@
+package dashboard
+
+import (
+	"testing"
+	"time"
+
+	"github.com/keptn/lifecycle-controller/operator/api/v1alpha1"
+	"github.com/keptn/lifecycle-controller/operator/api/v1alpha1/common"
+	testrequire "github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestNewAppVersionTimeline(t *testing.T) {
+	start := metav1.NewTime(time.Now().Add(-time.Minute))
+	end := metav1.NewTime(start.Add(30 * time.Second))
+
+	appVersion := v1alpha1.KeptnAppVersion{
+		ObjectMeta: metav1.ObjectMeta{Name: "myapp-1.0.0", Namespace: "default"},
+		Spec: v1alpha1.KeptnAppVersionSpec{
+			AppName: "myapp",
+			KeptnAppSpec: v1alpha1.KeptnAppSpec{
+				Version:   "1.0.0",
+				Workloads: []v1alpha1.KeptnWorkloadRef{{Name: "frontend", Version: "0.1"}},
+			},
+		},
+		Status: v1alpha1.KeptnAppVersionStatus{
+			Status:    common.StateSucceeded,
+			StartTime: start,
+			EndTime:   end,
+			PreDeploymentTaskStatus: []v1alpha1.TaskStatus{
+				{TaskDefinitionName: "check", TaskName: "pre-check", Status: common.StateSucceeded, StartTime: start, EndTime: end},
+			},
+		},
+	}
+	workloadInstances := []v1alpha1.KeptnWorkloadInstance{
+		makeWorkloadInstance("default", "myapp-frontend", "0.1"),
+		makeWorkloadInstance("default", "myapp-frontend", "0.2"),
+		makeWorkloadInstance("other", "myapp-frontend", "0.1"),
+	}
+
+	timeline := NewAppVersionTimeline(appVersion, workloadInstances)
+
+	testrequire.Equal(t, "myapp", timeline.AppName)
+	testrequire.Equal(t, float64(30), timeline.DurationSeconds)
+	testrequire.Len(t, timeline.Phases, 5)
+	testrequire.Len(t, timeline.Workloads, 1)
+	testrequire.Equal(t, "0.1", timeline.Workloads[0].Version)
+	testrequire.Len(t, timeline.Checks, 1)
+	testrequire.Equal(t, common.PreDeploymentCheckType, timeline.Checks[0].CheckType)
+}
+
+func TestSortTimelines(t *testing.T) {
+	older := time.Now().Add(-time.Hour)
+	newer := time.Now()
+	timelines := []AppVersionTimeline{{Name: "pending"}, {Name: "older", StartTime: &older}, {Name: "newer", StartTime: &newer}}
+
+	SortTimelines(timelines)
+
+	testrequire.Equal(t, "newer", timelines[0].Name)
+	testrequire.Equal(t, "older", timelines[1].Name)
+	testrequire.Equal(t, "pending", timelines[2].Name)
+}
+
+func makeWorkloadInstance(namespace string, workloadName string, version string) v1alpha1.KeptnWorkloadInstance {
+	return v1alpha1.KeptnWorkloadInstance{
+		ObjectMeta: metav1.ObjectMeta{Name: workloadName + "-" + version, Namespace: namespace},
+		Spec: v1alpha1.KeptnWorkloadInstanceSpec{
+			WorkloadName: workloadName,
+			KeptnWorkloadSpec: v1alpha1.KeptnWorkloadSpec{
+				Version: version,
+			},
+		},
+	}
+}
@@ -0,0 +1,89 @@
+package features
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Feature is the name of a subsystem of the operator that can be enabled or disabled per cluster
+type Feature string
+
+const (
+	// CanaryPhase shifts traffic to new workload versions gradually before they are released
+	CanaryPhase Feature = "CanaryPhase"
+	// Rollback reverts workloads to their previous version if their post-deployment checks fail
+	Rollback Feature = "Rollback"
+	// Promotion promotes app versions to the next environment once they have been deployed successfully
+	Promotion Feature = "Promotion"
+)
+
+// defaults are the known features and whether they are enabled if not set with --feature-gates.
+// New subsystems with a risk of disrupting deployments are added disabled and enabled by default once they are stable.
+var defaults = map[Feature]bool{
+	CanaryPhase: false,
+	Rollback:    false,
+	Promotion:   false,
+}
+
+// Gates define which features are enabled. They implement flag.Value, so that they can be set with a flag like
+// --feature-gates=CanaryPhase=true,Rollback=false
+type Gates map[Feature]bool
+
+// NewGates returns the gates with the defaults of all known features
+func NewGates() Gates {
+	gates := Gates{}
+	for feature, enabled := range defaults {
+		gates[feature] = enabled
+	}
+	return gates
+}
+
+// Enabled returns whether the feature is enabled. Unknown features are disabled.
+func (g Gates) Enabled(feature Feature) bool {
+	return g[feature]
+}
+
+// Set enables or disables the features of a comma separated list of <feature>=<true|false> pairs
+func (g Gates) Set(value string) error {
+	for _, pair := range strings.Split(value, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+		name, enabled, ok := strings.Cut(pair, "=")
+		if !ok {
+			return fmt.Errorf("missing value of feature gate %s, expected <feature>=<true|false>", pair)
+		}
+		feature := Feature(strings.TrimSpace(name))
+		if _, ok := defaults[feature]; !ok {
+			return fmt.Errorf("unknown feature gate %s, known feature gates are %s", feature, strings.Join(knownFeatures(), ", "))
+		}
+		b, err := strconv.ParseBool(strings.TrimSpace(enabled))
+		if err != nil {
+			return fmt.Errorf("invalid value of feature gate %s: %w", feature, err)
+		}
+		g[feature] = b
+	}
+	return nil
+}
+
+// String returns the gates as comma separated list sorted by feature
+func (g Gates) String() string {
+	pairs := make([]string, 0, len(g))
+	for feature, enabled := range g {
+		pairs = append(pairs, fmt.Sprintf("%s=%t", feature, enabled))
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, ",")
+}
+
+func knownFeatures() []string {
+	names := make([]string, 0, len(defaults))
+	for feature := range defaults {
+		names = append(names, string(feature))
+	}
+	sort.Strings(names)
+	return names
+}
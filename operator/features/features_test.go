@@ -0,0 +1,35 @@
+package features
+
+import (
+	"flag"
+	"testing"
+
+	testrequire "github.com/stretchr/testify/require"
+)
+
+func TestGates_Set(t *testing.T) {
+	gates := NewGates()
+	testrequire.False(t, gates.Enabled(CanaryPhase))
+
+	flags := flag.NewFlagSet("test", flag.ContinueOnError)
+	flags.Var(gates, "feature-gates", "")
+	testrequire.Nil(t, flags.Parse([]string{"--feature-gates=CanaryPhase=true, Rollback=false,"}))
+
+	testrequire.True(t, gates.Enabled(CanaryPhase))
+	testrequire.False(t, gates.Enabled(Rollback))
+	testrequire.False(t, gates.Enabled("Unknown"))
+	testrequire.Equal(t, "CanaryPhase=true,Promotion=false,Rollback=false", gates.String())
+}
+
+func TestGates_SetInvalid(t *testing.T) {
+	tests := []string{
+		"Unknown=true",
+		"CanaryPhase",
+		"CanaryPhase=maybe",
+	}
+	for _, value := range tests {
+		t.Run(value, func(t *testing.T) {
+			testrequire.NotNil(t, NewGates().Set(value))
+		})
+	}
+}
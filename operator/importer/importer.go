@@ -0,0 +1,70 @@
+package importer
+
+import (
+	"fmt"
+	"io"
+
+	klcv1alpha1 "github.com/keptn/lifecycle-controller/operator/api/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/yaml"
+)
+
+// Result contains the objects an app definition has been converted to
+type Result struct {
+	App             *klcv1alpha1.KeptnApp
+	TaskDefinitions []klcv1alpha1.KeptnTaskDefinition
+	// Warnings describe the parts of the definition that could not be converted and have to be migrated manually
+	Warnings []string
+}
+
+// Write writes the objects as multi-document YAML that can be applied with kubectl
+func (r *Result) Write(w io.Writer) error {
+	objects := []interface{}{r.App}
+	for i := range r.TaskDefinitions {
+		objects = append(objects, &r.TaskDefinitions[i])
+	}
+	for i, object := range objects {
+		out, err := yaml.Marshal(object)
+		if err != nil {
+			return fmt.Errorf("could not marshal object: %w", err)
+		}
+		if i > 0 {
+			if _, err := fmt.Fprintln(w, "---"); err != nil {
+				return err
+			}
+		}
+		if _, err := w.Write(out); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *Result) hasTaskDefinition(name string) bool {
+	for _, definition := range r.TaskDefinitions {
+		if definition.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *Result) warn(format string, args ...interface{}) {
+	r.Warnings = append(r.Warnings, fmt.Sprintf(format, args...))
+}
+
+func newApp(name string, namespace string, version string) *klcv1alpha1.KeptnApp {
+	return &klcv1alpha1.KeptnApp{
+		TypeMeta:   metav1.TypeMeta{APIVersion: klcv1alpha1.GroupVersion.String(), Kind: "KeptnApp"},
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+		Spec:       klcv1alpha1.KeptnAppSpec{Version: version},
+	}
+}
+
+func newTaskDefinition(name string, namespace string, function klcv1alpha1.FunctionSpec) klcv1alpha1.KeptnTaskDefinition {
+	return klcv1alpha1.KeptnTaskDefinition{
+		TypeMeta:   metav1.TypeMeta{APIVersion: klcv1alpha1.GroupVersion.String(), Kind: "KeptnTaskDefinition"},
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+		Spec:       klcv1alpha1.KeptnTaskDefinitionSpec{Function: function},
+	}
+}
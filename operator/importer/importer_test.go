@@ -0,0 +1,96 @@
+package importer
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	klcv1alpha1 "github.com/keptn/lifecycle-controller/operator/api/v1alpha1"
+	testrequire "github.com/stretchr/testify/require"
+)
+
+const shipyard = `apiVersion: "spec.keptn.sh/0.2.3"
+kind: "Shipyard"
+metadata:
+  name: "shipyard-podtato"
+spec:
+  stages:
+    - name: "dev"
+      sequences:
+        - name: "delivery"
+          tasks:
+            - name: "approval"
+            - name: "check-dependencies"
+            - name: "deployment"
+              properties:
+                deploymentstrategy: "direct"
+            - name: "test"
+              properties:
+                teststrategy: "functional"
+            - name: "evaluation"
+            - name: "release"
+    - name: "production"
+      sequences:
+        - name: "delivery"
+          tasks:
+            - name: "deployment"
+`
+
+func TestFromShipyard(t *testing.T) {
+	result, err := FromShipyard([]byte(shipyard), ShipyardOptions{
+		App:       "podtato-head",
+		Namespace: "podtato",
+		Version:   "1.0.0",
+		Workloads: []klcv1alpha1.KeptnWorkloadRef{{Name: "podtato-head-entry", Version: "0.1.0"}},
+	})
+	testrequire.Nil(t, err)
+
+	testrequire.Equal(t, "podtato", result.App.Namespace)
+	testrequire.Equal(t, []string{"podtato-head-check-dependencies"}, result.App.Spec.PreDeploymentTasks)
+	testrequire.Equal(t, []string{"podtato-head-test"}, result.App.Spec.PostDeploymentTasks)
+	testrequire.Equal(t, []string{"podtato-head-dev-evaluation"}, result.App.Spec.PostDeploymentEvaluations)
+	testrequire.Len(t, result.App.Spec.Workloads, 1)
+
+	testrequire.Len(t, result.TaskDefinitions, 2)
+	testrequire.Equal(t, map[string]string{"teststrategy": "functional"}, result.TaskDefinitions[1].Spec.Function.Parameters.Inline)
+	testrequire.Len(t, result.Warnings, 5)
+
+	_, err = FromShipyard([]byte(shipyard), ShipyardOptions{App: "podtato-head", Version: "1.0.0", Stage: "staging"})
+	testrequire.NotNil(t, err)
+}
+
+func TestFromManifest(t *testing.T) {
+	manifest := `name: podtato-head
+namespace: podtato
+version: 1.0.0
+workloads:
+  - name: podtato-head-entry
+    version: 0.1.0
+preDeploymentTasks:
+  - name: check-entry
+    url: https://raw.githubusercontent.com/keptn/lifecycle-controller/main/functions-runtime/samples/ts/http.ts
+    parameters:
+      url: http://podtato-head-entry
+postDeploymentTasks:
+  - name: notify
+    inline: console.log("deployed");
+postDeploymentEvaluations:
+  - app-evaluation
+`
+	result, err := FromManifest([]byte(manifest))
+	testrequire.Nil(t, err)
+	testrequire.Equal(t, []string{"check-entry"}, result.App.Spec.PreDeploymentTasks)
+	testrequire.Equal(t, []string{"notify"}, result.App.Spec.PostDeploymentTasks)
+	testrequire.Equal(t, []string{"app-evaluation"}, result.App.Spec.PostDeploymentEvaluations)
+	testrequire.Len(t, result.TaskDefinitions, 2)
+	testrequire.Equal(t, "http://podtato-head-entry", result.TaskDefinitions[0].Spec.Function.Parameters.Inline["url"])
+	testrequire.Equal(t, `console.log("deployed");`, result.TaskDefinitions[1].Spec.Function.Inline.Code)
+
+	out := &bytes.Buffer{}
+	testrequire.Nil(t, result.Write(out))
+	testrequire.Equal(t, 2, strings.Count(out.String(), "\n---\n"))
+	testrequire.True(t, strings.HasPrefix(out.String(), "apiVersion: lifecycle.keptn.sh/v1alpha1\nkind: KeptnApp\n"))
+
+	_, err = FromManifest([]byte("name: podtato-head\nversion: 1.0.0\npreDeploymentTasks:\n  - name: empty\n"))
+	testrequire.NotNil(t, err)
+}
@@ -0,0 +1,92 @@
+package importer
+
+import (
+	"fmt"
+
+	klcv1alpha1 "github.com/keptn/lifecycle-controller/operator/api/v1alpha1"
+	"sigs.k8s.io/yaml"
+)
+
+// Manifest is a simple YAML description of an app, its workloads and the tasks and evaluations of its deployment
+type Manifest struct {
+	Name      string             `json:"name"`
+	Namespace string             `json:"namespace,omitempty"`
+	Version   string             `json:"version"`
+	Workloads []ManifestWorkload `json:"workloads,omitempty"`
+	// PreDeploymentTasks and PostDeploymentTasks are converted to KeptnTaskDefinitions
+	PreDeploymentTasks  []ManifestTask `json:"preDeploymentTasks,omitempty"`
+	PostDeploymentTasks []ManifestTask `json:"postDeploymentTasks,omitempty"`
+	// PreDeploymentEvaluations and PostDeploymentEvaluations are the names of existing KeptnEvaluationDefinitions
+	PreDeploymentEvaluations  []string `json:"preDeploymentEvaluations,omitempty"`
+	PostDeploymentEvaluations []string `json:"postDeploymentEvaluations,omitempty"`
+}
+
+// ManifestWorkload is a workload of the app
+type ManifestWorkload struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+}
+
+// ManifestTask is a task of the app, whose function is either inline code or loaded from a URL
+type ManifestTask struct {
+	Name       string            `json:"name"`
+	Inline     string            `json:"inline,omitempty"`
+	URL        string            `json:"url,omitempty"`
+	Parameters map[string]string `json:"parameters,omitempty"`
+}
+
+// FromManifest converts a manifest to a KeptnApp and a KeptnTaskDefinition for each of its tasks
+func FromManifest(data []byte) (*Result, error) {
+	manifest := Manifest{}
+	if err := yaml.UnmarshalStrict(data, &manifest); err != nil {
+		return nil, fmt.Errorf("could not parse manifest: %w", err)
+	}
+	if manifest.Name == "" || manifest.Version == "" {
+		return nil, fmt.Errorf("manifest has to contain the name and version of the app")
+	}
+
+	result := &Result{App: newApp(manifest.Name, manifest.Namespace, manifest.Version)}
+	for _, workload := range manifest.Workloads {
+		result.App.Spec.Workloads = append(result.App.Spec.Workloads, klcv1alpha1.KeptnWorkloadRef{Name: workload.Name, Version: workload.Version})
+	}
+
+	for _, tasks := range []struct {
+		manifest []ManifestTask
+		app      *[]string
+	}{
+		{manifest.PreDeploymentTasks, &result.App.Spec.PreDeploymentTasks},
+		{manifest.PostDeploymentTasks, &result.App.Spec.PostDeploymentTasks},
+	} {
+		for _, task := range tasks.manifest {
+			function, err := functionOf(task)
+			if err != nil {
+				return nil, err
+			}
+			*tasks.app = append(*tasks.app, task.Name)
+			result.TaskDefinitions = append(result.TaskDefinitions, newTaskDefinition(task.Name, manifest.Namespace, function))
+		}
+	}
+
+	result.App.Spec.PreDeploymentEvaluations = manifest.PreDeploymentEvaluations
+	result.App.Spec.PostDeploymentEvaluations = manifest.PostDeploymentEvaluations
+	return result, nil
+}
+
+func functionOf(task ManifestTask) (klcv1alpha1.FunctionSpec, error) {
+	function := klcv1alpha1.FunctionSpec{
+		Parameters: klcv1alpha1.TaskParameters{Inline: task.Parameters},
+	}
+	switch {
+	case task.Name == "":
+		return function, fmt.Errorf("task without name")
+	case task.Inline != "" && task.URL != "":
+		return function, fmt.Errorf("task %s has both inline code and a URL", task.Name)
+	case task.Inline != "":
+		function.Inline.Code = task.Inline
+	case task.URL != "":
+		function.HttpReference.Url = task.URL
+	default:
+		return function, fmt.Errorf("task %s has neither inline code nor a URL", task.Name)
+	}
+	return function, nil
+}
@@ -0,0 +1,163 @@
+package importer
+
+import (
+	"fmt"
+
+	klcv1alpha1 "github.com/keptn/lifecycle-controller/operator/api/v1alpha1"
+	"github.com/keptn/lifecycle-controller/operator/api/v1alpha1/common"
+	"sigs.k8s.io/yaml"
+)
+
+// Shipyard is the definition of the stages and sequences of a Keptn v1 project
+type Shipyard struct {
+	APIVersion string `json:"apiVersion"`
+	Kind       string `json:"kind"`
+	Metadata   struct {
+		Name string `json:"name"`
+	} `json:"metadata"`
+	Spec struct {
+		Stages []ShipyardStage `json:"stages"`
+	} `json:"spec"`
+}
+
+// ShipyardStage is a stage of a Keptn v1 project, e.g. dev or production
+type ShipyardStage struct {
+	Name      string             `json:"name"`
+	Sequences []ShipyardSequence `json:"sequences"`
+}
+
+// ShipyardSequence is a sequence of tasks run in a stage, e.g. delivery
+type ShipyardSequence struct {
+	Name  string         `json:"name"`
+	Tasks []ShipyardTask `json:"tasks"`
+}
+
+// ShipyardTask is a task of a sequence, which is run by the Keptn services subscribed to it
+type ShipyardTask struct {
+	Name       string                 `json:"name"`
+	Properties map[string]interface{} `json:"properties,omitempty"`
+}
+
+// ShipyardOptions select the sequence of the shipyard that is converted and define the app it is converted to
+type ShipyardOptions struct {
+	App       string
+	Namespace string
+	Version   string
+	Workloads []klcv1alpha1.KeptnWorkloadRef
+	// Stage defaults to the first stage of the shipyard
+	Stage string
+	// Sequence defaults to delivery
+	Sequence string
+}
+
+const deploymentTask = "deployment"
+const evaluationTask = "evaluation"
+
+// builtinTasks are the tasks of Keptn v1 that are covered by the Lifecycle Controller or the deployment tooling itself
+var builtinTasks = map[string]bool{
+	"release":  true,
+	"rollback": true,
+	"approval": true,
+}
+
+// FromShipyard converts a sequence of a Keptn v1 shipyard to a KeptnApp. The tasks before the deployment task become
+// pre-deployment tasks, the tasks after it post-deployment tasks. Evaluation tasks become evaluations of the app, whose
+// KeptnEvaluationDefinition has to be created from the SLOs of the project.
+// Since the tasks of Keptn v1 are run by the services subscribed to them, a KeptnTaskDefinition with a placeholder
+// function is created for each task.
+func FromShipyard(data []byte, opts ShipyardOptions) (*Result, error) {
+	shipyard := Shipyard{}
+	if err := yaml.Unmarshal(data, &shipyard); err != nil {
+		return nil, fmt.Errorf("could not parse shipyard: %w", err)
+	}
+	if shipyard.Kind != "Shipyard" {
+		return nil, fmt.Errorf("unexpected kind %q, expected Shipyard", shipyard.Kind)
+	}
+	if opts.App == "" || opts.Version == "" {
+		return nil, fmt.Errorf("the name and version of the app have to be set")
+	}
+	if opts.Sequence == "" {
+		opts.Sequence = "delivery"
+	}
+
+	stage, err := findStage(shipyard, opts.Stage)
+	if err != nil {
+		return nil, err
+	}
+	var sequence *ShipyardSequence
+	for i := range stage.Sequences {
+		if stage.Sequences[i].Name == opts.Sequence {
+			sequence = &stage.Sequences[i]
+		}
+	}
+	if sequence == nil {
+		return nil, fmt.Errorf("stage %s has no sequence %s", stage.Name, opts.Sequence)
+	}
+
+	result := &Result{App: newApp(opts.App, opts.Namespace, opts.Version)}
+	result.App.Spec.Workloads = opts.Workloads
+
+	deployed := false
+	for _, task := range sequence.Tasks {
+		switch {
+		case task.Name == deploymentTask:
+			deployed = true
+		case builtinTasks[task.Name]:
+			result.warn("task %s is not converted, since it is handled by the deployment tooling", task.Name)
+		case task.Name == evaluationTask:
+			name := common.CreateResourceName(common.MaxK8sObjectLength, opts.App, stage.Name, task.Name)
+			if deployed {
+				result.App.Spec.PostDeploymentEvaluations = append(result.App.Spec.PostDeploymentEvaluations, name)
+			} else {
+				result.App.Spec.PreDeploymentEvaluations = append(result.App.Spec.PreDeploymentEvaluations, name)
+			}
+			result.warn("KeptnEvaluationDefinition %s has to be created from the SLOs of stage %s", name, stage.Name)
+		default:
+			name := common.CreateResourceName(common.MaxK8sObjectLength, opts.App, task.Name)
+			if deployed {
+				result.App.Spec.PostDeploymentTasks = append(result.App.Spec.PostDeploymentTasks, name)
+			} else {
+				result.App.Spec.PreDeploymentTasks = append(result.App.Spec.PreDeploymentTasks, name)
+			}
+			if result.hasTaskDefinition(name) {
+				continue
+			}
+			result.TaskDefinitions = append(result.TaskDefinitions, newTaskDefinition(name, opts.Namespace, placeholderFunction(task)))
+			result.warn("KeptnTaskDefinition %s has a placeholder function, which has to be replaced by the logic of the services subscribed to task %s", name, task.Name)
+		}
+	}
+	if !deployed {
+		result.warn("sequence %s has no deployment task, all tasks have been converted to pre-deployment tasks", sequence.Name)
+	}
+	return result, nil
+}
+
+func findStage(shipyard Shipyard, name string) (*ShipyardStage, error) {
+	if len(shipyard.Spec.Stages) == 0 {
+		return nil, fmt.Errorf("shipyard %s has no stages", shipyard.Metadata.Name)
+	}
+	if name == "" {
+		return &shipyard.Spec.Stages[0], nil
+	}
+	for i := range shipyard.Spec.Stages {
+		if shipyard.Spec.Stages[i].Name == name {
+			return &shipyard.Spec.Stages[i], nil
+		}
+	}
+	return nil, fmt.Errorf("shipyard %s has no stage %s", shipyard.Metadata.Name, name)
+}
+
+// placeholderFunction returns a function that logs the task and passes its properties as parameters
+func placeholderFunction(task ShipyardTask) klcv1alpha1.FunctionSpec {
+	function := klcv1alpha1.FunctionSpec{
+		Inline: klcv1alpha1.Inline{Code: fmt.Sprintf("console.log(\"TODO: migrate Keptn task %s\");\n", task.Name)},
+	}
+	if len(task.Properties) == 0 {
+		return function
+	}
+	function.Parameters.Inline = map[string]string{}
+	for key, value := range task.Properties {
+		function.Parameters.Inline[key] = fmt.Sprint(value)
+	}
+	return function
+}
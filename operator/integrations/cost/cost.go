@@ -0,0 +1,86 @@
+package cost
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	promapi "github.com/prometheus/client_golang/api"
+	prometheus "github.com/prometheus/client_golang/api/prometheus/v1"
+	"github.com/prometheus/common/model"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+const cpuPriceQuery = "avg(node_cpu_hourly_cost)"
+const ramPriceQuery = "avg(node_ram_hourly_cost)"
+
+const bytesPerGiB = 1024 * 1024 * 1024
+
+// Prices contains the average hourly cost of the resources of the cluster
+type Prices struct {
+	// CPU is the hourly cost of one CPU core
+	CPU float64
+	// RAM is the hourly cost of one GiB of memory
+	RAM float64
+}
+
+// Estimator estimates the cost of workloads using the node prices exported by OpenCost
+type Estimator struct {
+	api prometheus.API
+}
+
+// NewOpenCostEstimator returns an Estimator querying the Prometheus server at the given URL, which scrapes the OpenCost metrics
+func NewOpenCostEstimator(prometheusURL string) (*Estimator, error) {
+	client, err := promapi.NewClient(promapi.Config{Address: prometheusURL, Client: &http.Client{Timeout: 10 * time.Second}})
+	if err != nil {
+		return nil, fmt.Errorf("could not create prometheus client: %w", err)
+	}
+	return &Estimator{api: prometheus.NewAPI(client)}, nil
+}
+
+// GetPrices retrieves the current average hourly prices of CPU and memory
+func (e *Estimator) GetPrices(ctx context.Context) (Prices, error) {
+	cpu, err := e.queryScalar(ctx, cpuPriceQuery)
+	if err != nil {
+		return Prices{}, err
+	}
+	ram, err := e.queryScalar(ctx, ramPriceQuery)
+	if err != nil {
+		return Prices{}, err
+	}
+	return Prices{CPU: cpu, RAM: ram}, nil
+}
+
+func (e *Estimator) queryScalar(ctx context.Context, query string) (float64, error) {
+	result, _, err := e.api.Query(ctx, query, time.Now().UTC())
+	if err != nil {
+		return 0, fmt.Errorf("could not run query %s: %w", query, err)
+	}
+	resultVector, ok := result.(model.Vector)
+	if !ok || len(resultVector) != 1 {
+		return 0, fmt.Errorf("query %s did not return a single value", query)
+	}
+	return float64(resultVector[0].Value), nil
+}
+
+// HourlyCost returns the projected hourly cost of running the given number of replicas of a pod.
+// The cost is based on the resource requests of the containers, or their limits if no requests are set.
+func (p Prices) HourlyCost(spec corev1.PodSpec, replicas int32) float64 {
+	var cores, memory float64
+	for _, container := range spec.Containers {
+		cpuQuantity := getQuantity(container.Resources, corev1.ResourceCPU)
+		memoryQuantity := getQuantity(container.Resources, corev1.ResourceMemory)
+		cores += cpuQuantity.AsApproximateFloat64()
+		memory += memoryQuantity.AsApproximateFloat64()
+	}
+	return float64(replicas) * (cores*p.CPU + memory/bytesPerGiB*p.RAM)
+}
+
+func getQuantity(resources corev1.ResourceRequirements, name corev1.ResourceName) resource.Quantity {
+	if q, ok := resources.Requests[name]; ok {
+		return q
+	}
+	return resources.Limits[name]
+}
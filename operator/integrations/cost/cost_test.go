@@ -0,0 +1,58 @@
+package cost
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	testrequire "github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+func TestPrices_HourlyCost(t *testing.T) {
+	spec := corev1.PodSpec{
+		Containers: []corev1.Container{
+			{
+				Name: "app",
+				Resources: corev1.ResourceRequirements{
+					Requests: corev1.ResourceList{
+						corev1.ResourceCPU:    resource.MustParse("500m"),
+						corev1.ResourceMemory: resource.MustParse("1Gi"),
+					},
+				},
+			},
+			{
+				Name: "sidecar",
+				Resources: corev1.ResourceRequirements{
+					Limits: corev1.ResourceList{
+						corev1.ResourceCPU: resource.MustParse("500m"),
+					},
+				},
+			},
+		},
+	}
+	prices := Prices{CPU: 0.04, RAM: 0.01}
+
+	testrequire.InDelta(t, 0.15, prices.HourlyCost(spec, 3), 0.0001)
+}
+
+func TestEstimator_GetPrices(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		value := "0.005"
+		if r.FormValue("query") == cpuPriceQuery {
+			value = "0.03"
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"status":"success","data":{"resultType":"vector","result":[{"metric":{},"value":[1666000000,"` + value + `"]}]}}`))
+	}))
+	defer server.Close()
+
+	estimator, err := NewOpenCostEstimator(server.URL)
+	testrequire.Nil(t, err)
+
+	prices, err := estimator.GetPrices(context.TODO())
+	testrequire.Nil(t, err)
+	testrequire.Equal(t, Prices{CPU: 0.03, RAM: 0.005}, prices)
+}
@@ -0,0 +1,62 @@
+package energy
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	promapi "github.com/prometheus/client_golang/api"
+	prometheus "github.com/prometheus/client_golang/api/prometheus/v1"
+	"github.com/prometheus/common/model"
+)
+
+const ProviderKepler = "kepler"
+const ProviderScaphandre = "scaphandre"
+
+// power queries in Watts for the pods matching a namespace and a pod name pattern
+const keplerQuery = `sum(rate(kepler_container_joules_total{container_namespace="%s",pod_name=~"%s"}[5m]))`
+const scaphandreQuery = `sum(scaph_process_power_consumption_microwatts{kubernetes_pod_namespace="%s",kubernetes_pod_name=~"%s"}) / 1000000`
+
+// Meter measures the power consumption of workloads using the metrics exported by Kepler or Scaphandre
+type Meter struct {
+	api   prometheus.API
+	query string
+}
+
+// NewMeter returns a Meter querying the metrics of the given provider from the Prometheus server at the given URL.
+// If no provider is given, nil is returned.
+func NewMeter(provider string, prometheusURL string) (*Meter, error) {
+	var query string
+	switch provider {
+	case "":
+		return nil, nil
+	case ProviderKepler:
+		query = keplerQuery
+	case ProviderScaphandre:
+		query = scaphandreQuery
+	default:
+		return nil, fmt.Errorf("unknown energy provider %s", provider)
+	}
+
+	client, err := promapi.NewClient(promapi.Config{Address: prometheusURL, Client: &http.Client{Timeout: 10 * time.Second}})
+	if err != nil {
+		return nil, fmt.Errorf("could not create prometheus client: %w", err)
+	}
+	return &Meter{api: prometheus.NewAPI(client), query: query}, nil
+}
+
+// GetPower returns the current power consumption in Watts of all pods in the namespace whose name matches the given pattern.
+// The pattern is placed in a string literal of the query as is, so its backslashes have to be escaped already.
+func (m *Meter) GetPower(ctx context.Context, namespace string, podNamePattern string) (float64, error) {
+	query := fmt.Sprintf(m.query, namespace, podNamePattern)
+	result, _, err := m.api.Query(ctx, query, time.Now().UTC())
+	if err != nil {
+		return 0, fmt.Errorf("could not run query %s: %w", query, err)
+	}
+	resultVector, ok := result.(model.Vector)
+	if !ok || len(resultVector) != 1 {
+		return 0, fmt.Errorf("no power measurements found for pods %s in namespace %s", podNamePattern, namespace)
+	}
+	return float64(resultVector[0].Value), nil
+}
@@ -0,0 +1,37 @@
+package energy
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	testrequire "github.com/stretchr/testify/require"
+)
+
+func TestNewMeter(t *testing.T) {
+	meter, err := NewMeter("", "http://prometheus")
+	testrequire.Nil(t, err)
+	testrequire.Nil(t, meter)
+
+	_, err = NewMeter("unknown", "http://prometheus")
+	testrequire.NotNil(t, err)
+}
+
+func TestMeter_GetPower(t *testing.T) {
+	var query string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query = r.FormValue("query")
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"status":"success","data":{"resultType":"vector","result":[{"metric":{},"value":[1666000000,"12.5"]}]}}`))
+	}))
+	defer server.Close()
+
+	meter, err := NewMeter(ProviderKepler, server.URL)
+	testrequire.Nil(t, err)
+
+	power, err := meter.GetPower(context.TODO(), "default", "frontend-5d8f7c-.*")
+	testrequire.Nil(t, err)
+	testrequire.Equal(t, 12.5, power)
+	testrequire.Equal(t, `sum(rate(kepler_container_joules_total{container_namespace="default",pod_name=~"frontend-5d8f7c-.*"}[5m]))`, query)
+}
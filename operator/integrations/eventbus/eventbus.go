@@ -0,0 +1,45 @@
+package eventbus
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+const ProviderNATS = "nats"
+const ProviderKafka = "kafka"
+
+// Event is a lifecycle event published to the event bus, its JSON representation is described in schema.json
+type Event struct {
+	// Source is the component which recorded the event, e.g. keptnappversion-controller
+	Source    string `json:"source"`
+	Kind      string `json:"kind"`
+	Namespace string `json:"namespace"`
+	Name      string `json:"name"`
+	// Type is either Normal or Warning
+	Type    string    `json:"type"`
+	Reason  string    `json:"reason"`
+	Message string    `json:"message"`
+	Time    time.Time `json:"time"`
+	// Attributes contains the metrics attributes of the object, such as app name and version
+	Attributes map[string]string `json:"attributes,omitempty"`
+}
+
+// Publisher publishes messages to a topic of an event bus
+type Publisher interface {
+	Publish(ctx context.Context, topic string, payload []byte) error
+}
+
+// NewPublisher returns the Publisher for the given provider. If no provider is configured, nil is returned.
+func NewPublisher(provider string, url string) (Publisher, error) {
+	switch provider {
+	case "":
+		return nil, nil
+	case ProviderNATS:
+		return NewNATS(url)
+	case ProviderKafka:
+		return NewKafkaRESTProxy(url), nil
+	default:
+		return nil, fmt.Errorf("unsupported event bus provider %s", provider)
+	}
+}
@@ -0,0 +1,132 @@
+package eventbus
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+	klcv1alpha1 "github.com/keptn/lifecycle-controller/operator/api/v1alpha1"
+	testrequire "github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+)
+
+func TestNewPublisher(t *testing.T) {
+	publisher, err := NewPublisher("", "")
+	testrequire.Nil(t, err)
+	testrequire.Nil(t, publisher)
+
+	publisher, err = NewPublisher(ProviderNATS, "nats://nats.nats")
+	testrequire.Nil(t, err)
+	testrequire.Equal(t, "nats.nats:4222", publisher.(*NATS).Address)
+
+	_, err = NewPublisher("rabbitmq", "amqp://rabbitmq")
+	testrequire.NotNil(t, err)
+}
+
+func TestKafkaRESTProxy_Publish(t *testing.T) {
+	var path, contentType string
+	var body kafkaRecords
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path = r.URL.Path
+		contentType = r.Header.Get("Content-Type")
+		testrequire.Nil(t, json.NewDecoder(r.Body).Decode(&body))
+	}))
+	defer server.Close()
+
+	err := NewKafkaRESTProxy(server.URL+"/").Publish(context.TODO(), "keptn.lifecycle", []byte(`{"reason":"AppDeploySucceeded"}`))
+
+	testrequire.Nil(t, err)
+	testrequire.Equal(t, "/topics/keptn.lifecycle", path)
+	testrequire.Equal(t, kafkaContentType, contentType)
+	testrequire.Len(t, body.Records, 1)
+	testrequire.JSONEq(t, `{"reason":"AppDeploySucceeded"}`, string(body.Records[0].Value))
+}
+
+func TestNATS_Publish(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	testrequire.Nil(t, err)
+	defer listener.Close()
+
+	received := make(chan string, 1)
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		reader := bufio.NewReader(conn)
+		conn.Write([]byte("INFO {}\r\n"))
+		for {
+			line, err := reader.ReadString('\n')
+			if err != nil {
+				return
+			}
+			switch {
+			case strings.HasPrefix(line, "PUB"):
+				payload, _ := reader.ReadString('\n')
+				received <- line + payload
+			case strings.HasPrefix(line, "PING"):
+				conn.Write([]byte("PONG\r\n"))
+			}
+		}
+	}()
+
+	publisher, err := NewNATS("nats://" + listener.Addr().String())
+	testrequire.Nil(t, err)
+	err = publisher.Publish(context.TODO(), "keptn.lifecycle", []byte(`{}`))
+
+	testrequire.Nil(t, err)
+	testrequire.Equal(t, "PUB keptn.lifecycle 2\r\n{}\r\n", <-received)
+}
+
+type fakePublisher struct {
+	published chan []byte
+}
+
+func (p *fakePublisher) Publish(_ context.Context, _ string, payload []byte) error {
+	p.published <- payload
+	return nil
+}
+
+func TestExporter_Recorder(t *testing.T) {
+	publisher := &fakePublisher{published: make(chan []byte, 1)}
+	exporter := NewExporter(publisher, "keptn.lifecycle", logr.Discard())
+	ctx, cancel := context.WithCancel(context.TODO())
+	defer cancel()
+	go exporter.Start(ctx)
+
+	fakeRecorder := record.NewFakeRecorder(10)
+	recorder := exporter.Recorder("keptnappversion-controller", fakeRecorder)
+	appVersion := &klcv1alpha1.KeptnAppVersion{
+		ObjectMeta: metav1.ObjectMeta{Name: "myapp-1.0.0", Namespace: "default"},
+		Spec: klcv1alpha1.KeptnAppVersionSpec{
+			AppName:      "myapp",
+			KeptnAppSpec: klcv1alpha1.KeptnAppSpec{Version: "1.0.0"},
+		},
+	}
+	recorder.Eventf(appVersion, "Normal", "AppDeploySucceeded", "deployment of %s succeeded", "myapp")
+
+	testrequire.Equal(t, "Normal AppDeploySucceeded deployment of myapp succeeded", <-fakeRecorder.Events)
+
+	var event Event
+	select {
+	case payload := <-publisher.published:
+		testrequire.Nil(t, json.Unmarshal(payload, &event))
+	case <-time.After(5 * time.Second):
+		t.Fatal("event was not published")
+	}
+	testrequire.Equal(t, "keptnappversion-controller", event.Source)
+	testrequire.Equal(t, "KeptnAppVersion", event.Kind)
+	testrequire.Equal(t, "default", event.Namespace)
+	testrequire.Equal(t, "myapp-1.0.0", event.Name)
+	testrequire.Equal(t, "deployment of myapp succeeded", event.Message)
+	testrequire.Equal(t, "myapp", event.Attributes["keptn.deployment.app.name"])
+}
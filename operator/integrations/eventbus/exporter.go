@@ -0,0 +1,135 @@
+package eventbus
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"time"
+
+	"github.com/go-logr/logr"
+	"go.opentelemetry.io/otel/attribute"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+)
+
+const defaultQueueSize = 1024
+
+type metricsAttributesProvider interface {
+	GetMetricsAttributes() []attribute.KeyValue
+}
+
+// Exporter publishes the events recorded by the controllers to an event bus.
+// Events are queued and published in the background, so that recording an event never blocks a reconciliation.
+// If the queue is full, events are dropped.
+type Exporter struct {
+	Publisher Publisher
+	Topic     string
+	Log       logr.Logger
+
+	queue chan Event
+}
+
+// NewExporter returns an Exporter which publishes events to the given topic once it has been started
+func NewExporter(publisher Publisher, topic string, log logr.Logger) *Exporter {
+	return &Exporter{
+		Publisher: publisher,
+		Topic:     topic,
+		Log:       log,
+		queue:     make(chan Event, defaultQueueSize),
+	}
+}
+
+// Recorder wraps the given recorder, so that all events recorded with it are also exported
+func (e *Exporter) Recorder(source string, recorder record.EventRecorder) record.EventRecorder {
+	return NewRecorder(source, recorder, e.enqueue)
+}
+
+// Start publishes queued events until the context is cancelled
+func (e *Exporter) Start(ctx context.Context) error {
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case event := <-e.queue:
+			if err := e.publish(ctx, event); err != nil {
+				e.Log.Error(err, "could not export event", "reason", event.Reason, "namespace", event.Namespace, "name", event.Name)
+			}
+		}
+	}
+}
+
+// NeedLeaderElection returns false, so that events of the webhook are exported by every replica
+func (e *Exporter) NeedLeaderElection() bool {
+	return false
+}
+
+func (e *Exporter) publish(ctx context.Context, event Event) error {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("could not marshal event: %w", err)
+	}
+	return e.Publisher.Publish(ctx, e.Topic, payload)
+}
+
+func (e *Exporter) enqueue(event Event) {
+	select {
+	case e.queue <- event:
+	default:
+		e.Log.Info("event queue is full, dropping event", "reason", event.Reason, "namespace", event.Namespace, "name", event.Name)
+	}
+}
+
+// NewEvent returns the Event for an event recorded by the given source
+func NewEvent(source string, object runtime.Object, eventtype, reason, message string) Event {
+	event := Event{
+		Source:  source,
+		Kind:    object.GetObjectKind().GroupVersionKind().Kind,
+		Type:    eventtype,
+		Reason:  reason,
+		Message: message,
+		Time:    time.Now().UTC(),
+	}
+	if event.Kind == "" {
+		event.Kind = reflect.Indirect(reflect.ValueOf(object)).Type().Name()
+	}
+	if accessor, err := meta.Accessor(object); err == nil {
+		event.Namespace = accessor.GetNamespace()
+		event.Name = accessor.GetName()
+	}
+	if provider, ok := object.(metricsAttributesProvider); ok {
+		event.Attributes = map[string]string{}
+		for _, attr := range provider.GetMetricsAttributes() {
+			event.Attributes[string(attr.Key)] = attr.Value.Emit()
+		}
+	}
+	return event
+}
+
+// NewRecorder wraps the given recorder, so that all events recorded with it are also passed to the handler.
+// The handler is called synchronously and must not block.
+func NewRecorder(source string, recorder record.EventRecorder, handler func(Event)) record.EventRecorder {
+	return &forwardingRecorder{EventRecorder: recorder, handler: handler, source: source}
+}
+
+type forwardingRecorder struct {
+	record.EventRecorder
+	handler func(Event)
+	source  string
+}
+
+func (r *forwardingRecorder) Event(object runtime.Object, eventtype, reason, message string) {
+	r.EventRecorder.Event(object, eventtype, reason, message)
+	r.handler(NewEvent(r.source, object, eventtype, reason, message))
+}
+
+func (r *forwardingRecorder) Eventf(object runtime.Object, eventtype, reason, messageFmt string, args ...interface{}) {
+	r.EventRecorder.Eventf(object, eventtype, reason, messageFmt, args...)
+	r.handler(NewEvent(r.source, object, eventtype, reason, fmt.Sprintf(messageFmt, args...)))
+}
+
+func (r *forwardingRecorder) AnnotatedEventf(object runtime.Object, annotations map[string]string, eventtype, reason, messageFmt string, args ...interface{}) {
+	r.EventRecorder.AnnotatedEventf(object, annotations, eventtype, reason, messageFmt, args...)
+	r.handler(NewEvent(r.source, object, eventtype, reason, fmt.Sprintf(messageFmt, args...)))
+}
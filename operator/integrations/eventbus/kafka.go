@@ -0,0 +1,58 @@
+package eventbus
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const kafkaContentType = "application/vnd.kafka.json.v2+json"
+
+// KafkaRESTProxy publishes messages to Kafka topics via the Confluent REST Proxy
+type KafkaRESTProxy struct {
+	URL        string
+	HTTPClient *http.Client
+}
+
+// NewKafkaRESTProxy returns a Kafka publisher for a REST Proxy URL like http://kafka-rest.kafka:8082
+func NewKafkaRESTProxy(proxyURL string) *KafkaRESTProxy {
+	return &KafkaRESTProxy{
+		URL:        strings.TrimSuffix(proxyURL, "/"),
+		HTTPClient: &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+type kafkaRecords struct {
+	Records []kafkaRecord `json:"records"`
+}
+
+type kafkaRecord struct {
+	Value json.RawMessage `json:"value"`
+}
+
+// Publish produces the payload as a single JSON record to the given topic
+func (k *KafkaRESTProxy) Publish(ctx context.Context, topic string, payload []byte) error {
+	body, err := json.Marshal(kafkaRecords{Records: []kafkaRecord{{Value: payload}}})
+	if err != nil {
+		return fmt.Errorf("could not marshal records: %w", err)
+	}
+	url := fmt.Sprintf("%s/topics/%s", k.URL, topic)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("could not create request: %w", err)
+	}
+	req.Header.Set("Content-Type", kafkaContentType)
+	resp, err := k.HTTPClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("could not send request: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("unexpected response status %s from %s", resp.Status, url)
+	}
+	return nil
+}
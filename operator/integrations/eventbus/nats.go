@@ -0,0 +1,110 @@
+package eventbus
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+const natsDefaultPort = "4222"
+const natsTimeout = 10 * time.Second
+
+// NATS publishes messages to a NATS server using the NATS client protocol
+type NATS struct {
+	Address string
+
+	mtx    sync.Mutex
+	conn   net.Conn
+	reader *bufio.Reader
+}
+
+// NewNATS returns a NATS publisher for a server URL like nats://nats.nats:4222
+func NewNATS(serverURL string) (*NATS, error) {
+	u, err := url.Parse(serverURL)
+	if err != nil || u.Host == "" {
+		return nil, fmt.Errorf("invalid NATS server url %s", serverURL)
+	}
+	address := u.Host
+	if u.Port() == "" {
+		address = net.JoinHostPort(u.Hostname(), natsDefaultPort)
+	}
+	return &NATS{Address: address}, nil
+}
+
+// Publish sends the payload to the given subject and waits until the server has processed it.
+// If the connection is broken, it is re-established on the next call.
+func (n *NATS) Publish(ctx context.Context, subject string, payload []byte) error {
+	n.mtx.Lock()
+	defer n.mtx.Unlock()
+
+	if n.conn == nil {
+		if err := n.connect(ctx); err != nil {
+			return err
+		}
+	}
+	if err := n.publish(ctx, subject, payload); err != nil {
+		n.conn.Close()
+		n.conn = nil
+		return err
+	}
+	return nil
+}
+
+func (n *NATS) connect(ctx context.Context) error {
+	dialer := net.Dialer{Timeout: natsTimeout}
+	conn, err := dialer.DialContext(ctx, "tcp", n.Address)
+	if err != nil {
+		return fmt.Errorf("could not connect to NATS server %s: %w", n.Address, err)
+	}
+	reader := bufio.NewReader(conn)
+	conn.SetDeadline(deadline(ctx))
+	info, err := reader.ReadString('\n')
+	if err != nil || !strings.HasPrefix(info, "INFO") {
+		conn.Close()
+		return fmt.Errorf("could not read INFO from NATS server %s", n.Address)
+	}
+	if _, err := conn.Write([]byte("CONNECT {\"verbose\":false,\"pedantic\":false,\"name\":\"keptn-lifecycle-operator\"}\r\n")); err != nil {
+		conn.Close()
+		return fmt.Errorf("could not send CONNECT to NATS server %s: %w", n.Address, err)
+	}
+	n.conn = conn
+	n.reader = reader
+	return nil
+}
+
+func (n *NATS) publish(ctx context.Context, subject string, payload []byte) error {
+	n.conn.SetDeadline(deadline(ctx))
+	msg := fmt.Sprintf("PUB %s %d\r\n%s\r\nPING\r\n", subject, len(payload), payload)
+	if _, err := n.conn.Write([]byte(msg)); err != nil {
+		return fmt.Errorf("could not publish to NATS subject %s: %w", subject, err)
+	}
+	// the PONG reply confirms that the server has processed the message
+	for {
+		line, err := n.reader.ReadString('\n')
+		if err != nil {
+			return fmt.Errorf("could not publish to NATS subject %s: %w", subject, err)
+		}
+		switch {
+		case strings.HasPrefix(line, "PONG"):
+			return nil
+		case strings.HasPrefix(line, "PING"):
+			if _, err := n.conn.Write([]byte("PONG\r\n")); err != nil {
+				return fmt.Errorf("could not answer PING of NATS server: %w", err)
+			}
+		case strings.HasPrefix(line, "-ERR"):
+			return fmt.Errorf("NATS server rejected message: %s", strings.TrimSpace(strings.TrimPrefix(line, "-ERR")))
+		}
+	}
+}
+
+func deadline(ctx context.Context) time.Time {
+	if d, ok := ctx.Deadline(); ok {
+		return d
+	}
+	return time.Now().Add(natsTimeout)
+}
@@ -0,0 +1,68 @@
+package incident
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+const ProviderPagerDuty = "pagerduty"
+const ProviderOpsgenie = "opsgenie"
+
+// Incident describes a failed change that should be brought to the attention of the on-call team
+type Incident struct {
+	// Key identifies the incident, so that it can be resolved by a subsequent successful deployment
+	Key              string
+	Summary          string
+	Source           string
+	TraceID          string
+	FailedObjectives []string
+}
+
+// Manager opens and resolves incidents in an external incident management tool
+type Manager interface {
+	Open(ctx context.Context, incident Incident) error
+	Resolve(ctx context.Context, key string) error
+}
+
+// NewManager returns the Manager for the given provider. If no provider is configured, nil is returned.
+func NewManager(provider string, apiKey string) (Manager, error) {
+	httpClient := &http.Client{Timeout: 10 * time.Second}
+	switch provider {
+	case "":
+		return nil, nil
+	case ProviderPagerDuty:
+		return &PagerDuty{RoutingKey: apiKey, URL: PagerDutyEventsURL, HTTPClient: httpClient}, nil
+	case ProviderOpsgenie:
+		return &Opsgenie{APIKey: apiKey, URL: OpsgenieAlertsURL, HTTPClient: httpClient}, nil
+	default:
+		return nil, fmt.Errorf("unsupported incident provider %s", provider)
+	}
+}
+
+func post(ctx context.Context, httpClient *http.Client, url string, headers map[string]string, body interface{}) error {
+	payload, err := json.Marshal(body)
+	if err != nil {
+		return fmt.Errorf("could not marshal request: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(payload))
+	if err != nil {
+		return fmt.Errorf("could not create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("could not send request: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("unexpected response status %s from %s", resp.Status, url)
+	}
+	return nil
+}
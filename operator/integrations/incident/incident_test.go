@@ -0,0 +1,65 @@
+package incident
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	testrequire "github.com/stretchr/testify/require"
+)
+
+func TestPagerDuty_OpenAndResolve(t *testing.T) {
+	var events []pagerDutyEvent
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		event := pagerDutyEvent{}
+		testrequire.Nil(t, json.NewDecoder(r.Body).Decode(&event))
+		events = append(events, event)
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer server.Close()
+
+	p := &PagerDuty{RoutingKey: "my-key", URL: server.URL, HTTPClient: server.Client()}
+
+	err := p.Open(context.TODO(), Incident{Key: "keptn/default/my-app", Summary: "failed", TraceID: "1234", FailedObjectives: []string{"a", "b"}})
+	testrequire.Nil(t, err)
+	err = p.Resolve(context.TODO(), "keptn/default/my-app")
+	testrequire.Nil(t, err)
+
+	testrequire.Len(t, events, 2)
+	testrequire.Equal(t, "trigger", events[0].EventAction)
+	testrequire.Equal(t, "my-key", events[0].RoutingKey)
+	testrequire.Equal(t, "1234", events[0].Payload.CustomDetails["traceId"])
+	testrequire.Equal(t, "a\nb", events[0].Payload.CustomDetails["failedObjectives"])
+	testrequire.Equal(t, "resolve", events[1].EventAction)
+	testrequire.Equal(t, "keptn/default/my-app", events[1].DedupKey)
+}
+
+func TestOpsgenie_ResolveUsesAlias(t *testing.T) {
+	var path, query, auth string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path = r.URL.EscapedPath()
+		query = r.URL.RawQuery
+		auth = r.Header.Get("Authorization")
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer server.Close()
+
+	o := &Opsgenie{APIKey: "my-key", URL: server.URL + "/v2/alerts", HTTPClient: server.Client()}
+
+	err := o.Resolve(context.TODO(), "keptn/default/my-app")
+	testrequire.Nil(t, err)
+	testrequire.Equal(t, "/v2/alerts/keptn%2Fdefault%2Fmy-app/close", path)
+	testrequire.Equal(t, "identifierType=alias", query)
+	testrequire.Equal(t, "GenieKey my-key", auth)
+}
+
+func TestNewManager(t *testing.T) {
+	m, err := NewManager("", "")
+	testrequire.Nil(t, err)
+	testrequire.Nil(t, m)
+
+	_, err = NewManager("unknown", "")
+	testrequire.NotNil(t, err)
+}
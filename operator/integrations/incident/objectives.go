@@ -0,0 +1,36 @@
+package incident
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/go-logr/logr"
+	klcv1alpha1 "github.com/keptn/lifecycle-controller/operator/api/v1alpha1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// FailedObjectives returns the objectives of the failed evaluations that were not met, sorted by the name of their
+// evaluation definition. Evaluations that cannot be retrieved are skipped.
+func FailedObjectives(ctx context.Context, c client.Reader, log logr.Logger, namespace string, evaluations []klcv1alpha1.EvaluationStatus) []string {
+	var failedObjectives []string
+	for _, evaluationStatus := range evaluations {
+		if !evaluationStatus.Status.IsFailed() || evaluationStatus.EvaluationName == "" {
+			continue
+		}
+		evaluation := &klcv1alpha1.KeptnEvaluation{}
+		if err := c.Get(ctx, types.NamespacedName{Name: evaluationStatus.EvaluationName, Namespace: namespace}, evaluation); err != nil {
+			log.Error(err, "could not fetch KeptnEvaluation")
+			continue
+		}
+		for objective, item := range evaluation.Status.EvaluationStatus {
+			if item.Status.IsSucceeded() {
+				continue
+			}
+			failedObjectives = append(failedObjectives, fmt.Sprintf("%s/%s: value %q %s", evaluationStatus.EvaluationDefinitionName, objective, item.Value, item.Message))
+		}
+	}
+	sort.Strings(failedObjectives)
+	return failedObjectives
+}
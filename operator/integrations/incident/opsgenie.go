@@ -0,0 +1,48 @@
+package incident
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+const OpsgenieAlertsURL = "https://api.opsgenie.com/v2/alerts"
+
+// Opsgenie opens and closes alerts via the Opsgenie Alert API. The incident key is used as alias of the alert.
+type Opsgenie struct {
+	APIKey     string
+	URL        string
+	HTTPClient *http.Client
+}
+
+type opsgenieAlert struct {
+	Message     string            `json:"message"`
+	Alias       string            `json:"alias"`
+	Description string            `json:"description,omitempty"`
+	Source      string            `json:"source,omitempty"`
+	Priority    string            `json:"priority"`
+	Details     map[string]string `json:"details,omitempty"`
+}
+
+func (o *Opsgenie) Open(ctx context.Context, incident Incident) error {
+	return post(ctx, o.HTTPClient, o.URL, o.headers(), opsgenieAlert{
+		Message:     incident.Summary,
+		Alias:       incident.Key,
+		Description: strings.Join(incident.FailedObjectives, "\n"),
+		Source:      incident.Source,
+		Priority:    "P1",
+		Details: map[string]string{
+			"traceId": incident.TraceID,
+		},
+	})
+}
+
+func (o *Opsgenie) Resolve(ctx context.Context, key string) error {
+	closeURL := o.URL + "/" + url.PathEscape(key) + "/close?identifierType=alias"
+	return post(ctx, o.HTTPClient, closeURL, o.headers(), struct{}{})
+}
+
+func (o *Opsgenie) headers() map[string]string {
+	return map[string]string{"Authorization": "GenieKey " + o.APIKey}
+}
@@ -0,0 +1,55 @@
+package incident
+
+import (
+	"context"
+	"net/http"
+	"strings"
+)
+
+const PagerDutyEventsURL = "https://events.pagerduty.com/v2/enqueue"
+
+// PagerDuty opens and resolves incidents via the PagerDuty Events API v2
+type PagerDuty struct {
+	RoutingKey string
+	URL        string
+	HTTPClient *http.Client
+}
+
+type pagerDutyEvent struct {
+	RoutingKey  string            `json:"routing_key"`
+	EventAction string            `json:"event_action"`
+	DedupKey    string            `json:"dedup_key"`
+	Payload     *pagerDutyPayload `json:"payload,omitempty"`
+}
+
+type pagerDutyPayload struct {
+	Summary       string            `json:"summary"`
+	Source        string            `json:"source"`
+	Severity      string            `json:"severity"`
+	CustomDetails map[string]string `json:"custom_details,omitempty"`
+}
+
+func (p *PagerDuty) Open(ctx context.Context, incident Incident) error {
+	return post(ctx, p.HTTPClient, p.URL, nil, pagerDutyEvent{
+		RoutingKey:  p.RoutingKey,
+		EventAction: "trigger",
+		DedupKey:    incident.Key,
+		Payload: &pagerDutyPayload{
+			Summary:  incident.Summary,
+			Source:   incident.Source,
+			Severity: "critical",
+			CustomDetails: map[string]string{
+				"traceId":          incident.TraceID,
+				"failedObjectives": strings.Join(incident.FailedObjectives, "\n"),
+			},
+		},
+	})
+}
+
+func (p *PagerDuty) Resolve(ctx context.Context, key string) error {
+	return post(ctx, p.HTTPClient, p.URL, nil, pagerDutyEvent{
+		RoutingKey:  p.RoutingKey,
+		EventAction: "resolve",
+		DedupKey:    key,
+	})
+}
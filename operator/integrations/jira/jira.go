@@ -0,0 +1,86 @@
+package jira
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// Client comments on JIRA issues using the JIRA REST API
+type Client struct {
+	URL        string
+	User       string
+	APIToken   string
+	HTTPClient *http.Client
+	// TraceURL is the URL of the tracing UI a trace ID is appended to, e.g. http://jaeger-query:16686/trace/
+	TraceURL string
+}
+
+// DeploymentOutcome describes the result of a deployment that is reported to an issue
+type DeploymentOutcome struct {
+	Issue     string
+	Namespace string
+	Workload  string
+	Version   string
+	Status    string
+	Duration  time.Duration
+	TraceID   string
+}
+
+// NewClient returns a Client for the JIRA instance at the given URL
+func NewClient(jiraURL string, user string, apiToken string, traceURL string) *Client {
+	return &Client{
+		URL:        strings.TrimSuffix(jiraURL, "/"),
+		User:       user,
+		APIToken:   apiToken,
+		HTTPClient: &http.Client{Timeout: 10 * time.Second},
+		TraceURL:   traceURL,
+	}
+}
+
+// CommentDeploymentOutcome adds a comment with the outcome of the deployment to the referenced issue
+func (c *Client) CommentDeploymentOutcome(ctx context.Context, outcome DeploymentOutcome) error {
+	return c.Comment(ctx, outcome.Issue, c.formatOutcome(outcome))
+}
+
+// Comment adds the given comment to an issue
+func (c *Client) Comment(ctx context.Context, issue string, comment string) error {
+	payload, err := json.Marshal(map[string]string{"body": comment})
+	if err != nil {
+		return fmt.Errorf("could not marshal comment: %w", err)
+	}
+	commentURL := fmt.Sprintf("%s/rest/api/2/issue/%s/comment", c.URL, url.PathEscape(issue))
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, commentURL, bytes.NewReader(payload))
+	if err != nil {
+		return fmt.Errorf("could not create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.SetBasicAuth(c.User, c.APIToken)
+
+	resp, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("could not comment on issue %s: %w", issue, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("could not comment on issue %s: unexpected response status %s", issue, resp.Status)
+	}
+	return nil
+}
+
+func (c *Client) formatOutcome(outcome DeploymentOutcome) string {
+	comment := fmt.Sprintf("Deployment of %s version %s in namespace %s has %s after %s.",
+		outcome.Workload, outcome.Version, outcome.Namespace, strings.ToLower(outcome.Status), outcome.Duration.Round(time.Second))
+	if outcome.TraceID == "" {
+		return comment
+	}
+	if c.TraceURL != "" {
+		return fmt.Sprintf("%s\nTrace: %s%s", comment, c.TraceURL, outcome.TraceID)
+	}
+	return fmt.Sprintf("%s\nTrace ID: %s", comment, outcome.TraceID)
+}
@@ -0,0 +1,52 @@
+package jira
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	testrequire "github.com/stretchr/testify/require"
+)
+
+func TestClient_CommentDeploymentOutcome(t *testing.T) {
+	var path, user, comment string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path = r.URL.Path
+		user, _, _ = r.BasicAuth()
+		body := map[string]string{}
+		testrequire.Nil(t, json.NewDecoder(r.Body).Decode(&body))
+		comment = body["body"]
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer server.Close()
+
+	c := NewClient(server.URL+"/", "bot", "token", "http://jaeger/trace/")
+	err := c.CommentDeploymentOutcome(context.TODO(), DeploymentOutcome{
+		Issue:     "PROJ-42",
+		Namespace: "default",
+		Workload:  "myapp-frontend",
+		Version:   "1.0.0",
+		Status:    "Succeeded",
+		Duration:  90 * time.Second,
+		TraceID:   "abc",
+	})
+
+	testrequire.Nil(t, err)
+	testrequire.Equal(t, "/rest/api/2/issue/PROJ-42/comment", path)
+	testrequire.Equal(t, "bot", user)
+	testrequire.Equal(t, "Deployment of myapp-frontend version 1.0.0 in namespace default has succeeded after 1m30s.\nTrace: http://jaeger/trace/abc", comment)
+}
+
+func TestClient_CommentFails(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	c := NewClient(server.URL, "bot", "token", "")
+	err := c.Comment(context.TODO(), "PROJ-1", "hello")
+	testrequire.NotNil(t, err)
+}
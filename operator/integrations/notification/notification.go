@@ -0,0 +1,180 @@
+package notification
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/go-logr/logr"
+	klcv1alpha1 "github.com/keptn/lifecycle-controller/operator/api/v1alpha1"
+	"github.com/keptn/lifecycle-controller/operator/api/v1alpha1/common"
+	"github.com/keptn/lifecycle-controller/operator/integrations/eventbus"
+	"github.com/keptn/lifecycle-controller/operator/integrations/incident"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const defaultQueueSize = 1024
+
+//+kubebuilder:rbac:groups=lifecycle.keptn.sh,resources=keptnnotificationroutes,verbs=get;list;watch
+//+kubebuilder:rbac:groups=lifecycle.keptn.sh,resources=keptnapps,verbs=get;list;watch
+//+kubebuilder:rbac:groups=core,resources=secrets,verbs=get;list;watch
+
+// Router sends the events recorded by the controllers to the sinks of all KeptnNotificationRoutes matching them.
+// Like the event bus exporter, it queues events and sends them in the background, so that recording an event never
+// blocks a reconciliation. If the queue is full, events are dropped.
+type Router struct {
+	Client client.Reader
+	// Namespace is the namespace of the secrets of the sinks
+	Namespace    string
+	HTTPClient   *http.Client
+	PagerDutyURL string
+	Log          logr.Logger
+
+	queue chan eventbus.Event
+}
+
+// NewRouter returns a Router which reads the routes and the secrets of their sinks with the given client
+func NewRouter(c client.Reader, namespace string, log logr.Logger) *Router {
+	return &Router{
+		Client:       c,
+		Namespace:    namespace,
+		HTTPClient:   &http.Client{Timeout: 10 * time.Second},
+		PagerDutyURL: incident.PagerDutyEventsURL,
+		Log:          log,
+		queue:        make(chan eventbus.Event, defaultQueueSize),
+	}
+}
+
+// Recorder wraps the given recorder, so that all events recorded with it are also routed
+func (r *Router) Recorder(source string, recorder record.EventRecorder) record.EventRecorder {
+	return eventbus.NewRecorder(source, recorder, r.enqueue)
+}
+
+// Start routes queued events until the context is cancelled
+func (r *Router) Start(ctx context.Context) error {
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case event := <-r.queue:
+			if err := r.route(ctx, event); err != nil {
+				r.Log.Error(err, "could not route event", "reason", event.Reason, "namespace", event.Namespace, "name", event.Name)
+			}
+		}
+	}
+}
+
+// NeedLeaderElection returns false, so that events of the webhook are routed by every replica
+func (r *Router) NeedLeaderElection() bool {
+	return false
+}
+
+func (r *Router) enqueue(event eventbus.Event) {
+	select {
+	case r.queue <- event:
+	default:
+		r.Log.Info("notification queue is full, dropping event", "reason", event.Reason, "namespace", event.Namespace, "name", event.Name)
+	}
+}
+
+func (r *Router) route(ctx context.Context, event eventbus.Event) error {
+	routes := &klcv1alpha1.KeptnNotificationRouteList{}
+	if err := r.Client.List(ctx, routes); err != nil {
+		return fmt.Errorf("could not list notification routes: %w", err)
+	}
+	var appLabels labels.Set
+	var failed []string
+	for _, route := range routes.Items {
+		if route.Spec.Match.AppSelector != nil && appLabels == nil {
+			var err error
+			if appLabels, err = r.appLabels(ctx, event); err != nil {
+				return err
+			}
+		}
+		matches, err := Matches(route.Spec.Match, event, appLabels)
+		if err != nil {
+			failed = append(failed, fmt.Sprintf("route %s: %s", route.Name, err))
+			continue
+		}
+		if !matches {
+			continue
+		}
+		for _, sink := range route.Spec.Sinks {
+			if err := r.send(ctx, sink, event); err != nil {
+				failed = append(failed, fmt.Sprintf("route %s: %s sink: %s", route.Name, sink.Type, err))
+			}
+		}
+	}
+	if len(failed) > 0 {
+		return fmt.Errorf("could not notify all sinks: %s", strings.Join(failed, "; "))
+	}
+	return nil
+}
+
+// appLabels returns the labels of the KeptnApp the event belongs to, or an empty set if it does not belong to an app
+func (r *Router) appLabels(ctx context.Context, event eventbus.Event) (labels.Set, error) {
+	appName := event.Attributes[string(common.AppName)]
+	if event.Kind == "KeptnApp" {
+		appName = event.Name
+	}
+	if appName == "" {
+		return labels.Set{}, nil
+	}
+	app := &klcv1alpha1.KeptnApp{}
+	if err := r.Client.Get(ctx, types.NamespacedName{Namespace: event.Namespace, Name: appName}, app); err != nil {
+		if errors.IsNotFound(err) {
+			return labels.Set{}, nil
+		}
+		return nil, fmt.Errorf("could not retrieve KeptnApp %s: %w", appName, err)
+	}
+	return app.Labels, nil
+}
+
+// Matches returns whether the event is selected by the match. appLabels are the labels of the KeptnApp the event
+// belongs to and only have to be set if the match has an AppSelector.
+func Matches(match klcv1alpha1.NotificationMatch, event eventbus.Event, appLabels labels.Set) (bool, error) {
+	if len(match.Namespaces) > 0 && !contains(match.Namespaces, event.Namespace) {
+		return false, nil
+	}
+	if len(match.Severities) > 0 && !contains(match.Severities, Severity(event)) {
+		return false, nil
+	}
+	if len(match.Reasons) > 0 && !contains(match.Reasons, event.Reason) {
+		return false, nil
+	}
+	if match.AppSelector != nil {
+		selector, err := metav1.LabelSelectorAsSelector(match.AppSelector)
+		if err != nil {
+			return false, fmt.Errorf("invalid app selector: %w", err)
+		}
+		if !selector.Matches(appLabels) {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// Severity returns the severity of the event
+func Severity(event eventbus.Event) klcv1alpha1.NotificationSeverity {
+	if event.Type == corev1.EventTypeWarning {
+		return klcv1alpha1.NotificationSeverityWarning
+	}
+	return klcv1alpha1.NotificationSeverityInfo
+}
+
+func contains[T comparable](values []T, value T) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,160 @@
+package notification
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-logr/logr"
+	klcv1alpha1 "github.com/keptn/lifecycle-controller/operator/api/v1alpha1"
+	"github.com/keptn/lifecycle-controller/operator/integrations/eventbus"
+	testrequire "github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestMatches(t *testing.T) {
+	failure := eventbus.Event{Namespace: "prod", Kind: "KeptnAppVersion", Type: "Warning", Reason: "AppDeployFailed"}
+	prodFailures := klcv1alpha1.NotificationMatch{
+		Namespaces:  []string{"prod"},
+		Severities:  []klcv1alpha1.NotificationSeverity{klcv1alpha1.NotificationSeverityWarning},
+		AppSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"team": "checkout"}},
+	}
+
+	tests := []struct {
+		name      string
+		match     klcv1alpha1.NotificationMatch
+		event     eventbus.Event
+		appLabels labels.Set
+		want      bool
+	}{
+		{
+			name:  "empty match",
+			event: failure,
+			want:  true,
+		},
+		{
+			name:      "prod failure",
+			match:     prodFailures,
+			event:     failure,
+			appLabels: labels.Set{"team": "checkout"},
+			want:      true,
+		},
+		{
+			name:      "other team",
+			match:     prodFailures,
+			event:     failure,
+			appLabels: labels.Set{"team": "payment"},
+			want:      false,
+		},
+		{
+			name:      "info",
+			match:     prodFailures,
+			event:     eventbus.Event{Namespace: "prod", Type: "Normal", Reason: "AppDeploySucceeded"},
+			appLabels: labels.Set{"team": "checkout"},
+			want:      false,
+		},
+		{
+			name:      "staging",
+			match:     prodFailures,
+			event:     eventbus.Event{Namespace: "staging", Type: "Warning", Reason: "AppDeployFailed"},
+			appLabels: labels.Set{"team": "checkout"},
+			want:      false,
+		},
+		{
+			name:  "reason",
+			match: klcv1alpha1.NotificationMatch{Reasons: []string{"AppDeploySucceeded"}},
+			event: failure,
+			want:  false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Matches(tt.match, tt.event, tt.appLabels)
+			testrequire.Nil(t, err)
+			testrequire.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestRouter_Route(t *testing.T) {
+	slack := make(chan slackMessage, 2)
+	pagerDuty := make(chan map[string]interface{}, 2)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/slack":
+			var message slackMessage
+			testrequire.Nil(t, json.NewDecoder(r.Body).Decode(&message))
+			slack <- message
+		case "/pagerduty":
+			var body map[string]interface{}
+			testrequire.Nil(t, json.NewDecoder(r.Body).Decode(&body))
+			pagerDuty <- body
+		}
+	}))
+	defer server.Close()
+
+	scheme := runtime.NewScheme()
+	testrequire.Nil(t, clientgoscheme.AddToScheme(scheme))
+	testrequire.Nil(t, klcv1alpha1.AddToScheme(scheme))
+	k8sClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+		&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "slack", Namespace: "keptn-lifecycle-controller-system"},
+			Data:       map[string][]byte{SecretKeyURL: []byte(server.URL + "/slack")},
+		},
+		&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "pagerduty", Namespace: "keptn-lifecycle-controller-system"},
+			Data:       map[string][]byte{SecretKeyRoutingKey: []byte("routing-key")},
+		},
+		&klcv1alpha1.KeptnApp{ObjectMeta: metav1.ObjectMeta{Name: "podtato-head", Namespace: "prod", Labels: map[string]string{"tier": "critical"}}},
+		&klcv1alpha1.KeptnNotificationRoute{
+			ObjectMeta: metav1.ObjectMeta{Name: "everything"},
+			Spec: klcv1alpha1.KeptnNotificationRouteSpec{
+				Sinks: []klcv1alpha1.NotificationSink{{Type: klcv1alpha1.NotificationSinkSlack, SecretName: "slack", Channel: "#deployments"}},
+			},
+		},
+		&klcv1alpha1.KeptnNotificationRoute{
+			ObjectMeta: metav1.ObjectMeta{Name: "critical-prod-failures"},
+			Spec: klcv1alpha1.KeptnNotificationRouteSpec{
+				Match: klcv1alpha1.NotificationMatch{
+					Namespaces:  []string{"prod"},
+					Severities:  []klcv1alpha1.NotificationSeverity{klcv1alpha1.NotificationSeverityWarning},
+					AppSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"tier": "critical"}},
+				},
+				Sinks: []klcv1alpha1.NotificationSink{{Type: klcv1alpha1.NotificationSinkPagerDuty, SecretName: "pagerduty"}},
+			},
+		},
+	).Build()
+
+	r := NewRouter(k8sClient, "keptn-lifecycle-controller-system", logr.Discard())
+	r.PagerDutyURL = server.URL + "/pagerduty"
+
+	err := r.route(context.TODO(), eventbus.Event{
+		Kind:       "KeptnAppVersion",
+		Namespace:  "prod",
+		Name:       "podtato-head-1.0.0",
+		Type:       "Warning",
+		Reason:     "AppDeployFailed",
+		Message:    "post-deployment evaluation failed",
+		Attributes: map[string]string{"keptn.deployment.app.name": "podtato-head"},
+	})
+	testrequire.Nil(t, err)
+	testrequire.Equal(t, slackMessage{
+		Text:    "[Warning] AppDeployFailed KeptnAppVersion prod/podtato-head-1.0.0: post-deployment evaluation failed",
+		Channel: "#deployments",
+	}, <-slack)
+	incident := <-pagerDuty
+	testrequire.Equal(t, "routing-key", incident["routing_key"])
+	testrequire.Equal(t, "prod/KeptnAppVersion/podtato-head-1.0.0/AppDeployFailed", incident["dedup_key"])
+
+	err = r.route(context.TODO(), eventbus.Event{Kind: "KeptnApp", Namespace: "prod", Name: "podtato-head", Type: "Normal", Reason: "AppVersionCreated"})
+	testrequire.Nil(t, err)
+	testrequire.Len(t, slack, 1)
+	testrequire.Len(t, pagerDuty, 0)
+}
@@ -0,0 +1,100 @@
+package notification
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	neturl "net/url"
+
+	klcv1alpha1 "github.com/keptn/lifecycle-controller/operator/api/v1alpha1"
+	"github.com/keptn/lifecycle-controller/operator/integrations/eventbus"
+	"github.com/keptn/lifecycle-controller/operator/integrations/incident"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+const (
+	// SecretKeyURL is the key of the URL of Slack and Webhook sinks
+	SecretKeyURL = "url"
+	// SecretKeyRoutingKey is the key of the routing key of PagerDuty sinks
+	SecretKeyRoutingKey = "routingKey"
+)
+
+type slackMessage struct {
+	Text    string `json:"text"`
+	Channel string `json:"channel,omitempty"`
+}
+
+func (r *Router) send(ctx context.Context, sink klcv1alpha1.NotificationSink, event eventbus.Event) error {
+	switch sink.Type {
+	case klcv1alpha1.NotificationSinkSlack:
+		url, err := r.secretValue(ctx, sink.SecretName, SecretKeyURL)
+		if err != nil {
+			return err
+		}
+		return r.post(ctx, url, slackMessage{Text: Text(event), Channel: sink.Channel})
+	case klcv1alpha1.NotificationSinkWebhook:
+		url, err := r.secretValue(ctx, sink.SecretName, SecretKeyURL)
+		if err != nil {
+			return err
+		}
+		return r.post(ctx, url, event)
+	case klcv1alpha1.NotificationSinkPagerDuty:
+		routingKey, err := r.secretValue(ctx, sink.SecretName, SecretKeyRoutingKey)
+		if err != nil {
+			return err
+		}
+		pagerDuty := &incident.PagerDuty{RoutingKey: routingKey, URL: r.PagerDutyURL, HTTPClient: r.HTTPClient}
+		return pagerDuty.Open(ctx, incident.Incident{
+			Key:     fmt.Sprintf("%s/%s/%s/%s", event.Namespace, event.Kind, event.Name, event.Reason),
+			Summary: Text(event),
+			Source:  event.Source,
+		})
+	default:
+		return fmt.Errorf("unsupported sink type %s", sink.Type)
+	}
+}
+
+func (r *Router) secretValue(ctx context.Context, name string, key string) (string, error) {
+	secret := &corev1.Secret{}
+	if err := r.Client.Get(ctx, types.NamespacedName{Namespace: r.Namespace, Name: name}, secret); err != nil {
+		return "", fmt.Errorf("could not retrieve secret %s: %w", name, err)
+	}
+	value, ok := secret.Data[key]
+	if !ok || len(value) == 0 {
+		return "", fmt.Errorf("secret %s does not contain the key %s", name, key)
+	}
+	return string(value), nil
+}
+
+func (r *Router) post(ctx context.Context, url string, body interface{}) error {
+	payload, err := json.Marshal(body)
+	if err != nil {
+		return fmt.Errorf("could not marshal notification: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(payload))
+	if err != nil {
+		return fmt.Errorf("could not create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := r.HTTPClient.Do(req)
+	if err != nil {
+		// the URL is not part of the error, since the URLs of Slack incoming webhooks contain their token
+		if urlErr, ok := err.(*neturl.Error); ok {
+			err = urlErr.Err
+		}
+		return fmt.Errorf("could not send notification: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("unexpected response status %s", resp.Status)
+	}
+	return nil
+}
+
+// Text returns the human-readable notification of the event
+func Text(event eventbus.Event) string {
+	return fmt.Sprintf("[%s] %s %s %s/%s: %s", Severity(event), event.Reason, event.Kind, event.Namespace, event.Name, event.Message)
+}
@@ -0,0 +1,143 @@
+package servicenow
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// States of a change request, as returned by the Table API without display values
+const (
+	StateNew       = "-5"
+	StateAssess    = "-4"
+	StateAuthorize = "-3"
+	StateScheduled = "-2"
+	StateImplement = "-1"
+	StateReview    = "0"
+	StateClosed    = "3"
+	StateCanceled  = "4"
+)
+
+// ApprovalRejected is the approval of a change request which has been rejected
+const ApprovalRejected = "rejected"
+
+// dateLayout is the layout of date-time fields returned by the Table API, which are in UTC
+const dateLayout = "2006-01-02 15:04:05"
+
+// Verdict is the outcome of checking whether a change request allows a deployment
+type Verdict string
+
+const (
+	// VerdictAllowed change requests are in the Implement state and their planned end date has not passed
+	VerdictAllowed Verdict = "Allowed"
+	// VerdictPending change requests have not been approved and scheduled yet
+	VerdictPending Verdict = "Pending"
+	// VerdictDenied change requests have been rejected, canceled or closed, or their planned end date has passed
+	VerdictDenied Verdict = "Denied"
+)
+
+// Client reads change requests using the ServiceNow Table API
+type Client struct {
+	URL        string
+	User       string
+	Password   string
+	HTTPClient *http.Client
+}
+
+// ChangeRequest is a change request of the ServiceNow change management
+type ChangeRequest struct {
+	Number   string
+	State    string
+	Approval string
+	// EndDate is the planned end date of the change request, zero if it is not set
+	EndDate time.Time
+}
+
+type tableResponse struct {
+	Result []struct {
+		Number   string `json:"number"`
+		State    string `json:"state"`
+		Approval string `json:"approval"`
+		EndDate  string `json:"end_date"`
+	} `json:"result"`
+}
+
+// NewClient returns a Client for the ServiceNow instance at the given URL
+func NewClient(serviceNowURL string, user string, password string) *Client {
+	return &Client{
+		URL:        strings.TrimSuffix(serviceNowURL, "/"),
+		User:       user,
+		Password:   password,
+		HTTPClient: &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// GetChangeRequest returns the change request with the given number, e.g. CHG0030001
+func (c *Client) GetChangeRequest(ctx context.Context, number string) (*ChangeRequest, error) {
+	query := url.Values{}
+	query.Set("sysparm_query", "number="+number)
+	query.Set("sysparm_fields", "number,state,approval,end_date")
+	query.Set("sysparm_limit", "1")
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.URL+"/api/now/table/change_request?"+query.Encode(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("could not create request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+	req.SetBasicAuth(c.User, c.Password)
+
+	resp, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("could not fetch change request %s: %w", number, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return nil, fmt.Errorf("could not fetch change request %s: unexpected response status %s", number, resp.Status)
+	}
+
+	response := tableResponse{}
+	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		return nil, fmt.Errorf("could not decode change request %s: %w", number, err)
+	}
+	if len(response.Result) == 0 {
+		return nil, nil
+	}
+
+	result := response.Result[0]
+	changeRequest := &ChangeRequest{
+		Number:   result.Number,
+		State:    result.State,
+		Approval: result.Approval,
+	}
+	if result.EndDate != "" {
+		endDate, err := time.Parse(dateLayout, result.EndDate)
+		if err != nil {
+			return nil, fmt.Errorf("could not parse planned end date of change request %s: %w", number, err)
+		}
+		changeRequest.EndDate = endDate
+	}
+	return changeRequest, nil
+}
+
+// Check returns whether the change request allows a deployment at the given time, and the reason
+func (cr ChangeRequest) Check(now time.Time) (Verdict, string) {
+	if cr.Approval == ApprovalRejected {
+		return VerdictDenied, fmt.Sprintf("change request %s has been rejected", cr.Number)
+	}
+	switch cr.State {
+	case StateCanceled:
+		return VerdictDenied, fmt.Sprintf("change request %s has been canceled", cr.Number)
+	case StateReview, StateClosed:
+		return VerdictDenied, fmt.Sprintf("change request %s has already been implemented", cr.Number)
+	}
+	if !cr.EndDate.IsZero() && now.After(cr.EndDate) {
+		return VerdictDenied, fmt.Sprintf("change request %s has expired at %s", cr.Number, cr.EndDate.Format(time.RFC3339))
+	}
+	if cr.State == StateImplement {
+		return VerdictAllowed, fmt.Sprintf("change request %s is in the Implement state", cr.Number)
+	}
+	return VerdictPending, fmt.Sprintf("change request %s is not in the Implement state yet", cr.Number)
+}
@@ -0,0 +1,72 @@
+package servicenow
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	testrequire "github.com/stretchr/testify/require"
+)
+
+func TestClient_GetChangeRequest(t *testing.T) {
+	var path, query, user string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path = r.URL.Path
+		query = r.URL.Query().Get("sysparm_query")
+		user, _, _ = r.BasicAuth()
+		_, _ = w.Write([]byte(`{"result":[{"number":"CHG0030001","state":"-1","approval":"approved","end_date":"2022-11-08 18:00:00"}]}`))
+	}))
+	defer server.Close()
+
+	c := NewClient(server.URL+"/", "bot", "secret")
+	changeRequest, err := c.GetChangeRequest(context.TODO(), "CHG0030001")
+
+	testrequire.Nil(t, err)
+	testrequire.Equal(t, "/api/now/table/change_request", path)
+	testrequire.Equal(t, "number=CHG0030001", query)
+	testrequire.Equal(t, "bot", user)
+	testrequire.Equal(t, &ChangeRequest{
+		Number:   "CHG0030001",
+		State:    StateImplement,
+		Approval: "approved",
+		EndDate:  time.Date(2022, 11, 8, 18, 0, 0, 0, time.UTC),
+	}, changeRequest)
+}
+
+func TestClient_GetChangeRequestNotFound(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"result":[]}`))
+	}))
+	defer server.Close()
+
+	changeRequest, err := NewClient(server.URL, "bot", "secret").GetChangeRequest(context.TODO(), "CHG0030001")
+	testrequire.Nil(t, err)
+	testrequire.Nil(t, changeRequest)
+}
+
+func TestChangeRequest_Check(t *testing.T) {
+	now := time.Date(2022, 11, 8, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name          string
+		changeRequest ChangeRequest
+		want          Verdict
+	}{
+		{"implement", ChangeRequest{State: StateImplement, Approval: "approved", EndDate: now.Add(time.Hour)}, VerdictAllowed},
+		{"implement without end date", ChangeRequest{State: StateImplement, Approval: "approved"}, VerdictAllowed},
+		{"scheduled", ChangeRequest{State: StateScheduled, Approval: "approved", EndDate: now.Add(time.Hour)}, VerdictPending},
+		{"authorize", ChangeRequest{State: StateAuthorize, Approval: "requested"}, VerdictPending},
+		{"rejected", ChangeRequest{State: StateNew, Approval: ApprovalRejected}, VerdictDenied},
+		{"canceled", ChangeRequest{State: StateCanceled, Approval: "approved"}, VerdictDenied},
+		{"closed", ChangeRequest{State: StateClosed, Approval: "approved"}, VerdictDenied},
+		{"expired", ChangeRequest{State: StateImplement, Approval: "approved", EndDate: now.Add(-time.Minute)}, VerdictDenied},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			verdict, reason := tt.changeRequest.Check(now)
+			testrequire.Equal(t, tt.want, verdict)
+			testrequire.NotEmpty(t, reason)
+		})
+	}
+}
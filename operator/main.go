@@ -24,13 +24,14 @@ import (
 	"net/http"
 	"time"
 
+	"github.com/google/uuid"
 	"github.com/kelseyhightower/envconfig"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials/insecure"
 
 	"github.com/keptn/lifecycle-controller/operator/controllers/keptnappversion"
 
-	"github.com/keptn/lifecycle-controller/operator/api/v1alpha1/common"
+	klcsemconv "github.com/keptn/lifecycle-controller/operator/api/v1alpha1/semconv"
 
 	"github.com/keptn/lifecycle-controller/operator/controllers/keptnworkload"
 	"github.com/keptn/lifecycle-controller/operator/controllers/keptnworkloadinstance"
@@ -38,10 +39,36 @@ import (
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
 
+	"github.com/keptn/lifecycle-controller/operator/controllers/evaluationpreset"
+	"github.com/keptn/lifecycle-controller/operator/controllers/imagewarmer"
 	"github.com/keptn/lifecycle-controller/operator/controllers/keptnapp"
+	"github.com/keptn/lifecycle-controller/operator/controllers/keptnappdrift"
+	"github.com/keptn/lifecycle-controller/operator/controllers/keptndefinitionsource"
 	"github.com/keptn/lifecycle-controller/operator/controllers/keptnevaluation"
+	"github.com/keptn/lifecycle-controller/operator/controllers/keptnevaluationprovider"
+	"github.com/keptn/lifecycle-controller/operator/controllers/keptnmetric"
+	"github.com/keptn/lifecycle-controller/operator/controllers/keptnnamespacestatus"
 	"github.com/keptn/lifecycle-controller/operator/controllers/keptntask"
 	"github.com/keptn/lifecycle-controller/operator/controllers/keptntaskdefinition"
+	"github.com/keptn/lifecycle-controller/operator/controllers/propagate"
+	"github.com/keptn/lifecycle-controller/operator/controllers/selfmonitoring"
+	"github.com/keptn/lifecycle-controller/operator/dashboard"
+	"github.com/keptn/lifecycle-controller/operator/features"
+	"github.com/keptn/lifecycle-controller/operator/integrations/cost"
+	"github.com/keptn/lifecycle-controller/operator/integrations/energy"
+	"github.com/keptn/lifecycle-controller/operator/integrations/eventbus"
+	"github.com/keptn/lifecycle-controller/operator/integrations/incident"
+	"github.com/keptn/lifecycle-controller/operator/integrations/jira"
+	"github.com/keptn/lifecycle-controller/operator/integrations/notification"
+	"github.com/keptn/lifecycle-controller/operator/integrations/servicenow"
+	"github.com/keptn/lifecycle-controller/operator/metrics"
+	"github.com/keptn/lifecycle-controller/operator/metricsadapter"
+	"github.com/keptn/lifecycle-controller/operator/migration"
+	"github.com/keptn/lifecycle-controller/operator/preflight"
+	"github.com/keptn/lifecycle-controller/operator/redaction"
+	"github.com/keptn/lifecycle-controller/operator/replay"
+	"github.com/keptn/lifecycle-controller/operator/settings"
+	"github.com/keptn/lifecycle-controller/operator/tracing"
 
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
@@ -49,13 +76,13 @@ import (
 	"go.opentelemetry.io/otel/sdk/resource"
 	"go.opentelemetry.io/otel/sdk/trace"
 	semconv "go.opentelemetry.io/otel/semconv/v1.4.0"
+	oteltrace "go.opentelemetry.io/otel/trace"
+	uberzap "go.uber.org/zap"
 
 	"os"
 
 	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
 	otelprom "go.opentelemetry.io/otel/exporters/prometheus"
-	"go.opentelemetry.io/otel/metric/instrument"
-	"go.opentelemetry.io/otel/metric/unit"
 	"go.opentelemetry.io/otel/sdk/metric"
 
 	"sigs.k8s.io/controller-runtime/pkg/webhook"
@@ -64,10 +91,17 @@ import (
 	// to ensure that exec-entrypoint and run can make use of them.
 	_ "k8s.io/client-go/plugin/pkg/client/auth"
 
+	corev1 "k8s.io/api/core/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/healthz"
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
 
@@ -88,152 +122,207 @@ var (
 func init() {
 	utilruntime.Must(clientgoscheme.AddToScheme(scheme))
 	utilruntime.Must(lifecyclev1alpha1.AddToScheme(scheme))
+	utilruntime.Must(apiextensionsv1.AddToScheme(scheme))
 	//+kubebuilder:scaffold:scheme
 }
 
 type envConfig struct {
-	OTelCollectorURL string `envconfig:"OTEL_COLLECTOR_URL" default:""`
+	OTelCollectorURL      string        `envconfig:"OTEL_COLLECTOR_URL" default:""`
+	IncidentProvider      string        `envconfig:"INCIDENT_PROVIDER" default:""`
+	IncidentAPIKey        string        `envconfig:"INCIDENT_API_KEY" default:""`
+	JiraURL               string        `envconfig:"JIRA_URL" default:""`
+	JiraUser              string        `envconfig:"JIRA_USER" default:""`
+	JiraAPIToken          string        `envconfig:"JIRA_API_TOKEN" default:""`
+	ServiceNowURL         string        `envconfig:"SERVICENOW_URL" default:""`
+	ServiceNowUser        string        `envconfig:"SERVICENOW_USER" default:""`
+	ServiceNowPassword    string        `envconfig:"SERVICENOW_PASSWORD" default:""`
+	TraceUIURL            string        `envconfig:"TRACE_UI_URL" default:""`
+	CostPrometheusURL     string        `envconfig:"COST_PROMETHEUS_URL" default:""`
+	MaxHourlyCostIncrease float64       `envconfig:"COST_MAX_HOURLY_INCREASE" default:"0"`
+	EnergyProvider        string        `envconfig:"ENERGY_PROVIDER" default:""`
+	EnergyPrometheusURL   string        `envconfig:"ENERGY_PROMETHEUS_URL" default:""`
+	ProviderProbeInterval time.Duration `envconfig:"PROVIDER_PROBE_INTERVAL" default:"1m"`
+	MetricsAttributeAudit string        `envconfig:"METRICS_ATTRIBUTE_AUDIT" default:"warn"`
+	EnvironmentLabel      string        `envconfig:"ENVIRONMENT_LABEL" default:"environment"`
+	SpanExportQueueSize   int           `envconfig:"SPAN_EXPORT_QUEUE_SIZE" default:"2048"`
+	SpanExportBatchSize   int           `envconfig:"SPAN_EXPORT_BATCH_SIZE" default:"512"`
+	SpanExportTimeout     time.Duration `envconfig:"SPAN_EXPORT_TIMEOUT" default:"30s"`
+	PodNamespace          string        `envconfig:"POD_NAMESPACE" default:"keptn-lifecycle-controller-system"`
+	KeptnConfigName       string        `envconfig:"KEPTN_CONFIG_NAME" default:"keptn-config"`
+	EventBusProvider      string        `envconfig:"EVENT_BUS_PROVIDER" default:""`
+	EventBusURL           string        `envconfig:"EVENT_BUS_URL" default:""`
+	EventBusTopic         string        `envconfig:"EVENT_BUS_TOPIC" default:"keptn.lifecycle"`
+	ImageWarmerHelper     string        `envconfig:"IMAGE_WARMER_HELPER_IMAGE" default:"busybox:1.36"`
+	ImageWarmerPause      string        `envconfig:"IMAGE_WARMER_PAUSE_IMAGE" default:"registry.k8s.io/pause:3.9"`
+	InternalDomains       []string      `envconfig:"OFFLINE_INTERNAL_DOMAINS" default:""`
+	ConfigStatusName      string        `envconfig:"OPERATOR_CONFIG_STATUS_NAME" default:"keptn-operator-config-status"`
+	PodServiceAccount     string        `envconfig:"POD_SERVICE_ACCOUNT" default:"klc-controller-manager"`
+	SchedulerAccount      string        `envconfig:"SCHEDULER_SERVICE_ACCOUNT" default:"keptn-scheduler"`
+	StatusEditors         []string      `envconfig:"STATUS_EDITORS" default:""`
+	RedactionKeys         []string      `envconfig:"REDACTION_KEYS" default:""`
+	ProviderAllowedHosts  []string      `envconfig:"PROVIDER_ALLOWED_HOSTS" default:""`
+	DriftCheckInterval    time.Duration `envconfig:"DRIFT_CHECK_INTERVAL" default:"1m"`
+	PropagatedLabels      []string      `envconfig:"PROPAGATED_LABELS" default:""`
+	PropagatedAnnotations []string      `envconfig:"PROPAGATED_ANNOTATIONS" default:""`
+	MaxRunningTaskJobs    int           `envconfig:"MAX_RUNNING_TASK_JOBS" default:"0"`
+	ReplaySince           time.Duration `envconfig:"REPLAY_SINCE" default:"0"`
+	StatusEventsLimit     int           `envconfig:"STATUS_EVENTS_LIMIT" default:"0"`
+	ReconcileTraceRatio   float64       `envconfig:"RECONCILE_TRACE_SAMPLE_RATIO" default:"0"`
+	GaugeSnapshotInterval time.Duration `envconfig:"GAUGE_SNAPSHOT_INTERVAL" default:"30s"`
+	GaugeSnapshotName     string        `envconfig:"GAUGE_SNAPSHOT_NAME" default:"keptn-gauge-snapshot"`
 }
 
 func main() {
-	var env envConfig
-	if err := envconfig.Process("", &env); err != nil {
-		log.Fatalf("Failed to process env var: %s", err)
-	}
+	var configPath string
 	var metricsAddr string
 	var enableLeaderElection bool
 	var disableWebhook bool
+	var disableTracing bool
+	var enableImageWarmer bool
+	var hibernate bool
+	var debugStatus bool
+	var offline bool
+	var preflightOnly bool
+	var migrateStorage bool
+	var migrateStorageOnly bool
+	var replayHistory bool
+	var importHistory bool
 	var probeAddr string
+	var dashboardAddr string
+	var metricsAdapterAddr string
+	var metricsAdapterCertDir string
+	featureGates := features.NewGates()
+	flag.StringVar(&configPath, "operator-config", "", "The config file setting flags and environment variables of the operator, e.g. mounted from a ConfigMap. Values set on the command line or in the environment take precedence.")
 	flag.StringVar(&metricsAddr, "metrics-bind-address", ":8080", "The address the metric endpoint binds to.")
 	flag.StringVar(&probeAddr, "health-probe-bind-address", ":8081", "The address the probe endpoint binds to.")
+	flag.StringVar(&dashboardAddr, "dashboard-bind-address", "", "The address the deployment timeline dashboard binds to. The dashboard is disabled if empty.")
+	flag.StringVar(&metricsAdapterAddr, "metrics-adapter-bind-address", "", "The address the custom metrics API serving the KeptnMetrics binds to. The adapter is disabled if empty.")
+	flag.StringVar(&metricsAdapterCertDir, "metrics-adapter-cert-dir", "/tmp/k8s-webhook-server/serving-certs", "The directory containing the tls.crt and tls.key the custom metrics API is served with.")
 
-	// OTEL SETUP
-	// The exporter embeds a default OpenTelemetry Reader and
-	// implements prometheus.Collector, allowing it to be used as
-	// both a Reader and Collector.
-
-	exporter := otelprom.New()
-	provider := metric.NewMeterProvider(metric.WithReader(exporter))
-	meter := provider.Meter("keptn/task")
-	deploymentCount, err := meter.SyncInt64().Counter("keptn.deployment.count", instrument.WithDescription("a simple counter for Keptn Deployments"))
-	if err != nil {
-		setupLog.Error(err, "unable to start OTel")
-	}
-	deploymentDuration, err := meter.SyncFloat64().Histogram("keptn.deployment.duration", instrument.WithDescription("a histogram of duration for Keptn Deployments"), instrument.WithUnit(unit.Unit("s")))
-	if err != nil {
-		setupLog.Error(err, "unable to start OTel")
-	}
-	deploymentActiveGauge, err := meter.AsyncInt64().Gauge("keptn.deployment.active", instrument.WithDescription("a gauge keeping track of the currently active Keptn Deployments"))
-	if err != nil {
-		setupLog.Error(err, "unable to start OTel")
-	}
-	taskCount, err := meter.SyncInt64().Counter("keptn.task.count", instrument.WithDescription("a simple counter for Keptn Tasks"))
-	if err != nil {
-		setupLog.Error(err, "unable to start OTel")
-	}
-	taskDuration, err := meter.SyncFloat64().Histogram("keptn.task.duration", instrument.WithDescription("a histogram of duration for Keptn Tasks"), instrument.WithUnit(unit.Unit("s")))
-	if err != nil {
-		setupLog.Error(err, "unable to start OTel")
-	}
-	taskActiveGauge, err := meter.AsyncInt64().Gauge("keptn.task.active", instrument.WithDescription("a simple counter of active Keptn Tasks"))
-	if err != nil {
-		setupLog.Error(err, "unable to start OTel")
-	}
-	appCount, err := meter.SyncInt64().Counter("keptn.app.count", instrument.WithDescription("a simple counter for Keptn Apps"))
-	if err != nil {
-		setupLog.Error(err, "unable to start OTel")
-	}
-	appDuration, err := meter.SyncFloat64().Histogram("keptn.app.duration", instrument.WithDescription("a histogram of duration for Keptn Apps"), instrument.WithUnit(unit.Unit("s")))
-	if err != nil {
-		setupLog.Error(err, "unable to start OTel")
+	// As recommended by the kubebuilder docs, webhook registration should be disabled if running locally. See https://book.kubebuilder.io/cronjob-tutorial/running.html#running-webhooks-locally for reference
+	flag.BoolVar(&disableWebhook, "disable-webhook", false, "Disable the registration of webhooks.")
+	flag.BoolVar(&disableTracing, "disable-tracing", false, "Disable tracing. No tracer provider is initialized and no spans are recorded or exported.")
+	flag.BoolVar(&enableImageWarmer, "enable-image-warmer", false, "Pre-pull the runner images of the task definitions on all nodes with a DaemonSet.")
+	flag.BoolVar(&hibernate, "hibernate-idle-namespaces", false, "Skip fetching KeptnMetrics and probing KeptnEvaluationProviders periodically in namespaces without active instances.")
+	flag.BoolVar(&debugStatus, "debug-status", false, "Record the last decision of the KeptnWorkloadInstance and KeptnAppVersion reconcilers in status.debug, e.g. to find out why a deployment is stuck.")
+	flag.BoolVar(&offline, "offline", false, "Refuse to start if external dependencies require internet access, e.g. in air-gapped clusters.")
+	flag.BoolVar(&preflightOnly, "preflight", false, "Check that all external dependencies are reachable, print a report and exit.")
+	flag.BoolVar(&migrateStorage, "migrate-storage", false, "Rewrite the stored Keptn resources to the storage version of their CRDs in the background.")
+	flag.BoolVar(&migrateStorageOnly, "migrate-storage-only", false, "Rewrite the stored Keptn resources to the storage version of their CRDs, print the progress and exit.")
+	flag.BoolVar(&replayHistory, "replay-history", false, "Re-emit the spans and metrics of completed KeptnAppVersions and KeptnWorkloadInstances with their original timestamps in the background, e.g. after switching the tracing backend.")
+	flag.BoolVar(&importHistory, "import-history", false, "Import the earlier revision of the Deployment of a newly adopted workload as a completed KeptnWorkloadInstance, so that its first deployment has a previous version.")
+	flag.Var(featureGates, "feature-gates", "A comma separated list of <feature>=<true|false> pairs enabling or disabling features that are in development, e.g. CanaryPhase=true.")
+	flag.BoolVar(&enableLeaderElection, "leader-elect", false,
+		"Enable leader election for controller manager. "+
+			"Enabling this will ensure there is only one active controller manager.")
+	opts := zap.Options{
+		Development: true,
 	}
-	appActiveGauge, err := meter.AsyncInt64().Gauge("keptn.app.active", instrument.WithDescription("a simple counter of active Keptn Apps"))
+	opts.BindFlags(flag.CommandLine)
+	flag.Parse()
+
+	configFile, err := settings.Read(configPath)
 	if err != nil {
-		setupLog.Error(err, "unable to start OTel")
+		log.Fatalf("Failed to read config file: %s", err)
 	}
-	evaluationCount, err := meter.SyncInt64().Counter("keptn.evaluation.count", instrument.WithDescription("a simple counter for Keptn Evaluations"))
+	effectiveConfig, err := configFile.Apply(flag.CommandLine)
 	if err != nil {
-		setupLog.Error(err, "unable to start OTel")
+		log.Fatalf("Failed to apply config file: %s", err)
 	}
-	evaluationDuration, err := meter.SyncFloat64().Histogram("keptn.evaluation.duration", instrument.WithDescription("a histogram of duration for Keptn Evaluations"), instrument.WithUnit(unit.Unit("s")))
-	if err != nil {
-		setupLog.Error(err, "unable to start OTel")
-	}
-	evaluationActiveGauge, err := meter.AsyncInt64().Gauge("keptn.evaluation.active", instrument.WithDescription("a simple counter of active Keptn Evaluations"))
-	if err != nil {
-		setupLog.Error(err, "unable to start OTel")
+	var env envConfig
+	if err := envconfig.Process("", &env); err != nil {
+		log.Fatalf("Failed to process env var: %s", err)
 	}
-	appDeploymentIntervalGauge, err := meter.AsyncFloat64().Gauge("keptn.app.deploymentinterval", instrument.WithDescription("a gauge of the interval between deployments"))
-	if err != nil {
-		setupLog.Error(err, "unable to start OTel")
+
+	logLevel, ok := opts.Level.(uberzap.AtomicLevel)
+	if !ok {
+		// the logger logs on debug level in development mode if no level is set
+		logLevel = uberzap.NewAtomicLevelAt(uberzap.DebugLevel)
+		opts.Level = logLevel
 	}
+	ctrl.SetLogger(zap.New(zap.UseFlagOptions(&opts)))
+	setupLog.Info("feature gates", "gates", featureGates.String())
 
-	appDeploymentDurationGauge, err := meter.AsyncFloat64().Gauge("keptn.app.deploymentduration", instrument.WithDescription("a gauge of the duration of deployments"))
-	if err != nil {
-		setupLog.Error(err, "unable to start OTel")
+	auditMetricsAttributes(env.MetricsAttributeAudit)
+	redactor := redaction.New(env.RedactionKeys)
+
+	if offline || preflightOnly {
+		report := checkExternalDependencies(env, offline, preflightOnly)
+		fmt.Print(report)
+		if preflightOnly {
+			if report.Failed() {
+				os.Exit(1)
+			}
+			os.Exit(0)
+		}
+		if report.Failed() {
+			setupLog.Error(fmt.Errorf("external dependencies require internet access"), "unable to start in offline mode")
+			os.Exit(1)
+		}
 	}
 
-	workloadDeploymentIntervalGauge, err := meter.AsyncFloat64().Gauge("keptn.deployment.deploymentinterval", instrument.WithDescription("a gauge of the interval between deployments"))
-	if err != nil {
-		setupLog.Error(err, "unable to start OTel")
+	if migrateStorageOnly {
+		os.Exit(migrateStoredVersions())
 	}
 
-	workloadDeploymentDurationGauge, err := meter.AsyncFloat64().Gauge("keptn.deployment.deploymentduration", instrument.WithDescription("a gauge of the duration of deployments"))
+	// OTEL SETUP
+	// The exporter embeds a default OpenTelemetry Reader and
+	// implements prometheus.Collector, allowing it to be used as
+	// both a Reader and Collector.
+
+	metricsConfig := loadMetricsConfig(env)
+	exporter := otelprom.New()
+	provider := metric.NewMeterProvider(metric.WithReader(exporter))
+	meter := provider.Meter("keptn/task")
+	meters, err := metrics.NewOTelMeters(meter, metricsConfig)
 	if err != nil {
 		setupLog.Error(err, "unable to start OTel")
-	}
-
-	meters := common.KeptnMeters{
-		TaskCount:          taskCount,
-		TaskDuration:       taskDuration,
-		DeploymentCount:    deploymentCount,
-		DeploymentDuration: deploymentDuration,
-		AppCount:           appCount,
-		AppDuration:        appDuration,
-		EvaluationCount:    evaluationCount,
-		EvaluationDuration: evaluationDuration,
+		os.Exit(1)
 	}
 
 	// Start the prometheus HTTP server and pass the exporter Collector to it
 	go serveMetrics(exporter.Collector)
 
-	// As recommended by the kubebuilder docs, webhook registration should be disabled if running locally. See https://book.kubebuilder.io/cronjob-tutorial/running.html#running-webhooks-locally for reference
-	flag.BoolVar(&disableWebhook, "disable-webhook", false, "Disable the registration of webhooks.")
-	flag.BoolVar(&enableLeaderElection, "leader-elect", false,
-		"Enable leader election for controller manager. "+
-			"Enabling this will ensure there is only one active controller manager.")
-	opts := zap.Options{
-		Development: true,
-	}
-	opts.BindFlags(flag.CommandLine)
-	flag.Parse()
+	if disableTracing {
+		// spans are neither recorded nor exported, while the trace context and baggage are still propagated
+		setupLog.Info("tracing is disabled")
+		otel.SetTracerProvider(oteltrace.NewNoopTracerProvider())
+	} else {
+		// Enabling OTel
+		tpOptions, err := getOTelTracerProviderOptions(env, meters, redactor)
+		if err != nil {
+			setupLog.Error(err, "unable to initialize OTel tracer options")
+		}
 
-	ctrl.SetLogger(zap.New(zap.UseFlagOptions(&opts)))
+		tp := trace.NewTracerProvider(tpOptions...)
 
-	// Enabling OTel
-	tpOptions, err := getOTelTracerProviderOptions(env)
-	if err != nil {
-		setupLog.Error(err, "unable to initialize OTel tracer options")
+		defer func() {
+			if err := tp.Shutdown(context.Background()); err != nil {
+				setupLog.Error(err, "unable to shutdown  OTel exporter")
+				os.Exit(1)
+			}
+		}()
+		otel.SetTracerProvider(tp)
 	}
-
-	tp := trace.NewTracerProvider(tpOptions...)
-
-	defer func() {
-		if err := tp.Shutdown(context.Background()); err != nil {
-			setupLog.Error(err, "unable to shutdown  OTel exporter")
-			os.Exit(1)
-		}
-	}()
-	otel.SetTracerProvider(tp)
 	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
 
-	mgr, err := ctrl.NewManager(ctrl.GetConfigOrDie(), ctrl.Options{
+	restConfig := ctrl.GetConfigOrDie()
+	if env.ReconcileTraceRatio > 0 && !disableTracing {
+		// the reconciliations of the controllers are traced separately from the deployments
+		selfmonitoring.SampleRatio = env.ReconcileTraceRatio
+		restConfig.Wrap(selfmonitoring.CountAPICalls)
+	}
+
+	mgr, err := ctrl.NewManager(restConfig, ctrl.Options{
 		Scheme:                 scheme,
 		MetricsBindAddress:     metricsAddr,
 		Port:                   9443,
 		HealthProbeBindAddress: probeAddr,
 		LeaderElection:         enableLeaderElection,
 		LeaderElectionID:       "6b866dd9.keptn.sh",
+		// only the labeled secrets of the evaluation providers are watched, other secrets are read from the API server
+		NewCache:              cache.BuilderWithOptions(cache.Options{SelectorsByObject: cache.SelectorsByObject{&corev1.Secret{}: {Label: keptnevaluationprovider.SecretSelector}}}),
+		ClientDisableCacheFor: []client.Object{&corev1.Secret{}},
 		// LeaderElectionReleaseOnCancel defines if the leader should step down voluntarily
 		// when the Manager ends. This requires the binary to immediately end when the
 		// Manager is stopped, otherwise, this setting is unsafe. Setting this significantly
@@ -251,22 +340,83 @@ func main() {
 		os.Exit(1)
 	}
 
+	eventPublisher, err := eventbus.NewPublisher(env.EventBusProvider, env.EventBusURL)
+	if err != nil {
+		setupLog.Error(err, "unable to set up event bus integration")
+		os.Exit(1)
+	}
+	recorderFor := mgr.GetEventRecorderFor
+	if eventPublisher != nil {
+		eventExporter := eventbus.NewExporter(eventPublisher, env.EventBusTopic, ctrl.Log.WithName("Event Exporter"))
+		if err = mgr.Add(eventExporter); err != nil {
+			setupLog.Error(err, "unable to set up event exporter")
+			os.Exit(1)
+		}
+		recorderFor = func(name string) record.EventRecorder {
+			return eventExporter.Recorder(name, mgr.GetEventRecorderFor(name))
+		}
+	}
+	notificationRouter := notification.NewRouter(mgr.GetClient(), env.PodNamespace, ctrl.Log.WithName("Notification Router"))
+	if err = mgr.Add(notificationRouter); err != nil {
+		setupLog.Error(err, "unable to set up notification router")
+		os.Exit(1)
+	}
+	exportingRecorderFor := recorderFor
+	recorderFor = func(name string) record.EventRecorder {
+		return notificationRouter.Recorder(name, exportingRecorderFor(name))
+	}
+
 	if !disableWebhook {
 		mgr.GetWebhookServer().Register("/mutate-v1-pod", &webhook.Admission{
 			Handler: &webhooks.PodMutatingWebhook{
-				Client:   mgr.GetClient(),
-				Tracer:   otel.Tracer("keptn/webhook"),
-				Recorder: mgr.GetEventRecorderFor("keptn/webhook"),
-				Log:      ctrl.Log.WithName("Mutating Webhook"),
+				Client:        mgr.GetClient(),
+				Tracer:        otel.Tracer("keptn/webhook"),
+				Recorder:      recorderFor("keptn/webhook"),
+				Log:           ctrl.Log.WithName("Mutating Webhook"),
+				ImportHistory: importHistory,
+			}})
+		mgr.GetWebhookServer().Register("/validate-lifecycle-keptn-sh-v1alpha1-keptnevaluationdefinition", &webhook.Admission{
+			Handler: &webhooks.EvaluationDefinitionValidatingWebhook{
+				Log: ctrl.Log.WithName("Evaluation Definition Validating Webhook"),
+			}})
+		mgr.GetWebhookServer().Register("/validate-lifecycle-keptn-sh-v1alpha1-keptnevaluationprovider", &webhook.Admission{
+			Handler: &webhooks.EvaluationProviderValidatingWebhook{
+				Reader:       mgr.GetAPIReader(),
+				AllowedHosts: env.ProviderAllowedHosts,
+				Log:          ctrl.Log.WithName("Evaluation Provider Validating Webhook"),
+			}})
+		schedulerUsername := ""
+		if env.SchedulerAccount != "" {
+			schedulerUsername = webhooks.ServiceAccountUsername(env.PodNamespace, env.SchedulerAccount)
+		}
+		mgr.GetWebhookServer().Register("/validate-lifecycle-keptn-sh-v1alpha1-status", &webhook.Admission{
+			Handler: &webhooks.StatusProtectionValidatingWebhook{
+				ServiceAccount:          webhooks.ServiceAccountUsername(env.PodNamespace, env.PodServiceAccount),
+				SchedulerServiceAccount: schedulerUsername,
+				Editors:                 env.StatusEditors,
+				Log:                     ctrl.Log.WithName("Status Protection Validating Webhook"),
+			}})
+		mgr.GetWebhookServer().Register("/validate-lifecycle-keptn-sh-v1alpha1-quota", &webhook.Admission{
+			Handler: &webhooks.QuotaValidatingWebhook{
+				Client: mgr.GetClient(),
+				Config: types.NamespacedName{Namespace: env.PodNamespace, Name: env.KeptnConfigName},
+				Log:    ctrl.Log.WithName("Quota Validating Webhook"),
 			}})
 	}
+
+	// the selected labels and annotations of the apps are copied to their app versions, workload instances, tasks and jobs
+	metadataPropagation := propagate.Policy{Labels: env.PropagatedLabels, Annotations: env.PropagatedAnnotations}
 	taskReconciler := &keptntask.KeptnTaskReconciler{
-		Client:   mgr.GetClient(),
-		Scheme:   mgr.GetScheme(),
-		Log:      ctrl.Log.WithName("KeptnTask Controller"),
-		Recorder: mgr.GetEventRecorderFor("keptntask-controller"),
-		Meters:   meters,
-		Tracer:   otel.Tracer("keptn/operator/task"),
+		Client:         mgr.GetClient(),
+		Scheme:         mgr.GetScheme(),
+		Log:            ctrl.Log.WithName("KeptnTask Controller"),
+		Recorder:       recorderFor("keptntask-controller"),
+		Meters:         meters,
+		Tracer:         otel.Tracer("keptn/operator/task"),
+		Propagation:    metadataPropagation,
+		MaxRunningJobs: env.MaxRunningTaskJobs,
+		APIReader:      mgr.GetAPIReader(),
+		Identity:       replicaIdentity(),
 	}
 	if err = (taskReconciler).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "KeptnTask")
@@ -277,19 +427,37 @@ func main() {
 		Client:   mgr.GetClient(),
 		Scheme:   mgr.GetScheme(),
 		Log:      ctrl.Log.WithName("KeptnTaskDefinition Controller"),
-		Recorder: mgr.GetEventRecorderFor("keptntaskdefinition-controller"),
+		Recorder: recorderFor("keptntaskdefinition-controller"),
 	}
 	if err = (taskDefinitionReconciler).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "KeptnTaskDefinition")
 		os.Exit(1)
 	}
 
+	if enableImageWarmer {
+		imageWarmerReconciler := &imagewarmer.ImageWarmerReconciler{
+			Client:      mgr.GetClient(),
+			Scheme:      mgr.GetScheme(),
+			Log:         ctrl.Log.WithName("Image Warmer Controller"),
+			Recorder:    recorderFor("imagewarmer-controller"),
+			Namespace:   env.PodNamespace,
+			HelperImage: env.ImageWarmerHelper,
+			PauseImage:  env.ImageWarmerPause,
+		}
+		if err = (imageWarmerReconciler).SetupWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create controller", "controller", "ImageWarmer")
+			os.Exit(1)
+		}
+	}
+
 	appReconciler := &keptnapp.KeptnAppReconciler{
-		Client:   mgr.GetClient(),
-		Scheme:   mgr.GetScheme(),
-		Log:      ctrl.Log.WithName("KeptnApp Controller"),
-		Recorder: mgr.GetEventRecorderFor("keptnapp-controller"),
-		Tracer:   otel.Tracer("keptn/operator/app"),
+		Client:           mgr.GetClient(),
+		Scheme:           mgr.GetScheme(),
+		Log:              ctrl.Log.WithName("KeptnApp Controller"),
+		Recorder:         recorderFor("keptnapp-controller"),
+		Tracer:           otel.Tracer("keptn/operator/app"),
+		EnvironmentLabel: env.EnvironmentLabel,
+		Propagation:      metadataPropagation,
 	}
 	if err = (appReconciler).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "KeptnApp")
@@ -297,11 +465,13 @@ func main() {
 	}
 
 	workloadReconciler := &keptnworkload.KeptnWorkloadReconciler{
-		Client:   mgr.GetClient(),
-		Scheme:   mgr.GetScheme(),
-		Log:      ctrl.Log.WithName("KeptnWorkload Controller"),
-		Recorder: mgr.GetEventRecorderFor("keptnworkload-controller"),
-		Tracer:   otel.Tracer("keptn/operator/workload"),
+		Client:           mgr.GetClient(),
+		Scheme:           mgr.GetScheme(),
+		Log:              ctrl.Log.WithName("KeptnWorkload Controller"),
+		Recorder:         recorderFor("keptnworkload-controller"),
+		Tracer:           otel.Tracer("keptn/operator/workload"),
+		EnvironmentLabel: env.EnvironmentLabel,
+		Propagation:      metadataPropagation,
 	}
 	if err = (workloadReconciler).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "KeptnWorkload")
@@ -309,125 +479,250 @@ func main() {
 	}
 
 	workloadInstanceReconciler := &keptnworkloadinstance.KeptnWorkloadInstanceReconciler{
-		Client:   mgr.GetClient(),
-		Scheme:   mgr.GetScheme(),
-		Log:      ctrl.Log.WithName("KeptnWorkloadInstance Controller"),
-		Recorder: mgr.GetEventRecorderFor("keptnworkloadinstance-controller"),
-		Meters:   meters,
-		Tracer:   otel.Tracer("keptn/operator/workloadinstance"),
+		Client:          mgr.GetClient(),
+		Scheme:          mgr.GetScheme(),
+		Log:             ctrl.Log.WithName("KeptnWorkloadInstance Controller"),
+		Recorder:        recorderFor("keptnworkloadinstance-controller"),
+		Meters:          meters,
+		Tracer:          otel.Tracer("keptn/operator/workloadinstance"),
+		DebugStatus:     debugStatus,
+		Propagation:     metadataPropagation,
+		RollbackEnabled: featureGates.Enabled(features.Rollback),
+	}
+	if env.JiraURL != "" {
+		workloadInstanceReconciler.IssueTracker = jira.NewClient(env.JiraURL, env.JiraUser, env.JiraAPIToken, env.TraceUIURL)
+	}
+	if env.ServiceNowURL != "" {
+		workloadInstanceReconciler.ChangeManager = servicenow.NewClient(env.ServiceNowURL, env.ServiceNowUser, env.ServiceNowPassword)
+	}
+	if env.CostPrometheusURL != "" {
+		costEstimator, err := cost.NewOpenCostEstimator(env.CostPrometheusURL)
+		if err != nil {
+			setupLog.Error(err, "unable to set up cost estimation")
+			os.Exit(1)
+		}
+		workloadInstanceReconciler.CostEstimator = costEstimator
+		workloadInstanceReconciler.MaxHourlyCostIncrease = env.MaxHourlyCostIncrease
 	}
+	energyMeter, err := energy.NewMeter(env.EnergyProvider, env.EnergyPrometheusURL)
+	if err != nil {
+		setupLog.Error(err, "unable to set up energy measurement")
+		os.Exit(1)
+	}
+	workloadInstanceReconciler.EnergyMeter = energyMeter
+	incidentManager, err := incident.NewManager(env.IncidentProvider, env.IncidentAPIKey)
+	if err != nil {
+		setupLog.Error(err, "unable to set up incident integration")
+		os.Exit(1)
+	}
+	workloadInstanceReconciler.IncidentManager = incidentManager
 	if err = (workloadInstanceReconciler).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "KeptnWorkloadInstance")
 		os.Exit(1)
 	}
 
 	appVersionReconciler := &keptnappversion.KeptnAppVersionReconciler{
-		Client:   mgr.GetClient(),
-		Scheme:   mgr.GetScheme(),
-		Log:      ctrl.Log.WithName("KeptnAppVersion Controller"),
-		Recorder: mgr.GetEventRecorderFor("keptnappversion-controller"),
-		Tracer:   otel.Tracer("keptn/operator/appversion"),
-		Meters:   meters,
+		Client:            mgr.GetClient(),
+		Scheme:            mgr.GetScheme(),
+		Log:               ctrl.Log.WithName("KeptnAppVersion Controller"),
+		Recorder:          recorderFor("keptnappversion-controller"),
+		Tracer:            otel.Tracer("keptn/operator/appversion"),
+		Meters:            meters,
+		IncidentManager:   incidentManager,
+		DebugStatus:       debugStatus,
+		Propagation:       metadataPropagation,
+		RollbackEnabled:   featureGates.Enabled(features.Rollback),
+		StatusEventsLimit: env.StatusEventsLimit,
 	}
 	if err = (appVersionReconciler).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "KeptnAppVersion")
 		os.Exit(1)
 	}
 
+	providerClients := keptnevaluationprovider.NewClientCache()
 	evaluationReconciler := &keptnevaluation.KeptnEvaluationReconciler{
-		Client:   mgr.GetClient(),
-		Scheme:   mgr.GetScheme(),
-		Log:      ctrl.Log.WithName("KeptnEvaluation Controller"),
-		Recorder: mgr.GetEventRecorderFor("keptnevaluation-controller"),
-		Tracer:   otel.Tracer("keptn/operator/evaluation"),
-		Meters:   meters,
+		Client:          mgr.GetClient(),
+		Scheme:          mgr.GetScheme(),
+		Log:             ctrl.Log.WithName("KeptnEvaluation Controller"),
+		Recorder:        recorderFor("keptnevaluation-controller"),
+		Tracer:          otel.Tracer("keptn/operator/evaluation"),
+		Meters:          meters,
+		ProviderClients: providerClients,
+		Redactor:        redactor,
 	}
 	if err = (evaluationReconciler).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "KeptnEvaluation")
 		os.Exit(1)
 	}
-	//+kubebuilder:scaffold:builder
+	evaluationProviderReconciler := &keptnevaluationprovider.KeptnEvaluationProviderReconciler{
+		Client:          mgr.GetClient(),
+		Scheme:          mgr.GetScheme(),
+		Log:             ctrl.Log.WithName("KeptnEvaluationProvider Controller"),
+		Recorder:        recorderFor("keptnevaluationprovider-controller"),
+		ProviderClients: providerClients,
+		ProbeInterval:   env.ProviderProbeInterval,
+		Hibernate:       hibernate,
+	}
+	if err = (evaluationProviderReconciler).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "KeptnEvaluationProvider")
+		os.Exit(1)
+	}
+	metricReconciler := &keptnmetric.KeptnMetricReconciler{
+		Client:          mgr.GetClient(),
+		Scheme:          mgr.GetScheme(),
+		Log:             ctrl.Log.WithName("KeptnMetric Controller"),
+		Recorder:        recorderFor("keptnmetric-controller"),
+		ProviderClients: providerClients,
+		Hibernate:       hibernate,
+	}
+	if err = (metricReconciler).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "KeptnMetric")
+		os.Exit(1)
+	}
 
-	err = meter.RegisterCallback(
-		[]instrument.Asynchronous{
-			deploymentActiveGauge,
-			taskActiveGauge,
-			appActiveGauge,
-			evaluationActiveGauge,
-			appDeploymentIntervalGauge,
-			appDeploymentDurationGauge,
-			workloadDeploymentIntervalGauge,
-			workloadDeploymentDurationGauge,
-		},
-		func(ctx context.Context) {
-			activeDeployments, err := workloadInstanceReconciler.GetActiveDeployments(ctx)
-			if err != nil {
-				setupLog.Error(err, "unable to gather active deployments")
-			}
-			for _, val := range activeDeployments {
-				deploymentActiveGauge.Observe(ctx, val.Value, val.Attributes...)
-			}
+	namespaceStatusReconciler := &keptnnamespacestatus.KeptnNamespaceStatusReconciler{
+		Client:             mgr.GetClient(),
+		APIReader:          mgr.GetAPIReader(),
+		Scheme:             mgr.GetScheme(),
+		Log:                ctrl.Log.WithName("KeptnNamespaceStatus Controller"),
+		SchedulerNamespace: env.PodNamespace,
+		Interval:           time.Minute,
+	}
+	if err = (namespaceStatusReconciler).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "KeptnNamespaceStatus")
+		os.Exit(1)
+	}
 
-			activeApps, err := appVersionReconciler.GetActiveApps(ctx)
-			if err != nil {
-				setupLog.Error(err, "unable to gather active apps")
-			}
-			for _, val := range activeApps {
-				appActiveGauge.Observe(ctx, val.Value, val.Attributes...)
-			}
+	definitionSourceReconciler := &keptndefinitionsource.KeptnDefinitionSourceReconciler{
+		Client:   mgr.GetClient(),
+		Scheme:   mgr.GetScheme(),
+		Log:      ctrl.Log.WithName("KeptnDefinitionSource Controller"),
+		Recorder: recorderFor("keptndefinitionsource-controller"),
+		Registry: &keptndefinitionsource.Registry{HTTPClient: &http.Client{Timeout: time.Minute}},
+	}
+	if err = (definitionSourceReconciler).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "KeptnDefinitionSource")
+		os.Exit(1)
+	}
 
-			activeTasks, err := taskReconciler.GetActiveTasks(ctx)
-			if err != nil {
-				setupLog.Error(err, "unable to gather active tasks")
-			}
-			for _, val := range activeTasks {
-				taskActiveGauge.Observe(ctx, val.Value, val.Attributes...)
-			}
+	evaluationPresetReconciler := &evaluationpreset.EvaluationPresetReconciler{
+		Client:   mgr.GetClient(),
+		Scheme:   mgr.GetScheme(),
+		Log:      ctrl.Log.WithName("EvaluationPreset Controller"),
+		Recorder: recorderFor("evaluationpreset-controller"),
+	}
+	if err = (evaluationPresetReconciler).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "EvaluationPreset")
+		os.Exit(1)
+	}
 
-			activeEvaluations, err := evaluationReconciler.GetActiveEvaluations(ctx)
-			if err != nil {
-				setupLog.Error(err, "unable to gather active evaluations")
-			}
-			for _, val := range activeEvaluations {
-				evaluationActiveGauge.Observe(ctx, val.Value, val.Attributes...)
-			}
+	appDriftReconciler := &keptnappdrift.KeptnAppDriftReconciler{
+		Client:   mgr.GetClient(),
+		Scheme:   mgr.GetScheme(),
+		Log:      ctrl.Log.WithName("KeptnApp Drift Controller"),
+		Recorder: recorderFor("keptnappdrift-controller"),
+		Interval: env.DriftCheckInterval,
+	}
+	if err = (appDriftReconciler).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "KeptnAppDrift")
+		os.Exit(1)
+	}
+	//+kubebuilder:scaffold:builder
 
-			appDeploymentInterval, err := appVersionReconciler.GetDeploymentInterval(ctx)
-			if err != nil {
-				setupLog.Error(err, "unable to gather app deployment intervals")
-			}
-			for _, val := range appDeploymentInterval {
-				appDeploymentIntervalGauge.Observe(ctx, val.Value, val.Attributes...)
-			}
+	if dashboardAddr != "" {
+		if err = mgr.Add(&dashboard.Server{
+			Client:      mgr.GetClient(),
+			Log:         ctrl.Log.WithName("Dashboard"),
+			BindAddress: dashboardAddr,
+		}); err != nil {
+			setupLog.Error(err, "unable to set up dashboard")
+			os.Exit(1)
+		}
+	}
 
-			appDeploymentDuration, err := appVersionReconciler.GetDeploymentDuration(ctx)
-			if err != nil {
-				setupLog.Error(err, "unable to gather app deployment durations")
-			}
-			for _, val := range appDeploymentDuration {
-				appDeploymentDurationGauge.Observe(ctx, val.Value, val.Attributes...)
-			}
+	if metricsAdapterAddr != "" {
+		if err = mgr.Add(&metricsadapter.Server{
+			Client:      mgr.GetClient(),
+			Log:         ctrl.Log.WithName("Metrics Adapter"),
+			BindAddress: metricsAdapterAddr,
+			CertDir:     metricsAdapterCertDir,
+		}); err != nil {
+			setupLog.Error(err, "unable to set up metrics adapter")
+			os.Exit(1)
+		}
+	}
 
-			workloadDeploymentInterval, err := workloadInstanceReconciler.GetDeploymentInterval(ctx)
-			if err != nil {
-				setupLog.Error(err, "unable to gather workload deployment intervals")
-			}
-			for _, val := range workloadDeploymentInterval {
-				workloadDeploymentIntervalGauge.Observe(ctx, val.Value, val.Attributes...)
-			}
+	if configPath != "" {
+		if err = mgr.Add(&settings.Reloader{
+			Reader:   mgr.GetAPIReader(),
+			Writer:   mgr.GetClient(),
+			Log:      ctrl.Log.WithName("Config Reloader"),
+			Path:     configPath,
+			Interval: 30 * time.Second,
+			Status:   types.NamespacedName{Namespace: env.PodNamespace, Name: env.ConfigStatusName},
+			Live: map[string]func(string) error{
+				"zap-log-level": func(value string) error {
+					return setLogLevel(logLevel, value)
+				},
+			},
+			Applied:   configFile,
+			Effective: effectiveConfig,
+		}); err != nil {
+			setupLog.Error(err, "unable to set up config reloader")
+			os.Exit(1)
+		}
+	}
 
-			workloadDeploymentDuration, err := workloadInstanceReconciler.GetDeploymentDuration(ctx)
-			if err != nil {
-				setupLog.Error(err, "unable to gather workload deployment durations")
-			}
-			for _, val := range workloadDeploymentDuration {
-				workloadDeploymentDurationGauge.Observe(ctx, val.Value, val.Attributes...)
-			}
+	if migrateStorage {
+		if err = mgr.Add(&migration.Migrator{
+			Reader:   mgr.GetAPIReader(),
+			Writer:   mgr.GetClient(),
+			Log:      ctrl.Log.WithName("Storage Migration"),
+			Recorder: recorderFor("storage-migration"),
+			Group:    lifecyclev1alpha1.GroupVersion.Group,
+		}); err != nil {
+			setupLog.Error(err, "unable to set up storage migration")
+			os.Exit(1)
+		}
+	}
 
-		})
-	if err != nil {
-		fmt.Println("Failed to register callback")
-		panic(err)
+	if replayHistory {
+		replayer := &replay.Replayer{
+			Reader: mgr.GetAPIReader(),
+			Tracer: otel.Tracer("keptn/operator/replay"),
+			Meters: meters,
+			Log:    ctrl.Log.WithName("Replay"),
+		}
+		if env.ReplaySince > 0 {
+			replayer.Since = time.Now().Add(-env.ReplaySince)
+		}
+		if err = mgr.Add(replayer); err != nil {
+			setupLog.Error(err, "unable to set up replay")
+			os.Exit(1)
+		}
+	}
+
+	gauges := &metrics.Gauges{
+		Client: mgr.GetClient(),
+		Log:    ctrl.Log.WithName("Metrics"),
+		Config: metricsConfig,
+	}
+	if env.GaugeSnapshotInterval > 0 {
+		gauges.Snapshot = &metrics.GaugeSnapshot{
+			Reader:    mgr.GetAPIReader(),
+			Writer:    mgr.GetClient(),
+			Log:       ctrl.Log.WithName("Metrics"),
+			ConfigMap: types.NamespacedName{Namespace: env.PodNamespace, Name: env.GaugeSnapshotName},
+			Interval:  env.GaugeSnapshotInterval,
+		}
+		if err = mgr.Add(gauges.Snapshot); err != nil {
+			setupLog.Error(err, "unable to set up gauge snapshot")
+			os.Exit(1)
+		}
+	}
+	if err = gauges.Register(meter); err != nil {
+		setupLog.Error(err, "unable to register gauges")
+		os.Exit(1)
 	}
 
 	if err := mgr.AddHealthzCheck("healthz", healthz.Ping); err != nil {
@@ -447,14 +742,123 @@ func main() {
 	}
 }
 
-func getOTelTracerProviderOptions(env envConfig) ([]trace.TracerProviderOption, error) {
+// checkExternalDependencies validates the dependencies configured for the operator and in the cluster
+func checkExternalDependencies(env envConfig, offline bool, checkReachability bool) preflight.Report {
+	dependencies := []preflight.Dependency{}
+	if env.OTelCollectorURL != "" {
+		dependencies = append(dependencies, preflight.Dependency{Name: "OTel collector", Type: preflight.TypeEndpoint, Address: env.OTelCollectorURL})
+	}
+	switch env.IncidentProvider {
+	case incident.ProviderPagerDuty:
+		dependencies = append(dependencies, preflight.Dependency{Name: "incident provider", Type: preflight.TypeURL, Address: incident.PagerDutyEventsURL})
+	case incident.ProviderOpsgenie:
+		dependencies = append(dependencies, preflight.Dependency{Name: "incident provider", Type: preflight.TypeURL, Address: incident.OpsgenieAlertsURL})
+	}
+	urls := []struct{ name, url string }{
+		{"Jira", env.JiraURL},
+		{"ServiceNow", env.ServiceNowURL},
+		{"cost Prometheus", env.CostPrometheusURL},
+		{"energy Prometheus", env.EnergyPrometheusURL},
+		{"event bus", env.EventBusURL},
+	}
+	for _, u := range urls {
+		if u.url != "" {
+			dependencies = append(dependencies, preflight.Dependency{Name: u.name, Type: preflight.TypeURL, Address: u.url})
+		}
+	}
+	if image := os.Getenv("FUNCTION_RUNNER_IMAGE"); image != "" {
+		dependencies = append(dependencies, preflight.Dependency{Name: "function runner", Type: preflight.TypeImage, Address: image})
+	}
+	if image := os.Getenv("PYTHON_RUNNER_IMAGE"); image != "" {
+		dependencies = append(dependencies, preflight.Dependency{Name: "Python runner", Type: preflight.TypeImage, Address: image})
+	}
+
+	c, err := client.New(ctrl.GetConfigOrDie(), client.Options{Scheme: scheme})
+	if err != nil {
+		setupLog.Error(err, "unable to create client to read the dependencies configured in the cluster")
+	} else if clusterDependencies, err := preflight.ClusterDependencies(context.Background(), c); err != nil {
+		setupLog.Error(err, "unable to read the dependencies configured in the cluster")
+	} else {
+		dependencies = append(dependencies, clusterDependencies...)
+	}
+
+	checker := &preflight.Checker{
+		Offline:           offline,
+		CheckReachability: checkReachability,
+		InternalDomains:   env.InternalDomains,
+	}
+	return checker.Check(context.Background(), dependencies)
+}
+
+// replicaIdentity returns the identity of this replica of the operator, which holds the Leases of exactly-once tasks.
+// Like the identity used for the leader election, it is unique even if a restarted pod keeps its hostname.
+func replicaIdentity() string {
+	hostname, err := os.Hostname()
+	if err != nil {
+		setupLog.Error(err, "could not determine hostname")
+	}
+	return hostname + "_" + uuid.New().String()
+}
+
+//+kubebuilder:rbac:groups=lifecycle.keptn.sh,resources=keptnconfigs,verbs=get;list;watch
+
+// loadMetricsConfig reads the configuration of the instruments from the KeptnConfig in the namespace of the operator.
+// The instruments are created at startup, so changes of the KeptnConfig take effect after a restart.
+func loadMetricsConfig(env envConfig) metrics.Config {
+	c, err := client.New(ctrl.GetConfigOrDie(), client.Options{Scheme: scheme})
+	if err != nil {
+		setupLog.Error(err, "unable to create client to read KeptnConfig")
+		return metrics.Config{}
+	}
+	config := &lifecyclev1alpha1.KeptnConfig{}
+	err = c.Get(context.Background(), types.NamespacedName{Namespace: env.PodNamespace, Name: env.KeptnConfigName}, config)
+	if errors.IsNotFound(err) {
+		return metrics.Config{}
+	} else if err != nil {
+		setupLog.Error(err, "unable to read KeptnConfig, using the default metrics configuration")
+		return metrics.Config{}
+	}
+	return metrics.NewConfig(*config)
+}
+
+// migrateStoredVersions migrates the stored Keptn resources without starting the manager and returns the exit code
+func migrateStoredVersions() int {
+	k8sClient, err := client.New(ctrl.GetConfigOrDie(), client.Options{Scheme: scheme})
+	if err != nil {
+		setupLog.Error(err, "unable to create client")
+		return 1
+	}
+	migrator := &migration.Migrator{
+		Reader: k8sClient,
+		Writer: k8sClient,
+		Log:    ctrl.Log.WithName("Storage Migration"),
+		Group:  lifecyclev1alpha1.GroupVersion.Group,
+	}
+	progress, err := migrator.Run(context.Background())
+	for _, p := range progress {
+		fmt.Println(p.String())
+	}
+	if err != nil {
+		setupLog.Error(err, "storage migration failed")
+		return 1
+	}
+	return 0
+}
+
+func getOTelTracerProviderOptions(env envConfig, meters metrics.Meters, redactor *redaction.Redactor) ([]trace.TracerProviderOption, error) {
 	tracerProviderOptions := []trace.TracerProviderOption{}
+	// spans are exported from a bounded queue, so that a slow collector does not block the reconcilers
+	exportOptions := tracing.Options{
+		QueueSize:     env.SpanExportQueueSize,
+		BatchSize:     env.SpanExportBatchSize,
+		ExportTimeout: env.SpanExportTimeout,
+	}
 
 	stdOutExp, err := newStdOutExporter()
 	if err != nil {
 		return nil, fmt.Errorf("could not create stdout OTel exporter: %w", err)
 	}
-	tracerProviderOptions = append(tracerProviderOptions, trace.WithBatcher(stdOutExp))
+	tracerProviderOptions = append(tracerProviderOptions, trace.WithSpanProcessor(tracing.NewBufferedSpanProcessor("stdout", tracing.NewRedactingExporter(stdOutExp, redactor), meters, exportOptions)))
 
 	if env.OTelCollectorURL != "" {
 		// try to set OTel exporter for Jaeger
@@ -463,7 +867,7 @@ func getOTelTracerProviderOptions(env envConfig) ([]trace.TracerProviderOption,
 			// log the error, but do not break if Jaeger exporter cannot be created
 			setupLog.Error(err, "Could not set up OTel exporter")
 		} else if otelExporter != nil {
-			tracerProviderOptions = append(tracerProviderOptions, trace.WithBatcher(otelExporter))
+			tracerProviderOptions = append(tracerProviderOptions, trace.WithSpanProcessor(tracing.NewBufferedSpanProcessor("otlp", tracing.NewRedactingExporter(otelExporter, redactor), meters, exportOptions)))
 		}
 	}
 	tracerProviderOptions = append(tracerProviderOptions, trace.WithResource(newResource()))
@@ -520,3 +924,35 @@ func serveMetrics(collector prometheus.Collector) {
 		return
 	}
 }
+
+// setLogLevel changes the level of the logger to a value of the --zap-log-level flag, e.g. info or 2
+func setLogLevel(level uberzap.AtomicLevel, value string) error {
+	opts := zap.Options{}
+	fs := flag.NewFlagSet("zap", flag.ContinueOnError)
+	opts.BindFlags(fs)
+	if err := fs.Set("zap-log-level", value); err != nil {
+		return err
+	}
+	newLevel, ok := opts.Level.(uberzap.AtomicLevel)
+	if !ok {
+		return fmt.Errorf("unsupported log level %s", value)
+	}
+	level.SetLevel(newLevel.Level())
+	return nil
+}
+
+// auditMetricsAttributes logs the inconsistencies between the attributes of the metrics of the different resources.
+// In mode "fail", the operator does not start if there are any, in mode "off" the audit is skipped.
+func auditMetricsAttributes(mode string) {
+	if mode == "off" {
+		return
+	}
+	findings := klcsemconv.AuditMetricsAttributes()
+	for _, finding := range findings {
+		setupLog.Info("inconsistent metrics attributes", "rule", finding.Rule, "source", finding.Source, "dimension", finding.Dimension, "keys", finding.Keys)
+	}
+	if mode == "fail" && len(findings) > 0 {
+		setupLog.Error(fmt.Errorf("found %d inconsistencies in the metrics attributes", len(findings)), "metrics attribute audit failed")
+		os.Exit(1)
+	}
+}
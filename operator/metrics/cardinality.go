@@ -0,0 +1,106 @@
+package metrics
+
+import (
+	"regexp"
+	"sync"
+
+	klcv1alpha1 "github.com/keptn/lifecycle-controller/operator/api/v1alpha1"
+	"github.com/keptn/lifecycle-controller/operator/api/v1alpha1/common"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// OtherValue is recorded instead of the values of an attribute exceeding the maximum number of values
+const OtherValue = "other"
+
+const shortCommitSHALength = 7
+
+var commitSHA = regexp.MustCompile(`^[0-9a-f]{40}$`)
+
+// defaultGuardedAttributes are the attributes whose values usually change with every deployment
+var defaultGuardedAttributes = []attribute.Key{
+	common.AppVersion,
+	common.AppPreviousVersion,
+	common.WorkloadVersion,
+	common.WorkloadPreviousVersion,
+}
+
+// CardinalityGuard limits and normalizes the values of high-cardinality attributes, so that recording the metrics of
+// each deployment does not create an unbounded number of series in the metrics backend.
+// A nil CardinalityGuard leaves all attributes unchanged.
+type CardinalityGuard struct {
+	attributes        map[attribute.Key]bool
+	maxValueLength    int
+	shortenCommitSHAs bool
+	maxValues         int
+
+	mtx    sync.Mutex
+	values map[attribute.Key]map[string]bool
+}
+
+// NewCardinalityGuard creates a guard with the given limits, returning nil if no limits are configured
+func NewCardinalityGuard(config klcv1alpha1.KeptnConfigCardinality) *CardinalityGuard {
+	if config.MaxValueLength == 0 && !config.ShortenCommitSHAs && config.MaxValuesPerAttribute == 0 {
+		return nil
+	}
+	g := &CardinalityGuard{
+		attributes:        map[attribute.Key]bool{},
+		maxValueLength:    config.MaxValueLength,
+		shortenCommitSHAs: config.ShortenCommitSHAs,
+		maxValues:         config.MaxValuesPerAttribute,
+		values:            map[attribute.Key]map[string]bool{},
+	}
+	for _, key := range defaultGuardedAttributes {
+		g.attributes[key] = len(config.Attributes) == 0
+	}
+	for _, key := range config.Attributes {
+		g.attributes[attribute.Key(key)] = true
+	}
+	return g
+}
+
+// Apply returns the attributes with the values of the guarded attributes normalized and limited
+func (g *CardinalityGuard) Apply(attrs []attribute.KeyValue) []attribute.KeyValue {
+	if g == nil {
+		return attrs
+	}
+	res := make([]attribute.KeyValue, 0, len(attrs))
+	for _, kv := range attrs {
+		if g.attributes[kv.Key] && kv.Value.Type() == attribute.STRING {
+			kv = kv.Key.String(g.limit(kv.Key, g.normalize(kv.Value.AsString())))
+		}
+		res = append(res, kv)
+	}
+	return res
+}
+
+func (g *CardinalityGuard) normalize(value string) string {
+	if g.shortenCommitSHAs && commitSHA.MatchString(value) {
+		value = value[:shortCommitSHALength]
+	}
+	if g.maxValueLength > 0 && len(value) > g.maxValueLength {
+		value = value[:g.maxValueLength]
+	}
+	return value
+}
+
+// limit returns the value if it has already been recorded or the maximum number of values is not reached yet
+func (g *CardinalityGuard) limit(key attribute.Key, value string) string {
+	if g.maxValues == 0 || value == "" {
+		return value
+	}
+	g.mtx.Lock()
+	defer g.mtx.Unlock()
+	values, ok := g.values[key]
+	if !ok {
+		values = map[string]bool{}
+		g.values[key] = values
+	}
+	if values[value] {
+		return value
+	}
+	if len(values) >= g.maxValues {
+		return OtherValue
+	}
+	values[value] = true
+	return value
+}
@@ -0,0 +1,37 @@
+package metrics
+
+import (
+	"testing"
+
+	klcv1alpha1 "github.com/keptn/lifecycle-controller/operator/api/v1alpha1"
+	"github.com/keptn/lifecycle-controller/operator/api/v1alpha1/common"
+	testrequire "github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+func TestCardinalityGuard_Apply(t *testing.T) {
+	g := NewCardinalityGuard(klcv1alpha1.KeptnConfigCardinality{
+		MaxValueLength:        12,
+		ShortenCommitSHAs:     true,
+		MaxValuesPerAttribute: 2,
+	})
+
+	attrs := g.Apply([]attribute.KeyValue{
+		common.AppName.String("my-app-with-a-long-name"),
+		common.AppVersion.String("0123456789abcdef0123456789abcdef01234567"),
+	})
+	testrequire.Equal(t, "my-app-with-a-long-name", attrs[0].Value.AsString())
+	testrequire.Equal(t, "0123456", attrs[1].Value.AsString())
+
+	testrequire.Equal(t, "1.0.0-rc.1+b", g.Apply([]attribute.KeyValue{common.AppVersion.String("1.0.0-rc.1+build.123")})[0].Value.AsString())
+	testrequire.Equal(t, OtherValue, g.Apply([]attribute.KeyValue{common.AppVersion.String("2.0.0")})[0].Value.AsString())
+	testrequire.Equal(t, "0123456", g.Apply([]attribute.KeyValue{common.AppVersion.String("0123456")})[0].Value.AsString())
+}
+
+func TestCardinalityGuard_Disabled(t *testing.T) {
+	g := NewCardinalityGuard(klcv1alpha1.KeptnConfigCardinality{})
+	testrequire.Nil(t, g)
+
+	attrs := g.Apply([]attribute.KeyValue{common.AppVersion.String("0123456789abcdef0123456789abcdef01234567")})
+	testrequire.Equal(t, "0123456789abcdef0123456789abcdef01234567", attrs[0].Value.AsString())
+}
@@ -0,0 +1,40 @@
+package metrics
+
+import klcv1alpha1 "github.com/keptn/lifecycle-controller/operator/api/v1alpha1"
+
+// Config configures the names of the instruments and which of them are recorded
+type Config struct {
+	// Prefix is prepended to the names of all instruments
+	Prefix string
+	// Disabled contains the names of the instruments that are not recorded, without the prefix
+	Disabled []string
+	// Guard limits the values of high-cardinality attributes of all instruments
+	Guard *CardinalityGuard
+}
+
+// NewConfig returns the configuration of the instruments of the given KeptnConfig
+func NewConfig(config klcv1alpha1.KeptnConfig) Config {
+	return Config{
+		Prefix:   config.Spec.Metrics.Prefix,
+		Disabled: config.Spec.Metrics.DisabledInstruments,
+		Guard:    NewCardinalityGuard(config.Spec.Metrics.Cardinality),
+	}
+}
+
+// Name returns the name the instrument is created with
+func (c Config) Name(instrument string) string {
+	if c.Prefix == "" {
+		return instrument
+	}
+	return c.Prefix + "." + instrument
+}
+
+// Enabled returns whether the instrument is recorded
+func (c Config) Enabled(instrument string) bool {
+	for _, disabled := range c.Disabled {
+		if disabled == instrument {
+			return false
+		}
+	}
+	return true
+}
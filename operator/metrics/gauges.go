@@ -0,0 +1,375 @@
+package metrics
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/go-logr/logr"
+	klcv1alpha1 "github.com/keptn/lifecycle-controller/operator/api/v1alpha1"
+	"github.com/keptn/lifecycle-controller/operator/api/v1alpha1/common"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/metric/instrument"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+type GaugeValue struct {
+	Value      int64
+	Attributes []attribute.KeyValue
+}
+
+type GaugeFloatValue struct {
+	Value      float64
+	Attributes []attribute.KeyValue
+}
+
+// Gauges observes the state of the Keptn resources in the cluster for the asynchronous gauges
+type Gauges struct {
+	Client client.Reader
+	Log    logr.Logger
+	Config Config
+	// Snapshot persists the values of the gauges of the active apps and deployments, which are reported while the
+	// cache has not synced. The values are not persisted if it is nil.
+	Snapshot *GaugeSnapshot
+}
+
+type intGauge struct {
+	name        string
+	description string
+	observe     func(ctx context.Context) ([]GaugeValue, error)
+}
+
+type floatGauge struct {
+	name        string
+	description string
+	observe     func(ctx context.Context) ([]GaugeFloatValue, error)
+}
+
+//+kubebuilder:rbac:groups=lifecycle.keptn.sh,resources=keptnapps;keptnappversions;keptnworkloadinstances;keptntasks;keptnevaluations;keptnevaluationproviders,verbs=get;list;watch
+
+// Register creates the asynchronous gauges on the meter and observes them on every collection
+func (g *Gauges) Register(meter metric.Meter) error {
+	intGauges := []intGauge{
+		{name: "keptn.deployment.active", description: "a gauge keeping track of the currently active Keptn Deployments", observe: g.ActiveDeployments},
+		{name: "keptn.task.active", description: "a simple counter of active Keptn Tasks", observe: g.ActiveTasks},
+		{name: "keptn.app.active", description: "a simple counter of active Keptn Apps", observe: g.ActiveApps},
+		{name: "keptn.evaluation.active", description: "a simple counter of active Keptn Evaluations", observe: g.ActiveEvaluations},
+		{name: "keptn.evaluationprovider.available", description: "a gauge indicating whether the last connectivity probe of Keptn Evaluation Providers succeeded", observe: g.ProviderAvailability},
+		{name: "keptn.app.drift", description: "a gauge indicating whether the running versions of the workloads of Keptn Apps differ from their latest deployment", observe: g.AppDrift},
+	}
+	floatGauges := []floatGauge{
+		{name: "keptn.app.deploymentinterval", description: "a gauge of the interval between deployments", observe: g.AppDeploymentIntervals},
+		{name: "keptn.app.deploymentduration", description: "a gauge of the duration of deployments", observe: g.AppDeploymentDurations},
+		{name: "keptn.deployment.deploymentinterval", description: "a gauge of the interval between deployments", observe: g.WorkloadDeploymentIntervals},
+		{name: "keptn.deployment.deploymentduration", description: "a gauge of the duration of deployments", observe: g.WorkloadDeploymentDurations},
+	}
+
+	instruments := []instrument.Asynchronous{}
+	callbacks := []func(ctx context.Context){}
+	for _, gauge := range intGauges {
+		gauge := gauge
+		if !g.Config.Enabled(gauge.name) {
+			continue
+		}
+		otelGauge, err := meter.AsyncInt64().Gauge(g.Config.Name(gauge.name), instrument.WithDescription(gauge.description))
+		if err != nil {
+			return fmt.Errorf("could not create gauge %s: %w", gauge.name, err)
+		}
+		observe := gauge.observe
+		if g.Snapshot != nil {
+			observe = g.Snapshot.track(gauge.name, gauge.observe)
+		}
+		instruments = append(instruments, otelGauge)
+		callbacks = append(callbacks, func(ctx context.Context) {
+			values, err := observe(ctx)
+			if err != nil {
+				g.Log.Error(err, "unable to gather "+gauge.name)
+			}
+			for _, val := range values {
+				otelGauge.Observe(ctx, val.Value, g.Config.Guard.Apply(val.Attributes)...)
+			}
+		})
+	}
+	for _, gauge := range floatGauges {
+		gauge := gauge
+		if !g.Config.Enabled(gauge.name) {
+			continue
+		}
+		otelGauge, err := meter.AsyncFloat64().Gauge(g.Config.Name(gauge.name), instrument.WithDescription(gauge.description))
+		if err != nil {
+			return fmt.Errorf("could not create gauge %s: %w", gauge.name, err)
+		}
+		instruments = append(instruments, otelGauge)
+		callbacks = append(callbacks, func(ctx context.Context) {
+			values, err := gauge.observe(ctx)
+			if err != nil {
+				g.Log.Error(err, "unable to gather "+gauge.name)
+			}
+			for _, val := range values {
+				otelGauge.Observe(ctx, val.Value, g.Config.Guard.Apply(val.Attributes)...)
+			}
+		})
+	}
+
+	// the gauges list all resources on every collection, so the callback is not registered if all of them are disabled
+	if len(instruments) == 0 {
+		return nil
+	}
+	if err := meter.RegisterCallback(instruments, func(ctx context.Context) {
+		for _, callback := range callbacks {
+			callback(ctx)
+		}
+	}); err != nil {
+		return fmt.Errorf("could not register gauge callback: %w", err)
+	}
+	return nil
+}
+
+func (g *Gauges) ActiveApps(ctx context.Context) ([]GaugeValue, error) {
+	appInstances := &klcv1alpha1.KeptnAppVersionList{}
+	err := g.Client.List(ctx, appInstances)
+	if err != nil {
+		return nil, fmt.Errorf("could not retrieve app versions: %w", err)
+	}
+
+	res := []GaugeValue{}
+
+	for _, appInstance := range appInstances.Items {
+		gaugeValue := int64(0)
+		if !appInstance.IsEndTimeSet() {
+			gaugeValue = int64(1)
+		}
+		res = append(res, GaugeValue{
+			Value:      gaugeValue,
+			Attributes: appInstance.GetActiveMetricsAttributes(),
+		})
+	}
+
+	return res, nil
+}
+
+func (g *Gauges) ActiveDeployments(ctx context.Context) ([]GaugeValue, error) {
+	workloadInstances := &klcv1alpha1.KeptnWorkloadInstanceList{}
+	err := g.Client.List(ctx, workloadInstances)
+	if err != nil {
+		return nil, fmt.Errorf("could not retrieve workload instances: %w", err)
+	}
+
+	res := []GaugeValue{}
+
+	for _, workloadInstance := range workloadInstances.Items {
+		gaugeValue := int64(0)
+		if !workloadInstance.IsEndTimeSet() {
+			gaugeValue = int64(1)
+		}
+		res = append(res, GaugeValue{
+			Value:      gaugeValue,
+			Attributes: workloadInstance.GetActiveMetricsAttributes(),
+		})
+	}
+
+	return res, nil
+}
+
+func (g *Gauges) ActiveTasks(ctx context.Context) ([]GaugeValue, error) {
+	tasks := &klcv1alpha1.KeptnTaskList{}
+	err := g.Client.List(ctx, tasks)
+	if err != nil {
+		return nil, fmt.Errorf("could not retrieve tasks: %w", err)
+	}
+
+	res := []GaugeValue{}
+
+	for _, task := range tasks.Items {
+		gaugeValue := int64(0)
+		if !task.IsEndTimeSet() {
+			gaugeValue = int64(1)
+		}
+		res = append(res, GaugeValue{
+			Value:      gaugeValue,
+			Attributes: task.GetActiveMetricsAttributes(),
+		})
+	}
+
+	return res, nil
+}
+
+func (g *Gauges) ActiveEvaluations(ctx context.Context) ([]GaugeValue, error) {
+	evaluations := &klcv1alpha1.KeptnEvaluationList{}
+	err := g.Client.List(ctx, evaluations)
+	if err != nil {
+		return nil, fmt.Errorf("could not retrieve evaluations: %w", err)
+	}
+
+	res := []GaugeValue{}
+
+	for _, evaluation := range evaluations.Items {
+		gaugeValue := int64(0)
+		if !evaluation.IsEndTimeSet() {
+			gaugeValue = int64(1)
+		}
+		res = append(res, GaugeValue{
+			Value:      gaugeValue,
+			Attributes: evaluation.GetActiveMetricsAttributes(),
+		})
+	}
+
+	return res, nil
+}
+
+func (g *Gauges) ProviderAvailability(ctx context.Context) ([]GaugeValue, error) {
+	providers := &klcv1alpha1.KeptnEvaluationProviderList{}
+	err := g.Client.List(ctx, providers)
+	if err != nil {
+		return nil, fmt.Errorf("could not retrieve evaluation providers: %w", err)
+	}
+
+	res := []GaugeValue{}
+
+	for _, provider := range providers.Items {
+		gaugeValue := int64(0)
+		if meta.IsStatusConditionTrue(provider.Status.Conditions, klcv1alpha1.ProviderReachable) {
+			gaugeValue = int64(1)
+		}
+		res = append(res, GaugeValue{
+			Value:      gaugeValue,
+			Attributes: provider.GetMetricsAttributes(),
+		})
+	}
+
+	return res, nil
+}
+
+func (g *Gauges) AppDrift(ctx context.Context) ([]GaugeValue, error) {
+	apps := &klcv1alpha1.KeptnAppList{}
+	err := g.Client.List(ctx, apps)
+	if err != nil {
+		return nil, fmt.Errorf("could not retrieve apps: %w", err)
+	}
+
+	res := []GaugeValue{}
+
+	for _, app := range apps.Items {
+		gaugeValue := int64(0)
+		if meta.IsStatusConditionFalse(app.Status.Conditions, klcv1alpha1.VersionsInSync) {
+			gaugeValue = int64(1)
+		}
+		res = append(res, GaugeValue{
+			Value: gaugeValue,
+			Attributes: []attribute.KeyValue{
+				common.AppName.String(app.Name),
+				common.AppNamespace.String(app.Namespace),
+			},
+		})
+	}
+
+	return res, nil
+}
+
+func (g *Gauges) AppDeploymentIntervals(ctx context.Context) ([]GaugeFloatValue, error) {
+	appInstances := &klcv1alpha1.KeptnAppVersionList{}
+	err := g.Client.List(ctx, appInstances)
+	if err != nil {
+		return nil, fmt.Errorf("could not retrieve app versions: %w", err)
+	}
+
+	res := []GaugeFloatValue{}
+
+	for _, appInstance := range appInstances.Items {
+		if appInstance.Spec.PreviousVersion != "" {
+			previousAppVersion := &klcv1alpha1.KeptnAppVersion{}
+			appName := common.CreateInstanceName(appInstance.Spec.AppName, appInstance.Spec.PreviousVersion)
+			err := g.Client.Get(ctx, types.NamespacedName{Name: appName, Namespace: appInstance.Namespace}, previousAppVersion)
+			if err == nil {
+				err = previousAppVersion.VerifyIdentity(appInstance.Spec.AppName, appInstance.Spec.PreviousVersion)
+			}
+			if err != nil {
+				g.Log.Error(err, "Previous App Version not found")
+			} else {
+				previousInterval := appInstance.Status.StartTime.Time.Sub(previousAppVersion.Status.EndTime.Time)
+				res = append(res, GaugeFloatValue{
+					Value:      previousInterval.Seconds(),
+					Attributes: appInstance.GetDurationMetricsAttributes(),
+				})
+			}
+		}
+	}
+
+	return res, nil
+}
+
+func (g *Gauges) AppDeploymentDurations(ctx context.Context) ([]GaugeFloatValue, error) {
+	appInstances := &klcv1alpha1.KeptnAppVersionList{}
+	err := g.Client.List(ctx, appInstances)
+	if err != nil {
+		return nil, fmt.Errorf("could not retrieve app versions: %w", err)
+	}
+
+	res := []GaugeFloatValue{}
+
+	for _, appInstance := range appInstances.Items {
+		if appInstance.IsEndTimeSet() {
+			duration := appInstance.Status.EndTime.Time.Sub(appInstance.Status.StartTime.Time)
+			res = append(res, GaugeFloatValue{
+				Value:      duration.Seconds(),
+				Attributes: appInstance.GetDurationMetricsAttributes(),
+			})
+		}
+	}
+
+	return res, nil
+}
+
+func (g *Gauges) WorkloadDeploymentIntervals(ctx context.Context) ([]GaugeFloatValue, error) {
+	workloadInstances := &klcv1alpha1.KeptnWorkloadInstanceList{}
+	err := g.Client.List(ctx, workloadInstances)
+	if err != nil {
+		return nil, fmt.Errorf("could not retrieve workload instances: %w", err)
+	}
+
+	res := []GaugeFloatValue{}
+	for _, workloadInstance := range workloadInstances.Items {
+		if workloadInstance.Spec.PreviousVersion != "" {
+			previousWorkloadInstance := &klcv1alpha1.KeptnWorkloadInstance{}
+			err := g.Client.Get(ctx, types.NamespacedName{Name: common.CreateInstanceName(workloadInstance.Spec.WorkloadName, workloadInstance.Spec.PreviousVersion), Namespace: workloadInstance.Namespace}, previousWorkloadInstance)
+			if err == nil {
+				err = previousWorkloadInstance.VerifyIdentity(workloadInstance.Spec.WorkloadName, workloadInstance.Spec.PreviousVersion)
+			}
+			if err != nil {
+				g.Log.Error(err, "Previous WorkloadInstance not found")
+			} else if workloadInstance.IsEndTimeSet() {
+				previousInterval := workloadInstance.Status.StartTime.Time.Sub(previousWorkloadInstance.Status.EndTime.Time)
+				res = append(res, GaugeFloatValue{
+					Value:      previousInterval.Seconds(),
+					Attributes: workloadInstance.GetIntervalMetricsAttributes(),
+				})
+			}
+		}
+	}
+	return res, nil
+}
+
+func (g *Gauges) WorkloadDeploymentDurations(ctx context.Context) ([]GaugeFloatValue, error) {
+	workloadInstances := &klcv1alpha1.KeptnWorkloadInstanceList{}
+	err := g.Client.List(ctx, workloadInstances)
+	if err != nil {
+		return nil, fmt.Errorf("could not retrieve workload instances: %w", err)
+	}
+
+	res := []GaugeFloatValue{}
+
+	for _, workloadInstance := range workloadInstances.Items {
+		// imported workload instances have not been deployed by the lifecycle controller and have no duration
+		if workloadInstance.IsEndTimeSet() && !workloadInstance.IsImported() {
+			duration := workloadInstance.Status.EndTime.Time.Sub(workloadInstance.Status.StartTime.Time)
+			res = append(res, GaugeFloatValue{
+				Value:      duration.Seconds(),
+				Attributes: workloadInstance.GetIntervalMetricsAttributes(),
+			})
+		}
+	}
+
+	return res, nil
+}
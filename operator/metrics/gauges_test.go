@@ -0,0 +1,132 @@
+package metrics
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+	klcv1alpha1 "github.com/keptn/lifecycle-controller/operator/api/v1alpha1"
+	testrequire "github.com/stretchr/testify/require"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func newTestGauges(t *testing.T) *Gauges {
+	scheme := runtime.NewScheme()
+	testrequire.Nil(t, klcv1alpha1.AddToScheme(scheme))
+
+	now := time.Now()
+	previous := &klcv1alpha1.KeptnWorkloadInstance{
+		ObjectMeta: metav1.ObjectMeta{Name: "my-workload-1.0", Namespace: "default"},
+		Spec: klcv1alpha1.KeptnWorkloadInstanceSpec{
+			KeptnWorkloadSpec: klcv1alpha1.KeptnWorkloadSpec{AppName: "my-app", Version: "1.0"},
+			WorkloadName:      "my-workload",
+		},
+		Status: klcv1alpha1.KeptnWorkloadInstanceStatus{
+			StartTime: metav1.NewTime(now.Add(-time.Hour)),
+			EndTime:   metav1.NewTime(now.Add(-50 * time.Minute)),
+		},
+	}
+	current := &klcv1alpha1.KeptnWorkloadInstance{
+		ObjectMeta: metav1.ObjectMeta{Name: "my-workload-2.0", Namespace: "default"},
+		Spec: klcv1alpha1.KeptnWorkloadInstanceSpec{
+			KeptnWorkloadSpec: klcv1alpha1.KeptnWorkloadSpec{AppName: "my-app", Version: "2.0"},
+			WorkloadName:      "my-workload",
+			PreviousVersion:   "1.0",
+		},
+		Status: klcv1alpha1.KeptnWorkloadInstanceStatus{
+			StartTime: metav1.NewTime(now.Add(-20 * time.Minute)),
+			EndTime:   metav1.NewTime(now.Add(-10 * time.Minute)),
+		},
+	}
+	active := &klcv1alpha1.KeptnWorkloadInstance{
+		ObjectMeta: metav1.ObjectMeta{Name: "other-workload-1.0", Namespace: "default"},
+		Spec: klcv1alpha1.KeptnWorkloadInstanceSpec{
+			KeptnWorkloadSpec: klcv1alpha1.KeptnWorkloadSpec{AppName: "my-app", Version: "1.0"},
+			WorkloadName:      "other-workload",
+		},
+		Status: klcv1alpha1.KeptnWorkloadInstanceStatus{
+			StartTime: metav1.NewTime(now),
+		},
+	}
+
+	return &Gauges{
+		Client: fake.NewClientBuilder().WithScheme(scheme).WithObjects(previous, current, active).Build(),
+		Log:    logr.Discard(),
+	}
+}
+
+func TestGauges_ActiveDeployments(t *testing.T) {
+	g := newTestGauges(t)
+
+	values, err := g.ActiveDeployments(context.TODO())
+	testrequire.Nil(t, err)
+	testrequire.Len(t, values, 3)
+
+	active := int64(0)
+	for _, value := range values {
+		active += value.Value
+	}
+	testrequire.Equal(t, int64(1), active)
+}
+
+func TestGauges_WorkloadDeploymentIntervals(t *testing.T) {
+	g := newTestGauges(t)
+
+	values, err := g.WorkloadDeploymentIntervals(context.TODO())
+	testrequire.Nil(t, err)
+	testrequire.Len(t, values, 1)
+	testrequire.InDelta(t, (30 * time.Minute).Seconds(), values[0].Value, 1)
+
+	durations, err := g.WorkloadDeploymentDurations(context.TODO())
+	testrequire.Nil(t, err)
+	testrequire.Len(t, durations, 2)
+	for _, duration := range durations {
+		testrequire.InDelta(t, (10 * time.Minute).Seconds(), duration.Value, 1)
+	}
+}
+
+func TestGauges_Register(t *testing.T) {
+	g := newTestGauges(t)
+	reader := sdkmetric.NewManualReader()
+	meter := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader)).Meter("test")
+
+	testrequire.Nil(t, g.Register(meter))
+	_, err := NewOTelMeters(meter, Config{})
+	testrequire.Nil(t, err)
+
+	collected, err := reader.Collect(context.TODO())
+	testrequire.Nil(t, err)
+	names := map[string]bool{}
+	for _, scope := range collected.ScopeMetrics {
+		for _, m := range scope.Metrics {
+			names[m.Name] = true
+		}
+	}
+	testrequire.True(t, names["keptn.deployment.active"])
+	testrequire.True(t, names["keptn.deployment.deploymentinterval"])
+}
+
+func TestGauges_RegisterWithConfig(t *testing.T) {
+	g := newTestGauges(t)
+	g.Config = Config{Prefix: "acme", Disabled: []string{"keptn.deployment.active"}}
+	reader := sdkmetric.NewManualReader()
+	meter := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader)).Meter("test")
+
+	testrequire.Nil(t, g.Register(meter))
+
+	collected, err := reader.Collect(context.TODO())
+	testrequire.Nil(t, err)
+	names := map[string]bool{}
+	for _, scope := range collected.ScopeMetrics {
+		for _, m := range scope.Metrics {
+			names[m.Name] = true
+		}
+	}
+	testrequire.False(t, names["keptn.deployment.active"])
+	testrequire.False(t, names["acme.keptn.deployment.active"])
+	testrequire.True(t, names["acme.keptn.deployment.deploymentinterval"])
+}
@@ -0,0 +1,61 @@
+package metrics
+
+import (
+	"context"
+	"sync"
+
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// Measurement is a single value recorded for an instrument
+type Measurement struct {
+	Instrument string
+	Value      float64
+	Attributes []attribute.KeyValue
+}
+
+// InMemoryMeters keeps all recorded measurements in memory, so that tests can check the metrics of the reconcilers
+type InMemoryMeters struct {
+	mu           sync.Mutex
+	measurements []Measurement
+}
+
+func NewInMemoryMeters() *InMemoryMeters {
+	return &InMemoryMeters{}
+}
+
+func (m *InMemoryMeters) Add(ctx context.Context, counter Counter, incr int64, attrs ...attribute.KeyValue) {
+	m.record(string(counter), float64(incr), attrs)
+}
+
+func (m *InMemoryMeters) Record(ctx context.Context, histogram Histogram, value float64, attrs ...attribute.KeyValue) {
+	m.record(string(histogram), value, attrs)
+}
+
+func (m *InMemoryMeters) record(instrument string, value float64, attrs []attribute.KeyValue) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.measurements = append(m.measurements, Measurement{Instrument: instrument, Value: value, Attributes: attrs})
+}
+
+// Measurements returns the measurements recorded for the given instrument
+func (m *InMemoryMeters) Measurements(instrument string) []Measurement {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	res := []Measurement{}
+	for _, measurement := range m.measurements {
+		if measurement.Instrument == instrument {
+			res = append(res, measurement)
+		}
+	}
+	return res
+}
+
+// Sum returns the sum of all values recorded for the given instrument
+func (m *InMemoryMeters) Sum(instrument string) float64 {
+	sum := 0.0
+	for _, measurement := range m.Measurements(instrument) {
+		sum += measurement.Value
+	}
+	return sum
+}
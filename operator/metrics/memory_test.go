@@ -0,0 +1,26 @@
+package metrics
+
+import (
+	"context"
+	"testing"
+
+	testrequire "github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+func TestInMemoryMeters(t *testing.T) {
+	var meters Meters = NewInMemoryMeters()
+
+	meters.Add(context.TODO(), TaskCount, 1, attribute.String("keptn.deployment.task.name", "pre"))
+	meters.Add(context.TODO(), TaskCount, 1)
+	meters.Record(context.TODO(), TaskDuration, 2.5)
+
+	inMemory := meters.(*InMemoryMeters)
+	testrequire.Equal(t, 2.0, inMemory.Sum(string(TaskCount)))
+	testrequire.Equal(t, 2.5, inMemory.Sum(string(TaskDuration)))
+	testrequire.Empty(t, inMemory.Measurements(string(AppCount)))
+
+	measurements := inMemory.Measurements(string(TaskCount))
+	testrequire.Len(t, measurements, 2)
+	testrequire.Equal(t, []attribute.KeyValue{attribute.String("keptn.deployment.task.name", "pre")}, measurements[0].Attributes)
+}
@@ -0,0 +1,113 @@
+package metrics
+
+import (
+	"context"
+	"fmt"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/metric/instrument"
+	"go.opentelemetry.io/otel/metric/instrument/syncfloat64"
+	"go.opentelemetry.io/otel/metric/instrument/syncint64"
+	"go.opentelemetry.io/otel/metric/unit"
+)
+
+// Counter is the name of a counter recorded by the reconcilers
+type Counter string
+
+// Histogram is the name of a histogram recorded by the reconcilers
+type Histogram string
+
+const (
+	AppCount        Counter = "keptn.app.count"
+	DeploymentCount Counter = "keptn.deployment.count"
+	TaskCount       Counter = "keptn.task.count"
+	EvaluationCount Counter = "keptn.evaluation.count"
+
+	SpansDropped      Counter = "keptn.spans.dropped"
+	SpansExportFailed Counter = "keptn.spans.export.failed"
+)
+
+const (
+	AppDuration        Histogram = "keptn.app.duration"
+	DeploymentDuration Histogram = "keptn.deployment.duration"
+	TaskDuration       Histogram = "keptn.task.duration"
+	EvaluationDuration Histogram = "keptn.evaluation.duration"
+	CostDelta          Histogram = "keptn.deployment.costdelta"
+	PowerConsumption   Histogram = "keptn.deployment.power"
+)
+
+// Meters records the metrics of the resources handled by the reconcilers
+type Meters interface {
+	Add(ctx context.Context, counter Counter, incr int64, attrs ...attribute.KeyValue)
+	Record(ctx context.Context, histogram Histogram, value float64, attrs ...attribute.KeyValue)
+}
+
+var counters = map[Counter][]instrument.Option{
+	AppCount:        {instrument.WithDescription("a simple counter for Keptn Apps")},
+	DeploymentCount: {instrument.WithDescription("a simple counter for Keptn Deployments")},
+	TaskCount:       {instrument.WithDescription("a simple counter for Keptn Tasks")},
+	EvaluationCount: {instrument.WithDescription("a simple counter for Keptn Evaluations")},
+
+	SpansDropped:      {instrument.WithDescription("a counter of the spans dropped since the export queue was full")},
+	SpansExportFailed: {instrument.WithDescription("a counter of the spans that could not be exported to the collector")},
+}
+
+var histograms = map[Histogram][]instrument.Option{
+	AppDuration:        {instrument.WithDescription("a histogram of duration for Keptn Apps"), instrument.WithUnit(unit.Unit("s"))},
+	DeploymentDuration: {instrument.WithDescription("a histogram of duration for Keptn Deployments"), instrument.WithUnit(unit.Unit("s"))},
+	TaskDuration:       {instrument.WithDescription("a histogram of duration for Keptn Tasks"), instrument.WithUnit(unit.Unit("s"))},
+	EvaluationDuration: {instrument.WithDescription("a histogram of duration for Keptn Evaluations"), instrument.WithUnit(unit.Unit("s"))},
+	CostDelta:          {instrument.WithDescription("a histogram of the projected hourly cost difference of Keptn Deployments compared to their previous version")},
+	PowerConsumption:   {instrument.WithDescription("a histogram of the power consumption of Keptn Deployments after they have been deployed"), instrument.WithUnit(unit.Unit("W"))},
+}
+
+// OTelMeters records the metrics with the instruments of an OpenTelemetry meter
+type OTelMeters struct {
+	counters   map[Counter]syncint64.Counter
+	histograms map[Histogram]syncfloat64.Histogram
+	guard      *CardinalityGuard
+}
+
+// NewOTelMeters creates the counters and histograms of the reconcilers on the given meter.
+// Disabled instruments are not created, so recording them has no effect.
+func NewOTelMeters(meter metric.Meter, config Config) (*OTelMeters, error) {
+	m := &OTelMeters{
+		counters:   map[Counter]syncint64.Counter{},
+		histograms: map[Histogram]syncfloat64.Histogram{},
+		guard:      config.Guard,
+	}
+	for name, opts := range counters {
+		if !config.Enabled(string(name)) {
+			continue
+		}
+		counter, err := meter.SyncInt64().Counter(config.Name(string(name)), opts...)
+		if err != nil {
+			return nil, fmt.Errorf("could not create counter %s: %w", name, err)
+		}
+		m.counters[name] = counter
+	}
+	for name, opts := range histograms {
+		if !config.Enabled(string(name)) {
+			continue
+		}
+		histogram, err := meter.SyncFloat64().Histogram(config.Name(string(name)), opts...)
+		if err != nil {
+			return nil, fmt.Errorf("could not create histogram %s: %w", name, err)
+		}
+		m.histograms[name] = histogram
+	}
+	return m, nil
+}
+
+func (m *OTelMeters) Add(ctx context.Context, counter Counter, incr int64, attrs ...attribute.KeyValue) {
+	if c, ok := m.counters[counter]; ok {
+		c.Add(ctx, incr, m.guard.Apply(attrs)...)
+	}
+}
+
+func (m *OTelMeters) Record(ctx context.Context, histogram Histogram, value float64, attrs ...attribute.KeyValue) {
+	if h, ok := m.histograms[histogram]; ok {
+		h.Record(ctx, value, m.guard.Apply(attrs)...)
+	}
+}
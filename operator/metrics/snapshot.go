@@ -0,0 +1,206 @@
+package metrics
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/go-logr/logr"
+	"go.opentelemetry.io/otel/attribute"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// SnapshotTimeKey is the key of the time the values have been persisted at in the snapshot ConfigMap
+const SnapshotTimeKey = "time"
+
+// snapshotSyncTimeout is the time a persisted gauge waits for the cache before the persisted values are reported
+const snapshotSyncTimeout = 2 * time.Second
+
+// snapshotGauges are the gauges whose values are persisted. They report the deployments in progress, which would
+// drop to zero while the cache of a new leader syncs, although the deployments are still running.
+var snapshotGauges = map[string]bool{
+	"keptn.app.active":        true,
+	"keptn.deployment.active": true,
+}
+
+type snapshotValue struct {
+	Value      int64             `json:"value"`
+	Attributes map[string]string `json:"attributes"`
+}
+
+// GaugeSnapshot persists the values of the gauges of the active apps and deployments in a ConfigMap. A replica of the
+// operator reports the persisted values as long as its cache has not synced, e.g. right after it has been started or
+// elected as leader, so that the gauges neither have gaps nor drop to zero during a failover.
+type GaugeSnapshot struct {
+	// Reader reads the ConfigMap without caching it
+	Reader client.Reader
+	Writer client.Writer
+	Log    logr.Logger
+	// ConfigMap is the ConfigMap the values are persisted in
+	ConfigMap types.NamespacedName
+	// Interval is the interval in which the leader persists the values. Persisted values older than five intervals are
+	// not reported anymore.
+	Interval time.Duration
+
+	mu          sync.Mutex
+	observers   map[string]func(ctx context.Context) ([]GaugeValue, error)
+	restored    map[string][]GaugeValue
+	persistedAt time.Time
+}
+
+//+kubebuilder:rbac:groups=core,resources=configmaps,verbs=get;create;update
+
+// NeedLeaderElection is true, so that only the leader persists the values
+func (s *GaugeSnapshot) NeedLeaderElection() bool {
+	return true
+}
+
+// Start persists the values of the gauges until the context is done
+func (s *GaugeSnapshot) Start(ctx context.Context) error {
+	ticker := time.NewTicker(s.Interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			if err := s.Save(ctx, time.Now()); err != nil {
+				s.Log.Error(err, "could not persist the values of the gauges")
+			}
+		}
+	}
+}
+
+// track registers the observation of a gauge whose values are persisted, and returns an observation which reports the
+// persisted values if the cache cannot be read in time
+func (s *GaugeSnapshot) track(name string, observe func(ctx context.Context) ([]GaugeValue, error)) func(ctx context.Context) ([]GaugeValue, error) {
+	if !snapshotGauges[name] {
+		return observe
+	}
+	s.mu.Lock()
+	if s.observers == nil {
+		s.observers = map[string]func(ctx context.Context) ([]GaugeValue, error){}
+	}
+	s.observers[name] = observe
+	s.mu.Unlock()
+
+	return func(ctx context.Context) ([]GaugeValue, error) {
+		observeCtx, cancel := context.WithTimeout(ctx, snapshotSyncTimeout)
+		defer cancel()
+		values, err := observe(observeCtx)
+		if err == nil {
+			return values, nil
+		}
+		restored, restoreErr := s.Restore(ctx, name, time.Now())
+		if restoreErr != nil {
+			s.Log.Error(restoreErr, "could not restore the persisted values of "+name)
+			return nil, err
+		}
+		return restored, nil
+	}
+}
+
+// Save observes the persisted gauges and writes their values to the ConfigMap. Only the values of active apps and
+// deployments are persisted, which keeps the ConfigMap small.
+func (s *GaugeSnapshot) Save(ctx context.Context, now time.Time) error {
+	s.mu.Lock()
+	observers := s.observers
+	s.mu.Unlock()
+
+	data := map[string]string{SnapshotTimeKey: now.UTC().Format(time.RFC3339)}
+	for name, observe := range observers {
+		values, err := observe(ctx)
+		if err != nil {
+			return fmt.Errorf("could not observe %s: %w", name, err)
+		}
+		persisted := []snapshotValue{}
+		for _, value := range values {
+			if value.Value == 0 {
+				continue
+			}
+			attributes := map[string]string{}
+			for _, kv := range value.Attributes {
+				attributes[string(kv.Key)] = kv.Value.Emit()
+			}
+			persisted = append(persisted, snapshotValue{Value: value.Value, Attributes: attributes})
+		}
+		encoded, err := json.Marshal(persisted)
+		if err != nil {
+			return fmt.Errorf("could not marshal values of %s: %w", name, err)
+		}
+		data[name] = string(encoded)
+	}
+
+	configMap := &corev1.ConfigMap{}
+	err := s.Reader.Get(ctx, s.ConfigMap, configMap)
+	if errors.IsNotFound(err) {
+		configMap = &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: s.ConfigMap.Name, Namespace: s.ConfigMap.Namespace},
+			Data:       data,
+		}
+		err = s.Writer.Create(ctx, configMap)
+	} else if err == nil {
+		configMap.Data = data
+		err = s.Writer.Update(ctx, configMap)
+	}
+	if err != nil {
+		return fmt.Errorf("could not write ConfigMap %s: %w", s.ConfigMap.Name, err)
+	}
+	return nil
+}
+
+// Restore returns the persisted values of the gauge. The ConfigMap is read once, since the values are only needed until
+// the cache has synced.
+func (s *GaugeSnapshot) Restore(ctx context.Context, name string, now time.Time) ([]GaugeValue, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.restored == nil {
+		if err := s.restore(ctx); err != nil {
+			return nil, err
+		}
+	}
+	if now.Sub(s.persistedAt) > 5*s.Interval {
+		return nil, fmt.Errorf("the snapshot has been persisted at %s and is outdated", s.persistedAt.Format(time.RFC3339))
+	}
+	return s.restored[name], nil
+}
+
+func (s *GaugeSnapshot) restore(ctx context.Context) error {
+	configMap := &corev1.ConfigMap{}
+	if err := s.Reader.Get(ctx, s.ConfigMap, configMap); err != nil {
+		return fmt.Errorf("could not read ConfigMap %s: %w", s.ConfigMap.Name, err)
+	}
+	persistedAt, err := time.Parse(time.RFC3339, configMap.Data[SnapshotTimeKey])
+	if err != nil {
+		return fmt.Errorf("could not parse the time of the snapshot: %w", err)
+	}
+
+	restored := map[string][]GaugeValue{}
+	for gauge := range snapshotGauges {
+		var persisted []snapshotValue
+		if err := json.Unmarshal([]byte(configMap.Data[gauge]), &persisted); err != nil {
+			continue
+		}
+		for _, value := range persisted {
+			keys := make([]string, 0, len(value.Attributes))
+			for key := range value.Attributes {
+				keys = append(keys, key)
+			}
+			sort.Strings(keys)
+			attributes := make([]attribute.KeyValue, 0, len(keys))
+			for _, key := range keys {
+				attributes = append(attributes, attribute.String(key, value.Attributes[key]))
+			}
+			restored[gauge] = append(restored[gauge], GaugeValue{Value: value.Value, Attributes: attributes})
+		}
+	}
+	s.restored, s.persistedAt = restored, persistedAt
+	return nil
+}
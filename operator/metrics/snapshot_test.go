@@ -0,0 +1,71 @@
+package metrics
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+	klcv1alpha1 "github.com/keptn/lifecycle-controller/operator/api/v1alpha1"
+	testrequire "github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestGaugeSnapshot(t *testing.T) {
+	scheme := runtime.NewScheme()
+	testrequire.Nil(t, clientgoscheme.AddToScheme(scheme))
+	testrequire.Nil(t, klcv1alpha1.AddToScheme(scheme))
+
+	now := time.Now()
+	finished := &klcv1alpha1.KeptnWorkloadInstance{
+		ObjectMeta: metav1.ObjectMeta{Name: "my-workload-1.0", Namespace: "default"},
+		Spec: klcv1alpha1.KeptnWorkloadInstanceSpec{
+			KeptnWorkloadSpec: klcv1alpha1.KeptnWorkloadSpec{AppName: "my-app", Version: "1.0"},
+			WorkloadName:      "my-workload",
+		},
+		Status: klcv1alpha1.KeptnWorkloadInstanceStatus{StartTime: metav1.NewTime(now.Add(-time.Hour)), EndTime: metav1.NewTime(now.Add(-50 * time.Minute))},
+	}
+	active := &klcv1alpha1.KeptnWorkloadInstance{
+		ObjectMeta: metav1.ObjectMeta{Name: "my-workload-2.0", Namespace: "default"},
+		Spec: klcv1alpha1.KeptnWorkloadInstanceSpec{
+			KeptnWorkloadSpec: klcv1alpha1.KeptnWorkloadSpec{AppName: "my-app", Version: "2.0"},
+			WorkloadName:      "my-workload",
+		},
+		Status: klcv1alpha1.KeptnWorkloadInstanceStatus{StartTime: metav1.NewTime(now)},
+	}
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(finished, active).Build()
+	configMap := types.NamespacedName{Namespace: "keptn-lifecycle-controller-system", Name: "keptn-gauge-snapshot"}
+	gauges := &Gauges{Client: c, Log: logr.Discard()}
+
+	// the leader persists the active deployments
+	leader := &GaugeSnapshot{Reader: c, Writer: c, Log: logr.Discard(), ConfigMap: configMap, Interval: time.Minute}
+	observe := leader.track("keptn.deployment.active", gauges.ActiveDeployments)
+	values, err := observe(context.TODO())
+	testrequire.Nil(t, err)
+	testrequire.Len(t, values, 2)
+	testrequire.Nil(t, leader.Save(context.TODO(), now))
+	// the values are persisted again by the next leader
+	testrequire.Nil(t, leader.Save(context.TODO(), now))
+
+	// a new replica reports the persisted values until its cache has synced
+	failover := &GaugeSnapshot{Reader: c, Writer: c, Log: logr.Discard(), ConfigMap: configMap, Interval: time.Minute}
+	notSynced := func(ctx context.Context) ([]GaugeValue, error) {
+		return nil, fmt.Errorf("the cache is not started, can not read objects")
+	}
+	values, err = failover.track("keptn.deployment.active", notSynced)(context.TODO())
+	testrequire.Nil(t, err)
+	testrequire.Len(t, values, 1)
+	testrequire.Equal(t, int64(1), values[0].Value)
+	testrequire.ElementsMatch(t, active.GetActiveMetricsAttributes(), values[0].Attributes)
+
+	// gauges which are not persisted and outdated values are not restored
+	_, err = failover.track("keptn.task.active", notSynced)(context.TODO())
+	testrequire.NotNil(t, err)
+	_, err = failover.Restore(context.TODO(), "keptn.deployment.active", now.Add(10*time.Minute))
+	testrequire.NotNil(t, err)
+}
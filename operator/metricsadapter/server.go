@@ -0,0 +1,210 @@
+package metricsadapter
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-logr/logr"
+	klcv1alpha1 "github.com/keptn/lifecycle-controller/operator/api/v1alpha1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// GroupVersion is the group and version of the custom metrics API served by the adapter
+	GroupVersion = "custom.metrics.k8s.io/v1beta2"
+	// Resource is the resource the KeptnMetrics are exposed as
+	Resource = "keptnmetrics.lifecycle.keptn.sh"
+)
+
+// Server serves the values of the KeptnMetrics as custom metrics API, so that HorizontalPodAutoscalers can scale
+// workloads based on the values the operator already fetches from the providers.
+// Each KeptnMetric is exposed as metric with its own name, described by the KeptnMetric itself.
+type Server struct {
+	Client      client.Reader
+	Log         logr.Logger
+	BindAddress string
+	// CertDir contains the tls.crt and tls.key the API is served with, since the API aggregation layer requires TLS
+	CertDir string
+}
+
+// Start runs the adapter until the context is cancelled. It implements the manager.Runnable interface.
+func (s *Server) Start(ctx context.Context) error {
+	srv := &http.Server{
+		Addr:              s.BindAddress,
+		Handler:           s.Handler(),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := srv.Shutdown(shutdownCtx); err != nil {
+			s.Log.Error(err, "could not shut down metrics adapter")
+		}
+	}()
+
+	s.Log.Info("serving custom metrics API at " + s.BindAddress)
+	err := srv.ListenAndServeTLS(filepath.Join(s.CertDir, "tls.crt"), filepath.Join(s.CertDir, "tls.key"))
+	if err != nil && err != http.ErrServerClosed {
+		return err
+	}
+	return nil
+}
+
+// NeedLeaderElection returns false, since the adapter is read-only and can be served by every replica
+func (s *Server) NeedLeaderElection() bool {
+	return false
+}
+
+// Handler returns the http.Handler serving the custom metrics API
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/healthz", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+	mux.HandleFunc("/apis/"+GroupVersion, s.handleDiscovery)
+	mux.HandleFunc("/apis/"+GroupVersion+"/", s.handleMetric)
+	return mux
+}
+
+// handleDiscovery lists the KeptnMetrics of all namespaces as resources of the API
+func (s *Server) handleDiscovery(w http.ResponseWriter, r *http.Request) {
+	metrics := &klcv1alpha1.KeptnMetricList{}
+	if err := s.Client.List(r.Context(), metrics); err != nil {
+		s.Log.Error(err, "could not retrieve KeptnMetrics")
+		http.Error(w, "could not retrieve KeptnMetrics", http.StatusInternalServerError)
+		return
+	}
+
+	resources := &metav1.APIResourceList{
+		TypeMeta:     metav1.TypeMeta{Kind: "APIResourceList", APIVersion: "v1"},
+		GroupVersion: GroupVersion,
+		APIResources: []metav1.APIResource{},
+	}
+	seen := map[string]bool{}
+	for _, metric := range metrics.Items {
+		name := Resource + "/" + metric.Name
+		if seen[name] {
+			continue
+		}
+		seen[name] = true
+		resources.APIResources = append(resources.APIResources, metav1.APIResource{
+			Name:       name,
+			Namespaced: true,
+			Kind:       "MetricValueList",
+			Verbs:      metav1.Verbs{"get"},
+		})
+	}
+	s.writeJSON(w, resources)
+}
+
+// handleMetric serves /apis/custom.metrics.k8s.io/v1beta2/namespaces/<namespace>/keptnmetrics.lifecycle.keptn.sh/<name>/<metric>,
+// where name is either the name of the KeptnMetric or *
+func (s *Server) handleMetric(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	parts := strings.Split(strings.TrimPrefix(r.URL.Path, "/apis/"+GroupVersion+"/"), "/")
+	if len(parts) != 5 || parts[0] != "namespaces" || parts[2] != Resource {
+		http.Error(w, "only metrics of "+Resource+" in a namespace are supported", http.StatusNotFound)
+		return
+	}
+	namespace, name, metricName := parts[1], parts[3], parts[4]
+	if name != "*" && name != metricName {
+		http.Error(w, "KeptnMetric "+name+" only provides the metric "+name, http.StatusNotFound)
+		return
+	}
+
+	metric := &klcv1alpha1.KeptnMetric{}
+	if err := s.Client.Get(r.Context(), types.NamespacedName{Namespace: namespace, Name: metricName}, metric); err != nil {
+		if errors.IsNotFound(err) {
+			http.Error(w, "KeptnMetric "+metricName+" not found", http.StatusNotFound)
+			return
+		}
+		s.Log.Error(err, "could not retrieve KeptnMetric")
+		http.Error(w, "could not retrieve KeptnMetric", http.StatusInternalServerError)
+		return
+	}
+
+	value, err := NewMetricValue(*metric)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+	s.writeJSON(w, &MetricValueList{
+		TypeMeta: metav1.TypeMeta{Kind: "MetricValueList", APIVersion: GroupVersion},
+		Items:    []MetricValue{value},
+	})
+}
+
+func (s *Server) writeJSON(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		s.Log.Error(err, "could not encode response")
+	}
+}
+
+// MetricValueList is the response of the custom metrics API
+type MetricValueList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata"`
+	Items           []MetricValue `json:"items"`
+}
+
+// MetricValue is the value of a metric of a single object
+type MetricValue struct {
+	DescribedObject ObjectReference   `json:"describedObject"`
+	Metric          MetricIdentifier  `json:"metric"`
+	Timestamp       metav1.Time       `json:"timestamp"`
+	Value           resource.Quantity `json:"value"`
+}
+
+// ObjectReference references the object the metric is described by
+type ObjectReference struct {
+	Kind       string `json:"kind"`
+	Namespace  string `json:"namespace"`
+	Name       string `json:"name"`
+	APIVersion string `json:"apiVersion"`
+}
+
+// MetricIdentifier identifies a metric by its name
+type MetricIdentifier struct {
+	Name string `json:"name"`
+}
+
+// NewMetricValue converts the cached value of the KeptnMetric to a value of the custom metrics API
+func NewMetricValue(metric klcv1alpha1.KeptnMetric) (MetricValue, error) {
+	if metric.Status.Value == "" {
+		return MetricValue{}, fmt.Errorf("KeptnMetric %s has no value yet", metric.Name)
+	}
+	value, err := strconv.ParseFloat(metric.Status.Value, 64)
+	if err != nil || math.IsNaN(value) || math.IsInf(value, 0) {
+		return MetricValue{}, fmt.Errorf("KeptnMetric %s has no numeric value: %s", metric.Name, metric.Status.Value)
+	}
+
+	return MetricValue{
+		DescribedObject: ObjectReference{
+			Kind:       "KeptnMetric",
+			Namespace:  metric.Namespace,
+			Name:       metric.Name,
+			APIVersion: klcv1alpha1.GroupVersion.String(),
+		},
+		Metric:    MetricIdentifier{Name: metric.Name},
+		Timestamp: metric.Status.LastUpdated,
+		Value:     *resource.NewMilliQuantity(int64(math.Round(value*1000)), resource.DecimalSI),
+	}, nil
+}
@@ -0,0 +1,62 @@
+package metricsadapter
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-logr/logr"
+	klcv1alpha1 "github.com/keptn/lifecycle-controller/operator/api/v1alpha1"
+	testrequire "github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestServer(t *testing.T) {
+	scheme := runtime.NewScheme()
+	testrequire.Nil(t, klcv1alpha1.AddToScheme(scheme))
+
+	metric := &klcv1alpha1.KeptnMetric{
+		ObjectMeta: metav1.ObjectMeta{Name: "queue-length", Namespace: "default"},
+		Status:     klcv1alpha1.KeptnMetricStatus{Value: "12.5"},
+	}
+	pending := &klcv1alpha1.KeptnMetric{ObjectMeta: metav1.ObjectMeta{Name: "latency", Namespace: "default"}}
+	s := &Server{
+		Client: fake.NewClientBuilder().WithScheme(scheme).WithObjects(metric, pending).Build(),
+		Log:    logr.Discard(),
+	}
+	srv := httptest.NewServer(s.Handler())
+	defer srv.Close()
+
+	resp, err := http.Get(srv.URL + "/apis/" + GroupVersion)
+	testrequire.Nil(t, err)
+	resources := &metav1.APIResourceList{}
+	testrequire.Nil(t, json.NewDecoder(resp.Body).Decode(resources))
+	resp.Body.Close()
+	testrequire.Len(t, resources.APIResources, 2)
+
+	for _, name := range []string{"queue-length", "*"} {
+		resp, err = http.Get(srv.URL + "/apis/" + GroupVersion + "/namespaces/default/" + Resource + "/" + name + "/queue-length")
+		testrequire.Nil(t, err)
+		testrequire.Equal(t, http.StatusOK, resp.StatusCode)
+		values := &MetricValueList{}
+		testrequire.Nil(t, json.NewDecoder(resp.Body).Decode(values))
+		resp.Body.Close()
+		testrequire.Len(t, values.Items, 1)
+		testrequire.Equal(t, "KeptnMetric", values.Items[0].DescribedObject.Kind)
+		testrequire.Equal(t, "12500m", values.Items[0].Value.String())
+	}
+
+	for _, path := range []string{
+		"/namespaces/default/" + Resource + "/latency/latency",
+		"/namespaces/default/" + Resource + "/missing/missing",
+		"/namespaces/default/pods/queue-length/queue-length",
+	} {
+		resp, err = http.Get(srv.URL + "/apis/" + GroupVersion + path)
+		testrequire.Nil(t, err)
+		resp.Body.Close()
+		testrequire.Equal(t, http.StatusNotFound, resp.StatusCode, path)
+	}
+}
@@ -0,0 +1,181 @@
+package migration
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/go-logr/logr"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/client-go/util/retry"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const defaultPageSize = 100
+
+// Progress is the state of the migration of the objects of a CRD
+type Progress struct {
+	CRD            string
+	StorageVersion string
+	// StaleVersions are the versions objects of the CRD may still be stored in
+	StaleVersions []string
+	Migrated      int
+	// Done is true once all objects are stored in the storage version and the stale versions have been removed from the
+	// stored versions of the CRD
+	Done bool
+}
+
+func (p Progress) String() string {
+	if len(p.StaleVersions) == 0 {
+		return fmt.Sprintf("%s: all objects are stored in %s", p.CRD, p.StorageVersion)
+	}
+	state := "in progress"
+	if p.Done {
+		state = "completed"
+	}
+	return fmt.Sprintf("%s: migration from %v to %s %s, %d objects migrated", p.CRD, p.StaleVersions, p.StorageVersion, state, p.Migrated)
+}
+
+// Migrator rewrites the objects of the CRDs of an API group that may be stored in versions other than the storage
+// version of the CRD. Once all objects of a CRD have been rewritten, only the storage version is left in the stored
+// versions of the CRD, so that the other versions can be removed from the served versions in future releases.
+type Migrator struct {
+	// Reader lists the objects without caching them
+	Reader client.Reader
+	Writer client.Client
+	Log    logr.Logger
+	// Recorder reports the progress as events of the CRDs. It is optional.
+	Recorder record.EventRecorder
+	Group    string
+	// PageSize is the number of objects that are listed and rewritten at once
+	PageSize int64
+}
+
+//+kubebuilder:rbac:groups=apiextensions.k8s.io,resources=customresourcedefinitions,verbs=get;list
+//+kubebuilder:rbac:groups=apiextensions.k8s.io,resources=customresourcedefinitions/status,verbs=update
+//+kubebuilder:rbac:groups=lifecycle.keptn.sh,resources=*,verbs=get;list;update
+
+// NeedLeaderElection is true, so that only one replica of the operator migrates the objects
+func (m *Migrator) NeedLeaderElection() bool {
+	return true
+}
+
+// Start migrates the objects in the background of the operator. A failed migration does not stop the operator and
+// is retried on its next start.
+func (m *Migrator) Start(ctx context.Context) error {
+	if _, err := m.Run(ctx); err != nil {
+		m.Log.Error(err, "could not migrate the stored versions of the CRDs")
+	}
+	return nil
+}
+
+// Run migrates the objects of all CRDs of the group and returns their progress
+func (m *Migrator) Run(ctx context.Context) ([]Progress, error) {
+	crds := &apiextensionsv1.CustomResourceDefinitionList{}
+	if err := m.Reader.List(ctx, crds); err != nil {
+		return nil, fmt.Errorf("could not retrieve CustomResourceDefinitions: %w", err)
+	}
+	var progress []Progress
+	for i := range crds.Items {
+		crd := &crds.Items[i]
+		if crd.Spec.Group != m.Group {
+			continue
+		}
+		p, err := m.migrate(ctx, crd)
+		progress = append(progress, p)
+		if err != nil {
+			return progress, err
+		}
+		m.Log.Info(p.String())
+	}
+	return progress, nil
+}
+
+// migrate rewrites all objects of the CRD if it has stale stored versions and removes them from its stored versions
+func (m *Migrator) migrate(ctx context.Context, crd *apiextensionsv1.CustomResourceDefinition) (Progress, error) {
+	p := Progress{CRD: crd.Name, StorageVersion: storageVersion(crd)}
+	for _, version := range crd.Status.StoredVersions {
+		if version != p.StorageVersion {
+			p.StaleVersions = append(p.StaleVersions, version)
+		}
+	}
+	if p.StorageVersion == "" || len(p.StaleVersions) == 0 {
+		return p, nil
+	}
+	m.event(crd, "Normal", "MigrationStarted", p.String())
+
+	gvk := schema.GroupVersionKind{Group: crd.Spec.Group, Version: p.StorageVersion, Kind: crd.Spec.Names.ListKind}
+	pageSize := m.PageSize
+	if pageSize == 0 {
+		pageSize = defaultPageSize
+	}
+	continueToken := ""
+	for {
+		list := &unstructured.UnstructuredList{}
+		list.SetGroupVersionKind(gvk)
+		if err := m.Reader.List(ctx, list, client.Limit(pageSize), client.Continue(continueToken)); err != nil {
+			m.event(crd, "Warning", "MigrationFailed", err.Error())
+			return p, fmt.Errorf("could not retrieve %s: %w", crd.Spec.Names.Plural, err)
+		}
+		for i := range list.Items {
+			list.Items[i].SetGroupVersionKind(gvk.GroupVersion().WithKind(crd.Spec.Names.Kind))
+			if err := m.rewrite(ctx, &list.Items[i]); err != nil {
+				m.event(crd, "Warning", "MigrationFailed", err.Error())
+				return p, err
+			}
+			p.Migrated++
+		}
+		m.event(crd, "Normal", "MigrationProgress", p.String())
+		continueToken = list.GetContinue()
+		if continueToken == "" {
+			break
+		}
+	}
+
+	crd.Status.StoredVersions = []string{p.StorageVersion}
+	if err := m.Writer.Status().Update(ctx, crd); err != nil {
+		m.event(crd, "Warning", "MigrationFailed", err.Error())
+		return p, fmt.Errorf("could not update stored versions of %s: %w", crd.Name, err)
+	}
+	p.Done = true
+	m.event(crd, "Normal", "MigrationCompleted", p.String())
+	return p, nil
+}
+
+// rewrite updates the object without changes, which makes the API server store it in the storage version
+func (m *Migrator) rewrite(ctx context.Context, obj *unstructured.Unstructured) error {
+	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		err := m.Writer.Update(ctx, obj)
+		if errors.IsConflict(err) {
+			if getErr := m.Reader.Get(ctx, client.ObjectKeyFromObject(obj), obj); getErr != nil {
+				return getErr
+			}
+		}
+		return err
+	})
+	if errors.IsNotFound(err) {
+		// objects deleted during the migration do not need to be migrated
+		return nil
+	} else if err != nil {
+		return fmt.Errorf("could not migrate %s %s/%s: %w", obj.GetKind(), obj.GetNamespace(), obj.GetName(), err)
+	}
+	return nil
+}
+
+func (m *Migrator) event(crd *apiextensionsv1.CustomResourceDefinition, eventType string, reason string, message string) {
+	if m.Recorder != nil {
+		m.Recorder.Event(crd, eventType, reason, message)
+	}
+}
+
+func storageVersion(crd *apiextensionsv1.CustomResourceDefinition) string {
+	for _, version := range crd.Spec.Versions {
+		if version.Storage {
+			return version.Name
+		}
+	}
+	return ""
+}
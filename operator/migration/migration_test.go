@@ -0,0 +1,62 @@
+package migration
+
+import (
+	"context"
+	"testing"
+
+	"github.com/go-logr/logr"
+	klcv1alpha1 "github.com/keptn/lifecycle-controller/operator/api/v1alpha1"
+	testrequire "github.com/stretchr/testify/require"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func newCRD(plural string, kind string, storedVersions ...string) *apiextensionsv1.CustomResourceDefinition {
+	return &apiextensionsv1.CustomResourceDefinition{
+		ObjectMeta: metav1.ObjectMeta{Name: plural + ".lifecycle.keptn.sh"},
+		Spec: apiextensionsv1.CustomResourceDefinitionSpec{
+			Group:    "lifecycle.keptn.sh",
+			Names:    apiextensionsv1.CustomResourceDefinitionNames{Plural: plural, Kind: kind, ListKind: kind + "List"},
+			Versions: []apiextensionsv1.CustomResourceDefinitionVersion{{Name: "v1alpha1", Served: true, Storage: true}},
+		},
+		Status: apiextensionsv1.CustomResourceDefinitionStatus{StoredVersions: storedVersions},
+	}
+}
+
+func TestMigrator_Run(t *testing.T) {
+	scheme := runtime.NewScheme()
+	testrequire.Nil(t, apiextensionsv1.AddToScheme(scheme))
+	testrequire.Nil(t, klcv1alpha1.AddToScheme(scheme))
+
+	k8sClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+		newCRD("keptnapps", "KeptnApp", "v1alpha0", "v1alpha1"),
+		newCRD("keptnmetrics", "KeptnMetric", "v1alpha1"),
+		&klcv1alpha1.KeptnApp{ObjectMeta: metav1.ObjectMeta{Name: "app-1", Namespace: "default"}},
+		&klcv1alpha1.KeptnApp{ObjectMeta: metav1.ObjectMeta{Name: "app-2", Namespace: "default"}},
+		&klcv1alpha1.KeptnApp{ObjectMeta: metav1.ObjectMeta{Name: "app-3", Namespace: "other"}},
+	).Build()
+
+	before := &klcv1alpha1.KeptnApp{}
+	testrequire.Nil(t, k8sClient.Get(context.TODO(), types.NamespacedName{Namespace: "other", Name: "app-3"}, before))
+
+	m := &Migrator{Reader: k8sClient, Writer: k8sClient, Log: logr.Discard(), Group: "lifecycle.keptn.sh", PageSize: 2}
+	progress, err := m.Run(context.TODO())
+	testrequire.Nil(t, err)
+	testrequire.Len(t, progress, 2)
+
+	testrequire.Equal(t, Progress{CRD: "keptnapps.lifecycle.keptn.sh", StorageVersion: "v1alpha1", StaleVersions: []string{"v1alpha0"}, Migrated: 3, Done: true}, progress[0])
+	testrequire.Empty(t, progress[1].StaleVersions)
+	testrequire.False(t, progress[1].Done)
+
+	crd := &apiextensionsv1.CustomResourceDefinition{}
+	testrequire.Nil(t, k8sClient.Get(context.TODO(), types.NamespacedName{Name: "keptnapps.lifecycle.keptn.sh"}, crd))
+	testrequire.Equal(t, []string{"v1alpha1"}, crd.Status.StoredVersions)
+
+	// the objects have been rewritten
+	after := &klcv1alpha1.KeptnApp{}
+	testrequire.Nil(t, k8sClient.Get(context.TODO(), types.NamespacedName{Namespace: "other", Name: "app-3"}, after))
+	testrequire.NotEqual(t, before.ResourceVersion, after.ResourceVersion)
+}
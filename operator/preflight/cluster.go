@@ -0,0 +1,70 @@
+package preflight
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	klcv1alpha1 "github.com/keptn/lifecycle-controller/operator/api/v1alpha1"
+	"github.com/keptn/lifecycle-controller/operator/controllers/keptntask"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+//+kubebuilder:rbac:groups=lifecycle.keptn.sh,resources=keptnevaluationproviders;keptntaskdefinitions,verbs=get;list;watch
+
+// ClusterDependencies returns the dependencies configured in the cluster: the target servers of the KeptnEvaluationProviders,
+// and the runner images of the KeptnTaskDefinitions and the URLs their functions are loaded from
+func ClusterDependencies(ctx context.Context, c client.Reader) ([]Dependency, error) {
+	var dependencies []Dependency
+
+	providers := &klcv1alpha1.KeptnEvaluationProviderList{}
+	if err := c.List(ctx, providers); err != nil {
+		return nil, fmt.Errorf("could not retrieve KeptnEvaluationProviders: %w", err)
+	}
+	for _, provider := range providers.Items {
+		dependencies = append(dependencies, Dependency{
+			Name:    fmt.Sprintf("KeptnEvaluationProvider %s/%s", provider.Namespace, provider.Name),
+			Type:    TypeURL,
+			Address: provider.Spec.TargetServer,
+		})
+	}
+
+	definitions := &klcv1alpha1.KeptnTaskDefinitionList{}
+	if err := c.List(ctx, definitions); err != nil {
+		return nil, fmt.Errorf("could not retrieve KeptnTaskDefinitions: %w", err)
+	}
+	namespaces := map[string]map[string]string{}
+	images := map[string]bool{}
+	for i := range definitions.Items {
+		definition := &definitions.Items[i]
+		function, rt := keptntask.Function(definition)
+		if reflect.DeepEqual(function, klcv1alpha1.FunctionSpec{}) {
+			continue
+		}
+		name := fmt.Sprintf("KeptnTaskDefinition %s/%s", definition.Namespace, definition.Name)
+		if url := function.HttpReference.Url; url != "" {
+			dependencies = append(dependencies, Dependency{Name: name, Type: TypeURL, Address: url})
+		}
+
+		annotations, ok := namespaces[definition.Namespace]
+		if !ok {
+			namespace := &corev1.Namespace{}
+			if err := c.Get(ctx, client.ObjectKey{Name: definition.Namespace}, namespace); err != nil {
+				return nil, fmt.Errorf("could not retrieve namespace %s: %w", definition.Namespace, err)
+			}
+			annotations = namespace.Annotations
+			namespaces[definition.Namespace] = annotations
+		}
+		image := rt.RunnerImage(function.Runner, annotations).Image
+		if image != "" && !images[image] {
+			images[image] = true
+			dependencies = append(dependencies, Dependency{Name: "runner of " + name, Type: TypeImage, Address: image})
+		}
+		if image := function.Dependencies.Image; image != "" && !images[image] {
+			images[image] = true
+			dependencies = append(dependencies, Dependency{Name: "dependencies of " + name, Type: TypeImage, Address: image})
+		}
+	}
+	return dependencies, nil
+}
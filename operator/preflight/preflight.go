@@ -0,0 +1,192 @@
+package preflight
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+	"text/tabwriter"
+	"time"
+)
+
+// DependencyType defines how the address of a dependency is interpreted
+type DependencyType string
+
+const (
+	// TypeURL dependencies are addressed by a URL, e.g. http://prometheus.monitoring:9090
+	TypeURL DependencyType = "URL"
+	// TypeEndpoint dependencies are addressed by host and port, e.g. otel-collector:4317
+	TypeEndpoint DependencyType = "Endpoint"
+	// TypeImage dependencies are container images, whose registry has to be reachable from the nodes
+	TypeImage DependencyType = "Image"
+)
+
+const dockerHubRegistry = "registry-1.docker.io"
+
+// internalSuffixes are the domain suffixes which are resolved within the cluster or the local network
+var internalSuffixes = []string{".svc", ".cluster.local", ".local", ".internal", ".localhost"}
+
+// Dependency is an external system the operator or the tasks it runs depend on
+type Dependency struct {
+	// Name describes the dependency, e.g. OTel collector or KeptnEvaluationProvider default/prometheus
+	Name    string
+	Type    DependencyType
+	Address string
+}
+
+// Result is the outcome of the checks of a dependency
+type Result struct {
+	Dependency
+	// Host is the host:port the dependency is reached at
+	Host string
+	// Internet is true if the dependency requires internet access
+	Internet bool
+	Err      error
+}
+
+// Report contains the results of the checks of all dependencies
+type Report struct {
+	Offline bool
+	Results []Result
+}
+
+// Failed returns whether a dependency is not reachable, or requires internet access in offline mode
+func (r Report) Failed() bool {
+	for _, result := range r.Results {
+		if result.Err != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// String formats the report as table with one line per dependency
+func (r Report) String() string {
+	b := &strings.Builder{}
+	w := tabwriter.NewWriter(b, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "RESULT\tTYPE\tDEPENDENCY\tADDRESS\tDETAILS")
+	for _, result := range r.Results {
+		status, details := "OK", ""
+		if result.Err != nil {
+			status, details = "FAILED", result.Err.Error()
+		} else if result.Internet {
+			details = "requires internet access"
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", status, result.Type, result.Name, result.Address, details)
+	}
+	w.Flush()
+	return b.String()
+}
+
+// Checker validates the external dependencies of the operator
+type Checker struct {
+	// Offline refuses dependencies requiring internet access
+	Offline bool
+	// CheckReachability opens a connection to every dependency
+	CheckReachability bool
+	// InternalDomains are additional domain suffixes that do not require internet access, e.g. corp.example.com
+	InternalDomains []string
+	Timeout         time.Duration
+}
+
+// Check validates the given dependencies and returns the report
+func (c *Checker) Check(ctx context.Context, dependencies []Dependency) Report {
+	report := Report{Offline: c.Offline}
+	for _, dependency := range dependencies {
+		result := Result{Dependency: dependency}
+		result.Host, result.Err = HostOf(dependency)
+		if result.Err == nil {
+			result.Internet = c.requiresInternet(result.Host)
+			if c.Offline && result.Internet {
+				result.Err = fmt.Errorf("%s requires internet access, which is not allowed in offline mode", result.Host)
+			} else if c.CheckReachability {
+				result.Err = c.dial(ctx, result.Host)
+			}
+		}
+		report.Results = append(report.Results, result)
+	}
+	return report
+}
+
+func (c *Checker) dial(ctx context.Context, host string) error {
+	timeout := c.Timeout
+	if timeout == 0 {
+		timeout = 5 * time.Second
+	}
+	dialer := net.Dialer{Timeout: timeout}
+	conn, err := dialer.DialContext(ctx, "tcp", host)
+	if err != nil {
+		return fmt.Errorf("%s is not reachable: %w", host, err)
+	}
+	return conn.Close()
+}
+
+func (c *Checker) requiresInternet(host string) bool {
+	hostname, _, err := net.SplitHostPort(host)
+	if err != nil {
+		hostname = host
+	}
+	if ip := net.ParseIP(hostname); ip != nil {
+		return !(ip.IsPrivate() || ip.IsLoopback() || ip.IsLinkLocalUnicast())
+	}
+	// services in the cluster can be addressed by their name
+	if !strings.Contains(hostname, ".") {
+		return false
+	}
+	for _, suffix := range append(append([]string{}, internalSuffixes...), c.InternalDomains...) {
+		suffix = "." + strings.TrimPrefix(suffix, ".")
+		if strings.HasSuffix(hostname, suffix) || hostname == suffix[1:] {
+			return false
+		}
+	}
+	return true
+}
+
+// HostOf returns the host:port the given dependency is reached at
+func HostOf(dependency Dependency) (string, error) {
+	switch dependency.Type {
+	case TypeEndpoint:
+		if _, _, err := net.SplitHostPort(dependency.Address); err != nil {
+			return "", fmt.Errorf("invalid endpoint %s: %w", dependency.Address, err)
+		}
+		return dependency.Address, nil
+	case TypeImage:
+		return registryOf(dependency.Address), nil
+	default:
+		u, err := url.Parse(dependency.Address)
+		if err != nil || u.Hostname() == "" {
+			return "", fmt.Errorf("invalid url %s", dependency.Address)
+		}
+		if u.Port() != "" {
+			return u.Host, nil
+		}
+		return net.JoinHostPort(u.Hostname(), defaultPort(u.Scheme)), nil
+	}
+}
+
+// registryOf returns the host:port of the registry of an image reference like ghcr.io/keptn/functions-runtime:v0.3.0
+func registryOf(image string) string {
+	registry := dockerHubRegistry
+	if i := strings.Index(image, "/"); i > 0 {
+		first := image[:i]
+		if strings.ContainsAny(first, ".:") || first == "localhost" {
+			registry = first
+		}
+	}
+	if _, _, err := net.SplitHostPort(registry); err == nil {
+		return registry
+	}
+	return net.JoinHostPort(registry, "443")
+}
+
+func defaultPort(scheme string) string {
+	switch scheme {
+	case "http":
+		return "80"
+	case "nats":
+		return "4222"
+	default:
+		return "443"
+	}
+}
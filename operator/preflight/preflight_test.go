@@ -0,0 +1,69 @@
+package preflight
+
+import (
+	"context"
+	"net"
+	"strings"
+	"testing"
+
+	testrequire "github.com/stretchr/testify/require"
+)
+
+func TestHostOf(t *testing.T) {
+	tests := []struct {
+		dependency Dependency
+		want       string
+	}{
+		{Dependency{Type: TypeEndpoint, Address: "otel-collector:4317"}, "otel-collector:4317"},
+		{Dependency{Type: TypeURL, Address: "http://prometheus.monitoring:9090/api"}, "prometheus.monitoring:9090"},
+		{Dependency{Type: TypeURL, Address: "https://events.pagerduty.com/v2/enqueue"}, "events.pagerduty.com:443"},
+		{Dependency{Type: TypeURL, Address: "nats://nats.nats"}, "nats.nats:4222"},
+		{Dependency{Type: TypeImage, Address: "ghcr.io/keptn/functions-runtime:v0.3.0"}, "ghcr.io:443"},
+		{Dependency{Type: TypeImage, Address: "registry.local:5000/deno:1.0"}, "registry.local:5000"},
+		{Dependency{Type: TypeImage, Address: "denoland/deno:1.28"}, "registry-1.docker.io:443"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.dependency.Address, func(t *testing.T) {
+			host, err := HostOf(tt.dependency)
+			testrequire.Nil(t, err)
+			testrequire.Equal(t, tt.want, host)
+		})
+	}
+}
+
+func TestChecker_Offline(t *testing.T) {
+	c := &Checker{Offline: true, InternalDomains: []string{"corp.example.com"}}
+
+	report := c.Check(context.TODO(), []Dependency{
+		{Name: "OTel collector", Type: TypeEndpoint, Address: "otel-collector.observability.svc.cluster.local:4317"},
+		{Name: "Prometheus", Type: TypeURL, Address: "http://10.0.0.12:9090"},
+		{Name: "function runner", Type: TypeImage, Address: "registry.corp.example.com/keptn/functions-runtime:v0.3.0"},
+		{Name: "incident provider", Type: TypeURL, Address: "https://events.pagerduty.com/v2/enqueue"},
+	})
+
+	testrequire.True(t, report.Failed())
+	for _, result := range report.Results[:3] {
+		testrequire.Nil(t, result.Err, result.Name)
+	}
+	testrequire.True(t, report.Results[3].Internet)
+	testrequire.NotNil(t, report.Results[3].Err)
+	testrequire.Contains(t, report.String(), "FAILED  URL       incident provider")
+}
+
+func TestChecker_Reachability(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	testrequire.Nil(t, err)
+	address := listener.Addr().String()
+	listener.Close()
+
+	c := &Checker{CheckReachability: true}
+	report := c.Check(context.TODO(), []Dependency{{Name: "OTel collector", Type: TypeEndpoint, Address: address}})
+	testrequire.True(t, report.Failed())
+	testrequire.True(t, strings.HasPrefix(report.Results[0].Err.Error(), address+" is not reachable"))
+
+	listener, err = net.Listen("tcp", "127.0.0.1:0")
+	testrequire.Nil(t, err)
+	defer listener.Close()
+	report = c.Check(context.TODO(), []Dependency{{Name: "OTel collector", Type: TypeEndpoint, Address: listener.Addr().String()}})
+	testrequire.False(t, report.Failed())
+}
@@ -0,0 +1,90 @@
+package redaction
+
+import (
+	"regexp"
+	"strings"
+)
+
+// Placeholder replaces redacted values
+const Placeholder = "***"
+
+// DefaultKeys are the keys whose values are redacted by default. A key is sensitive if it contains one of the keys,
+// regardless of its case.
+var DefaultKeys = []string{
+	"password",
+	"passwd",
+	"secret",
+	"token",
+	"apikey",
+	"api_key",
+	"api-key",
+	"credential",
+	"private_key",
+	"access_key",
+	"authorization",
+}
+
+var defaultRedactor = New(nil)
+
+// Redactor hides the values of sensitive keys in task parameters and evaluation queries before they are added to
+// spans, events or status fields. A nil Redactor redacts the DefaultKeys.
+type Redactor struct {
+	keys       []string
+	assignment *regexp.Regexp
+}
+
+// New returns a Redactor for the DefaultKeys and the given additional keys
+func New(keys []string) *Redactor {
+	r := &Redactor{}
+	for _, key := range append(append([]string{}, DefaultKeys...), keys...) {
+		if key = strings.ToLower(strings.TrimSpace(key)); key != "" {
+			r.keys = append(r.keys, key)
+		}
+	}
+	quoted := make([]string, 0, len(r.keys))
+	for _, key := range r.keys {
+		quoted = append(quoted, regexp.QuoteMeta(key))
+	}
+	// matches assignments of values to sensitive keys, e.g. label matchers like token="..." or query parameters like
+	// api_key=...
+	r.assignment = regexp.MustCompile(`(?i)([\w.-]*(?:` + strings.Join(quoted, "|") + `)[\w.-]*["']?\s*(?:=~|!=|!~|=|:)\s*)("(?:[^"\\]|\\.)*"|'[^']*'|[^\s,&;)}\]]+)`)
+	return r
+}
+
+func (r *Redactor) orDefault() *Redactor {
+	if r == nil {
+		return defaultRedactor
+	}
+	return r
+}
+
+// IsSensitive returns whether the value of the key has to be redacted
+func (r *Redactor) IsSensitive(key string) bool {
+	key = strings.ToLower(key)
+	for _, sensitive := range r.orDefault().keys {
+		if strings.Contains(key, sensitive) {
+			return true
+		}
+	}
+	return false
+}
+
+// String redacts the values assigned to sensitive keys within the string
+func (r *Redactor) String(s string) string {
+	return r.orDefault().assignment.ReplaceAllStringFunc(s, func(match string) string {
+		groups := r.orDefault().assignment.FindStringSubmatch(match)
+		value := groups[2]
+		if strings.HasPrefix(value, `"`) || strings.HasPrefix(value, `'`) {
+			return groups[1] + value[:1] + Placeholder + value[:1]
+		}
+		return groups[1] + Placeholder
+	})
+}
+
+// Query redacts a query, or hides it completely if it is marked as secure
+func (r *Redactor) Query(query string, secure bool) string {
+	if secure && query != "" {
+		return Placeholder
+	}
+	return r.String(query)
+}
@@ -0,0 +1,64 @@
+package redaction
+
+import (
+	"testing"
+
+	testrequire "github.com/stretchr/testify/require"
+)
+
+func TestRedactor_String(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{
+			name: "prometheus query",
+			in:   `rate(http_requests_total{status="500"}[1m])`,
+			want: `rate(http_requests_total{status="500"}[1m])`,
+		},
+		{
+			name: "label matcher",
+			in:   `up{job="api", token="s3cr3t"}`,
+			want: `up{job="api", token="***"}`,
+		},
+		{
+			name: "query parameters",
+			in:   `/api/v2/metrics?Api-Token=dt0c01.abc&metricSelector=builtin:host.cpu`,
+			want: `/api/v2/metrics?Api-Token=***&metricSelector=builtin:host.cpu`,
+		},
+		{
+			name: "json",
+			in:   `{"user":"keptn","password":"hunter2"}`,
+			want: `{"user":"keptn","password":"***"}`,
+		},
+		{
+			name: "no assignment",
+			in:   `sum(secret_rotations_total)`,
+			want: `sum(secret_rotations_total)`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			testrequire.Equal(t, tt.want, (*Redactor)(nil).String(tt.in))
+		})
+	}
+}
+
+func TestRedactor_Keys(t *testing.T) {
+	r := New([]string{"Tenant"})
+
+	testrequire.True(t, r.IsSensitive("SLACK_TOKEN"))
+	testrequire.True(t, r.IsSensitive("tenant-id"))
+	testrequire.False(t, r.IsSensitive("targetDate"))
+	testrequire.Equal(t, `up{tenant_id="***"}`, r.String(`up{tenant_id="abc"}`))
+	testrequire.Equal(t, `up{tenant_id="abc"}`, New(nil).String(`up{tenant_id="abc"}`))
+}
+
+func TestRedactor_Query(t *testing.T) {
+	r := New(nil)
+
+	testrequire.Equal(t, Placeholder, r.Query(`up{job="api"}`, true))
+	testrequire.Equal(t, `up{job="api"}`, r.Query(`up{job="api"}`, false))
+	testrequire.Equal(t, "", r.Query("", true))
+}
@@ -0,0 +1,186 @@
+package replay
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/go-logr/logr"
+	klcv1alpha1 "github.com/keptn/lifecycle-controller/operator/api/v1alpha1"
+	"github.com/keptn/lifecycle-controller/operator/api/v1alpha1/common"
+	"github.com/keptn/lifecycle-controller/operator/api/v1alpha1/semconv"
+	"github.com/keptn/lifecycle-controller/operator/controllers/reasons"
+	"github.com/keptn/lifecycle-controller/operator/metrics"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const defaultPageSize = 100
+
+// Summary is the number of deployments that have been replayed
+type Summary struct {
+	AppVersions       int
+	WorkloadInstances int
+}
+
+func (s Summary) String() string {
+	return fmt.Sprintf("replayed %d KeptnAppVersions and %d KeptnWorkloadInstances", s.AppVersions, s.WorkloadInstances)
+}
+
+// Replayer re-emits the spans and metrics of completed KeptnAppVersions and KeptnWorkloadInstances from their status,
+// e.g. to fill a new observability backend with the history of the deployments after switching vendors.
+// The spans keep the original start and end times of the deployments, their tasks and evaluations, but get new trace IDs.
+type Replayer struct {
+	// Reader lists the objects without caching them
+	Reader client.Reader
+	Tracer trace.Tracer
+	Meters metrics.Meters
+	Log    logr.Logger
+	// Since limits the replay to the deployments that ended after it. All deployments are replayed if it is zero.
+	Since time.Time
+	// PageSize is the number of objects that are listed at once
+	PageSize int64
+}
+
+//+kubebuilder:rbac:groups=lifecycle.keptn.sh,resources=keptnappversions;keptnworkloadinstances,verbs=get;list
+
+// NeedLeaderElection is true, so that only one replica of the operator replays the deployments
+func (r *Replayer) NeedLeaderElection() bool {
+	return true
+}
+
+// Start replays the deployments in the background of the operator. A failed replay does not stop the operator.
+func (r *Replayer) Start(ctx context.Context) error {
+	summary, err := r.Run(ctx)
+	if err != nil {
+		r.Log.Error(err, "could not replay the deployments", "summary", summary.String())
+		return nil
+	}
+	r.Log.Info(summary.String())
+	return nil
+}
+
+// Run replays all completed deployments. The workload instances of an app version are replayed as children of its span.
+func (r *Replayer) Run(ctx context.Context) (Summary, error) {
+	var summary Summary
+	// the contexts of the app version spans the workload instances of the app versions are replayed in
+	parents := map[string]context.Context{}
+
+	err := r.list(ctx, &klcv1alpha1.KeptnAppVersionList{}, func(list client.ObjectList) {
+		for _, appVersion := range list.(*klcv1alpha1.KeptnAppVersionList).Items {
+			if !r.completed(appVersion.Status.Status, appVersion.Status.EndTime.Time) {
+				continue
+			}
+			appCtx := r.replayAppVersion(ctx, appVersion)
+			for _, w := range appVersion.Spec.Workloads {
+				name := common.CreateInstanceName(common.CreateResourceName(common.MaxK8sObjectLength, appVersion.Spec.AppName, w.Name), w.Version)
+				parents[appVersion.Namespace+"/"+name] = appCtx
+			}
+			summary.AppVersions++
+		}
+	})
+	if err != nil {
+		return summary, err
+	}
+
+	err = r.list(ctx, &klcv1alpha1.KeptnWorkloadInstanceList{}, func(list client.ObjectList) {
+		for _, workloadInstance := range list.(*klcv1alpha1.KeptnWorkloadInstanceList).Items {
+			if !r.completed(workloadInstance.Status.Status, workloadInstance.Status.EndTime.Time) {
+				continue
+			}
+			parent, ok := parents[workloadInstance.Namespace+"/"+workloadInstance.Name]
+			if !ok {
+				parent = ctx
+			}
+			r.replayWorkloadInstance(parent, workloadInstance)
+			summary.WorkloadInstances++
+		}
+	})
+	return summary, err
+}
+
+// list calls replay with each page of the objects of the list type
+func (r *Replayer) list(ctx context.Context, list client.ObjectList, replay func(client.ObjectList)) error {
+	pageSize := r.PageSize
+	if pageSize == 0 {
+		pageSize = defaultPageSize
+	}
+	continueToken := ""
+	for {
+		if err := r.Reader.List(ctx, list, client.Limit(pageSize), client.Continue(continueToken)); err != nil {
+			return fmt.Errorf("could not retrieve deployments to replay: %w", err)
+		}
+		replay(list)
+		continueToken = list.GetContinue()
+		if continueToken == "" {
+			return nil
+		}
+	}
+}
+
+func (r *Replayer) completed(state common.KeptnState, endTime time.Time) bool {
+	return state.IsCompleted() && !endTime.IsZero() && !endTime.Before(r.Since)
+}
+
+func (r *Replayer) replayAppVersion(ctx context.Context, appVersion klcv1alpha1.KeptnAppVersion) context.Context {
+	appCtx, span := r.Tracer.Start(ctx, appVersion.Name, trace.WithSpanKind(trace.SpanKindConsumer), trace.WithTimestamp(appVersion.Status.StartTime.Time))
+	semconv.AddAttributeFromAppVersion(span, appVersion)
+	span.SetAttributes(common.Replayed.Bool(true))
+	r.replayTasks(appCtx, appVersion.Status.PreDeploymentTaskStatus, appVersion.Status.PostDeploymentTaskStatus)
+	r.replayEvaluations(appCtx, appVersion.Status.PreDeploymentEvaluationTaskStatus, appVersion.Status.PostDeploymentEvaluationTaskStatus)
+	endSpan(span, appVersion.Status.Status, appVersion.Status.EndTime.Time)
+
+	attrs := appVersion.GetMetricsAttributes()
+	r.Meters.Add(ctx, metrics.AppCount, 1, attrs...)
+	r.Meters.Record(ctx, metrics.AppDuration, appVersion.Status.EndTime.Sub(appVersion.Status.StartTime.Time).Seconds(), attrs...)
+	return appCtx
+}
+
+func (r *Replayer) replayWorkloadInstance(ctx context.Context, workloadInstance klcv1alpha1.KeptnWorkloadInstance) {
+	workloadCtx, span := r.Tracer.Start(ctx, workloadInstance.Name, trace.WithSpanKind(trace.SpanKindConsumer), trace.WithTimestamp(workloadInstance.Status.StartTime.Time))
+	semconv.AddAttributeFromWorkloadInstance(span, workloadInstance)
+	span.SetAttributes(common.Replayed.Bool(true))
+	r.replayTasks(workloadCtx, workloadInstance.Status.PreDeploymentTaskStatus, workloadInstance.Status.MigrationTaskStatus, workloadInstance.Status.PostDeploymentTaskStatus)
+	r.replayEvaluations(workloadCtx, workloadInstance.Status.PreDeploymentEvaluationTaskStatus, workloadInstance.Status.PostDeploymentEvaluationTaskStatus)
+	endSpan(span, workloadInstance.Status.Status, workloadInstance.Status.EndTime.Time)
+
+	attrs := workloadInstance.GetMetricsAttributes()
+	r.Meters.Add(ctx, metrics.DeploymentCount, 1, attrs...)
+	r.Meters.Record(ctx, metrics.DeploymentDuration, workloadInstance.Status.EndTime.Sub(workloadInstance.Status.StartTime.Time).Seconds(), attrs...)
+}
+
+func (r *Replayer) replayTasks(ctx context.Context, statuses ...[]klcv1alpha1.TaskStatus) {
+	for _, phase := range statuses {
+		for _, task := range phase {
+			if task.StartTime.IsZero() || task.EndTime.IsZero() {
+				continue
+			}
+			_, span := r.Tracer.Start(ctx, task.TaskName, trace.WithSpanKind(trace.SpanKindProducer), trace.WithTimestamp(task.StartTime.Time))
+			span.SetAttributes(common.TaskName.String(task.TaskName), common.TaskStatus.String(string(task.Status)), common.Replayed.Bool(true))
+			endSpan(span, task.Status, task.EndTime.Time)
+		}
+	}
+}
+
+func (r *Replayer) replayEvaluations(ctx context.Context, statuses ...[]klcv1alpha1.EvaluationStatus) {
+	for _, phase := range statuses {
+		for _, evaluation := range phase {
+			if evaluation.StartTime.IsZero() || evaluation.EndTime.IsZero() {
+				continue
+			}
+			_, span := r.Tracer.Start(ctx, evaluation.EvaluationName, trace.WithSpanKind(trace.SpanKindProducer), trace.WithTimestamp(evaluation.StartTime.Time))
+			span.SetAttributes(common.EvaluationName.String(evaluation.EvaluationName), common.EvaluationStatus.String(string(evaluation.Status)), common.Replayed.Bool(true))
+			endSpan(span, evaluation.Status, evaluation.EndTime.Time)
+		}
+	}
+}
+
+func endSpan(span trace.Span, state common.KeptnState, endTime time.Time) {
+	if state.IsFailed() {
+		span.SetStatus(codes.Error, reasons.Failed.Code)
+	} else {
+		span.SetStatus(codes.Ok, string(state))
+	}
+	span.End(trace.WithTimestamp(endTime))
+}
@@ -0,0 +1,91 @@
+package replay
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+	klcv1alpha1 "github.com/keptn/lifecycle-controller/operator/api/v1alpha1"
+	"github.com/keptn/lifecycle-controller/operator/api/v1alpha1/common"
+	"github.com/keptn/lifecycle-controller/operator/metrics"
+	testrequire "github.com/stretchr/testify/require"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestReplayer_Run(t *testing.T) {
+	scheme := runtime.NewScheme()
+	testrequire.Nil(t, klcv1alpha1.AddToScheme(scheme))
+
+	start := time.Date(2022, 11, 8, 12, 0, 0, 0, time.UTC)
+	end := start.Add(5 * time.Minute)
+	appVersion := &klcv1alpha1.KeptnAppVersion{
+		ObjectMeta: metav1.ObjectMeta{Name: "podtato-head-1.0", Namespace: "default"},
+		Spec: klcv1alpha1.KeptnAppVersionSpec{
+			KeptnAppSpec: klcv1alpha1.KeptnAppSpec{
+				Version:   "1.0",
+				Workloads: []klcv1alpha1.KeptnWorkloadRef{{Name: "frontend", Version: "2.0"}},
+			},
+			AppName: "podtato-head",
+		},
+		Status: klcv1alpha1.KeptnAppVersionStatus{
+			Status:    common.StateSucceeded,
+			StartTime: metav1.NewTime(start),
+			EndTime:   metav1.NewTime(end),
+			PreDeploymentTaskStatus: []klcv1alpha1.TaskStatus{
+				{TaskName: "check", Status: common.StateSucceeded, StartTime: metav1.NewTime(start), EndTime: metav1.NewTime(start.Add(time.Minute))},
+				{TaskName: "unfinished", Status: common.StatePending},
+			},
+		},
+	}
+	workloadInstance := func(name string, state common.KeptnState, endTime time.Time) *klcv1alpha1.KeptnWorkloadInstance {
+		return &klcv1alpha1.KeptnWorkloadInstance{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+			Status: klcv1alpha1.KeptnWorkloadInstanceStatus{
+				Status:    state,
+				StartTime: metav1.NewTime(start.Add(time.Minute)),
+				EndTime:   metav1.NewTime(endTime),
+			},
+		}
+	}
+
+	spanRecorder := tracetest.NewSpanRecorder()
+	meters := metrics.NewInMemoryMeters()
+	r := &Replayer{
+		Reader: fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+			appVersion,
+			workloadInstance("podtato-head-frontend-2.0", common.StateSucceeded, end),
+			workloadInstance("standalone-1.0", common.StateFailed, start.Add(2*time.Minute)),
+			workloadInstance("progressing-1.0", common.StateProgressing, time.Time{}),
+			workloadInstance("old-1.0", common.StateSucceeded, start.Add(-time.Hour)),
+		).Build(),
+		Tracer:   sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(spanRecorder)).Tracer("test"),
+		Meters:   meters,
+		Log:      logr.Discard(),
+		Since:    start,
+		PageSize: 1,
+	}
+
+	summary, err := r.Run(context.TODO())
+	testrequire.Nil(t, err)
+	testrequire.Equal(t, Summary{AppVersions: 1, WorkloadInstances: 2}, summary)
+
+	spans := map[string]sdktrace.ReadOnlySpan{}
+	for _, span := range spanRecorder.Ended() {
+		spans[span.Name()] = span
+	}
+	testrequire.Len(t, spans, 4)
+	testrequire.True(t, start.Equal(spans["podtato-head-1.0"].StartTime()))
+	testrequire.True(t, end.Equal(spans["podtato-head-1.0"].EndTime()))
+	testrequire.Equal(t, spans["podtato-head-1.0"].SpanContext().SpanID(), spans["check"].Parent().SpanID())
+	testrequire.Equal(t, spans["podtato-head-1.0"].SpanContext().SpanID(), spans["podtato-head-frontend-2.0"].Parent().SpanID())
+	testrequire.False(t, spans["standalone-1.0"].Parent().IsValid())
+
+	testrequire.Equal(t, 1.0, meters.Sum(string(metrics.AppCount)))
+	testrequire.Equal(t, 300.0, meters.Sum(string(metrics.AppDuration)))
+	testrequire.Equal(t, 2.0, meters.Sum(string(metrics.DeploymentCount)))
+}
@@ -0,0 +1,142 @@
+package settings
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/yaml"
+)
+
+// StatusKey is the key of the effective configuration in the status ConfigMap
+const StatusKey = "effective.yaml"
+
+// Reloader watches the config file for changes, applies the changes of flags that can be changed while the operator is
+// running, and reports the effective configuration in a ConfigMap
+type Reloader struct {
+	Reader client.Reader
+	Writer client.Writer
+	Log    logr.Logger
+	// Path is the path of the config file
+	Path string
+	// Interval is the interval in which the config file is read. ConfigMaps mounted as volume are updated by the kubelet
+	// with a delay of up to a minute, so the interval does not need to be short.
+	Interval time.Duration
+	// Status is the ConfigMap the effective configuration is reported in
+	Status types.NamespacedName
+	// Live contains the handlers applying the new value of the flags that can be changed while the operator is running
+	Live map[string]func(value string) error
+	// Applied is the config file the operator has been started with
+	Applied *File
+	// Effective is the configuration the operator has been started with
+	Effective Effective
+}
+
+//+kubebuilder:rbac:groups=core,resources=configmaps,verbs=get;create;update
+
+// NeedLeaderElection is false, since every replica of the operator has to apply the changes of its config
+func (r *Reloader) NeedLeaderElection() bool {
+	return false
+}
+
+// Start reports the effective configuration and reloads the config file until the context is done
+func (r *Reloader) Start(ctx context.Context) error {
+	r.report(ctx)
+	ticker := time.NewTicker(r.Interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			if r.reload() {
+				r.report(ctx)
+			}
+		}
+	}
+}
+
+// reload reads the config file and applies the changes since the last reload. It returns whether the effective
+// configuration has changed.
+func (r *Reloader) reload() bool {
+	file, err := Read(r.Path)
+	if err != nil {
+		if r.Effective.Error == err.Error() {
+			return false
+		}
+		r.Log.Error(err, "could not reload config file, keeping the current configuration")
+		r.Effective.Error = err.Error()
+		return true
+	}
+	hadError := r.Effective.Error != ""
+	r.Effective.Error = ""
+
+	pending := map[string]bool{}
+	for _, name := range r.Effective.PendingRestart {
+		pending[name] = true
+	}
+	flags := changed(r.Applied.Flags, file.Flags)
+	for _, name := range flags {
+		value, ok := file.Flags[name]
+		apply, live := r.Live[name]
+		switch {
+		case r.Effective.Flags[name].Source == SourceCommandLine:
+			r.Log.Info("flag " + name + " of the config file is overridden on the command line")
+		case live && ok:
+			if err := apply(fmt.Sprint(value)); err != nil {
+				r.Log.Error(err, "could not apply flag "+name+" of the config file")
+				r.Effective.Error = err.Error()
+				continue
+			}
+			r.Log.Info("applied flag " + name + " of the config file")
+			r.Effective.Flags[name] = Value{Value: fmt.Sprint(value), Source: SourceConfig}
+		default:
+			r.Log.Info("flag " + name + " of the config file has changed, it takes effect after a restart of the operator")
+			pending["--"+name] = true
+		}
+	}
+	env := changed(r.Applied.Env, file.Env)
+	for _, name := range env {
+		r.Log.Info("environment variable " + name + " of the config file has changed, it takes effect after a restart of the operator")
+		pending[name] = true
+	}
+	r.Effective.PendingRestart = make([]string, 0, len(pending))
+	for name := range pending {
+		r.Effective.PendingRestart = append(r.Effective.PendingRestart, name)
+	}
+	sort.Strings(r.Effective.PendingRestart)
+
+	r.Applied = file
+	return hadError || len(flags) > 0 || len(env) > 0
+}
+
+// report writes the effective configuration to the status ConfigMap
+func (r *Reloader) report(ctx context.Context) {
+	data, err := yaml.Marshal(r.Effective)
+	if err != nil {
+		r.Log.Error(err, "could not marshal effective configuration")
+		return
+	}
+	status := &corev1.ConfigMap{}
+	err = r.Reader.Get(ctx, r.Status, status)
+	if errors.IsNotFound(err) {
+		status = &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: r.Status.Name, Namespace: r.Status.Namespace},
+			Data:       map[string]string{StatusKey: string(data)},
+		}
+		err = r.Writer.Create(ctx, status)
+	} else if err == nil {
+		status.Data = map[string]string{StatusKey: string(data)}
+		err = r.Writer.Update(ctx, status)
+	}
+	if err != nil {
+		r.Log.Error(err, "could not report effective configuration in ConfigMap "+r.Status.Name)
+	}
+}
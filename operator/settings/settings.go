@@ -0,0 +1,139 @@
+package settings
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"reflect"
+	"sort"
+
+	"sigs.k8s.io/yaml"
+)
+
+// Source defines where the effective value of a setting comes from
+type Source string
+
+const (
+	// SourceDefault is the default value of a flag
+	SourceDefault Source = "default"
+	// SourceCommandLine values are set with command line flags or environment variables of the container,
+	// which take precedence over the config file
+	SourceCommandLine Source = "command-line"
+	// SourceConfig values are set in the config file
+	SourceConfig Source = "config"
+)
+
+// File is the configuration of the operator, which is mounted from a ConfigMap, e.g. rendered from the values of a
+// Helm chart. It can set each command line flag and environment variable of the operator.
+type File struct {
+	// Flags contains the values of command line flags by name, e.g. hibernate-idle-namespaces: true
+	Flags map[string]interface{} `json:"flags,omitempty"`
+	// Env contains the values of environment variables by name, e.g. PROVIDER_PROBE_INTERVAL: 30s
+	Env map[string]interface{} `json:"env,omitempty"`
+}
+
+// Value is the effective value of a setting
+type Value struct {
+	Value  string `json:"value"`
+	Source Source `json:"source"`
+}
+
+// Effective is the configuration the operator runs with
+type Effective struct {
+	Flags map[string]Value `json:"flags"`
+	Env   map[string]Value `json:"env,omitempty"`
+	// PendingRestart lists the settings that have been changed in the config file since the start of the operator,
+	// but cannot be changed while it is running
+	PendingRestart []string `json:"pendingRestart,omitempty"`
+	// Error is the reason the config file could not be reloaded
+	Error string `json:"error,omitempty"`
+}
+
+// Read reads the config file. A missing file results in an empty configuration.
+func Read(path string) (*File, error) {
+	file := &File{}
+	if path == "" {
+		return file, nil
+	}
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return file, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("could not read config file %s: %w", path, err)
+	}
+	if err := yaml.UnmarshalStrict(data, file); err != nil {
+		return nil, fmt.Errorf("could not parse config file %s: %w", path, err)
+	}
+	return file, nil
+}
+
+// Apply sets the flags and environment variables of the file which have not been set on the command line or in the
+// environment of the container, and returns the resulting configuration
+func (f *File) Apply(fs *flag.FlagSet) (Effective, error) {
+	setOnCommandLine := map[string]bool{}
+	fs.Visit(func(fl *flag.Flag) {
+		setOnCommandLine[fl.Name] = true
+	})
+
+	effective := Effective{Flags: map[string]Value{}, Env: map[string]Value{}}
+	for _, name := range sortedKeys(f.Flags) {
+		if fs.Lookup(name) == nil {
+			return effective, fmt.Errorf("unknown flag %s in config file", name)
+		}
+		if setOnCommandLine[name] {
+			continue
+		}
+		if err := fs.Set(name, fmt.Sprint(f.Flags[name])); err != nil {
+			return effective, fmt.Errorf("invalid value of flag %s in config file: %w", name, err)
+		}
+		effective.Flags[name] = Value{Source: SourceConfig}
+	}
+	fs.VisitAll(func(fl *flag.Flag) {
+		source := SourceDefault
+		if setOnCommandLine[fl.Name] {
+			source = SourceCommandLine
+		} else if _, ok := effective.Flags[fl.Name]; ok {
+			source = SourceConfig
+		}
+		effective.Flags[fl.Name] = Value{Value: fl.Value.String(), Source: source}
+	})
+
+	for _, name := range sortedKeys(f.Env) {
+		if value, ok := os.LookupEnv(name); ok {
+			effective.Env[name] = Value{Value: value, Source: SourceCommandLine}
+			continue
+		}
+		value := fmt.Sprint(f.Env[name])
+		if err := os.Setenv(name, value); err != nil {
+			return effective, fmt.Errorf("could not set environment variable %s: %w", name, err)
+		}
+		effective.Env[name] = Value{Value: value, Source: SourceConfig}
+	}
+	return effective, nil
+}
+
+// changed returns the names of the flags and environment variables whose values differ between the files
+func changed(old map[string]interface{}, new map[string]interface{}) []string {
+	var names []string
+	for name, value := range new {
+		if oldValue, ok := old[name]; !ok || !reflect.DeepEqual(oldValue, value) {
+			names = append(names, name)
+		}
+	}
+	for name := range old {
+		if _, ok := new[name]; !ok {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+func sortedKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,94 @@
+package settings
+
+import (
+	"context"
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+	testrequire "github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/yaml"
+)
+
+func TestFile_Apply(t *testing.T) {
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	hibernate := fs.Bool("hibernate-idle-namespaces", false, "")
+	offline := fs.Bool("offline", false, "")
+	dashboard := fs.String("dashboard-bind-address", "", "")
+	testrequire.Nil(t, fs.Parse([]string{"--dashboard-bind-address=:8082"}))
+	t.Setenv("EVENT_BUS_TOPIC", "keptn")
+
+	file := &File{
+		Flags: map[string]interface{}{"hibernate-idle-namespaces": true, "dashboard-bind-address": ":9090"},
+		Env:   map[string]interface{}{"EVENT_BUS_TOPIC": "other", "PROVIDER_PROBE_INTERVAL": "30s"},
+	}
+	os.Unsetenv("PROVIDER_PROBE_INTERVAL")
+	defer os.Unsetenv("PROVIDER_PROBE_INTERVAL")
+
+	effective, err := file.Apply(fs)
+	testrequire.Nil(t, err)
+	testrequire.True(t, *hibernate)
+	testrequire.False(t, *offline)
+	testrequire.Equal(t, ":8082", *dashboard)
+	testrequire.Equal(t, "30s", os.Getenv("PROVIDER_PROBE_INTERVAL"))
+	testrequire.Equal(t, "keptn", os.Getenv("EVENT_BUS_TOPIC"))
+
+	testrequire.Equal(t, Value{Value: "true", Source: SourceConfig}, effective.Flags["hibernate-idle-namespaces"])
+	testrequire.Equal(t, Value{Value: "false", Source: SourceDefault}, effective.Flags["offline"])
+	testrequire.Equal(t, Value{Value: ":8082", Source: SourceCommandLine}, effective.Flags["dashboard-bind-address"])
+	testrequire.Equal(t, Value{Value: "keptn", Source: SourceCommandLine}, effective.Env["EVENT_BUS_TOPIC"])
+
+	_, err = (&File{Flags: map[string]interface{}{"unknown": true}}).Apply(fs)
+	testrequire.NotNil(t, err)
+}
+
+func TestReloader(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	testrequire.Nil(t, os.WriteFile(path, []byte("flags:\n  zap-log-level: info\n  offline: false\n"), 0600))
+
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.String("zap-log-level", "debug", "")
+	fs.Bool("offline", false, "")
+	file, err := Read(path)
+	testrequire.Nil(t, err)
+	effective, err := file.Apply(fs)
+	testrequire.Nil(t, err)
+
+	level := ""
+	c := fake.NewClientBuilder().Build()
+	r := &Reloader{
+		Reader:    c,
+		Writer:    c,
+		Log:       logr.Discard(),
+		Path:      path,
+		Interval:  time.Minute,
+		Status:    types.NamespacedName{Namespace: "keptn", Name: "keptn-operator-config-status"},
+		Live:      map[string]func(string) error{"zap-log-level": func(value string) error { level = value; return nil }},
+		Applied:   file,
+		Effective: effective,
+	}
+	testrequire.False(t, r.reload())
+
+	testrequire.Nil(t, os.WriteFile(path, []byte("flags:\n  zap-log-level: error\n  offline: true\n"), 0600))
+	testrequire.True(t, r.reload())
+	testrequire.Equal(t, "error", level)
+	testrequire.Equal(t, []string{"--offline"}, r.Effective.PendingRestart)
+
+	testrequire.Nil(t, os.WriteFile(path, []byte("flags: [\n"), 0600))
+	testrequire.True(t, r.reload())
+	testrequire.NotEmpty(t, r.Effective.Error)
+
+	r.report(context.TODO())
+	status := &corev1.ConfigMap{}
+	testrequire.Nil(t, c.Get(context.TODO(), r.Status, status))
+	reported := Effective{}
+	testrequire.Nil(t, yaml.Unmarshal([]byte(status.Data[StatusKey]), &reported))
+	testrequire.Equal(t, Value{Value: "error", Source: SourceConfig}, reported.Flags["zap-log-level"])
+	testrequire.Equal(t, r.Effective.Error, reported.Error)
+}
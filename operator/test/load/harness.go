@@ -0,0 +1,186 @@
+package load
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	klcv1alpha1 "github.com/keptn/lifecycle-controller/operator/api/v1alpha1"
+	"github.com/keptn/lifecycle-controller/operator/api/v1alpha1/common"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// Config defines the synthetic load generated by the harness
+type Config struct {
+	// Namespace is created for the synthetic objects
+	Namespace string
+	// Apps is the number of KeptnApps that are created
+	Apps int
+	// WorkloadsPerApp is the number of KeptnWorkloads that are created for each KeptnApp
+	WorkloadsPerApp int
+	// Timeout is the maximum time to wait for the operator to create all instances
+	Timeout time.Duration
+	// PollInterval is the interval in which the harness checks for new instances
+	PollInterval time.Duration
+}
+
+// Harness generates synthetic KeptnApps and KeptnWorkloads and measures how the operator handles them
+type Harness struct {
+	Client client.Client
+	Config Config
+	// MetricsURL is the URL of the Prometheus endpoint of the operator
+	MetricsURL string
+	// OwnRequests returns the number of API requests sent by the harness itself, which are subtracted from the
+	// requests of the operator if both run in the same process. It is nil otherwise.
+	OwnRequests func() float64
+}
+
+// Run creates the synthetic objects, waits until the operator has created a KeptnAppVersion for each KeptnApp and a
+// KeptnWorkloadInstance for each KeptnWorkload, and reports the latencies, reconciliations, API requests and memory
+func (h *Harness) Run(ctx context.Context) (*Report, error) {
+	ns := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: h.Config.Namespace}}
+	if err := h.Client.Create(ctx, ns); err != nil && !errors.IsAlreadyExists(err) {
+		return nil, fmt.Errorf("could not create namespace %s: %w", h.Config.Namespace, err)
+	}
+
+	before, err := Scrape(ctx, h.MetricsURL)
+	if err != nil {
+		return nil, err
+	}
+	ownRequestsBefore := h.ownRequests()
+	start := time.Now()
+
+	appsCreated, workloadsCreated, err := h.generate(ctx)
+	if err != nil {
+		return nil, err
+	}
+	appVersionLatencies, workloadInstanceLatencies, err := h.waitForInstances(ctx, appsCreated, workloadsCreated)
+	if err != nil {
+		return nil, err
+	}
+
+	duration := time.Since(start)
+	ownRequests := h.ownRequests() - ownRequestsBefore
+	after, err := Scrape(ctx, h.MetricsURL)
+	if err != nil {
+		return nil, err
+	}
+
+	report := newReport(len(appsCreated), len(workloadsCreated), duration, before, after, ownRequests)
+	report.AppVersionLatencies = appVersionLatencies
+	report.WorkloadInstanceLatencies = workloadInstanceLatencies
+	return report, nil
+}
+
+// Cleanup deletes the namespace of the synthetic objects
+func (h *Harness) Cleanup(ctx context.Context) error {
+	ns := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: h.Config.Namespace}}
+	if err := h.Client.Delete(ctx, ns); err != nil && !errors.IsNotFound(err) {
+		return fmt.Errorf("could not delete namespace %s: %w", h.Config.Namespace, err)
+	}
+	return nil
+}
+
+func (h *Harness) ownRequests() float64 {
+	if h.OwnRequests == nil {
+		return 0
+	}
+	return h.OwnRequests()
+}
+
+// generate creates the KeptnWorkloads and KeptnApps and returns when each of them has been created by name
+func (h *Harness) generate(ctx context.Context) (map[string]time.Time, map[string]time.Time, error) {
+	appsCreated := map[string]time.Time{}
+	workloadsCreated := map[string]time.Time{}
+	for i := 0; i < h.Config.Apps; i++ {
+		app := &klcv1alpha1.KeptnApp{
+			ObjectMeta: metav1.ObjectMeta{Name: fmt.Sprintf("app-%d", i), Namespace: h.Config.Namespace},
+			Spec:       klcv1alpha1.KeptnAppSpec{Version: "1.0.0"},
+		}
+		for j := 0; j < h.Config.WorkloadsPerApp; j++ {
+			name := fmt.Sprintf("workload-%d", j)
+			app.Spec.Workloads = append(app.Spec.Workloads, klcv1alpha1.KeptnWorkloadRef{Name: name, Version: "1.0.0"})
+
+			workload := &klcv1alpha1.KeptnWorkload{
+				ObjectMeta: metav1.ObjectMeta{Name: common.CreateResourceName(common.MaxK8sObjectLength, app.Name, name), Namespace: h.Config.Namespace},
+				Spec: klcv1alpha1.KeptnWorkloadSpec{
+					AppName: app.Name,
+					Version: "1.0.0",
+					ResourceReference: klcv1alpha1.ResourceReference{
+						UID:  types.UID(fmt.Sprintf("loadtest-%d-%d", i, j)),
+						Kind: "ReplicaSet",
+					},
+				},
+			}
+			if err := h.Client.Create(ctx, workload); err != nil {
+				return nil, nil, fmt.Errorf("could not create KeptnWorkload %s: %w", workload.Name, err)
+			}
+			workloadsCreated[workload.Name] = time.Now()
+		}
+		if err := h.Client.Create(ctx, app); err != nil {
+			return nil, nil, fmt.Errorf("could not create KeptnApp %s: %w", app.Name, err)
+		}
+		appsCreated[app.Name] = time.Now()
+	}
+	return appsCreated, workloadsCreated, nil
+}
+
+// waitForInstances polls the KeptnAppVersions and KeptnWorkloadInstances until all of them exist, and returns the
+// durations from the creation of their KeptnApps and KeptnWorkloads
+func (h *Harness) waitForInstances(ctx context.Context, appsCreated map[string]time.Time, workloadsCreated map[string]time.Time) (Latencies, Latencies, error) {
+	ctx, cancel := context.WithTimeout(ctx, h.Config.Timeout)
+	defer cancel()
+
+	appVersionsSeen := map[string]time.Duration{}
+	workloadInstancesSeen := map[string]time.Duration{}
+	for {
+		appVersions := &klcv1alpha1.KeptnAppVersionList{}
+		if err := h.Client.List(ctx, appVersions, client.InNamespace(h.Config.Namespace)); err != nil {
+			return nil, nil, fmt.Errorf("could not retrieve KeptnAppVersions: %w", err)
+		}
+		now := time.Now()
+		for _, appVersion := range appVersions.Items {
+			if created, ok := appsCreated[appVersion.Spec.AppName]; ok {
+				if _, seen := appVersionsSeen[appVersion.Spec.AppName]; !seen {
+					appVersionsSeen[appVersion.Spec.AppName] = now.Sub(created)
+				}
+			}
+		}
+
+		workloadInstances := &klcv1alpha1.KeptnWorkloadInstanceList{}
+		if err := h.Client.List(ctx, workloadInstances, client.InNamespace(h.Config.Namespace)); err != nil {
+			return nil, nil, fmt.Errorf("could not retrieve KeptnWorkloadInstances: %w", err)
+		}
+		now = time.Now()
+		for _, workloadInstance := range workloadInstances.Items {
+			if created, ok := workloadsCreated[workloadInstance.Spec.WorkloadName]; ok {
+				if _, seen := workloadInstancesSeen[workloadInstance.Spec.WorkloadName]; !seen {
+					workloadInstancesSeen[workloadInstance.Spec.WorkloadName] = now.Sub(created)
+				}
+			}
+		}
+
+		if len(appVersionsSeen) == len(appsCreated) && len(workloadInstancesSeen) == len(workloadsCreated) {
+			return latencies(appVersionsSeen), latencies(workloadInstancesSeen), nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, nil, fmt.Errorf("only %d of %d KeptnAppVersions and %d of %d KeptnWorkloadInstances have been created within %s",
+				len(appVersionsSeen), len(appsCreated), len(workloadInstancesSeen), len(workloadsCreated), h.Config.Timeout)
+		case <-time.After(h.Config.PollInterval):
+		}
+	}
+}
+
+func latencies(seen map[string]time.Duration) Latencies {
+	result := make(Latencies, 0, len(seen))
+	for _, latency := range seen {
+		result = append(result, latency)
+	}
+	return result
+}
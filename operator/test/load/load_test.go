@@ -0,0 +1,182 @@
+//go:build loadtest
+
+package load
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	klcv1alpha1 "github.com/keptn/lifecycle-controller/operator/api/v1alpha1"
+	"github.com/keptn/lifecycle-controller/operator/controllers/keptnapp"
+	"github.com/keptn/lifecycle-controller/operator/controllers/keptnappversion"
+	"github.com/keptn/lifecycle-controller/operator/controllers/keptnworkload"
+	"github.com/keptn/lifecycle-controller/operator/controllers/keptnworkloadinstance"
+	"github.com/keptn/lifecycle-controller/operator/metrics"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	testrequire "github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/sdk/metric"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/rest"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/envtest"
+	ctrlmetrics "sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+// TestLoad generates synthetic apps and workloads and prints a report of how the operator handled them.
+// By default, the controllers run in-process against envtest. With USE_EXISTING_CLUSTER=true, the load is generated
+// in the cluster of the current kubeconfig, e.g. kind, and LOADTEST_METRICS_URL has to point to the metrics endpoint
+// of the operator deployed there.
+func TestLoad(t *testing.T) {
+	config := Config{
+		Namespace:       getEnv("LOADTEST_NAMESPACE", "keptn-loadtest"),
+		Apps:            getEnvInt(t, "LOADTEST_APPS", 50),
+		WorkloadsPerApp: getEnvInt(t, "LOADTEST_WORKLOADS_PER_APP", 2),
+		Timeout:         getEnvDuration(t, "LOADTEST_TIMEOUT", 10*time.Minute),
+		PollInterval:    500 * time.Millisecond,
+	}
+	existingCluster := os.Getenv("USE_EXISTING_CLUSTER") == "true"
+
+	testEnv := &envtest.Environment{
+		CRDDirectoryPaths:     []string{filepath.Join("..", "..", "config", "crd", "bases")},
+		ErrorIfCRDPathMissing: true,
+		UseExistingCluster:    &existingCluster,
+	}
+	cfg, err := testEnv.Start()
+	testrequire.Nil(t, err)
+	defer func() {
+		testrequire.Nil(t, testEnv.Stop())
+	}()
+
+	scheme := runtime.NewScheme()
+	testrequire.Nil(t, clientgoscheme.AddToScheme(scheme))
+	testrequire.Nil(t, klcv1alpha1.AddToScheme(scheme))
+
+	// the requests of the harness are counted, so that they are not attributed to the operator running in the same process
+	var ownRequests int64
+	harnessConfig := rest.CopyConfig(cfg)
+	harnessConfig.WrapTransport = func(rt http.RoundTripper) http.RoundTripper {
+		return &countingTransport{next: rt, count: &ownRequests}
+	}
+	c, err := client.New(harnessConfig, client.Options{Scheme: scheme})
+	testrequire.Nil(t, err)
+
+	harness := &Harness{Client: c, Config: config, MetricsURL: os.Getenv("LOADTEST_METRICS_URL")}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	if existingCluster {
+		if harness.MetricsURL == "" {
+			t.Fatal("LOADTEST_METRICS_URL has to be set to the metrics endpoint of the operator, e.g. http://localhost:8080/metrics")
+		}
+	} else {
+		mgr, err := ctrl.NewManager(cfg, ctrl.Options{Scheme: scheme, MetricsBindAddress: "0"})
+		testrequire.Nil(t, err)
+		setupControllers(t, mgr)
+		go func() {
+			if err := mgr.Start(ctx); err != nil {
+				t.Error(err)
+			}
+		}()
+
+		server := httptest.NewServer(promhttp.HandlerFor(ctrlmetrics.Registry, promhttp.HandlerOpts{}))
+		defer server.Close()
+		harness.MetricsURL = server.URL
+		harness.OwnRequests = func() float64 {
+			return float64(atomic.LoadInt64(&ownRequests))
+		}
+	}
+
+	report, err := harness.Run(ctx)
+	testrequire.Nil(t, harness.Cleanup(context.Background()))
+	testrequire.Nil(t, err)
+	t.Log("\n" + report.String())
+
+	if maxLatency := getEnvDuration(t, "LOADTEST_MAX_P95_LATENCY", 0); maxLatency > 0 {
+		testrequire.LessOrEqual(t, report.AppVersionLatencies.Percentile(95), maxLatency, "p95 latency of KeptnAppVersions")
+		testrequire.LessOrEqual(t, report.WorkloadInstanceLatencies.Percentile(95), maxLatency, "p95 latency of KeptnWorkloadInstances")
+	}
+}
+
+// setupControllers registers the controllers handling KeptnApps and KeptnWorkloads and their instances
+func setupControllers(t *testing.T, mgr ctrl.Manager) {
+	meters, err := metrics.NewOTelMeters(metric.NewMeterProvider().Meter("keptn/loadtest"), metrics.Config{})
+	testrequire.Nil(t, err)
+
+	testrequire.Nil(t, (&keptnapp.KeptnAppReconciler{
+		Client:   mgr.GetClient(),
+		Scheme:   mgr.GetScheme(),
+		Log:      ctrl.Log.WithName("KeptnApp Controller"),
+		Recorder: mgr.GetEventRecorderFor("keptnapp-controller"),
+		Tracer:   otel.Tracer("keptn/operator/app"),
+	}).SetupWithManager(mgr))
+	testrequire.Nil(t, (&keptnworkload.KeptnWorkloadReconciler{
+		Client:   mgr.GetClient(),
+		Scheme:   mgr.GetScheme(),
+		Log:      ctrl.Log.WithName("KeptnWorkload Controller"),
+		Recorder: mgr.GetEventRecorderFor("keptnworkload-controller"),
+		Tracer:   otel.Tracer("keptn/operator/workload"),
+	}).SetupWithManager(mgr))
+	testrequire.Nil(t, (&keptnappversion.KeptnAppVersionReconciler{
+		Client:   mgr.GetClient(),
+		Scheme:   mgr.GetScheme(),
+		Log:      ctrl.Log.WithName("KeptnAppVersion Controller"),
+		Recorder: mgr.GetEventRecorderFor("keptnappversion-controller"),
+		Tracer:   otel.Tracer("keptn/operator/appversion"),
+		Meters:   meters,
+	}).SetupWithManager(mgr))
+	testrequire.Nil(t, (&keptnworkloadinstance.KeptnWorkloadInstanceReconciler{
+		Client:   mgr.GetClient(),
+		Scheme:   mgr.GetScheme(),
+		Log:      ctrl.Log.WithName("KeptnWorkloadInstance Controller"),
+		Recorder: mgr.GetEventRecorderFor("keptnworkloadinstance-controller"),
+		Tracer:   otel.Tracer("keptn/operator/workloadinstance"),
+		Meters:   meters,
+	}).SetupWithManager(mgr))
+}
+
+type countingTransport struct {
+	next  http.RoundTripper
+	count *int64
+}
+
+func (t *countingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	atomic.AddInt64(t.count, 1)
+	return t.next.RoundTrip(req)
+}
+
+func getEnv(name string, defaultValue string) string {
+	if value := os.Getenv(name); value != "" {
+		return value
+	}
+	return defaultValue
+}
+
+func getEnvInt(t *testing.T, name string, defaultValue int) int {
+	value := os.Getenv(name)
+	if value == "" {
+		return defaultValue
+	}
+	i, err := strconv.Atoi(value)
+	testrequire.Nil(t, err, name)
+	return i
+}
+
+func getEnvDuration(t *testing.T, name string, defaultValue time.Duration) time.Duration {
+	value := os.Getenv(name)
+	if value == "" {
+		return defaultValue
+	}
+	d, err := time.ParseDuration(value)
+	testrequire.Nil(t, err, name)
+	return d
+}
@@ -0,0 +1,94 @@
+package load
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/prometheus/common/expfmt"
+)
+
+const (
+	reconcileTimeMetric  = "controller_runtime_reconcile_time_seconds"
+	apiRequestsMetric    = "rest_client_requests_total"
+	residentMemoryMetric = "process_resident_memory_bytes"
+	heapMemoryMetric     = "go_memstats_heap_alloc_bytes"
+)
+
+// Sample is a single value of a metric of the operator
+type Sample struct {
+	Labels map[string]string
+	Value  float64
+}
+
+// Samples are the values of the metrics of the operator by name. The sum and count of histograms are stored as
+// <name>_sum and <name>_count, like in the Prometheus text format.
+type Samples map[string][]Sample
+
+// Sum returns the sum of the values of the metric, optionally filtered by the value of a label
+func (s Samples) Sum(name string, label string, value string) float64 {
+	sum := 0.0
+	for _, sample := range s[name] {
+		if label == "" || sample.Labels[label] == value {
+			sum += sample.Value
+		}
+	}
+	return sum
+}
+
+// LabelValues returns the distinct values of the label of the metric
+func (s Samples) LabelValues(name string, label string) []string {
+	seen := map[string]bool{}
+	var values []string
+	for _, sample := range s[name] {
+		if v := sample.Labels[label]; !seen[v] {
+			seen[v] = true
+			values = append(values, v)
+		}
+	}
+	return values
+}
+
+// Scrape reads the metrics of the operator from its Prometheus endpoint
+func Scrape(ctx context.Context, url string) (Samples, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("could not create request for %s: %w", url, err)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("could not scrape metrics from %s: %w", url, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("could not scrape metrics from %s: unexpected status %s", url, resp.Status)
+	}
+
+	parser := expfmt.TextParser{}
+	families, err := parser.TextToMetricFamilies(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("could not parse metrics from %s: %w", url, err)
+	}
+
+	samples := Samples{}
+	for name, family := range families {
+		for _, metric := range family.GetMetric() {
+			labels := map[string]string{}
+			for _, pair := range metric.GetLabel() {
+				labels[pair.GetName()] = pair.GetValue()
+			}
+			switch {
+			case metric.Counter != nil:
+				samples[name] = append(samples[name], Sample{Labels: labels, Value: metric.GetCounter().GetValue()})
+			case metric.Gauge != nil:
+				samples[name] = append(samples[name], Sample{Labels: labels, Value: metric.GetGauge().GetValue()})
+			case metric.Untyped != nil:
+				samples[name] = append(samples[name], Sample{Labels: labels, Value: metric.GetUntyped().GetValue()})
+			case metric.Histogram != nil:
+				samples[name+"_sum"] = append(samples[name+"_sum"], Sample{Labels: labels, Value: metric.GetHistogram().GetSampleSum()})
+				samples[name+"_count"] = append(samples[name+"_count"], Sample{Labels: labels, Value: float64(metric.GetHistogram().GetSampleCount())})
+			}
+		}
+	}
+	return samples, nil
+}
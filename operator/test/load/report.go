@@ -0,0 +1,111 @@
+package load
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+)
+
+// Latencies are the durations until the operator created the instances of the synthetic objects
+type Latencies []time.Duration
+
+// Percentile returns the smallest latency that is greater than or equal to the given percentage of the latencies
+func (l Latencies) Percentile(p float64) time.Duration {
+	if len(l) == 0 {
+		return 0
+	}
+	sorted := append(Latencies{}, l...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+	index := int(float64(len(sorted))*p/100+0.5) - 1
+	if index < 0 {
+		index = 0
+	} else if index >= len(sorted) {
+		index = len(sorted) - 1
+	}
+	return sorted[index]
+}
+
+// ReconcileStats summarizes the reconciliations of a controller during the load test
+type ReconcileStats struct {
+	Controller string
+	Count      float64
+	Average    time.Duration
+}
+
+// Report is the result of a load test
+type Report struct {
+	Apps      int
+	Workloads int
+	Duration  time.Duration
+	// AppVersionLatencies are the durations from the creation of the KeptnApps until their KeptnAppVersions exist
+	AppVersionLatencies Latencies
+	// WorkloadInstanceLatencies are the durations from the creation of the KeptnWorkloads until their KeptnWorkloadInstances exist
+	WorkloadInstanceLatencies Latencies
+	Reconciles                []ReconcileStats
+	// APIRequests is the number of requests the operator sent to the API server
+	APIRequests float64
+	// MemoryBytes is the memory used by the operator at the end of the load test
+	MemoryBytes float64
+}
+
+// QPS returns the average number of API requests per second the operator sent during the load test
+func (r Report) QPS() float64 {
+	if r.Duration <= 0 {
+		return 0
+	}
+	return r.APIRequests / r.Duration.Seconds()
+}
+
+func newReport(apps, workloads int, duration time.Duration, before, after Samples, ownRequests float64) *Report {
+	report := &Report{
+		Apps:        apps,
+		Workloads:   workloads,
+		Duration:    duration,
+		APIRequests: after.Sum(apiRequestsMetric, "", "") - before.Sum(apiRequestsMetric, "", "") - ownRequests,
+		MemoryBytes: after.Sum(residentMemoryMetric, "", ""),
+	}
+	if report.MemoryBytes == 0 {
+		report.MemoryBytes = after.Sum(heapMemoryMetric, "", "")
+	}
+
+	controllers := after.LabelValues(reconcileTimeMetric+"_count", "controller")
+	sort.Strings(controllers)
+	for _, controller := range controllers {
+		count := after.Sum(reconcileTimeMetric+"_count", "controller", controller) - before.Sum(reconcileTimeMetric+"_count", "controller", controller)
+		if count <= 0 {
+			continue
+		}
+		sum := after.Sum(reconcileTimeMetric+"_sum", "controller", controller) - before.Sum(reconcileTimeMetric+"_sum", "controller", controller)
+		report.Reconciles = append(report.Reconciles, ReconcileStats{
+			Controller: controller,
+			Count:      count,
+			Average:    time.Duration(sum / count * float64(time.Second)),
+		})
+	}
+	return report
+}
+
+func (r Report) String() string {
+	b := &strings.Builder{}
+	fmt.Fprintf(b, "%d apps, %d workloads in %s\n\n", r.Apps, r.Workloads, r.Duration.Round(time.Millisecond))
+
+	w := tabwriter.NewWriter(b, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "INSTANCE\tP50\tP95\tMAX")
+	fmt.Fprintf(w, "KeptnAppVersion\t%s\t%s\t%s\n", r.AppVersionLatencies.Percentile(50), r.AppVersionLatencies.Percentile(95), r.AppVersionLatencies.Percentile(100))
+	fmt.Fprintf(w, "KeptnWorkloadInstance\t%s\t%s\t%s\n", r.WorkloadInstanceLatencies.Percentile(50), r.WorkloadInstanceLatencies.Percentile(95), r.WorkloadInstanceLatencies.Percentile(100))
+	w.Flush()
+
+	fmt.Fprintln(b)
+	w = tabwriter.NewWriter(b, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "CONTROLLER\tRECONCILES\tAVERAGE")
+	for _, stats := range r.Reconciles {
+		fmt.Fprintf(w, "%s\t%.0f\t%s\n", stats.Controller, stats.Count, stats.Average)
+	}
+	w.Flush()
+
+	fmt.Fprintf(b, "\nAPI requests: %.0f (%.1f/s)\n", r.APIRequests, r.QPS())
+	fmt.Fprintf(b, "Memory: %.1f MiB\n", r.MemoryBytes/(1<<20))
+	return b.String()
+}
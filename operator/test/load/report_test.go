@@ -0,0 +1,55 @@
+package load
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	testrequire "github.com/stretchr/testify/require"
+)
+
+func TestLatencies_Percentile(t *testing.T) {
+	var latencies Latencies
+	testrequire.Zero(t, latencies.Percentile(95))
+
+	for i := 10; i > 0; i-- {
+		latencies = append(latencies, time.Duration(i)*time.Second)
+	}
+	testrequire.Equal(t, 5*time.Second, latencies.Percentile(50))
+	testrequire.Equal(t, 10*time.Second, latencies.Percentile(95))
+	testrequire.Equal(t, 10*time.Second, latencies.Percentile(100))
+	testrequire.Equal(t, time.Second, latencies.Percentile(0))
+}
+
+func TestNewReport(t *testing.T) {
+	before := Samples{
+		apiRequestsMetric: {{Value: 100}},
+		reconcileTimeMetric + "_count": {
+			{Labels: map[string]string{"controller": "keptnapp"}, Value: 2},
+			{Labels: map[string]string{"controller": "keptntask"}, Value: 5},
+		},
+		reconcileTimeMetric + "_sum": {
+			{Labels: map[string]string{"controller": "keptnapp"}, Value: 1},
+			{Labels: map[string]string{"controller": "keptntask"}, Value: 1},
+		},
+	}
+	after := Samples{
+		apiRequestsMetric: {{Labels: map[string]string{"code": "200"}, Value: 250}, {Labels: map[string]string{"code": "404"}, Value: 50}},
+		reconcileTimeMetric + "_count": {
+			{Labels: map[string]string{"controller": "keptnapp"}, Value: 12},
+			{Labels: map[string]string{"controller": "keptntask"}, Value: 5},
+		},
+		reconcileTimeMetric + "_sum": {
+			{Labels: map[string]string{"controller": "keptnapp"}, Value: 2},
+			{Labels: map[string]string{"controller": "keptntask"}, Value: 1},
+		},
+		heapMemoryMetric: {{Value: 64 << 20}},
+	}
+
+	report := newReport(10, 20, 10*time.Second, before, after, 50)
+	testrequire.Equal(t, 150.0, report.APIRequests)
+	testrequire.Equal(t, 15.0, report.QPS())
+	testrequire.Equal(t, float64(64<<20), report.MemoryBytes)
+	testrequire.Equal(t, []ReconcileStats{{Controller: "keptnapp", Count: 10, Average: 100 * time.Millisecond}}, report.Reconciles)
+	testrequire.True(t, strings.Contains(report.String(), "Memory: 64.0 MiB"))
+}
@@ -0,0 +1,176 @@
+package tracing
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/keptn/lifecycle-controller/operator/metrics"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+// ExporterKey is the attribute of the span export metrics containing the name of the exporter
+const ExporterKey = attribute.Key("keptn.spans.exporter")
+
+// Options configure the queue and the batches of a BufferedSpanProcessor
+type Options struct {
+	// QueueSize is the maximum number of spans waiting to be exported, further spans are dropped
+	QueueSize int
+	// BatchSize is the maximum number of spans exported at once
+	BatchSize int
+	// BatchTimeout is the maximum time a span waits for its batch to be filled
+	BatchTimeout time.Duration
+	// ExportTimeout is the maximum time an export of a batch may take
+	ExportTimeout time.Duration
+}
+
+// BufferedSpanProcessor exports the ended spans asynchronously in batches. The spans are buffered in a queue of bounded size,
+// so that a slow or unavailable collector never blocks the reconcilers. Spans that do not fit into the queue or cannot be
+// exported are dropped and counted in the metrics.SpansDropped and metrics.SpansExportFailed metrics.
+type BufferedSpanProcessor struct {
+	exporter sdktrace.SpanExporter
+	meters   metrics.Meters
+	attrs    []attribute.KeyValue
+	opts     Options
+
+	queue    chan sdktrace.ReadOnlySpan
+	flush    chan chan struct{}
+	stop     chan struct{}
+	done     chan struct{}
+	stopOnce sync.Once
+}
+
+// NewBufferedSpanProcessor creates a processor exporting the spans with the given exporter and starts exporting in the background
+func NewBufferedSpanProcessor(name string, exporter sdktrace.SpanExporter, meters metrics.Meters, opts Options) *BufferedSpanProcessor {
+	if opts.QueueSize <= 0 {
+		opts.QueueSize = 2048
+	}
+	if opts.BatchSize <= 0 || opts.BatchSize > opts.QueueSize {
+		opts.BatchSize = opts.QueueSize
+	}
+	if opts.BatchTimeout <= 0 {
+		opts.BatchTimeout = 5 * time.Second
+	}
+	if opts.ExportTimeout <= 0 {
+		opts.ExportTimeout = 30 * time.Second
+	}
+	p := &BufferedSpanProcessor{
+		exporter: exporter,
+		meters:   meters,
+		attrs:    []attribute.KeyValue{ExporterKey.String(name)},
+		opts:     opts,
+		queue:    make(chan sdktrace.ReadOnlySpan, opts.QueueSize),
+		flush:    make(chan chan struct{}),
+		stop:     make(chan struct{}),
+		done:     make(chan struct{}),
+	}
+	go p.run()
+	return p
+}
+
+func (p *BufferedSpanProcessor) OnStart(parent context.Context, s sdktrace.ReadWriteSpan) {}
+
+// OnEnd queues the span for export without blocking, dropping it if the queue is full
+func (p *BufferedSpanProcessor) OnEnd(s sdktrace.ReadOnlySpan) {
+	if !s.SpanContext().IsSampled() {
+		return
+	}
+	select {
+	case <-p.stop:
+		p.meters.Add(context.Background(), metrics.SpansDropped, 1, p.attrs...)
+		return
+	default:
+	}
+	select {
+	case p.queue <- s:
+	default:
+		p.meters.Add(context.Background(), metrics.SpansDropped, 1, p.attrs...)
+	}
+}
+
+// ForceFlush exports all queued spans
+func (p *BufferedSpanProcessor) ForceFlush(ctx context.Context) error {
+	ack := make(chan struct{})
+	select {
+	case p.flush <- ack:
+	case <-p.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+	select {
+	case <-ack:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Shutdown exports all queued spans and shuts down the exporter
+func (p *BufferedSpanProcessor) Shutdown(ctx context.Context) error {
+	p.stopOnce.Do(func() {
+		close(p.stop)
+	})
+	select {
+	case <-p.done:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+	return p.exporter.Shutdown(ctx)
+}
+
+func (p *BufferedSpanProcessor) run() {
+	defer close(p.done)
+	ticker := time.NewTicker(p.opts.BatchTimeout)
+	defer ticker.Stop()
+
+	batch := make([]sdktrace.ReadOnlySpan, 0, p.opts.BatchSize)
+	for {
+		select {
+		case s := <-p.queue:
+			batch = append(batch, s)
+			if len(batch) >= p.opts.BatchSize {
+				batch = p.export(batch)
+			}
+		case <-ticker.C:
+			batch = p.export(batch)
+		case ack := <-p.flush:
+			batch = p.export(p.drain(batch))
+			close(ack)
+		case <-p.stop:
+			p.export(p.drain(batch))
+			return
+		}
+	}
+}
+
+// drain moves all queued spans to the batch, exporting full batches on the way
+func (p *BufferedSpanProcessor) drain(batch []sdktrace.ReadOnlySpan) []sdktrace.ReadOnlySpan {
+	for {
+		select {
+		case s := <-p.queue:
+			batch = append(batch, s)
+			if len(batch) >= p.opts.BatchSize {
+				batch = p.export(batch)
+			}
+		default:
+			return batch
+		}
+	}
+}
+
+// export exports the batch and returns a new empty batch
+func (p *BufferedSpanProcessor) export(batch []sdktrace.ReadOnlySpan) []sdktrace.ReadOnlySpan {
+	if len(batch) == 0 {
+		return batch
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), p.opts.ExportTimeout)
+	defer cancel()
+	if err := p.exporter.ExportSpans(ctx, batch); err != nil {
+		p.meters.Add(context.Background(), metrics.SpansExportFailed, int64(len(batch)), p.attrs...)
+		otel.Handle(err)
+	}
+	return make([]sdktrace.ReadOnlySpan, 0, p.opts.BatchSize)
+}
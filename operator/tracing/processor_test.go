@@ -0,0 +1,77 @@
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/keptn/lifecycle-controller/operator/metrics"
+	testrequire "github.com/stretchr/testify/require"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+type fakeExporter struct {
+	mu      sync.Mutex
+	block   chan struct{}
+	err     error
+	spans   []sdktrace.ReadOnlySpan
+	stopped bool
+}
+
+func (e *fakeExporter) ExportSpans(ctx context.Context, spans []sdktrace.ReadOnlySpan) error {
+	if e.block != nil {
+		<-e.block
+	}
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.err != nil {
+		return e.err
+	}
+	e.spans = append(e.spans, spans...)
+	return nil
+}
+
+func (e *fakeExporter) Shutdown(ctx context.Context) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.stopped = true
+	return nil
+}
+
+func endSpans(tp *sdktrace.TracerProvider, count int) {
+	for i := 0; i < count; i++ {
+		_, span := tp.Tracer("test").Start(context.TODO(), fmt.Sprintf("span-%d", i))
+		span.End()
+	}
+}
+
+func TestBufferedSpanProcessor_DropsSpansIfQueueIsFull(t *testing.T) {
+	meters := metrics.NewInMemoryMeters()
+	exporter := &fakeExporter{block: make(chan struct{})}
+	processor := NewBufferedSpanProcessor("test", exporter, meters, Options{QueueSize: 2, BatchSize: 1})
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(processor))
+
+	// the exporter is blocked, so at most one span is being exported and two are queued
+	endSpans(tp, 5)
+	dropped := meters.Sum(string(metrics.SpansDropped))
+	testrequire.GreaterOrEqual(t, dropped, float64(2))
+
+	close(exporter.block)
+	testrequire.Nil(t, tp.Shutdown(context.TODO()))
+	testrequire.True(t, exporter.stopped)
+	testrequire.Equal(t, 5, len(exporter.spans)+int(dropped))
+}
+
+func TestBufferedSpanProcessor_CountsFailedExports(t *testing.T) {
+	meters := metrics.NewInMemoryMeters()
+	exporter := &fakeExporter{err: fmt.Errorf("collector unavailable")}
+	processor := NewBufferedSpanProcessor("test", exporter, meters, Options{QueueSize: 10, BatchSize: 10})
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(processor))
+
+	endSpans(tp, 3)
+	testrequire.Nil(t, tp.ForceFlush(context.TODO()))
+	testrequire.Equal(t, float64(3), meters.Sum(string(metrics.SpansExportFailed)))
+	testrequire.Equal(t, float64(0), meters.Sum(string(metrics.SpansDropped)))
+	testrequire.Nil(t, tp.Shutdown(context.TODO()))
+}
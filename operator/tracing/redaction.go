@@ -0,0 +1,68 @@
+package tracing
+
+import (
+	"context"
+
+	"github.com/keptn/lifecycle-controller/operator/redaction"
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+// RedactingExporter redacts the attributes of spans and their events before they are exported, so that values of
+// sensitive keys never leave the operator, regardless of where they have been added to a span
+type RedactingExporter struct {
+	sdktrace.SpanExporter
+	redactor *redaction.Redactor
+}
+
+// NewRedactingExporter wraps the exporter with the redactor
+func NewRedactingExporter(exporter sdktrace.SpanExporter, redactor *redaction.Redactor) *RedactingExporter {
+	return &RedactingExporter{SpanExporter: exporter, redactor: redactor}
+}
+
+// ExportSpans exports the redacted spans
+func (e *RedactingExporter) ExportSpans(ctx context.Context, spans []sdktrace.ReadOnlySpan) error {
+	redacted := make([]sdktrace.ReadOnlySpan, 0, len(spans))
+	for _, span := range spans {
+		redacted = append(redacted, &redactedSpan{ReadOnlySpan: span, redactor: e.redactor})
+	}
+	return e.SpanExporter.ExportSpans(ctx, redacted)
+}
+
+type redactedSpan struct {
+	sdktrace.ReadOnlySpan
+	redactor *redaction.Redactor
+}
+
+func (s *redactedSpan) Attributes() []attribute.KeyValue {
+	return redactAttributes(s.redactor, s.ReadOnlySpan.Attributes())
+}
+
+func (s *redactedSpan) Events() []sdktrace.Event {
+	events := s.ReadOnlySpan.Events()
+	redacted := make([]sdktrace.Event, 0, len(events))
+	for _, event := range events {
+		event.Attributes = redactAttributes(s.redactor, event.Attributes)
+		redacted = append(redacted, event)
+	}
+	return redacted
+}
+
+func (s *redactedSpan) Status() sdktrace.Status {
+	status := s.ReadOnlySpan.Status()
+	status.Description = s.redactor.String(status.Description)
+	return status
+}
+
+func redactAttributes(redactor *redaction.Redactor, attrs []attribute.KeyValue) []attribute.KeyValue {
+	redacted := make([]attribute.KeyValue, 0, len(attrs))
+	for _, attr := range attrs {
+		if redactor.IsSensitive(string(attr.Key)) {
+			attr = attr.Key.String(redaction.Placeholder)
+		} else if attr.Value.Type() == attribute.STRING {
+			attr = attr.Key.String(redactor.String(attr.Value.AsString()))
+		}
+		redacted = append(redacted, attr)
+	}
+	return redacted
+}
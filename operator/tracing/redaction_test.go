@@ -0,0 +1,39 @@
+package tracing
+
+import (
+	"context"
+	"testing"
+
+	"github.com/keptn/lifecycle-controller/operator/redaction"
+	testrequire "github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestRedactingExporter_ExportSpans(t *testing.T) {
+	exporter := &fakeExporter{}
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSyncer(NewRedactingExporter(exporter, redaction.New(nil))))
+
+	_, span := tp.Tracer("test").Start(context.TODO(), "query_provider")
+	span.SetAttributes(
+		attribute.String("keptn.deployment.evaluation.query", `up{token="s3cr3t"}`),
+		attribute.String("api_key", "s3cr3t"),
+		attribute.Int("retries", 1),
+	)
+	span.AddEvent("query", trace.WithAttributes(attribute.String("url", "/api?password=s3cr3t")))
+	span.SetStatus(codes.Error, "query token=s3cr3t failed")
+	span.End()
+
+	testrequire.Len(t, exporter.spans, 1)
+	exported := exporter.spans[0]
+	testrequire.Equal(t, []attribute.KeyValue{
+		attribute.String("keptn.deployment.evaluation.query", `up{token="***"}`),
+		attribute.String("api_key", "***"),
+		attribute.Int("retries", 1),
+	}, exported.Attributes())
+	testrequire.Equal(t, []attribute.KeyValue{attribute.String("url", "/api?password=***")}, exported.Events()[0].Attributes)
+	testrequire.Equal(t, "query token=*** failed", exported.Status().Description)
+	testrequire.Equal(t, "query_provider", exported.Name())
+}
@@ -0,0 +1,68 @@
+package webhooks
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/go-logr/logr"
+	klcv1alpha1 "github.com/keptn/lifecycle-controller/operator/api/v1alpha1"
+	"github.com/keptn/lifecycle-controller/operator/api/v1alpha1/common"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+// +kubebuilder:webhook:path=/validate-lifecycle-keptn-sh-v1alpha1-keptnevaluationdefinition,mutating=false,failurePolicy=fail,groups=lifecycle.keptn.sh,resources=keptnevaluationdefinitions,verbs=create;update,versions=v1alpha1,name=vkeptnevaluationdefinition.keptn.sh,admissionReviewVersions=v1,sideEffects=None
+
+// EvaluationDefinitionValidatingWebhook rejects KeptnEvaluationDefinitions with invalid evaluation targets or SLOs
+type EvaluationDefinitionValidatingWebhook struct {
+	decoder *admission.Decoder
+	Log     logr.Logger
+}
+
+// Handle parses the evaluation targets of all objectives of incoming KeptnEvaluationDefinitions
+func (a *EvaluationDefinitionValidatingWebhook) Handle(ctx context.Context, req admission.Request) admission.Response {
+	definition := &klcv1alpha1.KeptnEvaluationDefinition{}
+
+	err := a.decoder.Decode(req, definition)
+	if err != nil {
+		return admission.Errored(http.StatusBadRequest, err)
+	}
+
+	if errs := validateObjectives(definition.Spec.Objectives); len(errs) > 0 {
+		a.Log.Info("rejecting KeptnEvaluationDefinition", "namespace", req.Namespace, "name", req.Name, "errors", errs)
+		return admission.Denied(strings.Join(errs, "; "))
+	}
+	return admission.Allowed("")
+}
+
+// InjectDecoder injects the decoder.
+func (a *EvaluationDefinitionValidatingWebhook) InjectDecoder(d *admission.Decoder) error {
+	a.decoder = d
+	return nil
+}
+
+func validateObjectives(objectives []klcv1alpha1.Objective) []string {
+	errs := []string{}
+	for _, objective := range objectives {
+		if objective.Query == "" && objective.KeptnMetric == "" {
+			errs = append(errs, fmt.Sprintf("objective %s: either query or keptnMetric has to be set", objective.Name))
+		}
+		if objective.KeptnMetric != "" && objective.BurnRate != nil {
+			errs = append(errs, fmt.Sprintf("objective %s: burnRate cannot be used with keptnMetric", objective.Name))
+		}
+		if objective.BurnRate != nil {
+			if _, err := objective.BurnRate.GetTarget(); err != nil {
+				errs = append(errs, fmt.Sprintf("objective %s: %s", objective.Name, err.Error()))
+			}
+			if _, err := objective.BurnRate.GetMaxBurnRate(); err != nil {
+				errs = append(errs, fmt.Sprintf("objective %s: %s", objective.Name, err.Error()))
+			}
+			continue
+		}
+		if _, err := common.ParseTarget(objective.EvaluationTarget); err != nil {
+			errs = append(errs, fmt.Sprintf("objective %s: %s", objective.Name, err.Error()))
+		}
+	}
+	return errs
+}
@@ -0,0 +1,132 @@
+package webhooks
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/go-logr/logr"
+	klcv1alpha1 "github.com/keptn/lifecycle-controller/operator/api/v1alpha1"
+	"github.com/keptn/lifecycle-controller/operator/controllers/keptnevaluationprovider"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+// +kubebuilder:webhook:path=/validate-lifecycle-keptn-sh-v1alpha1-keptnevaluationprovider,mutating=false,failurePolicy=fail,groups=lifecycle.keptn.sh,resources=keptnevaluationproviders,verbs=create;update,versions=v1alpha1,name=vkeptnevaluationprovider.keptn.sh,admissionReviewVersions=v1,sideEffects=None
+//+kubebuilder:rbac:groups=core,resources=secrets,verbs=get
+
+// EvaluationProviderValidatingWebhook rejects KeptnEvaluationProviders with an invalid target server or credentials,
+// so that broken providers cannot be referenced by evaluations
+type EvaluationProviderValidatingWebhook struct {
+	// Reader reads the secrets of the providers without caching them
+	Reader  client.Reader
+	decoder *admission.Decoder
+	// AllowedHosts restricts the target servers to these hosts and their subdomains, if not empty
+	AllowedHosts []string
+	Log          logr.Logger
+}
+
+// Handle validates the target server and the secret of incoming KeptnEvaluationProviders
+func (a *EvaluationProviderValidatingWebhook) Handle(ctx context.Context, req admission.Request) admission.Response {
+	provider := &klcv1alpha1.KeptnEvaluationProvider{}
+
+	err := a.decoder.Decode(req, provider)
+	if err != nil {
+		return admission.Errored(http.StatusBadRequest, err)
+	}
+	if provider.Namespace == "" {
+		provider.Namespace = req.Namespace
+	}
+
+	errs := a.validateTargetServer(provider.Spec.TargetServer)
+	secretErrs, err := a.validateSecret(ctx, provider)
+	if err != nil {
+		return admission.Errored(http.StatusInternalServerError, err)
+	}
+	errs = append(errs, secretErrs...)
+	if len(errs) > 0 {
+		a.Log.Info("rejecting KeptnEvaluationProvider", "namespace", req.Namespace, "name", req.Name, "errors", errs)
+		return admission.Denied(strings.Join(errs, "; "))
+	}
+	return admission.Allowed("")
+}
+
+// InjectDecoder injects the decoder.
+func (a *EvaluationProviderValidatingWebhook) InjectDecoder(d *admission.Decoder) error {
+	a.decoder = d
+	return nil
+}
+
+func (a *EvaluationProviderValidatingWebhook) validateTargetServer(targetServer string) []string {
+	target, err := url.Parse(targetServer)
+	if err != nil {
+		return []string{fmt.Sprintf("targetServer %s is not a valid URL: %s", targetServer, err.Error())}
+	}
+	if target.Scheme != "http" && target.Scheme != "https" {
+		return []string{fmt.Sprintf("targetServer %s has to be an http or https URL", targetServer)}
+	}
+	if target.Hostname() == "" {
+		return []string{fmt.Sprintf("targetServer %s has no host", targetServer)}
+	}
+	if target.User != nil {
+		return []string{fmt.Sprintf("targetServer %s must not contain credentials, use secretName instead", target.Redacted())}
+	}
+	if !a.isAllowedHost(target.Hostname()) {
+		return []string{fmt.Sprintf("host %s of targetServer is not allowed, allowed hosts are %s", target.Hostname(), strings.Join(a.AllowedHosts, ", "))}
+	}
+	return nil
+}
+
+func (a *EvaluationProviderValidatingWebhook) isAllowedHost(hostname string) bool {
+	if len(a.AllowedHosts) == 0 {
+		return true
+	}
+	for _, allowed := range a.AllowedHosts {
+		allowed = strings.TrimPrefix(allowed, ".")
+		if hostname == allowed || strings.HasSuffix(hostname, "."+allowed) {
+			return true
+		}
+	}
+	return false
+}
+
+// validateSecret checks that the secret of the provider exists and contains either a token or a user and password.
+// Dynatrace providers require a secret with an API token.
+func (a *EvaluationProviderValidatingWebhook) validateSecret(ctx context.Context, provider *klcv1alpha1.KeptnEvaluationProvider) ([]string, error) {
+	if provider.Spec.SecretName == "" {
+		if provider.IsDynatrace() {
+			return []string{fmt.Sprintf("provider of type %s requires a secretName containing an API token", klcv1alpha1.ProviderTypeDynatrace)}, nil
+		}
+		return nil, nil
+	}
+	secret := &corev1.Secret{}
+	if err := a.Reader.Get(ctx, types.NamespacedName{Namespace: provider.Namespace, Name: provider.Spec.SecretName}, secret); err != nil {
+		if errors.IsNotFound(err) {
+			return []string{fmt.Sprintf("secret %s does not exist in namespace %s", provider.Spec.SecretName, provider.Namespace)}, nil
+		}
+		return nil, fmt.Errorf("could not retrieve secret %s: %w", provider.Spec.SecretName, err)
+	}
+
+	hasKey := func(key string) bool {
+		return len(secret.Data[key]) > 0 || secret.StringData[key] != ""
+	}
+	token := hasKey(keptnevaluationprovider.SecretKeyToken)
+	user := hasKey(keptnevaluationprovider.SecretKeyUser)
+	password := hasKey(keptnevaluationprovider.SecretKeyPassword)
+	switch {
+	case provider.IsDynatrace() && (!token || user || password):
+		return []string{fmt.Sprintf("secret %s of a provider of type %s has to contain only a %s", secret.Name, klcv1alpha1.ProviderTypeDynatrace, keptnevaluationprovider.SecretKeyToken)}, nil
+	case token && (user || password):
+		return []string{fmt.Sprintf("secret %s contains a %s and a %s or %s, which are mutually exclusive", secret.Name, keptnevaluationprovider.SecretKeyToken, keptnevaluationprovider.SecretKeyUser, keptnevaluationprovider.SecretKeyPassword)}, nil
+	case user != password:
+		return []string{fmt.Sprintf("secret %s has to contain both a %s and a %s", secret.Name, keptnevaluationprovider.SecretKeyUser, keptnevaluationprovider.SecretKeyPassword)}, nil
+	case !token && !user:
+		return []string{fmt.Sprintf("secret %s has to contain either a %s, or a %s and a %s", secret.Name, keptnevaluationprovider.SecretKeyToken, keptnevaluationprovider.SecretKeyUser, keptnevaluationprovider.SecretKeyPassword)}, nil
+	}
+	return nil, nil
+}
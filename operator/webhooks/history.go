@@ -0,0 +1,35 @@
+package webhooks
+
+import (
+	"context"
+
+	"github.com/keptn/lifecycle-controller/operator/api/v1alpha1/common"
+	"github.com/keptn/lifecycle-controller/operator/controllers/history"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// importedVersion returns the version of the earlier revision of the Deployment of a pod whose workload is adopted,
+// which is determined from the pod template of the revision like the version of the pod itself.
+// It returns an empty string if history import is disabled or the Deployment has no earlier revision with a version.
+func (a *PodMutatingWebhook) importedVersion(ctx context.Context, pod *corev1.Pod, namespace string) (string, error) {
+	reference := a.getResourceReference(pod)
+	if !a.ImportHistory || reference.Kind != "ReplicaSet" || reference.UID == "" {
+		return "", nil
+	}
+	replicaSet, err := history.PreviousReplicaSet(ctx, a.Client, namespace, reference.UID)
+	if err != nil || replicaSet == nil {
+		return "", err
+	}
+
+	template := replicaSet.Spec.Template.DeepCopy()
+	previousPod := &corev1.Pod{ObjectMeta: template.ObjectMeta, Spec: template.Spec}
+	if annotated, err := a.isKeptnAnnotated(previousPod); err != nil || !annotated {
+		// earlier revisions without valid Keptn annotations cannot be compared with
+		return "", nil
+	}
+	version := previousPod.Annotations[common.VersionAnnotation]
+	if version == pod.Annotations[common.VersionAnnotation] {
+		return "", nil
+	}
+	return version, nil
+}
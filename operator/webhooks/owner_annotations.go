@@ -0,0 +1,103 @@
+package webhooks
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/keptn/lifecycle-controller/operator/api/v1alpha1/common"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+//+kubebuilder:rbac:groups=apps,resources=replicasets;deployments;statefulsets;daemonsets,verbs=get;list;watch
+
+// ownerAnnotations are the annotations which can be set on the Deployment, StatefulSet or DaemonSet itself
+// instead of the pod template, so that app teams can manage their gates next to their manifests
+var ownerAnnotations = []string{
+	common.PreDeploymentTaskAnnotation,
+	common.PostDeploymentTaskAnnotation,
+	common.MigrationTaskAnnotation,
+	common.PreDeploymentEvaluationAnnotation,
+	common.PostDeploymentEvaluationAnnotation,
+	common.ChangeRequestAnnotation,
+	common.SimulateFailureAnnotation,
+	common.RollbackOnFailureAnnotation,
+	common.SkipPhasesAnnotation,
+}
+
+// inheritOwnerAnnotations copies the task and evaluation annotations of the Deployment, StatefulSet or DaemonSet
+// owning the pod to the pod. Annotations and labels of the pod template take precedence.
+func (a *PodMutatingWebhook) inheritOwnerAnnotations(ctx context.Context, pod *corev1.Pod, namespace string) error {
+	owner, err := a.getOwner(ctx, pod.OwnerReferences, namespace)
+	if err != nil || owner == nil {
+		return err
+	}
+	for _, key := range ownerAnnotations {
+		value := owner.GetAnnotations()[key]
+		if value == "" {
+			continue
+		}
+		if _, found := getLabelOrAnnotation(pod, key, ""); found {
+			continue
+		}
+		if pod.Annotations == nil {
+			pod.Annotations = make(map[string]string)
+		}
+		pod.Annotations[key] = value
+	}
+	return nil
+}
+
+// getOwner returns the Deployment, StatefulSet or DaemonSet controlling the pod, or nil if there is none.
+// Pods of a ReplicaSet which is not owned by a Deployment inherit the annotations of the ReplicaSet.
+func (a *PodMutatingWebhook) getOwner(ctx context.Context, ownerReferences []metav1.OwnerReference, namespace string) (client.Object, error) {
+	controller := getController(ownerReferences)
+	if controller == nil {
+		return nil, nil
+	}
+
+	var owner client.Object
+	switch controller.Kind {
+	case "ReplicaSet":
+		owner = &appsv1.ReplicaSet{}
+	case "Deployment":
+		owner = &appsv1.Deployment{}
+	case "StatefulSet":
+		owner = &appsv1.StatefulSet{}
+	case "DaemonSet":
+		owner = &appsv1.DaemonSet{}
+	default:
+		return nil, nil
+	}
+
+	err := a.Client.Get(ctx, types.NamespacedName{Namespace: namespace, Name: controller.Name}, owner)
+	if errors.IsNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("could not fetch %s %s: %w", controller.Kind, controller.Name, err)
+	}
+
+	// only Deployments are followed, so that malformed owner references, e.g. of a ReplicaSet owning itself, cannot loop
+	if replicaSet, ok := owner.(*appsv1.ReplicaSet); ok {
+		if c := getController(replicaSet.OwnerReferences); c != nil && c.Kind == "Deployment" {
+			if deployment, err := a.getOwner(ctx, []metav1.OwnerReference{*c}, namespace); err != nil || deployment != nil {
+				return deployment, err
+			}
+		}
+	}
+	return owner, nil
+}
+
+func getController(ownerReferences []metav1.OwnerReference) *metav1.OwnerReference {
+	for i := range ownerReferences {
+		if ownerReferences[i].Controller != nil && *ownerReferences[i].Controller {
+			return &ownerReferences[i]
+		}
+	}
+	return nil
+}
@@ -0,0 +1,108 @@
+package webhooks
+
+import (
+	"context"
+	"testing"
+
+	"github.com/keptn/lifecycle-controller/operator/api/v1alpha1/common"
+	testrequire "github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestPodMutatingWebhook_InheritOwnerAnnotations(t *testing.T) {
+	isController := true
+	deployment := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "my-deployment",
+			Namespace: "default",
+			Annotations: map[string]string{
+				common.PreDeploymentEvaluationAnnotation: "slo-latency,slo-errors",
+				common.PostDeploymentTaskAnnotation:      "notify",
+			},
+		},
+	}
+	replicaSet := &appsv1.ReplicaSet{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "my-deployment-5f7b",
+			Namespace: "default",
+			OwnerReferences: []metav1.OwnerReference{
+				{Kind: "Deployment", Name: "my-deployment", Controller: &isController},
+			},
+		},
+	}
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Annotations: map[string]string{
+				common.PostDeploymentTaskAnnotation: "smoke-test",
+			},
+			OwnerReferences: []metav1.OwnerReference{
+				{Kind: "ReplicaSet", Name: "my-deployment-5f7b", Controller: &isController},
+			},
+		},
+	}
+	a := &PodMutatingWebhook{Client: fake.NewClientBuilder().WithObjects(deployment, replicaSet).Build()}
+
+	err := a.inheritOwnerAnnotations(context.TODO(), pod, "default")
+
+	testrequire.Nil(t, err)
+	testrequire.Equal(t, "slo-latency,slo-errors", pod.Annotations[common.PreDeploymentEvaluationAnnotation])
+	testrequire.Equal(t, "smoke-test", pod.Annotations[common.PostDeploymentTaskAnnotation])
+
+	workload := a.generateWorkload(context.TODO(), pod, "default")
+	testrequire.Equal(t, []string{"slo-latency", "slo-errors"}, workload.Spec.PreDeploymentEvaluations)
+	testrequire.Equal(t, []string{"smoke-test"}, workload.Spec.PostDeploymentTasks)
+}
+
+func TestPodMutatingWebhook_InheritOwnerAnnotationsWithoutOwner(t *testing.T) {
+	a := &PodMutatingWebhook{Client: fake.NewClientBuilder().Build()}
+	pod := &corev1.Pod{}
+
+	err := a.inheritOwnerAnnotations(context.TODO(), pod, "default")
+
+	testrequire.Nil(t, err)
+	testrequire.Empty(t, pod.Annotations)
+}
+
+func FuzzPodMutatingWebhook_GetOwner(f *testing.F) {
+	f.Add("ReplicaSet", "my-deployment-5f7b", "Deployment", "my-deployment")
+	f.Add("ReplicaSet", "my-deployment-5f7b", "ReplicaSet", "my-deployment-5f7b")
+	f.Add("StatefulSet", "my-statefulset", "", "")
+	f.Add("Job", "my-job", "CronJob", "my-cronjob")
+	f.Fuzz(func(t *testing.T, podOwnerKind string, podOwnerName string, ownerKind string, ownerName string) {
+		if len(validation.IsDNS1123Subdomain(podOwnerName)) > 0 || len(validation.IsDNS1123Subdomain(ownerName)) > 0 {
+			return
+		}
+		isController := true
+		// the owner of the pod may reference itself or any other kind, which must neither loop nor fail
+		objects := []client.Object{
+			&appsv1.ReplicaSet{ObjectMeta: metav1.ObjectMeta{
+				Name:            podOwnerName,
+				Namespace:       "default",
+				Annotations:     map[string]string{common.PreDeploymentTaskAnnotation: "replicaset"},
+				OwnerReferences: []metav1.OwnerReference{{Kind: ownerKind, Name: ownerName, Controller: &isController}},
+			}},
+			&appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{
+				Name:        ownerName,
+				Namespace:   "default",
+				Annotations: map[string]string{common.PreDeploymentTaskAnnotation: "deployment"},
+			}},
+		}
+		a := &PodMutatingWebhook{Client: fake.NewClientBuilder().WithObjects(objects...).Build()}
+
+		owner, err := a.getOwner(context.TODO(), []metav1.OwnerReference{{Kind: podOwnerKind, Name: podOwnerName, Controller: &isController}}, "default")
+		testrequire.Nil(t, err)
+		if podOwnerKind == "ReplicaSet" {
+			testrequire.NotNil(t, owner)
+			if ownerKind == "Deployment" {
+				testrequire.Equal(t, "deployment", owner.GetAnnotations()[common.PreDeploymentTaskAnnotation])
+			} else {
+				testrequire.Equal(t, "replicaset", owner.GetAnnotations()[common.PreDeploymentTaskAnnotation])
+			}
+		}
+	})
+}
@@ -7,11 +7,14 @@ import (
 	"net/http"
 	"reflect"
 	"strings"
+	"time"
 
 	"github.com/go-logr/logr"
 	klcv1alpha1 "github.com/keptn/lifecycle-controller/operator/api/v1alpha1"
 	"github.com/keptn/lifecycle-controller/operator/api/v1alpha1/common"
 	"github.com/keptn/lifecycle-controller/operator/api/v1alpha1/semconv"
+	"github.com/keptn/lifecycle-controller/operator/controllers/apply"
+	"github.com/keptn/lifecycle-controller/operator/controllers/reasons"
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/codes"
 	"go.opentelemetry.io/otel/propagation"
@@ -39,6 +42,9 @@ type PodMutatingWebhook struct {
 	decoder  *admission.Decoder
 	Recorder record.EventRecorder
 	Log      logr.Logger
+	// ImportHistory annotates the workloads adopted from Deployments with an earlier revision with the version of that
+	// revision, which is imported as a completed KeptnWorkloadInstance
+	ImportHistory bool
 }
 
 // Handle inspects incoming Pods and injects the Keptn scheduler if they contain the Keptn lifecycle annotations.
@@ -77,7 +83,7 @@ func (a *PodMutatingWebhook) Handle(ctx context.Context, req admission.Request)
 
 	isAnnotated, err := a.isKeptnAnnotated(pod)
 	if err != nil {
-		span.SetStatus(codes.Error, "Invalid annotations")
+		span.SetStatus(codes.Error, reasons.InvalidAnnotations.Code)
 		return admission.Errored(http.StatusBadRequest, err)
 	}
 	if isAnnotated {
@@ -87,9 +93,19 @@ func (a *PodMutatingWebhook) Handle(ctx context.Context, req admission.Request)
 
 		isAppAnnotationPresent, err := a.isAppAnnotationPresent(pod)
 		if err != nil {
-			span.SetStatus(codes.Error, "Invalid annotations")
+			span.SetStatus(codes.Error, reasons.InvalidAnnotations.Code)
 			return admission.Errored(http.StatusBadRequest, err)
 		}
+		rolledBack, err := a.restoreRolledBackVersion(ctx, pod, req.Namespace)
+		if err != nil {
+			logger.Error(err, "Could not check for rolled back workload instance")
+			span.SetStatus(codes.Error, err.Error())
+			return admission.Errored(http.StatusInternalServerError, err)
+		}
+		if rolledBack != nil {
+			logger.Info("Pod belongs to an earlier workload instance, restoring its version", "workloadInstance", rolledBack.Name, "version", rolledBack.Spec.Version)
+			a.Recorder.Event(rolledBack, "Normal", "WorkloadInstanceRolledBack", fmt.Sprintf("Pods of ReplicaSet rolled back to KeptnWorkloadInstance / Namespace: %s, Name: %s ", rolledBack.Namespace, rolledBack.Name))
+		}
 		if !isAppAnnotationPresent {
 			if err := a.handleApp(ctx, logger, pod, req.Namespace); err != nil {
 				logger.Error(err, "Could not handle App")
@@ -97,6 +113,11 @@ func (a *PodMutatingWebhook) Handle(ctx context.Context, req admission.Request)
 				return admission.Errored(http.StatusBadRequest, err)
 			}
 		}
+		if err := a.inheritOwnerAnnotations(ctx, pod, req.Namespace); err != nil {
+			logger.Error(err, "Could not inherit annotations of owner")
+			span.SetStatus(codes.Error, err.Error())
+			return admission.Errored(http.StatusInternalServerError, err)
+		}
 		semconv.AddAttributeFromAnnotations(span, pod.Annotations)
 
 		logger.Info("Attributes from annotations set")
@@ -110,7 +131,7 @@ func (a *PodMutatingWebhook) Handle(ctx context.Context, req admission.Request)
 
 	marshaledPod, err := json.Marshal(pod)
 	if err != nil {
-		span.SetStatus(codes.Error, "Failed to marshal")
+		span.SetStatus(codes.Error, reasons.MarshalFailed.Code)
 		return admission.Errored(http.StatusInternalServerError, err)
 	}
 
@@ -130,17 +151,35 @@ func (a *PodMutatingWebhook) isKeptnAnnotated(pod *corev1.Pod) (bool, error) {
 	workload, gotWorkloadAnnotation := getLabelOrAnnotation(pod, common.WorkloadAnnotation, common.K8sRecommendedWorkloadAnnotations)
 	version, gotVersionAnnotation := getLabelOrAnnotation(pod, common.VersionAnnotation, common.K8sRecommendedVersionAnnotations)
 
+	if gotWorkloadAnnotation && gotVersionAnnotation {
+		normalized, err := common.NormalizeVersion(version)
+		if err != nil {
+			return false, err
+		}
+		version = normalized
+	}
+
 	if len(workload) > common.MaxWorkloadNameLength || len(version) > common.MaxVersionLength {
 		return false, common.ErrTooLongAnnotations
 	}
 
 	if gotWorkloadAnnotation {
+		if len(pod.Annotations) == 0 {
+			pod.Annotations = make(map[string]string)
+		}
 		if !gotVersionAnnotation {
-			if len(pod.Annotations) == 0 {
-				pod.Annotations = make(map[string]string)
+			containerVersions, err := getContainerVersions(pod)
+			if err != nil {
+				return false, err
+			}
+			if len(containerVersions) > 0 {
+				version = compositeVersion(pod, containerVersions)
+			} else {
+				version = a.calculateVersion(pod)
 			}
-			pod.Annotations[common.VersionAnnotation] = a.calculateVersion(pod)
 		}
+		// the normalized version takes precedence over the recommended label when the workload and app are generated
+		pod.Annotations[common.VersionAnnotation] = version
 		return true, nil
 	}
 	return false, nil
@@ -167,9 +206,11 @@ func (a *PodMutatingWebhook) calculateVersion(pod *corev1.Pod) string {
 	name := ""
 
 	if len(pod.Spec.Containers) == 1 {
-		image := strings.Split(pod.Spec.Containers[0].Image, ":")
-		if len(image) > 1 && image[1] != "" && image[1] != "latest" {
-			return image[1]
+		tag := imageTag(pod.Spec.Containers[0].Image)
+		if tag != "" && tag != "latest" {
+			if version, err := common.NormalizeVersion(tag); err == nil && len(version) <= common.MaxVersionLength {
+				return version
+			}
 		}
 	}
 
@@ -185,6 +226,64 @@ func (a *PodMutatingWebhook) calculateVersion(pod *corev1.Pod) string {
 	return fmt.Sprint(h.Sum32())
 }
 
+// imageTag returns the tag of an image reference, e.g. 1.0 for registry:5000/app:1.0@sha256:..., or an empty string if it has none
+func imageTag(image string) string {
+	if i := strings.Index(image, "@"); i >= 0 {
+		image = image[:i]
+	}
+	i := strings.LastIndex(image, ":")
+	if i < 0 || strings.Contains(image[i+1:], "/") {
+		return ""
+	}
+	return image[i+1:]
+}
+
+// getContainerVersions returns the normalized versions of the containers annotated with keptn.sh/container-version.<container>
+func getContainerVersions(pod *corev1.Pod) (map[string]string, error) {
+	containers := map[string]bool{}
+	for _, container := range pod.Spec.Containers {
+		containers[container.Name] = true
+	}
+
+	versions := map[string]string{}
+	for key, value := range pod.Annotations {
+		if !strings.HasPrefix(key, common.ContainerVersionAnnotationPrefix) {
+			continue
+		}
+		name := strings.TrimPrefix(key, common.ContainerVersionAnnotationPrefix)
+		if !containers[name] {
+			return nil, fmt.Errorf("annotation %s refers to unknown container %s", key, name)
+		}
+		version, err := common.NormalizeVersion(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid version of container %s: %w", name, err)
+		}
+		versions[name] = version
+	}
+	return versions, nil
+}
+
+// compositeVersion aggregates the versions of the containers in the order of the pod spec to the version of the workload,
+// falling back to a hash of the versions if they do not fit into a single version
+func compositeVersion(pod *corev1.Pod, containerVersions map[string]string) string {
+	parts := []string{}
+	identity := ""
+	for _, container := range pod.Spec.Containers {
+		if version, ok := containerVersions[container.Name]; ok {
+			parts = append(parts, version)
+			identity = identity + container.Name + "=" + version + ","
+		}
+	}
+
+	version := strings.Join(parts, "-")
+	if len(version) <= common.MaxVersionLength {
+		return version
+	}
+	h := fnv.New32a()
+	h.Write([]byte(identity))
+	return fmt.Sprint(h.Sum32())
+}
+
 func (a *PodMutatingWebhook) handleWorkload(ctx context.Context, logger logr.Logger, pod *corev1.Pod, namespace string) error {
 
 	ctx, span := a.Tracer.Start(ctx, "create_workload", trace.WithSpanKind(trace.SpanKindProducer))
@@ -201,7 +300,12 @@ func (a *PodMutatingWebhook) handleWorkload(ctx context.Context, logger logr.Log
 	if errors.IsNotFound(err) {
 		logger.Info("Creating workload", "workload", workload.Name)
 		workload = newWorkload
-		err = a.Client.Create(ctx, workload)
+		if version, err := a.importedVersion(ctx, pod, namespace); err != nil {
+			logger.Error(err, "Could not determine the version of the earlier revision of the Workload")
+		} else if version != "" {
+			workload.Annotations[common.ImportedVersionAnnotation] = version
+		}
+		err = apply.Apply(ctx, a.Client, workload, a.Recorder, workload)
 		if err != nil {
 			logger.Error(err, "Could not create Workload")
 			a.Recorder.Event(workload, "Warning", "WorkloadNotCreated", fmt.Sprintf("Could not create KeptnWorkload / Namespace: %s, Name: %s ", workload.Namespace, workload.Name))
@@ -224,9 +328,9 @@ func (a *PodMutatingWebhook) handleWorkload(ctx context.Context, logger logr.Log
 	}
 
 	logger.Info("Pod changed, updating workload")
-	workload.Spec = newWorkload.Spec
+	apply.KeepAnnotations(newWorkload, workload)
 
-	err = a.Client.Update(ctx, workload)
+	err = apply.Apply(ctx, a.Client, newWorkload, a.Recorder, workload)
 	if err != nil {
 		logger.Error(err, "Could not update Workload")
 		a.Recorder.Event(workload, "Warning", "WorkloadNotUpdated", fmt.Sprintf("Could not update KeptnWorkload / Namespace: %s, Name: %s ", workload.Namespace, workload.Name))
@@ -255,7 +359,7 @@ func (a *PodMutatingWebhook) handleApp(ctx context.Context, logger logr.Logger,
 	if errors.IsNotFound(err) {
 		logger.Info("Creating app", "app", app.Name)
 		app = newApp
-		err = a.Client.Create(ctx, app)
+		err = apply.Apply(ctx, a.Client, app, a.Recorder, app)
 		if err != nil {
 			logger.Error(err, "Could not create App")
 			a.Recorder.Event(app, "Warning", "AppNotCreated", fmt.Sprintf("Could not create KeptnApp / Namespace: %s, Name: %s ", app.Namespace, app.Name))
@@ -278,9 +382,9 @@ func (a *PodMutatingWebhook) handleApp(ctx context.Context, logger logr.Logger,
 	}
 
 	logger.Info("Pod changed, updating app")
-	app.Spec = newApp.Spec
+	apply.KeepAnnotations(newApp, app)
 
-	err = a.Client.Update(ctx, app)
+	err = apply.Apply(ctx, a.Client, newApp, a.Recorder, app)
 	if err != nil {
 		logger.Error(err, "Could not update App")
 		a.Recorder.Event(app, "Warning", "AppNotUpdated", fmt.Sprintf("Could not update KeptnApp / Namespace: %s, Name: %s ", app.Namespace, app.Name))
@@ -296,26 +400,84 @@ func (a *PodMutatingWebhook) handleApp(ctx context.Context, logger logr.Logger,
 func (a *PodMutatingWebhook) generateWorkload(ctx context.Context, pod *corev1.Pod, namespace string) *klcv1alpha1.KeptnWorkload {
 	version, _ := getLabelOrAnnotation(pod, common.VersionAnnotation, common.K8sRecommendedVersionAnnotations)
 	applicationName, _ := getLabelOrAnnotation(pod, common.AppAnnotation, common.K8sRecommendedAppAnnotations)
+	issue, _ := getLabelOrAnnotation(pod, common.IssueAnnotation, "")
+	changeRequest, _ := getLabelOrAnnotation(pod, common.ChangeRequestAnnotation, "")
+
+	var postDeploymentEvaluationDelay time.Duration
+	if annotation, found := getLabelOrAnnotation(pod, common.PostDeploymentEvaluationDelayAnnotation, ""); found {
+		delay, err := time.ParseDuration(annotation)
+		if err == nil && delay < 0 {
+			err = fmt.Errorf("negative duration %s", annotation)
+		}
+		if err != nil {
+			log.FromContext(ctx).Error(err, "invalid post-deployment evaluation delay, starting evaluations without delay")
+		} else {
+			postDeploymentEvaluationDelay = delay
+		}
+	}
+
+	var readinessCheck klcv1alpha1.ReadinessCheck
+	if annotation, found := getLabelOrAnnotation(pod, common.ReadinessCheckAnnotation, ""); found {
+		switch check := klcv1alpha1.ReadinessCheck(annotation); check {
+		case klcv1alpha1.ReadinessCheckReadyReplicas, klcv1alpha1.ReadinessCheckPodReady, klcv1alpha1.ReadinessCheckContainersReady:
+			readinessCheck = check
+		default:
+			log.FromContext(ctx).Error(fmt.Errorf("unknown readiness check %s", annotation), "invalid readiness check, using ready replicas")
+		}
+	}
+
+	var simulateFailure klcv1alpha1.SimulatedFailure
+	if annotation, found := getLabelOrAnnotation(pod, common.SimulateFailureAnnotation, ""); found {
+		if failure := klcv1alpha1.SimulatedFailure(annotation); failure.IsValid() {
+			simulateFailure = failure
+		} else {
+			log.FromContext(ctx).Error(fmt.Errorf("unknown phase %s", annotation), "invalid simulated failure, running all phases")
+		}
+	}
+
+	var skipPhases []klcv1alpha1.SkippedPhase
+	if annotation, found := getLabelOrAnnotation(pod, common.SkipPhasesAnnotation, ""); found {
+		for _, name := range splitList(annotation) {
+			if phase := klcv1alpha1.SkippedPhase(name); phase.IsValid() {
+				skipPhases = append(skipPhases, phase)
+			} else {
+				log.FromContext(ctx).Error(fmt.Errorf("unknown phase %s", name), "invalid skipped phase, running it")
+			}
+		}
+	}
+
+	rollbackOnFailure, _ := getLabelOrAnnotation(pod, common.RollbackOnFailureAnnotation, "")
+
+	// the container versions have already been validated when the version of the workload was determined
+	containerVersions, _ := getContainerVersions(pod)
+	if len(containerVersions) == 0 {
+		containerVersions = nil
+	}
 
 	var preDeploymentTasks []string
 	var postDeploymentTasks []string
+	var migrationTasks []string
 	var preDeploymentEvaluation []string
 	var postDeploymentEvaluation []string
 
 	if annotations, found := getLabelOrAnnotation(pod, common.PreDeploymentTaskAnnotation, ""); found {
-		preDeploymentTasks = strings.Split(annotations, ",")
+		preDeploymentTasks = splitList(annotations)
 	}
 
 	if annotations, found := getLabelOrAnnotation(pod, common.PostDeploymentTaskAnnotation, ""); found {
-		postDeploymentTasks = strings.Split(annotations, ",")
+		postDeploymentTasks = splitList(annotations)
+	}
+
+	if annotations, found := getLabelOrAnnotation(pod, common.MigrationTaskAnnotation, ""); found {
+		migrationTasks = splitList(annotations)
 	}
 
 	if annotations, found := getLabelOrAnnotation(pod, common.PreDeploymentEvaluationAnnotation, ""); found {
-		preDeploymentEvaluation = strings.Split(annotations, ",")
+		preDeploymentEvaluation = splitList(annotations)
 	}
 
 	if annotations, found := getLabelOrAnnotation(pod, common.PostDeploymentEvaluationAnnotation, ""); found {
-		postDeploymentEvaluation = strings.Split(annotations, ",")
+		postDeploymentEvaluation = splitList(annotations)
 	}
 
 	// create TraceContext
@@ -330,13 +492,22 @@ func (a *PodMutatingWebhook) generateWorkload(ctx context.Context, pod *corev1.P
 			Annotations: traceContextCarrier,
 		},
 		Spec: klcv1alpha1.KeptnWorkloadSpec{
-			AppName:                   applicationName,
-			Version:                   version,
-			ResourceReference:         a.getResourceReference(pod),
-			PreDeploymentTasks:        preDeploymentTasks,
-			PostDeploymentTasks:       postDeploymentTasks,
-			PreDeploymentEvaluations:  preDeploymentEvaluation,
-			PostDeploymentEvaluations: postDeploymentEvaluation,
+			AppName:                       applicationName,
+			Version:                       version,
+			ResourceReference:             a.getResourceReference(pod),
+			PreDeploymentTasks:            preDeploymentTasks,
+			PostDeploymentTasks:           postDeploymentTasks,
+			PreDeploymentEvaluations:      preDeploymentEvaluation,
+			PostDeploymentEvaluations:     postDeploymentEvaluation,
+			MigrationTasks:                migrationTasks,
+			Issue:                         issue,
+			ChangeRequest:                 changeRequest,
+			PostDeploymentEvaluationDelay: metav1.Duration{Duration: postDeploymentEvaluationDelay},
+			ContainerVersions:             containerVersions,
+			ReadinessCheck:                readinessCheck,
+			SimulateFailure:               simulateFailure,
+			RollbackOnFailure:             rollbackOnFailure == "true",
+			SkipPhases:                    skipPhases,
 		},
 	}
 }
@@ -375,12 +546,12 @@ func (a *PodMutatingWebhook) generateApp(ctx context.Context, pod *corev1.Pod, n
 func (a *PodMutatingWebhook) getWorkloadName(pod *corev1.Pod) string {
 	workloadName, _ := getLabelOrAnnotation(pod, common.WorkloadAnnotation, common.K8sRecommendedWorkloadAnnotations)
 	applicationName, _ := getLabelOrAnnotation(pod, common.AppAnnotation, common.K8sRecommendedAppAnnotations)
-	return strings.ToLower(applicationName + "-" + workloadName)
+	return common.CreateResourceName(common.MaxK8sObjectLength, applicationName, workloadName)
 }
 
 func (a *PodMutatingWebhook) getAppName(pod *corev1.Pod) string {
 	applicationName, _ := getLabelOrAnnotation(pod, common.AppAnnotation, common.K8sRecommendedAppAnnotations)
-	return strings.ToLower(applicationName)
+	return common.CreateResourceName(common.MaxK8sObjectLength, applicationName)
 }
 
 func (a *PodMutatingWebhook) getResourceReference(pod *corev1.Pod) klcv1alpha1.ResourceReference {
@@ -399,6 +570,17 @@ func (a *PodMutatingWebhook) getResourceReference(pod *corev1.Pod) klcv1alpha1.R
 	return reference
 }
 
+// splitList splits the comma-separated value of an annotation, ignoring surrounding whitespace and empty entries
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
 func getLabelOrAnnotation(pod *corev1.Pod, primaryAnnotation string, secondaryAnnotation string) (string, bool) {
 	if pod.Annotations[primaryAnnotation] != "" {
 		return pod.Annotations[primaryAnnotation], true
@@ -0,0 +1,174 @@
+package webhooks
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	klcv1alpha1 "github.com/keptn/lifecycle-controller/operator/api/v1alpha1"
+	"github.com/keptn/lifecycle-controller/operator/api/v1alpha1/common"
+	testrequire "github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func newMultiContainerPod(annotations map[string]string) *corev1.Pod {
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Annotations: annotations},
+		Spec: corev1.PodSpec{
+			Containers: []corev1.Container{
+				{Name: "app", Image: "app:1.2.0"},
+				{Name: "migration", Image: "migration:5"},
+			},
+		},
+	}
+}
+
+func TestPodMutatingWebhook_ContainerVersions(t *testing.T) {
+	a := &PodMutatingWebhook{}
+	pod := newMultiContainerPod(map[string]string{
+		common.WorkloadAnnotation:                             "my-workload",
+		common.ContainerVersionAnnotationPrefix + "app":       "1.2.0+build.7",
+		common.ContainerVersionAnnotationPrefix + "migration": "5",
+	})
+
+	annotated, err := a.isKeptnAnnotated(pod)
+	testrequire.Nil(t, err)
+	testrequire.True(t, annotated)
+	testrequire.Equal(t, "1.2.0-5", pod.Annotations[common.VersionAnnotation])
+
+	versions, err := getContainerVersions(pod)
+	testrequire.Nil(t, err)
+	testrequire.Equal(t, map[string]string{"app": "1.2.0", "migration": "5"}, versions)
+}
+
+func TestPodMutatingWebhook_ContainerVersionsTooLong(t *testing.T) {
+	pod := newMultiContainerPod(nil)
+	version := compositeVersion(pod, map[string]string{"app": "1.2.0-rc.1", "migration": "2022.10.1"})
+	testrequire.LessOrEqual(t, len(version), common.MaxVersionLength)
+	testrequire.NotEqual(t, version, compositeVersion(pod, map[string]string{"app": "1.2.0-rc.2", "migration": "2022.10.1"}))
+}
+
+func TestPodMutatingWebhook_ContainerVersionsInvalid(t *testing.T) {
+	a := &PodMutatingWebhook{}
+
+	_, err := a.isKeptnAnnotated(newMultiContainerPod(map[string]string{
+		common.WorkloadAnnotation:                           "my-workload",
+		common.ContainerVersionAnnotationPrefix + "sidecar": "1.0",
+	}))
+	testrequire.NotNil(t, err)
+
+	_, err = a.isKeptnAnnotated(newMultiContainerPod(map[string]string{
+		common.WorkloadAnnotation:                       "my-workload",
+		common.ContainerVersionAnnotationPrefix + "app": "1.0 beta",
+	}))
+	testrequire.NotNil(t, err)
+}
+
+func TestPodMutatingWebhook_CalculateVersion(t *testing.T) {
+	a := &PodMutatingWebhook{}
+	for image, want := range map[string]string{
+		"app:1.2.0":                           "1.2.0",
+		"registry:5000/app:1.2.0":             "1.2.0",
+		"registry:5000/app:1.2.0@sha256:abcd": "1.2.0",
+	} {
+		pod := &corev1.Pod{Spec: corev1.PodSpec{Containers: []corev1.Container{{Name: "app", Image: image}}}}
+		testrequire.Equal(t, want, a.calculateVersion(pod), image)
+	}
+
+	// images without a tag fall back to a hash of the containers
+	pod := &corev1.Pod{Spec: corev1.PodSpec{Containers: []corev1.Container{{Name: "app", Image: "registry:5000/app"}}}}
+	testrequire.NotContains(t, a.calculateVersion(pod), "/")
+}
+
+func FuzzPodMutatingWebhook_CalculateVersion(f *testing.F) {
+	for _, seed := range []string{"app:1.2.0", "registry:5000/app", "app@sha256:abcd", "app:latest", "app:", ":", "app:V1.0+build"} {
+		f.Add(seed)
+	}
+	a := &PodMutatingWebhook{}
+	f.Fuzz(func(t *testing.T, image string) {
+		pod := &corev1.Pod{Spec: corev1.PodSpec{Containers: []corev1.Container{{Name: "app", Image: image}}}}
+		version := a.calculateVersion(pod)
+		// the version is used in the names of the instances and has to be a valid, normalized label value
+		normalized, err := common.NormalizeVersion(version)
+		testrequire.Nil(t, err)
+		testrequire.Equal(t, normalized, version)
+		testrequire.LessOrEqual(t, len(version), common.MaxVersionLength)
+	})
+}
+
+func FuzzPodMutatingWebhook_IsKeptnAnnotated(f *testing.F) {
+	f.Add("my-workload", "1.2.0", "")
+	f.Add("my-workload", "", "1.2.0+build.7")
+	f.Add("my-workload", "", "")
+	f.Add("", "1.0", "")
+	a := &PodMutatingWebhook{}
+	f.Fuzz(func(t *testing.T, workload string, version string, containerVersion string) {
+		annotations := map[string]string{common.WorkloadAnnotation: workload, common.VersionAnnotation: version}
+		if containerVersion != "" {
+			annotations[common.ContainerVersionAnnotationPrefix+"app"] = containerVersion
+		}
+		pod := newMultiContainerPod(annotations)
+		annotated, err := a.isKeptnAnnotated(pod)
+		if err != nil || !annotated {
+			return
+		}
+		testrequire.LessOrEqual(t, len(pod.Annotations[common.VersionAnnotation]), common.MaxVersionLength)
+		testrequire.NotEmpty(t, pod.Annotations[common.VersionAnnotation])
+	})
+}
+
+func FuzzSplitList(f *testing.F) {
+	for _, seed := range []string{"slo-latency,slo-errors", "notify, smoke-test", ",,", "a,,b,", " "} {
+		f.Add(seed)
+	}
+	f.Fuzz(func(t *testing.T, value string) {
+		items := splitList(value)
+		for _, item := range items {
+			testrequire.NotEmpty(t, item)
+			testrequire.Equal(t, strings.TrimSpace(item), item)
+			testrequire.NotContains(t, item, ",")
+		}
+		testrequire.Equal(t, items, splitList(strings.Join(items, ",")))
+	})
+}
+
+func TestPodMutatingWebhook_SkipPhases(t *testing.T) {
+	a := &PodMutatingWebhook{}
+	pod := newMultiContainerPod(map[string]string{
+		common.WorkloadAnnotation:   "my-workload",
+		common.VersionAnnotation:    "1.0.0",
+		common.SkipPhasesAnnotation: "pre-deployment-evaluation, deployment, post-deployment",
+	})
+
+	workload := a.generateWorkload(context.TODO(), pod, "default")
+
+	testrequire.Equal(t, []klcv1alpha1.SkippedPhase{"pre-deployment-evaluation", "post-deployment"}, workload.Spec.SkipPhases)
+}
+
+func TestPodMutatingWebhook_PostDeploymentEvaluationDelay(t *testing.T) {
+	tests := []struct {
+		annotation string
+		want       time.Duration
+	}{
+		{annotation: "5m", want: 5 * time.Minute},
+		// invalid and negative delays are ignored, so the evaluations start right after the deployment
+		{annotation: "soon", want: 0},
+		{annotation: "-5m", want: 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.annotation, func(t *testing.T) {
+			a := &PodMutatingWebhook{}
+			pod := newMultiContainerPod(map[string]string{
+				common.WorkloadAnnotation:                      "my-workload",
+				common.VersionAnnotation:                       "1.0.0",
+				common.PostDeploymentEvaluationDelayAnnotation: tt.annotation,
+			})
+
+			workload := a.generateWorkload(context.TODO(), pod, "default")
+
+			testrequire.Equal(t, tt.want, workload.Spec.PostDeploymentEvaluationDelay.Duration)
+		})
+	}
+}
@@ -0,0 +1,109 @@
+package webhooks
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/go-logr/logr"
+	klcv1alpha1 "github.com/keptn/lifecycle-controller/operator/api/v1alpha1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+// +kubebuilder:webhook:path=/validate-lifecycle-keptn-sh-v1alpha1-quota,mutating=false,failurePolicy=fail,groups=lifecycle.keptn.sh,resources=keptnappversions;keptntasks,verbs=create,versions=v1alpha1,name=vquota.keptn.sh,admissionReviewVersions=v1,sideEffects=None
+//+kubebuilder:rbac:groups=lifecycle.keptn.sh,resources=keptnconfigs,verbs=get;list;watch
+//+kubebuilder:rbac:groups=lifecycle.keptn.sh,resources=keptnappversions;keptntasks,verbs=get;list;watch
+
+// QuotaValidatingWebhook rejects new KeptnAppVersions and KeptnTasks exceeding the quotas of the KeptnConfig, so that
+// runaway automation creating thousands of resources cannot flood the operator and the API server
+type QuotaValidatingWebhook struct {
+	Client  client.Reader
+	decoder *admission.Decoder
+	// Config is the name and namespace of the KeptnConfig with the quotas. Without a KeptnConfig, nothing is limited.
+	Config types.NamespacedName
+	Log    logr.Logger
+}
+
+// Handle denies the creation of KeptnAppVersions and KeptnTasks once the namespace or instance has reached its quota
+func (a *QuotaValidatingWebhook) Handle(ctx context.Context, req admission.Request) admission.Response {
+	config := &klcv1alpha1.KeptnConfig{}
+	if err := a.Client.Get(ctx, a.Config, config); errors.IsNotFound(err) {
+		return admission.Allowed("")
+	} else if err != nil {
+		return admission.Errored(http.StatusInternalServerError, fmt.Errorf("could not read KeptnConfig %s: %w", a.Config, err))
+	}
+
+	var reason string
+	var err error
+	switch req.Kind.Kind {
+	case "KeptnAppVersion":
+		reason, err = a.checkActiveAppVersions(ctx, req.Namespace, config.Spec.Quotas.MaxActiveAppVersions)
+	case "KeptnTask":
+		task := &klcv1alpha1.KeptnTask{}
+		if err := a.decoder.Decode(req, task); err != nil {
+			return admission.Errored(http.StatusBadRequest, err)
+		}
+		reason, err = a.checkInstanceTasks(ctx, req.Namespace, metav1.GetControllerOf(task), config.Spec.Quotas.MaxTasksPerInstance)
+	}
+	if err != nil {
+		return admission.Errored(http.StatusInternalServerError, err)
+	}
+	if reason != "" {
+		a.Log.Info("rejecting resource exceeding quota", "kind", req.Kind.Kind, "namespace", req.Namespace, "name", req.Name, "reason", reason)
+		return admission.Denied(fmt.Sprintf("%s, set in KeptnConfig %s", reason, a.Config))
+	}
+	return admission.Allowed("")
+}
+
+// InjectDecoder injects the decoder.
+func (a *QuotaValidatingWebhook) InjectDecoder(d *admission.Decoder) error {
+	a.decoder = d
+	return nil
+}
+
+// checkActiveAppVersions returns why a new KeptnAppVersion exceeds the quota of the namespace, or an empty string
+func (a *QuotaValidatingWebhook) checkActiveAppVersions(ctx context.Context, namespace string, limit int) (string, error) {
+	if limit <= 0 {
+		return "", nil
+	}
+	appVersions := &klcv1alpha1.KeptnAppVersionList{}
+	if err := a.Client.List(ctx, appVersions, client.InNamespace(namespace)); err != nil {
+		return "", fmt.Errorf("could not list KeptnAppVersions: %w", err)
+	}
+	active := 0
+	for _, appVersion := range appVersions.Items {
+		if !appVersion.Status.Status.IsCompleted() {
+			active++
+		}
+	}
+	if active < limit {
+		return "", nil
+	}
+	return fmt.Sprintf("namespace %s has reached the maximum of %d active KeptnAppVersions", namespace, limit), nil
+}
+
+// checkInstanceTasks returns why a new KeptnTask exceeds the quota of the KeptnAppVersion or KeptnWorkloadInstance
+// owning it, or an empty string. Tasks without an owner are not limited.
+func (a *QuotaValidatingWebhook) checkInstanceTasks(ctx context.Context, namespace string, owner *metav1.OwnerReference, limit int) (string, error) {
+	if limit <= 0 || owner == nil {
+		return "", nil
+	}
+	tasks := &klcv1alpha1.KeptnTaskList{}
+	if err := a.Client.List(ctx, tasks, client.InNamespace(namespace)); err != nil {
+		return "", fmt.Errorf("could not list KeptnTasks: %w", err)
+	}
+	count := 0
+	for i := range tasks.Items {
+		if taskOwner := metav1.GetControllerOf(&tasks.Items[i]); taskOwner != nil && taskOwner.UID == owner.UID {
+			count++
+		}
+	}
+	if count < limit {
+		return "", nil
+	}
+	return fmt.Sprintf("%s %s has reached the maximum of %d KeptnTasks", owner.Kind, owner.Name, limit), nil
+}
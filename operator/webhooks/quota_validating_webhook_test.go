@@ -0,0 +1,81 @@
+package webhooks
+
+import (
+	"context"
+	"testing"
+
+	"github.com/go-logr/logr"
+	klcv1alpha1 "github.com/keptn/lifecycle-controller/operator/api/v1alpha1"
+	"github.com/keptn/lifecycle-controller/operator/api/v1alpha1/common"
+	testrequire "github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestQuotaValidatingWebhook_ActiveAppVersions(t *testing.T) {
+	scheme := runtime.NewScheme()
+	testrequire.Nil(t, clientgoscheme.AddToScheme(scheme))
+	testrequire.Nil(t, klcv1alpha1.AddToScheme(scheme))
+
+	appVersion := func(name string, namespace string, status common.KeptnState) *klcv1alpha1.KeptnAppVersion {
+		return &klcv1alpha1.KeptnAppVersion{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+			Status:     klcv1alpha1.KeptnAppVersionStatus{Status: status},
+		}
+	}
+	a := &QuotaValidatingWebhook{
+		Client: fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+			appVersion("running", "default", common.StateProgressing),
+			appVersion("pending", "default", common.StatePending),
+			appVersion("done", "default", common.StateSucceeded),
+			appVersion("other", "other", common.StateProgressing),
+		).Build(),
+		Log: logr.Discard(),
+	}
+
+	reason, err := a.checkActiveAppVersions(context.TODO(), "default", 3)
+	testrequire.Nil(t, err)
+	testrequire.Empty(t, reason)
+
+	reason, err = a.checkActiveAppVersions(context.TODO(), "default", 2)
+	testrequire.Nil(t, err)
+	testrequire.Contains(t, reason, "maximum of 2 active KeptnAppVersions")
+
+	reason, err = a.checkActiveAppVersions(context.TODO(), "default", 0)
+	testrequire.Nil(t, err)
+	testrequire.Empty(t, reason)
+}
+
+func TestQuotaValidatingWebhook_InstanceTasks(t *testing.T) {
+	scheme := runtime.NewScheme()
+	testrequire.Nil(t, clientgoscheme.AddToScheme(scheme))
+	testrequire.Nil(t, klcv1alpha1.AddToScheme(scheme))
+
+	isController := true
+	owner := metav1.OwnerReference{Kind: "KeptnWorkloadInstance", Name: "podtato-head-1.0.0", UID: "instance", Controller: &isController}
+	task := func(name string, owner metav1.OwnerReference) *klcv1alpha1.KeptnTask {
+		return &klcv1alpha1.KeptnTask{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default", OwnerReferences: []metav1.OwnerReference{owner}}}
+	}
+	a := &QuotaValidatingWebhook{
+		Client: fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+			task("pre-1", owner),
+			task("pre-2", owner),
+			task("other", metav1.OwnerReference{Kind: "KeptnAppVersion", Name: "podtato-head-1.0.0", UID: "app", Controller: &isController}),
+		).Build(),
+		Log: logr.Discard(),
+	}
+
+	reason, err := a.checkInstanceTasks(context.TODO(), "default", &owner, 2)
+	testrequire.Nil(t, err)
+	testrequire.Contains(t, reason, "KeptnWorkloadInstance podtato-head-1.0.0 has reached the maximum of 2 KeptnTasks")
+
+	reason, err = a.checkInstanceTasks(context.TODO(), "default", &owner, 3)
+	testrequire.Nil(t, err)
+	testrequire.Empty(t, reason)
+
+	reason, err = a.checkInstanceTasks(context.TODO(), "default", nil, 1)
+	testrequire.Nil(t, err)
+	testrequire.Empty(t, reason)
+}
@@ -0,0 +1,47 @@
+package webhooks
+
+import (
+	"context"
+	"fmt"
+
+	klcv1alpha1 "github.com/keptn/lifecycle-controller/operator/api/v1alpha1"
+	"github.com/keptn/lifecycle-controller/operator/api/v1alpha1/common"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+//+kubebuilder:rbac:groups=lifecycle.keptn.sh,resources=keptnworkloadinstances,verbs=get;list;watch
+
+// restoreRolledBackVersion detects pods of a ReplicaSet which has already been deployed as an earlier version of the
+// workload, e.g. when a Deployment is rolled back, and sets the version of that earlier workload instance on the pod,
+// so that the existing workload instance is reconciled instead of a new one being created.
+// It returns the workload instance of the earlier version, or nil if the pod does not belong to one.
+func (a *PodMutatingWebhook) restoreRolledBackVersion(ctx context.Context, pod *corev1.Pod, namespace string) (*klcv1alpha1.KeptnWorkloadInstance, error) {
+	reference := a.getResourceReference(pod)
+	if reference.Kind != "ReplicaSet" || reference.UID == "" {
+		return nil, nil
+	}
+
+	instances := &klcv1alpha1.KeptnWorkloadInstanceList{}
+	if err := a.Client.List(ctx, instances, client.InNamespace(namespace)); err != nil {
+		return nil, fmt.Errorf("could not list KeptnWorkloadInstances: %w", err)
+	}
+
+	workloadName := a.getWorkloadName(pod)
+	var rolledBack *klcv1alpha1.KeptnWorkloadInstance
+	for i := range instances.Items {
+		instance := &instances.Items[i]
+		if instance.Spec.WorkloadName != workloadName || instance.Spec.ResourceReference.UID != reference.UID {
+			continue
+		}
+		if rolledBack == nil || rolledBack.CreationTimestamp.Before(&instance.CreationTimestamp) {
+			rolledBack = instance
+		}
+	}
+	if rolledBack == nil || rolledBack.Spec.Version == pod.Annotations[common.VersionAnnotation] {
+		return nil, nil
+	}
+
+	pod.Annotations[common.VersionAnnotation] = rolledBack.Spec.Version
+	return rolledBack, nil
+}
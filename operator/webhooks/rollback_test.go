@@ -0,0 +1,59 @@
+package webhooks
+
+import (
+	"context"
+	"testing"
+
+	klcv1alpha1 "github.com/keptn/lifecycle-controller/operator/api/v1alpha1"
+	"github.com/keptn/lifecycle-controller/operator/api/v1alpha1/common"
+	testrequire "github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestPodMutatingWebhook_RestoreRolledBackVersion(t *testing.T) {
+	scheme := runtime.NewScheme()
+	testrequire.Nil(t, klcv1alpha1.AddToScheme(scheme))
+	instance := func(version string, uid string) *klcv1alpha1.KeptnWorkloadInstance {
+		return &klcv1alpha1.KeptnWorkloadInstance{
+			ObjectMeta: metav1.ObjectMeta{Name: "my-app-my-workload-" + version, Namespace: "default"},
+			Spec: klcv1alpha1.KeptnWorkloadInstanceSpec{
+				KeptnWorkloadSpec: klcv1alpha1.KeptnWorkloadSpec{
+					Version:           version,
+					ResourceReference: klcv1alpha1.ResourceReference{UID: types.UID("rs-uid-" + uid), Kind: "ReplicaSet"},
+				},
+				WorkloadName: "my-app-my-workload",
+			},
+		}
+	}
+	a := &PodMutatingWebhook{Client: fake.NewClientBuilder().WithScheme(scheme).WithObjects(instance("1.0.0", "1"), instance("2.0.0", "2")).Build()}
+	pod := func(uid string) *corev1.Pod {
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Annotations: map[string]string{
+					common.AppAnnotation:      "my-app",
+					common.WorkloadAnnotation: "my-workload",
+					common.VersionAnnotation:  "3387606234",
+				},
+				OwnerReferences: []metav1.OwnerReference{{Kind: "ReplicaSet", Name: "my-deployment-5f7b", UID: types.UID("rs-uid-" + uid)}},
+			},
+		}
+	}
+
+	rolledBackPod := pod("1")
+	rolledBack, err := a.restoreRolledBackVersion(context.TODO(), rolledBackPod, "default")
+	testrequire.Nil(t, err)
+	testrequire.NotNil(t, rolledBack)
+	testrequire.Equal(t, "my-app-my-workload-1.0.0", rolledBack.Name)
+	testrequire.Equal(t, "1.0.0", rolledBackPod.Annotations[common.VersionAnnotation])
+	testrequire.Equal(t, "1.0.0", a.generateWorkload(context.TODO(), rolledBackPod, "default").Spec.Version)
+
+	newPod := pod("3")
+	rolledBack, err = a.restoreRolledBackVersion(context.TODO(), newPod, "default")
+	testrequire.Nil(t, err)
+	testrequire.Nil(t, rolledBack)
+	testrequire.Equal(t, "3387606234", newPod.Annotations[common.VersionAnnotation])
+}
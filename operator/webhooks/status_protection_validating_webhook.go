@@ -0,0 +1,56 @@
+package webhooks
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/go-logr/logr"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+// +kubebuilder:webhook:path=/validate-lifecycle-keptn-sh-v1alpha1-status,mutating=false,failurePolicy=fail,groups=lifecycle.keptn.sh,resources=keptnworkloadinstances/status;keptnappversions/status,verbs=update,versions=v1alpha1,name=vstatus.keptn.sh,admissionReviewVersions=v1,sideEffects=None
+
+// StatusProtectionValidatingWebhook rejects changes of the status of KeptnWorkloadInstances and KeptnAppVersions that
+// are not made by the operator, so that the results of their pre- and post-deployment checks can be trusted in audits
+type StatusProtectionValidatingWebhook struct {
+	// ServiceAccount is the user name of the service account of the operator
+	ServiceAccount string
+	// SchedulerServiceAccount is the user name of the service account of the Keptn scheduler, which records in the
+	// status of KeptnWorkloadInstances that their pods have been released. It is allowed by default, unless it is empty.
+	SchedulerServiceAccount string
+	// Editors are the users and groups that may change the status in addition to the operator, e.g. for break-glass
+	// access
+	Editors []string
+	Log     logr.Logger
+}
+
+// Handle denies updates of the status subresource by any user other than the operator and the editors
+func (a *StatusProtectionValidatingWebhook) Handle(ctx context.Context, req admission.Request) admission.Response {
+	if req.SubResource != "status" || a.isEditor(req.UserInfo.Username, req.UserInfo.Groups) {
+		return admission.Allowed("")
+	}
+	a.Log.Info("rejecting status update", "kind", req.Kind.Kind, "namespace", req.Namespace, "name", req.Name, "user", req.UserInfo.Username)
+	return admission.Denied(fmt.Sprintf("the status of %s %s/%s is managed by the Keptn Lifecycle Controller and cannot be changed by %s", req.Kind.Kind, req.Namespace, req.Name, req.UserInfo.Username))
+}
+
+func (a *StatusProtectionValidatingWebhook) isEditor(username string, groups []string) bool {
+	if username == a.ServiceAccount || (a.SchedulerServiceAccount != "" && username == a.SchedulerServiceAccount) {
+		return true
+	}
+	for _, editor := range a.Editors {
+		if editor == username {
+			return true
+		}
+		for _, group := range groups {
+			if editor == group {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// ServiceAccountUsername returns the user name a service account authenticates with
+func ServiceAccountUsername(namespace string, name string) string {
+	return fmt.Sprintf("system:serviceaccount:%s:%s", namespace, name)
+}
@@ -0,0 +1,86 @@
+package webhooks
+
+import (
+	"context"
+	"testing"
+
+	"github.com/go-logr/logr"
+	testrequire "github.com/stretchr/testify/require"
+	admissionv1 "k8s.io/api/admission/v1"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+func newStatusRequest(subResource string, username string, groups ...string) admission.Request {
+	return admission.Request{AdmissionRequest: admissionv1.AdmissionRequest{
+		Kind:        metav1.GroupVersionKind{Group: "lifecycle.keptn.sh", Version: "v1alpha1", Kind: "KeptnWorkloadInstance"},
+		Namespace:   "podtato",
+		Name:        "podtato-head-1.0.0",
+		Operation:   admissionv1.Update,
+		SubResource: subResource,
+		UserInfo:    authenticationv1.UserInfo{Username: username, Groups: groups},
+	}}
+}
+
+func TestStatusProtectionValidatingWebhook_Handle(t *testing.T) {
+	a := &StatusProtectionValidatingWebhook{
+		ServiceAccount:          ServiceAccountUsername("keptn-lifecycle-controller-system", "klc-controller-manager"),
+		SchedulerServiceAccount: ServiceAccountUsername("keptn-lifecycle-controller-system", "keptn-scheduler"),
+		Editors:                 []string{"auditors"},
+		Log:                     logr.Discard(),
+	}
+
+	tests := []struct {
+		name    string
+		req     admission.Request
+		allowed bool
+	}{
+		{
+			name:    "operator",
+			req:     newStatusRequest("status", "system:serviceaccount:keptn-lifecycle-controller-system:klc-controller-manager"),
+			allowed: true,
+		},
+		{
+			name:    "scheduler",
+			req:     newStatusRequest("status", "system:serviceaccount:keptn-lifecycle-controller-system:keptn-scheduler"),
+			allowed: true,
+		},
+		{
+			name:    "other service account",
+			req:     newStatusRequest("status", "system:serviceaccount:podtato:default", "system:serviceaccounts"),
+			allowed: false,
+		},
+		{
+			name:    "user",
+			req:     newStatusRequest("status", "jane", "system:authenticated"),
+			allowed: false,
+		},
+		{
+			name:    "editor group",
+			req:     newStatusRequest("status", "jane", "system:authenticated", "auditors"),
+			allowed: true,
+		},
+		{
+			name:    "spec",
+			req:     newStatusRequest("", "jane"),
+			allowed: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			testrequire.Equal(t, tt.allowed, a.Handle(context.TODO(), tt.req).Allowed)
+		})
+	}
+}
+
+func TestStatusProtectionValidatingWebhook_HandleWithoutScheduler(t *testing.T) {
+	a := &StatusProtectionValidatingWebhook{
+		ServiceAccount: ServiceAccountUsername("keptn-lifecycle-controller-system", "klc-controller-manager"),
+		Log:            logr.Discard(),
+	}
+
+	testrequire.False(t, a.Handle(context.TODO(), newStatusRequest("status", "system:serviceaccount:keptn-lifecycle-controller-system:keptn-scheduler")).Allowed)
+	// an empty user name must not match the unset scheduler service account
+	testrequire.False(t, a.Handle(context.TODO(), newStatusRequest("status", "")).Allowed)
+}
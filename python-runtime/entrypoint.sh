@@ -0,0 +1,13 @@
+#!/bin/bash
+
+set -eu
+
+script="$SCRIPT"
+
+# scripts referenced by an URL are downloaded before they are executed
+if [[ "$script" == http://* || "$script" == https://* ]]; then
+  script=/tmp/function.py
+  python -c 'import sys, urllib.request; urllib.request.urlretrieve(sys.argv[1], sys.argv[2])' "$SCRIPT" "$script"
+fi
+
+exec python "$script"
@@ -0,0 +1,13 @@
+import json
+import os
+import sys
+
+import requests
+
+data = json.loads(os.environ.get("DATA", "{}"))
+
+try:
+    requests.get(data["url"], timeout=10).raise_for_status()
+except Exception as error:
+    print("Could not fetch url: %s" % error, file=sys.stderr)
+    sys.exit(1)
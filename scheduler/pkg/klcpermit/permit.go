@@ -5,6 +5,7 @@ import (
 	"time"
 
 	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/rest"
@@ -17,10 +18,14 @@ const (
 	PluginName = "KLCPermit"
 )
 
+// releaseInterval is the interval in which the pre-deployment checks of the waiting pods are checked
+const releaseInterval = 10 * time.Second
+
 // Permit is a plugin that waits for pre-deployment checks to be successfully finished
 type Permit struct {
 	handler         framework.Handle
 	workloadManager *WorkloadManager
+	releaseQueue    *ReleaseQueue
 }
 
 var _ framework.PermitPlugin = &Permit{}
@@ -45,33 +50,56 @@ func (pl *Permit) Permit(ctx context.Context, state *framework.CycleState, p *v1
 		return framework.NewStatus(framework.Success), 0 * time.Second
 	default:
 		klog.Infof("[Keptn Permit Plugin] waiting for pre-deployment checks on %s", p.GetObjectMeta().GetName())
-		go func() {
-			// create a new context since we are in a new goroutine
-			ctx2, cancel := context.WithCancel(context.Background())
-			defer cancel()
-			pl.monitorPod(ctx2, p)
-		}()
+		pl.releaseQueue.Add(p)
 		return framework.NewStatus(framework.Wait), 5 * time.Minute
 	}
 
 }
 
-func (pl *Permit) monitorPod(ctx context.Context, p *v1.Pod) {
-	waitingPodHandler := pl.handler.GetWaitingPod(p.UID)
-
+// monitorWaitingPods checks the pre-deployment checks of all waiting pods periodically
+func (pl *Permit) monitorWaitingPods(ctx context.Context) {
 	for {
+		time.Sleep(releaseInterval)
+		pl.releaseWaitingPods(ctx)
+	}
+}
+
+// releaseWaitingPods allows the waiting pods whose pre-deployment checks have passed and rejects the ones whose checks
+// have failed. The pods are released in priority order, so that critical services are released first when the checks
+// of many workloads finish at once, e.g. after an outage of the operator.
+func (pl *Permit) releaseWaitingPods(ctx context.Context) {
+	for _, p := range pl.releaseQueue.Pods(pl.namespacePriorities(ctx)) {
+		waitingPodHandler := pl.handler.GetWaitingPod(p.UID)
+		if waitingPodHandler == nil {
+			// the pod is not waiting anymore, e.g. since the permit has timed out, unless it has just been added
+			if time.Since(pl.releaseQueue.AddedAt(p)) > releaseInterval {
+				pl.releaseQueue.Remove(p)
+			}
+			continue
+		}
 		switch pl.workloadManager.Permit(ctx, p) {
 		case Failure:
 			waitingPodHandler.Reject(PluginName, "Pre Deployment Check failed")
-			return
+			pl.releaseQueue.Remove(p)
 		case Success:
 			waitingPodHandler.Allow(PluginName)
-			return
-		default:
-			time.Sleep(10 * time.Second)
+			pl.releaseQueue.Remove(p)
 		}
 	}
+}
 
+// namespacePriorities returns the release priorities of the namespaces of the waiting pods
+func (pl *Permit) namespacePriorities(ctx context.Context) map[string]int {
+	priorities := map[string]int{}
+	for _, name := range pl.releaseQueue.Namespaces() {
+		namespace, err := pl.handler.ClientSet().CoreV1().Namespaces().Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			klog.Infof("[Keptn Permit Plugin] could not get release priority of namespace %s, err:%s", name, err.Error())
+			continue
+		}
+		priorities[name] = namespacePriority(namespace)
+	}
+	return priorities
 }
 
 // New initializes a new plugin and returns it.
@@ -81,10 +109,13 @@ func New(_ runtime.Object, h framework.Handle) (framework.Plugin, error) {
 		return nil, err
 	}
 
-	return &Permit{
+	pl := &Permit{
 		workloadManager: NewWorkloadManager(client),
 		handler:         h,
-	}, nil
+		releaseQueue:    NewReleaseQueue(),
+	}
+	go pl.monitorWaitingPods(context.Background())
+	return pl, nil
 }
 
 func newClient() (dynamic.Interface, error) {
@@ -0,0 +1,57 @@
+package klcpermit
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/klog/v2"
+)
+
+// PodsReleasedCondition is the type of the condition of a KeptnWorkloadInstance which is true as soon as the scheduler
+// has released its pods, e.g. for kubectl wait --for=condition=PodsReleased
+const PodsReleasedCondition = "PodsReleased"
+
+// setPodsReleased sets the PodsReleased condition of the workload instance, unless it is already set to the given status
+func (sMgr *WorkloadManager) setPodsReleased(ctx context.Context, crd *unstructured.Unstructured, status metav1.ConditionStatus, reason string, message string) {
+	conditions, _, _ := unstructured.NestedSlice(crd.UnstructuredContent(), "status", "conditions")
+	conditions, changed := withPodsReleased(conditions, status, reason, message, time.Now())
+	if !changed {
+		return
+	}
+	patch, err := json.Marshal(map[string]interface{}{"status": map[string]interface{}{"conditions": conditions}})
+	if err != nil {
+		klog.Errorf("[Keptn Permit Plugin] could not marshal conditions of workloadInstance crd %s, err:%s", crd.GetName(), err.Error())
+		return
+	}
+	_, err = sMgr.dynamicClient.Resource(workloadInstanceResource).Namespace(crd.GetNamespace()).Patch(ctx, crd.GetName(), types.MergePatchType, patch, metav1.PatchOptions{}, "status")
+	if err != nil {
+		klog.Errorf("[Keptn Permit Plugin] could not set %s condition of workloadInstance crd %s, err:%s", PodsReleasedCondition, crd.GetName(), err.Error())
+	}
+}
+
+// withPodsReleased returns the conditions with the PodsReleased condition set to the given status, and whether the
+// conditions have changed
+func withPodsReleased(conditions []interface{}, status metav1.ConditionStatus, reason string, message string, now time.Time) ([]interface{}, bool) {
+	result := make([]interface{}, 0, len(conditions)+1)
+	for _, c := range conditions {
+		condition, ok := c.(map[string]interface{})
+		if ok && condition["type"] == PodsReleasedCondition {
+			if condition["status"] == string(status) {
+				return conditions, false
+			}
+			continue
+		}
+		result = append(result, c)
+	}
+	return append(result, map[string]interface{}{
+		"type":               PodsReleasedCondition,
+		"status":             string(status),
+		"reason":             reason,
+		"message":            message,
+		"lastTransitionTime": now.UTC().Format(time.RFC3339),
+	}), true
+}
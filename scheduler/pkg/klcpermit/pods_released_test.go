@@ -0,0 +1,31 @@
+package klcpermit
+
+import (
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestWithPodsReleased(t *testing.T) {
+	now := time.Date(2022, 11, 8, 12, 0, 0, 0, time.UTC)
+	other := map[string]interface{}{"type": "Other", "status": "True"}
+
+	conditions, changed := withPodsReleased([]interface{}{other}, metav1.ConditionTrue, "PreDeploymentSucceeded", "released", now)
+	if !changed || len(conditions) != 2 {
+		t.Fatalf("expected condition to be added, got %v", conditions)
+	}
+	released := conditions[1].(map[string]interface{})
+	if released["type"] != PodsReleasedCondition || released["status"] != "True" || released["lastTransitionTime"] != "2022-11-08T12:00:00Z" {
+		t.Fatalf("unexpected condition %v", released)
+	}
+
+	if _, changed := withPodsReleased(conditions, metav1.ConditionTrue, "PreDeploymentSucceeded", "released", now.Add(time.Minute)); changed {
+		t.Fatalf("expected condition with the same status not to change")
+	}
+
+	conditions, changed = withPodsReleased(conditions, metav1.ConditionFalse, "PreDeploymentFailed", "rejected", now)
+	if !changed || len(conditions) != 2 || conditions[1].(map[string]interface{})["status"] != "False" {
+		t.Fatalf("expected condition to be replaced, got %v", conditions)
+	}
+}
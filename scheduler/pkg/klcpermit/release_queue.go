@@ -0,0 +1,112 @@
+package klcpermit
+
+import (
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// ReleasePriorityAnnotation is the annotation of a namespace defining the priority its pods are released with.
+// Pods of namespaces with a higher priority are released first.
+const ReleasePriorityAnnotation = "keptn.sh/release-priority"
+
+// ReleaseQueue holds the pods waiting for their pre-deployment checks
+type ReleaseQueue struct {
+	mu    sync.Mutex
+	pods  map[types.UID]*v1.Pod
+	added map[types.UID]time.Time
+}
+
+func NewReleaseQueue() *ReleaseQueue {
+	return &ReleaseQueue{
+		pods:  map[types.UID]*v1.Pod{},
+		added: map[types.UID]time.Time{},
+	}
+}
+
+// Add adds a waiting pod to the queue
+func (q *ReleaseQueue) Add(pod *v1.Pod) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if _, ok := q.pods[pod.UID]; !ok {
+		q.added[pod.UID] = time.Now()
+	}
+	q.pods[pod.UID] = pod
+}
+
+// Remove removes a pod that has been released, rejected or is not waiting anymore from the queue
+func (q *ReleaseQueue) Remove(pod *v1.Pod) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	delete(q.pods, pod.UID)
+	delete(q.added, pod.UID)
+}
+
+// AddedAt returns the time the pod has been added to the queue
+func (q *ReleaseQueue) AddedAt(pod *v1.Pod) time.Time {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return q.added[pod.UID]
+}
+
+// Namespaces returns the namespaces of the waiting pods
+func (q *ReleaseQueue) Namespaces() []string {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	seen := map[string]bool{}
+	var namespaces []string
+	for _, pod := range q.pods {
+		if !seen[pod.Namespace] {
+			seen[pod.Namespace] = true
+			namespaces = append(namespaces, pod.Namespace)
+		}
+	}
+	return namespaces
+}
+
+// Pods returns the waiting pods in the order they are released: pods of namespaces with a higher release priority
+// first, then pods with a higher priority, which is resolved from the PriorityClass of the workload, then the pods
+// which have been created first
+func (q *ReleaseQueue) Pods(namespacePriorities map[string]int) []*v1.Pod {
+	q.mu.Lock()
+	pods := make([]*v1.Pod, 0, len(q.pods))
+	for _, pod := range q.pods {
+		pods = append(pods, pod)
+	}
+	q.mu.Unlock()
+
+	sort.SliceStable(pods, func(i, j int) bool {
+		a, b := pods[i], pods[j]
+		if namespacePriorities[a.Namespace] != namespacePriorities[b.Namespace] {
+			return namespacePriorities[a.Namespace] > namespacePriorities[b.Namespace]
+		}
+		if podPriority(a) != podPriority(b) {
+			return podPriority(a) > podPriority(b)
+		}
+		if !a.CreationTimestamp.Equal(&b.CreationTimestamp) {
+			return a.CreationTimestamp.Before(&b.CreationTimestamp)
+		}
+		return a.Namespace+"/"+a.Name < b.Namespace+"/"+b.Name
+	})
+	return pods
+}
+
+func podPriority(pod *v1.Pod) int32 {
+	if pod.Spec.Priority == nil {
+		return 0
+	}
+	return *pod.Spec.Priority
+}
+
+// namespacePriority returns the release priority annotated on a namespace, 0 if it is not set or invalid
+func namespacePriority(namespace *v1.Namespace) int {
+	priority, err := strconv.Atoi(namespace.Annotations[ReleasePriorityAnnotation])
+	if err != nil {
+		return 0
+	}
+	return priority
+}
@@ -0,0 +1,63 @@
+package klcpermit
+
+import (
+	"testing"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+func TestReleaseQueue_Pods(t *testing.T) {
+	created := time.Date(2022, 11, 8, 12, 0, 0, 0, time.UTC)
+	pod := func(namespace string, name string, priority int32, age time.Duration) *v1.Pod {
+		return &v1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace:         namespace,
+				Name:              name,
+				UID:               types.UID(namespace + "/" + name),
+				CreationTimestamp: metav1.NewTime(created.Add(-age)),
+			},
+			Spec: v1.PodSpec{Priority: &priority},
+		}
+	}
+
+	q := NewReleaseQueue()
+	q.Add(pod("shop", "frontend", 0, time.Minute))
+	q.Add(pod("shop", "checkout", 1000, 0))
+	q.Add(pod("shop", "cart", 0, 2*time.Minute))
+	q.Add(pod("payments", "gateway", 0, 0))
+	q.Add(pod("batch", "report", 2000, time.Hour))
+
+	var order []string
+	for _, p := range q.Pods(map[string]int{"payments": 10, "batch": -1}) {
+		order = append(order, p.Namespace+"/"+p.Name)
+	}
+
+	want := []string{"payments/gateway", "shop/checkout", "shop/cart", "shop/frontend", "batch/report"}
+	if len(order) != len(want) {
+		t.Fatalf("got %v, want %v", order, want)
+	}
+	for i := range want {
+		if order[i] != want[i] {
+			t.Fatalf("got %v, want %v", order, want)
+		}
+	}
+
+	q.Remove(pod("payments", "gateway", 0, 0))
+	if len(q.Pods(nil)) != 4 {
+		t.Fatalf("expected removed pod not to be released")
+	}
+}
+
+func TestNamespacePriority(t *testing.T) {
+	ns := &v1.Namespace{ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{ReleasePriorityAnnotation: "100"}}}
+	if p := namespacePriority(ns); p != 100 {
+		t.Fatalf("got priority %d, want 100", p)
+	}
+	ns.Annotations[ReleasePriorityAnnotation] = "high"
+	if p := namespacePriority(ns); p != 0 {
+		t.Fatalf("got priority %d for invalid annotation, want 0", p)
+	}
+}
@@ -83,15 +83,31 @@ func (sMgr *WorkloadManager) Permit(ctx context.Context, pod *corev1.Pod) Status
 	klog.Infof("[Keptn Permit Plugin] workloadInstance crd %s, found %s with phase %s ", crd, found, phase)
 	if err == nil && found {
 		span.AddEvent("StatusEvaluation", trace.WithAttributes(tracing.Status.String(phase)))
+		// the pods of workloads with migration tasks are only scheduled once the migrations have succeeded as well
+		if KeptnState(phase) == StateSucceeded {
+			switch migrationState(crd) {
+			case StateSucceeded:
+			case StateFailed:
+				span.SetStatus(codes.Error, "Failed")
+				span.End()
+				unbindSpan(pod)
+				sMgr.setPodsReleased(ctx, crd, metav1.ConditionFalse, "MigrationFailed", "the pods have been rejected since the migration tasks have failed")
+				return Failure
+			default:
+				return Wait
+			}
+		}
 		switch KeptnState(phase) {
 		case StateFailed:
 			span.SetStatus(codes.Error, "Failed")
 			span.End()
 			unbindSpan(pod)
+			sMgr.setPodsReleased(ctx, crd, metav1.ConditionFalse, "PreDeploymentFailed", "the pods have been rejected since the pre-deployment checks have failed")
 			return Failure
 		case StateSucceeded:
 			span.End()
 			unbindSpan(pod)
+			sMgr.setPodsReleased(ctx, crd, metav1.ConditionTrue, "PreDeploymentSucceeded", "the pods have been released for scheduling")
 			return Success
 		case StatePending:
 			return Wait
@@ -104,6 +120,16 @@ func (sMgr *WorkloadManager) Permit(ctx context.Context, pod *corev1.Pod) Status
 	return WorkloadInstanceStatusNotSpecified
 }
 
+// migrationState returns the state of the migration tasks of the workload instance, which is Succeeded if it has none
+func migrationState(crd *unstructured.Unstructured) KeptnState {
+	tasks, _, _ := unstructured.NestedStringSlice(crd.UnstructuredContent(), "spec", "migrationTasks")
+	if len(tasks) == 0 {
+		return StateSucceeded
+	}
+	state, _, _ := unstructured.NestedString(crd.UnstructuredContent(), "status", "migrationStatus")
+	return KeptnState(state)
+}
+
 // GetCRD returns unstructured to avoid tight coupling with the CRD resource
 func (sMgr *WorkloadManager) GetCRD(ctx context.Context, namespace string, name string) (*unstructured.Unstructured, error) {
 	// GET /apis/lifecycle.keptn.sh/v1/namespaces/{namespace}/workloadinstance/name
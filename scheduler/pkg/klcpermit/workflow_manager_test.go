@@ -0,0 +1,25 @@
+package klcpermit
+
+import (
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func TestMigrationState(t *testing.T) {
+	withoutMigrations := &unstructured.Unstructured{Object: map[string]interface{}{
+		"spec":   map[string]interface{}{"preDeploymentTasks": []interface{}{"check"}},
+		"status": map[string]interface{}{"migrationStatus": "Pending"},
+	}}
+	if state := migrationState(withoutMigrations); state != StateSucceeded {
+		t.Fatalf("expected workload without migration tasks to be released, got %s", state)
+	}
+
+	withMigrations := &unstructured.Unstructured{Object: map[string]interface{}{
+		"spec":   map[string]interface{}{"migrationTasks": []interface{}{"migrate-schema"}},
+		"status": map[string]interface{}{"migrationStatus": "Progressing"},
+	}}
+	if state := migrationState(withMigrations); state != "Progressing" {
+		t.Fatalf("expected state of the migration, got %s", state)
+	}
+}